@@ -13,6 +13,7 @@ import (
 	"k8s.io/component-base/logs"
 
 	"open-cluster-management.io/ocm/pkg/cmd/hub"
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/version"
 )
 
@@ -22,10 +23,15 @@ func main() {
 	pflag.CommandLine.SetNormalizeFunc(utilflag.WordSepNormalizeFunc)
 	pflag.CommandLine.AddGoFlagSet(goflag.CommandLine)
 
+	loggingOptions := logging.NewOptions()
+	loggingOptions.AddFlags(pflag.CommandLine)
 	logs.InitLogs()
 	defer logs.FlushLogs()
 
 	command := newPlacementCommand()
+	command.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return loggingOptions.ValidateAndApply()
+	}
 	if err := command.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
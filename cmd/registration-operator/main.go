@@ -12,8 +12,10 @@ import (
 	utilflag "k8s.io/component-base/cli/flag"
 	"k8s.io/component-base/logs"
 
+	"open-cluster-management.io/ocm/pkg/cmd/diagnostics"
 	"open-cluster-management.io/ocm/pkg/cmd/hub"
 	"open-cluster-management.io/ocm/pkg/cmd/spoke"
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/version"
 )
 
@@ -23,11 +25,15 @@ func main() {
 	pflag.CommandLine.SetNormalizeFunc(utilflag.WordSepNormalizeFunc)
 	pflag.CommandLine.AddGoFlagSet(goflag.CommandLine)
 
-	logs.AddFlags(pflag.CommandLine)
+	loggingOptions := logging.NewOptions()
+	loggingOptions.AddFlags(pflag.CommandLine)
 	logs.InitLogs()
 	defer logs.FlushLogs()
 
 	command := newNucleusCommand()
+	command.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return loggingOptions.ValidateAndApply()
+	}
 	if err := command.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
@@ -53,6 +59,7 @@ func newNucleusCommand() *cobra.Command {
 	cmd.AddCommand(hub.NewHubOperatorCmd())
 	cmd.AddCommand(spoke.NewKlusterletOperatorCmd())
 	cmd.AddCommand(spoke.NewKlusterletAgentCmd())
+	cmd.AddCommand(diagnostics.NewDiagnosticsCmd())
 
 	return cmd
 }
@@ -18,6 +18,7 @@ import (
 	"open-cluster-management.io/ocm/pkg/cmd/hub"
 	"open-cluster-management.io/ocm/pkg/cmd/spoke"
 	"open-cluster-management.io/ocm/pkg/cmd/webhook"
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/features"
 	"open-cluster-management.io/ocm/pkg/version"
 )
@@ -31,7 +32,8 @@ func main() {
 	pflag.CommandLine.SetNormalizeFunc(utilflag.WordSepNormalizeFunc)
 	pflag.CommandLine.AddGoFlagSet(goflag.CommandLine)
 
-	logs.AddFlags(pflag.CommandLine)
+	loggingOptions := logging.NewOptions()
+	loggingOptions.AddFlags(pflag.CommandLine)
 	logs.InitLogs()
 	defer logs.FlushLogs()
 
@@ -39,6 +41,9 @@ func main() {
 	features.HubMutableFeatureGate.AddFlag(pflag.CommandLine)
 
 	command := newRegistrationCommand()
+	command.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return loggingOptions.ValidateAndApply()
+	}
 	if err := command.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
@@ -66,6 +71,7 @@ func newRegistrationCommand() *cobra.Command {
 	cmd.AddCommand(hub.NewRegistrationController())
 	cmd.AddCommand(spoke.NewRegistrationAgent())
 	cmd.AddCommand(webhook.NewRegistrationWebhook())
+	cmd.AddCommand(hub.NewFleetMetricsExporter())
 
 	return cmd
 }
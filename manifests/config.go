@@ -1,23 +1,46 @@
 package manifests
 
 type HubConfig struct {
-	ClusterManagerName             string
-	ClusterManagerNamespace        string
-	RegistrationImage              string
-	RegistrationAPIServiceCABundle string
-	WorkImage                      string
-	WorkAPIServiceCABundle         string
-	PlacementImage                 string
-	Replica                        int32
-	HostedMode                     bool
-	RegistrationWebhook            Webhook
-	WorkWebhook                    Webhook
-	RegistrationFeatureGates       []string
-	WorkFeatureGates               []string
-	AddOnManagerImage              string
-	AddOnManagerEnabled            bool
-	MWReplicaSetEnabled            bool
-	AutoApproveUsers               string
+	ClusterManagerName              string
+	ClusterManagerNamespace         string
+	RegistrationImage               string
+	RegistrationAPIServiceCABundle  string
+	WorkImage                       string
+	WorkAPIServiceCABundle          string
+	PlacementImage                  string
+	Replica                         int32
+	HostedMode                      bool
+	RegistrationWebhook             Webhook
+	WorkWebhook                     Webhook
+	RegistrationFeatureGates        []string
+	WorkFeatureGates                []string
+	AddOnManagerImage               string
+	AddOnManagerEnabled             bool
+	MWReplicaSetEnabled             bool
+	AutoApproveUsers                string
+	WebhookTLSMinVersion            string
+	WebhookTLSCipherSuites          string
+	StatusAggregationEnabled        bool
+	ClusterProfileNamespace         string
+	ClusterSetBindingRulesConfigMap string
+	TrustedCABundle                 string
+	TrustedCABundleHash             string
+	ClusterNamePattern              string
+	ClusterNameMaxLength            int
+	ClusterNameReservedPrefixes     string
+	ClusterLeaseDurationSeconds     int
+	ClusterDefaultClusterSet        string
+	NormalizeClusterClientURLs      bool
+	ClusterApprovalExpiration       string
+	CSRApprovalSigners              []string
+	ImportBootstrapKubeconfigSecret string
+	BootstrapTokenServiceAccount    string
+	BootstrapHubAPIServer           string
+	ClusterClaimLabelRulesConfigMap string
+	PlacementShardCount             int
+	WorkAuthorRolesEnabled          bool
+	PendingApprovalEnabled          bool
+	ClusterMigrationEnabled         bool
 }
 
 type Webhook struct {
@@ -0,0 +1,222 @@
+package addoncleanup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	certificatesv1informers "k8s.io/client-go/informers/certificates/v1"
+	rbacv1informers "k8s.io/client-go/informers/rbac/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addoninformerv1alpha1 "open-cluster-management.io/api/client/addon/informers/externalversions/addon/v1alpha1"
+	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
+	workv1client "open-cluster-management.io/api/client/work/clientset/versioned"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions/work/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	commonmetrics "open-cluster-management.io/ocm/pkg/common/metrics"
+	"open-cluster-management.io/ocm/pkg/common/queue"
+)
+
+const (
+	// AddonResourceByAddon is the index of the hub resources of addons by the namespace/name of the addon.
+	AddonResourceByAddon = "addonResourceByAddon"
+
+	// orphanGracePeriod is the time an addon resource is kept after it is created even if its addon does not exist,
+	// so the resources created before the addon is synced to the caches, or restored on a hub before the addon, are
+	// not deleted.
+	orphanGracePeriod = 10 * time.Minute
+)
+
+// addonCleanupController deletes the ManifestWorks, CSRs and RoleBindings on the hub which are left behind by the
+// deleted ManagedClusterAddOns. The resources are normally deleted with the addon, but they could be left behind
+// when they are not owned by the addon, e.g. the ClusterManagementAddOn is removed before the addon resources are
+// cleaned up, or the resources are restored on a hub without the addon.
+type addonCleanupController struct {
+	kubeClient                kubernetes.Interface
+	workClient                workv1client.Interface
+	managedClusterAddonLister addonlisterv1alpha1.ManagedClusterAddOnLister
+	workIndexer               cache.Indexer
+	csrIndexer                cache.Indexer
+	roleBindingIndexer        cache.Indexer
+}
+
+func NewAddonCleanupController(
+	kubeClient kubernetes.Interface,
+	workClient workv1client.Interface,
+	addonInformers addoninformerv1alpha1.ManagedClusterAddOnInformer,
+	workInformers workinformers.ManifestWorkInformer,
+	csrInformers certificatesv1informers.CertificateSigningRequestInformer,
+	roleBindingInformers rbacv1informers.RoleBindingInformer,
+	recorder events.Recorder,
+) factory.Controller {
+	c := &addonCleanupController{
+		kubeClient:                kubeClient,
+		workClient:                workClient,
+		managedClusterAddonLister: addonInformers.Lister(),
+		workIndexer:               workInformers.Informer().GetIndexer(),
+		csrIndexer:                csrInformers.Informer().GetIndexer(),
+		roleBindingIndexer:        roleBindingInformers.Informer().GetIndexer(),
+	}
+
+	return factory.New().
+		WithInformersQueueKeysFunc(queue.QueueKeyByMetaNamespaceName, addonInformers.Informer()).
+		WithFilteredEventsInformersQueueKeysFunc(
+			AddonResourceQueueKeys,
+			queue.FileterByLabel(addonapiv1alpha1.AddonLabelKey),
+			workInformers.Informer(), csrInformers.Informer(), roleBindingInformers.Informer()).
+		WithSync(commonmetrics.WithReconcileMetrics("addon-cleanup-controller", c.sync)).
+		ToController("addon-cleanup-controller", recorder)
+}
+
+// IndexAddonResourceByAddon indexes the hub resources of addons by the namespace/name of the addon.
+func IndexAddonResourceByAddon(obj interface{}) ([]string, error) {
+	runtimeObj, ok := obj.(runtime.Object)
+	if !ok {
+		return []string{}, fmt.Errorf("obj %T is not a runtime object", obj)
+	}
+	key, ok := addonKey(runtimeObj)
+	if !ok {
+		return []string{}, nil
+	}
+	return []string{key}, nil
+}
+
+// AddonResourceQueueKeys returns the namespace/name of the addon which the hub resource belongs to as the queue key.
+func AddonResourceQueueKeys(obj runtime.Object) []string {
+	key, ok := addonKey(obj)
+	if !ok {
+		return []string{}
+	}
+	return []string{key}
+}
+
+// addonKey returns the namespace/name of the addon which the hub resource belongs to. The namespace of the addon is
+// the namespace of the resource, or the addon namespace label for the resources of hosted addons, or the cluster name
+// label for the cluster scoped CSRs.
+func addonKey(obj runtime.Object) (string, bool) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", false
+	}
+	labels := accessor.GetLabels()
+	addonName := labels[addonapiv1alpha1.AddonLabelKey]
+	if len(addonName) == 0 {
+		return "", false
+	}
+
+	namespace := accessor.GetNamespace()
+	switch {
+	case len(labels[addonapiv1alpha1.AddonNamespaceLabelKey]) > 0:
+		namespace = labels[addonapiv1alpha1.AddonNamespaceLabelKey]
+	case len(namespace) == 0:
+		namespace = labels[clusterv1.ClusterNameLabelKey]
+	}
+	if len(namespace) == 0 {
+		return "", false
+	}
+	return fmt.Sprintf("%s/%s", namespace, addonName), true
+}
+
+func (c *addonCleanupController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	logger := klog.FromContext(ctx)
+	key := syncCtx.QueueKey()
+
+	namespace, addonName, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		// ignore addon whose key is invalid
+		return nil
+	}
+
+	_, err = c.managedClusterAddonLister.ManagedClusterAddOns(namespace).Get(addonName)
+	switch {
+	case err == nil:
+		return nil
+	case !errors.IsNotFound(err):
+		return err
+	}
+	logger.V(4).Info("Cleaning up orphaned addon resources", "addon", key)
+
+	var errs []error
+	var requeueAfter time.Duration
+	for _, indexer := range []cache.Indexer{c.workIndexer, c.csrIndexer, c.roleBindingIndexer} {
+		objs, err := indexer.ByIndex(AddonResourceByAddon, key)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, obj := range objs {
+			runtimeObj, ok := obj.(runtime.Object)
+			if !ok {
+				continue
+			}
+			accessor, err := meta.Accessor(runtimeObj)
+			if err != nil || accessor.GetDeletionTimestamp() != nil {
+				continue
+			}
+			if age := time.Since(accessor.GetCreationTimestamp().Time); age < orphanGracePeriod {
+				if requeueAfter == 0 || orphanGracePeriod-age < requeueAfter {
+					requeueAfter = orphanGracePeriod - age
+				}
+				continue
+			}
+
+			kind, err := c.deleteResource(ctx, runtimeObj)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			syncCtx.Recorder().Eventf("OrphanedAddonResourceDeleted",
+				"Deleted %s %s of the deleted addon %s", kind, cacheKey(accessor), key)
+		}
+	}
+
+	if requeueAfter > 0 {
+		syncCtx.Queue().AddAfter(key, requeueAfter)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// deleteResource deletes the addon resource and returns its kind.
+func (c *addonCleanupController) deleteResource(ctx context.Context, obj runtime.Object) (string, error) {
+	var kind string
+	var err error
+	switch o := obj.(type) {
+	case *workapiv1.ManifestWork:
+		kind = "ManifestWork"
+		err = c.workClient.WorkV1().ManifestWorks(o.Namespace).Delete(ctx, o.Name, metav1.DeleteOptions{})
+	case *certificatesv1.CertificateSigningRequest:
+		kind = "CertificateSigningRequest"
+		err = c.kubeClient.CertificatesV1().CertificateSigningRequests().Delete(ctx, o.Name, metav1.DeleteOptions{})
+	case *rbacv1.RoleBinding:
+		kind = "RoleBinding"
+		err = c.kubeClient.RbacV1().RoleBindings(o.Namespace).Delete(ctx, o.Name, metav1.DeleteOptions{})
+	default:
+		return "", fmt.Errorf("unsupported addon resource %T", obj)
+	}
+	if err != nil && !errors.IsNotFound(err) {
+		return kind, err
+	}
+	return kind, nil
+}
+
+func cacheKey(accessor metav1.Object) string {
+	if len(accessor.GetNamespace()) == 0 {
+		return accessor.GetName()
+	}
+	return fmt.Sprintf("%s/%s", accessor.GetNamespace(), accessor.GetName())
+}
@@ -0,0 +1,214 @@
+package addoncleanup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	fakeaddon "open-cluster-management.io/api/client/addon/clientset/versioned/fake"
+	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
+	fakework "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+)
+
+func newObjectMeta(namespace, name string, age time.Duration, labels map[string]string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace:         namespace,
+		Name:              name,
+		Labels:            labels,
+		CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+	}
+}
+
+func newWork(namespace, name string, age time.Duration, labels map[string]string) *workapiv1.ManifestWork {
+	return &workapiv1.ManifestWork{ObjectMeta: newObjectMeta(namespace, name, age, labels)}
+}
+
+func newCSR(name string, age time.Duration, labels map[string]string) *certificatesv1.CertificateSigningRequest {
+	return &certificatesv1.CertificateSigningRequest{ObjectMeta: newObjectMeta("", name, age, labels)}
+}
+
+func newRoleBinding(namespace, name string, age time.Duration, labels map[string]string) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{ObjectMeta: newObjectMeta(namespace, name, age, labels)}
+}
+
+func TestIndexAddonResourceByAddon(t *testing.T) {
+	cases := []struct {
+		name     string
+		obj      interface{}
+		expected []string
+	}{
+		{
+			name:     "work",
+			obj:      newWork("cluster1", "addon-test-deploy-0", 0, map[string]string{addonapiv1alpha1.AddonLabelKey: "test"}),
+			expected: []string{"cluster1/test"},
+		},
+		{
+			name: "hosted work",
+			obj: newWork("hosting", "addon-test-deploy-0", 0, map[string]string{
+				addonapiv1alpha1.AddonLabelKey:          "test",
+				addonapiv1alpha1.AddonNamespaceLabelKey: "cluster1",
+			}),
+			expected: []string{"cluster1/test"},
+		},
+		{
+			name: "csr",
+			obj: newCSR("addon-cluster1-test-abcde", 0, map[string]string{
+				addonapiv1alpha1.AddonLabelKey: "test",
+				clusterv1.ClusterNameLabelKey:  "cluster1",
+			}),
+			expected: []string{"cluster1/test"},
+		},
+		{
+			name:     "csr without cluster",
+			obj:      newCSR("addon-cluster1-test-abcde", 0, map[string]string{addonapiv1alpha1.AddonLabelKey: "test"}),
+			expected: []string{},
+		},
+		{
+			name:     "not addon resource",
+			obj:      newRoleBinding("cluster1", "test", 0, nil),
+			expected: []string{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			keys, err := IndexAddonResourceByAddon(c.obj)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(keys) != len(c.expected) || (len(keys) > 0 && keys[0] != c.expected[0]) {
+				t.Errorf("expected keys %v, but got %v", c.expected, keys)
+			}
+		})
+	}
+}
+
+func TestSync(t *testing.T) {
+	addonLabels := map[string]string{addonapiv1alpha1.AddonLabelKey: "test"}
+	csrLabels := map[string]string{addonapiv1alpha1.AddonLabelKey: "test", clusterv1.ClusterNameLabelKey: "cluster1"}
+
+	cases := []struct {
+		name                string
+		addons              []runtime.Object
+		works               []runtime.Object
+		kubeObjects         []runtime.Object
+		validateWorkActions func(t *testing.T, actions []clienttesting.Action)
+		validateKubeActions func(t *testing.T, actions []clienttesting.Action)
+	}{
+		{
+			name: "addon exists",
+			addons: []runtime.Object{&addonapiv1alpha1.ManagedClusterAddOn{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "cluster1"},
+			}},
+			works:               []runtime.Object{newWork("cluster1", "addon-test-deploy-0", time.Hour, addonLabels)},
+			kubeObjects:         []runtime.Object{newCSR("addon-cluster1-test-abcde", time.Hour, csrLabels)},
+			validateWorkActions: testingcommon.AssertNoActions,
+			validateKubeActions: testingcommon.AssertNoActions,
+		},
+		{
+			name:  "delete orphaned resources",
+			works: []runtime.Object{newWork("cluster1", "addon-test-deploy-0", time.Hour, addonLabels)},
+			kubeObjects: []runtime.Object{
+				newCSR("addon-cluster1-test-abcde", time.Hour, csrLabels),
+				newRoleBinding("cluster1", "open-cluster-management:test:clusterrole:agent", time.Hour, addonLabels),
+			},
+			validateWorkActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "delete")
+			},
+			validateKubeActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "delete", "delete")
+				if actions[0].GetResource().Resource != "certificatesigningrequests" ||
+					actions[1].GetResource().Resource != "rolebindings" {
+					t.Errorf("unexpected actions %v", actions)
+				}
+			},
+		},
+		{
+			name: "orphaned resources in grace period",
+			works: []runtime.Object{
+				newWork("cluster1", "addon-test-deploy-0", time.Minute, addonLabels),
+				newWork("cluster1", "addon-other-deploy-0", time.Hour, map[string]string{addonapiv1alpha1.AddonLabelKey: "other"}),
+			},
+			validateWorkActions: testingcommon.AssertNoActions,
+			validateKubeActions: testingcommon.AssertNoActions,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			addonClient := fakeaddon.NewSimpleClientset(c.addons...)
+			addonInformers := addoninformers.NewSharedInformerFactory(addonClient, 10*time.Minute)
+			for _, obj := range c.addons {
+				if err := addonInformers.Addon().V1alpha1().ManagedClusterAddOns().Informer().GetStore().Add(obj); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			workClient := fakework.NewSimpleClientset(c.works...)
+			workInformers := workinformers.NewSharedInformerFactory(workClient, 10*time.Minute)
+			workInformer := workInformers.Work().V1().ManifestWorks().Informer()
+			if err := workInformer.AddIndexers(cache.Indexers{AddonResourceByAddon: IndexAddonResourceByAddon}); err != nil {
+				t.Fatal(err)
+			}
+			for _, obj := range c.works {
+				if err := workInformer.GetStore().Add(obj); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			kubeClient := fakekube.NewSimpleClientset(c.kubeObjects...)
+			kubeInformers := kubeinformers.NewSharedInformerFactory(kubeClient, 10*time.Minute)
+			csrInformer := kubeInformers.Certificates().V1().CertificateSigningRequests().Informer()
+			roleBindingInformer := kubeInformers.Rbac().V1().RoleBindings().Informer()
+			for _, informer := range []cache.SharedIndexInformer{csrInformer, roleBindingInformer} {
+				if err := informer.AddIndexers(cache.Indexers{AddonResourceByAddon: IndexAddonResourceByAddon}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, obj := range c.kubeObjects {
+				switch obj.(type) {
+				case *certificatesv1.CertificateSigningRequest:
+					if err := csrInformer.GetStore().Add(obj); err != nil {
+						t.Fatal(err)
+					}
+				case *rbacv1.RoleBinding:
+					if err := roleBindingInformer.GetStore().Add(obj); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			syncContext := testingcommon.NewFakeSyncContext(t, "cluster1/test")
+			controller := NewAddonCleanupController(
+				kubeClient,
+				workClient,
+				addonInformers.Addon().V1alpha1().ManagedClusterAddOns(),
+				workInformers.Work().V1().ManifestWorks(),
+				kubeInformers.Certificates().V1().CertificateSigningRequests(),
+				kubeInformers.Rbac().V1().RoleBindings(),
+				syncContext.Recorder(),
+			)
+
+			if err := controller.Sync(context.TODO(), syncContext); err != nil {
+				t.Errorf("expected no error when sync: %v", err)
+			}
+			c.validateWorkActions(t, workClient.Actions())
+			c.validateKubeActions(t, kubeClient.Actions())
+		})
+	}
+}
@@ -2,12 +2,19 @@ package addonconfiguration
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
 
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
+	"open-cluster-management.io/addon-framework/pkg/utils"
 	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 	addonv1alpha1client "open-cluster-management.io/api/client/addon/clientset/versioned"
 
+	"open-cluster-management.io/ocm/pkg/common/helpers"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 )
 
@@ -20,7 +27,8 @@ func (d *managedClusterAddonConfigurationReconciler) reconcile(
 	var errs []error
 
 	for _, addon := range graph.getAddonsToUpdate() {
-		mca := d.mergeAddonConfig(addon.mca, addon.desiredConfigs)
+		mca := d.mergeAddonConfig(addon.mca, addon.desiredConfigs, addon.mergedConfigs)
+		d.setDeploymentConfigMergedCondition(ctx, mca, len(addon.mergedConfigs) > 0)
 		patcher := patcher.NewPatcher[
 			*addonv1alpha1.ManagedClusterAddOn, addonv1alpha1.ManagedClusterAddOnSpec, addonv1alpha1.ManagedClusterAddOnStatus](
 			d.addonClient.AddonV1alpha1().ManagedClusterAddOns(mca.Namespace))
@@ -34,13 +42,27 @@ func (d *managedClusterAddonConfigurationReconciler) reconcile(
 }
 
 func (d *managedClusterAddonConfigurationReconciler) mergeAddonConfig(
-	mca *addonv1alpha1.ManagedClusterAddOn, desiredConfigMap addonConfigMap) *addonv1alpha1.ManagedClusterAddOn {
+	mca *addonv1alpha1.ManagedClusterAddOn, desiredConfigMap addonConfigMap,
+	lowerConfigs []addonv1alpha1.ConfigReference) *addonv1alpha1.ManagedClusterAddOn {
 	mcaCopy := mca.DeepCopy()
 
+	// keep one existing config for each desired config group resource, prefer the one with the desired referent
+	existing := map[addonv1alpha1.ConfigGroupResource]int{}
+	for i, config := range mcaCopy.Status.ConfigReferences {
+		desired, ok := desiredConfigMap[config.ConfigGroupResource]
+		if !ok {
+			continue
+		}
+		if j, found := existing[config.ConfigGroupResource]; !found ||
+			(config.ConfigReferent == desired.ConfigReferent && mcaCopy.Status.ConfigReferences[j].ConfigReferent != desired.ConfigReferent) {
+			existing[config.ConfigGroupResource] = i
+		}
+	}
+
 	var mergedConfigs []addonv1alpha1.ConfigReference
 	// remove configs that are not desired
-	for _, config := range mcaCopy.Status.ConfigReferences {
-		if _, ok := desiredConfigMap[config.ConfigGroupResource]; ok {
+	for i, config := range mcaCopy.Status.ConfigReferences {
+		if j, ok := existing[config.ConfigGroupResource]; ok && i == j {
 			mergedConfigs = append(mergedConfigs, config)
 		}
 	}
@@ -67,6 +89,72 @@ func (d *managedClusterAddonConfigurationReconciler) mergeAddonConfig(
 		}
 	}
 
+	// insert the lower priority configs before the desired config of the same config group resource, so the
+	// configs in the status are ordered by priority from low to high.
+	if len(lowerConfigs) > 0 {
+		var configs []addonv1alpha1.ConfigReference
+		for _, config := range mergedConfigs {
+			if config.ConfigGroupResource == lowerConfigs[0].ConfigGroupResource {
+				for _, lower := range lowerConfigs {
+					configs = append(configs, lowerConfigReference(mca.Status.ConfigReferences, lower))
+				}
+			}
+			configs = append(configs, config)
+		}
+		mergedConfigs = configs
+	}
+
 	mcaCopy.Status.ConfigReferences = mergedConfigs
 	return mcaCopy
 }
+
+// lowerConfigReference returns the lower priority config to set in the status, the last applied config and the
+// observed generation are kept if the config is already in the status.
+func lowerConfigReference(
+	configs []addonv1alpha1.ConfigReference, lower addonv1alpha1.ConfigReference) addonv1alpha1.ConfigReference {
+	for _, config := range configs {
+		if config.ConfigGroupResource == lower.ConfigGroupResource && config.ConfigReferent == lower.ConfigReferent {
+			config.DesiredConfig = lower.DesiredConfig.DeepCopy()
+			return *config.DeepCopy()
+		}
+	}
+	return lower
+}
+
+// setDeploymentConfigMergedCondition records the effective AddOnDeploymentConfig of the mca in a condition if more
+// than one AddOnDeploymentConfig is referenced, or removes the condition otherwise.
+func (d *managedClusterAddonConfigurationReconciler) setDeploymentConfigMergedCondition(
+	ctx context.Context, mca *addonv1alpha1.ManagedClusterAddOn, merged bool) {
+	if !merged {
+		meta.RemoveStatusCondition(&mca.Status.Conditions, helpers.AddonConditionDeploymentConfigMerged)
+		return
+	}
+
+	var names []string
+	for _, referent := range helpers.GetAddOnDeploymentConfigReferents(mca) {
+		names = append(names, fmt.Sprintf("%s/%s", referent.Namespace, referent.Name))
+	}
+
+	spec, err := helpers.GetEffectiveAddOnDeploymentConfig(ctx, mca, utils.NewAddOnDeploymentConfigGetter(d.addonClient))
+	if err != nil {
+		meta.SetStatusCondition(&mca.Status.Conditions, metav1.Condition{
+			Type:    helpers.AddonConditionDeploymentConfigMerged,
+			Status:  metav1.ConditionFalse,
+			Reason:  "MergeFailed",
+			Message: fmt.Sprintf("Failed to merge AddOnDeploymentConfigs %s: %v", strings.Join(names, ", "), err),
+		})
+		return
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		data = []byte(err.Error())
+	}
+	meta.SetStatusCondition(&mca.Status.Conditions, metav1.Condition{
+		Type:   helpers.AddonConditionDeploymentConfigMerged,
+		Status: metav1.ConditionTrue,
+		Reason: "Merged",
+		Message: fmt.Sprintf("AddOnDeploymentConfigs %s are merged in order, the effective config is %s",
+			strings.Join(names, ", "), string(data)),
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -33,6 +34,7 @@ func TestAddonConfigReconcile(t *testing.T) {
 	cases := []struct {
 		name                   string
 		managedClusteraddon    []runtime.Object
+		deploymentConfigs      []runtime.Object
 		clusterManagementAddon *addonv1alpha1.ClusterManagementAddOn
 		placements             []runtime.Object
 		placementDecisions     []runtime.Object
@@ -835,6 +837,72 @@ func TestAddonConfigReconcile(t *testing.T) {
 				}})
 			},
 		},
+		{
+			name: "mca merge addon deployment configs",
+			managedClusteraddon: []runtime.Object{
+				func() *addonv1alpha1.ManagedClusterAddOn {
+					mca := newManagedClusterAddon("test", "cluster1", []addonv1alpha1.AddOnConfig{
+						newDeploymentConfig("cluster1", "override"),
+					}, []addonv1alpha1.ConfigReference{func() addonv1alpha1.ConfigReference {
+						config := newAppliedDeploymentConfigReference("cluster1", "override", "<override-hash>")
+						config.LastObservedGeneration = 1
+						return config
+					}()}, nil)
+					mca.Annotations = map[string]string{helpers.AddonMergeInheritedConfigsAnnotationKey: "true"}
+					return mca
+				}(),
+			},
+			deploymentConfigs: []runtime.Object{
+				&addonv1alpha1.AddOnDeploymentConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: "global", Namespace: "default"},
+					Spec: addonv1alpha1.AddOnDeploymentConfigSpec{
+						CustomizedVariables: []addonv1alpha1.CustomizedVariable{{Name: "a", Value: "global"}, {Name: "b", Value: "global"}},
+					},
+				},
+				&addonv1alpha1.AddOnDeploymentConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: "override", Namespace: "cluster1"},
+					Spec: addonv1alpha1.AddOnDeploymentConfigSpec{
+						CustomizedVariables: []addonv1alpha1.CustomizedVariable{{Name: "a", Value: "override"}},
+					},
+				},
+			},
+			clusterManagementAddon: addontesting.NewClusterManagementAddon("test", "", "").WithSupportedConfigs(addonv1alpha1.ConfigMeta{
+				ConfigGroupResource: helpers.AddOnDeploymentConfigGroupResource,
+				DefaultConfig:       &addonv1alpha1.ConfigReferent{Namespace: "default", Name: "global"},
+			}).WithDefaultConfigReferences(addonv1alpha1.DefaultConfigReference{
+				ConfigGroupResource: helpers.AddOnDeploymentConfigGroupResource,
+				DesiredConfig: &v1alpha1.ConfigSpecHash{
+					ConfigReferent: v1alpha1.ConfigReferent{Namespace: "default", Name: "global"},
+					SpecHash:       "<global-hash>",
+				},
+			}).Build(),
+			placements:         []runtime.Object{},
+			placementDecisions: []runtime.Object{},
+			validateAddonActions: func(t *testing.T, actions []clienttesting.Action) {
+				addontesting.AssertActions(t, actions, "get", "get", "patch")
+				override := newAppliedDeploymentConfigReference("cluster1", "override", "<override-hash>")
+				override.LastObservedGeneration = 1
+				expectPatchConfigurationAction(t, actions[2], []addonv1alpha1.ConfigReference{
+					newDeploymentConfigReference("default", "global", "<global-hash>"),
+					override,
+				})
+
+				patch := actions[2].(clienttesting.PatchActionImpl).GetPatch()
+				mca := &addonv1alpha1.ManagedClusterAddOn{}
+				if err := json.Unmarshal(patch, mca); err != nil {
+					t.Fatal(err)
+				}
+				cond := meta.FindStatusCondition(mca.Status.Conditions, helpers.AddonConditionDeploymentConfigMerged)
+				if cond == nil || cond.Status != metav1.ConditionTrue {
+					t.Fatalf("expected condition %s to be true, but got %v", helpers.AddonConditionDeploymentConfigMerged, cond)
+				}
+				expectedMessage := `AddOnDeploymentConfigs default/global, cluster1/override are merged in order, ` +
+					`the effective config is {"customizedVariables":[{"name":"a","value":"override"},{"name":"b","value":"global"}],"proxyConfig":{}}`
+				if cond.Message != expectedMessage {
+					t.Errorf("expected message %q, but got %q", expectedMessage, cond.Message)
+				}
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -842,7 +910,7 @@ func TestAddonConfigReconcile(t *testing.T) {
 			logger, _ := ktesting.NewTestContext(t)
 			clusterObj := append(c.placements, c.placementDecisions...)
 			fakeClusterClient := fakecluster.NewSimpleClientset(clusterObj...)
-			fakeAddonClient := fakeaddon.NewSimpleClientset(c.managedClusteraddon...)
+			fakeAddonClient := fakeaddon.NewSimpleClientset(append(c.managedClusteraddon, c.deploymentConfigs...)...)
 
 			addonInformers := addoninformers.NewSharedInformerFactory(fakeAddonClient, 10*time.Minute)
 			clusterInformers := clusterv1informers.NewSharedInformerFactory(fakeClusterClient, 10*time.Minute)
@@ -19,6 +19,7 @@ import (
 	clusterlisterv1beta1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
 
 	"open-cluster-management.io/ocm/pkg/common/helpers"
+	commonmetrics "open-cluster-management.io/ocm/pkg/common/metrics"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 )
@@ -89,7 +90,8 @@ func NewAddonConfigurationController(
 		WithInformersQueueKeysFunc(
 			index.ClusterManagementAddonByPlacementQueueKey(clusterManagementAddonInformers), placementInformer.Informer())
 
-	return controllerFactory.WithSync(c.sync).ToController("addon-configuration-controller", recorder)
+	return controllerFactory.WithSync(commonmetrics.WithReconcileMetrics("addon-configuration-controller", c.sync)).
+		ToController("addon-configuration-controller", recorder)
 }
 
 func (c *addonConfigurationController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
@@ -159,6 +161,12 @@ func (c *addonConfigurationController) buildConfigurationGraph(logger klog.Logge
 		return graph, nil
 	}
 
+	decisionGroupConfigs, err := getDecisionGroupConfigs(cma)
+	if err != nil {
+		logger.Info("Ignore the invalid decision group configs", "addonName", cma.Name, "error", err)
+	}
+	graph.decisionGroupConfigs = decisionGroupConfigs
+
 	// check each install strategy in status
 	var errs []error
 	for _, installProgression := range cma.Status.InstallProgressions {
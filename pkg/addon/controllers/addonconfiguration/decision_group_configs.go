@@ -0,0 +1,232 @@
+package addonconfiguration
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+)
+
+const (
+	// DecisionGroupConfigsAnnotationKey is the annotation on the ClusterManagementAddOn to override the configs of the
+	// install strategy of a placement for the clusters in some of its decision groups, so a config change could be
+	// rolled out to a canary group first with the ProgressivePerGroup rollout strategy. The value is a JSON list, each
+	// item has the name and namespace of the placement, the groupName or the groupIndex of the decision group, and the
+	// configs of the group. For example:
+	//
+	//	[{"name": "global", "namespace": "default", "groupName": "canary",
+	//	  "configs": [{"group": "addon.open-cluster-management.io", "resource": "addondeploymentconfigs",
+	//	    "name": "canary-config", "namespace": "default"}]}]
+	//
+	// The configs in the ManagedClusterAddOn spec still have a higher priority.
+	DecisionGroupConfigsAnnotationKey = "addon.open-cluster-management.io/decision-group-configs"
+
+	// InstallProgressionConditionDecisionGroupConfigs is the condition type in the install progression of a placement
+	// reporting the number of clusters on each version of the configs overridden per decision group.
+	InstallProgressionConditionDecisionGroupConfigs = "DecisionGroupConfigs"
+
+	DecisionGroupConfigsReasonReported = "Reported"
+)
+
+// decisionGroupConfig is the configs of the clusters in a decision group of a placement.
+type decisionGroupConfig struct {
+	addonv1alpha1.PlacementRef `json:",inline"`
+
+	// GroupName is the name of the decision group, it takes precedence over the GroupIndex if it is set.
+	GroupName string `json:"groupName,omitempty"`
+
+	// GroupIndex is the index of the decision group.
+	GroupIndex int32 `json:"groupIndex,omitempty"`
+
+	Configs []addonv1alpha1.AddOnConfig `json:"configs"`
+}
+
+func (c decisionGroupConfig) matches(groupKey clusterv1beta1.GroupKey) bool {
+	if len(c.GroupName) > 0 {
+		return c.GroupName == groupKey.GroupName
+	}
+	return c.GroupIndex == groupKey.GroupIndex
+}
+
+// getDecisionGroupConfigs returns the decision group configs in the annotation of the addon by placement.
+func getDecisionGroupConfigs(
+	cma *addonv1alpha1.ClusterManagementAddOn) (map[addonv1alpha1.PlacementRef][]decisionGroupConfig, error) {
+	value, ok := cma.GetAnnotations()[DecisionGroupConfigsAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var groupConfigs []decisionGroupConfig
+	if err := json.Unmarshal([]byte(value), &groupConfigs); err != nil {
+		return nil, fmt.Errorf("the annotation %s is not a list of decision group configs: %v",
+			DecisionGroupConfigsAnnotationKey, err)
+	}
+
+	configsByPlacement := map[addonv1alpha1.PlacementRef][]decisionGroupConfig{}
+	for _, groupConfig := range groupConfigs {
+		if len(groupConfig.Name) == 0 || len(groupConfig.Namespace) == 0 {
+			return nil, fmt.Errorf("the placement of the decision group configs in the annotation %s is not set",
+				DecisionGroupConfigsAnnotationKey)
+		}
+		for _, config := range groupConfig.Configs {
+			if len(config.Resource) == 0 || len(config.Name) == 0 {
+				return nil, fmt.Errorf("the resource and name of the configs of placement %s/%s in the annotation %s "+
+					"are required", groupConfig.Namespace, groupConfig.Name, DecisionGroupConfigsAnnotationKey)
+			}
+		}
+		configsByPlacement[groupConfig.PlacementRef] = append(configsByPlacement[groupConfig.PlacementRef], groupConfig)
+	}
+	return configsByPlacement, nil
+}
+
+// setGroupDesiredConfigs overrides the desired configs of the install strategy node for the clusters in the decision
+// groups with configs, the latter one wins if a decision group matches more than one of them.
+func (n *installStrategyNode) setGroupDesiredConfigs(groupConfigs []decisionGroupConfig) {
+	if len(groupConfigs) == 0 {
+		return
+	}
+
+	n.groupKeys = map[string]clusterv1beta1.GroupKey{}
+	n.groupDesiredConfigs = map[string]addonConfigMap{}
+	for groupKey, clusters := range n.pdTracker.ExistingClusterGroupsBesides() {
+		var desiredConfigs addonConfigMap
+		for _, groupConfig := range groupConfigs {
+			if !groupConfig.matches(groupKey) {
+				continue
+			}
+			if desiredConfigs == nil {
+				desiredConfigs = n.desiredConfigs.copy()
+			}
+			for _, config := range groupConfig.Configs {
+				desiredConfigs[config.ConfigGroupResource] = addonv1alpha1.ConfigReference{
+					ConfigGroupResource: config.ConfigGroupResource,
+					ConfigReferent:      config.ConfigReferent,
+					DesiredConfig: &addonv1alpha1.ConfigSpecHash{
+						ConfigReferent: config.ConfigReferent,
+					},
+				}
+				if !containsConfigGroupResource(n.groupConfigResources, config.ConfigGroupResource) {
+					n.groupConfigResources = append(n.groupConfigResources, config.ConfigGroupResource)
+				}
+			}
+		}
+		for cluster := range clusters {
+			n.groupKeys[cluster] = groupKey
+			if desiredConfigs != nil {
+				n.groupDesiredConfigs[cluster] = desiredConfigs
+			}
+		}
+	}
+}
+
+// inheritedConfigs returns the desired configs of the install strategy node for the cluster, which are the configs
+// of its decision group if there is one.
+func (n *installStrategyNode) inheritedConfigs(clusterName string) addonConfigMap {
+	if desiredConfigs, ok := n.groupDesiredConfigs[clusterName]; ok {
+		return desiredConfigs
+	}
+	return n.desiredConfigs
+}
+
+// setAddOnDecisionGroupConfigs reports the number of clusters on each applied version of the configs overridden per
+// decision group in the install progression, group by group, so the progress of a config change could be tracked
+// per decision group.
+func setAddOnDecisionGroupConfigs(installProgression *addonv1alpha1.InstallProgression, placementNode *installStrategyNode) {
+	if len(placementNode.groupConfigResources) == 0 {
+		meta.RemoveStatusCondition(&installProgression.Conditions, InstallProgressionConditionDecisionGroupConfigs)
+		return
+	}
+
+	groupVersions := map[clusterv1beta1.GroupKey]map[string]int{}
+	groupPending := map[clusterv1beta1.GroupKey]int{}
+	for clusterName, addon := range placementNode.children {
+		groupKey, ok := placementNode.groupKeys[clusterName]
+		if !ok {
+			continue
+		}
+		if _, ok := groupVersions[groupKey]; !ok {
+			groupVersions[groupKey] = map[string]int{}
+		}
+		for _, config := range addon.mca.Status.ConfigReferences {
+			if !containsConfigGroupResource(placementNode.groupConfigResources, config.ConfigGroupResource) {
+				continue
+			}
+			if config.LastAppliedConfig == nil || len(config.LastAppliedConfig.SpecHash) == 0 {
+				groupPending[groupKey]++
+				continue
+			}
+			specHash := config.LastAppliedConfig.SpecHash
+			if len(specHash) > templateVersionHashLen {
+				specHash = specHash[:templateVersionHashLen]
+			}
+			name := config.LastAppliedConfig.Name
+			if len(config.LastAppliedConfig.Namespace) > 0 {
+				name = fmt.Sprintf("%s/%s", config.LastAppliedConfig.Namespace, name)
+			}
+			groupVersions[groupKey][fmt.Sprintf("%s %s (%s)", config.Resource, name, specHash)]++
+		}
+	}
+
+	groupKeys := make([]clusterv1beta1.GroupKey, 0, len(groupVersions))
+	for groupKey := range groupVersions {
+		groupKeys = append(groupKeys, groupKey)
+	}
+	sort.Slice(groupKeys, func(i, j int) bool {
+		return groupKeys[i].GroupIndex < groupKeys[j].GroupIndex
+	})
+
+	var groups []string
+	for _, groupKey := range groupKeys {
+		versions := groupVersions[groupKey]
+		var names []string
+		for name := range versions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var counts []string
+		for _, name := range names {
+			counts = append(counts, fmt.Sprintf("%d on %s", versions[name], name))
+		}
+		if groupPending[groupKey] > 0 {
+			counts = append(counts, fmt.Sprintf("%d pending", groupPending[groupKey]))
+		}
+		if len(counts) == 0 {
+			continue
+		}
+		groups = append(groups, fmt.Sprintf("%s: %s", decisionGroupName(groupKey), strings.Join(counts, ", ")))
+	}
+	if len(groups) == 0 {
+		meta.RemoveStatusCondition(&installProgression.Conditions, InstallProgressionConditionDecisionGroupConfigs)
+		return
+	}
+
+	meta.SetStatusCondition(&installProgression.Conditions, metav1.Condition{
+		Type:    InstallProgressionConditionDecisionGroupConfigs,
+		Status:  metav1.ConditionTrue,
+		Reason:  DecisionGroupConfigsReasonReported,
+		Message: fmt.Sprintf("Config versions per decision group: %s", strings.Join(groups, "; ")),
+	})
+}
+
+func decisionGroupName(groupKey clusterv1beta1.GroupKey) string {
+	if len(groupKey.GroupName) > 0 {
+		return groupKey.GroupName
+	}
+	return fmt.Sprintf("group %d", groupKey.GroupIndex)
+}
+
+func containsConfigGroupResource(resources []addonv1alpha1.ConfigGroupResource, resource addonv1alpha1.ConfigGroupResource) bool {
+	for _, r := range resources {
+		if r == resource {
+			return true
+		}
+	}
+	return false
+}
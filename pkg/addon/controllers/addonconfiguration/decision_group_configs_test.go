@@ -0,0 +1,241 @@
+package addonconfiguration
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"open-cluster-management.io/addon-framework/pkg/addonmanager/addontesting"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	fakecluster "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterv1informers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/common/helpers"
+)
+
+func TestGetDecisionGroupConfigs(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotation  string
+		expected    map[addonv1alpha1.PlacementRef][]decisionGroupConfig
+		expectedErr bool
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name: "configs of decision groups",
+			annotation: `[{"name": "placement1", "namespace": "default", "groupName": "canary",
+				"configs": [{"group": "addon.open-cluster-management.io", "resource": "addondeploymentconfigs",
+				"name": "canary", "namespace": "default"}]},
+				{"name": "placement1", "namespace": "default", "groupIndex": 1, "configs": []}]`,
+			expected: map[addonv1alpha1.PlacementRef][]decisionGroupConfig{
+				{Name: "placement1", Namespace: "default"}: {
+					{
+						PlacementRef: addonv1alpha1.PlacementRef{Name: "placement1", Namespace: "default"},
+						GroupName:    "canary",
+						Configs:      []addonv1alpha1.AddOnConfig{newDeploymentConfig("default", "canary")},
+					},
+					{
+						PlacementRef: addonv1alpha1.PlacementRef{Name: "placement1", Namespace: "default"},
+						GroupIndex:   1,
+						Configs:      []addonv1alpha1.AddOnConfig{},
+					},
+				},
+			},
+		},
+		{
+			name:        "invalid json",
+			annotation:  `{"name": "placement1"}`,
+			expectedErr: true,
+		},
+		{
+			name:        "placement is not set",
+			annotation:  `[{"groupName": "canary"}]`,
+			expectedErr: true,
+		},
+		{
+			name:        "config name is not set",
+			annotation:  `[{"name": "placement1", "namespace": "default", "configs": [{"resource": "addondeploymentconfigs"}]}]`,
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cma := addontesting.NewClusterManagementAddon("test", "", "").Build()
+			if len(c.annotation) > 0 {
+				cma.Annotations = map[string]string{DecisionGroupConfigsAnnotationKey: c.annotation}
+			}
+
+			actual, err := getDecisionGroupConfigs(cma)
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, c.expected) {
+				t.Errorf("expected %v, but got %v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDecisionGroupConfigsGraph(t *testing.T) {
+	placementRef := addonv1alpha1.PlacementRef{Name: "placement1", Namespace: "default"}
+	canaryConfig := newDeploymentConfigReference("default", "canary", "")
+	canaryConfig.DesiredConfig.SpecHash = "canaryhash"
+
+	fakeClusterClient := fakecluster.NewSimpleClientset()
+	clusterInformers := clusterv1informers.NewSharedInformerFactory(fakeClusterClient, 10*time.Minute)
+	placementDecisionGetter := helpers.PlacementDecisionGetter{Client: clusterInformers.Cluster().V1beta1().PlacementDecisions().Lister()}
+	placementLister := clusterInformers.Cluster().V1beta1().Placements().Lister()
+
+	placement := &clusterv1beta1.Placement{ObjectMeta: metav1.ObjectMeta{Name: placementRef.Name, Namespace: placementRef.Namespace}}
+	if err := clusterInformers.Cluster().V1beta1().Placements().Informer().GetStore().Add(placement); err != nil {
+		t.Fatal(err)
+	}
+	for _, decision := range []*clusterv1beta1.PlacementDecision{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "placement1-0", Namespace: placementRef.Namespace,
+				Labels: map[string]string{
+					clusterv1beta1.PlacementLabel:          placementRef.Name,
+					clusterv1beta1.DecisionGroupNameLabel:  "canary",
+					clusterv1beta1.DecisionGroupIndexLabel: "0",
+				}},
+			Status: clusterv1beta1.PlacementDecisionStatus{Decisions: []clusterv1beta1.ClusterDecision{
+				{ClusterName: "cluster1"}, {ClusterName: "cluster2"},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "placement1-1", Namespace: placementRef.Namespace,
+				Labels: map[string]string{
+					clusterv1beta1.PlacementLabel:          placementRef.Name,
+					clusterv1beta1.DecisionGroupIndexLabel: "1",
+				}},
+			Status: clusterv1beta1.PlacementDecisionStatus{Decisions: []clusterv1beta1.ClusterDecision{
+				{ClusterName: "cluster3"},
+			}},
+		},
+	} {
+		if err := clusterInformers.Cluster().V1beta1().PlacementDecisions().Informer().GetStore().Add(decision); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	graph := newGraph(nil, nil)
+	graph.decisionGroupConfigs = map[addonv1alpha1.PlacementRef][]decisionGroupConfig{
+		placementRef: {
+			{
+				PlacementRef: placementRef,
+				GroupName:    "canary",
+				Configs:      []addonv1alpha1.AddOnConfig{newDeploymentConfig("default", "canary")},
+			},
+		},
+	}
+	graph.addAddonNode(newManagedClusterAddon("test", "cluster1", nil, nil, nil))
+	graph.addAddonNode(newManagedClusterAddon("test", "cluster2", nil, []addonv1alpha1.ConfigReference{canaryConfig}, nil))
+	graph.addAddonNode(newManagedClusterAddon("test", "cluster3", nil, nil, nil))
+
+	err := graph.addPlacementNode(
+		addonv1alpha1.PlacementStrategy{
+			PlacementRef:    placementRef,
+			RolloutStrategy: clusterv1alpha1.RolloutStrategy{Type: clusterv1alpha1.All},
+		},
+		addonv1alpha1.InstallProgression{
+			PlacementRef: placementRef,
+			ConfigReferences: []addonv1alpha1.InstallConfigReference{
+				{
+					ConfigGroupResource: helpers.AddOnDeploymentConfigGroupResource,
+					DesiredConfig: &addonv1alpha1.ConfigSpecHash{
+						ConfigReferent: addonv1alpha1.ConfigReferent{Namespace: "default", Name: "global"},
+						SpecHash:       "globalhash",
+					},
+				},
+			},
+		},
+		placementLister, placementDecisionGetter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := graph.generateRolloutResult(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]addonConfigMap{
+		"cluster1": {helpers.AddOnDeploymentConfigGroupResource: newDeploymentConfigReference("default", "canary", "")},
+		"cluster2": {helpers.AddOnDeploymentConfigGroupResource: canaryConfig},
+		"cluster3": {helpers.AddOnDeploymentConfigGroupResource: newDeploymentConfigReference("default", "global", "globalhash")},
+	}
+	addons := graph.getAddonsToUpdate()
+	if len(addons) != len(expected) {
+		t.Fatalf("expected %d addons to update, but got %d", len(expected), len(addons))
+	}
+	for _, addon := range addons {
+		if !reflect.DeepEqual(addon.desiredConfigs, expected[addon.mca.Namespace]) {
+			t.Errorf("expected desired configs %v of cluster %s, but got %v",
+				expected[addon.mca.Namespace], addon.mca.Namespace, addon.desiredConfigs)
+		}
+	}
+}
+
+func TestSetAddOnDecisionGroupConfigs(t *testing.T) {
+	cases := []struct {
+		name                 string
+		groupConfigResources []addonv1alpha1.ConfigGroupResource
+		expectedMessage      string
+	}{
+		{
+			name: "no decision group configs",
+		},
+		{
+			name:                 "config versions per decision group",
+			groupConfigResources: []addonv1alpha1.ConfigGroupResource{helpers.AddOnDeploymentConfigGroupResource},
+			expectedMessage: "Config versions per decision group: canary: 1 on addondeploymentconfigs default/canary " +
+				"(11111111), 1 pending; group 1: 1 on addondeploymentconfigs default/global (22222222)",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			node := &installStrategyNode{
+				children: map[string]*addonNode{
+					"cluster1": {mca: newManagedClusterAddon("test", "cluster1", nil, []addonv1alpha1.ConfigReference{
+						newAppliedDeploymentConfigReference("default", "canary", "1111111111")}, nil)},
+					"cluster2": {mca: newManagedClusterAddon("test", "cluster2", nil, []addonv1alpha1.ConfigReference{
+						newDeploymentConfigReference("default", "canary", "1111111111")}, nil)},
+					"cluster3": {mca: newManagedClusterAddon("test", "cluster3", nil, []addonv1alpha1.ConfigReference{
+						newAppliedDeploymentConfigReference("default", "global", "2222222222")}, nil)},
+				},
+				groupKeys: map[string]clusterv1beta1.GroupKey{
+					"cluster1": {GroupName: "canary"},
+					"cluster2": {GroupName: "canary"},
+					"cluster3": {GroupIndex: 1},
+				},
+				groupConfigResources: c.groupConfigResources,
+			}
+			installProgression := &addonv1alpha1.InstallProgression{}
+			setAddOnDecisionGroupConfigs(installProgression, node)
+
+			cond := meta.FindStatusCondition(installProgression.Conditions, InstallProgressionConditionDecisionGroupConfigs)
+			if len(c.expectedMessage) == 0 {
+				if cond != nil {
+					t.Errorf("expected no condition, but got %v", cond)
+				}
+				return
+			}
+			if cond == nil || cond.Message != c.expectedMessage {
+				t.Errorf("expected condition message %q, but got %v", c.expectedMessage, cond)
+			}
+		})
+	}
+}
@@ -24,6 +24,8 @@ type configurationGraph struct {
 	nodes []*installStrategyNode
 	// defaults is the nodes with no install strategy
 	defaults *installStrategyNode
+	// decisionGroupConfigs is the configs overridden per decision group of the placements
+	decisionGroupConfigs map[addonv1alpha1.PlacementRef][]decisionGroupConfig
 }
 
 // installStrategyNode is a node in configurationGraph defined by a install strategy
@@ -33,6 +35,12 @@ type installStrategyNode struct {
 	rolloutStrategy clusterv1alpha1.RolloutStrategy
 	rolloutResult   clusterv1alpha1.RolloutResult
 	desiredConfigs  addonConfigMap
+	// groupDesiredConfigs keeps the desired configs of the clusters whose decision group overrides the configs
+	groupDesiredConfigs map[string]addonConfigMap
+	// groupKeys keeps the decision group of each cluster if any decision group overrides the configs
+	groupKeys map[string]clusterv1beta1.GroupKey
+	// groupConfigResources is the config group resources overridden by any decision group
+	groupConfigResources []addonv1alpha1.ConfigGroupResource
 	// children keeps a map of addons node as the children of this node
 	children map[string]*addonNode
 	clusters sets.Set[string]
@@ -42,8 +50,11 @@ type installStrategyNode struct {
 // addonnode
 type addonNode struct {
 	desiredConfigs addonConfigMap
-	mca            *addonv1alpha1.ManagedClusterAddOn
-	status         *clusterv1alpha1.ClusterRolloutStatus
+	// mergedConfigs is the AddOnDeploymentConfigs merged with the desired AddOnDeploymentConfig, ordered by
+	// priority from low to high. The desired AddOnDeploymentConfig has the highest priority.
+	mergedConfigs []addonv1alpha1.ConfigReference
+	mca           *addonv1alpha1.ManagedClusterAddOn
+	status        *clusterv1alpha1.ClusterRolloutStatus
 }
 
 type addonConfigMap map[addonv1alpha1.ConfigGroupResource]addonv1alpha1.ConfigReference
@@ -51,7 +62,7 @@ type addonConfigMap map[addonv1alpha1.ConfigGroupResource]addonv1alpha1.ConfigRe
 // set addon rollout status
 func (n *addonNode) setRolloutStatus() {
 	// desired configs doesn't match actual configs, set to ToApply
-	if len(n.mca.Status.ConfigReferences) != len(n.desiredConfigs) {
+	if len(n.mca.Status.ConfigReferences) != len(n.desiredConfigs)+len(n.mergedConfigs) {
 		n.status = &clusterv1alpha1.ClusterRolloutStatus{Status: clusterv1alpha1.ToApply}
 		return
 	}
@@ -65,7 +76,7 @@ func (n *addonNode) setRolloutStatus() {
 	}
 
 	for _, actual := range n.mca.Status.ConfigReferences {
-		if desired, ok := n.desiredConfigs[actual.ConfigGroupResource]; ok {
+		if desired, ok := n.getDesiredConfig(actual); ok {
 			// desired config spec hash doesn't match actual, set to ToApply
 			if !equality.Semantic.DeepEqual(desired.DesiredConfig, actual.DesiredConfig) {
 				n.status = &clusterv1alpha1.ClusterRolloutStatus{Status: clusterv1alpha1.ToApply}
@@ -102,6 +113,24 @@ func (n *addonNode) setRolloutStatus() {
 
 }
 
+// getDesiredConfig returns the desired config of an actual config in the mca status. A config group resource has
+// more than one desired config if the AddOnDeploymentConfigs are merged, so they are matched by the referent.
+func (n *addonNode) getDesiredConfig(actual addonv1alpha1.ConfigReference) (addonv1alpha1.ConfigReference, bool) {
+	desired, ok := n.desiredConfigs[actual.ConfigGroupResource]
+	if !ok || len(n.mergedConfigs) == 0 || actual.ConfigGroupResource != helpers.AddOnDeploymentConfigGroupResource {
+		return desired, ok
+	}
+	if desired.ConfigReferent == actual.ConfigReferent {
+		return desired, true
+	}
+	for _, merged := range n.mergedConfigs {
+		if merged.ConfigReferent == actual.ConfigReferent {
+			return merged, true
+		}
+	}
+	return addonv1alpha1.ConfigReference{}, false
+}
+
 func (d addonConfigMap) copy() addonConfigMap {
 	output := addonConfigMap{}
 	for k, v := range d {
@@ -225,6 +254,9 @@ func (g *configurationGraph) addPlacementNode(
 		}
 	}
 
+	// overrides configuration by decision groups
+	node.setGroupDesiredConfigs(g.decisionGroupConfigs[placementRef])
+
 	// remove addon in defaults and other placements.
 	for _, cluster := range node.clusters.UnsortedList() {
 		if _, ok := g.defaults.children[cluster]; ok {
@@ -277,7 +309,26 @@ func (g *configurationGraph) getAddonsToUpdate() []*addonNode {
 func (n *installStrategyNode) addNode(addon *addonv1alpha1.ManagedClusterAddOn) {
 	n.children[addon.Namespace] = &addonNode{
 		mca:            addon,
-		desiredConfigs: n.desiredConfigs,
+		desiredConfigs: n.inheritedConfigs(addon.Namespace),
+	}
+
+	// the spechash of the decision group configs is not in the cma status, copy it from mca status
+	if groupConfigs, ok := n.groupDesiredConfigs[addon.Namespace]; ok {
+		desiredConfigs := groupConfigs.copy()
+		for configGroupResource, desired := range desiredConfigs {
+			if desired.DesiredConfig == nil || len(desired.DesiredConfig.SpecHash) > 0 {
+				continue
+			}
+			desired.DesiredConfig = desired.DesiredConfig.DeepCopy()
+			for _, configRef := range addon.Status.ConfigReferences {
+				if configRef.ConfigGroupResource == configGroupResource && configRef.DesiredConfig != nil &&
+					configRef.DesiredConfig.ConfigReferent == desired.ConfigReferent {
+					desired.DesiredConfig.SpecHash = configRef.DesiredConfig.SpecHash
+				}
+			}
+			desiredConfigs[configGroupResource] = desired
+		}
+		n.children[addon.Namespace].desiredConfigs = desiredConfigs
 	}
 
 	// override configuration by mca spec
@@ -303,12 +354,78 @@ func (n *installStrategyNode) addNode(addon *addonv1alpha1.ManagedClusterAddOn)
 				}
 			}
 		}
+		n.children[addon.Namespace].mergedConfigs = n.mergedDeploymentConfigs(addon, n.children[addon.Namespace].desiredConfigs)
 	}
 
 	// set addon node rollout status
 	n.children[addon.Namespace].setRolloutStatus()
 }
 
+// mergedDeploymentConfigs returns the AddOnDeploymentConfigs merged with the desired AddOnDeploymentConfig of the
+// mca, ordered by priority from low to high:
+//  1. the AddOnDeploymentConfig inherited from the install strategy or the defaults, if the mca has the annotation
+//     to merge the inherited configs.
+//  2. the AddOnDeploymentConfigs in the mca spec except the last one, in the order of the spec.
+//
+// The last AddOnDeploymentConfig in the mca spec is the desired one and has the highest priority.
+func (n *installStrategyNode) mergedDeploymentConfigs(
+	addon *addonv1alpha1.ManagedClusterAddOn, desiredConfigs addonConfigMap) []addonv1alpha1.ConfigReference {
+	desired, ok := desiredConfigs[helpers.AddOnDeploymentConfigGroupResource]
+	if !ok {
+		return nil
+	}
+
+	inheritedConfigs := n.inheritedConfigs(addon.Namespace)
+	var referents []addonv1alpha1.ConfigReferent
+	if addon.Annotations[helpers.AddonMergeInheritedConfigsAnnotationKey] == "true" {
+		if inherited, ok := inheritedConfigs[helpers.AddOnDeploymentConfigGroupResource]; ok {
+			referents = append(referents, inherited.ConfigReferent)
+		}
+	}
+	for _, config := range addon.Spec.Configs {
+		if config.ConfigGroupResource == helpers.AddOnDeploymentConfigGroupResource {
+			referents = append(referents, config.ConfigReferent)
+		}
+	}
+
+	var merged []addonv1alpha1.ConfigReference
+	for i, referent := range referents {
+		// the desired config and duplicated configs are ignored, a config is merged at its last position
+		if referent == desired.ConfigReferent || containsReferent(referents[i+1:], referent) {
+			continue
+		}
+		config := addonv1alpha1.ConfigReference{
+			ConfigGroupResource: helpers.AddOnDeploymentConfigGroupResource,
+			ConfigReferent:      referent,
+			DesiredConfig: &addonv1alpha1.ConfigSpecHash{
+				ConfigReferent: referent,
+			},
+		}
+		if inherited, ok := inheritedConfigs[helpers.AddOnDeploymentConfigGroupResource]; ok &&
+			inherited.ConfigReferent == referent && inherited.DesiredConfig != nil {
+			config.DesiredConfig.SpecHash = inherited.DesiredConfig.SpecHash
+		}
+		// copy the spechash from mca status
+		for _, configRef := range addon.Status.ConfigReferences {
+			if configRef.ConfigGroupResource == helpers.AddOnDeploymentConfigGroupResource &&
+				configRef.DesiredConfig != nil && configRef.DesiredConfig.ConfigReferent == referent {
+				config.DesiredConfig.SpecHash = configRef.DesiredConfig.SpecHash
+			}
+		}
+		merged = append(merged, config)
+	}
+	return merged
+}
+
+func containsReferent(referents []addonv1alpha1.ConfigReferent, referent addonv1alpha1.ConfigReferent) bool {
+	for _, r := range referents {
+		if r == referent {
+			return true
+		}
+	}
+	return false
+}
+
 func (n *installStrategyNode) generateRolloutResult() error {
 	if n.placementRef.Name == "" {
 		// default addons
@@ -365,8 +482,8 @@ func (n *installStrategyNode) getAddonsToUpdate() []*addonNode {
 
 func (n *installStrategyNode) countAddonUpgradeSucceed() int {
 	count := 0
-	for _, addon := range n.children {
-		if desiredConfigsEqual(addon.desiredConfigs, n.desiredConfigs) && addon.status.Status == clusterv1alpha1.Succeeded {
+	for clusterName, addon := range n.children {
+		if n.inheritsConfigs(clusterName, addon) && addon.status.Status == clusterv1alpha1.Succeeded {
 			count += 1
 		}
 	}
@@ -375,14 +492,23 @@ func (n *installStrategyNode) countAddonUpgradeSucceed() int {
 
 func (n *installStrategyNode) countAddonUpgrading() int {
 	count := 0
-	for _, addon := range n.children {
-		if desiredConfigsEqual(addon.desiredConfigs, n.desiredConfigs) && addon.status.Status == clusterv1alpha1.Progressing {
+	for clusterName, addon := range n.children {
+		if n.inheritsConfigs(clusterName, addon) && addon.status.Status == clusterv1alpha1.Progressing {
 			count += 1
 		}
 	}
 	return count
 }
 
+// inheritsConfigs returns if the addon uses the desired configs of the install strategy node, or the ones of its
+// decision group, without overriding them in the mca spec.
+func (n *installStrategyNode) inheritsConfigs(clusterName string, addon *addonNode) bool {
+	if _, ok := n.groupDesiredConfigs[clusterName]; ok {
+		return len(addon.mca.Spec.Configs) == 0
+	}
+	return desiredConfigsEqual(addon.desiredConfigs, n.desiredConfigs)
+}
+
 func (n *installStrategyNode) countAddonTimeOut() int {
 	return len(n.rolloutResult.ClustersTimeOut)
 }
@@ -573,6 +573,59 @@ func TestConfigurationGraph(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "mca merge addon deployment configs",
+			defaultConfigs: []addonv1alpha1.ConfigMeta{
+				{ConfigGroupResource: helpers.AddOnDeploymentConfigGroupResource,
+					DefaultConfig: &addonv1alpha1.ConfigReferent{Namespace: "default", Name: "global"}},
+			},
+			defaultConfigReference: []addonv1alpha1.DefaultConfigReference{
+				{
+					ConfigGroupResource: helpers.AddOnDeploymentConfigGroupResource,
+					DesiredConfig: &addonv1alpha1.ConfigSpecHash{
+						ConfigReferent: addonv1alpha1.ConfigReferent{Namespace: "default", Name: "global"},
+						SpecHash:       "<global-hash>",
+					},
+				},
+			},
+			addons: []*addonv1alpha1.ManagedClusterAddOn{
+				newMergingManagedClusterAddon("cluster1", nil, nil),
+				newManagedClusterAddon("test", "cluster2", []addonv1alpha1.AddOnConfig{
+					newDeploymentConfig("cluster2", "a"), newDeploymentConfig("cluster2", "b"),
+				}, nil, nil),
+				newMergingManagedClusterAddon("cluster3", []addonv1alpha1.ConfigReference{
+					newAppliedDeploymentConfigReference("default", "global", "<global-hash>"),
+					newAppliedDeploymentConfigReference("cluster3", "override", "<override-hash>"),
+				}, []metav1.Condition{{
+					Type:   addonv1alpha1.ManagedClusterAddOnConditionProgressing,
+					Reason: addonv1alpha1.ProgressingReasonInstallSucceed,
+				}}),
+			},
+			expected: []*addonNode{
+				{
+					desiredConfigs: map[addonv1alpha1.ConfigGroupResource]addonv1alpha1.ConfigReference{
+						helpers.AddOnDeploymentConfigGroupResource: newDeploymentConfigReference("cluster1", "override", ""),
+					},
+					mergedConfigs: []addonv1alpha1.ConfigReference{
+						newDeploymentConfigReference("default", "global", "<global-hash>"),
+					},
+					mca:    newMergingManagedClusterAddon("cluster1", nil, nil),
+					status: &clusterv1alpha1.ClusterRolloutStatus{Status: clusterv1alpha1.ToApply},
+				},
+				{
+					desiredConfigs: map[addonv1alpha1.ConfigGroupResource]addonv1alpha1.ConfigReference{
+						helpers.AddOnDeploymentConfigGroupResource: newDeploymentConfigReference("cluster2", "b", ""),
+					},
+					mergedConfigs: []addonv1alpha1.ConfigReference{
+						newDeploymentConfigReference("cluster2", "a", ""),
+					},
+					mca: newManagedClusterAddon("test", "cluster2", []addonv1alpha1.AddOnConfig{
+						newDeploymentConfig("cluster2", "a"), newDeploymentConfig("cluster2", "b"),
+					}, nil, nil),
+					status: &clusterv1alpha1.ClusterRolloutStatus{Status: clusterv1alpha1.ToApply},
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -635,6 +688,9 @@ func TestConfigurationGraph(t *testing.T) {
 						if !reflect.DeepEqual(v.desiredConfigs, ev.desiredConfigs) {
 							t.Errorf("output desiredConfigs is not correct, cluster %s, expected %v, got %v", v.mca.Namespace, ev.desiredConfigs, v.desiredConfigs)
 						}
+						if !reflect.DeepEqual(v.mergedConfigs, ev.mergedConfigs) {
+							t.Errorf("output mergedConfigs is not correct, cluster %s, expected %v, got %v", v.mca.Namespace, ev.mergedConfigs, v.mergedConfigs)
+						}
 						if !reflect.DeepEqual(v.status, ev.status) {
 							t.Errorf("output status is not correct, cluster %s, expected %v, got %v", v.mca.Namespace, ev.status, v.status)
 						}
@@ -675,3 +731,36 @@ func newDefaultConfigReference(group, resource, name, hash string) addonv1alpha1
 		},
 	}
 }
+
+func newMergingManagedClusterAddon(
+	namespace string, configStatus []addonv1alpha1.ConfigReference, conditions []metav1.Condition) *addonv1alpha1.ManagedClusterAddOn {
+	mca := newManagedClusterAddon("test", namespace, []addonv1alpha1.AddOnConfig{
+		newDeploymentConfig(namespace, "override"),
+	}, configStatus, conditions)
+	mca.Annotations = map[string]string{helpers.AddonMergeInheritedConfigsAnnotationKey: "true"}
+	return mca
+}
+
+func newDeploymentConfig(namespace, name string) addonv1alpha1.AddOnConfig {
+	return addonv1alpha1.AddOnConfig{
+		ConfigGroupResource: helpers.AddOnDeploymentConfigGroupResource,
+		ConfigReferent:      addonv1alpha1.ConfigReferent{Namespace: namespace, Name: name},
+	}
+}
+
+func newDeploymentConfigReference(namespace, name, hash string) addonv1alpha1.ConfigReference {
+	return addonv1alpha1.ConfigReference{
+		ConfigGroupResource: helpers.AddOnDeploymentConfigGroupResource,
+		ConfigReferent:      addonv1alpha1.ConfigReferent{Namespace: namespace, Name: name},
+		DesiredConfig: &addonv1alpha1.ConfigSpecHash{
+			ConfigReferent: addonv1alpha1.ConfigReferent{Namespace: namespace, Name: name},
+			SpecHash:       hash,
+		},
+	}
+}
+
+func newAppliedDeploymentConfigReference(namespace, name, hash string) addonv1alpha1.ConfigReference {
+	config := newDeploymentConfigReference(namespace, name, hash)
+	config.LastAppliedConfig = config.DesiredConfig.DeepCopy()
+	return config
+}
@@ -3,17 +3,53 @@ package addonconfiguration
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
+	"open-cluster-management.io/addon-framework/pkg/utils"
 	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 
+	"open-cluster-management.io/ocm/pkg/addon/controllers/metrics"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 )
 
+const (
+	// InstallProgressionConditionTemplateVersions is the condition type in the install progression of a placement
+	// reporting the number of clusters on each version of the addon template.
+	InstallProgressionConditionTemplateVersions = "TemplateVersions"
+
+	TemplateVersionsReasonReported = "Reported"
+
+	// InstallProgressionConditionAddonHealth is the condition type in the install progression of a placement
+	// aggregating the states of the addons on the clusters of the placement. It is true if the addon is available
+	// on all the clusters.
+	InstallProgressionConditionAddonHealth = "AddonHealth"
+
+	AddonHealthReasonAllAvailable    = "AllAvailable"
+	AddonHealthReasonNotAllAvailable = "NotAllAvailable"
+
+	templateVersionHashLen = 8
+
+	// maxUnhealthyAddonsReported is the max number of clusters listed in the addon health condition on which the
+	// addon is not available.
+	maxUnhealthyAddonsReported = 5
+)
+
+// addonStateSeverity orders the states of the addons from the worst, the addons in the worse states are listed first
+// in the addon health condition.
+var addonStateSeverity = map[string]int{
+	metrics.AddonStateDegraded:    0,
+	metrics.AddonStateUnavailable: 1,
+	metrics.AddonStateUnknown:     2,
+	metrics.AddonStateProgressing: 3,
+	metrics.AddonStateAvailable:   4,
+}
+
 type clusterManagementAddonProgressingReconciler struct {
 	patcher patcher.Patcher[
 		*addonv1alpha1.ClusterManagementAddOn, addonv1alpha1.ClusterManagementAddOnSpec, addonv1alpha1.ClusterManagementAddOnStatus]
@@ -48,6 +84,9 @@ func (d *clusterManagementAddonProgressingReconciler) reconcile(
 			placementNode.countAddonTimeOut(),
 			len(placementNode.clusters),
 		)
+		setAddOnTemplateVersions(&cmaCopy.Status.InstallProgressions[i], placementNode)
+		setAddOnHealth(&cmaCopy.Status.InstallProgressions[i], placementNode)
+		setAddOnDecisionGroupConfigs(&cmaCopy.Status.InstallProgressions[i], placementNode)
 	}
 
 	_, err := d.patcher.PatchStatus(ctx, cmaCopy, cmaCopy.Status, cma.Status)
@@ -101,3 +140,115 @@ func setAddOnInstallProgressionsAndLastApplied(
 	}
 	meta.SetStatusCondition(&installProgression.Conditions, condition)
 }
+
+// setAddOnTemplateVersions reports the number of clusters on each applied version of the addon template in the
+// install progression, so the progress of a template change could be tracked per version.
+func setAddOnTemplateVersions(installProgression *addonv1alpha1.InstallProgression, placementNode *installStrategyNode) {
+	versions := map[string]int{}
+	pending, found := 0, false
+	for _, addon := range placementNode.children {
+		for _, config := range addon.mca.Status.ConfigReferences {
+			if config.Group != utils.AddOnTemplateGVR.Group || config.Resource != utils.AddOnTemplateGVR.Resource {
+				continue
+			}
+			found = true
+			if config.LastAppliedConfig == nil || len(config.LastAppliedConfig.SpecHash) == 0 {
+				pending++
+				continue
+			}
+			specHash := config.LastAppliedConfig.SpecHash
+			if len(specHash) > templateVersionHashLen {
+				specHash = specHash[:templateVersionHashLen]
+			}
+			versions[fmt.Sprintf("%s (%s)", config.LastAppliedConfig.Name, specHash)]++
+		}
+	}
+	if !found {
+		meta.RemoveStatusCondition(&installProgression.Conditions, InstallProgressionConditionTemplateVersions)
+		return
+	}
+
+	var names []string
+	for name := range versions {
+		names = append(names, name)
+	}
+	// the versions with more clusters go first
+	sort.Slice(names, func(i, j int) bool {
+		if versions[names[i]] != versions[names[j]] {
+			return versions[names[i]] > versions[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	var counts []string
+	for _, name := range names {
+		counts = append(counts, fmt.Sprintf("%d on %s", versions[name], name))
+	}
+	if pending > 0 {
+		counts = append(counts, fmt.Sprintf("%d pending", pending))
+	}
+	meta.SetStatusCondition(&installProgression.Conditions, metav1.Condition{
+		Type:    InstallProgressionConditionTemplateVersions,
+		Status:  metav1.ConditionTrue,
+		Reason:  TemplateVersionsReasonReported,
+		Message: fmt.Sprintf("Clusters per addon template version: %s", strings.Join(counts, ", ")),
+	})
+}
+
+// setAddOnHealth reports the number of clusters of the placement on which the addon is in each state in the install
+// progression, and lists the clusters on which the addon is in the worst states.
+func setAddOnHealth(installProgression *addonv1alpha1.InstallProgression, placementNode *installStrategyNode) {
+	if len(placementNode.children) == 0 {
+		meta.RemoveStatusCondition(&installProgression.Conditions, InstallProgressionConditionAddonHealth)
+		return
+	}
+
+	states := map[string]int{}
+	var unhealthy []string
+	clusterStates := map[string]string{}
+	for clusterName, addon := range placementNode.children {
+		state := metrics.AddonState(addon.mca)
+		states[state]++
+		if state != metrics.AddonStateAvailable {
+			unhealthy = append(unhealthy, clusterName)
+			clusterStates[clusterName] = state
+		}
+	}
+	sort.Slice(unhealthy, func(i, j int) bool {
+		si, sj := addonStateSeverity[clusterStates[unhealthy[i]]], addonStateSeverity[clusterStates[unhealthy[j]]]
+		if si != sj {
+			return si < sj
+		}
+		return unhealthy[i] < unhealthy[j]
+	})
+
+	message := fmt.Sprintf("%d available, %d progressing, %d degraded, %d unavailable, %d unknown of %d clusters.",
+		states[metrics.AddonStateAvailable], states[metrics.AddonStateProgressing], states[metrics.AddonStateDegraded],
+		states[metrics.AddonStateUnavailable], states[metrics.AddonStateUnknown], len(placementNode.children))
+	if len(unhealthy) == 0 {
+		meta.SetStatusCondition(&installProgression.Conditions, metav1.Condition{
+			Type:    InstallProgressionConditionAddonHealth,
+			Status:  metav1.ConditionTrue,
+			Reason:  AddonHealthReasonAllAvailable,
+			Message: message,
+		})
+		return
+	}
+
+	var offenders []string
+	for _, clusterName := range unhealthy {
+		if len(offenders) == maxUnhealthyAddonsReported {
+			break
+		}
+		offenders = append(offenders, fmt.Sprintf("%s (%s)", clusterName, clusterStates[clusterName]))
+	}
+	if len(unhealthy) > maxUnhealthyAddonsReported {
+		offenders = append(offenders, fmt.Sprintf("and %d more", len(unhealthy)-maxUnhealthyAddonsReported))
+	}
+	meta.SetStatusCondition(&installProgression.Conditions, metav1.Condition{
+		Type:    InstallProgressionConditionAddonHealth,
+		Status:  metav1.ConditionFalse,
+		Reason:  AddonHealthReasonNotAllAvailable,
+		Message: fmt.Sprintf("%s Not available on %s.", message, strings.Join(offenders, ", ")),
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clienttesting "k8s.io/client-go/testing"
@@ -682,3 +683,126 @@ func TestMgmtAddonProgressingReconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestSetAddOnTemplateVersions(t *testing.T) {
+	templateGR := addonv1alpha1.ConfigGroupResource{Group: "addon.open-cluster-management.io", Resource: "addontemplates"}
+	newTemplateAddon := func(cluster, lastAppliedHash string) *addonv1alpha1.ManagedClusterAddOn {
+		config := addonv1alpha1.ConfigReference{
+			ConfigGroupResource: templateGR,
+			ConfigReferent:      addonv1alpha1.ConfigReferent{Name: "hello-template"},
+		}
+		if len(lastAppliedHash) > 0 {
+			config.LastAppliedConfig = &addonv1alpha1.ConfigSpecHash{
+				ConfigReferent: addonv1alpha1.ConfigReferent{Name: "hello-template"},
+				SpecHash:       lastAppliedHash,
+			}
+		}
+		return newManagedClusterAddon("test", cluster, nil, []addonv1alpha1.ConfigReference{config}, nil)
+	}
+
+	cases := []struct {
+		name            string
+		addons          []*addonv1alpha1.ManagedClusterAddOn
+		expectedMessage string
+	}{
+		{
+			name:   "no template",
+			addons: []*addonv1alpha1.ManagedClusterAddOn{addontesting.NewAddon("test", "cluster1")},
+		},
+		{
+			name: "multiple versions",
+			addons: []*addonv1alpha1.ManagedClusterAddOn{
+				newTemplateAddon("cluster1", "1111111111"),
+				newTemplateAddon("cluster2", "2222222222"),
+				newTemplateAddon("cluster3", "2222222222"),
+				newTemplateAddon("cluster4", ""),
+			},
+			expectedMessage: "Clusters per addon template version: 2 on hello-template (22222222), " +
+				"1 on hello-template (11111111), 1 pending",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			node := &installStrategyNode{children: map[string]*addonNode{}}
+			for _, addon := range c.addons {
+				node.children[addon.Namespace] = &addonNode{mca: addon}
+			}
+			installProgression := &addonv1alpha1.InstallProgression{}
+			setAddOnTemplateVersions(installProgression, node)
+
+			cond := meta.FindStatusCondition(installProgression.Conditions, InstallProgressionConditionTemplateVersions)
+			if len(c.expectedMessage) == 0 {
+				if cond != nil {
+					t.Errorf("expected no condition, but got %v", cond)
+				}
+				return
+			}
+			if cond == nil || cond.Message != c.expectedMessage {
+				t.Errorf("expected condition message %q, but got %v", c.expectedMessage, cond)
+			}
+		})
+	}
+}
+
+func TestSetAddOnHealth(t *testing.T) {
+	newAddon := func(cluster string, conditions ...metav1.Condition) *addonv1alpha1.ManagedClusterAddOn {
+		addon := addontesting.NewAddon("test", cluster)
+		addon.Status.Conditions = conditions
+		return addon
+	}
+	available := metav1.Condition{Type: addonv1alpha1.ManagedClusterAddOnConditionAvailable, Status: metav1.ConditionTrue}
+	unavailable := metav1.Condition{Type: addonv1alpha1.ManagedClusterAddOnConditionAvailable, Status: metav1.ConditionFalse}
+	degraded := metav1.Condition{Type: addonv1alpha1.ManagedClusterAddOnConditionDegraded, Status: metav1.ConditionTrue}
+
+	cases := []struct {
+		name            string
+		addons          []*addonv1alpha1.ManagedClusterAddOn
+		expectedStatus  metav1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name: "no addon",
+		},
+		{
+			name:            "all available",
+			addons:          []*addonv1alpha1.ManagedClusterAddOn{newAddon("cluster1", available), newAddon("cluster2", available)},
+			expectedStatus:  metav1.ConditionTrue,
+			expectedMessage: "2 available, 0 progressing, 0 degraded, 0 unavailable, 0 unknown of 2 clusters.",
+		},
+		{
+			name: "worst offenders",
+			addons: []*addonv1alpha1.ManagedClusterAddOn{
+				newAddon("cluster1", available),
+				newAddon("cluster2"),
+				newAddon("cluster3", unavailable),
+				newAddon("cluster4", available, degraded),
+			},
+			expectedStatus: metav1.ConditionFalse,
+			expectedMessage: "1 available, 0 progressing, 1 degraded, 1 unavailable, 1 unknown of 4 clusters. " +
+				"Not available on cluster4 (degraded), cluster3 (unavailable), cluster2 (unknown).",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			node := &installStrategyNode{children: map[string]*addonNode{}}
+			for _, addon := range c.addons {
+				node.children[addon.Namespace] = &addonNode{mca: addon}
+			}
+			installProgression := &addonv1alpha1.InstallProgression{}
+			setAddOnHealth(installProgression, node)
+
+			cond := meta.FindStatusCondition(installProgression.Conditions, InstallProgressionConditionAddonHealth)
+			if len(c.expectedMessage) == 0 {
+				if cond != nil {
+					t.Errorf("expected no condition, but got %v", cond)
+				}
+				return
+			}
+			if cond == nil || cond.Status != c.expectedStatus || cond.Message != c.expectedMessage {
+				t.Errorf("expected condition %s with message %q, but got %v", c.expectedStatus, c.expectedMessage, cond)
+			}
+		})
+	}
+}
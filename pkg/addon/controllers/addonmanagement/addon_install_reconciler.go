@@ -2,9 +2,12 @@ package addonmanagement
 
 import (
 	"context"
+	"strconv"
+	"time"
 
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -15,16 +18,22 @@ import (
 	"open-cluster-management.io/addon-framework/pkg/index"
 	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 	addonv1alpha1client "open-cluster-management.io/api/client/addon/clientset/versioned"
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
 	clusterlisterv1beta1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
 	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/common/patcher"
 )
 
 type managedClusterAddonInstallReconciler struct {
 	addonClient                addonv1alpha1client.Interface
 	managedClusterAddonIndexer cache.Indexer
+	clusterLister              clusterlisterv1.ManagedClusterLister
 	placementLister            clusterlisterv1beta1.PlacementLister
 	placementDecisionLister    clusterlisterv1beta1.PlacementDecisionLister
 	addonFilterFunc            factory.EventFilterFunc
+	patcher                    patcher.Patcher[
+		*addonv1alpha1.ClusterManagementAddOn, addonv1alpha1.ClusterManagementAddOnSpec, addonv1alpha1.ClusterManagementAddOnStatus]
 }
 
 func (d *managedClusterAddonInstallReconciler) reconcile(
@@ -46,20 +55,60 @@ func (d *managedClusterAddonInstallReconciler) reconcile(
 		return cma, reconcileContinue, err
 	}
 
-	existingDeployed := sets.Set[string]{}
+	existingDeployed := map[string]*addonv1alpha1.ManagedClusterAddOn{}
 	for _, addonObject := range addons {
 		addon := addonObject.(*addonv1alpha1.ManagedClusterAddOn)
-		existingDeployed.Insert(addon.Namespace)
+		existingDeployed[addon.Namespace] = addon
 	}
 
-	requiredDeployed, err := d.getAllDecisions(logger, cma.Name, cma.Spec.InstallStrategy.Placements)
+	placementDecisionGroups, err := d.getAllDecisions(logger, cma.Name, cma.Spec.InstallStrategy.Placements)
 	if err != nil {
 		return cma, reconcileContinue, err
 	}
 
+	// the clusters excluded by the install strategy are neither installed nor kept
+	exclusionSelectors, err := getInstallExclusionSelectors(cma)
+	if err != nil {
+		return cma, reconcileContinue, err
+	}
+	if err := excludeClusters(d.clusterLister, exclusionSelectors, placementDecisionGroups); err != nil {
+		return cma, reconcileContinue, err
+	}
+
+	requiredDeployed := sets.Set[string]{}
+	for _, groups := range placementDecisionGroups {
+		for _, group := range groups {
+			requiredDeployed.Insert(group.clusters...)
+		}
+	}
+
 	owner := metav1.NewControllerRef(cma, addonv1alpha1.GroupVersion.WithKind("ClusterManagementAddOn"))
-	toAdd := requiredDeployed.Difference(existingDeployed)
-	toRemove := existingDeployed.Difference(requiredDeployed)
+	toAdd := requiredDeployed.Difference(sets.KeySet(existingDeployed))
+	toRemove := sets.KeySet(existingDeployed).Difference(requiredDeployed)
+
+	// install the addon progressively per decision group if the rollout policy is set
+	rolloutConditions := map[addonv1alpha1.PlacementRef]metav1.Condition{}
+	var requeueAfter time.Duration
+	policy, policyErr := getInstallRolloutPolicy(cma)
+	if policy != nil || policyErr != nil {
+		toAdd = sets.Set[string]{}
+		for _, strategy := range cma.Spec.InstallStrategy.Placements {
+			groups, ok := placementDecisionGroups[strategy.PlacementRef]
+			if !ok {
+				continue
+			}
+			if policyErr != nil {
+				rolloutConditions[strategy.PlacementRef] = newInvalidInstallRolloutCondition(policyErr)
+				continue
+			}
+			result := rolloutInstall(policy, groups, existingDeployed, time.Now())
+			toAdd = toAdd.Union(result.toInstall.Difference(sets.KeySet(existingDeployed)))
+			rolloutConditions[strategy.PlacementRef] = result.condition
+			if result.requeueAfter > 0 && (requeueAfter == 0 || result.requeueAfter < requeueAfter) {
+				requeueAfter = result.requeueAfter
+			}
+		}
+	}
 
 	var errs []error
 	for cluster := range toAdd {
@@ -84,15 +133,33 @@ func (d *managedClusterAddonInstallReconciler) reconcile(
 		}
 	}
 
-	return cma, reconcileContinue, utilerrors.NewAggregate(errs)
+	// report the progress of the progressive install in the install progression of each placement
+	cmaCopy := cma.DeepCopy()
+	for i, installProgression := range cmaCopy.Status.InstallProgressions {
+		if condition, ok := rolloutConditions[installProgression.PlacementRef]; ok {
+			meta.SetStatusCondition(&cmaCopy.Status.InstallProgressions[i].Conditions, condition)
+		} else {
+			meta.RemoveStatusCondition(&cmaCopy.Status.InstallProgressions[i].Conditions, InstallProgressionConditionRollout)
+		}
+	}
+	if _, err := d.patcher.PatchStatus(ctx, cmaCopy, cmaCopy.Status, cma.Status); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 && requeueAfter > 0 {
+		return cmaCopy, reconcileContinue, newRequeueError("the addon is soaking on the decision group", requeueAfter)
+	}
+	return cmaCopy, reconcileContinue, utilerrors.NewAggregate(errs)
 }
 
+// getAllDecisions returns the decision groups of each placement in the install strategy, the placements not
+// found are ignored.
 func (d *managedClusterAddonInstallReconciler) getAllDecisions(
 	logger klog.Logger,
 	addonName string,
-	placements []addonv1alpha1.PlacementStrategy) (sets.Set[string], error) {
+	placements []addonv1alpha1.PlacementStrategy) (map[addonv1alpha1.PlacementRef][]decisionGroup, error) {
 	var errs []error
-	required := sets.Set[string]{}
+	required := map[addonv1alpha1.PlacementRef][]decisionGroup{}
 	for _, strategy := range placements {
 		_, err := d.placementLister.Placements(strategy.PlacementRef.Namespace).Get(strategy.PlacementRef.Name)
 		if errors.IsNotFound(err) {
@@ -114,11 +181,21 @@ func (d *managedClusterAddonInstallReconciler) getAllDecisions(
 			continue
 		}
 
+		// the decisions without a valid decision group index are regarded as in the first decision group
+		groups := map[int]sets.Set[string]{}
 		for _, d := range decisions {
+			groupIndex, err := strconv.Atoi(d.Labels[clusterv1beta1.DecisionGroupIndexLabel])
+			if err != nil {
+				groupIndex = 0
+			}
+			if _, ok := groups[groupIndex]; !ok {
+				groups[groupIndex] = sets.Set[string]{}
+			}
 			for _, sd := range d.Status.Decisions {
-				required.Insert(sd.ClusterName)
+				groups[groupIndex].Insert(sd.ClusterName)
 			}
 		}
+		required[strategy.PlacementRef] = sortDecisionGroups(groups)
 	}
 
 	return required, utilerrors.NewAggregate(errs)
@@ -2,10 +2,15 @@ package addonmanagement
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
-	"k8s.io/apimachinery/pkg/api/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
@@ -15,8 +20,11 @@ import (
 	addonv1alpha1client "open-cluster-management.io/api/client/addon/clientset/versioned"
 	addoninformerv1alpha1 "open-cluster-management.io/api/client/addon/informers/externalversions/addon/v1alpha1"
 	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
+	clusterinformersv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
 	clusterinformersv1beta1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1beta1"
 
+	commonmetrics "open-cluster-management.io/ocm/pkg/common/metrics"
+	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 )
 
@@ -40,10 +48,28 @@ const (
 	reconcileContinue
 )
 
+// requeueError is returned by a reconciler to sync the addon again after a while.
+type requeueError struct {
+	requeueAfter time.Duration
+	message      string
+}
+
+func (r *requeueError) Error() string {
+	return fmt.Sprintf("%s, requeue after %v", r.message, r.requeueAfter)
+}
+
+func newRequeueError(message string, requeueAfter time.Duration) *requeueError {
+	return &requeueError{
+		requeueAfter: requeueAfter,
+		message:      message,
+	}
+}
+
 func NewAddonManagementController(
 	addonClient addonv1alpha1client.Interface,
 	addonInformers addoninformerv1alpha1.ManagedClusterAddOnInformer,
 	clusterManagementAddonInformers addoninformerv1alpha1.ClusterManagementAddOnInformer,
+	clusterInformer clusterinformersv1.ManagedClusterInformer,
 	placementInformer clusterinformersv1beta1.PlacementInformer,
 	placementDecisionInformer clusterinformersv1beta1.PlacementDecisionInformer,
 	addonFilterFunc factory.EventFilterFunc,
@@ -57,10 +83,14 @@ func NewAddonManagementController(
 		reconcilers: []addonManagementReconcile{
 			&managedClusterAddonInstallReconciler{
 				addonClient:                addonClient,
+				clusterLister:              clusterInformer.Lister(),
 				placementDecisionLister:    placementDecisionInformer.Lister(),
 				placementLister:            placementInformer.Lister(),
 				managedClusterAddonIndexer: addonInformers.Informer().GetIndexer(),
 				addonFilterFunc:            addonFilterFunc,
+				patcher: patcher.NewPatcher[
+					*addonv1alpha1.ClusterManagementAddOn, addonv1alpha1.ClusterManagementAddOnSpec, addonv1alpha1.ClusterManagementAddOnStatus](
+					addonClient.AddonV1alpha1().ClusterManagementAddOns()),
 			},
 		},
 	}
@@ -76,7 +106,24 @@ func NewAddonManagementController(
 			index.ClusterManagementAddonByPlacementQueueKey(
 				clusterManagementAddonInformers),
 			placementInformer.Informer()).
-		WithSync(c.sync).ToController("addon-management-controller", recorder)
+		WithInformersQueueKeysFunc(c.clusterManagementAddonWithExclusionQueueKeys, clusterInformer.Informer()).
+		WithSync(commonmetrics.WithReconcileMetrics("addon-management-controller", c.sync)).ToController("addon-management-controller", recorder)
+}
+
+// clusterManagementAddonWithExclusionQueueKeys returns the keys of the ClusterManagementAddOns excluding clusters
+// from the install strategy, since the change of the labels or claims of a cluster may change the exclusion.
+func (c *addonManagementController) clusterManagementAddonWithExclusionQueueKeys(_ runtime.Object) []string {
+	cmas, err := c.clusterManagementAddonLister.List(labels.Everything())
+	if err != nil {
+		return []string{}
+	}
+	var keys []string
+	for _, cma := range cmas {
+		if _, ok := cma.GetAnnotations()[InstallExclusionSelectorsAnnotationKey]; ok {
+			keys = append(keys, cma.Name)
+		}
+	}
+	return keys
 }
 
 func (c *addonManagementController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
@@ -92,7 +139,7 @@ func (c *addonManagementController) sync(ctx context.Context, syncCtx factory.Sy
 
 	cma, err := c.clusterManagementAddonLister.Get(addonName)
 	switch {
-	case errors.IsNotFound(err):
+	case apierrors.IsNotFound(err):
 		return nil
 	case err != nil:
 		return err
@@ -103,8 +150,11 @@ func (c *addonManagementController) sync(ctx context.Context, syncCtx factory.Sy
 	var state reconcileState
 	var errs []error
 	for _, reconciler := range c.reconcilers {
+		var rqe *requeueError
 		cma, state, err = reconciler.reconcile(ctx, cma)
-		if err != nil {
+		if errors.As(err, &rqe) {
+			syncCtx.Queue().AddAfter(key, rqe.requeueAfter)
+		} else if err != nil {
 			errs = append(errs, err)
 		}
 		if state == reconcileStop {
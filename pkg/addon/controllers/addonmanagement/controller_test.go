@@ -2,9 +2,11 @@ package addonmanagement
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clienttesting "k8s.io/client-go/testing"
@@ -19,7 +21,10 @@ import (
 	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
 	fakecluster "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
 	clusterv1informers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/common/patcher"
 )
 
 func TestAddonInstallReconcile(t *testing.T) {
@@ -27,6 +32,7 @@ func TestAddonInstallReconcile(t *testing.T) {
 		name                   string
 		managedClusteraddon    []runtime.Object
 		clusterManagementAddon *addonv1alpha1.ClusterManagementAddOn
+		clusters               []runtime.Object
 		placements             []runtime.Object
 		placementDecisions     []runtime.Object
 		validateAddonActions   func(t *testing.T, actions []clienttesting.Action)
@@ -216,13 +222,198 @@ func TestAddonInstallReconcile(t *testing.T) {
 				addontesting.AssertActions(t, actions, "create", "create", "delete")
 			},
 		},
+		{
+			name: "install addon progressively",
+			managedClusteraddon: []runtime.Object{
+				addontesting.NewAddon("test", "cluster1"),
+			},
+			clusterManagementAddon: func() *addonv1alpha1.ClusterManagementAddOn {
+				addon := addontesting.NewClusterManagementAddon("test", "", "").Build()
+				addon.Annotations = map[string]string{InstallMaxConcurrencyAnnotationKey: "2"}
+				addon.Spec.InstallStrategy = addonv1alpha1.InstallStrategy{
+					Type: addonv1alpha1.AddonInstallStrategyPlacements,
+					Placements: []addonv1alpha1.PlacementStrategy{
+						{
+							PlacementRef: addonv1alpha1.PlacementRef{Name: "test-placement", Namespace: "default"},
+						},
+					},
+				}
+				addon.Status.InstallProgressions = []addonv1alpha1.InstallProgression{
+					{PlacementRef: addonv1alpha1.PlacementRef{Name: "test-placement", Namespace: "default"}},
+				}
+				return addon
+			}(),
+			placements: []runtime.Object{
+				&clusterv1beta1.Placement{ObjectMeta: metav1.ObjectMeta{Name: "test-placement", Namespace: "default"}},
+			},
+			placementDecisions: []runtime.Object{
+				&clusterv1beta1.PlacementDecision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-placement-decision-1",
+						Namespace: "default",
+						Labels: map[string]string{
+							clusterv1beta1.PlacementLabel:          "test-placement",
+							clusterv1beta1.DecisionGroupIndexLabel: "0",
+						},
+					},
+					Status: clusterv1beta1.PlacementDecisionStatus{
+						Decisions: []clusterv1beta1.ClusterDecision{
+							{ClusterName: "cluster1"}, {ClusterName: "cluster2"}, {ClusterName: "cluster3"}},
+					},
+				},
+				&clusterv1beta1.PlacementDecision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-placement-decision-2",
+						Namespace: "default",
+						Labels: map[string]string{
+							clusterv1beta1.PlacementLabel:          "test-placement",
+							clusterv1beta1.DecisionGroupIndexLabel: "1",
+						},
+					},
+					Status: clusterv1beta1.PlacementDecisionStatus{
+						Decisions: []clusterv1beta1.ClusterDecision{{ClusterName: "cluster4"}},
+					},
+				},
+			},
+			validateAddonActions: func(t *testing.T, actions []clienttesting.Action) {
+				addontesting.AssertActions(t, actions, "create", "patch")
+				if actions[0].GetNamespace() != "cluster2" {
+					t.Errorf("expected to install the addon on cluster2, but got %s", actions[0].GetNamespace())
+				}
+				patch := actions[1].(clienttesting.PatchActionImpl).Patch
+				cma := &addonv1alpha1.ClusterManagementAddOn{}
+				if err := json.Unmarshal(patch, cma); err != nil {
+					t.Fatal(err)
+				}
+				if !meta.IsStatusConditionTrue(cma.Status.InstallProgressions[0].Conditions, InstallProgressionConditionRollout) {
+					t.Errorf("expected the install rollout in progress, but got %v", cma.Status.InstallProgressions[0].Conditions)
+				}
+			},
+		},
+		{
+			name:                "invalid install rollout policy",
+			managedClusteraddon: []runtime.Object{},
+			clusterManagementAddon: func() *addonv1alpha1.ClusterManagementAddOn {
+				addon := addontesting.NewClusterManagementAddon("test", "", "").Build()
+				addon.Annotations = map[string]string{InstallMaxConcurrencyAnnotationKey: "0"}
+				addon.Spec.InstallStrategy = addonv1alpha1.InstallStrategy{
+					Type: addonv1alpha1.AddonInstallStrategyPlacements,
+					Placements: []addonv1alpha1.PlacementStrategy{
+						{
+							PlacementRef: addonv1alpha1.PlacementRef{Name: "test-placement", Namespace: "default"},
+						},
+					},
+				}
+				addon.Status.InstallProgressions = []addonv1alpha1.InstallProgression{
+					{PlacementRef: addonv1alpha1.PlacementRef{Name: "test-placement", Namespace: "default"}},
+				}
+				return addon
+			}(),
+			placements: []runtime.Object{
+				&clusterv1beta1.Placement{ObjectMeta: metav1.ObjectMeta{Name: "test-placement", Namespace: "default"}},
+			},
+			placementDecisions: []runtime.Object{
+				&clusterv1beta1.PlacementDecision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-placement",
+						Namespace: "default",
+						Labels:    map[string]string{clusterv1beta1.PlacementLabel: "test-placement"},
+					},
+					Status: clusterv1beta1.PlacementDecisionStatus{
+						Decisions: []clusterv1beta1.ClusterDecision{{ClusterName: "cluster1"}},
+					},
+				},
+			},
+			validateAddonActions: func(t *testing.T, actions []clienttesting.Action) {
+				addontesting.AssertActions(t, actions, "patch")
+			},
+		},
+		{
+			name: "exclude clusters",
+			managedClusteraddon: []runtime.Object{
+				addontesting.NewAddon("test", "cluster1"),
+			},
+			clusterManagementAddon: func() *addonv1alpha1.ClusterManagementAddOn {
+				addon := addontesting.NewClusterManagementAddon("test", "", "").Build()
+				addon.Annotations = map[string]string{InstallExclusionSelectorsAnnotationKey: `[
+					{"labelSelector": {"matchLabels": {"local-cluster": "true"}}},
+					{"claimSelector": {"matchExpressions": [{"key": "platform", "operator": "In", "values": ["Kind"]}]}}]`}
+				addon.Spec.InstallStrategy = addonv1alpha1.InstallStrategy{
+					Type: addonv1alpha1.AddonInstallStrategyPlacements,
+					Placements: []addonv1alpha1.PlacementStrategy{
+						{
+							PlacementRef: addonv1alpha1.PlacementRef{Name: "test-placement", Namespace: "default"},
+						},
+					},
+				}
+				return addon
+			}(),
+			clusters: []runtime.Object{
+				&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster1", Labels: map[string]string{"local-cluster": "true"}}},
+				&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster2"}},
+				&clusterv1.ManagedCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "cluster3"},
+					Status: clusterv1.ManagedClusterStatus{
+						ClusterClaims: []clusterv1.ManagedClusterClaim{{Name: "platform", Value: "Kind"}},
+					},
+				},
+			},
+			placements: []runtime.Object{
+				&clusterv1beta1.Placement{ObjectMeta: metav1.ObjectMeta{Name: "test-placement", Namespace: "default"}},
+			},
+			placementDecisions: []runtime.Object{
+				&clusterv1beta1.PlacementDecision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-placement",
+						Namespace: "default",
+						Labels:    map[string]string{clusterv1beta1.PlacementLabel: "test-placement"},
+					},
+					Status: clusterv1beta1.PlacementDecisionStatus{
+						Decisions: []clusterv1beta1.ClusterDecision{
+							{ClusterName: "cluster1"}, {ClusterName: "cluster2"}, {ClusterName: "cluster3"}},
+					},
+				},
+			},
+			validateAddonActions: func(t *testing.T, actions []clienttesting.Action) {
+				addontesting.AssertActions(t, actions, "create", "delete")
+				if actions[0].GetNamespace() != "cluster2" {
+					t.Errorf("expected to install the addon on cluster2, but got %s", actions[0].GetNamespace())
+				}
+				if actions[1].GetNamespace() != "cluster1" {
+					t.Errorf("expected to remove the addon from cluster1, but got %s", actions[1].GetNamespace())
+				}
+			},
+		},
+		{
+			name:                "invalid install exclusion selectors",
+			managedClusteraddon: []runtime.Object{},
+			clusterManagementAddon: func() *addonv1alpha1.ClusterManagementAddOn {
+				addon := addontesting.NewClusterManagementAddon("test", "", "").Build()
+				addon.Annotations = map[string]string{InstallExclusionSelectorsAnnotationKey: "local-cluster=true"}
+				addon.Spec.InstallStrategy = addonv1alpha1.InstallStrategy{
+					Type: addonv1alpha1.AddonInstallStrategyPlacements,
+					Placements: []addonv1alpha1.PlacementStrategy{
+						{
+							PlacementRef: addonv1alpha1.PlacementRef{Name: "test-placement", Namespace: "default"},
+						},
+					},
+				}
+				return addon
+			}(),
+			placements: []runtime.Object{
+				&clusterv1beta1.Placement{ObjectMeta: metav1.ObjectMeta{Name: "test-placement", Namespace: "default"}},
+			},
+			validateAddonActions: addontesting.AssertNoActions,
+			expectErr:            true,
+		},
 	}
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			clusterObj := append(c.placements, c.placementDecisions...)
+			clusterObj := append(append(c.placements, c.placementDecisions...), c.clusters...)
 			fakeClusterClient := fakecluster.NewSimpleClientset(clusterObj...)
-			fakeAddonClient := fakeaddon.NewSimpleClientset(c.managedClusteraddon...)
+			fakeAddonClient := fakeaddon.NewSimpleClientset(append(c.managedClusteraddon, c.clusterManagementAddon)...)
 
 			addonInformers := addoninformers.NewSharedInformerFactory(fakeAddonClient, 10*time.Minute)
 			clusterInformers := clusterv1informers.NewSharedInformerFactory(fakeClusterClient, 10*time.Minute)
@@ -241,6 +432,12 @@ func TestAddonInstallReconcile(t *testing.T) {
 				}
 			}
 
+			for _, obj := range c.clusters {
+				if err := clusterInformers.Cluster().V1().ManagedClusters().Informer().GetStore().Add(obj); err != nil {
+					t.Fatal(err)
+				}
+			}
+
 			for _, obj := range c.placementDecisions {
 				if err := clusterInformers.Cluster().V1beta1().PlacementDecisions().Informer().GetStore().Add(obj); err != nil {
 					t.Fatal(err)
@@ -255,10 +452,14 @@ func TestAddonInstallReconcile(t *testing.T) {
 
 			reconcile := &managedClusterAddonInstallReconciler{
 				addonClient:                fakeAddonClient,
+				clusterLister:              clusterInformers.Cluster().V1().ManagedClusters().Lister(),
 				placementLister:            clusterInformers.Cluster().V1beta1().Placements().Lister(),
 				placementDecisionLister:    clusterInformers.Cluster().V1beta1().PlacementDecisions().Lister(),
 				managedClusterAddonIndexer: addonInformers.Addon().V1alpha1().ManagedClusterAddOns().Informer().GetIndexer(),
 				addonFilterFunc:            utils.ManagedBySelf(map[string]agent.AgentAddon{"test": nil}),
+				patcher: patcher.NewPatcher[
+					*addonv1alpha1.ClusterManagementAddOn, addonv1alpha1.ClusterManagementAddOnSpec, addonv1alpha1.ClusterManagementAddOnStatus](
+					fakeAddonClient.AddonV1alpha1().ClusterManagementAddOns()),
 			}
 
 			_, _, err = reconcile.reconcile(context.TODO(), c.clusterManagementAddon)
@@ -0,0 +1,90 @@
+package addonmanagement
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	placementhelpers "open-cluster-management.io/ocm/pkg/placement/helpers"
+)
+
+// InstallExclusionSelectorsAnnotationKey is the annotation on the ClusterManagementAddOn to exclude clusters from
+// the install strategy. The value is a JSON list of cluster selectors, the same as the ones in the predicates of
+// placements, and the addon is not installed on the clusters matching any of them even if they are selected by the
+// placements. For example, to skip the hub cluster and the clusters with the claim "platform.open-cluster-management.io"
+// of "Kind":
+//
+//	[{"labelSelector": {"matchLabels": {"local-cluster": "true"}}},
+//	 {"claimSelector": {"matchExpressions": [{"key": "platform.open-cluster-management.io", "operator": "In",
+//	   "values": ["Kind"]}]}}]
+const InstallExclusionSelectorsAnnotationKey = "addon.open-cluster-management.io/install-exclusion-selectors"
+
+// getInstallExclusionSelectors returns the cluster selectors to exclude clusters from the install strategy of the
+// addon, it returns nil if no cluster is excluded.
+func getInstallExclusionSelectors(cma *addonv1alpha1.ClusterManagementAddOn) ([]*placementhelpers.ClusterSelector, error) {
+	value, ok := cma.GetAnnotations()[InstallExclusionSelectorsAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var clusterSelectors []clusterv1beta1.ClusterSelector
+	if err := json.Unmarshal([]byte(value), &clusterSelectors); err != nil {
+		return nil, fmt.Errorf("the annotation %s is not a list of cluster selectors: %v",
+			InstallExclusionSelectorsAnnotationKey, err)
+	}
+
+	var selectors []*placementhelpers.ClusterSelector
+	for _, clusterSelector := range clusterSelectors {
+		selector, err := placementhelpers.NewClusterSelector(clusterSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cluster selector in the annotation %s: %v",
+				InstallExclusionSelectorsAnnotationKey, err)
+		}
+		selectors = append(selectors, selector)
+	}
+	return selectors, nil
+}
+
+// excludeClusters removes the clusters matching any of the selectors from the decision groups. The clusters which
+// are not found are kept, so the addons are not removed from them when the cache of clusters is not synced.
+func excludeClusters(clusterLister clusterlisterv1.ManagedClusterLister,
+	selectors []*placementhelpers.ClusterSelector,
+	placementDecisionGroups map[addonv1alpha1.PlacementRef][]decisionGroup) error {
+	if len(selectors) == 0 {
+		return nil
+	}
+
+	for placementRef, groups := range placementDecisionGroups {
+		for i, group := range groups {
+			var clusters []string
+			for _, clusterName := range group.clusters {
+				cluster, err := clusterLister.Get(clusterName)
+				switch {
+				case errors.IsNotFound(err):
+					clusters = append(clusters, clusterName)
+					continue
+				case err != nil:
+					return err
+				}
+
+				excluded := false
+				for _, selector := range selectors {
+					if selector.Matches(cluster.Labels, placementhelpers.GetClusterClaims(cluster)) {
+						excluded = true
+						break
+					}
+				}
+				if !excluded {
+					clusters = append(clusters, clusterName)
+				}
+			}
+			placementDecisionGroups[placementRef][i].clusters = clusters
+		}
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package addonmanagement
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+)
+
+const (
+	// InstallMaxConcurrencyAnnotationKey is the annotation on the ClusterManagementAddOn to install the addon
+	// progressively on the clusters selected by the placements of the install strategy. The addon is installed
+	// on the decision groups of each placement one after another, and on at most this number of clusters of a
+	// decision group at the same time. The value is an integer or a percentage of the clusters of the decision
+	// group, like "10" or "25%".
+	InstallMaxConcurrencyAnnotationKey = "addon.open-cluster-management.io/install-max-concurrency"
+
+	// InstallSoakTimeAnnotationKey is the annotation on the ClusterManagementAddOn to set how long the addon
+	// should stay available on all the clusters of a decision group before it is installed on the next decision
+	// group, like "10m". It takes effect only when the InstallMaxConcurrencyAnnotationKey is set, and the
+	// default is 0.
+	InstallSoakTimeAnnotationKey = "addon.open-cluster-management.io/install-soak-time"
+
+	// InstallMaxFailuresAnnotationKey is the annotation on the ClusterManagementAddOn to set the max number of
+	// clusters of a placement on which the addon is not available. The install is halted once the failures
+	// exceed it. The value is an integer or a percentage of the clusters of the placement, and the default is 0.
+	// It takes effect only when the InstallMaxConcurrencyAnnotationKey is set.
+	InstallMaxFailuresAnnotationKey = "addon.open-cluster-management.io/install-max-failures"
+)
+
+const (
+	// InstallProgressionConditionRollout is the condition type in the install progression of a placement to
+	// report the progress of the progressive install.
+	InstallProgressionConditionRollout = "InstallRollout"
+
+	InstallRolloutReasonProgressing   = "Progressing"
+	InstallRolloutReasonSoaking       = "Soaking"
+	InstallRolloutReasonHalted        = "Halted"
+	InstallRolloutReasonCompleted     = "Completed"
+	InstallRolloutReasonInvalidPolicy = "InvalidPolicy"
+)
+
+// installRolloutPolicy is the policy to install the addon progressively.
+type installRolloutPolicy struct {
+	maxConcurrency intstr.IntOrString
+	soakTime       time.Duration
+	maxFailures    intstr.IntOrString
+}
+
+// decisionGroup is the clusters of a decision group of a placement.
+type decisionGroup struct {
+	index    int
+	clusters []string
+}
+
+// installRolloutResult is the clusters to install the addon on in the current step of the progressive install.
+type installRolloutResult struct {
+	toInstall    sets.Set[string]
+	condition    metav1.Condition
+	requeueAfter time.Duration
+}
+
+// getInstallRolloutPolicy returns the policy to install the addon progressively, it returns nil if the addon
+// is not installed progressively.
+func getInstallRolloutPolicy(cma *addonv1alpha1.ClusterManagementAddOn) (*installRolloutPolicy, error) {
+	annotations := cma.GetAnnotations()
+	value, ok := annotations[InstallMaxConcurrencyAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	policy := &installRolloutPolicy{}
+	maxConcurrency, err := parseIntOrPercent(value, 1)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q of annotation %s: %v", value, InstallMaxConcurrencyAnnotationKey, err)
+	}
+	policy.maxConcurrency = maxConcurrency
+
+	if value, ok := annotations[InstallSoakTimeAnnotationKey]; ok {
+		soakTime, err := time.ParseDuration(value)
+		if err != nil || soakTime < 0 {
+			return nil, fmt.Errorf("invalid value %q of annotation %s, it should be a non-negative duration",
+				value, InstallSoakTimeAnnotationKey)
+		}
+		policy.soakTime = soakTime
+	}
+
+	policy.maxFailures = intstr.FromInt(0)
+	if value, ok := annotations[InstallMaxFailuresAnnotationKey]; ok {
+		maxFailures, err := parseIntOrPercent(value, 0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q of annotation %s: %v", value, InstallMaxFailuresAnnotationKey, err)
+		}
+		policy.maxFailures = maxFailures
+	}
+
+	return policy, nil
+}
+
+// parseIntOrPercent parses an integer no less than min, or a percentage in the range [min%, 100%].
+func parseIntOrPercent(value string, min int) (intstr.IntOrString, error) {
+	value = strings.TrimSpace(value)
+	result := intstr.Parse(value)
+	if result.Type == intstr.String {
+		if !strings.HasSuffix(value, "%") {
+			return result, fmt.Errorf("it should be an integer or a percentage")
+		}
+		percent, err := intstr.GetScaledValueFromIntOrPercent(&result, 100, false)
+		if err != nil || percent < min || percent > 100 {
+			return result, fmt.Errorf("the percentage should be in the range [%d%%, 100%%]", min)
+		}
+		return result, nil
+	}
+	if result.IntValue() < min {
+		return result, fmt.Errorf("the integer should not be less than %d", min)
+	}
+	return result, nil
+}
+
+// newInvalidInstallRolloutCondition returns the condition reported when the install policy is invalid.
+func newInvalidInstallRolloutCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    InstallProgressionConditionRollout,
+		Status:  metav1.ConditionFalse,
+		Reason:  InstallRolloutReasonInvalidPolicy,
+		Message: err.Error(),
+	}
+}
+
+// rolloutInstall returns the clusters of a placement to install the addon on in the current step. The decision
+// groups are installed in the order of their indexes, and the install moves to the next decision group only after
+// the addon is available on all the clusters of the current decision group for the soak time. Nothing is installed
+// once the clusters on which the addon is not available exceed the max failures.
+func rolloutInstall(
+	policy *installRolloutPolicy,
+	groups []decisionGroup,
+	addons map[string]*addonv1alpha1.ManagedClusterAddOn,
+	now time.Time) installRolloutResult {
+	result := installRolloutResult{toInstall: sets.Set[string]{}}
+
+	total, failed := 0, 0
+	for _, group := range groups {
+		total += len(group.clusters)
+		for _, cluster := range group.clusters {
+			if addon, ok := addons[cluster]; ok &&
+				meta.IsStatusConditionFalse(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionAvailable) {
+				failed++
+			}
+		}
+	}
+
+	maxFailures, _ := intstr.GetScaledValueFromIntOrPercent(&policy.maxFailures, total, false)
+	if failed > maxFailures {
+		result.condition = metav1.Condition{
+			Type:   InstallProgressionConditionRollout,
+			Status: metav1.ConditionFalse,
+			Reason: InstallRolloutReasonHalted,
+			Message: fmt.Sprintf("The install is halted since the addon is not available on %d of %d clusters, "+
+				"which exceeds the max failures %d", failed, total, maxFailures),
+		}
+		return result
+	}
+
+	for i, group := range groups {
+		var pending []string
+		inFlight := 0
+		var lastAvailable time.Time
+		for _, cluster := range group.clusters {
+			addon, ok := addons[cluster]
+			if !ok {
+				pending = append(pending, cluster)
+				continue
+			}
+			available := meta.FindStatusCondition(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionAvailable)
+			if available == nil || available.Status != metav1.ConditionTrue {
+				inFlight++
+				continue
+			}
+			if available.LastTransitionTime.Time.After(lastAvailable) {
+				lastAvailable = available.LastTransitionTime.Time
+			}
+		}
+
+		if len(pending) > 0 || inFlight > 0 {
+			maxConcurrency, _ := intstr.GetScaledValueFromIntOrPercent(&policy.maxConcurrency, len(group.clusters), true)
+			if maxConcurrency < 1 {
+				maxConcurrency = 1
+			}
+			for _, cluster := range pending {
+				if inFlight+result.toInstall.Len() >= maxConcurrency {
+					break
+				}
+				result.toInstall.Insert(cluster)
+			}
+			result.condition = metav1.Condition{
+				Type:   InstallProgressionConditionRollout,
+				Status: metav1.ConditionTrue,
+				Reason: InstallRolloutReasonProgressing,
+				Message: fmt.Sprintf("Installing decision group %d (%d of %d): the addon is available on %d of %d clusters "+
+					"of the group, and being installed on %d clusters", group.index, i+1, len(groups),
+					len(group.clusters)-len(pending)-inFlight, len(group.clusters), inFlight+result.toInstall.Len()),
+			}
+			return result
+		}
+
+		if i == len(groups)-1 {
+			break
+		}
+		if soakEnd := lastAvailable.Add(policy.soakTime); now.Before(soakEnd) {
+			result.condition = metav1.Condition{
+				Type:   InstallProgressionConditionRollout,
+				Status: metav1.ConditionTrue,
+				Reason: InstallRolloutReasonSoaking,
+				Message: fmt.Sprintf("The addon is available on all clusters of decision group %d (%d of %d), "+
+					"the next decision group will be installed after %s", group.index, i+1, len(groups),
+					soakEnd.UTC().Format(time.RFC3339)),
+			}
+			result.requeueAfter = soakEnd.Sub(now)
+			return result
+		}
+	}
+
+	result.condition = metav1.Condition{
+		Type:    InstallProgressionConditionRollout,
+		Status:  metav1.ConditionFalse,
+		Reason:  InstallRolloutReasonCompleted,
+		Message: fmt.Sprintf("The addon is installed on all %d clusters", total),
+	}
+	return result
+}
+
+// sortDecisionGroups sorts the decision groups by index and the clusters in each decision group by name.
+func sortDecisionGroups(groups map[int]sets.Set[string]) []decisionGroup {
+	var sorted []decisionGroup
+	for index, clusters := range groups {
+		sorted = append(sorted, decisionGroup{index: index, clusters: sets.List(clusters)})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].index < sorted[j].index
+	})
+	return sorted
+}
@@ -0,0 +1,189 @@
+package addonmanagement
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"open-cluster-management.io/addon-framework/pkg/addonmanager/addontesting"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+)
+
+func TestGetInstallRolloutPolicy(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		expectNil   bool
+		expectErr   bool
+	}{
+		{
+			name:      "not progressive",
+			expectNil: true,
+		},
+		{
+			name: "progressive",
+			annotations: map[string]string{
+				InstallMaxConcurrencyAnnotationKey: "25%",
+				InstallSoakTimeAnnotationKey:       "10m",
+				InstallMaxFailuresAnnotationKey:    "1",
+			},
+		},
+		{
+			name:        "invalid max concurrency",
+			annotations: map[string]string{InstallMaxConcurrencyAnnotationKey: "0%"},
+			expectErr:   true,
+		},
+		{
+			name: "invalid soak time",
+			annotations: map[string]string{
+				InstallMaxConcurrencyAnnotationKey: "1",
+				InstallSoakTimeAnnotationKey:       "ten minutes",
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid max failures",
+			annotations: map[string]string{
+				InstallMaxConcurrencyAnnotationKey: "1",
+				InstallMaxFailuresAnnotationKey:    "101%",
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cma := addontesting.NewClusterManagementAddon("test", "", "").Build()
+			cma.Annotations = c.annotations
+			policy, err := getInstallRolloutPolicy(cma)
+			if (err != nil) != c.expectErr {
+				t.Fatalf("expected error %v, but got %v", c.expectErr, err)
+			}
+			if !c.expectErr && (policy == nil) != c.expectNil {
+				t.Errorf("expected nil policy %v, but got %v", c.expectNil, policy)
+			}
+		})
+	}
+}
+
+func TestRolloutInstall(t *testing.T) {
+	now := time.Now()
+	groups := []decisionGroup{
+		{index: 0, clusters: []string{"cluster1", "cluster2", "cluster3"}},
+		{index: 1, clusters: []string{"cluster4", "cluster5"}},
+	}
+
+	cases := []struct {
+		name                 string
+		policy               *installRolloutPolicy
+		addons               map[string]*addonv1alpha1.ManagedClusterAddOn
+		expectedToInstall    []string
+		expectedReason       string
+		expectedRequeueAfter time.Duration
+	}{
+		{
+			name:              "install the first decision group",
+			policy:            newInstallRolloutPolicy("2", 0, "0"),
+			expectedToInstall: []string{"cluster1", "cluster2"},
+			expectedReason:    InstallRolloutReasonProgressing,
+		},
+		{
+			name:   "wait for the clusters in progress",
+			policy: newInstallRolloutPolicy("2", 0, "0"),
+			addons: map[string]*addonv1alpha1.ManagedClusterAddOn{
+				"cluster1": newAddonWithAvailable("cluster1", metav1.ConditionTrue, now),
+				"cluster2": newAddonWithAvailable("cluster2", metav1.ConditionUnknown, now),
+			},
+			expectedToInstall: []string{"cluster3"},
+			expectedReason:    InstallRolloutReasonProgressing,
+		},
+		{
+			name:   "soak the first decision group",
+			policy: newInstallRolloutPolicy("100%", 10*time.Minute, "0"),
+			addons: map[string]*addonv1alpha1.ManagedClusterAddOn{
+				"cluster1": newAddonWithAvailable("cluster1", metav1.ConditionTrue, now.Add(-time.Hour)),
+				"cluster2": newAddonWithAvailable("cluster2", metav1.ConditionTrue, now.Add(-5*time.Minute)),
+				"cluster3": newAddonWithAvailable("cluster3", metav1.ConditionTrue, now.Add(-time.Hour)),
+			},
+			expectedReason:       InstallRolloutReasonSoaking,
+			expectedRequeueAfter: 5 * time.Minute,
+		},
+		{
+			name:   "install the next decision group after soaking",
+			policy: newInstallRolloutPolicy("1", 10*time.Minute, "0"),
+			addons: map[string]*addonv1alpha1.ManagedClusterAddOn{
+				"cluster1": newAddonWithAvailable("cluster1", metav1.ConditionTrue, now.Add(-time.Hour)),
+				"cluster2": newAddonWithAvailable("cluster2", metav1.ConditionTrue, now.Add(-time.Hour)),
+				"cluster3": newAddonWithAvailable("cluster3", metav1.ConditionTrue, now.Add(-time.Hour)),
+			},
+			expectedToInstall: []string{"cluster4"},
+			expectedReason:    InstallRolloutReasonProgressing,
+		},
+		{
+			name:   "halt with too many failures",
+			policy: newInstallRolloutPolicy("2", 0, "20%"),
+			addons: map[string]*addonv1alpha1.ManagedClusterAddOn{
+				"cluster1": newAddonWithAvailable("cluster1", metav1.ConditionFalse, now),
+				"cluster2": newAddonWithAvailable("cluster2", metav1.ConditionFalse, now),
+			},
+			expectedReason: InstallRolloutReasonHalted,
+		},
+		{
+			name:   "tolerate failures",
+			policy: newInstallRolloutPolicy("3", 0, "1"),
+			addons: map[string]*addonv1alpha1.ManagedClusterAddOn{
+				"cluster1": newAddonWithAvailable("cluster1", metav1.ConditionFalse, now),
+			},
+			expectedToInstall: []string{"cluster2", "cluster3"},
+			expectedReason:    InstallRolloutReasonProgressing,
+		},
+		{
+			name:   "completed",
+			policy: newInstallRolloutPolicy("1", time.Hour, "0"),
+			addons: map[string]*addonv1alpha1.ManagedClusterAddOn{
+				"cluster1": newAddonWithAvailable("cluster1", metav1.ConditionTrue, now.Add(-2*time.Hour)),
+				"cluster2": newAddonWithAvailable("cluster2", metav1.ConditionTrue, now.Add(-2*time.Hour)),
+				"cluster3": newAddonWithAvailable("cluster3", metav1.ConditionTrue, now.Add(-2*time.Hour)),
+				"cluster4": newAddonWithAvailable("cluster4", metav1.ConditionTrue, now),
+				"cluster5": newAddonWithAvailable("cluster5", metav1.ConditionTrue, now),
+			},
+			expectedReason: InstallRolloutReasonCompleted,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result := rolloutInstall(c.policy, groups, c.addons, now)
+			if !result.toInstall.Equal(sets.New(c.expectedToInstall...)) {
+				t.Errorf("expected to install %v, but got %v", c.expectedToInstall, sets.List(result.toInstall))
+			}
+			if result.condition.Reason != c.expectedReason {
+				t.Errorf("expected reason %q, but got %q", c.expectedReason, result.condition.Reason)
+			}
+			if result.requeueAfter != c.expectedRequeueAfter {
+				t.Errorf("expected requeue after %v, but got %v", c.expectedRequeueAfter, result.requeueAfter)
+			}
+		})
+	}
+}
+
+func newInstallRolloutPolicy(maxConcurrency string, soakTime time.Duration, maxFailures string) *installRolloutPolicy {
+	policy := &installRolloutPolicy{soakTime: soakTime}
+	policy.maxConcurrency, _ = parseIntOrPercent(maxConcurrency, 1)
+	policy.maxFailures, _ = parseIntOrPercent(maxFailures, 0)
+	return policy
+}
+
+func newAddonWithAvailable(cluster string, status metav1.ConditionStatus, lastTransitionTime time.Time) *addonv1alpha1.ManagedClusterAddOn {
+	addon := addontesting.NewAddon("test", cluster)
+	meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+		Type:               addonv1alpha1.ManagedClusterAddOnConditionAvailable,
+		Status:             status,
+		Reason:             "Test",
+		LastTransitionTime: metav1.NewTime(lastTransitionTime),
+	})
+	return addon
+}
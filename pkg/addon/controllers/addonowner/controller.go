@@ -16,6 +16,7 @@ import (
 	addoninformerv1alpha1 "open-cluster-management.io/api/client/addon/informers/externalversions/addon/v1alpha1"
 	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
 
+	commonmetrics "open-cluster-management.io/ocm/pkg/common/metrics"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 )
 
@@ -51,7 +52,7 @@ func NewAddonOwnerController(
 		WithInformersQueueKeysFunc(
 			queue.QueueKeyByMetaNamespaceName,
 			addonInformers.Informer()).
-		WithSync(c.sync).
+		WithSync(commonmetrics.WithReconcileMetrics("addon-owner-controller", c.sync)).
 		ToController("addon-owner-controller", recorder)
 }
 
@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -16,6 +18,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/client-go/tools/cache"
+	kevents "k8s.io/client-go/tools/events"
 	"k8s.io/klog/v2"
 
 	"open-cluster-management.io/addon-framework/pkg/addonmanager/constants"
@@ -28,6 +31,9 @@ import (
 	worklister "open-cluster-management.io/api/client/work/listers/work/v1"
 	workapiv1 "open-cluster-management.io/api/work/v1"
 
+	"open-cluster-management.io/ocm/pkg/addon/controllers/metrics"
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	commonmetrics "open-cluster-management.io/ocm/pkg/common/metrics"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 )
@@ -46,6 +52,8 @@ type addonProgressingController struct {
 	clusterManagementAddonLister addonlisterv1alpha1.ClusterManagementAddOnLister
 	workLister                   worklister.ManifestWorkLister
 	addonFilterFunc              factory.EventFilterFunc
+	// eventRecorder emits the events regarding the addons
+	eventRecorder kevents.EventRecorder
 }
 
 func NewAddonProgressingController(
@@ -55,6 +63,7 @@ func NewAddonProgressingController(
 	workInformers workinformers.ManifestWorkInformer,
 	addonFilterFunc factory.EventFilterFunc,
 	recorder events.Recorder,
+	krecorder kevents.EventRecorder,
 ) factory.Controller {
 	c := &addonProgressingController{
 		addonClient:                  addonClient,
@@ -62,6 +71,7 @@ func NewAddonProgressingController(
 		clusterManagementAddonLister: clusterManagementAddonInformers.Lister(),
 		workLister:                   workInformers.Lister(),
 		addonFilterFunc:              addonFilterFunc,
+		eventRecorder:                krecorder,
 	}
 
 	return factory.New().WithInformersQueueKeysFunc(
@@ -74,7 +84,7 @@ func NewAddonProgressingController(
 				return []string{fmt.Sprintf("%s/%s", accessor.GetNamespace(), accessor.GetLabels()[addonapiv1alpha1.AddonLabelKey])}
 			},
 			workInformers.Informer()).
-		WithSync(c.sync).ToController("addon-progressing-controller", recorder)
+		WithSync(commonmetrics.WithReconcileMetrics("addon-progressing-controller", c.sync)).ToController("addon-progressing-controller", recorder)
 }
 
 func (c *addonProgressingController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
@@ -188,7 +198,56 @@ func (c *addonProgressingController) updateAddonProgressingAndLastApplied(
 
 	// set lastAppliedConfig when all the work matches addon and are ready.
 	setAddOnProgressingAndLastApplied(isUpgrade, ProgressingSucceed, "", newaddon)
-	return patcher.PatchStatus(ctx, newaddon, newaddon.Status, oldaddon.Status)
+	updated, err := patcher.PatchStatus(ctx, newaddon, newaddon.Status, oldaddon.Status)
+	if err == nil && updated {
+		observeProgressingDuration(oldaddon, newaddon)
+		c.recordProgressingEvent(oldaddon, newaddon)
+	}
+	return updated, err
+}
+
+// recordProgressingEvent emits an event regarding the addon when its install or upgrade is completed.
+func (c *addonProgressingController) recordProgressingEvent(oldaddon, newaddon *addonapiv1alpha1.ManagedClusterAddOn) {
+	newCond := meta.FindStatusCondition(newaddon.Status.Conditions, addonapiv1alpha1.ManagedClusterAddOnConditionProgressing)
+	if newCond == nil {
+		return
+	}
+	oldCond := meta.FindStatusCondition(oldaddon.Status.Conditions, addonapiv1alpha1.ManagedClusterAddOnConditionProgressing)
+	if oldCond != nil && oldCond.Reason == newCond.Reason {
+		return
+	}
+
+	switch newCond.Reason {
+	case addonapiv1alpha1.ProgressingReasonInstallSucceed:
+		c.eventRecorder.Eventf(newaddon, nil, corev1.EventTypeNormal,
+			commonhelpers.EventReasonAddOnInstalled, commonhelpers.EventActionInstall,
+			"Addon %s is installed on cluster %s", newaddon.Name, newaddon.Namespace)
+	case addonapiv1alpha1.ProgressingReasonUpgradeSucceed:
+		c.eventRecorder.Eventf(newaddon, nil, corev1.EventTypeNormal,
+			commonhelpers.EventReasonAddOnUpgraded, commonhelpers.EventActionUpgrade,
+			"Addon %s is upgraded on cluster %s", newaddon.Name, newaddon.Namespace)
+	}
+}
+
+// observeProgressingDuration records the duration of the install or upgrade of the addon when it is completed, the
+// duration starts from the time the Progressing condition turned to true.
+func observeProgressingDuration(oldaddon, newaddon *addonapiv1alpha1.ManagedClusterAddOn) {
+	oldCond := meta.FindStatusCondition(oldaddon.Status.Conditions, addonapiv1alpha1.ManagedClusterAddOnConditionProgressing)
+	if oldCond == nil || oldCond.Status != metav1.ConditionTrue {
+		return
+	}
+	newCond := meta.FindStatusCondition(newaddon.Status.Conditions, addonapiv1alpha1.ManagedClusterAddOnConditionProgressing)
+	if newCond == nil {
+		return
+	}
+
+	duration := time.Since(oldCond.LastTransitionTime.Time).Seconds()
+	switch newCond.Reason {
+	case addonapiv1alpha1.ProgressingReasonInstallSucceed:
+		metrics.InstallDuration.WithLabelValues(newaddon.Name).Observe(duration)
+	case addonapiv1alpha1.ProgressingReasonUpgradeSucceed:
+		metrics.UpgradeDuration.WithLabelValues(newaddon.Name).Observe(duration)
+	}
 }
 
 func isConfigurationSupported(addon *addonapiv1alpha1.ManagedClusterAddOn) (bool, addonapiv1alpha1.ConfigGroupResource) {
@@ -10,6 +10,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clienttesting "k8s.io/client-go/testing"
+	kevents "k8s.io/client-go/tools/events"
 
 	"open-cluster-management.io/addon-framework/pkg/addonmanager/addontesting"
 	"open-cluster-management.io/addon-framework/pkg/agent"
@@ -22,6 +23,7 @@ import (
 	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
 	workapiv1 "open-cluster-management.io/api/work/v1"
 
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
 )
 
@@ -33,6 +35,7 @@ func TestReconcile(t *testing.T) {
 		clusterManagementAddon []runtime.Object
 		work                   []runtime.Object
 		validateAddonActions   func(t *testing.T, actions []clienttesting.Action)
+		expectedEvents         []string
 	}{
 		{
 			name:                   "no clustermanagementaddon",
@@ -442,6 +445,7 @@ func TestReconcile(t *testing.T) {
 					t.Errorf("LastAppliedConfig object is not correct: %v", addOn.Status.ConfigReferences[0].LastAppliedConfig.SpecHash)
 				}
 			},
+			expectedEvents: []string{commonhelpers.EventReasonAddOnInstalled},
 		},
 		{
 			name:    "update managedclusteraddon to upgrade succeed",
@@ -515,6 +519,7 @@ func TestReconcile(t *testing.T) {
 					t.Errorf("LastAppliedConfig object is not correct: %v", addOn.Status.ConfigReferences[0].LastAppliedConfig.SpecHash)
 				}
 			},
+			expectedEvents: []string{commonhelpers.EventReasonAddOnUpgraded},
 		},
 		{
 			name:    "update managedclusteraddon to configuration unsupported...",
@@ -594,6 +599,7 @@ func TestReconcile(t *testing.T) {
 
 			syncContext := testingcommon.NewFakeSyncContext(t, c.syncKey)
 			recorder := syncContext.Recorder()
+			krecorder := kevents.NewFakeRecorder(10)
 
 			controller := NewAddonProgressingController(
 				fakeAddonClient,
@@ -602,6 +608,7 @@ func TestReconcile(t *testing.T) {
 				workInformers.Work().V1().ManifestWorks(),
 				utils.ManagedBySelf(map[string]agent.AgentAddon{"test": nil}),
 				recorder,
+				krecorder,
 			)
 
 			err := controller.Sync(context.TODO(), syncContext)
@@ -609,6 +616,7 @@ func TestReconcile(t *testing.T) {
 				t.Errorf("expected no error when sync: %v", err)
 			}
 			c.validateAddonActions(t, fakeAddonClient.Actions())
+			testingcommon.AssertEventReasons(t, krecorder, c.expectedEvents...)
 		})
 	}
 }
@@ -29,6 +29,7 @@ import (
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 
 	"open-cluster-management.io/ocm/pkg/addon/templateagent"
+	commonmetrics "open-cluster-management.io/ocm/pkg/common/metrics"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 )
 
@@ -48,6 +49,12 @@ type addonTemplateController struct {
 	dynamicInformers  dynamicinformer.DynamicSharedInformerFactory
 	workInformers     workv1informers.SharedInformerFactory
 	runControllerFunc runController
+	// templateRevisionNamespace is the namespace of the ConfigMaps keeping the revisions of the addon templates
+	templateRevisionNamespace string
+	// helmChartOptions configures where the helm charts of the addon templates could be downloaded from
+	helmChartOptions templateagent.HelmChartOptions
+	// addonFilterFunc filters the template type addons managed by the controller
+	addonFilterFunc factory.EventFilterFunc
 }
 
 type runController func(ctx context.Context, addonName string) error
@@ -61,19 +68,25 @@ func NewAddonTemplateController(
 	clusterInformers clusterv1informers.SharedInformerFactory,
 	dynamicInformers dynamicinformer.DynamicSharedInformerFactory,
 	workInformers workv1informers.SharedInformerFactory,
+	templateRevisionNamespace string,
+	helmChartOptions templateagent.HelmChartOptions,
+	addonFilterFunc factory.EventFilterFunc,
 	recorder events.Recorder,
 	runController ...runController,
 ) factory.Controller {
 	c := &addonTemplateController{
-		kubeConfig:       hubKubeconfig,
-		kubeClient:       hubKubeClient,
-		addonClient:      addonClient,
-		cmaLister:        addonInformers.Addon().V1alpha1().ClusterManagementAddOns().Lister(),
-		addonManagers:    make(map[string]context.CancelFunc),
-		addonInformers:   addonInformers,
-		clusterInformers: clusterInformers,
-		dynamicInformers: dynamicInformers,
-		workInformers:    workInformers,
+		kubeConfig:                hubKubeconfig,
+		kubeClient:                hubKubeClient,
+		addonClient:               addonClient,
+		cmaLister:                 addonInformers.Addon().V1alpha1().ClusterManagementAddOns().Lister(),
+		addonManagers:             make(map[string]context.CancelFunc),
+		addonInformers:            addonInformers,
+		clusterInformers:          clusterInformers,
+		dynamicInformers:          dynamicInformers,
+		workInformers:             workInformers,
+		templateRevisionNamespace: templateRevisionNamespace,
+		helmChartOptions:          helmChartOptions,
+		addonFilterFunc:           addonFilterFunc,
 	}
 
 	if len(runController) > 0 {
@@ -85,7 +98,7 @@ func NewAddonTemplateController(
 	return factory.New().WithInformersQueueKeysFunc(
 		queue.QueueKeyByMetaNamespaceName,
 		addonInformers.Addon().V1alpha1().ClusterManagementAddOns().Informer()).
-		WithSync(c.sync).
+		WithSync(commonmetrics.WithReconcileMetrics("addon-template-controller", c.sync)).
 		ToController("addon-template-controller", recorder)
 }
 
@@ -118,7 +131,7 @@ func (c *addonTemplateController) sync(ctx context.Context, syncCtx factory.Sync
 		return err
 	}
 
-	if !templateagent.SupportAddOnTemplate(cma) {
+	if !templateagent.SupportAddOnTemplate(cma) || !c.addonFilterFunc(cma) {
 		c.stopUnusedManagers(ctx, syncCtx, cma.Name)
 		return nil
 	}
@@ -196,13 +209,18 @@ func (c *addonTemplateController) runController(
 		kubeInformers.Rbac().V1().RoleBindings().Lister(),
 		// image overrides from cluster annotation has lower priority than from the addonDeploymentConfig
 		getValuesClosure,
-		addonfactory.GetAddOnDeploymentConfigValues(
+		templateagent.GetMergedAddOnDeploymentConfigValues(
 			addonfactory.NewAddOnDeploymentConfigGetter(c.addonClient),
 			addonfactory.ToAddOnCustomizedVariableValues,
 			templateagent.ToAddOnNodePlacementPrivateValues,
 			templateagent.ToAddOnRegistriesPrivateValues,
 		),
-	)
+		templateagent.GetAgentWorkloadOverridesPrivateValues(
+			addonfactory.NewAddOnDeploymentConfigGetter(c.addonClient),
+		),
+	).WithTemplateRevisionNamespace(c.templateRevisionNamespace).
+		WithHelmChartOptions(c.helmChartOptions).
+		WithWorkLister(c.workInformers.Work().V1().ManifestWorks().Lister())
 	err = mgr.AddAgent(agentAddon)
 	if err != nil {
 		return err
@@ -24,6 +24,7 @@ import (
 	fakework "open-cluster-management.io/api/client/work/clientset/versioned/fake"
 	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
 
+	"open-cluster-management.io/ocm/pkg/addon/templateagent"
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
 )
 
@@ -180,6 +181,9 @@ func TestReconcile(t *testing.T) {
 			clusterInformers,
 			dynamicInformerFactory,
 			workInformers,
+			"open-cluster-management-hub",
+			templateagent.HelmChartOptions{},
+			func(obj interface{}) bool { return true },
 			eventstesting.NewTestingEventRecorder(t),
 			runController,
 		)
@@ -0,0 +1,229 @@
+package addontemplaterevision
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/addon-framework/pkg/index"
+	"open-cluster-management.io/addon-framework/pkg/utils"
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addoninformerv1alpha1 "open-cluster-management.io/api/client/addon/informers/externalversions/addon/v1alpha1"
+	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
+
+	"open-cluster-management.io/ocm/pkg/addon/templateagent"
+	commonmetrics "open-cluster-management.io/ocm/pkg/common/metrics"
+	"open-cluster-management.io/ocm/pkg/common/queue"
+)
+
+// templateRevisionHistoryLimit is the max number of revisions kept for an AddOnTemplate besides the current one and
+// the ones still used by the addons.
+const templateRevisionHistoryLimit = 5
+
+// addonTemplateRevisionController keeps the revisions of the AddOnTemplates in ConfigMaps. When an AddOnTemplate is
+// changed, the addons which are not in the rollout of the change yet keep using the previous revision, and the
+// AddOnTemplate could be rolled back to a previous revision by the rollout strategy as well.
+type addonTemplateRevisionController struct {
+	kubeClient                    kubernetes.Interface
+	namespace                     string
+	addonTemplateLister           addonlisterv1alpha1.AddOnTemplateLister
+	configMapLister               corev1lister.ConfigMapLister
+	managedClusterAddonIndexer    cache.Indexer
+	clusterManagementAddonIndexer cache.Indexer
+}
+
+func NewAddonTemplateRevisionController(
+	kubeClient kubernetes.Interface,
+	namespace string,
+	configMapInformer corev1informers.ConfigMapInformer,
+	addonTemplateInformer addoninformerv1alpha1.AddOnTemplateInformer,
+	addonInformers addoninformerv1alpha1.ManagedClusterAddOnInformer,
+	clusterManagementAddonInformers addoninformerv1alpha1.ClusterManagementAddOnInformer,
+	recorder events.Recorder,
+) factory.Controller {
+	c := &addonTemplateRevisionController{
+		kubeClient:                    kubeClient,
+		namespace:                     namespace,
+		addonTemplateLister:           addonTemplateInformer.Lister(),
+		configMapLister:               configMapInformer.Lister(),
+		managedClusterAddonIndexer:    addonInformers.Informer().GetIndexer(),
+		clusterManagementAddonIndexer: clusterManagementAddonInformers.Informer().GetIndexer(),
+	}
+
+	return factory.New().
+		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, addonTemplateInformer.Informer()).
+		WithInformersQueueKeysFunc(addonTemplateQueueKeys, addonInformers.Informer()).
+		WithSync(commonmetrics.WithReconcileMetrics("addon-template-revision-controller", c.sync)).
+		ToController("addon-template-revision-controller", recorder)
+}
+
+// addonTemplateQueueKeys returns the names of the addon templates referenced by the addon.
+func addonTemplateQueueKeys(obj runtime.Object) []string {
+	addon, ok := obj.(*addonapiv1alpha1.ManagedClusterAddOn)
+	if !ok {
+		return []string{}
+	}
+	var keys []string
+	for _, config := range addon.Status.ConfigReferences {
+		if config.Group == utils.AddOnTemplateGVR.Group && config.Resource == utils.AddOnTemplateGVR.Resource &&
+			len(config.Name) > 0 {
+			keys = append(keys, config.Name)
+		}
+	}
+	return keys
+}
+
+func (c *addonTemplateRevisionController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	logger := klog.FromContext(ctx)
+	templateName := syncCtx.QueueKey()
+	logger.V(4).Info("Reconciling addon template revisions", "addonTemplate", templateName)
+
+	historyLimit := templateRevisionHistoryLimit
+	currentSpecHash := ""
+	template, err := c.addonTemplateLister.Get(templateName)
+	switch {
+	case errors.IsNotFound(err):
+		// the template is deleted, only keep the revisions still used by the addons
+		historyLimit = 0
+	case err != nil:
+		return err
+	default:
+		currentSpecHash, err = templateagent.GetTemplateSpecHash(template)
+		if err != nil {
+			return err
+		}
+		if err := c.ensureRevision(ctx, template, currentSpecHash); err != nil {
+			return err
+		}
+	}
+
+	return c.pruneRevisions(ctx, templateName, currentSpecHash, historyLimit)
+}
+
+func (c *addonTemplateRevisionController) ensureRevision(
+	ctx context.Context, template *addonapiv1alpha1.AddOnTemplate, specHash string) error {
+	revision, err := templateagent.NewTemplateRevision(template, c.namespace, specHash)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.configMapLister.ConfigMaps(c.namespace).Get(revision.Name)
+	switch {
+	case errors.IsNotFound(err):
+		_, err = c.kubeClient.CoreV1().ConfigMaps(c.namespace).Create(ctx, revision, metav1.CreateOptions{})
+		if errors.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	default:
+		return err
+	}
+}
+
+// pruneRevisions deletes the revisions of the template which are neither the current one nor used by any addon,
+// except the latest historyLimit ones.
+func (c *addonTemplateRevisionController) pruneRevisions(
+	ctx context.Context, templateName, currentSpecHash string, historyLimit int) error {
+	selector := labels.SelectorFromSet(labels.Set{templateagent.TemplateRevisionLabelKey: templateName})
+	revisions, err := c.configMapLister.ConfigMaps(c.namespace).List(selector)
+	if err != nil {
+		return err
+	}
+
+	used, err := c.usedSpecHashes(templateName)
+	if err != nil {
+		return err
+	}
+	used.Insert(currentSpecHash)
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[j].CreationTimestamp.Before(&revisions[i].CreationTimestamp)
+	})
+
+	var errs []error
+	kept := 0
+	for _, revision := range revisions {
+		if used.Has(revision.Annotations[templateagent.TemplateRevisionSpecHashAnnotationKey]) {
+			continue
+		}
+		if kept < historyLimit {
+			kept++
+			continue
+		}
+		err := c.kubeClient.CoreV1().ConfigMaps(c.namespace).Delete(ctx, revision.Name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// usedSpecHashes returns the spec hashes of the template which are desired or applied by the addons.
+func (c *addonTemplateRevisionController) usedSpecHashes(templateName string) (sets.Set[string], error) {
+	used := sets.New[string]()
+	insert := func(configSpecHashes ...*addonapiv1alpha1.ConfigSpecHash) {
+		for _, configSpecHash := range configSpecHashes {
+			if configSpecHash != nil && configSpecHash.Name == templateName && len(configSpecHash.SpecHash) > 0 {
+				used.Insert(configSpecHash.SpecHash)
+			}
+		}
+	}
+	isTemplate := func(gr addonapiv1alpha1.ConfigGroupResource) bool {
+		return gr.Group == utils.AddOnTemplateGVR.Group && gr.Resource == utils.AddOnTemplateGVR.Resource
+	}
+	indexKey := fmt.Sprintf("%s/%s/%s", utils.AddOnTemplateGVR.Group, utils.AddOnTemplateGVR.Resource, templateName)
+
+	addons, err := c.managedClusterAddonIndexer.ByIndex(index.AddonByConfig, indexKey)
+	if err != nil {
+		return used, err
+	}
+	for _, obj := range addons {
+		addon, ok := obj.(*addonapiv1alpha1.ManagedClusterAddOn)
+		if !ok {
+			continue
+		}
+		for _, config := range addon.Status.ConfigReferences {
+			if isTemplate(config.ConfigGroupResource) {
+				insert(config.DesiredConfig, config.LastAppliedConfig)
+			}
+		}
+	}
+
+	cmas, err := c.clusterManagementAddonIndexer.ByIndex(index.ClusterManagementAddonByConfig, indexKey)
+	if err != nil {
+		return used, err
+	}
+	for _, obj := range cmas {
+		cma, ok := obj.(*addonapiv1alpha1.ClusterManagementAddOn)
+		if !ok {
+			continue
+		}
+		for _, config := range cma.Status.DefaultConfigReferences {
+			if isTemplate(config.ConfigGroupResource) {
+				insert(config.DesiredConfig)
+			}
+		}
+		for _, installProgression := range cma.Status.InstallProgressions {
+			for _, config := range installProgression.ConfigReferences {
+				if isTemplate(config.ConfigGroupResource) {
+					insert(config.DesiredConfig, config.LastAppliedConfig, config.LastKnownGoodConfig)
+				}
+			}
+		}
+	}
+	return used, nil
+}
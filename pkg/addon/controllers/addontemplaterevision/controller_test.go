@@ -0,0 +1,182 @@
+package addontemplaterevision
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	"open-cluster-management.io/addon-framework/pkg/index"
+	"open-cluster-management.io/addon-framework/pkg/utils"
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	fakeaddon "open-cluster-management.io/api/client/addon/clientset/versioned/fake"
+	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
+
+	"open-cluster-management.io/ocm/pkg/addon/templateagent"
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+)
+
+const testNamespace = "open-cluster-management-hub"
+
+var templateGroupResource = addonapiv1alpha1.ConfigGroupResource{
+	Group:    utils.AddOnTemplateGVR.Group,
+	Resource: utils.AddOnTemplateGVR.Resource,
+}
+
+func newTemplate(addonName string) *addonapiv1alpha1.AddOnTemplate {
+	return &addonapiv1alpha1.AddOnTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-template"},
+		Spec:       addonapiv1alpha1.AddOnTemplateSpec{AddonName: addonName},
+	}
+}
+
+func newRevision(t *testing.T, addonName string, age time.Duration) (*corev1.ConfigMap, string) {
+	template := newTemplate(addonName)
+	specHash, err := templateagent.GetTemplateSpecHash(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revision, err := templateagent.NewTemplateRevision(template, testNamespace, specHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revision.CreationTimestamp = metav1.NewTime(time.Now().Add(-age))
+	return revision, specHash
+}
+
+func newAddon(cluster, specHash string) *addonapiv1alpha1.ManagedClusterAddOn {
+	addon := &addonapiv1alpha1.ManagedClusterAddOn{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello", Namespace: cluster},
+	}
+	addon.Status.ConfigReferences = []addonapiv1alpha1.ConfigReference{{
+		ConfigGroupResource: templateGroupResource,
+		ConfigReferent:      addonapiv1alpha1.ConfigReferent{Name: "hello-template"},
+		DesiredConfig: &addonapiv1alpha1.ConfigSpecHash{
+			ConfigReferent: addonapiv1alpha1.ConfigReferent{Name: "hello-template"},
+			SpecHash:       specHash,
+		},
+	}}
+	return addon
+}
+
+func TestSync(t *testing.T) {
+	current, _ := newRevision(t, "v0", 0)
+	var history []runtime.Object
+	var historyHashes []string
+	for i := 1; i <= templateRevisionHistoryLimit+2; i++ {
+		revision, specHash := newRevision(t, fmt.Sprintf("v%d", i), time.Duration(i)*time.Hour)
+		history = append(history, revision)
+		historyHashes = append(historyHashes, specHash)
+	}
+
+	cases := []struct {
+		name            string
+		template        *addonapiv1alpha1.AddOnTemplate
+		revisions       []runtime.Object
+		addons          []runtime.Object
+		validateActions func(t *testing.T, actions []clienttesting.Action)
+	}{
+		{
+			name:     "create revision",
+			template: newTemplate("v0"),
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "create")
+				revision := actions[0].(clienttesting.CreateActionImpl).Object.(*corev1.ConfigMap)
+				if revision.Name != current.Name || revision.Namespace != testNamespace {
+					t.Errorf("unexpected revision %s/%s", revision.Namespace, revision.Name)
+				}
+			},
+		},
+		{
+			name:            "revision exists",
+			template:        newTemplate("v0"),
+			revisions:       []runtime.Object{current},
+			validateActions: testingcommon.AssertNoActions,
+		},
+		{
+			name:      "prune revisions",
+			template:  newTemplate("v0"),
+			revisions: append([]runtime.Object{current}, history...),
+			// the oldest revision is used by an addon
+			addons: []runtime.Object{newAddon("cluster1", historyHashes[len(historyHashes)-1])},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "delete")
+				name := actions[0].(clienttesting.DeleteActionImpl).Name
+				expected := history[len(history)-2].(*corev1.ConfigMap).Name
+				if name != expected {
+					t.Errorf("expected revision %s to be deleted, but got %s", expected, name)
+				}
+			},
+		},
+		{
+			name:      "template deleted",
+			revisions: append([]runtime.Object{}, history[:2]...),
+			addons:    []runtime.Object{newAddon("cluster1", historyHashes[0])},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "delete")
+				name := actions[0].(clienttesting.DeleteActionImpl).Name
+				expected := history[1].(*corev1.ConfigMap).Name
+				if name != expected {
+					t.Errorf("expected revision %s to be deleted, but got %s", expected, name)
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kubeClient := fakekube.NewSimpleClientset(c.revisions...)
+			kubeInformers := kubeinformers.NewSharedInformerFactory(kubeClient, 10*time.Minute)
+			for _, obj := range c.revisions {
+				if err := kubeInformers.Core().V1().ConfigMaps().Informer().GetStore().Add(obj); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			addonClient := fakeaddon.NewSimpleClientset()
+			addonInformers := addoninformers.NewSharedInformerFactory(addonClient, 10*time.Minute)
+			if err := addonInformers.Addon().V1alpha1().ManagedClusterAddOns().Informer().AddIndexers(
+				cache.Indexers{index.AddonByConfig: index.IndexAddonByConfig}); err != nil {
+				t.Fatal(err)
+			}
+			if err := addonInformers.Addon().V1alpha1().ClusterManagementAddOns().Informer().AddIndexers(
+				cache.Indexers{index.ClusterManagementAddonByConfig: index.IndexClusterManagementAddonByConfig}); err != nil {
+				t.Fatal(err)
+			}
+			for _, obj := range c.addons {
+				if err := addonInformers.Addon().V1alpha1().ManagedClusterAddOns().Informer().GetStore().Add(obj); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if c.template != nil {
+				if err := addonInformers.Addon().V1alpha1().AddOnTemplates().Informer().GetStore().Add(c.template); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			syncContext := testingcommon.NewFakeSyncContext(t, "hello-template")
+			controller := NewAddonTemplateRevisionController(
+				kubeClient,
+				testNamespace,
+				kubeInformers.Core().V1().ConfigMaps(),
+				addonInformers.Addon().V1alpha1().AddOnTemplates(),
+				addonInformers.Addon().V1alpha1().ManagedClusterAddOns(),
+				addonInformers.Addon().V1alpha1().ClusterManagementAddOns(),
+				syncContext.Recorder(),
+			)
+
+			if err := controller.Sync(context.TODO(), syncContext); err != nil {
+				t.Errorf("expected no error when sync: %v", err)
+			}
+			c.validateActions(t, kubeClient.Actions())
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package addonversion
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/addon-framework/pkg/utils"
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonv1alpha1client "open-cluster-management.io/api/client/addon/clientset/versioned"
+	addoninformerv1alpha1 "open-cluster-management.io/api/client/addon/informers/externalversions/addon/v1alpha1"
+	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions/work/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/addon/controllers/addoncleanup"
+	commonmetrics "open-cluster-management.io/ocm/pkg/common/metrics"
+	"open-cluster-management.io/ocm/pkg/common/patcher"
+	"open-cluster-management.io/ocm/pkg/common/queue"
+)
+
+const (
+	// AgentVersionAnnotationKey is the annotation on the AddOnTemplate, or on the ClusterManagementAddOn of the
+	// addons not deployed by templates, to set the desired version of the addon agent, e.g. "v1.2.0".
+	AgentVersionAnnotationKey = "addon.open-cluster-management.io/agent-version"
+
+	// MaxVersionSkewAnnotationKey is the annotation on the same object as the agent version to set the max number of
+	// minor versions the running agent could be behind or ahead of the desired version, defaults to 2.
+	MaxVersionSkewAnnotationKey = "addon.open-cluster-management.io/max-version-skew"
+
+	// AgentVersionFeedbackName is the name of the status feedback value of the addon ManifestWorks which reports the
+	// running version of the agent, e.g. a json path of a health probe reading the version from the status of a
+	// resource maintained by the agent.
+	AgentVersionFeedbackName = "AgentVersion"
+
+	// ConditionUpgradeAvailable is true if the running agent is older than the desired version.
+	ConditionUpgradeAvailable = "UpgradeAvailable"
+	// ConditionVersionSkewTooLarge is true if the minor versions of the running agent and the desired version
+	// differ by more than the max version skew, or the major versions differ.
+	ConditionVersionSkewTooLarge = "VersionSkewTooLarge"
+
+	ReasonNewerVersionAvailable = "NewerVersionAvailable"
+	ReasonUpToDate              = "UpToDate"
+	ReasonVersionSkewTooLarge   = "VersionSkewTooLarge"
+	ReasonVersionSkewAllowed    = "VersionSkewAllowed"
+
+	defaultMaxVersionSkew = 2
+)
+
+// addonVersionController compares the running version of the addon agents reported by the status feedback of the
+// addon ManifestWorks with the desired version of the addon, and sets the UpgradeAvailable and VersionSkewTooLarge
+// conditions of the addons, so the fleet upgrade tooling could find the addons to upgrade.
+type addonVersionController struct {
+	addonClient                  addonv1alpha1client.Interface
+	managedClusterAddonLister    addonlisterv1alpha1.ManagedClusterAddOnLister
+	clusterManagementAddonLister addonlisterv1alpha1.ClusterManagementAddOnLister
+	addonTemplateLister          addonlisterv1alpha1.AddOnTemplateLister
+	workIndexer                  cache.Indexer
+}
+
+func NewAddonVersionController(
+	addonClient addonv1alpha1client.Interface,
+	addonInformers addoninformerv1alpha1.ManagedClusterAddOnInformer,
+	clusterManagementAddonInformers addoninformerv1alpha1.ClusterManagementAddOnInformer,
+	addonTemplateInformers addoninformerv1alpha1.AddOnTemplateInformer,
+	workInformers workinformers.ManifestWorkInformer,
+	recorder events.Recorder,
+) factory.Controller {
+	c := &addonVersionController{
+		addonClient:                  addonClient,
+		managedClusterAddonLister:    addonInformers.Lister(),
+		clusterManagementAddonLister: clusterManagementAddonInformers.Lister(),
+		addonTemplateLister:          addonTemplateInformers.Lister(),
+		workIndexer:                  workInformers.Informer().GetIndexer(),
+	}
+
+	return factory.New().
+		WithInformersQueueKeysFunc(queue.QueueKeyByMetaNamespaceName, addonInformers.Informer()).
+		WithInformersQueueKeysFunc(c.addonQueueKeys, clusterManagementAddonInformers.Informer(),
+			addonTemplateInformers.Informer()).
+		WithFilteredEventsInformersQueueKeysFunc(
+			addoncleanup.AddonResourceQueueKeys,
+			queue.FileterByLabel(addonapiv1alpha1.AddonLabelKey),
+			workInformers.Informer()).
+		WithSync(commonmetrics.WithReconcileMetrics("addon-version-controller", c.sync)).
+		ToController("addon-version-controller", recorder)
+}
+
+// addonQueueKeys returns the keys of the addons of the ClusterManagementAddOn or the AddOnTemplate.
+func (c *addonVersionController) addonQueueKeys(obj runtime.Object) []string {
+	var addonName string
+	switch o := obj.(type) {
+	case *addonapiv1alpha1.ClusterManagementAddOn:
+		addonName = o.Name
+	case *addonapiv1alpha1.AddOnTemplate:
+		addonName = o.Spec.AddonName
+	default:
+		return []string{}
+	}
+
+	addons, err := c.managedClusterAddonLister.List(labels.Everything())
+	if err != nil {
+		return []string{}
+	}
+	var keys []string
+	for _, addon := range addons {
+		if addon.Name == addonName {
+			keys = append(keys, fmt.Sprintf("%s/%s", addon.Namespace, addon.Name))
+		}
+	}
+	return keys
+}
+
+func (c *addonVersionController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	logger := klog.FromContext(ctx)
+	key := syncCtx.QueueKey()
+	logger.V(4).Info("Reconciling addon version", "addon", key)
+
+	namespace, addonName, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		// ignore addon whose key is invalid
+		return nil
+	}
+
+	addon, err := c.managedClusterAddonLister.ManagedClusterAddOns(namespace).Get(addonName)
+	switch {
+	case errors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	newAddon := addon.DeepCopy()
+	desired, maxSkew, err := c.desiredVersion(addon)
+	if err != nil {
+		logger.Info("Ignore the invalid desired agent version of addon", "addon", key, "error", err)
+	}
+	running, err := c.runningVersion(key)
+	if err != nil {
+		return err
+	}
+
+	if desired == nil || running == nil {
+		meta.RemoveStatusCondition(&newAddon.Status.Conditions, ConditionUpgradeAvailable)
+		meta.RemoveStatusCondition(&newAddon.Status.Conditions, ConditionVersionSkewTooLarge)
+	} else {
+		setVersionConditions(newAddon, running, desired, maxSkew)
+	}
+
+	addonPatcher := patcher.NewPatcher[
+		*addonapiv1alpha1.ManagedClusterAddOn, addonapiv1alpha1.ManagedClusterAddOnSpec, addonapiv1alpha1.ManagedClusterAddOnStatus](
+		c.addonClient.AddonV1alpha1().ManagedClusterAddOns(namespace))
+	_, err = addonPatcher.PatchStatus(ctx, newAddon, newAddon.Status, addon.Status)
+	return err
+}
+
+// desiredVersion returns the desired agent version and the max version skew set on the AddOnTemplate used by the
+// addon, or on the ClusterManagementAddOn. A nil version is returned if the desired version is not set.
+func (c *addonVersionController) desiredVersion(
+	addon *addonapiv1alpha1.ManagedClusterAddOn) (*version.Version, int, error) {
+	var annotations map[string]string
+	for _, config := range addon.Status.ConfigReferences {
+		if config.Group != utils.AddOnTemplateGVR.Group || config.Resource != utils.AddOnTemplateGVR.Resource ||
+			config.DesiredConfig == nil {
+			continue
+		}
+		template, err := c.addonTemplateLister.Get(config.DesiredConfig.Name)
+		switch {
+		case errors.IsNotFound(err):
+			continue
+		case err != nil:
+			return nil, 0, err
+		}
+		if _, ok := template.Annotations[AgentVersionAnnotationKey]; ok {
+			annotations = template.Annotations
+			break
+		}
+	}
+
+	if annotations == nil {
+		cma, err := c.clusterManagementAddonLister.Get(addon.Name)
+		switch {
+		case errors.IsNotFound(err):
+			return nil, 0, nil
+		case err != nil:
+			return nil, 0, err
+		}
+		annotations = cma.Annotations
+	}
+
+	value, ok := annotations[AgentVersionAnnotationKey]
+	if !ok {
+		return nil, 0, nil
+	}
+	desired, err := version.ParseGeneric(value)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	maxSkew := defaultMaxVersionSkew
+	if value, ok := annotations[MaxVersionSkewAnnotationKey]; ok {
+		if skew, err := strconv.Atoi(value); err == nil && skew >= 0 {
+			maxSkew = skew
+		}
+	}
+	return desired, maxSkew, nil
+}
+
+// runningVersion returns the oldest agent version reported by the status feedback of the ManifestWorks of the addon,
+// or nil if no version is reported.
+func (c *addonVersionController) runningVersion(addonKey string) (*version.Version, error) {
+	objs, err := c.workIndexer.ByIndex(addoncleanup.AddonResourceByAddon, addonKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var running *version.Version
+	for _, obj := range objs {
+		work, ok := obj.(*workapiv1.ManifestWork)
+		if !ok {
+			continue
+		}
+		for _, manifest := range work.Status.ResourceStatus.Manifests {
+			for _, value := range manifest.StatusFeedbacks.Values {
+				if value.Name != AgentVersionFeedbackName || value.Value.String == nil {
+					continue
+				}
+				v, err := version.ParseGeneric(*value.Value.String)
+				if err != nil {
+					continue
+				}
+				if running == nil || v.LessThan(running) {
+					running = v
+				}
+			}
+		}
+	}
+	return running, nil
+}
+
+func setVersionConditions(addon *addonapiv1alpha1.ManagedClusterAddOn, running, desired *version.Version, maxSkew int) {
+	message := fmt.Sprintf("The agent is running version %s, and the desired version is %s", running, desired)
+
+	if running.LessThan(desired) {
+		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+			Type:    ConditionUpgradeAvailable,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonNewerVersionAvailable,
+			Message: message,
+		})
+	} else {
+		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+			Type:    ConditionUpgradeAvailable,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonUpToDate,
+			Message: message,
+		})
+	}
+
+	minorSkew := int(running.Minor()) - int(desired.Minor())
+	if minorSkew < 0 {
+		minorSkew = -minorSkew
+	}
+	if running.Major() != desired.Major() || minorSkew > maxSkew {
+		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+			Type:   ConditionVersionSkewTooLarge,
+			Status: metav1.ConditionTrue,
+			Reason: ReasonVersionSkewTooLarge,
+			Message: fmt.Sprintf("The agent version %s is more than %d minor versions away from the desired version %s",
+				running, maxSkew, desired),
+		})
+	} else {
+		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+			Type:    ConditionVersionSkewTooLarge,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonVersionSkewAllowed,
+			Message: message,
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package addonversion
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	"open-cluster-management.io/addon-framework/pkg/utils"
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	fakeaddon "open-cluster-management.io/api/client/addon/clientset/versioned/fake"
+	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
+	fakework "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/addon/controllers/addoncleanup"
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+)
+
+func newAddon(conditions ...metav1.Condition) *addonapiv1alpha1.ManagedClusterAddOn {
+	addon := &addonapiv1alpha1.ManagedClusterAddOn{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "cluster1"},
+	}
+	addon.Status.ConfigReferences = []addonapiv1alpha1.ConfigReference{{
+		ConfigGroupResource: addonapiv1alpha1.ConfigGroupResource{
+			Group:    utils.AddOnTemplateGVR.Group,
+			Resource: utils.AddOnTemplateGVR.Resource,
+		},
+		ConfigReferent: addonapiv1alpha1.ConfigReferent{Name: "test-template"},
+		DesiredConfig: &addonapiv1alpha1.ConfigSpecHash{
+			ConfigReferent: addonapiv1alpha1.ConfigReferent{Name: "test-template"},
+		},
+	}}
+	addon.Status.Conditions = conditions
+	return addon
+}
+
+func newTemplate(annotations map[string]string) *addonapiv1alpha1.AddOnTemplate {
+	return &addonapiv1alpha1.AddOnTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-template", Annotations: annotations},
+		Spec:       addonapiv1alpha1.AddOnTemplateSpec{AddonName: "test"},
+	}
+}
+
+func newWork(name string, versions ...string) *workapiv1.ManifestWork {
+	work := &workapiv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "cluster1",
+			Labels:    map[string]string{addonapiv1alpha1.AddonLabelKey: "test"},
+		},
+	}
+	for i := range versions {
+		work.Status.ResourceStatus.Manifests = append(work.Status.ResourceStatus.Manifests, workapiv1.ManifestCondition{
+			StatusFeedbacks: workapiv1.StatusFeedbackResult{
+				Values: []workapiv1.FeedbackValue{{
+					Name:  AgentVersionFeedbackName,
+					Value: workapiv1.FieldValue{Type: workapiv1.String, String: &versions[i]},
+				}},
+			},
+		})
+	}
+	return work
+}
+
+func TestSync(t *testing.T) {
+	cases := []struct {
+		name                   string
+		addon                  *addonapiv1alpha1.ManagedClusterAddOn
+		template               *addonapiv1alpha1.AddOnTemplate
+		cma                    *addonapiv1alpha1.ClusterManagementAddOn
+		works                  []runtime.Object
+		expectedUpgrade        metav1.ConditionStatus
+		expectedSkewTooLarge   metav1.ConditionStatus
+		expectedNoConditions   bool
+		expectedNoPatchActions bool
+	}{
+		{
+			name:                   "no desired version",
+			addon:                  newAddon(),
+			template:               newTemplate(nil),
+			works:                  []runtime.Object{newWork("addon-test-deploy-0", "v1.0.0")},
+			expectedNoPatchActions: true,
+		},
+		{
+			name:                   "no running version",
+			addon:                  newAddon(),
+			template:               newTemplate(map[string]string{AgentVersionAnnotationKey: "v1.2.0"}),
+			works:                  []runtime.Object{newWork("addon-test-deploy-0")},
+			expectedNoPatchActions: true,
+		},
+		{
+			name:                 "up to date",
+			addon:                newAddon(),
+			template:             newTemplate(map[string]string{AgentVersionAnnotationKey: "v1.2.0"}),
+			works:                []runtime.Object{newWork("addon-test-deploy-0", "v1.2.0")},
+			expectedUpgrade:      metav1.ConditionFalse,
+			expectedSkewTooLarge: metav1.ConditionFalse,
+		},
+		{
+			name:                 "upgrade available",
+			addon:                newAddon(),
+			template:             newTemplate(map[string]string{AgentVersionAnnotationKey: "v1.2.0"}),
+			works:                []runtime.Object{newWork("addon-test-deploy-0", "v1.2.0", "v1.1.3")},
+			expectedUpgrade:      metav1.ConditionTrue,
+			expectedSkewTooLarge: metav1.ConditionFalse,
+		},
+		{
+			name:                 "version skew too large",
+			addon:                newAddon(),
+			template:             newTemplate(map[string]string{AgentVersionAnnotationKey: "v1.5.0"}),
+			works:                []runtime.Object{newWork("addon-test-deploy-0", "v1.2.0")},
+			expectedUpgrade:      metav1.ConditionTrue,
+			expectedSkewTooLarge: metav1.ConditionTrue,
+		},
+		{
+			name:  "max version skew and desired version on the cluster management addon",
+			addon: newAddon(),
+			cma: &addonapiv1alpha1.ClusterManagementAddOn{ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+				Annotations: map[string]string{
+					AgentVersionAnnotationKey:   "v1.2.0",
+					MaxVersionSkewAnnotationKey: "0",
+				},
+			}},
+			works:                []runtime.Object{newWork("addon-test-deploy-0", "v1.1.0")},
+			expectedUpgrade:      metav1.ConditionTrue,
+			expectedSkewTooLarge: metav1.ConditionTrue,
+		},
+		{
+			name: "conditions removed",
+			addon: newAddon(
+				metav1.Condition{Type: ConditionUpgradeAvailable, Status: metav1.ConditionTrue, Reason: ReasonNewerVersionAvailable},
+				metav1.Condition{Type: ConditionVersionSkewTooLarge, Status: metav1.ConditionFalse, Reason: ReasonVersionSkewAllowed},
+			),
+			template:             newTemplate(nil),
+			works:                []runtime.Object{newWork("addon-test-deploy-0", "v1.1.0")},
+			expectedNoConditions: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			addonClient := fakeaddon.NewSimpleClientset(c.addon)
+			addonInformers := addoninformers.NewSharedInformerFactory(addonClient, 10*time.Minute)
+			if err := addonInformers.Addon().V1alpha1().ManagedClusterAddOns().Informer().GetStore().Add(c.addon); err != nil {
+				t.Fatal(err)
+			}
+			if c.template != nil {
+				if err := addonInformers.Addon().V1alpha1().AddOnTemplates().Informer().GetStore().Add(c.template); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if c.cma != nil {
+				if err := addonInformers.Addon().V1alpha1().ClusterManagementAddOns().Informer().GetStore().Add(c.cma); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			workClient := fakework.NewSimpleClientset()
+			workInformers := workinformers.NewSharedInformerFactory(workClient, 10*time.Minute)
+			workInformer := workInformers.Work().V1().ManifestWorks().Informer()
+			if err := workInformer.AddIndexers(cache.Indexers{
+				addoncleanup.AddonResourceByAddon: addoncleanup.IndexAddonResourceByAddon}); err != nil {
+				t.Fatal(err)
+			}
+			for _, obj := range c.works {
+				if err := workInformer.GetStore().Add(obj); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			syncContext := testingcommon.NewFakeSyncContext(t, "cluster1/test")
+			controller := NewAddonVersionController(
+				addonClient,
+				addonInformers.Addon().V1alpha1().ManagedClusterAddOns(),
+				addonInformers.Addon().V1alpha1().ClusterManagementAddOns(),
+				addonInformers.Addon().V1alpha1().AddOnTemplates(),
+				workInformers.Work().V1().ManifestWorks(),
+				syncContext.Recorder(),
+			)
+
+			if err := controller.Sync(context.TODO(), syncContext); err != nil {
+				t.Fatalf("expected no error when sync: %v", err)
+			}
+
+			actions := addonClient.Actions()
+			if c.expectedNoPatchActions {
+				testingcommon.AssertNoActions(t, actions)
+				return
+			}
+			testingcommon.AssertActions(t, actions, "patch")
+			patch := actions[0].(clienttesting.PatchActionImpl).Patch
+			addon := &addonapiv1alpha1.ManagedClusterAddOn{}
+			if err := json.Unmarshal(patch, addon); err != nil {
+				t.Fatal(err)
+			}
+
+			if c.expectedNoConditions {
+				// the removed conditions are patched with a null list of conditions
+				if len(addon.Status.Conditions) != 0 {
+					t.Errorf("expected conditions removed, but got %v", addon.Status.Conditions)
+				}
+				return
+			}
+			if !meta.IsStatusConditionPresentAndEqual(addon.Status.Conditions, ConditionUpgradeAvailable, c.expectedUpgrade) {
+				t.Errorf("expected condition %s to be %s, but got %v",
+					ConditionUpgradeAvailable, c.expectedUpgrade, addon.Status.Conditions)
+			}
+			if !meta.IsStatusConditionPresentAndEqual(addon.Status.Conditions, ConditionVersionSkewTooLarge,
+				c.expectedSkewTooLarge) {
+				t.Errorf("expected condition %s to be %s, but got %v",
+					ConditionVersionSkewTooLarge, c.expectedSkewTooLarge, addon.Status.Conditions)
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	addoninformerv1alpha1 "open-cluster-management.io/api/client/addon/informers/externalversions/addon/v1alpha1"
 	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
 
+	commonmetrics "open-cluster-management.io/ocm/pkg/common/metrics"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 )
@@ -46,7 +47,7 @@ func NewManagementAddonInstallProgressionController(
 	return factory.New().WithInformersQueueKeysFunc(
 		queue.QueueKeyByMetaName,
 		addonInformers.Informer(), clusterManagementAddonInformers.Informer()).
-		WithSync(c.sync).ToController("management-addon-status-controller", recorder)
+		WithSync(commonmetrics.WithReconcileMetrics("management-addon-status-controller", c.sync)).ToController("management-addon-status-controller", recorder)
 
 }
 
@@ -0,0 +1,173 @@
+package metrics
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
+)
+
+const subsystem = "addon_manager"
+
+// The states of the ManagedClusterAddOns reported by the addons metric.
+const (
+	AddonStateProgressing = "progressing"
+	AddonStateDegraded    = "degraded"
+	AddonStateAvailable   = "available"
+	AddonStateUnavailable = "unavailable"
+	AddonStateUnknown     = "unknown"
+)
+
+var (
+	// InstallDuration is the duration from an addon starting to install on a cluster to the install succeeding.
+	InstallDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      subsystem,
+			Name:           "install_duration_seconds",
+			Help:           "Duration in seconds of installing an addon on a managed cluster.",
+			Buckets:        metrics.ExponentialBuckets(1, 2, 14),
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"addon_name"},
+	)
+
+	// UpgradeDuration is the duration from an addon starting to upgrade on a cluster to the upgrade succeeding.
+	UpgradeDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      subsystem,
+			Name:           "upgrade_duration_seconds",
+			Help:           "Duration in seconds of upgrading an addon on a managed cluster.",
+			Buckets:        metrics.ExponentialBuckets(1, 2, 14),
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"addon_name"},
+	)
+
+	// CSRSignLatency is the latency from a CSR of an addon agent being created to it being signed by the
+	// addon-manager.
+	CSRSignLatency = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      subsystem,
+			Name:           "csr_sign_latency_seconds",
+			Help:           "Latency in seconds from a CSR of an addon agent being created to it being signed.",
+			Buckets:        metrics.ExponentialBuckets(0.01, 2, 15),
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"addon_name"},
+	)
+
+	// TemplateRenderFailures is the number of failures to render the manifests of a template addon.
+	TemplateRenderFailures = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      subsystem,
+			Name:           "template_render_failures_total",
+			Help:           "Number of failures to render the manifests of a template addon for a managed cluster.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"addon_name"},
+	)
+
+	addonsDesc = metrics.NewDesc(
+		metrics.BuildFQName("", subsystem, "managed_cluster_addons"),
+		"Number of ManagedClusterAddOns of an addon in each state.",
+		[]string{"addon_name", "state"}, nil, metrics.ALPHA, "")
+
+	desyncedAddonsDesc = metrics.NewDesc(
+		metrics.BuildFQName("", subsystem, "desynced_managed_cluster_addons"),
+		"Number of ManagedClusterAddOns of an addon whose desired configs are not applied yet.",
+		[]string{"addon_name"}, nil, metrics.ALPHA, "")
+
+	registerOnce sync.Once
+)
+
+// Register registers the addon-manager metrics to the legacy registry, which is served by the controller
+// on the /metrics endpoint. The metrics of the ManagedClusterAddOns are collected from the lister when scraped.
+func Register(addonLister addonlisterv1alpha1.ManagedClusterAddOnLister) {
+	registerOnce.Do(func() {
+		legacyregistry.MustRegister(InstallDuration)
+		legacyregistry.MustRegister(UpgradeDuration)
+		legacyregistry.MustRegister(CSRSignLatency)
+		legacyregistry.MustRegister(TemplateRenderFailures)
+		legacyregistry.CustomMustRegister(NewAddonCollector(addonLister))
+	})
+}
+
+// addonCollector collects the number of ManagedClusterAddOns by addon and state.
+type addonCollector struct {
+	metrics.BaseStableCollector
+
+	addonLister addonlisterv1alpha1.ManagedClusterAddOnLister
+}
+
+// NewAddonCollector returns a collector of the ManagedClusterAddOns metrics.
+func NewAddonCollector(addonLister addonlisterv1alpha1.ManagedClusterAddOnLister) metrics.StableCollector {
+	return &addonCollector{addonLister: addonLister}
+}
+
+func (c *addonCollector) DescribeWithStability(ch chan<- *metrics.Desc) {
+	ch <- addonsDesc
+	ch <- desyncedAddonsDesc
+}
+
+func (c *addonCollector) CollectWithStability(ch chan<- metrics.Metric) {
+	addons, err := c.addonLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list ManagedClusterAddOns for metrics: %v", err)
+		return
+	}
+
+	states := map[string]map[string]int{}
+	desynced := map[string]int{}
+	for _, addon := range addons {
+		if _, ok := states[addon.Name]; !ok {
+			states[addon.Name] = map[string]int{}
+			desynced[addon.Name] = 0
+		}
+		states[addon.Name][AddonState(addon)]++
+		if IsDesynced(addon) {
+			desynced[addon.Name]++
+		}
+	}
+
+	for addonName, counts := range states {
+		for _, state := range []string{
+			AddonStateProgressing, AddonStateDegraded, AddonStateAvailable, AddonStateUnavailable, AddonStateUnknown} {
+			ch <- metrics.NewLazyConstMetric(addonsDesc, metrics.GaugeValue, float64(counts[state]), addonName, state)
+		}
+		ch <- metrics.NewLazyConstMetric(desyncedAddonsDesc, metrics.GaugeValue, float64(desynced[addonName]), addonName)
+	}
+}
+
+// AddonState returns the state of the ManagedClusterAddOn reported in the metrics. An addon being installed or
+// upgraded is progressing, otherwise its state is determined by the Degraded and Available conditions.
+func AddonState(addon *addonv1alpha1.ManagedClusterAddOn) string {
+	switch {
+	case meta.IsStatusConditionTrue(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionProgressing):
+		return AddonStateProgressing
+	case meta.IsStatusConditionTrue(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionDegraded):
+		return AddonStateDegraded
+	case meta.IsStatusConditionTrue(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionAvailable):
+		return AddonStateAvailable
+	case meta.IsStatusConditionFalse(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionAvailable):
+		return AddonStateUnavailable
+	default:
+		return AddonStateUnknown
+	}
+}
+
+// IsDesynced returns true if any desired config of the ManagedClusterAddOn is not applied yet.
+func IsDesynced(addon *addonv1alpha1.ManagedClusterAddOn) bool {
+	for _, config := range addon.Status.ConfigReferences {
+		if !equality.Semantic.DeepEqual(config.DesiredConfig, config.LastAppliedConfig) {
+			return true
+		}
+	}
+	return false
+}
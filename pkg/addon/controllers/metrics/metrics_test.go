@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/metrics/testutil"
+
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	fakeaddon "open-cluster-management.io/api/client/addon/clientset/versioned/fake"
+	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
+)
+
+func newAddon(name, cluster string, desynced bool, conditions ...metav1.Condition) *addonv1alpha1.ManagedClusterAddOn {
+	addon := &addonv1alpha1.ManagedClusterAddOn{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster},
+	}
+	addon.Status.Conditions = conditions
+	applied := &addonv1alpha1.ConfigSpecHash{SpecHash: "hash1"}
+	desired := applied
+	if desynced {
+		desired = &addonv1alpha1.ConfigSpecHash{SpecHash: "hash2"}
+	}
+	addon.Status.ConfigReferences = []addonv1alpha1.ConfigReference{
+		{DesiredConfig: desired, LastAppliedConfig: applied},
+	}
+	return addon
+}
+
+func newCondition(conditionType string, status metav1.ConditionStatus) metav1.Condition {
+	return metav1.Condition{Type: conditionType, Status: status}
+}
+
+func TestAddonCollector(t *testing.T) {
+	addons := []*addonv1alpha1.ManagedClusterAddOn{
+		newAddon("hello", "cluster1", true,
+			newCondition(addonv1alpha1.ManagedClusterAddOnConditionProgressing, metav1.ConditionTrue),
+			newCondition(addonv1alpha1.ManagedClusterAddOnConditionAvailable, metav1.ConditionTrue)),
+		newAddon("hello", "cluster2", false,
+			newCondition(addonv1alpha1.ManagedClusterAddOnConditionAvailable, metav1.ConditionTrue)),
+		newAddon("hello", "cluster3", false,
+			newCondition(addonv1alpha1.ManagedClusterAddOnConditionDegraded, metav1.ConditionTrue),
+			newCondition(addonv1alpha1.ManagedClusterAddOnConditionAvailable, metav1.ConditionTrue)),
+		newAddon("world", "cluster1", false,
+			newCondition(addonv1alpha1.ManagedClusterAddOnConditionAvailable, metav1.ConditionFalse)),
+		newAddon("world", "cluster2", true),
+	}
+
+	addonInformers := addoninformers.NewSharedInformerFactory(fakeaddon.NewSimpleClientset(), 10*time.Minute)
+	for _, addon := range addons {
+		if err := addonInformers.Addon().V1alpha1().ManagedClusterAddOns().Informer().GetStore().Add(addon); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := `
+# HELP addon_manager_desynced_managed_cluster_addons [ALPHA] Number of ManagedClusterAddOns of an addon whose desired configs are not applied yet.
+# TYPE addon_manager_desynced_managed_cluster_addons gauge
+addon_manager_desynced_managed_cluster_addons{addon_name="hello"} 1
+addon_manager_desynced_managed_cluster_addons{addon_name="world"} 1
+# HELP addon_manager_managed_cluster_addons [ALPHA] Number of ManagedClusterAddOns of an addon in each state.
+# TYPE addon_manager_managed_cluster_addons gauge
+addon_manager_managed_cluster_addons{addon_name="hello",state="available"} 1
+addon_manager_managed_cluster_addons{addon_name="hello",state="degraded"} 1
+addon_manager_managed_cluster_addons{addon_name="hello",state="progressing"} 1
+addon_manager_managed_cluster_addons{addon_name="hello",state="unavailable"} 0
+addon_manager_managed_cluster_addons{addon_name="hello",state="unknown"} 0
+addon_manager_managed_cluster_addons{addon_name="world",state="available"} 0
+addon_manager_managed_cluster_addons{addon_name="world",state="degraded"} 0
+addon_manager_managed_cluster_addons{addon_name="world",state="progressing"} 0
+addon_manager_managed_cluster_addons{addon_name="world",state="unavailable"} 1
+addon_manager_managed_cluster_addons{addon_name="world",state="unknown"} 1
+`
+	collector := NewAddonCollector(addonInformers.Addon().V1alpha1().ManagedClusterAddOns().Lister())
+	if err := testutil.CustomCollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
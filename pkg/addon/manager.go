@@ -2,12 +2,16 @@ package addon
 
 import (
 	"context"
+	"fmt"
+	"hash/fnv"
 	"time"
 
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
@@ -21,15 +25,124 @@ import (
 	workv1client "open-cluster-management.io/api/client/work/clientset/versioned"
 	workv1informers "open-cluster-management.io/api/client/work/informers/externalversions"
 
+	"open-cluster-management.io/ocm/pkg/addon/controllers/addoncleanup"
 	"open-cluster-management.io/ocm/pkg/addon/controllers/addonconfiguration"
 	"open-cluster-management.io/ocm/pkg/addon/controllers/addonmanagement"
 	"open-cluster-management.io/ocm/pkg/addon/controllers/addonowner"
 	"open-cluster-management.io/ocm/pkg/addon/controllers/addonprogressing"
 	"open-cluster-management.io/ocm/pkg/addon/controllers/addontemplate"
+	"open-cluster-management.io/ocm/pkg/addon/controllers/addontemplaterevision"
+	"open-cluster-management.io/ocm/pkg/addon/controllers/addonversion"
 	"open-cluster-management.io/ocm/pkg/addon/controllers/managementaddoninstallprogression"
+	"open-cluster-management.io/ocm/pkg/addon/controllers/metrics"
+	"open-cluster-management.io/ocm/pkg/addon/templateagent"
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
 )
 
-func RunManager(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
+const (
+	addonManagementControllerName         = "addon-management"
+	addonConfigurationControllerName      = "addon-configuration"
+	addonOwnerControllerName              = "addon-owner"
+	addonProgressingControllerName        = "addon-progressing"
+	addonInstallProgressionControllerName = "addon-install-progression"
+	addonTemplateRevisionControllerName   = "addon-template-revision"
+	addonCleanupControllerName            = "addon-cleanup"
+	addonVersionControllerName            = "addon-version"
+)
+
+// AddonManagerOptions holds configuration for the addon manager
+type AddonManagerOptions struct {
+	// ControllerWorkers is the number of workers of each controller keyed by the controller name, the controllers
+	// not in it run with their default number of workers.
+	ControllerWorkers map[string]int
+	// ShardCount is the number of shards the addons are divided into by their names, each shard is managed by
+	// an addon manager with the ShardIndex of the shard.
+	ShardCount int
+	// ShardIndex is the index of the shard of addons managed by this addon manager, from 0 to ShardCount-1.
+	ShardIndex int
+	// HelmChart configures where the helm charts referenced by the addon templates could be downloaded from.
+	HelmChart templateagent.HelmChartOptions
+}
+
+// NewAddonManagerOptions returns an AddonManagerOptions
+func NewAddonManagerOptions() *AddonManagerOptions {
+	return &AddonManagerOptions{
+		ControllerWorkers: map[string]int{},
+		ShardCount:        1,
+	}
+}
+
+// AddFlags registers flags for the addon manager
+func (o *AddonManagerOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringToIntVar(&o.ControllerWorkers, "controller-workers", o.ControllerWorkers,
+		fmt.Sprintf("The number of workers of the controllers, like %s=4,%s=8. The controllers are %s, %s, %s, "+
+			"%s, %s, %s, %s and %s.", addonConfigurationControllerName, addonProgressingControllerName,
+			addonManagementControllerName, addonConfigurationControllerName, addonOwnerControllerName,
+			addonProgressingControllerName, addonInstallProgressionControllerName,
+			addonTemplateRevisionControllerName, addonCleanupControllerName, addonVersionControllerName))
+	fs.IntVar(&o.ShardCount, "shard-count", o.ShardCount,
+		"The number of shards the addons are divided into by their names. Each shard should be managed by an "+
+			"addon manager deployed separately with its shard index, and with a leader election lock of its own.")
+	fs.IntVar(&o.ShardIndex, "shard-index", o.ShardIndex,
+		"The index of the shard of addons managed by this addon manager, from 0 to shard-count minus 1.")
+	fs.StringSliceVar(&o.HelmChart.AllowedHosts, "helm-chart-allowed-hosts", o.HelmChart.AllowedHosts,
+		"The hosts of the chart repositories and the OCI registries the helm charts of the addon templates could be "+
+			"downloaded from, even if they have private addresses. The charts could be downloaded from any host with "+
+			"public addresses if it is empty.")
+	fs.StringVar(&o.HelmChart.RegistryConfig, "helm-registry-config", o.HelmChart.RegistryConfig,
+		"The path of the config file with the credentials of the OCI registries of the helm charts. The default "+
+			"config files of Helm and Docker are used if it is empty.")
+}
+
+// Validate checks the options of the addon manager
+func (o *AddonManagerOptions) Validate() error {
+	if o.ShardCount < 1 {
+		return fmt.Errorf("the shard count must be at least 1, but got %d", o.ShardCount)
+	}
+	if o.ShardIndex < 0 || o.ShardIndex >= o.ShardCount {
+		return fmt.Errorf("the shard index must be in [0, %d), but got %d", o.ShardCount, o.ShardIndex)
+	}
+	for name, workers := range o.ControllerWorkers {
+		if workers < 1 {
+			return fmt.Errorf("the number of workers of controller %s must be at least 1, but got %d", name, workers)
+		}
+	}
+	return nil
+}
+
+// workers returns the number of workers of the controller.
+func (o *AddonManagerOptions) workers(controllerName string, defaultWorkers int) int {
+	if workers, ok := o.ControllerWorkers[controllerName]; ok {
+		return workers
+	}
+	return defaultWorkers
+}
+
+// inShard returns true if the addon with the name belongs to the shard of this addon manager.
+func (o *AddonManagerOptions) inShard(addonName string) bool {
+	if o.ShardCount <= 1 {
+		return true
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(addonName))
+	return int(h.Sum32()%uint32(o.ShardCount)) == o.ShardIndex
+}
+
+// shardFilter filters the ClusterManagementAddOns and ManagedClusterAddOns in the shard of this addon manager.
+func (o *AddonManagerOptions) shardFilter(obj interface{}) bool {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	return o.inShard(accessor.GetName())
+}
+
+// RunAddonManager starts the controllers on hub to manage the addons.
+func (o *AddonManagerOptions) RunAddonManager(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+
 	kubeConfig := controllerContext.KubeConfig
 	hubKubeClient, err := kubernetes.NewForConfig(kubeConfig)
 	if err != nil {
@@ -58,26 +171,21 @@ func RunManager(ctx context.Context, controllerContext *controllercmd.Controller
 
 	clusterInformerFactory := clusterinformers.NewSharedInformerFactory(hubClusterClient, 30*time.Minute)
 	addonInformerFactory := addoninformers.NewSharedInformerFactory(addonClient, 30*time.Minute)
+	// only watch the manifestworks deployed by the addons, and list them in pages.
 	workInformers := workv1informers.NewSharedInformerFactoryWithOptions(workClient, 10*time.Minute,
-		workv1informers.WithTweakListOptions(func(listOptions *metav1.ListOptions) {
-			selector := &metav1.LabelSelector{
-				MatchExpressions: []metav1.LabelSelectorRequirement{
-					{
-						Key:      addonv1alpha1.AddonLabelKey,
-						Operator: metav1.LabelSelectorOpExists,
-					},
-				},
-			}
-			listOptions.LabelSelector = metav1.FormatLabelSelector(selector)
-		}),
+		workv1informers.WithTweakListOptions(commonhelpers.PaginatedListOptions(
+			commonhelpers.DefaultListPageSize,
+			commonhelpers.LabelExistsListOptions(addonv1alpha1.AddonLabelKey),
+		)),
 	)
 
 	dynamicInformers := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 10*time.Minute)
 
-	return RunControllerManagerWithInformers(
+	return o.RunControllerManagerWithInformers(
 		ctx, controllerContext,
 		hubKubeClient,
 		addonClient,
+		workClient,
 		clusterInformerFactory,
 		addonInformerFactory,
 		workInformers,
@@ -85,16 +193,36 @@ func RunManager(ctx context.Context, controllerContext *controllercmd.Controller
 	)
 }
 
-func RunControllerManagerWithInformers(
+func (o *AddonManagerOptions) RunControllerManagerWithInformers(
 	ctx context.Context,
 	controllerContext *controllercmd.ControllerContext,
 	hubKubeClient kubernetes.Interface,
 	hubAddOnClient addonv1alpha1client.Interface,
+	hubWorkClient workv1client.Interface,
 	clusterInformers clusterinformers.SharedInformerFactory,
 	addonInformers addoninformers.SharedInformerFactory,
 	workinformers workv1informers.SharedInformerFactory,
 	dynamicInformers dynamicinformer.DynamicSharedInformerFactory,
 ) error {
+	if err := commonhelpers.SetInformerTransform(commonhelpers.TrimManagedFieldsAndLastApplied,
+		workinformers.Work().V1().ManifestWorks().Informer(),
+		addonInformers.Addon().V1alpha1().ClusterManagementAddOns().Informer(),
+		addonInformers.Addon().V1alpha1().AddOnTemplates().Informer(),
+		clusterInformers.Cluster().V1().ManagedClusters().Informer(),
+		clusterInformers.Cluster().V1beta1().Placements().Informer(),
+		clusterInformers.Cluster().V1beta1().PlacementDecisions().Informer(),
+	); err != nil {
+		return err
+	}
+	// the addons are updated by the addonowner controller
+	if err := commonhelpers.SetInformerTransform(commonhelpers.TrimManagedFields,
+		addonInformers.Addon().V1alpha1().ManagedClusterAddOns().Informer(),
+	); err != nil {
+		return err
+	}
+
+	metrics.Register(addonInformers.Addon().V1alpha1().ManagedClusterAddOns().Lister())
+
 	// addonDeployController
 	err := workinformers.Work().V1().ManifestWorks().Informer().AddIndexers(
 		cache.Indexers{
@@ -118,6 +246,33 @@ func RunControllerManagerWithInformers(
 		return err
 	}
 
+	// addonCleanupController
+	err = workinformers.Work().V1().ManifestWorks().Informer().AddIndexers(
+		cache.Indexers{addoncleanup.AddonResourceByAddon: addoncleanup.IndexAddonResourceByAddon})
+	if err != nil {
+		return err
+	}
+	// only watch the CSRs and rolebindings created for the addons
+	addonResourceInformers := kubeinformers.NewSharedInformerFactoryWithOptions(hubKubeClient, 10*time.Minute,
+		kubeinformers.WithTweakListOptions(commonhelpers.LabelExistsListOptions(addonv1alpha1.AddonLabelKey)),
+	)
+	if err := commonhelpers.SetInformerTransform(commonhelpers.TrimManagedFieldsAndLastApplied,
+		addonResourceInformers.Certificates().V1().CertificateSigningRequests().Informer(),
+		addonResourceInformers.Rbac().V1().RoleBindings().Informer(),
+	); err != nil {
+		return err
+	}
+	err = addonResourceInformers.Certificates().V1().CertificateSigningRequests().Informer().AddIndexers(
+		cache.Indexers{addoncleanup.AddonResourceByAddon: addoncleanup.IndexAddonResourceByAddon})
+	if err != nil {
+		return err
+	}
+	err = addonResourceInformers.Rbac().V1().RoleBindings().Informer().AddIndexers(
+		cache.Indexers{addoncleanup.AddonResourceByAddon: addoncleanup.IndexAddonResourceByAddon})
+	if err != nil {
+		return err
+	}
+
 	// managementAddonConfigController
 	err = addonInformers.Addon().V1alpha1().ClusterManagementAddOns().Informer().AddIndexers(
 		cache.Indexers{
@@ -128,13 +283,19 @@ func RunControllerManagerWithInformers(
 		return err
 	}
 
+	// the addons not in the shard of this addon manager are filtered out
+	addonFilterFunc := func(obj interface{}) bool {
+		return utils.ManagedByAddonManager(obj) && o.shardFilter(obj)
+	}
+
 	addonManagementController := addonmanagement.NewAddonManagementController(
 		hubAddOnClient,
 		addonInformers.Addon().V1alpha1().ManagedClusterAddOns(),
 		addonInformers.Addon().V1alpha1().ClusterManagementAddOns(),
+		clusterInformers.Cluster().V1().ManagedClusters(),
 		clusterInformers.Cluster().V1beta1().Placements(),
 		clusterInformers.Cluster().V1beta1().PlacementDecisions(),
-		utils.ManagedByAddonManager,
+		addonFilterFunc,
 		controllerContext.EventRecorder,
 	)
 
@@ -144,7 +305,7 @@ func RunControllerManagerWithInformers(
 		addonInformers.Addon().V1alpha1().ClusterManagementAddOns(),
 		clusterInformers.Cluster().V1beta1().Placements(),
 		clusterInformers.Cluster().V1beta1().PlacementDecisions(),
-		utils.ManagedByAddonManager,
+		addonFilterFunc,
 		controllerContext.EventRecorder,
 	)
 
@@ -152,7 +313,7 @@ func RunControllerManagerWithInformers(
 		hubAddOnClient,
 		addonInformers.Addon().V1alpha1().ManagedClusterAddOns(),
 		addonInformers.Addon().V1alpha1().ClusterManagementAddOns(),
-		utils.ManagedByAddonManager,
+		addonFilterFunc,
 		controllerContext.EventRecorder,
 	)
 
@@ -161,18 +322,33 @@ func RunControllerManagerWithInformers(
 		addonInformers.Addon().V1alpha1().ManagedClusterAddOns(),
 		addonInformers.Addon().V1alpha1().ClusterManagementAddOns(),
 		workinformers.Work().V1().ManifestWorks(),
-		utils.ManagedByAddonManager,
+		addonFilterFunc,
 		controllerContext.EventRecorder,
+		commonhelpers.NewEventRecorder(ctx, hubKubeClient, "addon-manager"),
 	)
 
 	mgmtAddonInstallProgressionController := managementaddoninstallprogression.NewManagementAddonInstallProgressionController(
 		hubAddOnClient,
 		addonInformers.Addon().V1alpha1().ManagedClusterAddOns(),
 		addonInformers.Addon().V1alpha1().ClusterManagementAddOns(),
-		utils.ManagedByAddonManager,
+		addonFilterFunc,
 		controllerContext.EventRecorder,
 	)
 
+	// the revisions of the addon templates are kept in the namespace of the addon-manager
+	templateRevisionNamespace := controllerContext.OperatorNamespace
+	if len(templateRevisionNamespace) == 0 {
+		templateRevisionNamespace = templateagent.DefaultTemplateRevisionNamespace
+	}
+	templateRevisionInformers := kubeinformers.NewSharedInformerFactoryWithOptions(hubKubeClient, 10*time.Minute,
+		kubeinformers.WithNamespace(templateRevisionNamespace),
+		kubeinformers.WithTweakListOptions(commonhelpers.LabelExistsListOptions(templateagent.TemplateRevisionLabelKey)),
+	)
+	if err := commonhelpers.SetInformerTransform(commonhelpers.TrimManagedFields,
+		templateRevisionInformers.Core().V1().ConfigMaps().Informer()); err != nil {
+		return err
+	}
+
 	addonTemplateController := addontemplate.NewAddonTemplateController(
 		controllerContext.KubeConfig,
 		hubKubeClient,
@@ -181,22 +357,62 @@ func RunControllerManagerWithInformers(
 		clusterInformers,
 		dynamicInformers,
 		workinformers,
+		templateRevisionNamespace,
+		o.HelmChart,
+		o.shardFilter,
 		controllerContext.EventRecorder,
 	)
 
-	go addonManagementController.Run(ctx, 2)
-	go addonConfigurationController.Run(ctx, 2)
-	go addonOwnerController.Run(ctx, 2)
-	go addonProgressingController.Run(ctx, 2)
-	go mgmtAddonInstallProgressionController.Run(ctx, 2)
+	addonTemplateRevisionController := addontemplaterevision.NewAddonTemplateRevisionController(
+		hubKubeClient,
+		templateRevisionNamespace,
+		templateRevisionInformers.Core().V1().ConfigMaps(),
+		addonInformers.Addon().V1alpha1().AddOnTemplates(),
+		addonInformers.Addon().V1alpha1().ManagedClusterAddOns(),
+		addonInformers.Addon().V1alpha1().ClusterManagementAddOns(),
+		controllerContext.EventRecorder,
+	)
+
+	addonCleanupController := addoncleanup.NewAddonCleanupController(
+		hubKubeClient,
+		hubWorkClient,
+		addonInformers.Addon().V1alpha1().ManagedClusterAddOns(),
+		workinformers.Work().V1().ManifestWorks(),
+		addonResourceInformers.Certificates().V1().CertificateSigningRequests(),
+		addonResourceInformers.Rbac().V1().RoleBindings(),
+		controllerContext.EventRecorder,
+	)
+
+	addonVersionController := addonversion.NewAddonVersionController(
+		hubAddOnClient,
+		addonInformers.Addon().V1alpha1().ManagedClusterAddOns(),
+		addonInformers.Addon().V1alpha1().ClusterManagementAddOns(),
+		addonInformers.Addon().V1alpha1().AddOnTemplates(),
+		workinformers.Work().V1().ManifestWorks(),
+		controllerContext.EventRecorder,
+	)
+
+	go addonManagementController.Run(ctx, o.workers(addonManagementControllerName, 2))
+	go addonConfigurationController.Run(ctx, o.workers(addonConfigurationControllerName, 2))
+	go addonOwnerController.Run(ctx, o.workers(addonOwnerControllerName, 2))
+	go addonProgressingController.Run(ctx, o.workers(addonProgressingControllerName, 2))
+	go mgmtAddonInstallProgressionController.Run(ctx, o.workers(addonInstallProgressionControllerName, 2))
 	// There should be only one instance of addonTemplateController running, since the addonTemplateController will
 	// start a goroutine for each template-type addon it watches.
 	go addonTemplateController.Run(ctx, 1)
+	// the controllers below are not filtered by the addon, so they only run in the first shard.
+	if o.ShardIndex == 0 {
+		go addonTemplateRevisionController.Run(ctx, o.workers(addonTemplateRevisionControllerName, 1))
+		go addonCleanupController.Run(ctx, o.workers(addonCleanupControllerName, 1))
+		go addonVersionController.Run(ctx, o.workers(addonVersionControllerName, 2))
+	}
 
 	clusterInformers.Start(ctx.Done())
 	addonInformers.Start(ctx.Done())
 	workinformers.Start(ctx.Done())
 	dynamicInformers.Start(ctx.Done())
+	templateRevisionInformers.Start(ctx.Done())
+	addonResourceInformers.Start(ctx.Done())
 
 	<-ctx.Done()
 	return nil
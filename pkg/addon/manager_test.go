@@ -0,0 +1,78 @@
+package addon
+
+import (
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name      string
+		options   *AddonManagerOptions
+		expectErr bool
+	}{
+		{
+			name:    "default",
+			options: NewAddonManagerOptions(),
+		},
+		{
+			name:      "invalid shard count",
+			options:   &AddonManagerOptions{ShardCount: 0},
+			expectErr: true,
+		},
+		{
+			name:      "invalid shard index",
+			options:   &AddonManagerOptions{ShardCount: 2, ShardIndex: 2},
+			expectErr: true,
+		},
+		{
+			name: "invalid workers",
+			options: &AddonManagerOptions{
+				ShardCount:        1,
+				ControllerWorkers: map[string]int{addonProgressingControllerName: 0},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.options.Validate()
+			if (err != nil) != c.expectErr {
+				t.Errorf("expected error %v, but got %v", c.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestShardFilter(t *testing.T) {
+	shards := []*AddonManagerOptions{
+		{ShardCount: 3, ShardIndex: 0},
+		{ShardCount: 3, ShardIndex: 1},
+		{ShardCount: 3, ShardIndex: 2},
+	}
+
+	// each addon belongs to exactly one shard
+	for i := 0; i < 20; i++ {
+		addon := &addonv1alpha1.ClusterManagementAddOn{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("addon-%d", i)},
+		}
+		count := 0
+		for _, shard := range shards {
+			if shard.shardFilter(addon) {
+				count++
+			}
+		}
+		if count != 1 {
+			t.Errorf("expected addon %s in one shard, but got %d", addon.Name, count)
+		}
+	}
+
+	if !NewAddonManagerOptions().shardFilter(&addonv1alpha1.ClusterManagementAddOn{}) {
+		t.Errorf("expected all addons in the shard if the addons are not sharded")
+	}
+}
@@ -8,7 +8,10 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	"open-cluster-management.io/addon-framework/pkg/addonfactory"
+	"open-cluster-management.io/addon-framework/pkg/addonmanager/constants"
 	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+
+	"open-cluster-management.io/ocm/pkg/common/helpers"
 )
 
 type deploymentDecorator interface {
@@ -112,6 +115,52 @@ func (d *volumeDecorator) decorate(deployment *appsv1.Deployment) error {
 	return nil
 }
 
+// managedKubeconfigDecorator mounts the kubeconfig secret of the managed cluster to the deployments which run on the
+// hosting cluster in Hosted mode, so the agent could access both the hub cluster and the managed cluster.
+type managedKubeconfigDecorator struct {
+	addonName string
+	hosted    bool
+}
+
+func newManagedKubeconfigDecorator(addonName string, hosted bool) deploymentDecorator {
+	return &managedKubeconfigDecorator{
+		addonName: addonName,
+		hosted:    hosted,
+	}
+}
+
+func (d *managedKubeconfigDecorator) decorate(deployment *appsv1.Deployment) error {
+	if !d.hosted {
+		return nil
+	}
+	location, exist, err := constants.GetHostedManifestLocation(deployment.Labels, deployment.Annotations)
+	if err != nil {
+		return err
+	}
+	if !exist || location != addonapiv1alpha1.HostedManifestLocationHostingValue {
+		return nil
+	}
+
+	for j := range deployment.Spec.Template.Spec.Containers {
+		deployment.Spec.Template.Spec.Containers[j].VolumeMounts = append(
+			deployment.Spec.Template.Spec.Containers[j].VolumeMounts, corev1.VolumeMount{
+				Name:      "managed-kubeconfig",
+				MountPath: managedKubeconfigSecretMountPath(),
+			})
+	}
+
+	deployment.Spec.Template.Spec.Volumes = append(deployment.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: "managed-kubeconfig",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: ManagedKubeconfigSecretName(d.addonName),
+			},
+		},
+	})
+
+	return nil
+}
+
 type nodePlacementDecorator struct {
 	privateValues addonfactory.Values
 }
@@ -173,6 +222,50 @@ func (d *imageDecorator) decorate(deployment *appsv1.Deployment) error {
 	return nil
 }
 
+// workloadOverridesDecorator sets the resource requirements of the containers, the replicas and the priority class
+// name of the deployments from the AddOnDeploymentConfigs, so heavyweight agents could be sized per cluster.
+type workloadOverridesDecorator struct {
+	privateValues addonfactory.Values
+}
+
+func newWorkloadOverridesDecorator(privateValues addonfactory.Values) deploymentDecorator {
+	return &workloadOverridesDecorator{
+		privateValues: privateValues,
+	}
+}
+
+func (d *workloadOverridesDecorator) decorate(deployment *appsv1.Deployment) error {
+	value, ok := d.privateValues[WorkloadOverridesPrivateValueKey]
+	if !ok {
+		return nil
+	}
+
+	overrides, ok := value.(*helpers.AgentWorkloadOverrides)
+	if !ok {
+		return fmt.Errorf("workload overrides value is invalid")
+	}
+
+	for i := range deployment.Spec.Template.Spec.Containers {
+		container := &deployment.Spec.Template.Spec.Containers[i]
+		if requirements, ok := overrides.ResourceRequirements[container.Name]; ok {
+			container.Resources = requirements
+		} else if requirements, ok := overrides.ResourceRequirements[helpers.AllContainersResourceRequirementsKey]; ok {
+			container.Resources = requirements
+		}
+	}
+
+	if overrides.Replicas != nil {
+		replicas := *overrides.Replicas
+		deployment.Spec.Replicas = &replicas
+	}
+
+	if len(overrides.PriorityClassName) > 0 {
+		deployment.Spec.Template.Spec.PriorityClassName = overrides.PriorityClassName
+	}
+
+	return nil
+}
+
 func hubKubeconfigSecretMountPath() string {
 	return "/managed/hub-kubeconfig"
 }
@@ -181,6 +274,18 @@ func HubKubeconfigSecretName(addonName string) string {
 	return fmt.Sprintf("%s-hub-kubeconfig", addonName)
 }
 
+func managedKubeconfigSecretMountPath() string {
+	return "/managed/managed-kubeconfig"
+}
+
+// ManagedKubeconfigSecretName returns the name of the secret which contains the kubeconfig of the managed cluster
+// for the agent running on the hosting cluster in Hosted mode. The secret is expected to be provisioned in the
+// install namespace of the agent on the hosting cluster, e.g. copied from the external managed kubeconfig secret
+// of the klusterlet in Hosted mode.
+func ManagedKubeconfigSecretName(addonName string) string {
+	return fmt.Sprintf("%s-managed-kubeconfig", addonName)
+}
+
 func CustomSignedSecretName(addonName, signerName string) string {
 	return fmt.Sprintf("%s-%s-client-cert", addonName, strings.ReplaceAll(signerName, "/", "-"))
 }
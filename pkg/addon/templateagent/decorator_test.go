@@ -0,0 +1,115 @@
+package templateagent
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"open-cluster-management.io/addon-framework/pkg/addonfactory"
+
+	"open-cluster-management.io/ocm/pkg/common/helpers"
+)
+
+func TestWorkloadOverridesDecorator(t *testing.T) {
+	newDeployment := func() *appsv1.Deployment {
+		replicas := int32(2)
+		deployment := &appsv1.Deployment{}
+		deployment.Spec.Replicas = &replicas
+		deployment.Spec.Template.Spec.Containers = []corev1.Container{
+			{
+				Name: "manager",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				},
+			},
+			{Name: "sidecar"},
+		}
+		return deployment
+	}
+	small := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("32Mi")},
+	}
+	large := corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+	}
+	one := int32(1)
+
+	cases := []struct {
+		name          string
+		privateValues addonfactory.Values
+		validate      func(t *testing.T, deployment *appsv1.Deployment)
+		expectErr     bool
+	}{
+		{
+			name:          "no overrides",
+			privateValues: addonfactory.Values{},
+			validate: func(t *testing.T, deployment *appsv1.Deployment) {
+				if !equality.Semantic.DeepEqual(deployment, newDeployment()) {
+					t.Errorf("expected deployment not changed, but got %v", deployment)
+				}
+			},
+		},
+		{
+			name: "overrides",
+			privateValues: addonfactory.Values{
+				WorkloadOverridesPrivateValueKey: &helpers.AgentWorkloadOverrides{
+					ResourceRequirements: map[string]corev1.ResourceRequirements{
+						helpers.AllContainersResourceRequirementsKey: small,
+						"manager": large,
+					},
+					Replicas:          &one,
+					PriorityClassName: "edge-low",
+				},
+			},
+			validate: func(t *testing.T, deployment *appsv1.Deployment) {
+				containers := deployment.Spec.Template.Spec.Containers
+				if !equality.Semantic.DeepEqual(containers[0].Resources, large) {
+					t.Errorf("unexpected resources of manager %v", containers[0].Resources)
+				}
+				if !equality.Semantic.DeepEqual(containers[1].Resources, small) {
+					t.Errorf("unexpected resources of sidecar %v", containers[1].Resources)
+				}
+				if *deployment.Spec.Replicas != 1 {
+					t.Errorf("expected replicas 1, but got %d", *deployment.Spec.Replicas)
+				}
+				if deployment.Spec.Template.Spec.PriorityClassName != "edge-low" {
+					t.Errorf("unexpected priority class name %q", deployment.Spec.Template.Spec.PriorityClassName)
+				}
+			},
+		},
+		{
+			name: "only replicas",
+			privateValues: addonfactory.Values{
+				WorkloadOverridesPrivateValueKey: &helpers.AgentWorkloadOverrides{Replicas: &one},
+			},
+			validate: func(t *testing.T, deployment *appsv1.Deployment) {
+				expected := newDeployment()
+				expected.Spec.Replicas = &one
+				if !equality.Semantic.DeepEqual(deployment, expected) {
+					t.Errorf("expected only replicas changed, but got %v", deployment)
+				}
+			},
+		},
+		{
+			name:          "invalid value",
+			privateValues: addonfactory.Values{WorkloadOverridesPrivateValueKey: "1"},
+			expectErr:     true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			deployment := newDeployment()
+			err := newWorkloadOverridesDecorator(c.privateValues).decorate(deployment)
+			if (err != nil) != c.expectErr {
+				t.Fatalf("expected error %v, but got %v", c.expectErr, err)
+			}
+			if c.validate != nil {
+				c.validate(t, deployment)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package templateagent
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"open-cluster-management.io/addon-framework/pkg/agent"
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+const (
+	// HealthProbesAnnotationKey is a JSON list of health probes on the AddOnTemplate. When it is set, the health
+	// of the addon is computed from CEL expressions over the status feedback values of the probed resources,
+	// instead of the availability of the agent deployments. For example:
+	//
+	//	[{"resourceIdentifier": {"group": "apps", "resource": "deployments", "name": "agent", "namespace": "addon"},
+	//	  "wellKnownStatus": true,
+	//	  "jsonPaths": [{"name": "isReady", "path": ".status.conditions[?(@.type==\"Ready\")].status"}],
+	//	  "expressions": ["values.ReadyReplicas >= 1", "values.isReady == \"True\""]}]
+	//
+	// The expressions of a probe access the feedback values of the resource by name with the variable "values",
+	// and the addon is available only if all the expressions of all the probes are evaluated to true. The probes
+	// of all the AddOnTemplates of an addon are merged, so the templates of an addon should define compatible
+	// probes.
+	HealthProbesAnnotationKey = "addon.open-cluster-management.io/health-probes"
+
+	// healthProbeCostLimit limits the cost of evaluating an expression against the feedback values.
+	healthProbeCostLimit = 1000000
+)
+
+// HealthProbe defines how to probe a resource of the addon on the managed cluster.
+type HealthProbe struct {
+	// ResourceIdentifier is the resource to probe, it must be one of the manifests of the AddOnTemplate.
+	ResourceIdentifier workapiv1.ResourceIdentifier `json:"resourceIdentifier"`
+	// WellKnownStatus returns the well known status of the resource as feedback values.
+	WellKnownStatus bool `json:"wellKnownStatus,omitempty"`
+	// JsonPaths returns the fields of the resource as feedback values with the given names.
+	JsonPaths []workapiv1.JsonPath `json:"jsonPaths,omitempty"`
+	// Expressions are the CEL expressions evaluated to a bool over the feedback values.
+	Expressions []string `json:"expressions"`
+}
+
+// compiledHealthProbes is the health probes of an AddOnTemplate compiled from the annotation.
+type compiledHealthProbes struct {
+	value    string
+	fields   []agent.ProbeField
+	programs map[workapiv1.ResourceIdentifier][]cel.Program
+}
+
+// compileHealthProbes parses the health probes in the annotation value and compiles the expressions to programs.
+func compileHealthProbes(value string) (*compiledHealthProbes, error) {
+	var probes []HealthProbe
+	if err := json.Unmarshal([]byte(value), &probes); err != nil {
+		return nil, fmt.Errorf("the annotation %s is not a list of health probes: %v", HealthProbesAnnotationKey, err)
+	}
+
+	env, err := cel.NewEnv(
+		cel.Variable("values", cel.MapType(cel.StringType, cel.DynType)),
+		ext.Strings(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled := &compiledHealthProbes{
+		value:    value,
+		programs: map[workapiv1.ResourceIdentifier][]cel.Program{},
+	}
+	for _, probe := range probes {
+		if len(probe.Expressions) == 0 {
+			return nil, fmt.Errorf("no expression defined for the health probe of %v", probe.ResourceIdentifier)
+		}
+		var rules []workapiv1.FeedbackRule
+		if probe.WellKnownStatus {
+			rules = append(rules, workapiv1.FeedbackRule{Type: workapiv1.WellKnownStatusType})
+		}
+		if len(probe.JsonPaths) > 0 {
+			rules = append(rules, workapiv1.FeedbackRule{Type: workapiv1.JSONPathsType, JsonPaths: probe.JsonPaths})
+		}
+		if len(rules) == 0 {
+			return nil, fmt.Errorf("no feedback value defined for the health probe of %v", probe.ResourceIdentifier)
+		}
+		compiled.fields = append(compiled.fields, agent.ProbeField{
+			ResourceIdentifier: probe.ResourceIdentifier,
+			ProbeRules:         rules,
+		})
+
+		for _, expression := range probe.Expressions {
+			ast, issues := env.Compile(expression)
+			if issues.Err() != nil {
+				return nil, fmt.Errorf("failed to compile expression %q: %v", expression, issues.Err())
+			}
+			if !cel.BoolType.IsAssignableType(ast.OutputType()) {
+				return nil, fmt.Errorf("the expression %q is not evaluated to a bool but %v", expression, ast.OutputType())
+			}
+			program, err := env.Program(ast, cel.CostLimit(healthProbeCostLimit))
+			if err != nil {
+				return nil, fmt.Errorf("failed to build program of expression %q: %v", expression, err)
+			}
+			compiled.programs[probe.ResourceIdentifier] = append(compiled.programs[probe.ResourceIdentifier], program)
+		}
+	}
+	return compiled, nil
+}
+
+// healthProber returns the work prober built from the health probes of the AddOnTemplates of the addon, or the
+// deployment availability prober if none of the templates defines health probes.
+func (a *CRDTemplateAgentAddon) healthProber() *agent.HealthProber {
+	deploymentProber := &agent.HealthProber{
+		Type: agent.HealthProberTypeDeploymentAvailability,
+	}
+
+	templates, err := a.addonTemplateLister.List(labels.Everything())
+	if err != nil {
+		a.logger.Error(err, "Failed to list addon templates", "addonName", a.addonName)
+		return deploymentProber
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+
+	var fields []agent.ProbeField
+	programs := map[workapiv1.ResourceIdentifier][]cel.Program{}
+	for _, template := range templates {
+		if template.Spec.AddonName != a.addonName {
+			continue
+		}
+		probes, err := a.getHealthProbes(template)
+		if err != nil {
+			a.logger.Error(err, "Invalid health probes of addon template, ignore them", "addonTemplate", template.Name)
+			continue
+		}
+		if probes == nil {
+			continue
+		}
+		for _, field := range probes.fields {
+			if _, ok := programs[field.ResourceIdentifier]; !ok {
+				fields = append(fields, field)
+			}
+			programs[field.ResourceIdentifier] = append(programs[field.ResourceIdentifier],
+				probes.programs[field.ResourceIdentifier]...)
+		}
+	}
+	if len(fields) == 0 {
+		return deploymentProber
+	}
+
+	return &agent.HealthProber{
+		Type: agent.HealthProberTypeWork,
+		WorkProber: &agent.WorkHealthProber{
+			ProbeFields: fields,
+			HealthCheck: celHealthCheck(programs),
+		},
+	}
+}
+
+// getHealthProbes returns the compiled health probes of the template, the probes are cached until the annotation
+// changes.
+func (a *CRDTemplateAgentAddon) getHealthProbes(template *addonapiv1alpha1.AddOnTemplate) (*compiledHealthProbes, error) {
+	value, ok := template.GetAnnotations()[HealthProbesAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	a.healthProbesLock.Lock()
+	defer a.healthProbesLock.Unlock()
+	if cached, ok := a.healthProbes[template.Name]; ok && cached.value == value {
+		return cached, nil
+	}
+	probes, err := compileHealthProbes(value)
+	if err != nil {
+		return nil, err
+	}
+	a.healthProbes[template.Name] = probes
+	return probes, nil
+}
+
+// celHealthCheck returns a health check func which evaluates the programs of a resource against its feedback values.
+func celHealthCheck(programs map[workapiv1.ResourceIdentifier][]cel.Program) agent.AddonHealthCheckFunc {
+	return func(identifier workapiv1.ResourceIdentifier, result workapiv1.StatusFeedbackResult) error {
+		values := feedbackValues(result)
+		for _, program := range programs[identifier] {
+			out, _, err := program.Eval(map[string]interface{}{"values": values})
+			if err != nil {
+				return fmt.Errorf("failed to evaluate the health probe of %s %s/%s: %v",
+					identifier.Resource, identifier.Namespace, identifier.Name, err)
+			}
+			if healthy, ok := out.Value().(bool); !ok || !healthy {
+				return fmt.Errorf("the health probe of %s %s/%s is not satisfied",
+					identifier.Resource, identifier.Namespace, identifier.Name)
+			}
+		}
+		return nil
+	}
+}
+
+// feedbackValues converts the status feedback values to a map keyed by the value name.
+func feedbackValues(result workapiv1.StatusFeedbackResult) map[string]interface{} {
+	values := map[string]interface{}{}
+	for _, value := range result.Values {
+		switch {
+		case value.Value.Integer != nil:
+			values[value.Name] = *value.Value.Integer
+		case value.Value.String != nil:
+			values[value.Name] = *value.Value.String
+		case value.Value.Boolean != nil:
+			values[value.Name] = *value.Value.Boolean
+		case value.Value.JsonRaw != nil:
+			var raw interface{}
+			if err := json.Unmarshal([]byte(*value.Value.JsonRaw), &raw); err != nil {
+				values[value.Name] = *value.Value.JsonRaw
+				continue
+			}
+			values[value.Name] = raw
+		}
+	}
+	return values
+}
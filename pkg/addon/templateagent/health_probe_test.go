@@ -0,0 +1,203 @@
+package templateagent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"open-cluster-management.io/addon-framework/pkg/agent"
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	fakeaddon "open-cluster-management.io/api/client/addon/clientset/versioned/fake"
+	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+const testHealthProbes = `[{
+	"resourceIdentifier": {"group": "apps", "resource": "deployments", "name": "agent", "namespace": "addon"},
+	"wellKnownStatus": true,
+	"jsonPaths": [{"name": "isReady", "path": ".status.conditions[?(@.type==\"Ready\")].status"}],
+	"expressions": ["values.ReadyReplicas >= 1", "values.isReady == \"True\""]
+}]`
+
+func TestHealthProber(t *testing.T) {
+	cases := []struct {
+		name         string
+		templates    []*addonapiv1alpha1.AddOnTemplate
+		expectedType agent.HealthProberType
+		expectedNum  int
+	}{
+		{
+			name:         "no health probes",
+			templates:    []*addonapiv1alpha1.AddOnTemplate{newHealthProbeTemplate("template1", "test", "")},
+			expectedType: agent.HealthProberTypeDeploymentAvailability,
+		},
+		{
+			name:         "health probes",
+			templates:    []*addonapiv1alpha1.AddOnTemplate{newHealthProbeTemplate("template1", "test", testHealthProbes)},
+			expectedType: agent.HealthProberTypeWork,
+			expectedNum:  1,
+		},
+		{
+			name: "merge the health probes of the templates",
+			templates: []*addonapiv1alpha1.AddOnTemplate{
+				newHealthProbeTemplate("template1", "test", testHealthProbes),
+				newHealthProbeTemplate("template2", "test", testHealthProbes),
+				newHealthProbeTemplate("template3", "other", `[{"resourceIdentifier": {"name": "other"},
+					"wellKnownStatus": true, "expressions": ["true"]}]`),
+			},
+			expectedType: agent.HealthProberTypeWork,
+			expectedNum:  1,
+		},
+		{
+			name: "invalid health probes",
+			templates: []*addonapiv1alpha1.AddOnTemplate{
+				newHealthProbeTemplate("template1", "test", `[{"resourceIdentifier": {"name": "agent"},
+					"wellKnownStatus": true, "expressions": ["values.ReadyReplicas"]}]`),
+			},
+			expectedType: agent.HealthProberTypeDeploymentAvailability,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			addonClient := fakeaddon.NewSimpleClientset()
+			addonInformerFactory := addoninformers.NewSharedInformerFactory(addonClient, 30*time.Minute)
+			for _, template := range c.templates {
+				if err := addonInformerFactory.Addon().V1alpha1().AddOnTemplates().Informer().GetStore().Add(template); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			agentAddon := NewCRDTemplateAgentAddon(context.TODO(), "test", "test-agent", nil, addonClient,
+				addonInformerFactory, nil)
+			prober := agentAddon.GetAgentAddonOptions().HealthProber
+			if prober.Type != c.expectedType {
+				t.Fatalf("expected prober type %s, but got %s", c.expectedType, prober.Type)
+			}
+			if prober.WorkProber != nil && len(prober.WorkProber.ProbeFields) != c.expectedNum {
+				t.Errorf("expected %d probe fields, but got %v", c.expectedNum, prober.WorkProber.ProbeFields)
+			}
+		})
+	}
+}
+
+func TestCELHealthCheck(t *testing.T) {
+	probes, err := compileHealthProbes(testHealthProbes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	identifier := probes.fields[0].ResourceIdentifier
+
+	cases := []struct {
+		name        string
+		identifier  workapiv1.ResourceIdentifier
+		values      []workapiv1.FeedbackValue
+		expectedErr bool
+	}{
+		{
+			name:       "healthy",
+			identifier: identifier,
+			values: []workapiv1.FeedbackValue{
+				newIntegerFeedbackValue("ReadyReplicas", 1),
+				newStringFeedbackValue("isReady", "True"),
+			},
+		},
+		{
+			name:       "not ready",
+			identifier: identifier,
+			values: []workapiv1.FeedbackValue{
+				newIntegerFeedbackValue("ReadyReplicas", 1),
+				newStringFeedbackValue("isReady", "False"),
+			},
+			expectedErr: true,
+		},
+		{
+			name:        "value missing",
+			identifier:  identifier,
+			values:      []workapiv1.FeedbackValue{newStringFeedbackValue("isReady", "True")},
+			expectedErr: true,
+		},
+		{
+			name:       "resource without probe",
+			identifier: workapiv1.ResourceIdentifier{Name: "other"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := celHealthCheck(probes.programs)(c.identifier, workapiv1.StatusFeedbackResult{Values: c.values})
+			if (err != nil) != c.expectedErr {
+				t.Errorf("expected error %v, but got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestCompileHealthProbes(t *testing.T) {
+	cases := []struct {
+		name        string
+		value       string
+		expectedErr bool
+	}{
+		{
+			name:  "valid",
+			value: testHealthProbes,
+		},
+		{
+			name:        "not a list",
+			value:       `{}`,
+			expectedErr: true,
+		},
+		{
+			name:        "no expression",
+			value:       `[{"resourceIdentifier": {"name": "agent"}, "wellKnownStatus": true}]`,
+			expectedErr: true,
+		},
+		{
+			name:        "no feedback value",
+			value:       `[{"resourceIdentifier": {"name": "agent"}, "expressions": ["true"]}]`,
+			expectedErr: true,
+		},
+		{
+			name:        "invalid expression",
+			value:       `[{"resourceIdentifier": {"name": "agent"}, "wellKnownStatus": true, "expressions": ["values."]}]`,
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := compileHealthProbes(c.value)
+			if (err != nil) != c.expectedErr {
+				t.Errorf("expected error %v, but got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
+
+func newHealthProbeTemplate(name, addonName, probes string) *addonapiv1alpha1.AddOnTemplate {
+	template := &addonapiv1alpha1.AddOnTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       addonapiv1alpha1.AddOnTemplateSpec{AddonName: addonName},
+	}
+	if len(probes) > 0 {
+		template.Annotations = map[string]string{HealthProbesAnnotationKey: probes}
+	}
+	return template
+}
+
+func newIntegerFeedbackValue(name string, value int64) workapiv1.FeedbackValue {
+	return workapiv1.FeedbackValue{
+		Name:  name,
+		Value: workapiv1.FieldValue{Type: workapiv1.Integer, Integer: &value},
+	}
+}
+
+func newStringFeedbackValue(name string, value string) workapiv1.FeedbackValue {
+	return workapiv1.FeedbackValue{
+		Name:  name,
+		Value: workapiv1.FieldValue{Type: workapiv1.String, String: &value},
+	}
+}
@@ -0,0 +1,449 @@
+package templateagent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/repo"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/util/flowcontrol"
+	sigsyaml "sigs.k8s.io/yaml"
+
+	"open-cluster-management.io/addon-framework/pkg/addonfactory"
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+const (
+	// HelmChartAnnotationKey is the annotation on the AddOnTemplate to reference a Helm chart, which is rendered
+	// per cluster and deployed together with the manifests in the spec of the template. The chart is referenced by
+	//   - a chart repository, like {"repo": "https://charts.example.com", "name": "agent", "version": "1.0.0"}
+	//   - an OCI registry, like {"url": "oci://registry.example.com/charts/agent", "version": "1.0.0"}
+	//   - a chart archive, like {"url": "https://charts.example.com/agent-1.0.0.tgz"}
+	//
+	// The chart is rendered with the values in the values.yaml of the chart, overridden by the customized
+	// variables of the AddOnDeploymentConfig, in which a key like "image.tag" sets a nested value. The built-in
+	// values clusterName and addonInstallNamespace are always set, and the values are validated against the
+	// values.schema.json of the chart if it has one.
+	//
+	// The charts are only downloaded over https or from the OCI registries, and from the hosts allowed by the addon
+	// manager. The charts are cached by their references, so a new version of the chart should be referenced by a new
+	// version or a new AddOnTemplate rather than overwriting the existing one.
+	HelmChartAnnotationKey = "addon.open-cluster-management.io/helm-chart"
+
+	ociScheme = "oci://"
+
+	// the charts are cached by their references, and cleaned up if they are not used for a day.
+	helmChartCacheSize = 64
+	helmChartCacheTTL  = 24 * time.Hour
+
+	helmChartDownloadTimeout = 30 * time.Second
+	helmChartInitialBackoff  = 10 * time.Second
+	helmChartMaxBackoff      = 10 * time.Minute
+)
+
+// HelmChartReference is the reference of the Helm chart in the AddOnTemplate annotation.
+type HelmChartReference struct {
+	// Repo is the URL of the chart repository, the chart is resolved by name and version in its index.yaml.
+	Repo string `json:"repo,omitempty"`
+	// Name is the name of the chart in the chart repository.
+	Name string `json:"name,omitempty"`
+	// URL is the URL of an OCI chart starting with oci://, or the URL of a chart archive.
+	URL string `json:"url,omitempty"`
+	// Version is the version of the chart, it is required for the chart repository and the OCI registry.
+	Version string `json:"version,omitempty"`
+}
+
+// helmBuiltinValues includes the built-in values for the helm chart of template addons.
+type helmBuiltinValues struct {
+	ClusterName           string `json:"clusterName"`
+	AddonInstallNamespace string `json:"addonInstallNamespace"`
+}
+
+// getHelmChartReference returns the reference of the helm chart in the template, or nil if the template does not
+// reference a helm chart.
+func getHelmChartReference(template *addonapiv1alpha1.AddOnTemplate) (*HelmChartReference, error) {
+	value, ok := template.GetAnnotations()[HelmChartAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	ref := &HelmChartReference{}
+	if err := json.Unmarshal([]byte(value), ref); err != nil {
+		return nil, fmt.Errorf("the annotation %s is not a helm chart reference: %v", HelmChartAnnotationKey, err)
+	}
+	switch {
+	case len(ref.Repo) > 0 && len(ref.URL) > 0:
+		return nil, fmt.Errorf("only one of repo and url can be set in the annotation %s", HelmChartAnnotationKey)
+	case len(ref.Repo) > 0 && (len(ref.Name) == 0 || len(ref.Version) == 0):
+		return nil, fmt.Errorf("name and version are required for the chart repository in the annotation %s",
+			HelmChartAnnotationKey)
+	case strings.HasPrefix(ref.URL, ociScheme) && len(ref.Version) == 0:
+		return nil, fmt.Errorf("version is required for the OCI chart in the annotation %s", HelmChartAnnotationKey)
+	case len(ref.Repo) == 0 && len(ref.URL) == 0:
+		return nil, fmt.Errorf("one of repo and url is required in the annotation %s", HelmChartAnnotationKey)
+	}
+	return ref, nil
+}
+
+// HelmChartOptions configures where the helm charts referenced by the templates could be downloaded from.
+type HelmChartOptions struct {
+	// AllowedHosts are the hosts of the chart repositories and the OCI registries the charts could be downloaded
+	// from, the hosts are allowed even if they have private addresses. If it is empty, the charts could be
+	// downloaded from any host with public addresses.
+	AllowedHosts []string
+	// RegistryConfig is the path of the config file with the credentials of the OCI registries, the default
+	// config files of Helm and Docker are used if it is empty.
+	RegistryConfig string
+}
+
+// helmChartLoader loads the helm charts referenced by the templates.
+type helmChartLoader interface {
+	load(ref HelmChartReference) (*chart.Chart, error)
+}
+
+// httpHelmChartLoader downloads the helm charts with the getters of Helm from the chart repositories and the URLs
+// of the chart archives over https, or from the OCI registries, and caches them in memory. A chart is downloaded
+// once for concurrent loads of the same reference, and a failed download is not retried until its backoff expires.
+type httpHelmChartLoader struct {
+	allowedHosts   sets.Set[string]
+	registryConfig string
+	getters        getter.Providers
+	// tlsConfig is the TLS config to download the charts over https, it is replaced in the unit tests.
+	tlsConfig *tls.Config
+
+	group    singleflight.Group
+	charts   *utilcache.LRUExpireCache
+	failures *utilcache.LRUExpireCache
+	backoff  *flowcontrol.Backoff
+}
+
+func newHTTPHelmChartLoader(options HelmChartOptions) *httpHelmChartLoader {
+	l := &httpHelmChartLoader{
+		allowedHosts:   sets.New[string](options.AllowedHosts...),
+		registryConfig: options.RegistryConfig,
+		tlsConfig:      &tls.Config{MinVersion: tls.VersionTLS12},
+		charts:         utilcache.NewLRUExpireCache(helmChartCacheSize),
+		failures:       utilcache.NewLRUExpireCache(helmChartCacheSize),
+		backoff:        flowcontrol.NewBackOff(helmChartInitialBackoff, helmChartMaxBackoff),
+	}
+
+	// plain http is refused, and the addresses are checked when dialing, so the charts are not downloaded
+	// through a proxy.
+	transport := &http.Transport{
+		DialContext: func(_ context.Context, _, addr string) (net.Conn, error) {
+			return nil, fmt.Errorf("plain http to %s is not allowed", addr)
+		},
+		DialTLSContext:      l.dialTLS,
+		DisableCompression:  true,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+	l.getters = getter.Providers{
+		{
+			Schemes: []string{"https"},
+			New: func(options ...getter.Option) (getter.Getter, error) {
+				return getter.NewHTTPGetter(append(options,
+					getter.WithTransport(transport), getter.WithTimeout(helmChartDownloadTimeout))...)
+			},
+		},
+		{
+			Schemes: []string{registry.OCIScheme},
+			New: func(options ...getter.Option) (getter.Getter, error) {
+				client, err := registry.NewClient(registry.ClientOptCredentialsFile(l.registryConfig))
+				if err != nil {
+					return nil, err
+				}
+				return getter.NewOCIGetter(append(options, getter.WithRegistryClient(client))...)
+			},
+		},
+	}
+	return l
+}
+
+func (l *httpHelmChartLoader) load(ref HelmChartReference) (*chart.Chart, error) {
+	if c, ok := l.charts.Get(ref); ok {
+		return c.(*chart.Chart), nil
+	}
+	if err, ok := l.failures.Get(ref); ok {
+		return nil, err.(error)
+	}
+
+	key, err := json.Marshal(ref)
+	if err != nil {
+		return nil, err
+	}
+	c, err, _ := l.group.Do(string(key), func() (interface{}, error) {
+		c, err := l.download(ref)
+		if err != nil {
+			l.backoff.Next(string(key), l.backoff.Clock.Now())
+			l.failures.Add(ref, err, l.backoff.Get(string(key)))
+			l.backoff.GC()
+			return nil, err
+		}
+		l.backoff.Reset(string(key))
+		l.charts.Add(ref, c, helmChartCacheTTL)
+		return c, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.(*chart.Chart), nil
+}
+
+func (l *httpHelmChartLoader) download(ref HelmChartReference) (*chart.Chart, error) {
+	chartURL := ref.URL
+	switch {
+	case len(ref.Repo) > 0:
+		var err error
+		if chartURL, err = l.resolveFromRepo(ref); err != nil {
+			return nil, err
+		}
+	case strings.HasPrefix(ref.URL, ociScheme):
+		chartURL = fmt.Sprintf("%s:%s", ref.URL, ref.Version)
+	}
+
+	data, err := l.get(chartURL)
+	if err != nil {
+		return nil, err
+	}
+	c, err := loader.LoadArchive(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load helm chart %v: %v", ref, err)
+	}
+	if len(ref.Version) > 0 && c.Metadata.Version != ref.Version {
+		return nil, fmt.Errorf("the version of helm chart %v is %s", ref, c.Metadata.Version)
+	}
+	return c, nil
+}
+
+// resolveFromRepo returns the URL of the chart resolved by name and version in the index of the chart repository.
+func (l *httpHelmChartLoader) resolveFromRepo(ref HelmChartReference) (string, error) {
+	data, err := l.get(strings.TrimSuffix(ref.Repo, "/") + "/index.yaml")
+	if err != nil {
+		return "", err
+	}
+	index := &repo.IndexFile{}
+	if err := sigsyaml.Unmarshal(data.Bytes(), index); err != nil {
+		return "", fmt.Errorf("failed to parse the index of chart repository %s: %v", ref.Repo, err)
+	}
+	index.SortEntries()
+	version, err := index.Get(ref.Name, ref.Version)
+	if err != nil || version.Version != ref.Version || len(version.URLs) == 0 {
+		return "", fmt.Errorf("chart %s version %s is not found in chart repository %s", ref.Name, ref.Version, ref.Repo)
+	}
+	return repo.ResolveReferenceURL(ref.Repo, version.URLs[0])
+}
+
+// get downloads the URL with the getter of its scheme, only https and oci are allowed.
+func (l *httpHelmChartLoader) get(rawURL string) (*bytes.Buffer, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	g, err := l.getters.ByScheme(u.Scheme)
+	if err != nil {
+		return nil, fmt.Errorf("the scheme of %s is not allowed, only https and oci are supported", rawURL)
+	}
+	// the registry client of Helm does not accept a transport, so the addresses of the OCI registries are checked
+	// before pulling. Note that the registry client accesses the loopback registries over plain http, they have to
+	// be in the allowed hosts anyway.
+	if _, err := l.lookupHost(context.TODO(), u.Hostname()); err != nil {
+		return nil, err
+	}
+	return g.Get(rawURL, getter.WithURL(rawURL))
+}
+
+// dialTLS dials the checked addresses of the host with TLS.
+func (l *httpHelmChartLoader) dialTLS(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	ips, err := l.lookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := l.tlsConfig.Clone()
+	tlsConfig.ServerName = host
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: helmChartDownloadTimeout}, Config: tlsConfig}
+	for _, ip := range ips {
+		var conn net.Conn
+		if conn, err = dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port)); err == nil {
+			return conn, nil
+		}
+	}
+	return nil, err
+}
+
+// lookupHost returns the addresses of the host. The host must be in the allowed hosts if they are set, otherwise
+// all its addresses must be public, so the charts could not be downloaded from the services in the cluster network.
+func (l *httpHelmChartLoader) lookupHost(ctx context.Context, host string) ([]net.IP, error) {
+	if l.allowedHosts.Len() > 0 && !l.allowedHosts.Has(host) {
+		return nil, fmt.Errorf("the host %s is not allowed to download helm charts from", host)
+	}
+
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		var err error
+		if ips, err = net.DefaultResolver.LookupIP(ctx, "ip", host); err != nil {
+			return nil, err
+		}
+	}
+	if l.allowedHosts.Has(host) {
+		return ips, nil
+	}
+	for _, ip := range ips {
+		if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+			ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+			return nil, fmt.Errorf("the host %s has the non-public address %s", host, ip)
+		}
+	}
+	return ips, nil
+}
+
+// renderHelmChart renders the helm chart referenced by the template to objects.
+func (a *CRDTemplateAgentAddon) renderHelmChart(
+	cluster *clusterv1.ManagedCluster,
+	installNamespace string,
+	ref *HelmChartReference,
+	presetValues orderedValues,
+	configValues addonfactory.Values) ([]runtime.Object, error) {
+	c, err := a.helmChartLoader.load(*ref)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := helmValues(cluster, installNamespace, presetValues, configValues)
+	if err != nil {
+		return nil, err
+	}
+	renderValues, err := chartutil.ToRenderValues(c, values,
+		chartutil.ReleaseOptions{Name: a.addonName, Namespace: installNamespace},
+		&chartutil.Capabilities{KubeVersion: chartutil.KubeVersion{Version: cluster.Status.Version.Kubernetes}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render helm chart %s with values: %v", c.Name(), err)
+	}
+
+	var objects []runtime.Object
+	for _, crd := range c.CRDObjects() {
+		crdObjects, err := decodeHelmManifests(crd.File.Data)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, crdObjects...)
+	}
+
+	templates, err := engine.Engine{Strict: true}.Render(c, renderValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render helm chart %s: %v", c.Name(), err)
+	}
+	// sort the filenames of the templates so the manifests are ordered consistently
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		if path.Ext(name) == ".txt" {
+			// skip the NOTES.txt
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		templateObjects, err := decodeHelmManifests([]byte(templates[name]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s of helm chart %s: %v", name, c.Name(), err)
+		}
+		objects = append(objects, templateObjects...)
+	}
+	return objects, nil
+}
+
+// helmValues returns the values to render the helm chart. The customized variables are set as nested values by
+// splitting their keys with ".", and the preset values of template addons are not passed to the chart.
+func helmValues(
+	cluster *clusterv1.ManagedCluster,
+	installNamespace string,
+	presetValues orderedValues,
+	configValues addonfactory.Values) (map[string]interface{}, error) {
+	preset := map[string]bool{}
+	for _, value := range presetValues {
+		preset[value.name] = true
+	}
+
+	values := map[string]interface{}{}
+	for key, value := range configValues {
+		if preset[key] {
+			continue
+		}
+		current := values
+		parts := strings.Split(key, ".")
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := current[part].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				current[part] = next
+			}
+			current = next
+		}
+		current[parts[len(parts)-1]] = value
+	}
+
+	builtinValues, err := addonfactory.JsonStructToValues(helmBuiltinValues{
+		ClusterName:           cluster.Name,
+		AddonInstallNamespace: installNamespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return addonfactory.MergeValues(values, builtinValues), nil
+}
+
+// decodeHelmManifests decodes the yaml documents rendered by the helm chart to unstructured objects.
+func decodeHelmManifests(data []byte) ([]runtime.Object, error) {
+	var objects []runtime.Object
+	reader := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		jsonData, err := yaml.ToJSON(doc)
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(jsonData)) == 0 || string(bytes.TrimSpace(jsonData)) == "null" {
+			continue
+		}
+		object := &unstructured.Unstructured{}
+		if err := object.UnmarshalJSON(jsonData); err != nil {
+			return nil, err
+		}
+		objects = append(objects, object)
+	}
+}
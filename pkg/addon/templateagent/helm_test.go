@@ -0,0 +1,422 @@
+package templateagent
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/registry"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/client-go/util/flowcontrol"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	"open-cluster-management.io/addon-framework/pkg/addonfactory"
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+const testChartDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}-agent
+  namespace: {{ .Values.addonInstallNamespace }}
+spec:
+  selector:
+    matchLabels:
+      app: agent
+  template:
+    metadata:
+      labels:
+        app: agent
+    spec:
+      containers:
+      - name: agent
+        image: {{ .Values.image.repository }}:{{ .Values.image.tag }}
+        args:
+        - --cluster-name={{ .Values.clusterName }}
+`
+
+func newTestChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "agent", Version: "1.0.0"},
+		Values: map[string]interface{}{
+			"image": map[string]interface{}{"repository": "quay.io/agent", "tag": "v1"},
+		},
+		Templates: []*chart.File{
+			{Name: "templates/deployment.yaml", Data: []byte(testChartDeployment)},
+			{Name: "templates/NOTES.txt", Data: []byte("installed")},
+		},
+		Schema: []byte(`{"properties": {"image": {"properties": {"tag": {"type": "string"}}}}}`),
+	}
+}
+
+func newTestChartArchive(t *testing.T) []byte {
+	dir := t.TempDir()
+	path, err := chartutil.Save(newTestChart(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+type fakeHelmChartLoader struct {
+	chart *chart.Chart
+}
+
+func (l *fakeHelmChartLoader) load(ref HelmChartReference) (*chart.Chart, error) {
+	return l.chart, nil
+}
+
+func TestGetHelmChartReference(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		expectedNil bool
+		expectedErr bool
+	}{
+		{
+			name:        "no helm chart",
+			expectedNil: true,
+		},
+		{
+			name:        "chart repository",
+			annotations: map[string]string{HelmChartAnnotationKey: `{"repo": "https://charts.example.com", "name": "agent", "version": "1.0.0"}`},
+		},
+		{
+			name:        "oci chart",
+			annotations: map[string]string{HelmChartAnnotationKey: `{"url": "oci://registry.example.com/charts/agent", "version": "1.0.0"}`},
+		},
+		{
+			name:        "chart archive",
+			annotations: map[string]string{HelmChartAnnotationKey: `{"url": "https://charts.example.com/agent-1.0.0.tgz"}`},
+		},
+		{
+			name:        "oci chart without version",
+			annotations: map[string]string{HelmChartAnnotationKey: `{"url": "oci://registry.example.com/charts/agent"}`},
+			expectedErr: true,
+		},
+		{
+			name:        "chart repository without name",
+			annotations: map[string]string{HelmChartAnnotationKey: `{"repo": "https://charts.example.com", "version": "1.0.0"}`},
+			expectedErr: true,
+		},
+		{
+			name:        "both repo and url",
+			annotations: map[string]string{HelmChartAnnotationKey: `{"repo": "https://charts.example.com", "url": "https://charts.example.com/agent-1.0.0.tgz"}`},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid json",
+			annotations: map[string]string{HelmChartAnnotationKey: `agent`},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			template := &addonapiv1alpha1.AddOnTemplate{ObjectMeta: metav1.ObjectMeta{Annotations: c.annotations}}
+			ref, err := getHelmChartReference(template)
+			if (err != nil) != c.expectedErr {
+				t.Fatalf("expected error %v, but got %v", c.expectedErr, err)
+			}
+			if !c.expectedErr && (ref == nil) != c.expectedNil {
+				t.Errorf("expected nil reference %v, but got %v", c.expectedNil, ref)
+			}
+		})
+	}
+}
+
+// newTestOCIServer returns a registry over plain http serving the chart archive as charts/agent:1.0.0, the loopback
+// registries are accessed over plain http by the registry client of Helm.
+func newTestOCIServer(t *testing.T, archive []byte) *httptest.Server {
+	config := []byte(`{"apiVersion": "v2", "name": "agent", "version": "1.0.0"}`)
+	digest := func(data []byte) string { return fmt.Sprintf("sha256:%x", sha256.Sum256(data)) }
+	manifest := []byte(fmt.Sprintf(`{"schemaVersion": 2, "mediaType": "%s", `+
+		`"config": {"mediaType": "%s", "digest": "%s", "size": %d}, `+
+		`"layers": [{"mediaType": "%s", "digest": "%s", "size": %d}]}`,
+		ocispec.MediaTypeImageManifest, registry.ConfigMediaType, digest(config), len(config),
+		registry.ChartLayerMediaType, digest(archive), len(archive)))
+
+	var server *httptest.Server
+	authorized := func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.Header().Set("WWW-Authenticate",
+				fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:charts/agent:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return false
+		}
+		return true
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("scope") != "repository:charts/agent:pull" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"token": "test-token"}`)
+	})
+	serve := func(path, mediaType string, data []byte) {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			if !authorized(w, r) {
+				return
+			}
+			w.Header().Set("Content-Type", mediaType)
+			w.Header().Set("Docker-Content-Digest", digest(data))
+			w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+			if r.Method != http.MethodHead {
+				_, _ = w.Write(data)
+			}
+		})
+	}
+	serve("/v2/charts/agent/manifests/1.0.0", ocispec.MediaTypeImageManifest, manifest)
+	serve("/v2/charts/agent/manifests/"+digest(manifest), ocispec.MediaTypeImageManifest, manifest)
+	serve("/v2/charts/agent/blobs/"+digest(config), registry.ConfigMediaType, config)
+	serve("/v2/charts/agent/blobs/"+digest(archive), registry.ChartLayerMediaType, archive)
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestRepoServer returns a chart repository over https serving the chart archive, and counts the downloads
+// of the chart archives.
+func newTestRepoServer(t *testing.T, archive []byte, downloads *int32) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/charts/index.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "apiVersion: v1\nentries:\n  agent:\n  - version: 0.9.0\n    urls: [agent-0.9.0.tgz]\n"+
+			"  - version: 1.0.0\n    urls: [agent-1.0.0.tgz]\n")
+	})
+	mux.HandleFunc("/charts/agent-1.0.0.tgz", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(downloads, 1)
+		_, _ = w.Write(archive)
+	})
+	mux.HandleFunc("/charts/agent-2.0.0.tgz", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(downloads, 1)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	server := httptest.NewTLSServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestHelmChartLoader(server *httptest.Server, allowedHosts ...string) *httpHelmChartLoader {
+	l := newHTTPHelmChartLoader(HelmChartOptions{AllowedHosts: allowedHosts})
+	l.tlsConfig = server.Client().Transport.(*http.Transport).TLSClientConfig
+	return l
+}
+
+func TestHTTPHelmChartLoader(t *testing.T) {
+	archive := newTestChartArchive(t)
+	var downloads int32
+	server := newTestRepoServer(t, archive, &downloads)
+	ociServer := newTestOCIServer(t, archive)
+	ociHost := strings.TrimPrefix(ociServer.URL, "http://")
+
+	cases := []struct {
+		name         string
+		ref          HelmChartReference
+		allowedHosts []string
+		expectedErr  bool
+	}{
+		{
+			name:         "chart repository",
+			ref:          HelmChartReference{Repo: server.URL + "/charts", Name: "agent", Version: "1.0.0"},
+			allowedHosts: []string{"127.0.0.1"},
+		},
+		{
+			name:         "version not found in chart repository",
+			ref:          HelmChartReference{Repo: server.URL + "/charts", Name: "agent", Version: "2.0.0"},
+			allowedHosts: []string{"127.0.0.1"},
+			expectedErr:  true,
+		},
+		{
+			name:         "chart archive",
+			ref:          HelmChartReference{URL: server.URL + "/charts/agent-1.0.0.tgz"},
+			allowedHosts: []string{"127.0.0.1"},
+		},
+		{
+			name:         "chart archive over plain http",
+			ref:          HelmChartReference{URL: ociServer.URL + "/charts/agent-1.0.0.tgz"},
+			allowedHosts: []string{"127.0.0.1"},
+			expectedErr:  true,
+		},
+		{
+			name:        "chart archive on a private address",
+			ref:         HelmChartReference{URL: server.URL + "/charts/agent-1.0.0.tgz"},
+			expectedErr: true,
+		},
+		{
+			name:         "chart archive on a host not allowed",
+			ref:          HelmChartReference{URL: server.URL + "/charts/agent-1.0.0.tgz"},
+			allowedHosts: []string{"charts.example.com"},
+			expectedErr:  true,
+		},
+		{
+			name:         "oci chart",
+			ref:          HelmChartReference{URL: "oci://" + ociHost + "/charts/agent", Version: "1.0.0"},
+			allowedHosts: []string{"127.0.0.1"},
+		},
+		{
+			name:         "oci chart not found",
+			ref:          HelmChartReference{URL: "oci://" + ociHost + "/charts/agent", Version: "2.0.0"},
+			allowedHosts: []string{"127.0.0.1"},
+			expectedErr:  true,
+		},
+		{
+			name:        "oci chart on a private address",
+			ref:         HelmChartReference{URL: "oci://" + ociHost + "/charts/agent", Version: "1.0.0"},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			l := newTestHelmChartLoader(server, c.allowedHosts...)
+			loaded, err := l.load(c.ref)
+			if (err != nil) != c.expectedErr {
+				t.Fatalf("expected error %v, but got %v", c.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if loaded.Name() != "agent" || loaded.Metadata.Version != "1.0.0" {
+				t.Errorf("unexpected chart %s:%s", loaded.Name(), loaded.Metadata.Version)
+			}
+			if _, ok := l.charts.Get(c.ref); !ok {
+				t.Errorf("expected the chart to be cached")
+			}
+		})
+	}
+}
+
+func TestHTTPHelmChartLoaderCache(t *testing.T) {
+	archive := newTestChartArchive(t)
+	var downloads int32
+	server := newTestRepoServer(t, archive, &downloads)
+
+	l := newTestHelmChartLoader(server, "127.0.0.1")
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	l.failures = utilcache.NewLRUExpireCacheWithClock(helmChartCacheSize, fakeClock)
+	l.backoff = flowcontrol.NewFakeBackOff(helmChartInitialBackoff, helmChartMaxBackoff, fakeClock)
+
+	// the chart is downloaded once for the concurrent loads
+	ref := HelmChartReference{URL: server.URL + "/charts/agent-1.0.0.tgz"}
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := l.load(ref); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if downloads := atomic.LoadInt32(&downloads); downloads != 1 {
+		t.Errorf("expected the chart downloaded once, but got %d", downloads)
+	}
+
+	// the failed download is not retried until the backoff expires
+	notFound := HelmChartReference{URL: server.URL + "/charts/agent-2.0.0.tgz"}
+	steps := []struct {
+		step              time.Duration
+		expectedDownloads int32
+	}{
+		{expectedDownloads: 2},
+		{step: helmChartInitialBackoff / 2, expectedDownloads: 2},
+		{step: helmChartInitialBackoff, expectedDownloads: 3},
+		// the backoff doubles after the second failure
+		{step: helmChartInitialBackoff + time.Second, expectedDownloads: 3},
+		{step: helmChartInitialBackoff, expectedDownloads: 4},
+	}
+	for _, step := range steps {
+		fakeClock.Step(step.step)
+		if _, err := l.load(notFound); err == nil {
+			t.Errorf("expected error when loading the chart not found")
+		}
+		if got := atomic.LoadInt32(&downloads); got != step.expectedDownloads {
+			t.Errorf("expected %d downloads after %v, but got %d", step.expectedDownloads, step.step, got)
+		}
+	}
+}
+
+func TestRenderHelmChart(t *testing.T) {
+	cluster := &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}}
+	presetValues := orderedValues{{name: "CLUSTER_NAME", value: "cluster1"}}
+
+	cases := []struct {
+		name          string
+		configValues  addonfactory.Values
+		expectedImage string
+		expectedErr   bool
+	}{
+		{
+			name:          "default values",
+			configValues:  addonfactory.Values{"CLUSTER_NAME": "cluster1"},
+			expectedImage: "quay.io/agent:v1",
+		},
+		{
+			name:          "customized values",
+			configValues:  addonfactory.Values{"CLUSTER_NAME": "cluster1", "image.tag": "v2"},
+			expectedImage: "quay.io/agent:v2",
+		},
+		{
+			name:         "values against schema",
+			configValues: addonfactory.Values{"image": "quay.io/agent:v2"},
+			expectedErr:  true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			a := &CRDTemplateAgentAddon{
+				addonName:       "test",
+				helmChartLoader: &fakeHelmChartLoader{chart: newTestChart()},
+			}
+			objects, err := a.renderHelmChart(cluster, "addon-ns", &HelmChartReference{}, presetValues, c.configValues)
+			if (err != nil) != c.expectedErr {
+				t.Fatalf("expected error %v, but got %v", c.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if len(objects) != 1 {
+				t.Fatalf("expected 1 object, but got %d", len(objects))
+			}
+
+			deployment := &appsv1.Deployment{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(
+				objects[0].(*unstructured.Unstructured).Object, deployment); err != nil {
+				t.Fatal(err)
+			}
+			if deployment.Name != "test-agent" || deployment.Namespace != "addon-ns" {
+				t.Errorf("unexpected deployment %s/%s", deployment.Namespace, deployment.Name)
+			}
+			container := deployment.Spec.Template.Spec.Containers[0]
+			if container.Image != c.expectedImage {
+				t.Errorf("expected image %s, but got %s", c.expectedImage, container.Image)
+			}
+			if container.Args[0] != "--cluster-name=cluster1" {
+				t.Errorf("unexpected args %v", container.Args)
+			}
+		})
+	}
+}
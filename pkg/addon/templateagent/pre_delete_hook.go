@@ -0,0 +1,102 @@
+package templateagent
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"open-cluster-management.io/addon-framework/pkg/addonmanager/constants"
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+const (
+	// PreDeleteHookTimeoutAnnotationKey is the annotation on the AddOnTemplate to set the max duration, e.g. "10m",
+	// the pre-delete hook of the addon could run. There is no timeout if it is not set.
+	PreDeleteHookTimeoutAnnotationKey = "addon.open-cluster-management.io/pre-delete-hook-timeout"
+
+	// PreDeleteHookFailurePolicyAnnotationKey is the annotation on the AddOnTemplate to set how the addon is deleted
+	// when its pre-delete hook fails or times out, the value is Fail or Ignore, and defaults to Fail.
+	PreDeleteHookFailurePolicyAnnotationKey = "addon.open-cluster-management.io/pre-delete-hook-failure-policy"
+
+	// PreDeleteHookFailurePolicyFail blocks the deletion of the addon until the pre-delete hook completes.
+	PreDeleteHookFailurePolicyFail = "Fail"
+	// PreDeleteHookFailurePolicyIgnore skips the pre-delete hook and continues the deletion of the addon.
+	PreDeleteHookFailurePolicyIgnore = "Ignore"
+)
+
+// skipPreDeleteHook returns true if the pre-delete hook of the deleting addon fails or times out, and the failure
+// policy of the template is Ignore. The hook objects are not returned by the manifests then, so the addon-framework
+// removes the pre-delete hook finalizer and the deletion of the addon continues.
+func (a *CRDTemplateAgentAddon) skipPreDeleteHook(
+	addon *addonapiv1alpha1.ManagedClusterAddOn, template *addonapiv1alpha1.AddOnTemplate) bool {
+	if addon.DeletionTimestamp.IsZero() || a.workLister == nil {
+		return false
+	}
+
+	workNamespace, workName := addon.Namespace, constants.PreDeleteHookWorkName(addon.Name)
+	if installMode, hostingCluster := constants.GetHostedModeInfo(addon.Annotations); installMode == constants.InstallModeHosted {
+		workNamespace, workName = hostingCluster, constants.PreDeleteHookHostingWorkName(addon.Namespace, addon.Name)
+	}
+	hookWork, err := a.workLister.ManifestWorks(workNamespace).Get(workName)
+	if err != nil {
+		// the hook is not deployed yet
+		return false
+	}
+
+	reason := ""
+	timeout, err := time.ParseDuration(template.Annotations[PreDeleteHookTimeoutAnnotationKey])
+	switch {
+	case hookWorkFailed(hookWork):
+		reason = "failed"
+	case err == nil && timeout > 0 && time.Since(hookWork.CreationTimestamp.Time) > timeout:
+		reason = "timed out"
+	default:
+		return false
+	}
+
+	if template.Annotations[PreDeleteHookFailurePolicyAnnotationKey] != PreDeleteHookFailurePolicyIgnore {
+		a.logger.Info("Pre-delete hook of addon is blocking the deletion",
+			"addonNamespace", addon.Namespace, "addonName", addon.Name, "hookWork", hookWork.Name, "reason", reason)
+		return false
+	}
+	a.logger.Info("Skip pre-delete hook of addon",
+		"addonNamespace", addon.Namespace, "addonName", addon.Name, "hookWork", hookWork.Name, "reason", reason)
+	return true
+}
+
+// hookWorkFailed returns true if any job in the hook work fails or any pod in the hook work is in the failed phase.
+func hookWorkFailed(hookWork *workapiv1.ManifestWork) bool {
+	for _, manifest := range hookWork.Status.ResourceStatus.Manifests {
+		for _, value := range manifest.StatusFeedbacks.Values {
+			if value.Value.String == nil {
+				continue
+			}
+			switch {
+			case manifest.ResourceMeta.Resource == "jobs" && value.Name == "JobFailed" && *value.Value.String == "True":
+				return true
+			case manifest.ResourceMeta.Resource == "pods" && value.Name == "PodPhase" && *value.Value.String == "Failed":
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// removePreDeleteHookObjects removes the pre-delete hook objects from the objects.
+func removePreDeleteHookObjects(objects []runtime.Object) []runtime.Object {
+	var rst []runtime.Object
+	for _, obj := range objects {
+		accessor, err := meta.Accessor(obj)
+		if err == nil {
+			_, hasLabel := accessor.GetLabels()[addonapiv1alpha1.AddonPreDeleteHookLabelKey]
+			_, hasAnnotation := accessor.GetAnnotations()[addonapiv1alpha1.AddonPreDeleteHookAnnotationKey]
+			if hasLabel || hasAnnotation {
+				continue
+			}
+		}
+		rst = append(rst, obj)
+	}
+	return rst
+}
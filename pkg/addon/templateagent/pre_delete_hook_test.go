@@ -0,0 +1,141 @@
+package templateagent
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"k8s.io/klog/v2/ktesting"
+
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	fakework "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+func newHookWork(age time.Duration, jobFailed string) *workapiv1.ManifestWork {
+	work := &workapiv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "addon-hello-pre-delete",
+			Namespace:         "cluster1",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+		},
+	}
+	work.Status.ResourceStatus.Manifests = []workapiv1.ManifestCondition{{
+		ResourceMeta: workapiv1.ManifestResourceMeta{Group: "batch", Resource: "jobs", Name: "hello-cleanup"},
+		StatusFeedbacks: workapiv1.StatusFeedbackResult{Values: []workapiv1.FeedbackValue{{
+			Name:  "JobFailed",
+			Value: workapiv1.FieldValue{Type: workapiv1.String, String: &jobFailed},
+		}}},
+	}}
+	return work
+}
+
+func TestSkipPreDeleteHook(t *testing.T) {
+	_, ctx := ktesting.NewTestContext(t)
+	now := metav1.Now()
+
+	cases := []struct {
+		name        string
+		deleting    bool
+		annotations map[string]string
+		hookWork    *workapiv1.ManifestWork
+		expected    bool
+	}{
+		{
+			name:        "addon is not deleting",
+			annotations: map[string]string{PreDeleteHookFailurePolicyAnnotationKey: PreDeleteHookFailurePolicyIgnore},
+			hookWork:    newHookWork(time.Hour, "True"),
+		},
+		{
+			name:        "hook is not deployed",
+			deleting:    true,
+			annotations: map[string]string{PreDeleteHookFailurePolicyAnnotationKey: PreDeleteHookFailurePolicyIgnore},
+		},
+		{
+			name:     "hook failed with the default failure policy",
+			deleting: true,
+			hookWork: newHookWork(time.Minute, "True"),
+		},
+		{
+			name:        "hook failed with the ignore failure policy",
+			deleting:    true,
+			annotations: map[string]string{PreDeleteHookFailurePolicyAnnotationKey: PreDeleteHookFailurePolicyIgnore},
+			hookWork:    newHookWork(time.Minute, "True"),
+			expected:    true,
+		},
+		{
+			name:     "hook is running",
+			deleting: true,
+			annotations: map[string]string{
+				PreDeleteHookFailurePolicyAnnotationKey: PreDeleteHookFailurePolicyIgnore,
+				PreDeleteHookTimeoutAnnotationKey:       "10m",
+			},
+			hookWork: newHookWork(time.Minute, "False"),
+		},
+		{
+			name:     "hook timed out",
+			deleting: true,
+			annotations: map[string]string{
+				PreDeleteHookFailurePolicyAnnotationKey: PreDeleteHookFailurePolicyIgnore,
+				PreDeleteHookTimeoutAnnotationKey:       "10m",
+			},
+			hookWork: newHookWork(time.Hour, "False"),
+			expected: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var works []runtime.Object
+			if c.hookWork != nil {
+				works = append(works, c.hookWork)
+			}
+			workInformers := workinformers.NewSharedInformerFactory(fakework.NewSimpleClientset(works...), 10*time.Minute)
+			for _, work := range works {
+				if err := workInformers.Work().V1().ManifestWorks().Informer().GetStore().Add(work); err != nil {
+					t.Fatal(err)
+				}
+			}
+			a := &CRDTemplateAgentAddon{logger: klog.FromContext(ctx)}
+			a.WithWorkLister(workInformers.Work().V1().ManifestWorks().Lister())
+
+			addon := &addonapiv1alpha1.ManagedClusterAddOn{
+				ObjectMeta: metav1.ObjectMeta{Name: "hello", Namespace: "cluster1"},
+			}
+			if c.deleting {
+				addon.DeletionTimestamp = &now
+			}
+			template := &addonapiv1alpha1.AddOnTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "hello-template", Annotations: c.annotations},
+			}
+
+			if actual := a.skipPreDeleteHook(addon, template); actual != c.expected {
+				t.Errorf("expected %v, but got %v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRemovePreDeleteHookObjects(t *testing.T) {
+	newObject := func(apiVersion, kind, name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetNamespace("default")
+		obj.SetName(name)
+		return obj
+	}
+	hookByAnnotation := newObject("batch/v1", "Job", "hook-by-annotation")
+	hookByAnnotation.SetAnnotations(map[string]string{addonapiv1alpha1.AddonPreDeleteHookAnnotationKey: ""})
+	hookByLabel := newObject("v1", "Pod", "hook-by-label")
+	hookByLabel.SetLabels(map[string]string{addonapiv1alpha1.AddonPreDeleteHookLabelKey: ""})
+
+	rst := removePreDeleteHookObjects([]runtime.Object{hookByAnnotation, hookByLabel, newObject("apps/v1", "Deployment", "agent")})
+	if len(rst) != 1 || rst[0].(*unstructured.Unstructured).GetName() != "agent" {
+		t.Errorf("expected only the agent deployment, but got %v", rst)
+	}
+}
@@ -24,6 +24,8 @@ import (
 	"open-cluster-management.io/addon-framework/pkg/utils"
 	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/addon/controllers/metrics"
 )
 
 const (
@@ -237,7 +239,12 @@ func (a *CRDTemplateAgentAddon) TemplateCSRSignFunc() agent.CSRSignerFunc {
 					continue
 				}
 				if csr.Spec.SignerName == registration.CustomSigner.SignerName {
-					return CustomSignerWithExpiry(a.hubKubeClient, registration.CustomSigner, 24*time.Hour)(csr)
+					cert := CustomSignerWithExpiry(a.hubKubeClient, registration.CustomSigner, 24*time.Hour)(csr)
+					if len(cert) > 0 {
+						metrics.CSRSignLatency.WithLabelValues(a.addonName).Observe(
+							time.Since(csr.CreationTimestamp.Time).Seconds())
+					}
+					return cert
 				}
 
 			default:
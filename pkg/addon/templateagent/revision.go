@@ -0,0 +1,112 @@
+package templateagent
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"open-cluster-management.io/addon-framework/pkg/utils"
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+)
+
+const (
+	// TemplateRevisionLabelKey is the label on the ConfigMaps which keep the revisions of an AddOnTemplate, the value
+	// is the name of the AddOnTemplate.
+	TemplateRevisionLabelKey = "addon.open-cluster-management.io/template-revision-of"
+
+	// TemplateRevisionSpecHashAnnotationKey is the annotation on the revision ConfigMaps recording the spec hash of
+	// the AddOnTemplate revision.
+	TemplateRevisionSpecHashAnnotationKey = "addon.open-cluster-management.io/template-spec-hash"
+
+	// DefaultTemplateRevisionNamespace is the namespace of the revision ConfigMaps if the namespace of the
+	// addon-manager is unknown.
+	DefaultTemplateRevisionNamespace = "open-cluster-management-hub"
+
+	templateRevisionDataKey = "spec"
+	templateRevisionPrefix  = "addon-template-"
+	templateRevisionHashLen = 16
+)
+
+// GetTemplateSpecHash returns the spec hash of the AddOnTemplate, it is the same as the spec hash recorded in the
+// config references of the addons.
+func GetTemplateSpecHash(template *addonapiv1alpha1.AddOnTemplate) (string, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(template)
+	if err != nil {
+		return "", err
+	}
+	// the null fields are pruned by the apiserver, so they are not in the object which the spec hash in the config
+	// references is computed from.
+	pruneNulls(obj)
+	return utils.GetSpecHash(&unstructured.Unstructured{Object: obj})
+}
+
+func pruneNulls(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if field == nil {
+				delete(v, key)
+				continue
+			}
+			pruneNulls(field)
+		}
+	case []interface{}:
+		for _, item := range v {
+			pruneNulls(item)
+		}
+	}
+}
+
+// TemplateRevisionName returns the name of the ConfigMap keeping the revision of the AddOnTemplate with the spec hash.
+func TemplateRevisionName(templateName, specHash string) string {
+	if len(specHash) > templateRevisionHashLen {
+		specHash = specHash[:templateRevisionHashLen]
+	}
+	// the name of a ConfigMap is at most 253 characters
+	maxNameLen := 253 - len(templateRevisionPrefix) - len(specHash) - 1
+	if len(templateName) > maxNameLen {
+		templateName = templateName[:maxNameLen]
+	}
+	return fmt.Sprintf("%s%s-%s", templateRevisionPrefix, templateName, specHash)
+}
+
+// NewTemplateRevision returns the ConfigMap keeping the current revision of the AddOnTemplate.
+func NewTemplateRevision(template *addonapiv1alpha1.AddOnTemplate, namespace, specHash string) (*corev1.ConfigMap, error) {
+	data, err := json.Marshal(template.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TemplateRevisionName(template.Name, specHash),
+			Namespace: namespace,
+			Labels: map[string]string{
+				TemplateRevisionLabelKey: template.Name,
+			},
+			Annotations: map[string]string{
+				TemplateRevisionSpecHashAnnotationKey: specHash,
+			},
+		},
+		Data: map[string]string{
+			templateRevisionDataKey: string(data),
+		},
+	}, nil
+}
+
+// TemplateFromRevision returns the AddOnTemplate kept in the revision ConfigMap.
+func TemplateFromRevision(revision *corev1.ConfigMap) (*addonapiv1alpha1.AddOnTemplate, error) {
+	template := &addonapiv1alpha1.AddOnTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: revision.Labels[TemplateRevisionLabelKey],
+		},
+	}
+	if err := json.Unmarshal([]byte(revision.Data[templateRevisionDataKey]), &template.Spec); err != nil {
+		return nil, fmt.Errorf("failed to decode the addon template revision %s/%s: %v",
+			revision.Namespace, revision.Name, err)
+	}
+	return template, nil
+}
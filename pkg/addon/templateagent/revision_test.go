@@ -0,0 +1,166 @@
+package templateagent
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2/ktesting"
+
+	"open-cluster-management.io/addon-framework/pkg/utils"
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	fakeaddon "open-cluster-management.io/api/client/addon/clientset/versioned/fake"
+	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+func newRevisionTestTemplate(image string) *addonapiv1alpha1.AddOnTemplate {
+	return &addonapiv1alpha1.AddOnTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-template"},
+		Spec: addonapiv1alpha1.AddOnTemplateSpec{
+			AddonName: "hello",
+			AgentSpec: workapiv1.ManifestWorkSpec{
+				Workload: workapiv1.ManifestsTemplate{
+					Manifests: []workapiv1.Manifest{{RawExtension: runtime.RawExtension{
+						Raw: []byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"hello","namespace":"default"},` +
+							`"spec":{"containers":[{"name":"hello","image":"` + image + `"}]}}`),
+					}}},
+				},
+			},
+		},
+	}
+}
+
+func TestGetTemplateSpecHash(t *testing.T) {
+	template := newRevisionTestTemplate("hello:v1")
+	specHash, err := GetTemplateSpecHash(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the spec hash should be the same as the one computed from the object read from the apiserver, in which
+	// the null fields are pruned
+	data := []byte(`{"apiVersion":"addon.open-cluster-management.io/v1alpha1","kind":"AddOnTemplate",` +
+		`"metadata":{"name":"hello-template"},"spec":{"addonName":"hello","agentSpec":{"workload":{"manifests":[` +
+		`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"hello","namespace":"default"},` +
+		`"spec":{"containers":[{"name":"hello","image":"hello:v1"}]}}]}}}}`)
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	expected, err := utils.GetSpecHash(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if specHash != expected {
+		t.Errorf("expected spec hash %s, but got %s", expected, specHash)
+	}
+}
+
+func TestTemplateRevision(t *testing.T) {
+	template := newRevisionTestTemplate("hello:v1")
+	revision, err := NewTemplateRevision(template, "open-cluster-management-hub", "0123456789abcdef0123456789abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revision.Name != "addon-template-hello-template-0123456789abcdef" {
+		t.Errorf("unexpected revision name %s", revision.Name)
+	}
+
+	actual, err := TemplateFromRevision(revision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual.Name != template.Name || !equality.Semantic.DeepEqual(actual.Spec, template.Spec) {
+		t.Errorf("expected template %v, but got %v", template, actual)
+	}
+
+	name := TemplateRevisionName(strings.Repeat("a", 300), "0123456789abcdef0123456789abcdef")
+	if len(name) != 253 {
+		t.Errorf("expected the revision name to be truncated to 253 characters, but got %d", len(name))
+	}
+}
+
+func TestGetDesiredAddOnTemplateRevision(t *testing.T) {
+	_, ctx := ktesting.NewTestContext(t)
+
+	previous := newRevisionTestTemplate("hello:v1")
+	previousHash, err := GetTemplateSpecHash(previous)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current := newRevisionTestTemplate("hello:v2")
+	currentHash, err := GetTemplateSpecHash(current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revision, err := NewTemplateRevision(previous, "test-ns", previousHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name          string
+		specHash      string
+		expectedImage string
+	}{
+		{
+			name:          "current revision",
+			specHash:      currentHash,
+			expectedImage: "hello:v2",
+		},
+		{
+			name:          "previous revision",
+			specHash:      previousHash,
+			expectedImage: "hello:v1",
+		},
+		{
+			name:          "revision not found",
+			specHash:      "unknown",
+			expectedImage: "hello:v2",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hubKubeClient := fakekube.NewSimpleClientset(revision)
+			addonClient := fakeaddon.NewSimpleClientset()
+			addonInformerFactory := addoninformers.NewSharedInformerFactory(addonClient, 30*time.Minute)
+			if err := addonInformerFactory.Addon().V1alpha1().AddOnTemplates().Informer().GetStore().Add(current); err != nil {
+				t.Fatal(err)
+			}
+
+			agentAddon := NewCRDTemplateAgentAddon(ctx, "hello", "test-agent", hubKubeClient, addonClient,
+				addonInformerFactory, nil).WithTemplateRevisionNamespace("test-ns")
+
+			addon := &addonapiv1alpha1.ManagedClusterAddOn{
+				ObjectMeta: metav1.ObjectMeta{Name: "hello", Namespace: "cluster1"},
+			}
+			addon.Status.ConfigReferences = []addonapiv1alpha1.ConfigReference{{
+				ConfigGroupResource: addonapiv1alpha1.ConfigGroupResource{
+					Group:    utils.AddOnTemplateGVR.Group,
+					Resource: utils.AddOnTemplateGVR.Resource,
+				},
+				ConfigReferent: addonapiv1alpha1.ConfigReferent{Name: "hello-template"},
+				DesiredConfig: &addonapiv1alpha1.ConfigSpecHash{
+					ConfigReferent: addonapiv1alpha1.ConfigReferent{Name: "hello-template"},
+					SpecHash:       c.specHash,
+				},
+			}}
+
+			template, err := agentAddon.GetDesiredAddOnTemplateByAddon(addon)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(template.Spec.AgentSpec.Workload.Manifests[0].Raw), c.expectedImage) {
+				t.Errorf("expected the template with image %s, but got %s",
+					c.expectedImage, string(template.Spec.AgentSpec.Workload.Manifests[0].Raw))
+			}
+		})
+	}
+}
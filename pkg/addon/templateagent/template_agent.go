@@ -3,8 +3,11 @@ package templateagent
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/valyala/fasttemplate"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -13,18 +16,24 @@ import (
 	"k8s.io/klog/v2"
 
 	"open-cluster-management.io/addon-framework/pkg/addonfactory"
+	"open-cluster-management.io/addon-framework/pkg/addonmanager/constants"
 	"open-cluster-management.io/addon-framework/pkg/agent"
 	"open-cluster-management.io/addon-framework/pkg/utils"
 	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 	addonv1alpha1client "open-cluster-management.io/api/client/addon/clientset/versioned"
 	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
 	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
+	worklister "open-cluster-management.io/api/client/work/listers/work/v1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/addon/controllers/metrics"
+	"open-cluster-management.io/ocm/pkg/common/helpers"
 )
 
 const (
-	NodePlacementPrivateValueKey = "__NODE_PLACEMENT"
-	RegistriesPrivateValueKey    = "__REGISTRIES"
+	NodePlacementPrivateValueKey     = "__NODE_PLACEMENT"
+	RegistriesPrivateValueKey        = "__REGISTRIES"
+	WorkloadOverridesPrivateValueKey = "__WORKLOAD_OVERRIDES"
 )
 
 // templateBuiltinValues includes the built-in values for crd template agentAddon.
@@ -57,6 +66,18 @@ type CRDTemplateAgentAddon struct {
 	rolebindingLister   rbacv1lister.RoleBindingLister
 	addonName           string
 	agentName           string
+
+	// healthProbes caches the compiled health probes of the addon templates, keyed by the template name
+	healthProbesLock sync.Mutex
+	healthProbes     map[string]*compiledHealthProbes
+
+	helmChartLoader helmChartLoader
+
+	// templateRevisionNamespace is the namespace of the ConfigMaps keeping the revisions of the addon templates
+	templateRevisionNamespace string
+
+	// workLister is used to get the status of the pre-delete hook works
+	workLister worklister.ManifestWorkLister
 }
 
 // NewCRDTemplateAgentAddon creates a CRDTemplateAgentAddon instance
@@ -82,8 +103,33 @@ func NewCRDTemplateAgentAddon(
 		rolebindingLister:   rolebindingLister,
 		addonName:           addonName,
 		agentName:           agentName,
+		healthProbes:        map[string]*compiledHealthProbes{},
+		helmChartLoader:     newHTTPHelmChartLoader(HelmChartOptions{}),
+
+		templateRevisionNamespace: DefaultTemplateRevisionNamespace,
+	}
+
+	return a
+}
+
+// WithTemplateRevisionNamespace sets the namespace of the ConfigMaps keeping the revisions of the addon templates.
+func (a *CRDTemplateAgentAddon) WithTemplateRevisionNamespace(namespace string) *CRDTemplateAgentAddon {
+	if len(namespace) > 0 {
+		a.templateRevisionNamespace = namespace
 	}
+	return a
+}
 
+// WithHelmChartOptions sets where the helm charts referenced by the addon templates could be downloaded from.
+func (a *CRDTemplateAgentAddon) WithHelmChartOptions(options HelmChartOptions) *CRDTemplateAgentAddon {
+	a.helmChartLoader = newHTTPHelmChartLoader(options)
+	return a
+}
+
+// WithWorkLister sets the lister of the addon works, it is required to apply the timeout and failure policy of the
+// pre-delete hooks.
+func (a *CRDTemplateAgentAddon) WithWorkLister(workLister worklister.ManifestWorkLister) *CRDTemplateAgentAddon {
+	a.workLister = workLister
 	return a
 }
 
@@ -98,7 +144,15 @@ func (a *CRDTemplateAgentAddon) Manifests(
 	if template == nil {
 		return nil, fmt.Errorf("addon %s/%s template not found in status", addon.Namespace, addon.Name)
 	}
-	return a.renderObjects(cluster, addon, template)
+	objects, err := a.renderObjects(cluster, addon, template)
+	if err != nil {
+		metrics.TemplateRenderFailures.WithLabelValues(a.addonName).Inc()
+		return nil, err
+	}
+	if a.skipPreDeleteHook(addon, template) {
+		objects = removePreDeleteHookObjects(objects)
+	}
+	return objects, nil
 }
 
 func (a *CRDTemplateAgentAddon) GetAgentAddonOptions() agent.AgentAddonOptions {
@@ -108,18 +162,19 @@ func (a *CRDTemplateAgentAddon) GetAgentAddonOptions() agent.AgentAddonOptions {
 		supportedConfigGVRs = append(supportedConfigGVRs, gvr)
 	}
 	return agent.AgentAddonOptions{
-		AddonName:       a.addonName,
-		InstallStrategy: nil,
-		HealthProber: &agent.HealthProber{
-			Type: agent.HealthProberTypeDeploymentAvailability,
-		},
+		AddonName:           a.addonName,
+		InstallStrategy:     nil,
+		HealthProber:        a.healthProber(),
 		SupportedConfigGVRs: supportedConfigGVRs,
+		// the manifests with the hosted manifest location annotation "hosting" are deployed on the hosting
+		// cluster if the addon is in Hosted mode, all the manifests are deployed on the managed cluster otherwise.
+		HostedModeEnabled: true,
 		Registration: &agent.RegistrationOption{
 			CSRConfigurations: a.TemplateCSRConfigurationsFunc(),
 			PermissionConfig:  a.TemplatePermissionConfigFunc(),
 			CSRApproveCheck:   a.TemplateCSRApproveCheckFunc(),
 			CSRSign:           a.TemplateCSRSignFunc(),
-			AgentInstallNamespace: utils.AgentInstallNamespaceFromDeploymentConfigFunc(
+			AgentInstallNamespace: AgentInstallNamespaceFromMergedDeploymentConfigFunc(
 				utils.NewAddOnDeploymentConfigGetter(a.addonClient)),
 		},
 		AgentDeployTriggerClusterFilter: utils.ClusterImageRegistriesAnnotationChanged,
@@ -131,7 +186,11 @@ func (a *CRDTemplateAgentAddon) renderObjects(
 	addon *addonapiv1alpha1.ManagedClusterAddOn,
 	template *addonapiv1alpha1.AddOnTemplate) ([]runtime.Object, error) {
 	var objects []runtime.Object
-	presetValues, configValues, privateValues, err := a.getValues(cluster, addon, template)
+	installNamespace, err := a.agentInstallNamespace(addon)
+	if err != nil {
+		return objects, err
+	}
+	presetValues, configValues, privateValues, err := a.getValues(cluster, addon, installNamespace, template)
 	if err != nil {
 		return objects, err
 	}
@@ -154,23 +213,105 @@ func (a *CRDTemplateAgentAddon) renderObjects(
 		objects = append(objects, object)
 	}
 
-	objects, err = a.decorateObjects(template, objects, presetValues, configValues, privateValues)
+	helmChart, err := getHelmChartReference(template)
+	if err != nil {
+		return objects, err
+	}
+	if helmChart != nil {
+		helmObjects, err := a.renderHelmChart(cluster, installNamespace, helmChart, presetValues, configValues)
+		if err != nil {
+			return objects, err
+		}
+		objects = append(objects, helmObjects...)
+	}
+
+	if err := setRBACNamespace(objects, installNamespace); err != nil {
+		return objects, err
+	}
+
+	objects, err = a.decorateObjects(addon, template, objects, presetValues, configValues, privateValues)
 	if err != nil {
 		return objects, err
 	}
 	return objects, nil
 }
 
+// agentInstallNamespace returns the namespace to install the addon agent on the managed cluster. The namespace set
+// in the AddOnDeploymentConfigs of the addon takes precedence over the install namespace in the addon spec, so the
+// agent could be installed in a different namespace per cluster.
+func (a *CRDTemplateAgentAddon) agentInstallNamespace(addon *addonapiv1alpha1.ManagedClusterAddOn) (string, error) {
+	spec, err := helpers.GetEffectiveAddOnDeploymentConfig(
+		context.TODO(), addon, utils.NewAddOnDeploymentConfigGetter(a.addonClient))
+	if err != nil {
+		return "", fmt.Errorf("failed to get the install namespace of addon %s/%s: %v", addon.Namespace, addon.Name, err)
+	}
+	if spec != nil && len(spec.AgentInstallNamespace) > 0 {
+		return spec.AgentInstallNamespace, nil
+	}
+	if len(addon.Spec.InstallNamespace) > 0 {
+		return addon.Spec.InstallNamespace, nil
+	}
+	return addonfactory.AddonDefaultInstallNamespace, nil
+}
+
+// setRBACNamespace sets the install namespace to the namespace-scoped RBAC objects and the service account subjects
+// of the bindings whose namespace is not set in the template, so the agent permissions follow the install namespace.
+func setRBACNamespace(objects []runtime.Object, namespace string) error {
+	for _, obj := range objects {
+		object, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+
+		gvk := object.GroupVersionKind()
+		switch {
+		case gvk.Group == "" && gvk.Kind == "ServiceAccount",
+			gvk.Group == rbacv1.GroupName && (gvk.Kind == "Role" || gvk.Kind == "RoleBinding"):
+			if len(object.GetNamespace()) == 0 {
+				object.SetNamespace(namespace)
+			}
+		}
+
+		if gvk.Group != rbacv1.GroupName || (gvk.Kind != "RoleBinding" && gvk.Kind != "ClusterRoleBinding") {
+			continue
+		}
+		subjects, found, err := unstructured.NestedSlice(object.Object, "subjects")
+		if err != nil {
+			return fmt.Errorf("invalid subjects of %s %s: %v", gvk.Kind, object.GetName(), err)
+		}
+		if !found {
+			continue
+		}
+		for _, s := range subjects {
+			subject, ok := s.(map[string]interface{})
+			if !ok || subject["kind"] != rbacv1.ServiceAccountKind {
+				continue
+			}
+			if ns, _ := subject["namespace"].(string); len(ns) == 0 {
+				subject["namespace"] = namespace
+			}
+		}
+		if err := unstructured.SetNestedSlice(object.Object, subjects, "subjects"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (a *CRDTemplateAgentAddon) decorateObjects(
+	addon *addonapiv1alpha1.ManagedClusterAddOn,
 	template *addonapiv1alpha1.AddOnTemplate,
 	objects []runtime.Object,
 	orderedValues orderedValues,
 	configValues, privateValues addonfactory.Values) ([]runtime.Object, error) {
+	installMode, _ := constants.GetHostedModeInfo(addon.GetAnnotations())
 	decorators := []deploymentDecorator{
 		newEnvironmentDecorator(orderedValues),
 		newVolumeDecorator(a.addonName, template),
 		newNodePlacementDecorator(privateValues),
 		newImageDecorator(privateValues),
+		newWorkloadOverridesDecorator(privateValues),
+		newManagedKubeconfigDecorator(a.addonName, installMode == constants.InstallModeHosted),
 	}
 	for index, obj := range objects {
 		deployment, err := utils.ConvertToDeployment(obj)
@@ -210,5 +351,36 @@ func (a *CRDTemplateAgentAddon) GetDesiredAddOnTemplateByAddon(
 		return nil, err
 	}
 
-	return template.DeepCopy(), nil
+	return a.getTemplateRevision(template, desiredTemplate.SpecHash), nil
+}
+
+// getTemplateRevision returns the revision of the addon template with the spec hash. The addon template may be
+// changed before the change is rolled out to the addon, in that case, the revision kept in the ConfigMap is
+// returned, so the addon is not upgraded until it is in the rollout. The current template is returned if the
+// revision is not found.
+func (a *CRDTemplateAgentAddon) getTemplateRevision(
+	template *addonapiv1alpha1.AddOnTemplate, specHash string) *addonapiv1alpha1.AddOnTemplate {
+	if a.hubKubeClient == nil {
+		return template.DeepCopy()
+	}
+	currentSpecHash, err := GetTemplateSpecHash(template)
+	if err != nil || currentSpecHash == specHash {
+		return template.DeepCopy()
+	}
+
+	revision, err := a.hubKubeClient.CoreV1().ConfigMaps(a.templateRevisionNamespace).Get(
+		context.TODO(), TemplateRevisionName(template.Name, specHash), metav1.GetOptions{})
+	if err != nil || revision.Annotations[TemplateRevisionSpecHashAnnotationKey] != specHash {
+		a.logger.Info("Addon template revision is not found, use the current template",
+			"addonName", a.addonName, "addonTemplate", template.Name, "specHash", specHash)
+		return template.DeepCopy()
+	}
+
+	revisionTemplate, err := TemplateFromRevision(revision)
+	if err != nil {
+		a.logger.Error(err, "Invalid addon template revision, use the current template",
+			"addonName", a.addonName, "addonTemplate", template.Name, "specHash", specHash)
+		return template.DeepCopy()
+	}
+	return revisionTemplate
 }
@@ -11,6 +11,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	kubeinformers "k8s.io/client-go/informers"
@@ -25,6 +26,7 @@ import (
 	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	clusterv1apha1 "open-cluster-management.io/api/cluster/v1alpha1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
 )
 
 func TestAddonTemplateAgentManifests(t *testing.T) {
@@ -167,7 +169,7 @@ func TestAddonTemplateAgentManifests(t *testing.T) {
 					{Name: "LOG_LEVEL", Value: "4"},
 					{Name: "HUB_KUBECONFIG", Value: "/managed/hub-kubeconfig/kubeconfig"},
 					{Name: "CLUSTER_NAME", Value: clusterName},
-					{Name: "INSTALL_NAMESPACE", Value: "open-cluster-management-agent-addon"},
+					{Name: "INSTALL_NAMESPACE", Value: "test-install-namespace"},
 				}
 				if !equality.Semantic.DeepEqual(envs, expectedEnvs) {
 					t.Errorf("unexpected envs %v", envs)
@@ -385,6 +387,212 @@ func TestAgentInstallNamespace(t *testing.T) {
 	}
 }
 
+func TestRenderAgentInstallNamespace(t *testing.T) {
+	_, ctx := ktesting.NewTestContext(t)
+
+	template := &addonapiv1alpha1.AddOnTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-template"},
+		Spec: addonapiv1alpha1.AddOnTemplateSpec{
+			AddonName: "hello",
+			AgentSpec: workapiv1.ManifestWorkSpec{
+				Workload: workapiv1.ManifestsTemplate{
+					Manifests: []workapiv1.Manifest{
+						{RawExtension: runtime.RawExtension{Raw: []byte(
+							`{"apiVersion":"v1","kind":"ServiceAccount","metadata":{"name":"hello-sa"}}`)}},
+						{RawExtension: runtime.RawExtension{Raw: []byte(
+							`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"RoleBinding",` +
+								`"metadata":{"name":"hello"},"roleRef":{"apiGroup":"rbac.authorization.k8s.io",` +
+								`"kind":"Role","name":"hello"},"subjects":[{"kind":"ServiceAccount","name":"hello-sa"}]}`)}},
+						{RawExtension: runtime.RawExtension{Raw: []byte(
+							`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRoleBinding",` +
+								`"metadata":{"name":"hello"},"roleRef":{"apiGroup":"rbac.authorization.k8s.io",` +
+								`"kind":"ClusterRole","name":"hello"},"subjects":[` +
+								`{"kind":"ServiceAccount","name":"hello-sa"},` +
+								`{"kind":"ServiceAccount","name":"other-sa","namespace":"other"}]}`)}},
+						{RawExtension: runtime.RawExtension{Raw: []byte(
+							`{"apiVersion":"v1","kind":"ConfigMap",` +
+								`"metadata":{"name":"hello","namespace":"{{INSTALL_NAMESPACE}}"}}`)}},
+					},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		name                  string
+		installNamespace      string
+		addonDeploymentConfig *addonapiv1alpha1.AddOnDeploymentConfig
+		expected              string
+	}{
+		{
+			name:     "default install namespace",
+			expected: "open-cluster-management-agent-addon",
+		},
+		{
+			name:             "install namespace in addon spec",
+			installNamespace: "addon-ns",
+			expected:         "addon-ns",
+		},
+		{
+			name:             "install namespace in addon deployment config",
+			installNamespace: "addon-ns",
+			addonDeploymentConfig: &addonapiv1alpha1.AddOnDeploymentConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "hello-config", Namespace: "cluster1"},
+				Spec:       addonapiv1alpha1.AddOnDeploymentConfigSpec{AgentInstallNamespace: "cluster1-ns"},
+			},
+			expected: "cluster1-ns",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			builder := newManagedClusterAddonBuilder(&addonapiv1alpha1.ManagedClusterAddOn{
+				ObjectMeta: metav1.ObjectMeta{Name: "hello", Namespace: "cluster1"},
+				Spec:       addonapiv1alpha1.ManagedClusterAddOnSpec{InstallNamespace: c.installNamespace},
+			}).withAddonTemplate(template)
+			objs := []runtime.Object{template}
+			if c.addonDeploymentConfig != nil {
+				builder = builder.withAddonDeploymentConfig(c.addonDeploymentConfig)
+				objs = append(objs, c.addonDeploymentConfig)
+			}
+			addon := builder.build()
+
+			addonClient := fakeaddon.NewSimpleClientset(objs...)
+			addonInformerFactory := addoninformers.NewSharedInformerFactory(addonClient, 30*time.Minute)
+			if err := addonInformerFactory.Addon().V1alpha1().AddOnTemplates().Informer().GetStore().Add(template); err != nil {
+				t.Fatal(err)
+			}
+			agentAddon := NewCRDTemplateAgentAddon(ctx, "hello", "test-agent", nil, addonClient, addonInformerFactory, nil)
+
+			objects, err := agentAddon.Manifests(addonfactory.NewFakeManagedCluster("cluster1", "1.10.1"), addon)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(objects) != 4 {
+				t.Fatalf("expected 4 objects, but got %d", len(objects))
+			}
+			for _, obj := range objects {
+				object := obj.(*unstructured.Unstructured)
+				if object.GetKind() != "ClusterRoleBinding" && object.GetNamespace() != c.expected {
+					t.Errorf("expected %s %s in namespace %s, but got %s",
+						object.GetKind(), object.GetName(), c.expected, object.GetNamespace())
+				}
+				subjects, _, _ := unstructured.NestedSlice(object.Object, "subjects")
+				for _, s := range subjects {
+					subject := s.(map[string]interface{})
+					expected := c.expected
+					if subject["name"] == "other-sa" {
+						expected = "other"
+					}
+					if subject["namespace"] != expected {
+						t.Errorf("expected subject %s of %s in namespace %s, but got %v",
+							subject["name"], object.GetKind(), expected, subject["namespace"])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestHostedModeManifests(t *testing.T) {
+	_, ctx := ktesting.NewTestContext(t)
+
+	template := &addonapiv1alpha1.AddOnTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-template"},
+		Spec: addonapiv1alpha1.AddOnTemplateSpec{
+			AddonName: "hello",
+			AgentSpec: workapiv1.ManifestWorkSpec{
+				Workload: workapiv1.ManifestsTemplate{
+					Manifests: []workapiv1.Manifest{
+						{RawExtension: runtime.RawExtension{Raw: []byte(
+							`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"hello-agent",` +
+								`"namespace":"{{INSTALL_NAMESPACE}}","annotations":` +
+								`{"addon.open-cluster-management.io/hosted-manifest-location":"hosting"}},` +
+								`"spec":{"template":{"spec":{"containers":[{"name":"hello","image":"hello:v1"}]}}}}`)}},
+						{RawExtension: runtime.RawExtension{Raw: []byte(
+							`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"hello-managed",` +
+								`"namespace":"{{INSTALL_NAMESPACE}}"},` +
+								`"spec":{"template":{"spec":{"containers":[{"name":"hello","image":"hello:v1"}]}}}}`)}},
+					},
+				},
+			},
+			Registration: []addonapiv1alpha1.RegistrationSpec{{Type: addonapiv1alpha1.RegistrationTypeKubeClient}},
+		},
+	}
+
+	cases := []struct {
+		name                    string
+		annotations             map[string]string
+		expectedManagedVolume   bool
+		expectedManagedKubePath string
+	}{
+		{
+			name: "default mode",
+		},
+		{
+			name:                    "hosted mode",
+			annotations:             map[string]string{addonapiv1alpha1.HostingClusterNameAnnotationKey: "hosting"},
+			expectedManagedVolume:   true,
+			expectedManagedKubePath: "/managed/managed-kubeconfig/kubeconfig",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			addon := newManagedClusterAddonBuilder(&addonapiv1alpha1.ManagedClusterAddOn{
+				ObjectMeta: metav1.ObjectMeta{Name: "hello", Namespace: "cluster1", Annotations: c.annotations},
+			}).withAddonTemplate(template).build()
+
+			addonClient := fakeaddon.NewSimpleClientset(template)
+			addonInformerFactory := addoninformers.NewSharedInformerFactory(addonClient, 30*time.Minute)
+			if err := addonInformerFactory.Addon().V1alpha1().AddOnTemplates().Informer().GetStore().Add(template); err != nil {
+				t.Fatal(err)
+			}
+			agentAddon := NewCRDTemplateAgentAddon(ctx, "hello", "test-agent", nil, addonClient, addonInformerFactory, nil)
+			if !agentAddon.GetAgentAddonOptions().HostedModeEnabled {
+				t.Errorf("expected hosted mode enabled")
+			}
+
+			objects, err := agentAddon.Manifests(addonfactory.NewFakeManagedCluster("cluster1", "1.10.1"), addon)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(objects) != 2 {
+				t.Fatalf("expected 2 objects, but got %d", len(objects))
+			}
+
+			for _, obj := range objects {
+				deployment, ok := obj.(*appsv1.Deployment)
+				if !ok {
+					t.Fatalf("expected object to be *appsv1.Deployment, but got %T", obj)
+				}
+				hasManagedVolume := false
+				for _, volume := range deployment.Spec.Template.Spec.Volumes {
+					if volume.Secret != nil && volume.Secret.SecretName == "hello-managed-kubeconfig" {
+						hasManagedVolume = true
+					}
+				}
+				expectedManagedVolume := c.expectedManagedVolume && deployment.Name == "hello-agent"
+				if hasManagedVolume != expectedManagedVolume {
+					t.Errorf("expected managed kubeconfig volume %v in deployment %s, but got %v",
+						expectedManagedVolume, deployment.Name, hasManagedVolume)
+				}
+
+				managedKubePath := ""
+				for _, env := range deployment.Spec.Template.Spec.Containers[0].Env {
+					if env.Name == "MANAGED_KUBECONFIG" {
+						managedKubePath = env.Value
+					}
+				}
+				if managedKubePath != c.expectedManagedKubePath {
+					t.Errorf("expected MANAGED_KUBECONFIG %q in deployment %s, but got %q",
+						c.expectedManagedKubePath, deployment.Name, managedKubePath)
+				}
+			}
+		})
+	}
+}
+
 type testManagedClusterAddOnBuilder struct {
 	managedClusterAddOn   *addonapiv1alpha1.ManagedClusterAddOn
 	addonTemplate         *addonapiv1alpha1.AddOnTemplate
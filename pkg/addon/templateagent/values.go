@@ -1,15 +1,21 @@
 package templateagent
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"sort"
 
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/klog/v2"
 
 	"open-cluster-management.io/addon-framework/pkg/addonfactory"
+	"open-cluster-management.io/addon-framework/pkg/addonmanager/constants"
+	"open-cluster-management.io/addon-framework/pkg/utils"
 	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/helpers"
 )
 
 // ToAddOnNodePlacementPrivateValues only transform the AddOnDeploymentConfig NodePlacement part into Values object
@@ -36,6 +42,70 @@ func ToAddOnRegistriesPrivateValues(config addonapiv1alpha1.AddOnDeploymentConfi
 	}, nil
 }
 
+// GetMergedAddOnDeploymentConfigValues merges all the AddOnDeploymentConfigs of the addon at first, and then uses
+// the toValuesFuncs to transform the merged AddOnDeploymentConfig to Values object. Unlike the
+// addonfactory.GetAddOnDeploymentConfigValues, the customized variables, node placement, registries and proxy
+// config of the AddOnDeploymentConfigs are deep merged.
+func GetMergedAddOnDeploymentConfigValues(
+	getter utils.AddOnDeploymentConfigGetter,
+	toValuesFuncs ...addonfactory.AddOnDeploymentConfigToValuesFunc) addonfactory.GetValuesFunc {
+	return func(cluster *clusterv1.ManagedCluster, addon *addonapiv1alpha1.ManagedClusterAddOn) (addonfactory.Values, error) {
+		spec, err := helpers.GetEffectiveAddOnDeploymentConfig(context.Background(), addon, getter)
+		if err != nil || spec == nil {
+			return addonfactory.Values{}, err
+		}
+
+		config := addonapiv1alpha1.AddOnDeploymentConfig{Spec: *spec}
+		values := addonfactory.Values{}
+		for _, toValuesFunc := range toValuesFuncs {
+			v, err := toValuesFunc(config)
+			if err != nil {
+				return nil, err
+			}
+			values = addonfactory.MergeValues(values, v)
+		}
+		return values, nil
+	}
+}
+
+// GetAgentWorkloadOverridesPrivateValues returns a func to get the agent workload overrides merged from the
+// annotations of the AddOnDeploymentConfigs of the addon as a private value, this value would be used by the addon
+// template controller to size the agent deployments.
+func GetAgentWorkloadOverridesPrivateValues(getter utils.AddOnDeploymentConfigGetter) addonfactory.GetValuesFunc {
+	return func(cluster *clusterv1.ManagedCluster, addon *addonapiv1alpha1.ManagedClusterAddOn) (addonfactory.Values, error) {
+		overrides, err := helpers.GetEffectiveAgentWorkloadOverrides(context.Background(), addon, getter)
+		if err != nil || overrides == nil {
+			return addonfactory.Values{}, err
+		}
+
+		return addonfactory.Values{
+			WorkloadOverridesPrivateValueKey: overrides,
+		}, nil
+	}
+}
+
+// AgentInstallNamespaceFromMergedDeploymentConfigFunc returns a func to get the agent install namespace from the
+// merged AddOnDeploymentConfigs of the addon, it returns an empty string if there is no AddOnDeploymentConfig.
+func AgentInstallNamespaceFromMergedDeploymentConfigFunc(
+	getter utils.AddOnDeploymentConfigGetter) func(*addonapiv1alpha1.ManagedClusterAddOn) string {
+	return func(addon *addonapiv1alpha1.ManagedClusterAddOn) string {
+		if addon == nil {
+			utilruntime.HandleError(fmt.Errorf("failed to get addon install namespace, addon is nil"))
+			return ""
+		}
+
+		spec, err := helpers.GetEffectiveAddOnDeploymentConfig(context.Background(), addon, getter)
+		if err != nil {
+			utilruntime.HandleError(fmt.Errorf("failed to get deployment config for addon %s: %v", addon.Name, err))
+			return ""
+		}
+		if spec == nil {
+			return ""
+		}
+		return spec.AgentInstallNamespace
+	}
+}
+
 type keyValuePair struct {
 	name  string
 	value string
@@ -46,6 +116,7 @@ type orderedValues []keyValuePair
 func (a *CRDTemplateAgentAddon) getValues(
 	cluster *clusterv1.ManagedCluster,
 	addon *addonapiv1alpha1.ManagedClusterAddOn,
+	installNamespace string,
 	template *addonapiv1alpha1.AddOnTemplate,
 ) (orderedValues, map[string]interface{}, map[string]interface{}, error) {
 
@@ -60,8 +131,9 @@ func (a *CRDTemplateAgentAddon) getValues(
 	overrideValues = addonfactory.MergeValues(overrideValues, defaultValues)
 
 	privateValuesKeys := map[string]struct{}{
-		NodePlacementPrivateValueKey: {},
-		RegistriesPrivateValueKey:    {},
+		NodePlacementPrivateValueKey:     {},
+		RegistriesPrivateValueKey:        {},
+		WorkloadOverridesPrivateValueKey: {},
 	}
 
 	for i := 0; i < len(a.getValuesFuncs); i++ {
@@ -83,7 +155,7 @@ func (a *CRDTemplateAgentAddon) getValues(
 			overrideValues = addonfactory.MergeValues(overrideValues, publicValues)
 		}
 	}
-	builtinSortedKeys, builtinValues, err := a.getBuiltinValues(cluster, addon)
+	builtinSortedKeys, builtinValues, err := a.getBuiltinValues(cluster, installNamespace)
 	if err != nil {
 		return presetValues, overrideValues, privateValues, nil
 	}
@@ -109,14 +181,9 @@ func (a *CRDTemplateAgentAddon) getValues(
 
 func (a *CRDTemplateAgentAddon) getBuiltinValues(
 	cluster *clusterv1.ManagedCluster,
-	addon *addonapiv1alpha1.ManagedClusterAddOn) ([]string, addonfactory.Values, error) {
+	installNamespace string) ([]string, addonfactory.Values, error) {
 	builtinValues := templateCRDBuiltinValues{}
 	builtinValues.ClusterName = cluster.GetName()
-
-	installNamespace := addon.Spec.InstallNamespace
-	if len(installNamespace) == 0 {
-		installNamespace = addonfactory.AddonDefaultInstallNamespace
-	}
 	builtinValues.AddonInstallNamespace = installNamespace
 
 	value, err := addonfactory.JsonStructToValues(builtinValues)
@@ -136,6 +203,9 @@ func (a *CRDTemplateAgentAddon) getDefaultValues(
 	if template.Spec.Registration != nil {
 		defaultValues.HubKubeConfigPath = hubKubeconfigPath()
 	}
+	if installMode, _ := constants.GetHostedModeInfo(addon.GetAnnotations()); installMode == constants.InstallModeHosted {
+		defaultValues.ManagedKubeConfigPath = managedKubeconfigPath()
+	}
 
 	value, err := addonfactory.JsonStructToValues(defaultValues)
 	if err != nil {
@@ -158,6 +228,10 @@ func hubKubeconfigPath() string {
 	return "/managed/hub-kubeconfig/kubeconfig"
 }
 
+func managedKubeconfigPath() string {
+	return "/managed/managed-kubeconfig/kubeconfig"
+}
+
 func GetAddOnRegistriesPrivateValuesFromClusterAnnotation(
 	logger klog.Logger,
 	cluster *clusterv1.ManagedCluster,
@@ -0,0 +1,3 @@
+// package webhook contains the managedclusteraddon admission hook to validate the ManagedClusterAddOn create and
+// update operations
+package webhook
@@ -0,0 +1,99 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+)
+
+var _ webhook.CustomValidator = &ManagedClusterAddOnWebhook{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *ManagedClusterAddOnWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (
+	admission.Warnings, error) {
+	addon, ok := obj.(*addonv1alpha1.ManagedClusterAddOn)
+	if !ok {
+		return nil, apierrors.NewBadRequest("Request managedClusterAddOn obj format is not right")
+	}
+	return nil, r.validateConfigs(ctx, addon)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *ManagedClusterAddOnWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (
+	admission.Warnings, error) {
+	addon, ok := newObj.(*addonv1alpha1.ManagedClusterAddOn)
+	if !ok {
+		return nil, apierrors.NewBadRequest("Request managedClusterAddOn obj format is not right")
+	}
+	oldAddon, ok := oldObj.(*addonv1alpha1.ManagedClusterAddOn)
+	if !ok {
+		return nil, apierrors.NewBadRequest("Request managedClusterAddOn obj format is not right")
+	}
+
+	// only validate the configs when they are changed, so the addons created before the supported configs of the
+	// ClusterManagementAddOn are changed could still be updated.
+	if equality.Semantic.DeepEqual(addon.Spec.Configs, oldAddon.Spec.Configs) {
+		return nil, nil
+	}
+	return nil, r.validateConfigs(ctx, addon)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *ManagedClusterAddOnWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateConfigs rejects the config references of the addon whose group and resource are not declared in the
+// supported configs of the ClusterManagementAddOn. The addon is not validated if the ClusterManagementAddOn does not
+// exist yet.
+func (r *ManagedClusterAddOnWebhook) validateConfigs(ctx context.Context, addon *addonv1alpha1.ManagedClusterAddOn) error {
+	if len(addon.Spec.Configs) == 0 {
+		return nil
+	}
+
+	cma, err := r.addonClient.AddonV1alpha1().ClusterManagementAddOns().Get(ctx, addon.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return apierrors.NewInternalError(err)
+	}
+
+	supported := map[addonv1alpha1.ConfigGroupResource]bool{}
+	var supportedValues []string
+	for _, config := range cma.Spec.SupportedConfigs {
+		supported[config.ConfigGroupResource] = true
+		supportedValues = append(supportedValues, groupResourceString(config.ConfigGroupResource))
+	}
+
+	var errs field.ErrorList
+	for i, config := range addon.Spec.Configs {
+		if supported[config.ConfigGroupResource] {
+			continue
+		}
+		errs = append(errs, field.NotSupported(field.NewPath("spec", "configs").Index(i),
+			groupResourceString(config.ConfigGroupResource), supportedValues))
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: addonv1alpha1.GroupName, Kind: "ManagedClusterAddOn"}, addon.Name, errs)
+}
+
+func groupResourceString(gr addonv1alpha1.ConfigGroupResource) string {
+	if len(gr.Group) == 0 {
+		return gr.Resource
+	}
+	return fmt.Sprintf("%s.%s", gr.Resource, gr.Group)
+}
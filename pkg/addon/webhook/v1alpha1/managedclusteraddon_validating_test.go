@@ -0,0 +1,113 @@
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	fakeaddon "open-cluster-management.io/api/client/addon/clientset/versioned/fake"
+)
+
+var (
+	deploymentConfig = addonv1alpha1.ConfigGroupResource{
+		Group:    "addon.open-cluster-management.io",
+		Resource: "addondeploymentconfigs",
+	}
+	unsupportedConfig = addonv1alpha1.ConfigGroupResource{
+		Group:    "example.io",
+		Resource: "foos",
+	}
+)
+
+func newAddon(configs ...addonv1alpha1.ConfigGroupResource) *addonv1alpha1.ManagedClusterAddOn {
+	addon := &addonv1alpha1.ManagedClusterAddOn{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "cluster1"},
+	}
+	for _, config := range configs {
+		addon.Spec.Configs = append(addon.Spec.Configs, addonv1alpha1.AddOnConfig{
+			ConfigGroupResource: config,
+			ConfigReferent:      addonv1alpha1.ConfigReferent{Name: "config"},
+		})
+	}
+	return addon
+}
+
+func TestManagedClusterAddOnValidate(t *testing.T) {
+	cma := &addonv1alpha1.ClusterManagementAddOn{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: addonv1alpha1.ClusterManagementAddOnSpec{
+			SupportedConfigs: []addonv1alpha1.ConfigMeta{{ConfigGroupResource: deploymentConfig}},
+		},
+	}
+
+	cases := []struct {
+		name          string
+		cmas          []runtime.Object
+		addon         *addonv1alpha1.ManagedClusterAddOn
+		oldAddon      *addonv1alpha1.ManagedClusterAddOn
+		expectedError string
+	}{
+		{
+			name:  "no configs",
+			cmas:  []runtime.Object{cma},
+			addon: newAddon(),
+		},
+		{
+			name:  "supported config",
+			cmas:  []runtime.Object{cma},
+			addon: newAddon(deploymentConfig),
+		},
+		{
+			name:          "unsupported config",
+			cmas:          []runtime.Object{cma},
+			addon:         newAddon(deploymentConfig, unsupportedConfig),
+			expectedError: `spec.configs[1]: Unsupported value: "foos.example.io"`,
+		},
+		{
+			name:  "cluster management addon not found",
+			addon: newAddon(unsupportedConfig),
+		},
+		{
+			name:     "configs not changed",
+			cmas:     []runtime.Object{cma},
+			addon:    newAddon(unsupportedConfig),
+			oldAddon: newAddon(unsupportedConfig),
+		},
+		{
+			name:          "unsupported config added",
+			cmas:          []runtime.Object{cma},
+			addon:         newAddon(unsupportedConfig),
+			oldAddon:      newAddon(),
+			expectedError: `spec.configs[0]: Unsupported value: "foos.example.io"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			w := &ManagedClusterAddOnWebhook{}
+			w.SetExternalAddonClientSet(fakeaddon.NewSimpleClientset(c.cmas...))
+
+			var err error
+			if c.oldAddon == nil {
+				_, err = w.ValidateCreate(context.TODO(), c.addon)
+			} else {
+				_, err = w.ValidateUpdate(context.TODO(), c.oldAddon, c.addon)
+			}
+
+			if len(c.expectedError) == 0 {
+				if err != nil {
+					t.Errorf("expected no error, but got %v", err)
+				}
+				return
+			}
+			if !apierrors.IsInvalid(err) || !strings.Contains(err.Error(), c.expectedError) {
+				t.Errorf("expected invalid error %q, but got %v", c.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package v1alpha1
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"open-cluster-management.io/api/addon/v1alpha1"
+	addonv1alpha1client "open-cluster-management.io/api/client/addon/clientset/versioned"
+)
+
+type ManagedClusterAddOnWebhook struct {
+	addonClient addonv1alpha1client.Interface
+}
+
+func (r *ManagedClusterAddOnWebhook) Init(mgr ctrl.Manager) error {
+	err := r.SetupWebhookWithManager(mgr)
+	if err != nil {
+		return err
+	}
+	r.addonClient, err = addonv1alpha1client.NewForConfig(mgr.GetConfig())
+	return err
+}
+
+// SetExternalAddonClientSet is function to enable the webhook injecting to kube admssion
+func (r *ManagedClusterAddOnWebhook) SetExternalAddonClientSet(client addonv1alpha1client.Interface) {
+	r.addonClient = client
+}
+
+func (r *ManagedClusterAddOnWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		WithValidator(r).
+		For(&v1alpha1.ManagedClusterAddOn{}).
+		Complete()
+}
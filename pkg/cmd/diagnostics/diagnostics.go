@@ -0,0 +1,22 @@
+package diagnostics
+
+import (
+	"github.com/spf13/cobra"
+
+	"open-cluster-management.io/ocm/pkg/operator/diagnostics"
+)
+
+// NewDiagnosticsCmd generates a command to collect the diagnostics bundle for a support case
+func NewDiagnosticsCmd() *cobra.Command {
+	opts := diagnostics.NewOptions()
+	cmd := &cobra.Command{
+		Use:   "diagnostics",
+		Short: "Collect a diagnostics bundle of the cluster manager and the klusterlet for a support case",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Run(cmd.Context())
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+	return cmd
+}
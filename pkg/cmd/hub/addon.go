@@ -13,14 +13,16 @@ import (
 // NewAddonManager generates a command to start addon manager
 func NewAddonManager() *cobra.Command {
 	opts := commonoptions.NewOptions()
+	manager := addon.NewAddonManagerOptions()
 	cmdConfig := opts.
-		NewControllerCommandConfig("manager", version.Get(), addon.RunManager)
+		NewControllerCommandConfig("manager", version.Get(), manager.RunAddonManager)
 	cmd := cmdConfig.NewCommandWithContext(context.TODO())
 	cmd.Use = "manager"
 	cmd.Short = "Start the Addon Manager"
 
 	flags := cmd.Flags()
 	opts.AddFlags(flags)
+	manager.AddFlags(flags)
 
 	return cmd
 }
@@ -0,0 +1,27 @@
+package hub
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	commonoptions "open-cluster-management.io/ocm/pkg/common/options"
+	"open-cluster-management.io/ocm/pkg/fleetmetrics"
+	"open-cluster-management.io/ocm/pkg/version"
+)
+
+// NewFleetMetricsExporter generates a command to start the fleet metrics exporter
+func NewFleetMetricsExporter() *cobra.Command {
+	opts := commonoptions.NewOptions()
+	exporter := fleetmetrics.NewExporterOptions()
+	cmdConfig := opts.
+		NewControllerCommandConfig("fleet-metrics-exporter", version.Get(), exporter.RunExporter)
+	cmd := cmdConfig.NewCommandWithContext(context.TODO())
+	cmd.Use = "fleet-metrics"
+	cmd.Short = "Start the Fleet Metrics Exporter"
+
+	flags := cmd.Flags()
+	opts.AddFlags(flags)
+
+	return cmd
+}
@@ -22,6 +22,9 @@ func NewHubOperatorCmd() *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.BoolVar(&cmOptions.SkipRemoveCRDs, "skip-remove-crds", false, "Skip removing CRDs while ClusterManager is deleting.")
+	flags.BoolVar(&cmOptions.EnableHealthProbe, "enable-health-probe", false,
+		"Probe the /healthz endpoint of the pods of the hub components, and report the components which are running "+
+			"but not healthy as degraded.")
 	opts.AddFlags(flags)
 	return cmd
 }
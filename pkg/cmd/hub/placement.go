@@ -12,14 +12,23 @@ import (
 
 func NewPlacementController() *cobra.Command {
 	opts := commonoptions.NewOptions()
+	manager := controllers.NewPlacementManagerOptions()
 	cmdConfig := opts.
-		NewControllerCommandConfig("placement", version.Get(), controllers.RunControllerManager)
+		NewControllerCommandConfig("placement", version.Get(), manager.RunControllerManager)
 	cmd := cmdConfig.NewCommandWithContext(context.TODO())
 	cmd.Use = "controller"
 	cmd.Short = "Start the Placement Scheduling Controller"
 
 	flags := cmd.Flags()
+	manager.AddFlags(flags)
 	opts.AddFlags(flags)
 
+	// the replicas elect the leaders of the shards instead of a global leader when the placements are sharded.
+	cmd.PreRun = func(cmd *cobra.Command, args []string) {
+		if manager.ShardCount > 1 {
+			cmdConfig.DisableLeaderElection = true
+		}
+	}
+
 	return cmd
 }
@@ -13,11 +13,16 @@ import (
 // NewHubManager generates a command to start hub manager
 func NewWorkController() *cobra.Command {
 	opts := commonoptions.NewOptions()
+	manager := hub.NewWorkHubManagerOptions()
 	cmdConfig := opts.
-		NewControllerCommandConfig("work-manager", version.Get(), hub.RunWorkHubManager)
+		NewControllerCommandConfig("work-manager", version.Get(), manager.RunWorkHubManager)
 	cmd := cmdConfig.NewCommandWithContext(context.TODO())
 	cmd.Use = "manager"
 	cmd.Short = "Start the Work Hub Manager"
 
+	flags := cmd.Flags()
+	manager.AddFlags(flags)
+	opts.AddFlags(flags)
+
 	return cmd
 }
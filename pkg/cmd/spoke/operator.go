@@ -34,6 +34,9 @@ func NewKlusterletOperatorCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&klOptions.SkipPlaceholderHubSecret, "skip-placeholder-hub-secret", false,
 		"If set, will skip ensuring a placeholder hub secret which is originally intended for pulling "+
 			"work image before approved")
+	flags.BoolVar(&klOptions.EnableHealthProbe, "enable-health-probe", false,
+		"Probe the /healthz endpoint of the pods of the agents, and report the agents which are running but not "+
+			"healthy as degraded.")
 	opts.AddFlags(flags)
 
 	return cmd
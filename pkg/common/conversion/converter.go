@@ -0,0 +1,102 @@
+package conversion
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ConvertFunc converts the object in place between a version and the hub version of a kind. The apiVersion of
+// the object is set by the Converter, so a ConvertFunc only converts the fields changed across the versions.
+type ConvertFunc func(obj *unstructured.Unstructured) error
+
+// Converter converts the objects across the versions of the kinds registered. Each kind has a hub version, an
+// object is converted to the hub version first and then to the desired version, so a new version only needs the
+// conversions from and to the hub version.
+type Converter struct {
+	kinds map[schema.GroupKind]*kindConverter
+}
+
+type kindConverter struct {
+	hubVersion string
+	toHub      map[string]ConvertFunc
+	fromHub    map[string]ConvertFunc
+}
+
+// NewConverter returns a Converter without any kind registered
+func NewConverter() *Converter {
+	return &Converter{kinds: map[schema.GroupKind]*kindConverter{}}
+}
+
+// Register registers the conversions between a version and the hub version of a kind. A nil ConvertFunc means
+// the schemas of the two versions are the same.
+func (c *Converter) Register(gk schema.GroupKind, hubVersion, version string, toHub, fromHub ConvertFunc) error {
+	kc, ok := c.kinds[gk]
+	if !ok {
+		kc = &kindConverter{
+			hubVersion: hubVersion,
+			toHub:      map[string]ConvertFunc{},
+			fromHub:    map[string]ConvertFunc{},
+		}
+		c.kinds[gk] = kc
+	}
+	if kc.hubVersion != hubVersion {
+		return fmt.Errorf("the hub version of %s is %s, but got %s", gk, kc.hubVersion, hubVersion)
+	}
+	if version == hubVersion {
+		return fmt.Errorf("the version %s of %s is the hub version", version, gk)
+	}
+	if _, ok := kc.toHub[version]; ok {
+		return fmt.Errorf("the version %s of %s is registered already", version, gk)
+	}
+	kc.toHub[version] = toHub
+	kc.fromHub[version] = fromHub
+	return nil
+}
+
+// Convert returns a copy of the object converted to the desired version.
+func (c *Converter) Convert(obj *unstructured.Unstructured, toVersion string) (*unstructured.Unstructured, error) {
+	gvk := obj.GroupVersionKind()
+	kc, ok := c.kinds[gvk.GroupKind()]
+	if !ok {
+		return nil, fmt.Errorf("the kind %s is not registered", gvk.GroupKind())
+	}
+	if !kc.served(gvk.Version) {
+		return nil, fmt.Errorf("the version %s of %s is not registered", gvk.Version, gvk.GroupKind())
+	}
+	if !kc.served(toVersion) {
+		return nil, fmt.Errorf("the version %s of %s is not registered", toVersion, gvk.GroupKind())
+	}
+
+	converted := obj.DeepCopy()
+	if gvk.Version == toVersion {
+		return converted, nil
+	}
+
+	if gvk.Version != kc.hubVersion {
+		if convert := kc.toHub[gvk.Version]; convert != nil {
+			if err := convert(converted); err != nil {
+				return nil, fmt.Errorf("failed to convert %s from %s to %s: %w", gvk.GroupKind(), gvk.Version, kc.hubVersion, err)
+			}
+		}
+	}
+	if toVersion != kc.hubVersion {
+		if convert := kc.fromHub[toVersion]; convert != nil {
+			if err := convert(converted); err != nil {
+				return nil, fmt.Errorf("failed to convert %s from %s to %s: %w", gvk.GroupKind(), kc.hubVersion, toVersion, err)
+			}
+		}
+	}
+
+	converted.SetAPIVersion(schema.GroupVersion{Group: gvk.Group, Version: toVersion}.String())
+	return converted, nil
+}
+
+func (kc *kindConverter) served(version string) bool {
+	if version == kc.hubVersion {
+		return true
+	}
+	_, ok := kc.toHub[version]
+	return ok
+}
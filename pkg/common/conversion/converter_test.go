@@ -0,0 +1,239 @@
+package conversion
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+)
+
+var testGroupKind = schema.GroupKind{Group: "test.open-cluster-management.io", Kind: "Test"}
+
+// newTestConverter registers the versions v1alpha1, v1beta1 and the hub version v1 of the test kind. The field
+// spec.size is renamed to spec.replicas in v1, and spec.count is renamed to spec.size in v1beta1.
+func newTestConverter(t *testing.T) *Converter {
+	converter := NewConverter()
+	if err := converter.Register(testGroupKind, "v1", "v1alpha1",
+		renameField("count", "replicas"), renameField("replicas", "count")); err != nil {
+		t.Fatal(err)
+	}
+	if err := converter.Register(testGroupKind, "v1", "v1beta1",
+		renameField("size", "replicas"), renameField("replicas", "size")); err != nil {
+		t.Fatal(err)
+	}
+	return converter
+}
+
+func renameField(from, to string) ConvertFunc {
+	return func(obj *unstructured.Unstructured) error {
+		value, found, err := unstructured.NestedFieldNoCopy(obj.Object, "spec", from)
+		if err != nil || !found {
+			return err
+		}
+		unstructured.RemoveNestedField(obj.Object, "spec", from)
+		return unstructured.SetNestedField(obj.Object, value, "spec", to)
+	}
+}
+
+func newTestObject(version, field string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "test.open-cluster-management.io/" + version,
+		"kind":       "Test",
+		"metadata":   map[string]interface{}{"name": "test"},
+		"spec":       map[string]interface{}{field: int64(3)},
+	}}
+}
+
+func TestConvert(t *testing.T) {
+	converter := newTestConverter(t)
+
+	cases := []struct {
+		name          string
+		obj           *unstructured.Unstructured
+		toVersion     string
+		expected      *unstructured.Unstructured
+		expectedError string
+	}{
+		{
+			name:      "to hub",
+			obj:       newTestObject("v1beta1", "size"),
+			toVersion: "v1",
+			expected:  newTestObject("v1", "replicas"),
+		},
+		{
+			name:      "from hub",
+			obj:       newTestObject("v1", "replicas"),
+			toVersion: "v1alpha1",
+			expected:  newTestObject("v1alpha1", "count"),
+		},
+		{
+			name:      "through hub",
+			obj:       newTestObject("v1alpha1", "count"),
+			toVersion: "v1beta1",
+			expected:  newTestObject("v1beta1", "size"),
+		},
+		{
+			name:      "same version",
+			obj:       newTestObject("v1beta1", "size"),
+			toVersion: "v1beta1",
+			expected:  newTestObject("v1beta1", "size"),
+		},
+		{
+			name:          "unregistered version",
+			obj:           newTestObject("v1beta1", "size"),
+			toVersion:     "v2",
+			expectedError: "the version v2 of Test.test.open-cluster-management.io is not registered",
+		},
+		{
+			name: "unregistered kind",
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "test.open-cluster-management.io/v1",
+				"kind":       "Other",
+			}},
+			toVersion:     "v1beta1",
+			expectedError: "the kind Other.test.open-cluster-management.io is not registered",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			converted, err := converter.Convert(c.obj, c.toVersion)
+			if len(c.expectedError) > 0 {
+				testingcommon.AssertError(t, err, c.expectedError)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equality.Semantic.DeepEqual(converted, c.expected) {
+				t.Errorf("expected %v, but got %v", c.expected, converted)
+			}
+		})
+	}
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	converter := newTestConverter(t)
+	fields := map[string]string{"v1alpha1": "count", "v1beta1": "size", "v1": "replicas"}
+
+	for from, field := range fields {
+		for to := range fields {
+			original := newTestObject(from, field)
+			converted, err := converter.Convert(original, to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			back, err := converter.Convert(converted, from)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equality.Semantic.DeepEqual(original, back) {
+				t.Errorf("expected %v is not changed in the round trip through %s, but got %v", original, to, back)
+			}
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	converter := newTestConverter(t)
+	testingcommon.AssertError(t, converter.Register(testGroupKind, "v1beta1", "v1alpha1", nil, nil),
+		"the hub version of Test.test.open-cluster-management.io is v1, but got v1beta1")
+	testingcommon.AssertError(t, converter.Register(testGroupKind, "v1", "v1", nil, nil),
+		"the version v1 of Test.test.open-cluster-management.io is the hub version")
+	testingcommon.AssertError(t, converter.Register(testGroupKind, "v1", "v1beta1", nil, nil),
+		"the version v1beta1 of Test.test.open-cluster-management.io is registered already")
+}
+
+func TestServeHTTP(t *testing.T) {
+	cases := []struct {
+		name              string
+		desiredAPIVersion string
+		objects           []*unstructured.Unstructured
+		expectedStatus    string
+		expectedObjects   []*unstructured.Unstructured
+	}{
+		{
+			name:              "convert",
+			desiredAPIVersion: "test.open-cluster-management.io/v1",
+			objects:           []*unstructured.Unstructured{newTestObject("v1alpha1", "count"), newTestObject("v1beta1", "size")},
+			expectedStatus:    metav1.StatusSuccess,
+			expectedObjects:   []*unstructured.Unstructured{newTestObject("v1", "replicas"), newTestObject("v1", "replicas")},
+		},
+		{
+			name:              "another group",
+			desiredAPIVersion: "other.open-cluster-management.io/v1",
+			objects:           []*unstructured.Unstructured{newTestObject("v1alpha1", "count")},
+			expectedStatus:    metav1.StatusFailure,
+		},
+		{
+			name:              "unregistered version",
+			desiredAPIVersion: "test.open-cluster-management.io/v2",
+			objects:           []*unstructured.Unstructured{newTestObject("v1alpha1", "count")},
+			expectedStatus:    metav1.StatusFailure,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			review := &apiextensionsv1.ConversionReview{
+				Request: &apiextensionsv1.ConversionRequest{UID: "123", DesiredAPIVersion: c.desiredAPIVersion},
+			}
+			for _, obj := range c.objects {
+				data, err := obj.MarshalJSON()
+				if err != nil {
+					t.Fatal(err)
+				}
+				review.Request.Objects = append(review.Request.Objects, runtime.RawExtension{Raw: data})
+			}
+			body, err := json.Marshal(review)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			recorder := httptest.NewRecorder()
+			newTestConverter(t).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, WebhookPath, bytes.NewReader(body)))
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("expected status code 200, but got %d", recorder.Code)
+			}
+
+			response := &apiextensionsv1.ConversionReview{}
+			if err := json.Unmarshal(recorder.Body.Bytes(), response); err != nil {
+				t.Fatal(err)
+			}
+			if response.Response.UID != "123" {
+				t.Errorf("expected uid 123, but got %s", response.Response.UID)
+			}
+			if response.Response.Result.Status != c.expectedStatus {
+				t.Errorf("expected status %s, but got %v", c.expectedStatus, response.Response.Result)
+			}
+			if len(response.Response.ConvertedObjects) != len(c.expectedObjects) {
+				t.Fatalf("expected %d objects, but got %d", len(c.expectedObjects), len(response.Response.ConvertedObjects))
+			}
+			for i, raw := range response.Response.ConvertedObjects {
+				obj := &unstructured.Unstructured{}
+				if err := obj.UnmarshalJSON(raw.Raw); err != nil {
+					t.Fatal(err)
+				}
+				if !equality.Semantic.DeepEqual(obj, c.expectedObjects[i]) {
+					t.Errorf("expected %v, but got %v", c.expectedObjects[i], obj)
+				}
+			}
+		})
+	}
+
+	recorder := httptest.NewRecorder()
+	NewConverter().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, WebhookPath, bytes.NewReader([]byte("{}"))))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("expected status code 400 without request, but got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,4 @@
+// Package conversion is the conversion webhook of the OCM APIs serving multiple versions. The conversions are
+// registered per kind on the unstructured objects, so the deprecated versions are able to be converted without
+// keeping their go types.
+package conversion
@@ -0,0 +1,80 @@
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+)
+
+// WebhookPath is the path of the conversion webhook on the webhook servers, which is referred by the conversion
+// of the CRDs serving multiple versions.
+const WebhookPath = "/convert"
+
+// ServeHTTP handles the ConversionReview requests from the apiserver.
+func (c *Converter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	review := &apiextensionsv1.ConversionReview{}
+	if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode the conversion review: %v", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "the conversion review has no request", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = c.review(review.Request)
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		klog.Errorf("Failed to write the conversion review response: %v", err)
+	}
+}
+
+func (c *Converter) review(request *apiextensionsv1.ConversionRequest) *apiextensionsv1.ConversionResponse {
+	response := &apiextensionsv1.ConversionResponse{UID: request.UID}
+
+	desired, err := schema.ParseGroupVersion(request.DesiredAPIVersion)
+	if err != nil {
+		response.Result = failure(err)
+		return response
+	}
+
+	for _, raw := range request.Objects {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(raw.Raw); err != nil {
+			response.Result = failure(err)
+			return response
+		}
+		if obj.GroupVersionKind().Group != desired.Group {
+			response.Result = failure(fmt.Errorf("the group of %s is not the desired group %s", obj.GetAPIVersion(), desired.Group))
+			return response
+		}
+
+		converted, err := c.Convert(obj, desired.Version)
+		if err != nil {
+			response.Result = failure(err)
+			return response
+		}
+		data, err := converted.MarshalJSON()
+		if err != nil {
+			response.Result = failure(err)
+			return response
+		}
+		response.ConvertedObjects = append(response.ConvertedObjects, runtime.RawExtension{Raw: data})
+	}
+
+	response.Result = metav1.Status{Status: metav1.StatusSuccess}
+	return response
+}
+
+func failure(err error) metav1.Status {
+	return metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+}
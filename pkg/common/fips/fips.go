@@ -0,0 +1,175 @@
+// Package fips restricts the TLS configurations and the certificates of the components to the FIPS 140-2 approved
+// algorithms and key sizes. The FIPS mode is enabled by building the components with the "fips" build tag, or by the
+// --fips-mode flag at runtime.
+package fips
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"k8s.io/client-go/rest"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+// minRSAKeySize is the minimum size of the approved RSA keys.
+const minRSAKeySize = 2048
+
+var enabled = buildTagEnabled
+
+// approvedCipherSuites are the approved cipher suites of TLS 1.2.
+var approvedCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+// approvedCurves are the approved curves of the ECDSA keys and the key exchanges.
+var approvedCurves = []tls.CurveID{tls.CurveP256, tls.CurveP384, tls.CurveP521}
+
+var approvedSignatureAlgorithms = map[x509.SignatureAlgorithm]bool{
+	x509.SHA256WithRSA:    true,
+	x509.SHA384WithRSA:    true,
+	x509.SHA512WithRSA:    true,
+	x509.SHA256WithRSAPSS: true,
+	x509.SHA384WithRSAPSS: true,
+	x509.SHA512WithRSAPSS: true,
+	x509.ECDSAWithSHA256:  true,
+	x509.ECDSAWithSHA384:  true,
+	x509.ECDSAWithSHA512:  true,
+}
+
+// ErrNotCompliant is returned when a key, a certificate or a TLS configuration is not FIPS compliant.
+var ErrNotCompliant = errors.New("not FIPS compliant")
+
+// Enable enables the FIPS mode, it is expected to be called once on startup before the controllers are started.
+func Enable() {
+	enabled = true
+}
+
+// Enabled returns true if the FIPS mode is enabled by the build tag or the flag.
+func Enabled() bool {
+	return enabled
+}
+
+// SecureTLSConfig restricts the config to TLS 1.2 with the approved cipher suites and curves if the FIPS mode is
+// enabled, it does nothing otherwise. TLS 1.3 is disabled since its cipher suites are not configurable and include
+// ChaCha20-Poly1305 which is not approved.
+func SecureTLSConfig(config *tls.Config) {
+	if !Enabled() {
+		return
+	}
+	config.MinVersion = tls.VersionTLS12
+	config.MaxVersion = tls.VersionTLS12
+	config.CipherSuites = approvedCipherSuites
+	config.CurvePreferences = approvedCurves
+}
+
+// ValidateTLSConfig returns an ErrNotCompliant error if the config requires a TLS version other than TLS 1.2, or
+// has a cipher suite which is not approved.
+func ValidateTLSConfig(config *tls.Config) error {
+	if config.MinVersion > tls.VersionTLS12 {
+		return fmt.Errorf("%w: TLS version %s is not supported", ErrNotCompliant, tls.VersionName(config.MinVersion))
+	}
+	for _, suite := range config.CipherSuites {
+		approved := false
+		for _, approvedSuite := range approvedCipherSuites {
+			if suite == approvedSuite {
+				approved = true
+				break
+			}
+		}
+		if !approved {
+			return fmt.Errorf("%w: cipher suite %s is not approved", ErrNotCompliant, tls.CipherSuiteName(suite))
+		}
+	}
+	return nil
+}
+
+// ValidatePublicKey returns an ErrNotCompliant error if the key is not an RSA key of at least 2048 bits or an ECDSA
+// key on an approved curve.
+func ValidatePublicKey(key crypto.PublicKey) error {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if k.N.BitLen() < minRSAKeySize {
+			return fmt.Errorf("%w: RSA key size %d is less than %d", ErrNotCompliant, k.N.BitLen(), minRSAKeySize)
+		}
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256(), elliptic.P384(), elliptic.P521():
+		default:
+			return fmt.Errorf("%w: ECDSA curve %s is not approved", ErrNotCompliant, k.Curve.Params().Name)
+		}
+	default:
+		return fmt.Errorf("%w: key type %T is not approved", ErrNotCompliant, key)
+	}
+	return nil
+}
+
+// ValidateCertificates returns an ErrNotCompliant error if any of the certificates is signed by an algorithm which is
+// not approved or has a key which is not approved.
+func ValidateCertificates(certs ...*x509.Certificate) error {
+	for _, cert := range certs {
+		if !approvedSignatureAlgorithms[cert.SignatureAlgorithm] {
+			return fmt.Errorf("%w: certificate %q is signed with %s", ErrNotCompliant,
+				cert.Subject.CommonName, cert.SignatureAlgorithm)
+		}
+		if err := ValidatePublicKey(cert.PublicKey); err != nil {
+			return fmt.Errorf("certificate %q: %w", cert.Subject.CommonName, err)
+		}
+	}
+	return nil
+}
+
+// ValidateCertificatesPEM parses the PEM encoded certificates and validates them with ValidateCertificates.
+func ValidateCertificatesPEM(data []byte) error {
+	certs, err := certutil.ParseCertsPEM(data)
+	if err != nil {
+		return err
+	}
+	return ValidateCertificates(certs...)
+}
+
+// ValidateCertificateFile reads the PEM encoded certificates from the file and validates them with
+// ValidateCertificates.
+func ValidateCertificateFile(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return ValidateCertificatesPEM(data)
+}
+
+// ValidateRESTConfig validates the CA and the client certificates of the config, the files are read if the data of
+// the certificates are not set.
+func ValidateRESTConfig(config *rest.Config) error {
+	for _, certs := range []struct {
+		data []byte
+		file string
+	}{
+		{data: config.CAData, file: config.CAFile},
+		{data: config.CertData, file: config.CertFile},
+	} {
+		data := certs.data
+		if len(data) == 0 && len(certs.file) > 0 {
+			var err error
+			if data, err = os.ReadFile(certs.file); err != nil {
+				return err
+			}
+		}
+		if len(data) == 0 {
+			continue
+		}
+		if err := ValidateCertificatesPEM(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,6 @@
+//go:build fips
+
+package fips
+
+// buildTagEnabled enables the FIPS mode for the components built with the "fips" build tag.
+const buildTagEnabled = true
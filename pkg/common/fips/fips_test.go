@@ -0,0 +1,119 @@
+package fips
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func newCert(t *testing.T, key crypto.Signer) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestValidateCertificates(t *testing.T) {
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p224Key, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsa2048Key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsa1024Key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name            string
+		key             crypto.Signer
+		expectCompliant bool
+	}{
+		{
+			name:            "ecdsa p256",
+			key:             p256Key,
+			expectCompliant: true,
+		},
+		{
+			name:            "rsa 2048",
+			key:             rsa2048Key,
+			expectCompliant: true,
+		},
+		{
+			name: "ecdsa p224",
+			key:  p224Key,
+		},
+		{
+			name: "rsa 1024",
+			key:  rsa1024Key,
+		},
+		{
+			name: "ed25519",
+			key:  ed25519Key,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateCertificates(newCert(t, c.key))
+			if c.expectCompliant && err != nil {
+				t.Errorf("expected compliant, but got %v", err)
+			}
+			if !c.expectCompliant && !errors.Is(err, ErrNotCompliant) {
+				t.Errorf("expected not compliant error, but got %v", err)
+			}
+		})
+	}
+}
+
+func TestSecureTLSConfig(t *testing.T) {
+	defer func() { enabled = buildTagEnabled }()
+
+	enabled = false
+	config := &tls.Config{MinVersion: tls.VersionTLS13}
+	SecureTLSConfig(config)
+	if config.MinVersion != tls.VersionTLS13 || len(config.CipherSuites) != 0 {
+		t.Errorf("expected config not changed, but got %v", config)
+	}
+
+	Enable()
+	SecureTLSConfig(config)
+	if config.MinVersion != tls.VersionTLS12 || config.MaxVersion != tls.VersionTLS12 {
+		t.Errorf("expected TLS 1.2 only, but got min %d max %d", config.MinVersion, config.MaxVersion)
+	}
+	if len(config.CipherSuites) != len(approvedCipherSuites) {
+		t.Errorf("expected approved cipher suites, but got %v", config.CipherSuites)
+	}
+}
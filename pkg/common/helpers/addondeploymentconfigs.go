@@ -0,0 +1,282 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	"open-cluster-management.io/addon-framework/pkg/utils"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+)
+
+const (
+	// AddonMergeInheritedConfigsAnnotationKey is the annotation on the ManagedClusterAddOn to merge the
+	// AddOnDeploymentConfigs in its spec on top of the AddOnDeploymentConfig inherited from the install strategy or
+	// the default configs of the ClusterManagementAddOn, instead of replacing it. Set it to "true" to only override
+	// part of a global AddOnDeploymentConfig for a cluster.
+	AddonMergeInheritedConfigsAnnotationKey = "addon.open-cluster-management.io/merge-inherited-configs"
+
+	// AddonConditionDeploymentConfigMerged is the condition type of the ManagedClusterAddOn which records the
+	// effective AddOnDeploymentConfig when the addon references more than one AddOnDeploymentConfig.
+	AddonConditionDeploymentConfigMerged = "DeploymentConfigMerged"
+
+	// AgentResourceRequirementsAnnotationKey is the annotation on the AddOnDeploymentConfig to set the resource
+	// requirements of the containers of the addon agent deployments. The value is a JSON object keyed by the
+	// container name, and the key "*" applies to the containers without their own entry, for example:
+	//
+	//	{"*": {"requests": {"cpu": "10m", "memory": "32Mi"}}, "manager": {"limits": {"memory": "512Mi"}}}
+	AgentResourceRequirementsAnnotationKey = "addon.open-cluster-management.io/agent-resource-requirements"
+
+	// AgentReplicasAnnotationKey is the annotation on the AddOnDeploymentConfig to set the replicas of the addon
+	// agent deployments.
+	AgentReplicasAnnotationKey = "addon.open-cluster-management.io/agent-replicas"
+
+	// AgentPriorityClassNameAnnotationKey is the annotation on the AddOnDeploymentConfig to set the priority class
+	// name of the pods of the addon agent deployments.
+	AgentPriorityClassNameAnnotationKey = "addon.open-cluster-management.io/agent-priority-class-name"
+
+	// AllContainersResourceRequirementsKey is the key in the resource requirements annotation which applies to
+	// all the containers.
+	AllContainersResourceRequirementsKey = "*"
+)
+
+// AgentWorkloadOverrides is the sizing of the addon agent deployments set by the annotations of the
+// AddOnDeploymentConfigs, so the agents could be sized differently per cluster. Since the spec hash of the configs
+// does not cover the annotations, changing only the annotations takes effect when the addon is reconciled next time.
+type AgentWorkloadOverrides struct {
+	// ResourceRequirements is keyed by the container name, see AgentResourceRequirementsAnnotationKey.
+	ResourceRequirements map[string]corev1.ResourceRequirements
+	Replicas             *int32
+	PriorityClassName    string
+}
+
+// AddOnDeploymentConfigGroupResource is the config group resource of the AddOnDeploymentConfig.
+var AddOnDeploymentConfigGroupResource = addonv1alpha1.ConfigGroupResource{
+	Group:    utils.AddOnDeploymentConfigGVR.Group,
+	Resource: utils.AddOnDeploymentConfigGVR.Resource,
+}
+
+// GetAddOnDeploymentConfigReferents returns the referents of the AddOnDeploymentConfigs in the status of the addon.
+// The referents are ordered by priority from low to high.
+func GetAddOnDeploymentConfigReferents(addon *addonv1alpha1.ManagedClusterAddOn) []addonv1alpha1.ConfigReferent {
+	var referents []addonv1alpha1.ConfigReferent
+	for _, config := range addon.Status.ConfigReferences {
+		if config.ConfigGroupResource != AddOnDeploymentConfigGroupResource {
+			continue
+		}
+		referent := config.ConfigReferent
+		if config.DesiredConfig != nil {
+			referent = config.DesiredConfig.ConfigReferent
+		}
+		referents = append(referents, referent)
+	}
+	return referents
+}
+
+// GetEffectiveAddOnDeploymentConfig returns the spec merged from all the AddOnDeploymentConfigs in the status of
+// the addon, it returns nil if the addon does not reference any AddOnDeploymentConfig.
+func GetEffectiveAddOnDeploymentConfig(
+	ctx context.Context,
+	addon *addonv1alpha1.ManagedClusterAddOn,
+	getter utils.AddOnDeploymentConfigGetter) (*addonv1alpha1.AddOnDeploymentConfigSpec, error) {
+	referents := GetAddOnDeploymentConfigReferents(addon)
+	if len(referents) == 0 {
+		return nil, nil
+	}
+
+	configs, err := getAddOnDeploymentConfigs(ctx, referents, getter)
+	if err != nil {
+		return nil, err
+	}
+
+	var specs []addonv1alpha1.AddOnDeploymentConfigSpec
+	for _, config := range configs {
+		specs = append(specs, config.Spec)
+	}
+
+	spec := MergeAddOnDeploymentConfigSpecs(specs...)
+	return &spec, nil
+}
+
+// GetEffectiveAgentWorkloadOverrides returns the agent workload overrides merged from the annotations of all the
+// AddOnDeploymentConfigs in the status of the addon, it returns nil if no AddOnDeploymentConfig of the addon has
+// the annotations.
+func GetEffectiveAgentWorkloadOverrides(
+	ctx context.Context,
+	addon *addonv1alpha1.ManagedClusterAddOn,
+	getter utils.AddOnDeploymentConfigGetter) (*AgentWorkloadOverrides, error) {
+	referents := GetAddOnDeploymentConfigReferents(addon)
+	if len(referents) == 0 {
+		return nil, nil
+	}
+
+	configs, err := getAddOnDeploymentConfigs(ctx, referents, getter)
+	if err != nil {
+		return nil, err
+	}
+
+	var overrides []AgentWorkloadOverrides
+	for _, config := range configs {
+		o, err := agentWorkloadOverridesFromAnnotations(config)
+		if err != nil {
+			return nil, err
+		}
+		if o != nil {
+			overrides = append(overrides, *o)
+		}
+	}
+	if len(overrides) == 0 {
+		return nil, nil
+	}
+
+	merged := MergeAgentWorkloadOverrides(overrides...)
+	return &merged, nil
+}
+
+// MergeAgentWorkloadOverrides merges the agent workload overrides, a later one has a higher priority. The resource
+// requirements are merged by the container name, and the replicas and priority class name are overridden if they
+// are set in a later one.
+func MergeAgentWorkloadOverrides(overrides ...AgentWorkloadOverrides) AgentWorkloadOverrides {
+	merged := AgentWorkloadOverrides{}
+	for _, o := range overrides {
+		for name, requirements := range o.ResourceRequirements {
+			if merged.ResourceRequirements == nil {
+				merged.ResourceRequirements = map[string]corev1.ResourceRequirements{}
+			}
+			merged.ResourceRequirements[name] = requirements
+		}
+		if o.Replicas != nil {
+			replicas := *o.Replicas
+			merged.Replicas = &replicas
+		}
+		if len(o.PriorityClassName) > 0 {
+			merged.PriorityClassName = o.PriorityClassName
+		}
+	}
+	return merged
+}
+
+func agentWorkloadOverridesFromAnnotations(config *addonv1alpha1.AddOnDeploymentConfig) (*AgentWorkloadOverrides, error) {
+	annotations := config.GetAnnotations()
+	resources, hasResources := annotations[AgentResourceRequirementsAnnotationKey]
+	replicas, hasReplicas := annotations[AgentReplicasAnnotationKey]
+	priorityClassName, hasPriorityClassName := annotations[AgentPriorityClassNameAnnotationKey]
+	if !hasResources && !hasReplicas && !hasPriorityClassName {
+		return nil, nil
+	}
+
+	overrides := &AgentWorkloadOverrides{PriorityClassName: priorityClassName}
+	if hasResources {
+		if err := json.Unmarshal([]byte(resources), &overrides.ResourceRequirements); err != nil {
+			return nil, fmt.Errorf("invalid annotation %s of addon deployment config %s/%s: %v",
+				AgentResourceRequirementsAnnotationKey, config.Namespace, config.Name, err)
+		}
+	}
+	if hasReplicas {
+		r, err := strconv.ParseInt(replicas, 10, 32)
+		if err != nil || r < 0 {
+			return nil, fmt.Errorf("invalid annotation %s of addon deployment config %s/%s: %q is not a non-negative integer",
+				AgentReplicasAnnotationKey, config.Namespace, config.Name, replicas)
+		}
+		r32 := int32(r)
+		overrides.Replicas = &r32
+	}
+	return overrides, nil
+}
+
+func getAddOnDeploymentConfigs(
+	ctx context.Context,
+	referents []addonv1alpha1.ConfigReferent,
+	getter utils.AddOnDeploymentConfigGetter) ([]*addonv1alpha1.AddOnDeploymentConfig, error) {
+	var configs []*addonv1alpha1.AddOnDeploymentConfig
+	for _, referent := range referents {
+		config, err := getter.Get(ctx, referent.Namespace, referent.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get addon deployment config %s/%s: %w", referent.Namespace, referent.Name, err)
+		}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+// MergeAddOnDeploymentConfigSpecs merges the specs of AddOnDeploymentConfigs, a later spec has a higher priority.
+//   - customized variables and node selectors are merged by name, and the value of a later spec wins.
+//   - tolerations are merged and deduplicated.
+//   - registries are merged by source, and the mirror of a later spec wins.
+//   - each field of the proxy config and the agent install namespace are overridden if they are set in a later spec.
+func MergeAddOnDeploymentConfigSpecs(specs ...addonv1alpha1.AddOnDeploymentConfigSpec) addonv1alpha1.AddOnDeploymentConfigSpec {
+	merged := addonv1alpha1.AddOnDeploymentConfigSpec{}
+	for _, spec := range specs {
+		for _, variable := range spec.CustomizedVariables {
+			merged.CustomizedVariables = mergeCustomizedVariable(merged.CustomizedVariables, variable)
+		}
+
+		if spec.NodePlacement != nil {
+			if merged.NodePlacement == nil {
+				merged.NodePlacement = &addonv1alpha1.NodePlacement{}
+			}
+			for key, value := range spec.NodePlacement.NodeSelector {
+				if merged.NodePlacement.NodeSelector == nil {
+					merged.NodePlacement.NodeSelector = map[string]string{}
+				}
+				merged.NodePlacement.NodeSelector[key] = value
+			}
+			for _, toleration := range spec.NodePlacement.Tolerations {
+				found := false
+				for _, existing := range merged.NodePlacement.Tolerations {
+					if equality.Semantic.DeepEqual(existing, toleration) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					merged.NodePlacement.Tolerations = append(merged.NodePlacement.Tolerations, toleration)
+				}
+			}
+		}
+
+		for _, registry := range spec.Registries {
+			merged.Registries = mergeImageMirror(merged.Registries, registry)
+		}
+
+		if len(spec.ProxyConfig.HTTPProxy) > 0 {
+			merged.ProxyConfig.HTTPProxy = spec.ProxyConfig.HTTPProxy
+		}
+		if len(spec.ProxyConfig.HTTPSProxy) > 0 {
+			merged.ProxyConfig.HTTPSProxy = spec.ProxyConfig.HTTPSProxy
+		}
+		if len(spec.ProxyConfig.NoProxy) > 0 {
+			merged.ProxyConfig.NoProxy = spec.ProxyConfig.NoProxy
+		}
+
+		if len(spec.AgentInstallNamespace) > 0 {
+			merged.AgentInstallNamespace = spec.AgentInstallNamespace
+		}
+	}
+	return merged
+}
+
+func mergeCustomizedVariable(
+	variables []addonv1alpha1.CustomizedVariable, variable addonv1alpha1.CustomizedVariable) []addonv1alpha1.CustomizedVariable {
+	for i := range variables {
+		if variables[i].Name == variable.Name {
+			variables[i].Value = variable.Value
+			return variables
+		}
+	}
+	return append(variables, variable)
+}
+
+func mergeImageMirror(registries []addonv1alpha1.ImageMirror, registry addonv1alpha1.ImageMirror) []addonv1alpha1.ImageMirror {
+	for i := range registries {
+		if registries[i].Source == registry.Source {
+			registries[i].Mirror = registry.Mirror
+			return registries
+		}
+	}
+	return append(registries, registry)
+}
@@ -0,0 +1,264 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+)
+
+func TestMergeAddOnDeploymentConfigSpecs(t *testing.T) {
+	tests := []struct {
+		name     string
+		specs    []addonv1alpha1.AddOnDeploymentConfigSpec
+		expected addonv1alpha1.AddOnDeploymentConfigSpec
+	}{
+		{
+			name:     "no spec",
+			expected: addonv1alpha1.AddOnDeploymentConfigSpec{},
+		},
+		{
+			name: "customized variables",
+			specs: []addonv1alpha1.AddOnDeploymentConfigSpec{
+				{CustomizedVariables: []addonv1alpha1.CustomizedVariable{{Name: "a", Value: "1"}, {Name: "b", Value: "1"}}},
+				{CustomizedVariables: []addonv1alpha1.CustomizedVariable{{Name: "c", Value: "2"}, {Name: "a", Value: "2"}}},
+			},
+			expected: addonv1alpha1.AddOnDeploymentConfigSpec{
+				CustomizedVariables: []addonv1alpha1.CustomizedVariable{
+					{Name: "a", Value: "2"}, {Name: "b", Value: "1"}, {Name: "c", Value: "2"}},
+			},
+		},
+		{
+			name: "node placement",
+			specs: []addonv1alpha1.AddOnDeploymentConfigSpec{
+				{NodePlacement: &addonv1alpha1.NodePlacement{
+					NodeSelector: map[string]string{"zone": "a", "disk": "ssd"},
+					Tolerations:  []corev1.Toleration{{Key: "foo", Operator: corev1.TolerationOpExists}},
+				}},
+				{},
+				{NodePlacement: &addonv1alpha1.NodePlacement{
+					NodeSelector: map[string]string{"zone": "b"},
+					Tolerations: []corev1.Toleration{
+						{Key: "foo", Operator: corev1.TolerationOpExists},
+						{Key: "bar", Operator: corev1.TolerationOpExists},
+					},
+				}},
+			},
+			expected: addonv1alpha1.AddOnDeploymentConfigSpec{
+				NodePlacement: &addonv1alpha1.NodePlacement{
+					NodeSelector: map[string]string{"zone": "b", "disk": "ssd"},
+					Tolerations: []corev1.Toleration{
+						{Key: "foo", Operator: corev1.TolerationOpExists},
+						{Key: "bar", Operator: corev1.TolerationOpExists},
+					},
+				},
+			},
+		},
+		{
+			name: "registries, proxy config and install namespace",
+			specs: []addonv1alpha1.AddOnDeploymentConfigSpec{
+				{
+					Registries: []addonv1alpha1.ImageMirror{
+						{Source: "quay.io/a", Mirror: "mirror.io/a"}, {Source: "quay.io/b", Mirror: "mirror.io/b"}},
+					ProxyConfig:           addonv1alpha1.ProxyConfig{HTTPProxy: "http://proxy1", NoProxy: "localhost"},
+					AgentInstallNamespace: "ns1",
+				},
+				{
+					Registries:            []addonv1alpha1.ImageMirror{{Source: "quay.io/b", Mirror: "other.io/b"}},
+					ProxyConfig:           addonv1alpha1.ProxyConfig{HTTPProxy: "http://proxy2", HTTPSProxy: "https://proxy2"},
+					AgentInstallNamespace: "ns2",
+				},
+			},
+			expected: addonv1alpha1.AddOnDeploymentConfigSpec{
+				Registries: []addonv1alpha1.ImageMirror{
+					{Source: "quay.io/a", Mirror: "mirror.io/a"}, {Source: "quay.io/b", Mirror: "other.io/b"}},
+				ProxyConfig: addonv1alpha1.ProxyConfig{
+					HTTPProxy: "http://proxy2", HTTPSProxy: "https://proxy2", NoProxy: "localhost"},
+				AgentInstallNamespace: "ns2",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := MergeAddOnDeploymentConfigSpecs(tt.specs...)
+			if !equality.Semantic.DeepEqual(merged, tt.expected) {
+				t.Errorf("expected %v, but got %v", tt.expected, merged)
+			}
+		})
+	}
+}
+
+type fakeAddOnDeploymentConfigGetter map[string]*addonv1alpha1.AddOnDeploymentConfig
+
+func (g fakeAddOnDeploymentConfigGetter) Get(
+	_ context.Context, namespace, name string) (*addonv1alpha1.AddOnDeploymentConfig, error) {
+	config, ok := g[namespace+"/"+name]
+	if !ok {
+		return nil, fmt.Errorf("%s/%s not found", namespace, name)
+	}
+	return config, nil
+}
+
+func TestGetEffectiveAddOnDeploymentConfig(t *testing.T) {
+	getter := fakeAddOnDeploymentConfigGetter{
+		"ns/global": {Spec: addonv1alpha1.AddOnDeploymentConfigSpec{
+			CustomizedVariables: []addonv1alpha1.CustomizedVariable{{Name: "a", Value: "global"}, {Name: "b", Value: "global"}},
+		}},
+		"cluster1/override": {Spec: addonv1alpha1.AddOnDeploymentConfigSpec{
+			CustomizedVariables: []addonv1alpha1.CustomizedVariable{{Name: "a", Value: "override"}},
+		}},
+	}
+	newConfigReference := func(gr addonv1alpha1.ConfigGroupResource, namespace, name string) addonv1alpha1.ConfigReference {
+		referent := addonv1alpha1.ConfigReferent{Namespace: namespace, Name: name}
+		return addonv1alpha1.ConfigReference{
+			ConfigGroupResource: gr,
+			ConfigReferent:      referent,
+			DesiredConfig:       &addonv1alpha1.ConfigSpecHash{ConfigReferent: referent},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		configs   []addonv1alpha1.ConfigReference
+		expected  *addonv1alpha1.AddOnDeploymentConfigSpec
+		expectErr bool
+	}{
+		{
+			name: "no addon deployment config",
+			configs: []addonv1alpha1.ConfigReference{
+				newConfigReference(addonv1alpha1.ConfigGroupResource{Group: "core", Resource: "Foo"}, "ns", "global"),
+			},
+		},
+		{
+			name: "merged in order",
+			configs: []addonv1alpha1.ConfigReference{
+				newConfigReference(AddOnDeploymentConfigGroupResource, "ns", "global"),
+				newConfigReference(AddOnDeploymentConfigGroupResource, "cluster1", "override"),
+			},
+			expected: &addonv1alpha1.AddOnDeploymentConfigSpec{
+				CustomizedVariables: []addonv1alpha1.CustomizedVariable{{Name: "a", Value: "override"}, {Name: "b", Value: "global"}},
+			},
+		},
+		{
+			name: "config not found",
+			configs: []addonv1alpha1.ConfigReference{
+				newConfigReference(AddOnDeploymentConfigGroupResource, "ns", "global"),
+				newConfigReference(AddOnDeploymentConfigGroupResource, "ns", "missing"),
+			},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addon := &addonv1alpha1.ManagedClusterAddOn{}
+			addon.Status.ConfigReferences = tt.configs
+			spec, err := GetEffectiveAddOnDeploymentConfig(context.TODO(), addon, getter)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("expected error %v, but got %v", tt.expectErr, err)
+			}
+			if !equality.Semantic.DeepEqual(spec, tt.expected) {
+				t.Errorf("expected %v, but got %v", tt.expected, spec)
+			}
+		})
+	}
+}
+
+func TestGetEffectiveAgentWorkloadOverrides(t *testing.T) {
+	newConfig := func(annotations map[string]string) *addonv1alpha1.AddOnDeploymentConfig {
+		config := &addonv1alpha1.AddOnDeploymentConfig{}
+		config.Annotations = annotations
+		return config
+	}
+	getter := fakeAddOnDeploymentConfigGetter{
+		"ns/none": newConfig(nil),
+		"ns/datacenter": newConfig(map[string]string{
+			AgentResourceRequirementsAnnotationKey: `{"*":{"requests":{"cpu":"100m"}},"manager":{"limits":{"memory":"1Gi"}}}`,
+			AgentReplicasAnnotationKey:             "3",
+			AgentPriorityClassNameAnnotationKey:    "system-cluster-critical",
+		}),
+		"cluster1/edge": newConfig(map[string]string{
+			AgentResourceRequirementsAnnotationKey: `{"manager":{"limits":{"memory":"128Mi"}}}`,
+			AgentReplicasAnnotationKey:             "1",
+		}),
+		"ns/invalid-replicas":  newConfig(map[string]string{AgentReplicasAnnotationKey: "-1"}),
+		"ns/invalid-resources": newConfig(map[string]string{AgentResourceRequirementsAnnotationKey: "100m"}),
+	}
+	newConfigReference := func(namespace, name string) addonv1alpha1.ConfigReference {
+		return addonv1alpha1.ConfigReference{
+			ConfigGroupResource: AddOnDeploymentConfigGroupResource,
+			ConfigReferent:      addonv1alpha1.ConfigReferent{Namespace: namespace, Name: name},
+		}
+	}
+	replicas := func(r int32) *int32 { return &r }
+
+	tests := []struct {
+		name      string
+		configs   []addonv1alpha1.ConfigReference
+		expected  *AgentWorkloadOverrides
+		expectErr bool
+	}{
+		{
+			name: "no addon deployment config",
+		},
+		{
+			name:    "no annotations",
+			configs: []addonv1alpha1.ConfigReference{newConfigReference("ns", "none")},
+		},
+		{
+			name:    "single config",
+			configs: []addonv1alpha1.ConfigReference{newConfigReference("ns", "datacenter")},
+			expected: &AgentWorkloadOverrides{
+				ResourceRequirements: map[string]corev1.ResourceRequirements{
+					"*":       {Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}},
+					"manager": {Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}},
+				},
+				Replicas:          replicas(3),
+				PriorityClassName: "system-cluster-critical",
+			},
+		},
+		{
+			name: "merged in order",
+			configs: []addonv1alpha1.ConfigReference{
+				newConfigReference("ns", "datacenter"),
+				newConfigReference("ns", "none"),
+				newConfigReference("cluster1", "edge"),
+			},
+			expected: &AgentWorkloadOverrides{
+				ResourceRequirements: map[string]corev1.ResourceRequirements{
+					"*":       {Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}},
+					"manager": {Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")}},
+				},
+				Replicas:          replicas(1),
+				PriorityClassName: "system-cluster-critical",
+			},
+		},
+		{
+			name:      "invalid replicas",
+			configs:   []addonv1alpha1.ConfigReference{newConfigReference("ns", "invalid-replicas")},
+			expectErr: true,
+		},
+		{
+			name:      "invalid resource requirements",
+			configs:   []addonv1alpha1.ConfigReference{newConfigReference("ns", "invalid-resources")},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addon := &addonv1alpha1.ManagedClusterAddOn{}
+			addon.Status.ConfigReferences = tt.configs
+			overrides, err := GetEffectiveAgentWorkloadOverrides(context.TODO(), addon, getter)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("expected error %v, but got %v", tt.expectErr, err)
+			}
+			if !equality.Semantic.DeepEqual(overrides, tt.expected) {
+				t.Errorf("expected %v, but got %v", tt.expected, overrides)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package helpers
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// CertRenewalJitter returns a factor in [0, 1) derived from the data of a certificate. It is used to spread the
+// renewals of the certificates issued at the same time, e.g. on the installation, over a range of their lifetime.
+// Unlike a random jitter drawn on each sync, the factor of a certificate does not change across the syncs and the
+// restarts, so its renewal time is stable, and the renewal times of the certificates are evenly distributed.
+func CertRenewalJitter(certData []byte) float64 {
+	h := fnv.New32a()
+	_, _ = h.Write(certData)
+	return float64(h.Sum32()) / (math.MaxUint32 + 1)
+}
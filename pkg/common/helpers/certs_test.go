@@ -0,0 +1,27 @@
+package helpers
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCertRenewalJitter(t *testing.T) {
+	if CertRenewalJitter([]byte("cert")) != CertRenewalJitter([]byte("cert")) {
+		t.Errorf("expected the same jitter of the same certificate")
+	}
+
+	// the jitters of the certificates should be in [0, 1) and spread over the range.
+	buckets := make([]int, 10)
+	for i := 0; i < 1000; i++ {
+		jitter := CertRenewalJitter([]byte(fmt.Sprintf("cert-%d", i)))
+		if jitter < 0 || jitter >= 1 {
+			t.Fatalf("expected jitter in [0, 1), but got %v", jitter)
+		}
+		buckets[int(jitter*10)]++
+	}
+	for i, count := range buckets {
+		if count == 0 {
+			t.Errorf("expected jitters in [%.1f, %.1f), but got none", float64(i)/10, float64(i+1)/10)
+		}
+	}
+}
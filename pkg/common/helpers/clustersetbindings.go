@@ -0,0 +1,85 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+)
+
+const (
+	// ClusterSetBindingBoundByAnnotationKey is the annotation on the ManagedClusterSetBinding recording the user
+	// who created it. It is set by the webhook and is used to check if the user is still allowed to bind the
+	// ManagedClusterSet.
+	ClusterSetBindingBoundByAnnotationKey = "cluster.open-cluster-management.io/bound-by"
+
+	// ClusterSetBindingConditionClusterSetEmpty is the condition type of the ManagedClusterSetBinding, it is true
+	// if the bound ManagedClusterSet has no ManagedCluster.
+	ClusterSetBindingConditionClusterSetEmpty = "ClusterSetEmpty"
+
+	// ClusterSetBindingConditionPermissionDenied is the condition type of the ManagedClusterSetBinding, it is true
+	// if the user who created the binding is not allowed to bind the ManagedClusterSet anymore. The placements
+	// ignore the bindings with this condition.
+	ClusterSetBindingConditionPermissionDenied = "PermissionDenied"
+
+	// ClusterSetBindingWorkAuthorGroupsAnnotationKey is the annotation on the ManagedClusterSetBinding whose value
+	// is the comma-separated groups allowed to author the ManifestWorks in the namespaces of the clusters in the
+	// bound ManagedClusterSet. The hub provisions the roles and the rolebindings of the groups in the namespaces.
+	ClusterSetBindingWorkAuthorGroupsAnnotationKey = "cluster.open-cluster-management.io/work-author-groups"
+
+	// ClusterSetBindingConditionWorkAuthorRolesProvisioned is the condition type of the ManagedClusterSetBinding
+	// with the work-author-groups annotation, it is true if the roles of the groups are provisioned.
+	ClusterSetBindingConditionWorkAuthorRolesProvisioned = "WorkAuthorRolesProvisioned"
+)
+
+// GetClusterSetBindingWorkAuthorGroups returns the sorted groups allowed to author the ManifestWorks by the
+// ManagedClusterSetBinding.
+func GetClusterSetBindingWorkAuthorGroups(binding *clusterv1beta2.ManagedClusterSetBinding) []string {
+	groups := sets.New[string]()
+	for _, group := range strings.Split(binding.GetAnnotations()[ClusterSetBindingWorkAuthorGroupsAnnotationKey], ",") {
+		if group = strings.TrimSpace(group); len(group) > 0 {
+			groups.Insert(group)
+		}
+	}
+	return sets.List(groups)
+}
+
+// GetClusterSetBindingBoundBy returns the user who created the ManagedClusterSetBinding, it returns nil if the
+// binding does not have the bound-by annotation.
+func GetClusterSetBindingBoundBy(binding *clusterv1beta2.ManagedClusterSetBinding) (*authenticationv1.UserInfo, error) {
+	value, ok := binding.GetAnnotations()[ClusterSetBindingBoundByAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	userInfo := &authenticationv1.UserInfo{}
+	if err := json.Unmarshal([]byte(value), userInfo); err != nil {
+		return nil, fmt.Errorf("invalid value of annotation %s: %v", ClusterSetBindingBoundByAnnotationKey, err)
+	}
+	return userInfo, nil
+}
+
+// SetClusterSetBindingBoundBy records the user who created the ManagedClusterSetBinding. The extra info of the
+// user is not recorded.
+func SetClusterSetBindingBoundBy(binding *clusterv1beta2.ManagedClusterSetBinding, userInfo authenticationv1.UserInfo) error {
+	data, err := json.Marshal(authenticationv1.UserInfo{
+		Username: userInfo.Username,
+		UID:      userInfo.UID,
+		Groups:   userInfo.Groups,
+	})
+	if err != nil {
+		return err
+	}
+
+	annotations := binding.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ClusterSetBindingBoundByAnnotationKey] = string(data)
+	binding.SetAnnotations(annotations)
+	return nil
+}
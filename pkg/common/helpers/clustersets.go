@@ -0,0 +1,146 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+)
+
+const (
+	// NestedClusterSetsAnnotationKey is the annotation on the ManagedClusterSet whose value is the comma-separated
+	// names of the ManagedClusterSets nested in it. The clusters of the nested sets are members of the set as well,
+	// so a hierarchy, e.g. site -> region -> global, is composed without labeling the clusters for each level.
+	NestedClusterSetsAnnotationKey = "cluster.open-cluster-management.io/nested-clustersets"
+
+	// ClusterSetConditionNestedClusterSetsValid is the condition type of the ManagedClusterSet with nested sets,
+	// it is false if the nested sets form a cycle.
+	ClusterSetConditionNestedClusterSetsValid = "NestedClusterSetsValid"
+)
+
+// ManagedClusterSetGetter gets the ManagedClusterSets by name.
+type ManagedClusterSetGetter interface {
+	Get(name string) (*clusterv1beta2.ManagedClusterSet, error)
+	List(selector labels.Selector) ([]*clusterv1beta2.ManagedClusterSet, error)
+}
+
+// GetNestedClusterSetNames returns the names of the ManagedClusterSets nested in the set directly.
+func GetNestedClusterSetNames(clusterSet *clusterv1beta2.ManagedClusterSet) []string {
+	var names []string
+	for _, name := range strings.Split(clusterSet.Annotations[NestedClusterSetsAnnotationKey], ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// GetClustersFromNestedClusterSet returns the effective members of the ManagedClusterSet, which are the clusters
+// selected by the set itself and by its nested sets recursively. The nested sets which do not exist are ignored,
+// and each set is only visited once, so the members are still computed if the nested sets form a cycle.
+func GetClustersFromNestedClusterSet(clusterSet *clusterv1beta2.ManagedClusterSet,
+	clusterGetter clusterv1beta2.ManagedClustersGetter, clusterSetGetter ManagedClusterSetGetter) ([]*clusterv1.ManagedCluster, error) {
+	members := map[string]*clusterv1.ManagedCluster{}
+	visited := sets.New[string]()
+	queue := []*clusterv1beta2.ManagedClusterSet{clusterSet}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if visited.Has(current.Name) {
+			continue
+		}
+		visited.Insert(current.Name)
+
+		clusters, err := clusterv1beta2.GetClustersFromClusterSet(current, clusterGetter)
+		if err != nil {
+			return nil, err
+		}
+		for _, cluster := range clusters {
+			members[cluster.Name] = cluster
+		}
+
+		for _, name := range GetNestedClusterSetNames(current) {
+			nested, err := clusterSetGetter.Get(name)
+			if errors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			queue = append(queue, nested)
+		}
+	}
+
+	names := sets.List(sets.KeySet(members))
+	clusters := make([]*clusterv1.ManagedCluster, 0, len(names))
+	for _, name := range names {
+		clusters = append(clusters, members[name])
+	}
+	return clusters, nil
+}
+
+// ValidateNestedClusterSets returns an error describing the cycle if the ManagedClusterSet is nested in itself
+// directly or indirectly.
+func ValidateNestedClusterSets(clusterSet *clusterv1beta2.ManagedClusterSet, clusterSetGetter ManagedClusterSetGetter) error {
+	var visit func(current *clusterv1beta2.ManagedClusterSet, path []string) error
+	visited := sets.New[string]()
+	visit = func(current *clusterv1beta2.ManagedClusterSet, path []string) error {
+		path = append(path, current.Name)
+		for _, name := range GetNestedClusterSetNames(current) {
+			if name == clusterSet.Name {
+				return fmt.Errorf("the nested ManagedClusterSets form a cycle: %s", strings.Join(append(path, name), " -> "))
+			}
+			if visited.Has(name) {
+				continue
+			}
+			visited.Insert(name)
+			nested, err := clusterSetGetter.Get(name)
+			if errors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := visit(nested, path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return visit(clusterSet, nil)
+}
+
+// GetAncestorClusterSetNames returns the sorted names of the ManagedClusterSets the set is nested in directly or
+// indirectly.
+func GetAncestorClusterSetNames(clusterSetName string, clusterSetGetter ManagedClusterSetGetter) ([]string, error) {
+	clusterSets, err := clusterSetGetter.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	parents := map[string][]string{}
+	for _, clusterSet := range clusterSets {
+		for _, name := range GetNestedClusterSetNames(clusterSet) {
+			parents[name] = append(parents[name], clusterSet.Name)
+		}
+	}
+
+	ancestors := sets.New[string]()
+	queue := []string{clusterSetName}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, parent := range parents[current] {
+			if parent == clusterSetName || ancestors.Has(parent) {
+				continue
+			}
+			ancestors.Insert(parent)
+			queue = append(queue, parent)
+		}
+	}
+	return sets.List(ancestors), nil
+}
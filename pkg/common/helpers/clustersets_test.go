@@ -0,0 +1,149 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fakecluster "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterv1informers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+)
+
+func newNestedClusterSet(name, nestedClusterSets string) *clusterv1beta2.ManagedClusterSet {
+	clusterSet := &clusterv1beta2.ManagedClusterSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}
+	if len(nestedClusterSets) > 0 {
+		clusterSet.Annotations = map[string]string{NestedClusterSetsAnnotationKey: nestedClusterSets}
+	}
+	return clusterSet
+}
+
+func newClusterSetMember(name, clusterSetName string) *clusterv1.ManagedCluster {
+	return &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{clusterv1beta2.ClusterSetLabel: clusterSetName},
+		},
+	}
+}
+
+func TestNestedClusterSets(t *testing.T) {
+	cases := []struct {
+		name              string
+		clusterSets       []*clusterv1beta2.ManagedClusterSet
+		clusterSet        string
+		expectedClusters  []string
+		expectedAncestors []string
+		expectedErr       string
+	}{
+		{
+			name: "no nested clustersets",
+			clusterSets: []*clusterv1beta2.ManagedClusterSet{
+				newNestedClusterSet("site1", ""),
+			},
+			clusterSet:       "site1",
+			expectedClusters: []string{"cluster1"},
+		},
+		{
+			name: "nested clustersets",
+			clusterSets: []*clusterv1beta2.ManagedClusterSet{
+				newNestedClusterSet("global", "region1, missing"),
+				newNestedClusterSet("region1", "site1,site2"),
+				newNestedClusterSet("site1", ""),
+				newNestedClusterSet("site2", ""),
+			},
+			clusterSet:       "global",
+			expectedClusters: []string{"cluster1", "cluster2", "cluster3"},
+		},
+		{
+			name: "ancestors of the nested clusterset",
+			clusterSets: []*clusterv1beta2.ManagedClusterSet{
+				newNestedClusterSet("global", "region1"),
+				newNestedClusterSet("region1", "site1"),
+				newNestedClusterSet("site1", ""),
+				newNestedClusterSet("site2", ""),
+			},
+			clusterSet:        "site1",
+			expectedClusters:  []string{"cluster1"},
+			expectedAncestors: []string{"global", "region1"},
+		},
+		{
+			name: "nested clustersets in a cycle",
+			clusterSets: []*clusterv1beta2.ManagedClusterSet{
+				newNestedClusterSet("global", "region1"),
+				newNestedClusterSet("region1", "site1"),
+				newNestedClusterSet("site1", "global"),
+			},
+			clusterSet:        "global",
+			expectedClusters:  []string{"cluster1", "cluster3"},
+			expectedAncestors: []string{"region1", "site1"},
+			expectedErr:       "the nested ManagedClusterSets form a cycle: global -> region1 -> site1 -> global",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusterClient := fakecluster.NewSimpleClientset()
+			informerFactory := clusterv1informers.NewSharedInformerFactory(clusterClient, 10*time.Minute)
+			clusterStore := informerFactory.Cluster().V1().ManagedClusters().Informer().GetStore()
+			for _, cluster := range []*clusterv1.ManagedCluster{
+				newClusterSetMember("cluster1", "site1"),
+				newClusterSetMember("cluster2", "site2"),
+				newClusterSetMember("cluster3", "region1"),
+				newClusterSetMember("cluster4", "others"),
+			} {
+				if err := clusterStore.Add(cluster); err != nil {
+					t.Fatal(err)
+				}
+			}
+			clusterSetStore := informerFactory.Cluster().V1beta2().ManagedClusterSets().Informer().GetStore()
+			for _, clusterSet := range c.clusterSets {
+				if err := clusterSetStore.Add(clusterSet); err != nil {
+					t.Fatal(err)
+				}
+			}
+			clusterLister := informerFactory.Cluster().V1().ManagedClusters().Lister()
+			clusterSetLister := informerFactory.Cluster().V1beta2().ManagedClusterSets().Lister()
+
+			clusterSet, err := clusterSetLister.Get(c.clusterSet)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			clusters, err := GetClustersFromNestedClusterSet(clusterSet, clusterLister, clusterSetLister)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var clusterNames []string
+			for _, cluster := range clusters {
+				clusterNames = append(clusterNames, cluster.Name)
+			}
+			if !reflect.DeepEqual(clusterNames, c.expectedClusters) {
+				t.Errorf("expected clusters %v, but got %v", c.expectedClusters, clusterNames)
+			}
+
+			ancestors, err := GetAncestorClusterSetNames(c.clusterSet, clusterSetLister)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ancestors) != 0 || len(c.expectedAncestors) != 0 {
+				if !reflect.DeepEqual(ancestors, c.expectedAncestors) {
+					t.Errorf("expected ancestors %v, but got %v", c.expectedAncestors, ancestors)
+				}
+			}
+
+			err = ValidateNestedClusterSets(clusterSet, clusterSetLister)
+			switch {
+			case len(c.expectedErr) == 0 && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case len(c.expectedErr) > 0 && (err == nil || err.Error() != c.expectedErr):
+				t.Errorf("expected error %q, but got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	kevents "k8s.io/client-go/tools/events"
+
+	addonscheme "open-cluster-management.io/api/client/addon/clientset/versioned/scheme"
+	clusterscheme "open-cluster-management.io/api/client/cluster/clientset/versioned/scheme"
+	workscheme "open-cluster-management.io/api/client/work/clientset/versioned/scheme"
+)
+
+// The reasons of the events emitted regarding the objects when their lifecycle transitions, they are kept in one
+// place so the events of the components are consistent and could be filtered by the reasons, e.g.
+// "kubectl get events --field-selector reason=ManagedClusterAccepted".
+const (
+	EventReasonManagedClusterAccepted   = "ManagedClusterAccepted"
+	EventReasonManagedClusterDenied     = "ManagedClusterDenied"
+	EventReasonManifestWorkApplied      = "ManifestWorkApplied"
+	EventReasonManifestWorkApplyFailed  = "ManifestWorkApplyFailed"
+	EventReasonAddOnInstalled           = "AddOnInstalled"
+	EventReasonAddOnUpgraded            = "AddOnUpgraded"
+	EventReasonPlacementDecisionChanged = "PlacementDecisionChanged"
+	EventReasonRebootstrapTriggered     = "RebootstrapTriggered"
+)
+
+// The actions of the events emitted regarding the objects.
+const (
+	EventActionAccept    = "Accept"
+	EventActionDeny      = "Deny"
+	EventActionApply     = "Apply"
+	EventActionInstall   = "Install"
+	EventActionUpgrade   = "Upgrade"
+	EventActionSchedule  = "Schedule"
+	EventActionBootstrap = "Bootstrap"
+)
+
+// eventScheme is used to build the references of the objects in the events, the objects from the listers do not
+// have the type meta, so their kinds are resolved by the scheme.
+var eventScheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clusterscheme.AddToScheme(eventScheme))
+	utilruntime.Must(workscheme.AddToScheme(eventScheme))
+	utilruntime.Must(addonscheme.AddToScheme(eventScheme))
+}
+
+// NewEventRecorder returns a recorder which emits the events regarding the API objects of ocm, e.g. ManagedCluster,
+// ManifestWork and ManagedClusterAddOn. Unlike the recorder of the controller context whose events are regarding
+// the deployment of the component, the events are listed with the objects, for example by "kubectl describe". The
+// events are sent until the ctx is done.
+func NewEventRecorder(ctx context.Context, kubeClient kubernetes.Interface, component string) kevents.EventRecorder {
+	broadcaster := kevents.NewBroadcaster(&kevents.EventSinkImpl{Interface: kubeClient.EventsV1()})
+	broadcaster.StartRecordingToSink(ctx.Done())
+	return broadcaster.NewRecorder(eventScheme, component)
+}
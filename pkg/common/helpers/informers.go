@@ -0,0 +1,98 @@
+package helpers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DefaultListPageSize is the chunk size used by the informers to list resources from the apiserver.
+const DefaultListPageSize int64 = 500
+
+// TrimManagedFields is an informer transform func which drops the managedFields of an object before
+// it is stored in the informer cache. The managedFields are not used by the controllers and they can
+// take a big part of the cache memory when a large number of objects are watched.
+func TrimManagedFields(obj interface{}) (interface{}, error) {
+	// tombstones are passed through, their object has been trimmed when it was added to the cache
+	if _, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		return obj, nil
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		// the object does not have object meta, return it as is
+		return obj, nil
+	}
+	accessor.SetManagedFields(nil)
+	return obj, nil
+}
+
+// TrimManagedFieldsAndLastApplied is an informer transform func which drops the last-applied-configuration
+// annotation set by "kubectl apply" in addition to the managedFields, the annotation holds a full copy of the
+// object when it is applied by kubectl. It must only be set on the informers whose objects are not updated by the
+// controllers with the Update API, otherwise the annotation is removed from the objects by the updates.
+func TrimManagedFieldsAndLastApplied(obj interface{}) (interface{}, error) {
+	obj, err := TrimManagedFields(obj)
+	if err != nil {
+		return obj, err
+	}
+	if _, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		return obj, nil
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return obj, nil
+	}
+	annotations := accessor.GetAnnotations()
+	if _, ok := annotations[corev1.LastAppliedConfigAnnotation]; ok {
+		delete(annotations, corev1.LastAppliedConfigAnnotation)
+		accessor.SetAnnotations(annotations)
+	}
+	return obj, nil
+}
+
+// SetInformerTransform sets the transform func on the informers, so the objects are trimmed before they are stored
+// in the caches. It must be called before the informers are started.
+//
+// The managedFields, and the last-applied annotation set by "kubectl apply", are not used by the controllers, and
+// they can take a big part of the cache memory. TrimManagedFieldsAndLastApplied should be used for the informers
+// whose objects are not updated by the controllers, and TrimManagedFields for the others.
+func SetInformerTransform(transform cache.TransformFunc, informers ...cache.SharedInformer) error {
+	for _, informer := range informers {
+		if err := informer.SetTransform(transform); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PaginatedListOptions returns a tweak list options func which requests the list in chunks of the
+// given page size in addition to the given tweak funcs.
+func PaginatedListOptions(pageSize int64, tweaks ...func(*metav1.ListOptions)) func(*metav1.ListOptions) {
+	return func(listOptions *metav1.ListOptions) {
+		for _, tweak := range tweaks {
+			tweak(listOptions)
+		}
+		if listOptions.Limit == 0 {
+			listOptions.Limit = pageSize
+		}
+	}
+}
+
+// LabelExistsListOptions returns a tweak list options func which only lists the resources with the
+// given label key.
+func LabelExistsListOptions(key string) func(*metav1.ListOptions) {
+	return func(listOptions *metav1.ListOptions) {
+		selector := &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      key,
+					Operator: metav1.LabelSelectorOpExists,
+				},
+			},
+		}
+		listOptions.LabelSelector = metav1.FormatLabelSelector(selector)
+	}
+}
@@ -0,0 +1,104 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+func TestTrimManagedFields(t *testing.T) {
+	work := &workapiv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "work1",
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "test"}},
+		},
+	}
+
+	obj, err := TrimManagedFields(work)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(obj.(*workapiv1.ManifestWork).ManagedFields) != 0 {
+		t.Errorf("expected managedFields to be trimmed, but got %v", obj.(*workapiv1.ManifestWork).ManagedFields)
+	}
+
+	tombstone := cache.DeletedFinalStateUnknown{Key: "work1", Obj: work}
+	obj, err = TrimManagedFields(tombstone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := obj.(cache.DeletedFinalStateUnknown); !ok {
+		t.Errorf("expected tombstone to be returned, but got %T", obj)
+	}
+
+	obj, err = TrimManagedFields("invalid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj != "invalid" {
+		t.Errorf("expected object to be returned as is, but got %v", obj)
+	}
+}
+
+func TestPaginatedListOptions(t *testing.T) {
+	listOptions := &metav1.ListOptions{}
+	PaginatedListOptions(100, LabelExistsListOptions("test"))(listOptions)
+	if listOptions.Limit != 100 {
+		t.Errorf("expected limit 100, but got %d", listOptions.Limit)
+	}
+	if listOptions.LabelSelector != "test" {
+		t.Errorf("expected label selector test, but got %q", listOptions.LabelSelector)
+	}
+
+	listOptions = &metav1.ListOptions{Limit: 10}
+	PaginatedListOptions(100)(listOptions)
+	if listOptions.Limit != 10 {
+		t.Errorf("expected limit 10, but got %d", listOptions.Limit)
+	}
+}
+
+func TestTrimManagedFieldsAndLastApplied(t *testing.T) {
+	work := &workapiv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "work1",
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "test"}},
+			Annotations: map[string]string{
+				corev1.LastAppliedConfigAnnotation: "{}",
+				"test":                             "true",
+			},
+		},
+	}
+
+	obj, err := TrimManagedFieldsAndLastApplied(work)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trimmed := obj.(*workapiv1.ManifestWork)
+	if len(trimmed.ManagedFields) != 0 {
+		t.Errorf("expected managedFields to be trimmed, but got %v", trimmed.ManagedFields)
+	}
+	if !reflect.DeepEqual(trimmed.Annotations, map[string]string{"test": "true"}) {
+		t.Errorf("expected last-applied annotation to be trimmed, but got %v", trimmed.Annotations)
+	}
+
+	tombstone := cache.DeletedFinalStateUnknown{Key: "work1", Obj: work}
+	obj, err = TrimManagedFieldsAndLastApplied(tombstone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := obj.(cache.DeletedFinalStateUnknown); !ok {
+		t.Errorf("expected tombstone to be returned, but got %T", obj)
+	}
+}
+
+func TestSetInformerTransform(t *testing.T) {
+	informer := cache.NewSharedIndexInformer(&cache.ListWatch{}, &workapiv1.ManifestWork{}, 0, cache.Indexers{})
+	if err := SetInformerTransform(TrimManagedFields, informer); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
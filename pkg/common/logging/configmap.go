@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// verbosityConfigMapResyncInterval is the interval to reload the verbosity overrides from the ConfigMap.
+const verbosityConfigMapResyncInterval = 30 * time.Second
+
+// RunVerbosityConfigMapReloader reloads the verbosity overrides of the controllers from the ConfigMap periodically
+// until the context is done. Each key of the ConfigMap data is a controller name and the value is its verbosity,
+// the overrides in the ConfigMap take precedence over the defaultLevels, e.g. the ones set by the flag. The
+// defaultLevels are used if the ConfigMap does not exist.
+func RunVerbosityConfigMapReloader(ctx context.Context, kubeClient kubernetes.Interface,
+	namespace, name string, defaultLevels map[string]int) {
+	logger := klog.FromContext(ctx)
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		levels := map[string]int{}
+		for controllerName, level := range defaultLevels {
+			levels[controllerName] = level
+		}
+
+		cm, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case errors.IsNotFound(err):
+		case err != nil:
+			logger.Error(err, "Failed to get the controller verbosity ConfigMap", "namespace", namespace, "name", name)
+			return
+		default:
+			for controllerName, value := range cm.Data {
+				level, err := strconv.Atoi(value)
+				if err != nil || level < 0 {
+					logger.Info("Ignore the invalid controller verbosity", "controller", controllerName, "verbosity", value)
+					continue
+				}
+				levels[controllerName] = level
+			}
+		}
+
+		SetControllerVerbosity(levels)
+	}, verbosityConfigMapResyncInterval)
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/spf13/pflag"
+	"k8s.io/component-base/featuregate"
+	logsapi "k8s.io/component-base/logs/api/v1"
+	// register the json log format
+	_ "k8s.io/component-base/logs/json/register"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/ocm/pkg/common/metrics"
+)
+
+// Options is the logging options of the components, it adds the flags of the kubernetes logging configuration,
+// including the "--logging-format" to switch to the json format, so the logs could be ingested by the log
+// collectors of the fleet.
+type Options struct {
+	Config *logsapi.LoggingConfiguration
+}
+
+// NewOptions returns the logging options with the default text format.
+func NewOptions() *Options {
+	return &Options{
+		Config: logsapi.NewLoggingConfiguration(),
+	}
+}
+
+// AddFlags adds the logging flags, it replaces the logs.AddFlags, so it should not be used together with it.
+func (o *Options) AddFlags(flags *pflag.FlagSet) {
+	logsapi.AddFlags(o.Config, flags)
+}
+
+// ValidateAndApply validates the logging configuration and applies it to klog. The contextual logging is always
+// enabled, so the loggers set in the context by the controllers, e.g. with the controller name and verbosity, are
+// used.
+func (o *Options) ValidateAndApply() error {
+	gate := featuregate.NewFeatureGate()
+	if err := logsapi.AddFeatureGates(gate); err != nil {
+		return err
+	}
+	if err := gate.SetFromMap(map[string]bool{string(logsapi.ContextualLogging): true}); err != nil {
+		return err
+	}
+	if err := logsapi.ValidateAndApply(o.Config, gate); err != nil {
+		return fmt.Errorf("invalid logging configuration: %w", err)
+	}
+	return nil
+}
+
+// NewControllerContext returns a context whose logger is named by the controller name, and the verbosity of the
+// logger follows the verbosity override of the controller if there is one.
+func NewControllerContext(ctx context.Context, controllerName string) context.Context {
+	logger := klog.FromContext(ctx).WithName(controllerName)
+	if logger.GetSink() == nil {
+		return klog.NewContext(ctx, logger)
+	}
+	logger = logger.WithSink(newVerbositySink(logger.GetSink(), controllerName))
+	return klog.NewContext(ctx, logger)
+}
+
+// WithControllerLogger wraps the sync func of a controller, so the logger from the context of the sync func is
+// named by the controller name and follows the verbosity override of the controller. The reconcile metrics of the
+// controller are recorded with the controller name as well.
+func WithControllerLogger(controllerName string, sync factory.SyncFunc) factory.SyncFunc {
+	return metrics.WithReconcileMetrics(controllerName, func(ctx context.Context, syncCtx factory.SyncContext) error {
+		return sync(NewControllerContext(ctx, controllerName), syncCtx)
+	})
+}
@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+// controllerVerbosity holds the verbosity overrides of the controllers, it is keyed by the controller name.
+var controllerVerbosity = &verbosityOverrides{levels: map[string]int{}}
+
+type verbosityOverrides struct {
+	lock   sync.RWMutex
+	levels map[string]int
+}
+
+func (o *verbosityOverrides) get(controllerName string) (int, bool) {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+	level, ok := o.levels[controllerName]
+	return level, ok
+}
+
+func (o *verbosityOverrides) set(levels map[string]int) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.levels = map[string]int{}
+	for name, level := range levels {
+		// negative levels would disable even the info messages, ignore them
+		if level < 0 {
+			continue
+		}
+		o.levels[name] = level
+	}
+}
+
+// SetControllerVerbosity replaces the verbosity overrides of the controllers. The loggers of a controller log the
+// messages whose verbosity is not greater than its override, regardless of the global verbosity set by "-v". It
+// could be called at runtime and the loggers already in use pick up the new overrides.
+func SetControllerVerbosity(levels map[string]int) {
+	controllerVerbosity.set(levels)
+}
+
+// verbositySink wraps the sink of a controller logger to apply the verbosity override of the controller.
+type verbositySink struct {
+	sink           logr.LogSink
+	controllerName string
+}
+
+var _ logr.LogSink = &verbositySink{}
+var _ logr.CallDepthLogSink = &verbositySink{}
+
+func newVerbositySink(sink logr.LogSink, controllerName string) logr.LogSink {
+	// skip the frame of the verbositySink when the sink logs the call site
+	if withCallDepth, ok := sink.(logr.CallDepthLogSink); ok {
+		sink = withCallDepth.WithCallDepth(1)
+	}
+	return &verbositySink{sink: sink, controllerName: controllerName}
+}
+
+// Init is a no-op since the wrapped sink has been initialized by its logger.
+func (s *verbositySink) Init(_ logr.RuntimeInfo) {}
+
+func (s *verbositySink) Enabled(level int) bool {
+	if v, ok := controllerVerbosity.get(s.controllerName); ok {
+		return level <= v
+	}
+	return s.sink.Enabled(level)
+}
+
+func (s *verbositySink) Info(level int, msg string, keysAndValues ...interface{}) {
+	// the message is enabled by the override but the wrapped sink filters it by the global verbosity, log it as
+	// an info message instead.
+	if _, ok := controllerVerbosity.get(s.controllerName); ok && !s.sink.Enabled(level) {
+		level = 0
+	}
+	s.sink.Info(level, msg, keysAndValues...)
+}
+
+func (s *verbositySink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.sink.Error(err, msg, keysAndValues...)
+}
+
+func (s *verbositySink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return &verbositySink{sink: s.sink.WithValues(keysAndValues...), controllerName: s.controllerName}
+}
+
+func (s *verbositySink) WithName(name string) logr.LogSink {
+	return &verbositySink{sink: s.sink.WithName(name), controllerName: s.controllerName}
+}
+
+func (s *verbositySink) WithCallDepth(depth int) logr.LogSink {
+	withCallDepth, ok := s.sink.(logr.CallDepthLogSink)
+	if !ok {
+		return s
+	}
+	return &verbositySink{sink: withCallDepth.WithCallDepth(depth), controllerName: s.controllerName}
+}
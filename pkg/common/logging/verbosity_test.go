@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+)
+
+func TestControllerVerbosity(t *testing.T) {
+	var messages []string
+	base := funcr.New(func(prefix, args string) {
+		messages = append(messages, fmt.Sprintf("%s %s", prefix, args))
+	}, funcr.Options{Verbosity: 2})
+
+	cases := []struct {
+		name             string
+		overrides        map[string]int
+		expectedMessages int
+	}{
+		{
+			name:             "no override",
+			expectedMessages: 2,
+		},
+		{
+			name:             "raise verbosity",
+			overrides:        map[string]int{"test": 4},
+			expectedMessages: 3,
+		},
+		{
+			name:             "lower verbosity",
+			overrides:        map[string]int{"test": 0},
+			expectedMessages: 1,
+		},
+		{
+			name:             "override of other controllers",
+			overrides:        map[string]int{"other": 4},
+			expectedMessages: 2,
+		},
+		{
+			name:             "negative override is ignored",
+			overrides:        map[string]int{"test": -1},
+			expectedMessages: 2,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			messages = nil
+			SetControllerVerbosity(c.overrides)
+			defer SetControllerVerbosity(nil)
+
+			ctx := NewControllerContext(klog.NewContext(context.TODO(), base), "test")
+			logger := klog.FromContext(ctx).WithValues("key", "value")
+			logger.Info("info")
+			logger.V(2).Info("v2")
+			logger.V(4).Info("v4")
+
+			if len(messages) != c.expectedMessages {
+				t.Errorf("expected %d messages, but got %v", c.expectedMessages, messages)
+			}
+			for _, message := range messages {
+				if !strings.HasPrefix(message, "test ") {
+					t.Errorf("expected the message named by the controller, but got %q", message)
+				}
+			}
+		})
+	}
+}
+
+func TestNewControllerContextWithoutSink(t *testing.T) {
+	ctx := NewControllerContext(klog.NewContext(context.TODO(), logr.Discard()), "test")
+	klog.FromContext(ctx).Info("should not panic")
+}
+
+func TestRunVerbosityConfigMapReloader(t *testing.T) {
+	defer SetControllerVerbosity(nil)
+
+	kubeClient := kubefake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "verbosity"},
+		Data:       map[string]string{"ManifestWorkAgent": "4", "invalid": "high"},
+	})
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	go RunVerbosityConfigMapReloader(ctx, kubeClient, "ns", "verbosity",
+		map[string]int{"ManifestWorkAgent": 2, "AvailableStatusController": 2})
+
+	err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			level, ok := controllerVerbosity.get("ManifestWorkAgent")
+			return ok && level == 4, nil
+		})
+	if err != nil {
+		t.Fatalf("expected the verbosity reloaded from the configmap, but got %v", err)
+	}
+	if level, _ := controllerVerbosity.get("AvailableStatusController"); level != 2 {
+		t.Errorf("expected the default verbosity 2, but got %d", level)
+	}
+	if _, ok := controllerVerbosity.get("invalid"); ok {
+		t.Errorf("expected the invalid verbosity ignored")
+	}
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	k8smetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const subsystem = "controller"
+
+var (
+	// reconcileDuration is the duration of the reconciles of each controller.
+	reconcileDuration = k8smetrics.NewHistogramVec(
+		&k8smetrics.HistogramOpts{
+			Subsystem:      subsystem,
+			Name:           "reconcile_duration_seconds",
+			Help:           "Duration in seconds of the reconciles of the controller.",
+			Buckets:        k8smetrics.ExponentialBuckets(0.001, 2, 15),
+			StabilityLevel: k8smetrics.ALPHA,
+		},
+		[]string{"controller"},
+	)
+
+	// reconcileErrors is the number of the reconciles of each controller returning an error.
+	reconcileErrors = k8smetrics.NewCounterVec(
+		&k8smetrics.CounterOpts{
+			Subsystem:      subsystem,
+			Name:           "reconcile_errors_total",
+			Help:           "Number of the reconciles of the controller returning an error.",
+			StabilityLevel: k8smetrics.ALPHA,
+		},
+		[]string{"controller"},
+	)
+)
+
+func init() {
+	// the controllers of all the components wrap their sync funcs, register the metrics once it is imported, so they
+	// are served on the /metrics endpoint of the components.
+	legacyregistry.MustRegister(reconcileDuration, reconcileErrors)
+}
+
+// WithReconcileMetrics wraps the sync func of a controller, so the duration and the errors of its reconciles are
+// recorded with the controller name. The synthetic requeue requests are not counted as errors.
+func WithReconcileMetrics(controllerName string, sync factory.SyncFunc) factory.SyncFunc {
+	return func(ctx context.Context, syncCtx factory.SyncContext) error {
+		start := time.Now()
+		err := sync(ctx, syncCtx)
+		reconcileDuration.WithLabelValues(controllerName).Observe(time.Since(start).Seconds())
+		if err != nil && !errors.Is(err, factory.SyntheticRequeueError) {
+			reconcileErrors.WithLabelValues(controllerName).Inc()
+		}
+		return err
+	}
+}
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+)
+
+func TestWithReconcileMetrics(t *testing.T) {
+	results := []error{nil, fmt.Errorf("failed"), factory.SyntheticRequeueError}
+	sync := WithReconcileMetrics("TestReconcileMetricsController", func(ctx context.Context, syncCtx factory.SyncContext) error {
+		err := results[0]
+		results = results[1:]
+		return err
+	})
+	for i := 0; i < 3; i++ {
+		_ = sync(context.TODO(), testingcommon.NewFakeSyncContext(t, "key"))
+	}
+
+	expected := `
+		# HELP controller_reconcile_errors_total [ALPHA] Number of the reconciles of the controller returning an error.
+		# TYPE controller_reconcile_errors_total counter
+		controller_reconcile_errors_total{controller="TestReconcileMetricsController"} 1
+	`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected),
+		"controller_reconcile_errors_total"); err != nil {
+		t.Errorf("unexpected reconcile errors metrics: %v", err)
+	}
+
+	count, err := testutil.GetHistogramMetricCount(reconcileDuration.WithLabelValues("TestReconcileMetricsController"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 reconciles recorded, but got %d", count)
+	}
+}
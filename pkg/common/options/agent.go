@@ -34,6 +34,9 @@ type AgentOptions struct {
 	HubKubeconfigDir    string
 	HubKubeconfigFile   string
 	AgentID             string
+	// FeatureGatesConfigMap is the name of the ConfigMap in the component namespace with the feature gates of the
+	// agents, which are reloaded once the ConfigMap is changed.
+	FeatureGatesConfigMap string
 }
 
 // NewAgentOptions returns the flags with default value set
@@ -63,6 +66,9 @@ func (o *AgentOptions) AddFlags(flags *pflag.FlagSet) {
 		"The mount path of hub-kubeconfig-secret in the container.")
 	flags.StringVar(&o.HubKubeconfigFile, "hub-kubeconfig", o.HubKubeconfigFile, "Location of kubeconfig file to connect to hub cluster.")
 	flags.StringVar(&o.AgentID, "agent-id", o.AgentID, "ID of the agent")
+	flags.StringVar(&o.FeatureGatesConfigMap, "feature-gates-configmap", o.FeatureGatesConfigMap,
+		"Name of the ConfigMap in the component namespace with the feature gates of the agents. If it is set, the "+
+			"feature gates in the ConfigMap are loaded on start and reloaded once the ConfigMap is changed.")
 }
 
 // SpokeKubeConfig builds kubeconfig for the spoke/managed cluster
@@ -2,38 +2,85 @@ package options
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
 	"github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes"
+	// register the workqueue metrics provider, so the depth, latency and retries of the queues of all the
+	// controllers are exposed on the metrics endpoint, labeled by the queue name which is the controller name.
+	_ "k8s.io/component-base/metrics/prometheus/workqueue"
+
+	"open-cluster-management.io/ocm/pkg/common/fips"
+	"open-cluster-management.io/ocm/pkg/common/logging"
 )
 
 type Options struct {
 	CmdConfig *controllercmd.ControllerCommandConfig
 	Burst     int
 	QPS       float32
+
+	// ControllerVerbosity is the verbosity overrides of the controllers keyed by the controller name.
+	ControllerVerbosity map[string]int
+	// ControllerVerbosityConfigMap is the name of the ConfigMap in the component namespace to override the
+	// verbosity of the controllers at runtime.
+	ControllerVerbosityConfigMap string
+
+	// EnablePprof serves the pprof endpoints on the PprofBindAddress to capture the profiles at runtime.
+	EnablePprof      bool
+	PprofBindAddress string
+
+	// FIPSMode restricts the TLS configurations and the certificates to the FIPS approved algorithms, it is enabled
+	// regardless of the flag for the components built with the "fips" build tag.
+	FIPSMode bool
 }
 
 // NewOptions returns the flags with default value set
 func NewOptions() *Options {
 	opts := &Options{
-		QPS:   50,
-		Burst: 100,
+		QPS:              50,
+		Burst:            100,
+		PprofBindAddress: defaultPprofBindAddress,
 	}
 	return opts
 }
 
 func (o *Options) NewControllerCommandConfig(
 	componentName string, version version.Info, startFunc controllercmd.StartFunc) *controllercmd.ControllerCommandConfig {
-	o.CmdConfig = controllercmd.NewControllerCommandConfig(componentName, version, o.startWithQPS(startFunc))
+	o.CmdConfig = controllercmd.NewControllerCommandConfig(componentName, version, o.startWithOptions(startFunc))
 	return o.CmdConfig
 }
 
-func (o *Options) startWithQPS(startFunc controllercmd.StartFunc) controllercmd.StartFunc {
+func (o *Options) startWithOptions(startFunc controllercmd.StartFunc) controllercmd.StartFunc {
 	return func(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
 		controllerContext.KubeConfig.QPS = o.QPS
 		controllerContext.KubeConfig.Burst = o.Burst
+
+		if o.FIPSMode {
+			fips.Enable()
+		}
+		if fips.Enabled() {
+			if err := fips.ValidateRESTConfig(controllerContext.KubeConfig); err != nil {
+				return fmt.Errorf("the kubeconfig is not valid in FIPS mode: %w", err)
+			}
+		}
+
+		logging.SetControllerVerbosity(o.ControllerVerbosity)
+		if len(o.ControllerVerbosityConfigMap) > 0 {
+			kubeClient, err := kubernetes.NewForConfig(controllerContext.KubeConfig)
+			if err != nil {
+				return err
+			}
+			go logging.RunVerbosityConfigMapReloader(ctx, kubeClient,
+				controllerContext.OperatorNamespace, o.ControllerVerbosityConfigMap, o.ControllerVerbosity)
+		}
+
+		if o.EnablePprof {
+			go runPprofServer(ctx, o.PprofBindAddress)
+		}
+
 		return startFunc(ctx, controllerContext)
 	}
 }
@@ -41,6 +88,20 @@ func (o *Options) startWithQPS(startFunc controllercmd.StartFunc) controllercmd.
 func (o *Options) AddFlags(flags *pflag.FlagSet) {
 	flags.Float32Var(&o.QPS, "kube-api-qps", o.QPS, "QPS to use while talking with apiserver on spoke cluster.")
 	flags.IntVar(&o.Burst, "kube-api-burst", o.Burst, "Burst to use while talking with apiserver on spoke cluster.")
+	flags.StringToIntVar(&o.ControllerVerbosity, "controller-verbosity", o.ControllerVerbosity,
+		"The verbosity overrides of the controllers in the format of <controller name>=<level>, e.g. "+
+			"ManifestWorkAgent=4. The logs of a controller are filtered by its override instead of -v.")
+	flags.StringVar(&o.ControllerVerbosityConfigMap, "controller-verbosity-configmap", o.ControllerVerbosityConfigMap,
+		"The name of the ConfigMap in the component namespace to override the verbosity of the controllers at "+
+			"runtime, the keys are the controller names and the values are the levels. It takes precedence over "+
+			"--controller-verbosity.")
+	flags.BoolVar(&o.EnablePprof, "enable-pprof", o.EnablePprof,
+		"Serve the pprof endpoints under /debug/pprof/ on the --pprof-bind-address to capture the profiles.")
+	flags.StringVar(&o.PprofBindAddress, "pprof-bind-address", o.PprofBindAddress,
+		"The address the pprof endpoints bind to when --enable-pprof is set.")
+	flags.BoolVar(&o.FIPSMode, "fips-mode", o.FIPSMode,
+		"Restrict the TLS configurations and the certificates to the FIPS approved algorithms and key sizes. The "+
+			"certificates of the kubeconfig are validated on startup.")
 	if o.CmdConfig != nil {
 		flags.BoolVar(&o.CmdConfig.DisableLeaderElection, "disable-leader-election", false, "Disable leader election.")
 		flags.DurationVar(&o.CmdConfig.LeaseDuration.Duration, "leader-election-lease-duration", 137*time.Second, ""+
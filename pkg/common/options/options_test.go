@@ -4,15 +4,19 @@ import (
 	"context"
 	"os"
 	"path"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
+	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events/eventstesting"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
 
 	"open-cluster-management.io/ocm/pkg/registration/clientcert"
 	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
@@ -263,3 +267,19 @@ func TestNewOptions(t *testing.T) {
 		t.Errorf("Should return err")
 	}
 }
+
+func TestWorkqueueMetrics(t *testing.T) {
+	syncCtx := factory.NewSyncContext("TestWorkqueueMetricsController", eventstesting.NewTestingEventRecorder(t))
+	syncCtx.Queue().Add("key")
+	defer syncCtx.Queue().ShutDown()
+
+	expected := `
+		# HELP workqueue_adds_total [ALPHA] Total number of adds handled by workqueue
+		# TYPE workqueue_adds_total counter
+		workqueue_adds_total{name="TestWorkqueueMetricsController"} 1
+	`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected),
+		"workqueue_adds_total"); err != nil {
+		t.Errorf("unexpected workqueue metrics: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package options
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// defaultPprofBindAddress only listens on the loopback interface, so the profiles are captured with
+// port-forward and not exposed out of the pod.
+const defaultPprofBindAddress = "127.0.0.1:6060"
+
+// runPprofServer serves the pprof endpoints on the bind address until the context is done.
+func runPprofServer(ctx context.Context, bindAddress string) {
+	logger := klog.FromContext(ctx)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	server := &http.Server{
+		Addr:              bindAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Error(err, "Failed to shutdown the pprof server")
+		}
+	}()
+
+	logger.Info("Starting the pprof server", "address", bindAddress)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error(err, "Failed to run the pprof server", "address", bindAddress)
+	}
+}
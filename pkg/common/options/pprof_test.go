@@ -0,0 +1,48 @@
+package options
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestRunPprofServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	stopped := make(chan struct{})
+	go func() {
+		runPprofServer(ctx, address)
+		close(stopped)
+	}()
+
+	err = wait.PollUntilContextTimeout(ctx, 50*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			resp, err := http.Get(fmt.Sprintf("http://%s/debug/pprof/cmdline", address))
+			if err != nil {
+				return false, nil
+			}
+			defer resp.Body.Close()
+			return resp.StatusCode == http.StatusOK, nil
+		})
+	if err != nil {
+		t.Fatalf("expected the pprof endpoints served, but got %v", err)
+	}
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected the pprof server stopped when the context is done")
+	}
+}
@@ -0,0 +1,70 @@
+package options
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	cliflag "k8s.io/component-base/cli/flag"
+
+	"open-cluster-management.io/ocm/pkg/common/fips"
+)
+
+const defaultWebhookShutdownTimeout = 60 * time.Second
+
+// WebhookServingOptions is the TLS and shutdown options of the webhook servers.
+type WebhookServingOptions struct {
+	// TLSMinVersion is the minimum TLS version supported, e.g. VersionTLS12.
+	TLSMinVersion string
+	// TLSCipherSuites is the cipher suites of TLS 1.2, the default cipher suites of golang are used if it is empty.
+	TLSCipherSuites []string
+	// ShutdownTimeout is the time the server waits for the in-flight admission requests on shutdown.
+	ShutdownTimeout time.Duration
+}
+
+// NewWebhookServingOptions returns the options with default value set
+func NewWebhookServingOptions() *WebhookServingOptions {
+	return &WebhookServingOptions{
+		ShutdownTimeout: defaultWebhookShutdownTimeout,
+	}
+}
+
+func (o *WebhookServingOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.TLSMinVersion, "tls-min-version", o.TLSMinVersion,
+		"Minimum TLS version supported by the webhook server. Possible values: "+
+			strings.Join(cliflag.TLSPossibleVersions(), ", ")+". The default is VersionTLS12.")
+	fs.StringSliceVar(&o.TLSCipherSuites, "tls-cipher-suites", o.TLSCipherSuites,
+		"Comma-separated list of the cipher suites of TLS 1.2 for the webhook server. If omitted, the default "+
+			"Go cipher suites will be used. The cipher suites of TLS 1.3 are not configurable.")
+	fs.DurationVar(&o.ShutdownTimeout, "shutdown-timeout", o.ShutdownTimeout,
+		"The duration the webhook server waits for the in-flight admission requests to finish on shutdown.")
+}
+
+// TLSConfigFunc returns a func setting the TLS options to the config of the webhook server. An error is returned if
+// the options are not valid, or the options are not FIPS compliant when the FIPS mode is enabled.
+func (o *WebhookServingOptions) TLSConfigFunc() (func(*tls.Config), error) {
+	minVersion, err := cliflag.TLSVersion(o.TLSMinVersion)
+	if err != nil {
+		return nil, err
+	}
+	cipherSuites, err := cliflag.TLSCipherSuites(o.TLSCipherSuites)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{MinVersion: minVersion, CipherSuites: cipherSuites}
+	if fips.Enabled() {
+		if err := fips.ValidateTLSConfig(config); err != nil {
+			return nil, fmt.Errorf("the TLS options are not valid in FIPS mode: %w", err)
+		}
+	}
+
+	return func(c *tls.Config) {
+		c.MinVersion = config.MinVersion
+		if len(config.CipherSuites) > 0 {
+			c.CipherSuites = config.CipherSuites
+		}
+	}, nil
+}
@@ -0,0 +1,66 @@
+package options
+
+import (
+	"crypto/tls"
+	"testing"
+)
+
+func TestTLSConfigFunc(t *testing.T) {
+	cases := []struct {
+		name                 string
+		minVersion           string
+		cipherSuites         []string
+		expectedErr          bool
+		expectedMinVersion   uint16
+		expectedCipherSuites []uint16
+	}{
+		{
+			name:               "default",
+			expectedMinVersion: tls.VersionTLS12,
+		},
+		{
+			name:                 "min version and cipher suites",
+			minVersion:           "VersionTLS13",
+			cipherSuites:         []string{"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"},
+			expectedMinVersion:   tls.VersionTLS13,
+			expectedCipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384},
+		},
+		{
+			name:        "invalid min version",
+			minVersion:  "VersionTLS14",
+			expectedErr: true,
+		},
+		{
+			name:         "invalid cipher suite",
+			cipherSuites: []string{"TLS_FOO"},
+			expectedErr:  true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := NewWebhookServingOptions()
+			o.TLSMinVersion = c.minVersion
+			o.TLSCipherSuites = c.cipherSuites
+
+			tlsConfigFunc, err := o.TLSConfigFunc()
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			config := &tls.Config{}
+			tlsConfigFunc(config)
+			if config.MinVersion != c.expectedMinVersion {
+				t.Errorf("expected min version %d, but got %d", c.expectedMinVersion, config.MinVersion)
+			}
+			if len(config.CipherSuites) != len(c.expectedCipherSuites) {
+				t.Errorf("expected cipher suites %v, but got %v", c.expectedCipherSuites, config.CipherSuites)
+			}
+		})
+	}
+}
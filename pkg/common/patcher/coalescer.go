@@ -0,0 +1,86 @@
+package patcher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+// Coalescer coalesces the status patches of an object within a window into one patch. The first patch of an
+// object is sent immediately and opens a window, the patches in the window are deferred and only the last one is
+// sent when the window closes, so an object is patched at most twice in a window no matter how many times its
+// status changes.
+//
+// The deferred patch is built against the status and the resource version of the object when it was requested, it
+// is dropped if it fails, e.g. the object is changed by others in the window, the controller is expected to resync
+// the objects and patch the status again. A Coalescer could be shared by the patchers of different types.
+type Coalescer struct {
+	window time.Duration
+	clock  clock.WithDelayedExecution
+
+	lock sync.Mutex
+	// windows are keyed by the type, namespace and name of the objects.
+	windows map[string]*coalescingWindow
+}
+
+type coalescingWindow struct {
+	// pending is the last patch requested in the window, it is nil if no patch is deferred.
+	pending func(context.Context) error
+	ctx     context.Context
+}
+
+// NewCoalescer returns a Coalescer with the window, it returns nil if the window is not positive, and the patches
+// are not coalesced with a nil Coalescer.
+func NewCoalescer(window time.Duration) *Coalescer {
+	if window <= 0 {
+		return nil
+	}
+	return &Coalescer{
+		window:  window,
+		clock:   clock.RealClock{},
+		windows: map[string]*coalescingWindow{},
+	}
+}
+
+// patch sends the patch immediately if there is no open window of the object, otherwise the patch is deferred to
+// the end of the window. It returns true if the patch is deferred.
+func (c *Coalescer) patch(ctx context.Context, key string, patchFunc func(context.Context) error) (bool, error) {
+	c.lock.Lock()
+	if w, ok := c.windows[key]; ok {
+		if w.pending != nil {
+			suppressedPatches.WithLabelValues(suppressedReasonCoalesced).Inc()
+		}
+		w.pending = patchFunc
+		w.ctx = ctx
+		c.lock.Unlock()
+		return true, nil
+	}
+	c.windows[key] = &coalescingWindow{}
+	c.clock.AfterFunc(c.window, func() { c.closeWindow(key) })
+	c.lock.Unlock()
+
+	return false, patchFunc(ctx)
+}
+
+// closeWindow closes the window of the object and sends the deferred patch if there is one.
+func (c *Coalescer) closeWindow(key string) {
+	c.lock.Lock()
+	w := c.windows[key]
+	delete(c.windows, key)
+	c.lock.Unlock()
+
+	if w == nil || w.pending == nil || w.ctx.Err() != nil {
+		return
+	}
+	if err := w.pending(w.ctx); err != nil {
+		klog.FromContext(w.ctx).Error(err, "Failed to send the coalesced patch", "object", key)
+	}
+}
+
+func coalescingKey(object interface{}, namespace, name string) string {
+	return fmt.Sprintf("%T/%s/%s", object, namespace, name)
+}
@@ -0,0 +1,88 @@
+package patcher
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/component-base/metrics/testutil"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+)
+
+func TestCoalescePatchStatus(t *testing.T) {
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	coalescer := NewCoalescer(10 * time.Second)
+	coalescer.clock = fakeClock
+
+	cluster := newManagedClusterWithConditions(metav1.Condition{Type: "Type1"})
+	clusterClient := clusterfake.NewSimpleClientset(cluster)
+	patcher := NewPatcher[
+		*clusterv1.ManagedCluster, clusterv1.ManagedClusterSpec, clusterv1.ManagedClusterStatus](
+		clusterClient.ClusterV1().ManagedClusters()).WithOptions(PatchOptions{Coalescer: coalescer})
+
+	coalesced, err := testutil.GetCounterMetricValue(suppressedPatches.WithLabelValues(suppressedReasonCoalesced))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first patch is sent immediately and the others in the window are deferred
+	for _, conditionType := range []string{"Type2", "Type3", "Type4"} {
+		newCluster := newManagedClusterWithConditions(metav1.Condition{Type: conditionType})
+		updated, err := patcher.PatchStatus(context.TODO(), cluster, newCluster.Status, cluster.Status)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !updated {
+			t.Errorf("expected the status of condition %s is updated", conditionType)
+		}
+	}
+	testingcommon.AssertActions(t, clusterClient.Actions(), "patch")
+	assertPatchedConditionType(t, clusterClient.Actions()[0], "Type2")
+
+	// only the last patch is sent when the window closes
+	fakeClock.Step(10 * time.Second)
+	testingcommon.AssertActions(t, clusterClient.Actions(), "patch", "patch")
+	assertPatchedConditionType(t, clusterClient.Actions()[1], "Type4")
+
+	actual, err := testutil.GetCounterMetricValue(suppressedPatches.WithLabelValues(suppressedReasonCoalesced))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual-coalesced != 1 {
+		t.Errorf("expected 1 coalesced patch, but got %v", actual-coalesced)
+	}
+
+	// a new window is opened by the next patch
+	newCluster := newManagedClusterWithConditions(metav1.Condition{Type: "Type5"})
+	if _, err := patcher.PatchStatus(context.TODO(), cluster, newCluster.Status, cluster.Status); err != nil {
+		t.Fatal(err)
+	}
+	testingcommon.AssertActions(t, clusterClient.Actions(), "patch", "patch", "patch")
+	fakeClock.Step(10 * time.Second)
+	testingcommon.AssertActions(t, clusterClient.Actions(), "patch", "patch", "patch")
+}
+
+func TestNewCoalescer(t *testing.T) {
+	if NewCoalescer(0) != nil {
+		t.Errorf("expected no coalescer with zero window")
+	}
+}
+
+func assertPatchedConditionType(t *testing.T, action clienttesting.Action, conditionType string) {
+	t.Helper()
+	managedCluster := &clusterv1.ManagedCluster{}
+	if err := json.Unmarshal(action.(clienttesting.PatchAction).GetPatch(), managedCluster); err != nil {
+		t.Fatal(err)
+	}
+	if len(managedCluster.Status.Conditions) != 1 || managedCluster.Status.Conditions[0].Type != conditionType {
+		t.Errorf("expected condition %s is patched, but got %v", conditionType, managedCluster.Status.Conditions)
+	}
+}
@@ -0,0 +1,32 @@
+package patcher
+
+import (
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+// The reasons of the patches suppressed by the patchers.
+const (
+	// suppressedReasonNoop is the reason of the patches which do not change the object after being serialized,
+	// e.g. a nil list is replaced by an empty one.
+	suppressedReasonNoop = "noop"
+	// suppressedReasonCoalesced is the reason of the patches replaced by a later patch in a coalescing window.
+	suppressedReasonCoalesced = "coalesced"
+)
+
+// suppressedPatches is the number of the patches which are not sent to the apiserver.
+var suppressedPatches = metrics.NewCounterVec(
+	&metrics.CounterOpts{
+		Subsystem:      "patcher",
+		Name:           "suppressed_patches_total",
+		Help:           "Number of patches not sent to the apiserver since they are no-op or coalesced.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"reason"},
+)
+
+func init() {
+	// the patcher is used by all the components, register the metric once it is imported, so it is served on the
+	// /metrics endpoint of the components.
+	legacyregistry.MustRegister(suppressedPatches)
+}
@@ -1,6 +1,7 @@
 package patcher
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -34,6 +35,8 @@ type Patcher[R runtime.Object, Sp any, St any] interface {
 type PatchOptions struct {
 	// IgnoreResourceVersion will ignore the resource version matching when patching.
 	IgnoreResourceVersion bool
+	// Coalescer coalesces the status patches of an object in a short window into one patch if it is set.
+	Coalescer *Coalescer
 }
 
 // Resource is a generic wrapper around resources so we can generate patches.
@@ -172,16 +175,24 @@ func (p *patcher[R, Sp, St]) RemoveFinalizer(ctx context.Context, object R, fina
 	return err
 }
 
-func (p *patcher[R, Sp, St]) patch(ctx context.Context, object R, newObject, oldObject *Resource[Sp, St], subresources ...string) error {
-	logger := klog.FromContext(ctx)
-	accessor, err := meta.Accessor(object)
+// buildPatch returns the merge patch from the old object to the new object, it returns nil if the patch does not
+// change the object after being serialized.
+func (p *patcher[R, Sp, St]) buildPatch(accessor metav1.Object, newObject, oldObject *Resource[Sp, St]) ([]byte, error) {
+	oldData, err := json.Marshal(oldObject)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to Marshal old data for %s: %w", accessor.GetName(), err)
 	}
 
-	oldData, err := json.Marshal(oldObject)
+	newData, err := json.Marshal(newObject)
 	if err != nil {
-		return fmt.Errorf("failed to Marshal old data for %s: %w", accessor.GetName(), err)
+		return nil, fmt.Errorf("failed to Marshal new data for %s: %w", accessor.GetName(), err)
+	}
+
+	// the objects are semantically different but the same after being serialized, e.g. a nil list and an empty one
+	// which is omitted, skip the patch.
+	if bytes.Equal(oldData, newData) {
+		suppressedPatches.WithLabelValues(suppressedReasonNoop).Inc()
+		return nil, nil
 	}
 
 	newObject.UID = accessor.GetUID()
@@ -191,14 +202,23 @@ func (p *patcher[R, Sp, St]) patch(ctx context.Context, object R, newObject, old
 		newObject.ResourceVersion = accessor.GetResourceVersion()
 	}
 
-	newData, err := json.Marshal(newObject)
+	newData, err = json.Marshal(newObject)
 	if err != nil {
-		return fmt.Errorf("failed to Marshal new data for %s: %w", accessor.GetName(), err)
+		return nil, fmt.Errorf("failed to Marshal new data for %s: %w", accessor.GetName(), err)
 	}
 
 	patchBytes, err := jsonpatch.CreateMergePatch(oldData, newData)
 	if err != nil {
-		return fmt.Errorf("failed to create patch for %s: %w", accessor.GetName(), err)
+		return nil, fmt.Errorf("failed to create patch for %s: %w", accessor.GetName(), err)
+	}
+	return patchBytes, nil
+}
+
+func (p *patcher[R, Sp, St]) patch(ctx context.Context, object R, patchBytes []byte, subresources ...string) error {
+	logger := klog.FromContext(ctx)
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return err
 	}
 
 	_, err = p.client.Patch(
@@ -212,16 +232,33 @@ func (p *patcher[R, Sp, St]) patch(ctx context.Context, object R, newObject, old
 	return err
 }
 
+// PatchStatus patches the status of the object, it returns true if the status is patched or the patch is deferred
+// by the Coalescer of the patcher.
 func (p *patcher[R, Sp, St]) PatchStatus(ctx context.Context, object R, newStatus, oldStatus St) (bool, error) {
 	statusChanged := !equality.Semantic.DeepEqual(oldStatus, newStatus)
 	if !statusChanged {
 		return false, nil
 	}
 
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return false, err
+	}
 	oldObject := &Resource[Sp, St]{Status: oldStatus}
 	newObject := &Resource[Sp, St]{Status: newStatus}
+	patchBytes, err := p.buildPatch(accessor, newObject, oldObject)
+	if err != nil || patchBytes == nil {
+		return false, err
+	}
 
-	return true, p.patch(ctx, object, newObject, oldObject, "status")
+	if p.opts.Coalescer == nil {
+		return true, p.patch(ctx, object, patchBytes, "status")
+	}
+	_, err = p.opts.Coalescer.patch(ctx, coalescingKey(object, accessor.GetNamespace(), accessor.GetName()),
+		func(ctx context.Context) error {
+			return p.patch(ctx, object, patchBytes, "status")
+		})
+	return true, err
 }
 
 func (p *patcher[R, Sp, St]) PatchSpec(ctx context.Context, object R, newSpec, oldSpec Sp) (bool, error) {
@@ -230,9 +267,17 @@ func (p *patcher[R, Sp, St]) PatchSpec(ctx context.Context, object R, newSpec, o
 		return false, nil
 	}
 
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return false, err
+	}
 	oldObject := &Resource[Sp, St]{Spec: oldSpec}
 	newObject := &Resource[Sp, St]{Spec: newSpec}
-	return true, p.patch(ctx, object, newObject, oldObject)
+	patchBytes, err := p.buildPatch(accessor, newObject, oldObject)
+	if err != nil || patchBytes == nil {
+		return false, err
+	}
+	return true, p.patch(ctx, object, patchBytes)
 }
 
 func (p *patcher[R, Sp, St]) PatchLabelAnnotations(ctx context.Context, object R, newObject, oldObject metav1.ObjectMeta) (bool, error) {
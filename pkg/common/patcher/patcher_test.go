@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -245,6 +246,14 @@ func TestPatchStatus(t *testing.T) {
 			newObj:          newManagedClusterWithTaint(clusterv1.Taint{Key: "key2"}),
 			validateActions: testingcommon.AssertNoActions,
 		},
+		{
+			name: "no patch if the status is the same after being serialized",
+			obj: newManagedClusterWithConditions(metav1.Condition{
+				Type: "Type1", LastTransitionTime: metav1.NewTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))}),
+			newObj: newManagedClusterWithConditions(metav1.Condition{
+				Type: "Type1", LastTransitionTime: metav1.NewTime(time.Date(2023, 1, 1, 0, 0, 0, 100, time.UTC))}),
+			validateActions: testingcommon.AssertNoActions,
+		},
 	}
 
 	for _, c := range cases {
@@ -9,6 +9,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	clienttesting "k8s.io/client-go/testing"
+	kevents "k8s.io/client-go/tools/events"
 )
 
 // AssertError asserts the actual error representation is the same with the expected,
@@ -166,3 +167,21 @@ func AssertEqualNameNamespace(t *testing.T, actualName, actualNamespace, name, n
 		t.Errorf("Namespace of the object does not match, expected %s, actual %s", namespace, actualNamespace)
 	}
 }
+
+// AssertEventReasons asserts the events recorded by the fake recorder have the expected reasons in order
+func AssertEventReasons(t *testing.T, recorder *kevents.FakeRecorder, expectedReasons ...string) {
+	t.Helper()
+	var reasons []string
+	for len(recorder.Events) > 0 {
+		// the event is formatted as "<type> <reason> <note>"
+		fields := strings.SplitN(<-recorder.Events, " ", 3)
+		if len(fields) < 2 {
+			t.Errorf("unexpected event format %q", strings.Join(fields, " "))
+			continue
+		}
+		reasons = append(reasons, fields[1])
+	}
+	if strings.Join(reasons, ",") != strings.Join(expectedReasons, ",") {
+		t.Errorf("expected events with reasons %v, but got %v", expectedReasons, reasons)
+	}
+}
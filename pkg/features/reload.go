@@ -0,0 +1,252 @@
+package features
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/featuregate"
+	"k8s.io/klog/v2"
+
+	commonmetrics "open-cluster-management.io/ocm/pkg/common/metrics"
+	"open-cluster-management.io/ocm/pkg/common/queue"
+)
+
+const (
+	// FeatureGatesConfigMapRegistrationKey and FeatureGatesConfigMapWorkKey are the keys of the feature gates of the
+	// registration agent and the work agent in the feature gates ConfigMap, whose values are in the format of
+	// A=true,B=false.
+	FeatureGatesConfigMapRegistrationKey = "registration"
+	FeatureGatesConfigMapWorkKey         = "work"
+
+	// ActiveFeatureGatesAnnotationPrefix is the prefix of the annotations on the feature gates ConfigMap with the feature
+	// gates active in the agents, the suffix is the key of the feature gates in the ConfigMap. The feature gates are in
+	// the format returned by FormatFeatureGates.
+	ActiveFeatureGatesAnnotationPrefix = "feature-gates.open-cluster-management.io/active-"
+)
+
+// gateCheckPeriod is the period RunGated checks the feature gate.
+var gateCheckPeriod = 5 * time.Second
+
+// ParseFeatureGates parses the feature gates in the format of A=true,B=false, and returns the state of each one of the
+// features, which is its default if it is not set in the value.
+func ParseFeatureGates(value string,
+	features map[featuregate.Feature]featuregate.FeatureSpec) (map[featuregate.Feature]bool, error) {
+	enabled := map[featuregate.Feature]bool{}
+	for feature, spec := range features {
+		enabled[feature] = spec.Default
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if len(pair) == 0 {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("missing bool value for feature gate %s", pair)
+		}
+		feature := featuregate.Feature(strings.TrimSpace(kv[0]))
+		if _, ok := features[feature]; !ok {
+			return nil, fmt.Errorf("unrecognized feature gate %s", feature)
+		}
+		v, err := strconv.ParseBool(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of feature gate %s: %w", feature, err)
+		}
+		enabled[feature] = v
+	}
+	return enabled, nil
+}
+
+// FormatFeatureGates returns the state of the features in the format of A=true,B=false, it only includes the features
+// which are not in their default state, sorted by their names.
+func FormatFeatureGates(enabled map[featuregate.Feature]bool,
+	features map[featuregate.Feature]featuregate.FeatureSpec) string {
+	var pairs []string
+	for feature, spec := range features {
+		if v, ok := enabled[feature]; ok && v != spec.Default {
+			pairs = append(pairs, fmt.Sprintf("%s=%t", feature, v))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// LoadFeatureGates sets the feature gates in the key of the ConfigMap to the gate, it is expected to be called before
+// the controllers are built, so the static features are set as well. The features keep their current state if the
+// ConfigMap is not found.
+func LoadFeatureGates(ctx context.Context, kubeClient kubernetes.Interface, namespace, name, key string,
+	gate featuregate.MutableFeatureGate, features map[featuregate.Feature]featuregate.FeatureSpec) error {
+	configMap, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		klog.Warningf("The feature gates ConfigMap %s/%s is not found", namespace, name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	enabled, err := ParseFeatureGates(configMap.Data[key], features)
+	if err != nil {
+		return fmt.Errorf("failed to parse the feature gates in ConfigMap %s/%s: %w", namespace, name, err)
+	}
+	return gate.SetFromMap(toStringMap(enabled))
+}
+
+// featureGateReloadController reloads the feature gates in a key of the feature gates ConfigMap to the gate once the
+// ConfigMap is changed, and records the active feature gates in the annotation of the key on the ConfigMap. The static
+// features are only read when the controllers are built, so they are not reloaded and take effect once the agent is
+// restarted.
+type featureGateReloadController struct {
+	kubeClient      kubernetes.Interface
+	configMapLister corev1listers.ConfigMapLister
+	key             string
+	gate            featuregate.MutableFeatureGate
+	features        map[featuregate.Feature]featuregate.FeatureSpec
+	staticFeatures  sets.Set[featuregate.Feature]
+	recorder        events.Recorder
+}
+
+// NewFeatureGateReloadController returns a controller reloading the features in the key of the ConfigMap with the name
+// to the gate.
+func NewFeatureGateReloadController(
+	kubeClient kubernetes.Interface,
+	configMapInformer corev1informers.ConfigMapInformer,
+	name, key string,
+	gate featuregate.MutableFeatureGate,
+	features map[featuregate.Feature]featuregate.FeatureSpec,
+	recorder events.Recorder,
+	staticFeatures ...featuregate.Feature) factory.Controller {
+	c := &featureGateReloadController{
+		kubeClient:      kubeClient,
+		configMapLister: configMapInformer.Lister(),
+		key:             key,
+		gate:            gate,
+		features:        features,
+		staticFeatures:  sets.New[featuregate.Feature](staticFeatures...),
+		recorder:        recorder,
+	}
+
+	return factory.New().
+		WithFilteredEventsInformersQueueKeysFunc(
+			queue.QueueKeyByMetaNamespaceName,
+			queue.FilterByNames(name),
+			configMapInformer.Informer()).
+		WithSync(commonmetrics.WithReconcileMetrics("FeatureGateReloadController", c.sync)).
+		ToController("FeatureGateReloadController", recorder)
+}
+
+func (c *featureGateReloadController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(syncCtx.QueueKey())
+	if err != nil {
+		// ignore the key which is not in the format of namespace/name
+		return nil
+	}
+
+	configMap, err := c.configMapLister.ConfigMaps(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	desired, err := ParseFeatureGates(configMap.Data[c.key], c.features)
+	if err != nil {
+		c.recorder.Warningf("FeatureGatesInvalid", "the feature gates in ConfigMap %s/%s are invalid: %v", namespace, name, err)
+		return nil
+	}
+
+	changed := map[string]bool{}
+	for feature, v := range desired {
+		if c.gate.Enabled(feature) == v {
+			continue
+		}
+		if c.staticFeatures.Has(feature) {
+			klog.Infof("The feature gate %s=%t takes effect once the agent is restarted", feature, v)
+			continue
+		}
+		changed[string(feature)] = v
+	}
+	if len(changed) > 0 {
+		if err := c.gate.SetFromMap(changed); err != nil {
+			c.recorder.Warningf("FeatureGatesInvalid", "failed to reload the feature gates in ConfigMap %s/%s: %v",
+				namespace, name, err)
+			return nil
+		}
+		c.recorder.Eventf("FeatureGatesReloaded", "the feature gates %v in ConfigMap %s/%s are reloaded",
+			changed, namespace, name)
+	}
+
+	active := map[featuregate.Feature]bool{}
+	for feature := range c.features {
+		active[feature] = c.gate.Enabled(feature)
+	}
+	annotation := ActiveFeatureGatesAnnotationPrefix + c.key
+	activeFeatureGates := FormatFeatureGates(active, c.features)
+	if v, ok := configMap.Annotations[annotation]; ok && v == activeFeatureGates {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{annotation: activeFeatureGates},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.kubeClient.CoreV1().ConfigMaps(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// RunGated calls the run func once the feature is enabled in the gate, and cancels the context passed to the run func
+// once the feature is disabled, so the controllers started by the run func are stopped. The run func is called again
+// once the feature is enabled again. The run func is expected to start the controllers without blocking, and the
+// informers used by the controllers are expected to be started with the context of the agent, since an informer is
+// not able to be started again once it is stopped. It blocks until the context is done.
+func RunGated(ctx context.Context, gate featuregate.FeatureGate, feature featuregate.Feature, run func(ctx context.Context)) {
+	var cancel context.CancelFunc
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		enabled := gate.Enabled(feature)
+		switch {
+		case enabled && cancel == nil:
+			klog.Infof("The feature %s is enabled, start its controllers", feature)
+			var runCtx context.Context
+			runCtx, cancel = context.WithCancel(ctx)
+			run(runCtx)
+		case !enabled && cancel != nil:
+			klog.Infof("The feature %s is disabled, stop its controllers", feature)
+			cancel()
+			cancel = nil
+		}
+	}, gateCheckPeriod)
+
+	if cancel != nil {
+		cancel()
+	}
+}
+
+func toStringMap(enabled map[featuregate.Feature]bool) map[string]bool {
+	m := map[string]bool{}
+	for feature, v := range enabled {
+		m[string(feature)] = v
+	}
+	return m
+}
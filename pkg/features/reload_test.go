@@ -0,0 +1,287 @@
+package features
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events/eventstesting"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/component-base/featuregate"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+)
+
+const (
+	featureA featuregate.Feature = "FeatureA"
+	featureB featuregate.Feature = "FeatureB"
+	featureC featuregate.Feature = "FeatureC"
+)
+
+var testFeatures = map[featuregate.Feature]featuregate.FeatureSpec{
+	featureA: {Default: false, PreRelease: featuregate.Alpha},
+	featureB: {Default: true, PreRelease: featuregate.Beta},
+	featureC: {Default: false, PreRelease: featuregate.Alpha},
+}
+
+func newTestFeatureGate(t *testing.T) featuregate.MutableFeatureGate {
+	gate := featuregate.NewFeatureGate()
+	if err := gate.Add(testFeatures); err != nil {
+		t.Fatal(err)
+	}
+	return gate
+}
+
+func newFeatureGatesConfigMap(value string, annotations map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "feature-gates",
+			Namespace:   "open-cluster-management-agent",
+			Annotations: annotations,
+		},
+		Data: map[string]string{FeatureGatesConfigMapWorkKey: value},
+	}
+}
+
+func TestParseAndFormatFeatureGates(t *testing.T) {
+	cases := []struct {
+		name           string
+		value          string
+		expectedErr    bool
+		expectedFormat string
+	}{
+		{
+			name: "empty value",
+		},
+		{
+			name:           "non-default values",
+			value:          "FeatureB=false, FeatureA=true",
+			expectedFormat: "FeatureA=true,FeatureB=false",
+		},
+		{
+			name:  "default values",
+			value: "FeatureA=false,FeatureB=true",
+		},
+		{
+			name:        "unknown feature",
+			value:       "FeatureD=true",
+			expectedErr: true,
+		},
+		{
+			name:        "invalid value",
+			value:       "FeatureA=yes",
+			expectedErr: true,
+		},
+		{
+			name:        "missing value",
+			value:       "FeatureA",
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			enabled, err := ParseFeatureGates(c.value, testFeatures)
+			if (err != nil) != c.expectedErr {
+				t.Fatalf("expected error %v, but got %v", c.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if len(enabled) != len(testFeatures) {
+				t.Errorf("expected the state of all features, but got %v", enabled)
+			}
+			if format := FormatFeatureGates(enabled, testFeatures); format != c.expectedFormat {
+				t.Errorf("expected %q, but got %q", c.expectedFormat, format)
+			}
+		})
+	}
+}
+
+func TestLoadFeatureGates(t *testing.T) {
+	gate := newTestFeatureGate(t)
+	kubeClient := kubefake.NewSimpleClientset()
+	if err := LoadFeatureGates(context.TODO(), kubeClient, "open-cluster-management-agent", "feature-gates",
+		FeatureGatesConfigMapWorkKey, gate, testFeatures); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	kubeClient = kubefake.NewSimpleClientset(newFeatureGatesConfigMap("FeatureA=true,FeatureC=true", nil))
+	if err := LoadFeatureGates(context.TODO(), kubeClient, "open-cluster-management-agent", "feature-gates",
+		FeatureGatesConfigMapWorkKey, gate, testFeatures); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !gate.Enabled(featureA) || !gate.Enabled(featureB) || !gate.Enabled(featureC) {
+		t.Errorf("expected all features are enabled")
+	}
+
+	kubeClient = kubefake.NewSimpleClientset(newFeatureGatesConfigMap("FeatureD=true", nil))
+	if err := LoadFeatureGates(context.TODO(), kubeClient, "open-cluster-management-agent", "feature-gates",
+		FeatureGatesConfigMapWorkKey, gate, testFeatures); err == nil {
+		t.Errorf("expected error of the unknown feature")
+	}
+}
+
+func TestFeatureGateReloadSync(t *testing.T) {
+	cases := []struct {
+		name               string
+		configMap          *corev1.ConfigMap
+		initial            map[string]bool
+		expectedActions    []string
+		expectedEnabled    map[featuregate.Feature]bool
+		expectedAnnotation string
+	}{
+		{
+			name:            "configmap is not found",
+			expectedEnabled: map[featuregate.Feature]bool{featureA: false, featureB: true, featureC: false},
+		},
+		{
+			name:               "reload the feature gates",
+			configMap:          newFeatureGatesConfigMap("FeatureA=true,FeatureB=false", nil),
+			expectedActions:    []string{"patch"},
+			expectedEnabled:    map[featuregate.Feature]bool{featureA: true, featureB: false, featureC: false},
+			expectedAnnotation: "FeatureA=true,FeatureB=false",
+		},
+		{
+			name:               "reset the feature gates to the defaults",
+			configMap:          newFeatureGatesConfigMap("", nil),
+			initial:            map[string]bool{"FeatureA": true},
+			expectedActions:    []string{"patch"},
+			expectedEnabled:    map[featuregate.Feature]bool{featureA: false, featureB: true, featureC: false},
+			expectedAnnotation: "",
+		},
+		{
+			name:               "static feature is not reloaded",
+			configMap:          newFeatureGatesConfigMap("FeatureA=true,FeatureC=true", nil),
+			expectedActions:    []string{"patch"},
+			expectedEnabled:    map[featuregate.Feature]bool{featureA: true, featureB: true, featureC: false},
+			expectedAnnotation: "FeatureA=true",
+		},
+		{
+			name: "feature gates are active",
+			configMap: newFeatureGatesConfigMap("FeatureA=true", map[string]string{
+				ActiveFeatureGatesAnnotationPrefix + FeatureGatesConfigMapWorkKey: "FeatureA=true",
+			}),
+			initial:         map[string]bool{"FeatureA": true},
+			expectedEnabled: map[featuregate.Feature]bool{featureA: true, featureB: true, featureC: false},
+		},
+		{
+			name:            "feature gates are invalid",
+			configMap:       newFeatureGatesConfigMap("FeatureA=true,FeatureD=true", nil),
+			expectedEnabled: map[featuregate.Feature]bool{featureA: false, featureB: true, featureC: false},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			gate := newTestFeatureGate(t)
+			if err := gate.SetFromMap(c.initial); err != nil {
+				t.Fatal(err)
+			}
+
+			var objs []runtime.Object
+			if c.configMap != nil {
+				objs = append(objs, c.configMap)
+			}
+			kubeClient := kubefake.NewSimpleClientset(objs...)
+			informerFactory := informers.NewSharedInformerFactory(kubeClient, 10*time.Minute)
+			configMapInformer := informerFactory.Core().V1().ConfigMaps()
+			if c.configMap != nil {
+				if err := configMapInformer.Informer().GetStore().Add(c.configMap); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctrl := &featureGateReloadController{
+				kubeClient:      kubeClient,
+				configMapLister: configMapInformer.Lister(),
+				key:             FeatureGatesConfigMapWorkKey,
+				gate:            gate,
+				features:        testFeatures,
+				staticFeatures:  sets.New[featuregate.Feature](featureC),
+				recorder:        eventstesting.NewTestingEventRecorder(t),
+			}
+			syncCtx := testingcommon.NewFakeSyncContext(t, "open-cluster-management-agent/feature-gates")
+			if err := ctrl.sync(context.TODO(), syncCtx); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			testingcommon.AssertActions(t, kubeClient.Actions(), c.expectedActions...)
+			for feature, enabled := range c.expectedEnabled {
+				if gate.Enabled(feature) != enabled {
+					t.Errorf("expected feature %s enabled %v, but got %v", feature, enabled, gate.Enabled(feature))
+				}
+			}
+			if len(c.expectedActions) == 0 {
+				return
+			}
+
+			patch := kubeClient.Actions()[0].(clienttesting.PatchActionImpl).Patch
+			configMap := &corev1.ConfigMap{}
+			if err := json.Unmarshal(patch, configMap); err != nil {
+				t.Fatal(err)
+			}
+			annotation, ok := configMap.Annotations[ActiveFeatureGatesAnnotationPrefix+FeatureGatesConfigMapWorkKey]
+			if !ok || annotation != c.expectedAnnotation {
+				t.Errorf("expected active feature gates %q, but got %q", c.expectedAnnotation, annotation)
+			}
+		})
+	}
+}
+
+func TestRunGated(t *testing.T) {
+	gateCheckPeriod = 10 * time.Millisecond
+	defer func() {
+		gateCheckPeriod = 5 * time.Second
+	}()
+
+	gate := newTestFeatureGate(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan context.Context, 2)
+	go RunGated(ctx, gate, featureA, func(ctx context.Context) {
+		started <- ctx
+	})
+
+	select {
+	case <-started:
+		t.Fatalf("expected the controllers are not started when the feature is disabled")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := gate.SetFromMap(map[string]bool{"FeatureA": true}); err != nil {
+		t.Fatal(err)
+	}
+	var runCtx context.Context
+	select {
+	case runCtx = <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the controllers are started once the feature is enabled")
+	}
+
+	if err := gate.SetFromMap(map[string]bool{"FeatureA": false}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-runCtx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the controllers are stopped once the feature is disabled")
+	}
+
+	if err := gate.SetFromMap(map[string]bool{"FeatureA": true}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the controllers are started again once the feature is enabled again")
+	}
+}
@@ -0,0 +1,230 @@
+package fleetmetrics
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/component-base/metrics"
+	"k8s.io/klog/v2"
+
+	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	clusterlisterv1beta1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
+	worklisterv1 "open-cluster-management.io/api/client/work/listers/work/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	addonmetrics "open-cluster-management.io/ocm/pkg/addon/controllers/metrics"
+)
+
+const subsystem = "fleet"
+
+// The states of the ManifestWorks reported by the manifest works metric.
+const (
+	WorkStateTotal     = "total"
+	WorkStateApplied   = "applied"
+	WorkStateAvailable = "available"
+	WorkStateDegraded  = "degraded"
+)
+
+var (
+	clusterInfoDesc = metrics.NewDesc(
+		metrics.BuildFQName("", subsystem, "managed_cluster_info"),
+		"Information of a ManagedCluster, the value is always 1.",
+		[]string{"managed_cluster", "kubernetes_version", "hub_accepted"}, nil, metrics.ALPHA, "")
+
+	clusterConditionDesc = metrics.NewDesc(
+		metrics.BuildFQName("", subsystem, "managed_cluster_condition"),
+		"The condition of a ManagedCluster, the value is 1 for the current status of the condition and 0 for the others.",
+		[]string{"managed_cluster", "condition", "status"}, nil, metrics.ALPHA, "")
+
+	clusterClaimDesc = metrics.NewDesc(
+		metrics.BuildFQName("", subsystem, "managed_cluster_claim"),
+		"A cluster claim reported by a ManagedCluster, the value is always 1.",
+		[]string{"managed_cluster", "claim", "value"}, nil, metrics.ALPHA, "")
+
+	worksDesc = metrics.NewDesc(
+		metrics.BuildFQName("", subsystem, "manifest_works"),
+		"Number of ManifestWorks of a ManagedCluster in each state.",
+		[]string{"managed_cluster", "state"}, nil, metrics.ALPHA, "")
+
+	placementDecisionsDesc = metrics.NewDesc(
+		metrics.BuildFQName("", subsystem, "placement_decisions"),
+		"Number of ManagedClusters selected by a Placement.",
+		[]string{"namespace", "placement"}, nil, metrics.ALPHA, "")
+
+	placementMisplacedDesc = metrics.NewDesc(
+		metrics.BuildFQName("", subsystem, "placement_misplaced_decisions"),
+		"Number of ManagedClusters selected by a Placement which are not found or not available.",
+		[]string{"namespace", "placement"}, nil, metrics.ALPHA, "")
+
+	addonInfoDesc = metrics.NewDesc(
+		metrics.BuildFQName("", subsystem, "managed_cluster_addon_info"),
+		"State of a ManagedClusterAddOn, the value is always 1.",
+		[]string{"managed_cluster", "addon_name", "state"}, nil, metrics.ALPHA, "")
+
+	conditionStatuses = []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionUnknown}
+)
+
+// fleetCollector collects the state of the fleet from the listers when scraped, in the style of kube-state-metrics.
+type fleetCollector struct {
+	metrics.BaseStableCollector
+
+	clusterLister   clusterlisterv1.ManagedClusterLister
+	workLister      worklisterv1.ManifestWorkLister
+	placementLister clusterlisterv1beta1.PlacementLister
+	decisionLister  clusterlisterv1beta1.PlacementDecisionLister
+	addonLister     addonlisterv1alpha1.ManagedClusterAddOnLister
+}
+
+// NewFleetCollector returns a collector of the fleet metrics.
+func NewFleetCollector(
+	clusterLister clusterlisterv1.ManagedClusterLister,
+	workLister worklisterv1.ManifestWorkLister,
+	placementLister clusterlisterv1beta1.PlacementLister,
+	decisionLister clusterlisterv1beta1.PlacementDecisionLister,
+	addonLister addonlisterv1alpha1.ManagedClusterAddOnLister) metrics.StableCollector {
+	return &fleetCollector{
+		clusterLister:   clusterLister,
+		workLister:      workLister,
+		placementLister: placementLister,
+		decisionLister:  decisionLister,
+		addonLister:     addonLister,
+	}
+}
+
+func (c *fleetCollector) DescribeWithStability(ch chan<- *metrics.Desc) {
+	ch <- clusterInfoDesc
+	ch <- clusterConditionDesc
+	ch <- clusterClaimDesc
+	ch <- worksDesc
+	ch <- placementDecisionsDesc
+	ch <- placementMisplacedDesc
+	ch <- addonInfoDesc
+}
+
+func (c *fleetCollector) CollectWithStability(ch chan<- metrics.Metric) {
+	clusters, err := c.clusterLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list ManagedClusters for metrics: %v", err)
+		return
+	}
+	available := map[string]bool{}
+	for _, cluster := range clusters {
+		available[cluster.Name] = meta.IsStatusConditionTrue(cluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable)
+		c.collectCluster(ch, cluster)
+	}
+
+	c.collectWorks(ch)
+	c.collectPlacements(ch, available)
+	c.collectAddons(ch)
+}
+
+func (c *fleetCollector) collectCluster(ch chan<- metrics.Metric, cluster *clusterv1.ManagedCluster) {
+	ch <- metrics.NewLazyConstMetric(clusterInfoDesc, metrics.GaugeValue, 1,
+		cluster.Name, cluster.Status.Version.Kubernetes, boolLabel(cluster.Spec.HubAcceptsClient))
+
+	for _, conditionType := range []string{clusterv1.ManagedClusterConditionAvailable, clusterv1.ManagedClusterConditionJoined} {
+		status := metav1.ConditionUnknown
+		if condition := meta.FindStatusCondition(cluster.Status.Conditions, conditionType); condition != nil {
+			status = condition.Status
+		}
+		for _, s := range conditionStatuses {
+			value := 0.0
+			if s == status {
+				value = 1
+			}
+			ch <- metrics.NewLazyConstMetric(clusterConditionDesc, metrics.GaugeValue, value,
+				cluster.Name, conditionType, strings.ToLower(string(s)))
+		}
+	}
+
+	for _, claim := range cluster.Status.ClusterClaims {
+		ch <- metrics.NewLazyConstMetric(clusterClaimDesc, metrics.GaugeValue, 1, cluster.Name, claim.Name, claim.Value)
+	}
+}
+
+func (c *fleetCollector) collectWorks(ch chan<- metrics.Metric) {
+	works, err := c.workLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list ManifestWorks for metrics: %v", err)
+		return
+	}
+
+	counts := map[string]map[string]int{}
+	for _, work := range works {
+		if _, ok := counts[work.Namespace]; !ok {
+			counts[work.Namespace] = map[string]int{}
+		}
+		counts[work.Namespace][WorkStateTotal]++
+		if meta.IsStatusConditionTrue(work.Status.Conditions, workapiv1.WorkApplied) {
+			counts[work.Namespace][WorkStateApplied]++
+		}
+		if meta.IsStatusConditionTrue(work.Status.Conditions, workapiv1.WorkAvailable) {
+			counts[work.Namespace][WorkStateAvailable]++
+		}
+		if meta.IsStatusConditionTrue(work.Status.Conditions, workapiv1.WorkDegraded) {
+			counts[work.Namespace][WorkStateDegraded]++
+		}
+	}
+
+	for clusterName, count := range counts {
+		for _, state := range []string{WorkStateTotal, WorkStateApplied, WorkStateAvailable, WorkStateDegraded} {
+			ch <- metrics.NewLazyConstMetric(worksDesc, metrics.GaugeValue, float64(count[state]), clusterName, state)
+		}
+	}
+}
+
+func (c *fleetCollector) collectPlacements(ch chan<- metrics.Metric, available map[string]bool) {
+	placements, err := c.placementLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list Placements for metrics: %v", err)
+		return
+	}
+
+	for _, placement := range placements {
+		decisions, err := c.decisionLister.PlacementDecisions(placement.Namespace).List(labels.SelectorFromSet(labels.Set{
+			clusterv1beta1.PlacementLabel: placement.Name,
+		}))
+		if err != nil {
+			klog.Errorf("Failed to list PlacementDecisions for metrics: %v", err)
+			return
+		}
+
+		misplaced := 0
+		for _, decision := range decisions {
+			for _, d := range decision.Status.Decisions {
+				if !available[d.ClusterName] {
+					misplaced++
+				}
+			}
+		}
+		ch <- metrics.NewLazyConstMetric(placementDecisionsDesc, metrics.GaugeValue,
+			float64(placement.Status.NumberOfSelectedClusters), placement.Namespace, placement.Name)
+		ch <- metrics.NewLazyConstMetric(placementMisplacedDesc, metrics.GaugeValue,
+			float64(misplaced), placement.Namespace, placement.Name)
+	}
+}
+
+func (c *fleetCollector) collectAddons(ch chan<- metrics.Metric) {
+	addons, err := c.addonLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list ManagedClusterAddOns for metrics: %v", err)
+		return
+	}
+
+	for _, addon := range addons {
+		ch <- metrics.NewLazyConstMetric(addonInfoDesc, metrics.GaugeValue, 1,
+			addon.Namespace, addon.Name, addonmetrics.AddonState(addon))
+	}
+}
+
+func boolLabel(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
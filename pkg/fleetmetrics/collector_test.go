@@ -0,0 +1,156 @@
+package fleetmetrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/metrics/testutil"
+
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	fakeaddon "open-cluster-management.io/api/client/addon/clientset/versioned/fake"
+	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
+	fakecluster "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	fakework "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+func newCondition(conditionType string, status metav1.ConditionStatus) metav1.Condition {
+	return metav1.Condition{Type: conditionType, Status: status}
+}
+
+func TestFleetCollector(t *testing.T) {
+	clusterInformers := clusterinformers.NewSharedInformerFactory(fakecluster.NewSimpleClientset(), 10*time.Minute)
+	workInformers := workinformers.NewSharedInformerFactory(fakework.NewSimpleClientset(), 10*time.Minute)
+	addonInformers := addoninformers.NewSharedInformerFactory(fakeaddon.NewSimpleClientset(), 10*time.Minute)
+
+	clusters := []*clusterv1.ManagedCluster{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster1"},
+			Spec:       clusterv1.ManagedClusterSpec{HubAcceptsClient: true},
+			Status: clusterv1.ManagedClusterStatus{
+				Conditions: []metav1.Condition{
+					newCondition(clusterv1.ManagedClusterConditionJoined, metav1.ConditionTrue),
+					newCondition(clusterv1.ManagedClusterConditionAvailable, metav1.ConditionTrue),
+				},
+				Version:       clusterv1.ManagedClusterVersion{Kubernetes: "v1.27.0"},
+				ClusterClaims: []clusterv1.ManagedClusterClaim{{Name: "region", Value: "us-east-1"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster2"},
+		},
+	}
+	for _, cluster := range clusters {
+		if err := clusterInformers.Cluster().V1().ManagedClusters().Informer().GetStore().Add(cluster); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	works := []*workapiv1.ManifestWork{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "work1", Namespace: "cluster1"},
+			Status: workapiv1.ManifestWorkStatus{Conditions: []metav1.Condition{
+				newCondition(workapiv1.WorkApplied, metav1.ConditionTrue),
+				newCondition(workapiv1.WorkAvailable, metav1.ConditionTrue),
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "work2", Namespace: "cluster1"},
+			Status: workapiv1.ManifestWorkStatus{Conditions: []metav1.Condition{
+				newCondition(workapiv1.WorkApplied, metav1.ConditionTrue),
+				newCondition(workapiv1.WorkDegraded, metav1.ConditionTrue),
+			}},
+		},
+	}
+	for _, work := range works {
+		if err := workInformers.Work().V1().ManifestWorks().Informer().GetStore().Add(work); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	placement := &clusterv1beta1.Placement{
+		ObjectMeta: metav1.ObjectMeta{Name: "placement1", Namespace: "default"},
+		Status:     clusterv1beta1.PlacementStatus{NumberOfSelectedClusters: 3},
+	}
+	if err := clusterInformers.Cluster().V1beta1().Placements().Informer().GetStore().Add(placement); err != nil {
+		t.Fatal(err)
+	}
+	decision := &clusterv1beta1.PlacementDecision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "placement1-decision-1",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1beta1.PlacementLabel: "placement1"},
+		},
+		Status: clusterv1beta1.PlacementDecisionStatus{Decisions: []clusterv1beta1.ClusterDecision{
+			{ClusterName: "cluster1"}, {ClusterName: "cluster2"}, {ClusterName: "cluster3"},
+		}},
+	}
+	if err := clusterInformers.Cluster().V1beta1().PlacementDecisions().Informer().GetStore().Add(decision); err != nil {
+		t.Fatal(err)
+	}
+
+	addon := &addonv1alpha1.ManagedClusterAddOn{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello", Namespace: "cluster1"},
+		Status: addonv1alpha1.ManagedClusterAddOnStatus{Conditions: []metav1.Condition{
+			newCondition(addonv1alpha1.ManagedClusterAddOnConditionAvailable, metav1.ConditionTrue),
+		}},
+	}
+	if err := addonInformers.Addon().V1alpha1().ManagedClusterAddOns().Informer().GetStore().Add(addon); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `
+# HELP fleet_managed_cluster_addon_info [ALPHA] State of a ManagedClusterAddOn, the value is always 1.
+# TYPE fleet_managed_cluster_addon_info gauge
+fleet_managed_cluster_addon_info{addon_name="hello",managed_cluster="cluster1",state="available"} 1
+# HELP fleet_managed_cluster_claim [ALPHA] A cluster claim reported by a ManagedCluster, the value is always 1.
+# TYPE fleet_managed_cluster_claim gauge
+fleet_managed_cluster_claim{claim="region",managed_cluster="cluster1",value="us-east-1"} 1
+# HELP fleet_managed_cluster_condition [ALPHA] The condition of a ManagedCluster, the value is 1 for the current status of the condition and 0 for the others.
+# TYPE fleet_managed_cluster_condition gauge
+fleet_managed_cluster_condition{condition="ManagedClusterConditionAvailable",managed_cluster="cluster1",status="false"} 0
+fleet_managed_cluster_condition{condition="ManagedClusterConditionAvailable",managed_cluster="cluster1",status="true"} 1
+fleet_managed_cluster_condition{condition="ManagedClusterConditionAvailable",managed_cluster="cluster1",status="unknown"} 0
+fleet_managed_cluster_condition{condition="ManagedClusterConditionAvailable",managed_cluster="cluster2",status="false"} 0
+fleet_managed_cluster_condition{condition="ManagedClusterConditionAvailable",managed_cluster="cluster2",status="true"} 0
+fleet_managed_cluster_condition{condition="ManagedClusterConditionAvailable",managed_cluster="cluster2",status="unknown"} 1
+fleet_managed_cluster_condition{condition="ManagedClusterJoined",managed_cluster="cluster1",status="false"} 0
+fleet_managed_cluster_condition{condition="ManagedClusterJoined",managed_cluster="cluster1",status="true"} 1
+fleet_managed_cluster_condition{condition="ManagedClusterJoined",managed_cluster="cluster1",status="unknown"} 0
+fleet_managed_cluster_condition{condition="ManagedClusterJoined",managed_cluster="cluster2",status="false"} 0
+fleet_managed_cluster_condition{condition="ManagedClusterJoined",managed_cluster="cluster2",status="true"} 0
+fleet_managed_cluster_condition{condition="ManagedClusterJoined",managed_cluster="cluster2",status="unknown"} 1
+# HELP fleet_managed_cluster_info [ALPHA] Information of a ManagedCluster, the value is always 1.
+# TYPE fleet_managed_cluster_info gauge
+fleet_managed_cluster_info{hub_accepted="false",kubernetes_version="",managed_cluster="cluster2"} 1
+fleet_managed_cluster_info{hub_accepted="true",kubernetes_version="v1.27.0",managed_cluster="cluster1"} 1
+# HELP fleet_manifest_works [ALPHA] Number of ManifestWorks of a ManagedCluster in each state.
+# TYPE fleet_manifest_works gauge
+fleet_manifest_works{managed_cluster="cluster1",state="applied"} 2
+fleet_manifest_works{managed_cluster="cluster1",state="available"} 1
+fleet_manifest_works{managed_cluster="cluster1",state="degraded"} 1
+fleet_manifest_works{managed_cluster="cluster1",state="total"} 2
+# HELP fleet_placement_decisions [ALPHA] Number of ManagedClusters selected by a Placement.
+# TYPE fleet_placement_decisions gauge
+fleet_placement_decisions{namespace="default",placement="placement1"} 3
+# HELP fleet_placement_misplaced_decisions [ALPHA] Number of ManagedClusters selected by a Placement which are not found or not available.
+# TYPE fleet_placement_misplaced_decisions gauge
+fleet_placement_misplaced_decisions{namespace="default",placement="placement1"} 2
+`
+	collector := NewFleetCollector(
+		clusterInformers.Cluster().V1().ManagedClusters().Lister(),
+		workInformers.Work().V1().ManifestWorks().Lister(),
+		clusterInformers.Cluster().V1beta1().Placements().Lister(),
+		clusterInformers.Cluster().V1beta1().PlacementDecisions().Lister(),
+		addonInformers.Addon().V1alpha1().ManagedClusterAddOns().Lister(),
+	)
+	if err := testutil.CustomCollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,3 @@
+// Package fleetmetrics contains the exporter which publishes the state of the fleet of the hub as metrics, in the
+// style of kube-state-metrics, so the fleet dashboards are built on the metrics without querying the API.
+package fleetmetrics
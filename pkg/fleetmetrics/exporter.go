@@ -0,0 +1,84 @@
+package fleetmetrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/openshift/library-go/pkg/controller/controllercmd"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	addonclient "open-cluster-management.io/api/client/addon/clientset/versioned"
+	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
+	clusterclient "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	workclient "open-cluster-management.io/api/client/work/clientset/versioned"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
+
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+)
+
+// ExporterOptions holds the configuration of the fleet metrics exporter
+type ExporterOptions struct{}
+
+// NewExporterOptions returns an ExporterOptions
+func NewExporterOptions() *ExporterOptions {
+	return &ExporterOptions{}
+}
+
+// RunExporter caches the ManagedClusters, ManifestWorks, Placements and ManagedClusterAddOns of the hub, and serves
+// their state as metrics on the /metrics endpoint of the exporter.
+func (o *ExporterOptions) RunExporter(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
+	clusterClient, err := clusterclient.NewForConfig(controllerContext.KubeConfig)
+	if err != nil {
+		return err
+	}
+	workClient, err := workclient.NewForConfig(controllerContext.KubeConfig)
+	if err != nil {
+		return err
+	}
+	addonClient, err := addonclient.NewForConfig(controllerContext.KubeConfig)
+	if err != nil {
+		return err
+	}
+
+	clusterInformers := clusterinformers.NewSharedInformerFactory(clusterClient, 30*time.Minute)
+	workInformers := workinformers.NewSharedInformerFactoryWithOptions(workClient, 30*time.Minute,
+		workinformers.WithTweakListOptions(commonhelpers.PaginatedListOptions(commonhelpers.DefaultListPageSize)))
+	addonInformers := addoninformers.NewSharedInformerFactory(addonClient, 30*time.Minute)
+
+	if err := commonhelpers.SetInformerTransform(commonhelpers.TrimManagedFieldsAndLastApplied,
+		clusterInformers.Cluster().V1().ManagedClusters().Informer(),
+		clusterInformers.Cluster().V1beta1().Placements().Informer(),
+		clusterInformers.Cluster().V1beta1().PlacementDecisions().Informer(),
+		workInformers.Work().V1().ManifestWorks().Informer(),
+		addonInformers.Addon().V1alpha1().ManagedClusterAddOns().Informer(),
+	); err != nil {
+		return err
+	}
+
+	legacyregistry.CustomMustRegister(NewFleetCollector(
+		clusterInformers.Cluster().V1().ManagedClusters().Lister(),
+		workInformers.Work().V1().ManifestWorks().Lister(),
+		clusterInformers.Cluster().V1beta1().Placements().Lister(),
+		clusterInformers.Cluster().V1beta1().PlacementDecisions().Lister(),
+		addonInformers.Addon().V1alpha1().ManagedClusterAddOns().Lister(),
+	))
+
+	go clusterInformers.Start(ctx.Done())
+	go workInformers.Start(ctx.Done())
+	go addonInformers.Start(ctx.Done())
+
+	if !cache.WaitForCacheSync(ctx.Done(),
+		clusterInformers.Cluster().V1().ManagedClusters().Informer().HasSynced,
+		clusterInformers.Cluster().V1beta1().Placements().Informer().HasSynced,
+		clusterInformers.Cluster().V1beta1().PlacementDecisions().Informer().HasSynced,
+		workInformers.Work().V1().ManifestWorks().Informer().HasSynced,
+		addonInformers.Addon().V1alpha1().ManagedClusterAddOns().Informer().HasSynced,
+	) {
+		return ctx.Err()
+	}
+
+	<-ctx.Done()
+	return nil
+}
@@ -3,6 +3,7 @@ package certrotation
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
 	"fmt"
 	"time"
 
@@ -15,9 +16,12 @@ import (
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/util/cert"
+
+	"open-cluster-management.io/ocm/pkg/common/fips"
+	"open-cluster-management.io/ocm/pkg/common/helpers"
 )
 
-// SigningRotation rotates a self-signed signing CA stored in a secret. It creates a new one when 80%
+// SigningRotation rotates a self-signed signing CA stored in a secret. It creates a new one when 70% to 80%
 // of the lifetime of the old CA has passed.
 type SigningRotation struct {
 	Namespace        string
@@ -81,10 +85,14 @@ func needNewSigningCertKeyPair(secret *corev1.Secret) string {
 		return "already expired"
 	}
 
-	maxWait := cert.NotAfter.Sub(cert.NotBefore) / 5
-	latestTime := cert.NotAfter.Add(-maxWait)
+	if fips.Enabled() {
+		if err := fips.ValidateCertificates(cert); err != nil {
+			return err.Error()
+		}
+	}
+
 	now := time.Now()
-	if now.After(latestTime) {
+	if now.After(renewalTime(cert)) {
 		return fmt.Sprintf("expired in %6.3f seconds", cert.NotAfter.Sub(now).Seconds())
 	}
 
@@ -113,3 +121,11 @@ func setSigningCertKeyPairSecret(signingCertKeyPairSecret *corev1.Secret, signer
 
 	return nil
 }
+
+// renewalTime returns the time to renew the cert, which is after 70% to 80% of its lifetime. The jitter is stable for
+// a cert, so the certs created at the same time are renewed at different times instead of all at once.
+func renewalTime(cert *x509.Certificate) time.Time {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	maxWait := lifetime/5 + time.Duration(float64(lifetime/10)*helpers.CertRenewalJitter(cert.Raw))
+	return cert.NotAfter.Add(-maxWait)
+}
@@ -17,9 +17,11 @@ import (
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/util/cert"
+
+	"open-cluster-management.io/ocm/pkg/common/fips"
 )
 
-// TargetRotation rotates a key and cert signed by a CA. It creates a new one when 80%
+// TargetRotation rotates a key and cert signed by a CA. It creates a new one when 70% to 80%
 // of the lifetime of the old cert has passed, or the CA used to signed the old cert is
 // gone from the CA bundle.
 type TargetRotation struct {
@@ -66,7 +68,7 @@ func (c TargetRotation) EnsureTargetCertKeyPair(ctx context.Context, signingCert
 // We create a new target cert/key pair if
 //  1. no cert/key pair exits
 //  2. or the cert expired (then we are also pretty late)
-//  3. or we are over the renewal percentage of the validity
+//  3. or we are over the renewal percentage (70% to 80%) of the validity
 //  4. or our old CA is gone from the bundle (then we are pretty late to the renewal party)
 func needNewTargetCertKeyPair(secret *corev1.Secret, caBundleCerts []*x509.Certificate) string {
 	certData := secret.Data["tls.crt"]
@@ -87,10 +89,14 @@ func needNewTargetCertKeyPair(secret *corev1.Secret, caBundleCerts []*x509.Certi
 		return "already expired"
 	}
 
-	maxWait := cert.NotAfter.Sub(cert.NotBefore) / 5
-	latestTime := cert.NotAfter.Add(-maxWait)
+	if fips.Enabled() {
+		if err := fips.ValidateCertificates(cert); err != nil {
+			return err.Error()
+		}
+	}
+
 	now := time.Now()
-	if now.After(latestTime) {
+	if now.After(renewalTime(cert)) {
 		return fmt.Sprintf("expired in %6.3f seconds", cert.NotAfter.Sub(now).Seconds())
 	}
 
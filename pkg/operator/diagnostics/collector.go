@@ -0,0 +1,301 @@
+package diagnostics
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/operator/helpers"
+)
+
+// secretSummary is the metadata of a secret in the bundle, the contents of the secret are never collected.
+type secretSummary struct {
+	Name              string            `json:"name"`
+	Type              corev1.SecretType `json:"type"`
+	Keys              []string          `json:"keys,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	CreationTimestamp metav1.Time       `json:"creationTimestamp"`
+}
+
+// csrSummary is the state of a CSR of a managed cluster in the bundle, the request itself is not collected.
+type csrSummary struct {
+	Name              string                                              `json:"name"`
+	SignerName        string                                              `json:"signerName"`
+	Username          string                                              `json:"username"`
+	ClusterName       string                                              `json:"clusterName"`
+	Conditions        []certificatesv1.CertificateSigningRequestCondition `json:"conditions,omitempty"`
+	Issued            bool                                                `json:"issued"`
+	CreationTimestamp metav1.Time                                         `json:"creationTimestamp"`
+}
+
+// Collector collects the state of the cluster manager and the klusterlets on a cluster into a bundle.
+type Collector struct {
+	kubeClient     kubernetes.Interface
+	operatorClient operatorclient.Interface
+	// namespaces are collected in addition to the namespaces of the cluster managers and the klusterlets, they are
+	// the namespaces of the operators usually.
+	namespaces []string
+	// logTailLines is the number of the lines of the logs collected for each container.
+	logTailLines int64
+	// eventsSince is the age of the oldest events collected.
+	eventsSince time.Duration
+	now         func() time.Time
+}
+
+// NewCollector returns a Collector
+func NewCollector(kubeClient kubernetes.Interface, operatorClient operatorclient.Interface,
+	namespaces []string, logTailLines int64, eventsSince time.Duration) *Collector {
+	return &Collector{
+		kubeClient:     kubeClient,
+		operatorClient: operatorClient,
+		namespaces:     namespaces,
+		logTailLines:   logTailLines,
+		eventsSince:    eventsSince,
+		now:            time.Now,
+	}
+}
+
+// Collect writes the bundle as a gzipped tarball to the writer. The failures to collect a part of the bundle are
+// recorded in the errors.txt of the bundle rather than aborting the collection, so the bundle is as complete as
+// possible for a support case.
+func (c *Collector) Collect(ctx context.Context, w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	b := &bundle{writer: tw, modTime: c.now()}
+
+	namespaces := sets.New[string](c.namespaces...)
+
+	clusterManagers, err := c.operatorClient.OperatorV1().ClusterManagers().List(ctx, metav1.ListOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		// the cluster manager is not installed
+	case err != nil:
+		b.recordError("clustermanagers", err)
+	default:
+		for i := range clusterManagers.Items {
+			cm := &clusterManagers.Items[i]
+			cm.ManagedFields = nil
+			b.addYAML(path.Join("clustermanagers", cm.Name+".yaml"), cm)
+			namespaces.Insert(helpers.ClusterManagerNamespace(cm.Name, cm.Spec.DeployOption.Mode))
+		}
+	}
+
+	klusterlets, err := c.operatorClient.OperatorV1().Klusterlets().List(ctx, metav1.ListOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		// the klusterlet is not installed
+	case err != nil:
+		b.recordError("klusterlets", err)
+	default:
+		for i := range klusterlets.Items {
+			klusterlet := &klusterlets.Items[i]
+			klusterlet.ManagedFields = nil
+			b.addYAML(path.Join("klusterlets", klusterlet.Name+".yaml"), klusterlet)
+			namespaces.Insert(helpers.KlusterletNamespace(klusterlet), helpers.AgentNamespace(klusterlet))
+		}
+	}
+
+	for _, namespace := range sets.List(namespaces) {
+		c.collectNamespace(ctx, b, namespace)
+	}
+	c.collectCSRs(ctx, b)
+
+	if len(b.errors) > 0 {
+		b.addFile("errors.txt", []byte(strings.Join(b.errors, "\n")+"\n"))
+	}
+	if b.err != nil {
+		return b.err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func (c *Collector) collectNamespace(ctx context.Context, b *bundle, namespace string) {
+	dir := path.Join("namespaces", namespace)
+
+	pods, err := c.kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.recordError(path.Join(dir, "pods"), err)
+	} else {
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			pod.ManagedFields = nil
+			b.addYAML(path.Join(dir, "pods", pod.Name+".yaml"), pod)
+			for _, container := range pod.Spec.Containers {
+				c.collectLogs(ctx, b, dir, pod, container.Name)
+			}
+		}
+	}
+
+	secrets, err := c.kubeClient.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.recordError(path.Join(dir, "secrets"), err)
+	} else {
+		var summaries []secretSummary
+		for _, secret := range secrets.Items {
+			summaries = append(summaries, summarizeSecret(secret))
+		}
+		b.addYAML(path.Join(dir, "secrets.yaml"), summaries)
+	}
+
+	events, err := c.kubeClient.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.recordError(path.Join(dir, "events"), err)
+	} else {
+		since := c.now().Add(-c.eventsSince)
+		var recent []corev1.Event
+		for _, event := range events.Items {
+			if eventTime(event).Before(since) {
+				continue
+			}
+			event.ManagedFields = nil
+			recent = append(recent, event)
+		}
+		sort.Slice(recent, func(i, j int) bool {
+			return eventTime(recent[i]).Before(eventTime(recent[j]))
+		})
+		b.addYAML(path.Join(dir, "events.yaml"), recent)
+	}
+}
+
+func (c *Collector) collectLogs(ctx context.Context, b *bundle, dir string, pod *corev1.Pod, container string) {
+	name := path.Join(dir, "logs", pod.Name, container+".log")
+	stream, err := c.kubeClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		TailLines: &c.logTailLines,
+	}).Stream(ctx)
+	if err != nil {
+		b.recordError(name, err)
+		return
+	}
+	defer stream.Close()
+
+	logs, err := io.ReadAll(stream)
+	if err != nil {
+		b.recordError(name, err)
+	}
+	b.addFile(name, logs)
+}
+
+func (c *Collector) collectCSRs(ctx context.Context, b *bundle) {
+	csrs, err := c.kubeClient.CertificatesV1().CertificateSigningRequests().List(ctx, metav1.ListOptions{
+		LabelSelector: clusterv1.ClusterNameLabelKey,
+	})
+	if err != nil {
+		b.recordError("certificatesigningrequests", err)
+		return
+	}
+
+	var summaries []csrSummary
+	for _, csr := range csrs.Items {
+		summaries = append(summaries, csrSummary{
+			Name:              csr.Name,
+			SignerName:        csr.Spec.SignerName,
+			Username:          csr.Spec.Username,
+			ClusterName:       csr.Labels[clusterv1.ClusterNameLabelKey],
+			Conditions:        csr.Status.Conditions,
+			Issued:            len(csr.Status.Certificate) > 0,
+			CreationTimestamp: csr.CreationTimestamp,
+		})
+	}
+	b.addYAML("certificatesigningrequests.yaml", summaries)
+}
+
+func summarizeSecret(secret corev1.Secret) secretSummary {
+	var keys []string
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// the last-applied annotation may carry the contents of the secret
+	annotations := map[string]string{}
+	for key, value := range secret.Annotations {
+		if key == corev1.LastAppliedConfigAnnotation {
+			continue
+		}
+		annotations[key] = value
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	return secretSummary{
+		Name:              secret.Name,
+		Type:              secret.Type,
+		Keys:              keys,
+		Annotations:       annotations,
+		CreationTimestamp: secret.CreationTimestamp,
+	}
+}
+
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// bundle writes the files into the tarball, the first error to write is kept and the following files are skipped.
+type bundle struct {
+	writer  *tar.Writer
+	modTime time.Time
+	errors  []string
+	err     error
+}
+
+func (b *bundle) addYAML(name string, obj interface{}) {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		b.recordError(name, err)
+		return
+	}
+	b.addFile(name, data)
+}
+
+func (b *bundle) addFile(name string, data []byte) {
+	if b.err != nil {
+		return
+	}
+	if err := b.writer.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: b.modTime,
+	}); err != nil {
+		b.err = err
+		return
+	}
+	if _, err := b.writer.Write(data); err != nil {
+		b.err = err
+	}
+}
+
+func (b *bundle) recordError(name string, err error) {
+	klog.Warningf("Failed to collect %s: %v", name, err)
+	b.errors = append(b.errors, fmt.Sprintf("%s: %v", name, err))
+}
@@ -0,0 +1,168 @@
+package diagnostics
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+
+	fakeoperator "open-cluster-management.io/api/client/operator/clientset/versioned/fake"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+)
+
+func readBundle(t *testing.T, data []byte) map[string]string {
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gr)
+	files := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[header.Name] = string(content)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	now := time.Now()
+
+	kubeObjects := []runtime.Object{
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-manager-registration-controller", Namespace: "open-cluster-management-hub"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "hub-registration-controller"}}},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "klusterlet-agent", Namespace: "open-cluster-management-agent"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "klusterlet-agent"}}},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "hub-kubeconfig-secret",
+				Namespace: "open-cluster-management-agent",
+				Annotations: map[string]string{
+					corev1.LastAppliedConfigAnnotation: "secret-last-applied",
+					"owner":                            "klusterlet",
+				},
+			},
+			Data: map[string][]byte{"kubeconfig": []byte("secret-kubeconfig"), "tls.key": []byte("secret-key")},
+		},
+		&corev1.Event{
+			ObjectMeta:    metav1.ObjectMeta{Name: "recent", Namespace: "open-cluster-management"},
+			Reason:        "RecentEvent",
+			LastTimestamp: metav1.NewTime(now.Add(-time.Minute)),
+		},
+		&corev1.Event{
+			ObjectMeta:    metav1.ObjectMeta{Name: "old", Namespace: "open-cluster-management"},
+			Reason:        "OldEvent",
+			LastTimestamp: metav1.NewTime(now.Add(-2 * time.Hour)),
+		},
+		&certificatesv1.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "cluster1-csr",
+				Labels: map[string]string{clusterv1.ClusterNameLabelKey: "cluster1"},
+			},
+			Spec: certificatesv1.CertificateSigningRequestSpec{
+				Request:    []byte("secret-request"),
+				SignerName: certificatesv1.KubeAPIServerClientSignerName,
+				Username:   "system:open-cluster-management:cluster1",
+			},
+			Status: certificatesv1.CertificateSigningRequestStatus{
+				Conditions: []certificatesv1.CertificateSigningRequestCondition{
+					{Type: certificatesv1.CertificateApproved, Status: corev1.ConditionTrue},
+				},
+				Certificate: []byte("secret-certificate"),
+			},
+		},
+		&certificatesv1.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-csr"},
+		},
+	}
+	operatorObjects := []runtime.Object{
+		&operatorapiv1.ClusterManager{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-manager"},
+			Spec: operatorapiv1.ClusterManagerSpec{
+				DeployOption: operatorapiv1.ClusterManagerDeployOption{Mode: operatorapiv1.InstallModeDefault},
+			},
+		},
+		&operatorapiv1.Klusterlet{
+			ObjectMeta: metav1.ObjectMeta{Name: "klusterlet"},
+			Spec: operatorapiv1.KlusterletSpec{
+				Namespace:    "open-cluster-management-agent",
+				DeployOption: operatorapiv1.KlusterletDeployOption{Mode: operatorapiv1.InstallModeSingleton},
+			},
+		},
+	}
+
+	collector := NewCollector(fakekube.NewSimpleClientset(kubeObjects...), fakeoperator.NewSimpleClientset(operatorObjects...),
+		[]string{defaultOperatorNamespace}, 100, time.Hour)
+	collector.now = func() time.Time { return now }
+
+	buf := &bytes.Buffer{}
+	if err := collector.Collect(context.TODO(), buf); err != nil {
+		t.Fatal(err)
+	}
+	files := readBundle(t, buf.Bytes())
+
+	for _, name := range []string{
+		"clustermanagers/cluster-manager.yaml",
+		"klusterlets/klusterlet.yaml",
+		"namespaces/open-cluster-management/events.yaml",
+		"namespaces/open-cluster-management-hub/pods/cluster-manager-registration-controller.yaml",
+		"namespaces/open-cluster-management-hub/logs/cluster-manager-registration-controller/hub-registration-controller.log",
+		"namespaces/open-cluster-management-agent/pods/klusterlet-agent.yaml",
+		"namespaces/open-cluster-management-agent/logs/klusterlet-agent/klusterlet-agent.log",
+		"namespaces/open-cluster-management-agent/secrets.yaml",
+		"certificatesigningrequests.yaml",
+	} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("expected %s in the bundle", name)
+		}
+	}
+	if _, ok := files["errors.txt"]; ok {
+		t.Errorf("unexpected errors in the bundle: %s", files["errors.txt"])
+	}
+
+	for name, content := range files {
+		if strings.Contains(content, "secret-") {
+			t.Errorf("expected the contents of the secrets and the csrs are not collected, but got in %s:\n%s", name, content)
+		}
+	}
+
+	secrets := files["namespaces/open-cluster-management-agent/secrets.yaml"]
+	for _, expected := range []string{"hub-kubeconfig-secret", "kubeconfig", "tls.key", "owner: klusterlet"} {
+		if !strings.Contains(secrets, expected) {
+			t.Errorf("expected %q in the secrets, but got:\n%s", expected, secrets)
+		}
+	}
+
+	events := files["namespaces/open-cluster-management/events.yaml"]
+	if !strings.Contains(events, "RecentEvent") || strings.Contains(events, "OldEvent") {
+		t.Errorf("expected only the recent events, but got:\n%s", events)
+	}
+
+	csrs := files["certificatesigningrequests.yaml"]
+	if !strings.Contains(csrs, "cluster1-csr") || !strings.Contains(csrs, "issued: true") || strings.Contains(csrs, "other-csr") {
+		t.Errorf("unexpected csrs:\n%s", csrs)
+	}
+}
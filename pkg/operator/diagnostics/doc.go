@@ -0,0 +1,5 @@
+// Package diagnostics collects a support bundle of a cluster running the cluster manager or the klusterlet. The bundle
+// is a gzipped tarball containing the ClusterManagers and Klusterlets, the pods, the container logs, the metadata of
+// the secrets and the recent events of the related namespaces, and the states of the CSRs of the managed clusters.
+// The contents of the secrets and of the CSRs are never collected.
+package diagnostics
@@ -0,0 +1,104 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+
+	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
+)
+
+const defaultOperatorNamespace = "open-cluster-management"
+
+// Options holds the configuration of the diagnostics collection
+type Options struct {
+	Kubeconfig         string
+	Output             string
+	OperatorNamespaces []string
+	LogTailLines       int64
+	EventsSince        time.Duration
+}
+
+// NewOptions returns an Options with the defaults
+func NewOptions() *Options {
+	return &Options{
+		OperatorNamespaces: []string{defaultOperatorNamespace},
+		LogTailLines:       1000,
+		EventsSince:        time.Hour,
+	}
+}
+
+// AddFlags registers the flags of the diagnostics collection
+func (o *Options) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&o.Kubeconfig, "kubeconfig", o.Kubeconfig,
+		"The kubeconfig of the cluster to collect from, the KUBECONFIG env or ~/.kube/config is used if it is not set.")
+	flags.StringVarP(&o.Output, "output", "o", o.Output,
+		"The path of the gzipped tarball to write, defaults to ocm-diagnostics-<timestamp>.tar.gz in the current directory.")
+	flags.StringSliceVar(&o.OperatorNamespaces, "operator-namespaces", o.OperatorNamespaces,
+		"The namespaces of the operators to collect, in addition to the namespaces of the cluster managers and the klusterlets.")
+	flags.Int64Var(&o.LogTailLines, "log-tail-lines", o.LogTailLines,
+		"The number of the lines of the logs collected for each container.")
+	flags.DurationVar(&o.EventsSince, "events-since", o.EventsSince,
+		"Only the events not older than this duration are collected.")
+}
+
+// Validate verifies the options
+func (o *Options) Validate() error {
+	if o.LogTailLines <= 0 {
+		return fmt.Errorf("log-tail-lines must be greater than 0")
+	}
+	if o.EventsSince <= 0 {
+		return fmt.Errorf("events-since must be greater than 0")
+	}
+	return nil
+}
+
+// Run collects the diagnostics bundle of the cluster into the output file
+func (o *Options) Run(ctx context.Context) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.Kubeconfig
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	operatorClient, err := operatorclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	output := o.Output
+	if len(output) == 0 {
+		output = fmt.Sprintf("ocm-diagnostics-%s.tar.gz", time.Now().UTC().Format("20060102150405"))
+	}
+	file, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	collector := NewCollector(kubeClient, operatorClient, o.OperatorNamespaces, o.LogTailLines, o.EventsSince)
+	if err := collector.Collect(ctx, file); err != nil {
+		return fmt.Errorf("failed to collect the diagnostics bundle: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	klog.Infof("The diagnostics bundle is written to %s", output)
+	return nil
+}
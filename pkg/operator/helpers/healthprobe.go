@@ -0,0 +1,109 @@
+package helpers
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ComponentHealthPort is the port the hub components and the agents serve the /healthz endpoint on.
+	ComponentHealthPort = 8443
+	// HealthProbeResyncInterval is the interval the status controllers probe the components when the health probe is
+	// enabled, since a wedged component does not change its deployment.
+	HealthProbeResyncInterval = time.Minute
+	// HealthProbeTimeout is the timeout of a probe to a pod.
+	HealthProbeTimeout = 5 * time.Second
+
+	maxHealthProbeBodyBytes = 1024
+)
+
+// HealthProber probes the health of a pod of the hub components or the agents.
+type HealthProber interface {
+	Probe(ctx context.Context, pod *corev1.Pod) error
+}
+
+type httpHealthProber struct {
+	client *http.Client
+	port   int
+}
+
+// NewHTTPHealthProber returns a prober requesting the /healthz endpoint on the port of the pods. The endpoint is
+// served with the self-signed certificates of the components, so the certificates are not verified.
+func NewHTTPHealthProber(port int, timeout time.Duration) HealthProber {
+	return &httpHealthProber{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //#nosec G402
+			},
+		},
+		port: port,
+	}
+}
+
+func (p *httpHealthProber) Probe(ctx context.Context, pod *corev1.Pod) error {
+	if len(pod.Status.PodIP) == 0 {
+		return fmt.Errorf("pod %q has no IP", pod.Name)
+	}
+
+	url := fmt.Sprintf("https://%s/healthz", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(p.port)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("pod %q: %w", pod.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxHealthProbeBodyBytes))
+		return fmt.Errorf("pod %q returned %d: %s", pod.Name, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// ProbeDeploymentPods probes the running pods of the deployment, it returns the number of the unhealthy pods and the
+// message of the failed probes. The pods not running or deleting are skipped, they are reported by the replicas of
+// the deployment.
+func ProbeDeploymentPods(ctx context.Context, kubeClient kubernetes.Interface, prober HealthProber,
+	deployment *appsv1.Deployment) (int, string, error) {
+	if deployment.Spec.Selector == nil {
+		return 0, "", nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return 0, "", err
+	}
+	pods, err := kubeClient.CoreV1().Pods(deployment.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return 0, "", err
+	}
+
+	var messages []string
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if err := prober.Probe(ctx, pod); err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+	return len(messages), strings.Join(messages, "; "), nil
+}
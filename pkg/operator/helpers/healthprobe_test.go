@@ -0,0 +1,71 @@
+package helpers
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestHTTPHealthProber(t *testing.T) {
+	cases := []struct {
+		name        string
+		statusCode  int
+		podIP       bool
+		expectedErr bool
+	}{
+		{
+			name:       "healthy",
+			statusCode: http.StatusOK,
+			podIP:      true,
+		},
+		{
+			name:        "unhealthy",
+			statusCode:  http.StatusInternalServerError,
+			podIP:       true,
+			expectedErr: true,
+		},
+		{
+			name:        "no pod ip",
+			statusCode:  http.StatusOK,
+			expectedErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/healthz" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.WriteHeader(c.statusCode)
+			}))
+			defer server.Close()
+
+			host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			portNum, err := strconv.Atoi(port)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pod := &corev1.Pod{}
+			pod.Name = "test"
+			if c.podIP {
+				pod.Status.PodIP = host
+			}
+
+			err = NewHTTPHealthProber(portNum, time.Second).Probe(context.TODO(), pod)
+			if c.expectedErr != (err != nil) {
+				t.Errorf("expected error %v, but got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
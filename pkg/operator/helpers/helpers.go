@@ -25,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/version"
 	"k8s.io/client-go/kubernetes"
 	admissionclient "k8s.io/client-go/kubernetes/typed/admissionregistration/v1"
@@ -429,7 +430,8 @@ func DetermineReplica(ctx context.Context, kubeClient kubernetes.Interface, mode
 		// And then the leader election of agent running on this cluster should be disabled, because
 		// it leverages the lease API. Kubernetes starts support lease/v1 from v1.14.
 		if cnt, err := kubeVersion.Compare("v1.14.0"); err != nil {
-			klog.Warningf("set replica to %d because it's failed to check whether the cluster supports lease/v1 or not: %v", singleReplica, err)
+			klog.FromContext(ctx).Error(err, "Failed to check whether the cluster supports lease/v1, set replica to single",
+				"replica", singleReplica)
 			return singleReplica
 		} else if cnt == -1 {
 			return singleReplica
@@ -756,3 +758,16 @@ func IsSingleton(mode operatorapiv1.InstallMode) bool {
 func IsHosted(mode operatorapiv1.InstallMode) bool {
 	return mode == operatorapiv1.InstallModeHosted || mode == operatorapiv1.InstallModeSingletonHosted
 }
+
+// ValidateSignerName returns an error if the name is not a valid signer name of the CSRs, which is in the format of
+// <domain>/<path>, e.g. example.com/corporate-ca.
+func ValidateSignerName(name string) error {
+	domain, path, ok := strings.Cut(name, "/")
+	if !ok || len(path) == 0 || strings.ContainsAny(path, " \t\"'") {
+		return fmt.Errorf("invalid signer name %q, it should be in the format of <domain>/<path>", name)
+	}
+	if errs := validation.IsDNS1123Subdomain(domain); len(errs) > 0 {
+		return fmt.Errorf("invalid signer name %q: %s", name, strings.Join(errs, ", "))
+	}
+	return nil
+}
@@ -36,6 +36,9 @@ const (
 	// ExternalManagedKubeConfigAgent is the secret name of kubeconfig secret to connecting to the managed cluster
 	// Only applicable to SingletonHosted mode, agent uses it to connect to the managed cluster.
 	ExternalManagedKubeConfigAgent = "external-managed-kubeconfig-agent"
+	// FeatureGatesConfigMapSuffix is the suffix of the name of the configmap in the agent namespace with the feature
+	// gates of the agents, the prefix is the name of the klusterlet.
+	FeatureGatesConfigMapSuffix = "-feature-gates"
 
 	RegistrationWebhookSecret  = "registration-webhook-serving-cert"
 	RegistrationWebhookService = "cluster-manager-registration-webhook"
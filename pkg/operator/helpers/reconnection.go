@@ -0,0 +1,169 @@
+package helpers
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+const (
+	// HubReconnection is the secret name of the signed reconnection manifest. It is created in the agent namespace
+	// when the hub is restored onto a new cluster with a new CA or endpoint, the klusterlet accepts the new hub
+	// only if the manifest of the cluster is signed by a dedicated signer trusted by the current hub kubeconfig,
+	// and it is not expired.
+	HubReconnection = "hub-reconnection"
+
+	// HubReconnectionKubeConfigKey is the key of the kubeconfig of the restored hub in the reconnection secret.
+	HubReconnectionKubeConfigKey = "kubeconfig"
+	// HubReconnectionClusterNameKey is the key of the name of the managed cluster the reconnection is for.
+	HubReconnectionClusterNameKey = "cluster-name"
+	// HubReconnectionExpirationKey is the key of the expiration time of the reconnection in RFC3339.
+	HubReconnectionExpirationKey = "expiration"
+	// HubReconnectionSignatureKey is the key of the signature of the kubeconfig, the cluster name and the
+	// expiration in the reconnection secret.
+	HubReconnectionSignatureKey = "signature"
+	// HubReconnectionSignerCertKey is the key of the cert of the signer in the reconnection secret.
+	HubReconnectionSignerCertKey = "signer.crt"
+
+	// HubReconnectionSignerCommonName is the common name of the signer cert of the reconnection secrets, the cert
+	// must also have the code signing extended key usage, so the client and serving certs issued by the hub CA
+	// cannot sign a reconnection.
+	HubReconnectionSignerCommonName = "open-cluster-management:hub-reconnection-signer"
+)
+
+// hubReconnectionContent returns the content signed in the reconnection secret.
+func hubReconnectionContent(kubeconfig []byte, clusterName, expiration string) ([]byte, error) {
+	return json.Marshal(struct {
+		KubeConfig  []byte `json:"kubeconfig"`
+		ClusterName string `json:"clusterName"`
+		Expiration  string `json:"expiration"`
+	}{
+		KubeConfig:  kubeconfig,
+		ClusterName: clusterName,
+		Expiration:  expiration,
+	})
+}
+
+// SignHubReconnection returns the reconnection secret of the kubeconfig for the managed cluster in the namespace,
+// the kubeconfig, the cluster name and the expiration are signed by the key of the signer cert.
+func SignHubReconnection(namespace, clusterName string, expiration time.Time,
+	kubeconfig, signerCertPEM []byte, key crypto.Signer) (*corev1.Secret, error) {
+	expirationValue := expiration.UTC().Format(time.RFC3339)
+	content, err := hubReconnectionContent(kubeconfig, clusterName, expirationValue)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(content)
+	signature, err := key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign the kubeconfig: %v", err)
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      HubReconnection,
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			HubReconnectionKubeConfigKey:  kubeconfig,
+			HubReconnectionClusterNameKey: []byte(clusterName),
+			HubReconnectionExpirationKey:  []byte(expirationValue),
+			HubReconnectionSignatureKey:   signature,
+			HubReconnectionSignerCertKey:  signerCertPEM,
+		},
+	}, nil
+}
+
+// VerifyHubReconnection verifies the reconnection secret of the managed cluster and returns the kubeconfig of the
+// restored hub. The signer cert in the secret must be the dedicated signer issued by one of the CA bundles, the
+// kubeconfig, the cluster name and the expiration must be signed by its key, and the reconnection must be for the
+// cluster and not expired at now.
+func VerifyHubReconnection(secret *corev1.Secret, clusterName string, now time.Time, caBundles ...[]byte) ([]byte, error) {
+	kubeconfig := secret.Data[HubReconnectionKubeConfigKey]
+	if len(kubeconfig) == 0 {
+		return nil, fmt.Errorf("there is no %q", HubReconnectionKubeConfigKey)
+	}
+	signature := secret.Data[HubReconnectionSignatureKey]
+	if len(signature) == 0 {
+		return nil, fmt.Errorf("there is no %q", HubReconnectionSignatureKey)
+	}
+
+	certs, err := certutil.ParseCertsPEM(secret.Data[HubReconnectionSignerCertKey])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the signer cert: %v", err)
+	}
+
+	roots := x509.NewCertPool()
+	for _, caBundle := range caBundles {
+		roots.AppendCertsFromPEM(caBundle)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	signer := certs[0]
+	if signer.Subject.CommonName != HubReconnectionSignerCommonName {
+		return nil, fmt.Errorf("the common name of the signer cert is %q, but expected %q",
+			signer.Subject.CommonName, HubReconnectionSignerCommonName)
+	}
+	// a cert without extended key usages is valid for any usage, so the code signing usage must be listed.
+	codeSigning := false
+	for _, usage := range signer.ExtKeyUsage {
+		if usage == x509.ExtKeyUsageCodeSigning {
+			codeSigning = true
+		}
+	}
+	if !codeSigning {
+		return nil, fmt.Errorf("the signer cert is not for code signing")
+	}
+	if _, err := signer.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return nil, fmt.Errorf("the signer cert is not trusted: %v", err)
+	}
+
+	var algorithm x509.SignatureAlgorithm
+	switch signer.PublicKey.(type) {
+	case *rsa.PublicKey:
+		algorithm = x509.SHA256WithRSA
+	case *ecdsa.PublicKey:
+		algorithm = x509.ECDSAWithSHA256
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T of the signer cert", signer.PublicKey)
+	}
+	content, err := hubReconnectionContent(kubeconfig,
+		string(secret.Data[HubReconnectionClusterNameKey]), string(secret.Data[HubReconnectionExpirationKey]))
+	if err != nil {
+		return nil, err
+	}
+	if err := signer.CheckSignature(algorithm, content, signature); err != nil {
+		return nil, fmt.Errorf("the kubeconfig is not signed by the signer: %v", err)
+	}
+
+	// the cluster name and the expiration are trusted only after the signature is verified.
+	if name := string(secret.Data[HubReconnectionClusterNameKey]); name != clusterName {
+		return nil, fmt.Errorf("the reconnection is for the cluster %q, but expected %q", name, clusterName)
+	}
+	expiration, err := time.Parse(time.RFC3339, string(secret.Data[HubReconnectionExpirationKey]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the expiration: %v", err)
+	}
+	if !now.Before(expiration) {
+		return nil, fmt.Errorf("the reconnection is expired at %s", expiration.Format(time.RFC3339))
+	}
+
+	return kubeconfig, nil
+}
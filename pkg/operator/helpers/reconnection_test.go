@@ -0,0 +1,198 @@
+package helpers
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	cryptorand "crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	certutil "k8s.io/client-go/util/cert"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+)
+
+func TestVerifyHubReconnection(t *testing.T) {
+	caPEM, caKey, caCert := newTestCA(t)
+	otherCAPEM, _, _ := newTestCA(t)
+
+	rsaKey, err := rsa.GenerateKey(cryptorand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), cryptorand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kubeconfig := []byte("kubeconfig of the restored hub")
+	now := time.Now()
+	cases := []struct {
+		name        string
+		key         crypto.Signer
+		commonName  string
+		extKeyUsage []x509.ExtKeyUsage
+		caBundles   [][]byte
+		kubeconfig  []byte
+		clusterName string
+		expiration  time.Time
+		tamper      func(data map[string][]byte)
+		expectedErr string
+	}{
+		{
+			name:      "signed by a rsa key",
+			key:       rsaKey,
+			caBundles: [][]byte{caPEM},
+		},
+		{
+			name:      "signed by an ecdsa key",
+			key:       ecdsaKey,
+			caBundles: [][]byte{otherCAPEM, caPEM},
+		},
+		{
+			name:        "untrusted signer",
+			key:         rsaKey,
+			caBundles:   [][]byte{otherCAPEM},
+			expectedErr: "the signer cert is not trusted",
+		},
+		{
+			name:        "signer with another common name",
+			key:         rsaKey,
+			commonName:  "system:open-cluster-management:cluster1:agent",
+			caBundles:   [][]byte{caPEM},
+			expectedErr: "the common name of the signer cert is",
+		},
+		{
+			name:        "signer without extended key usages",
+			key:         rsaKey,
+			extKeyUsage: []x509.ExtKeyUsage{},
+			caBundles:   [][]byte{caPEM},
+			expectedErr: "the signer cert is not for code signing",
+		},
+		{
+			name:        "client cert as signer",
+			key:         rsaKey,
+			extKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			caBundles:   [][]byte{caPEM},
+			expectedErr: "the signer cert is not for code signing",
+		},
+		{
+			name:        "tampered kubeconfig",
+			key:         rsaKey,
+			caBundles:   [][]byte{caPEM},
+			kubeconfig:  []byte("kubeconfig of another hub"),
+			expectedErr: "the kubeconfig is not signed by the signer: crypto/rsa: verification error",
+		},
+		{
+			name:      "tampered cluster name",
+			key:       rsaKey,
+			caBundles: [][]byte{caPEM},
+			tamper: func(data map[string][]byte) {
+				data[HubReconnectionClusterNameKey] = []byte("cluster2")
+			},
+			expectedErr: "the kubeconfig is not signed by the signer: crypto/rsa: verification error",
+		},
+		{
+			name:      "tampered expiration",
+			key:       rsaKey,
+			caBundles: [][]byte{caPEM},
+			tamper: func(data map[string][]byte) {
+				data[HubReconnectionExpirationKey] = []byte(now.Add(24 * time.Hour).UTC().Format(time.RFC3339))
+			},
+			expectedErr: "the kubeconfig is not signed by the signer: crypto/rsa: verification error",
+		},
+		{
+			name:        "another cluster",
+			key:         rsaKey,
+			caBundles:   [][]byte{caPEM},
+			clusterName: "cluster2",
+			expectedErr: `the reconnection is for the cluster "cluster2", but expected "cluster1"`,
+		},
+		{
+			name:        "expired",
+			key:         rsaKey,
+			caBundles:   [][]byte{caPEM},
+			expiration:  now.Add(-time.Minute),
+			expectedErr: "the reconnection is expired at",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			commonName := HubReconnectionSignerCommonName
+			if len(c.commonName) > 0 {
+				commonName = c.commonName
+			}
+			extKeyUsage := []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning}
+			if c.extKeyUsage != nil {
+				extKeyUsage = c.extKeyUsage
+			}
+			clusterName := "cluster1"
+			if len(c.clusterName) > 0 {
+				clusterName = c.clusterName
+			}
+			expiration := now.Add(time.Hour)
+			if !c.expiration.IsZero() {
+				expiration = c.expiration
+			}
+
+			signerPEM := newTestSignerCert(t, caCert, caKey, c.key, commonName, extKeyUsage)
+			secret, err := SignHubReconnection("test", clusterName, expiration, kubeconfig, signerPEM, c.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.kubeconfig != nil {
+				secret.Data[HubReconnectionKubeConfigKey] = c.kubeconfig
+			}
+			if c.tamper != nil {
+				c.tamper(secret.Data)
+			}
+
+			actual, err := VerifyHubReconnection(secret, "cluster1", now, c.caBundles...)
+			testingcommon.AssertErrorWithPrefix(t, err, c.expectedErr)
+			if err == nil && string(actual) != string(kubeconfig) {
+				t.Errorf("expected kubeconfig %q, but got %q", kubeconfig, actual)
+			}
+		})
+	}
+}
+
+func newTestCA(t *testing.T) ([]byte, *rsa.PrivateKey, *x509.Certificate) {
+	caKey, err := rsa.GenerateKey(cryptorand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := certutil.NewSelfSignedCACert(certutil.Config{CommonName: "open-cluster-management.io"}, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: caCert.Raw}), caKey, caCert
+}
+
+func newTestSignerCert(t *testing.T, caCert *x509.Certificate, caKey *rsa.PrivateKey, key crypto.Signer,
+	commonName string, extKeyUsage []x509.ExtKeyUsage) []byte {
+	certDERBytes, err := x509.CreateCertificate(
+		cryptorand.Reader,
+		&x509.Certificate{
+			Subject:      pkix.Name{CommonName: commonName},
+			SerialNumber: big.NewInt(1),
+			NotBefore:    caCert.NotBefore,
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  extKeyUsage,
+		},
+		caCert,
+		key.Public(),
+		caKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: certDERBytes})
+}
@@ -20,6 +20,7 @@ import (
 	operatorlister "open-cluster-management.io/api/client/operator/listers/operator/v1"
 	operatorv1 "open-cluster-management.io/api/operator/v1"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/operator/certrotation"
 	"open-cluster-management.io/ocm/pkg/operator/helpers"
@@ -77,7 +78,7 @@ func NewCertRotationController(
 	}
 	return factory.New().
 		ResyncEvery(ResyncInterval).
-		WithSync(c.sync).
+		WithSync(logging.WithControllerLogger("CertRotationController", c.sync)).
 		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterManagerInformer.Informer()).
 		WithInformersQueueKeysFunc(helpers.ClusterManagerQueueKeyFunc(c.clusterManagerLister),
 			configMapInformer.Informer(),
@@ -101,7 +102,7 @@ func (c certRotationController) sync(ctx context.Context, syncCtx factory.SyncCo
 
 		// do nothing if there is no cluster manager
 		if len(clustermanagers) == 0 {
-			klog.V(4).Infof("No ClusterManager found")
+			klog.FromContext(ctx).V(4).Info("No ClusterManager found")
 			return nil
 		}
 
@@ -134,7 +135,8 @@ func (c certRotationController) syncOne(ctx context.Context, syncCtx factory.Syn
 
 	var err error
 
-	klog.Infof("Reconciling ClusterManager %q", clustermanagerName)
+	logger := klog.FromContext(ctx)
+	logger.Info("Reconciling ClusterManager", "clusterManager", clustermanagerName)
 	// if the cluster manager is deleting, delete the rotation in map as well.
 	if !clustermanager.DeletionTimestamp.IsZero() {
 		// clean up all resources related with this clustermanager
@@ -2,8 +2,13 @@ package clustermanagercontroller
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/json"
 	errorhelpers "errors"
+	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,12 +20,15 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
 	appsinformer "k8s.io/client-go/informers/apps/v1"
 	corev1informers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
+	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/klog/v2"
 	migrationclient "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/typed/migration/v1alpha1"
 
@@ -31,6 +39,7 @@ import (
 	operatorapiv1 "open-cluster-management.io/api/operator/v1"
 
 	"open-cluster-management.io/ocm/manifests"
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/operator/helpers"
@@ -41,6 +50,71 @@ const (
 	clusterManagerApplied     = "Applied"
 	clusterManagerProgressing = "Progressing"
 
+	// webhookTLSMinVersionAnnotation and webhookTLSCipherSuitesAnnotation on the ClusterManager set the minimum TLS
+	// version, e.g. VersionTLS13, and the comma-separated TLS 1.2 cipher suites of the registration and work webhook
+	// servers.
+	webhookTLSMinVersionAnnotation   = "operator.open-cluster-management.io/webhook-tls-min-version"
+	webhookTLSCipherSuitesAnnotation = "operator.open-cluster-management.io/webhook-tls-cipher-suites"
+	// statusAggregationAnnotation on the ClusterManager set to "true" enables the summary of the fleet in the cluster
+	// claims of the hub, which are reported to the parent hub when the hub is also a managed cluster.
+	statusAggregationAnnotation = "operator.open-cluster-management.io/enable-status-aggregation"
+	// clusterProfileNamespaceAnnotation on the ClusterManager is the namespace of the ClusterProfiles mirrored from
+	// the ManagedClusters, the ClusterProfiles are not maintained without it.
+	clusterProfileNamespaceAnnotation = "operator.open-cluster-management.io/cluster-profile-namespace"
+	// clusterSetBindingRulesAnnotation on the ClusterManager is the name of a ConfigMap in the namespace of the
+	// cluster manager on the hub, containing the CEL validation rules of the ManagedClusterSetBinding creation.
+	clusterSetBindingRulesAnnotation = "operator.open-cluster-management.io/clustersetbinding-rules-configmap"
+	// trustedCABundleAnnotation on the ClusterManager is the name of a ConfigMap in the namespace where the hub
+	// controllers run, containing the additional CA certificates trusted by the hub controllers, e.g. the CA of a
+	// proxy between the hub controllers and the hub apiserver.
+	trustedCABundleAnnotation = "operator.open-cluster-management.io/trusted-ca-bundle"
+	// clusterNamePatternAnnotation, clusterNameMaxLengthAnnotation and clusterNameReservedPrefixesAnnotation on the
+	// ClusterManager set the naming policy of the ManagedClusters enforced by the registration webhook when the
+	// clusters join, which are the regular expression the whole name must match, the maximum length of the name and
+	// the comma-separated prefixes the name must not start with.
+	clusterNamePatternAnnotation          = "operator.open-cluster-management.io/cluster-name-pattern"
+	clusterNameMaxLengthAnnotation        = "operator.open-cluster-management.io/cluster-name-max-length"
+	clusterNameReservedPrefixesAnnotation = "operator.open-cluster-management.io/cluster-name-reserved-prefixes"
+	// clusterDefaultLeaseDurationAnnotation, clusterDefaultClusterSetAnnotation and
+	// normalizeClusterClientURLsAnnotation on the ClusterManager set the defaults of the ManagedClusters set by the
+	// registration webhook, which are the lease duration of the clusters created without one, the ManagedClusterSet
+	// of the clusters without the clusterset label, and whether the client URLs of the clusters are normalized.
+	clusterDefaultLeaseDurationAnnotation = "operator.open-cluster-management.io/cluster-default-lease-duration-seconds"
+	clusterDefaultClusterSetAnnotation    = "operator.open-cluster-management.io/cluster-default-clusterset"
+	normalizeClusterClientURLsAnnotation  = "operator.open-cluster-management.io/normalize-cluster-client-urls"
+	// clusterApprovalExpirationAnnotation on the ClusterManager is the duration, e.g. 72h, a ManagedCluster waits
+	// for the hub to accept it, the cluster and its CSRs are deleted if it is not accepted in time.
+	clusterApprovalExpirationAnnotation = "operator.open-cluster-management.io/cluster-approval-expiration"
+	// csrApprovalSignersAnnotation on the ClusterManager is the comma-separated signers of the managed cluster CSRs
+	// approved by the hub besides kubernetes.io/kube-apiserver-client, the certificates are issued by the signing
+	// controllers of the signers, e.g. the one of a corporate CA.
+	csrApprovalSignersAnnotation = "operator.open-cluster-management.io/csr-approval-signers"
+	// importBootstrapSecretAnnotation on the ClusterManager is the namespace/name of the secret on the hub holding the
+	// bootstrap kubeconfig in the klusterlet manifests of the ManagedClusters imported by the hub.
+	importBootstrapSecretAnnotation = "operator.open-cluster-management.io/import-bootstrap-kubeconfig-secret"
+	// bootstrapTokenServiceAccountAnnotation on the ClusterManager is the namespace/name of the service account on the
+	// hub whose tokens authenticate the bootstrap kubeconfigs rotated on the managed clusters, and
+	// bootstrapHubAPIServerAnnotation is the URL of the hub apiserver in the bootstrap kubeconfigs. The bootstrap
+	// kubeconfigs are rotated only if both are set.
+	bootstrapTokenServiceAccountAnnotation = "operator.open-cluster-management.io/bootstrap-token-service-account"
+	bootstrapHubAPIServerAnnotation        = "operator.open-cluster-management.io/bootstrap-hub-apiserver"
+	// clusterClaimLabelRulesAnnotation on the ClusterManager is the name of a ConfigMap in the namespace of the
+	// cluster manager on the hub, containing the rules projecting the cluster claims into the labels of the
+	// ManagedClusters.
+	clusterClaimLabelRulesAnnotation = "operator.open-cluster-management.io/cluster-claim-label-rules-configmap"
+	// placementShardCountAnnotation on the ClusterManager is the number of shards the placements are split into,
+	// the placement controller is scaled to at least the number of the shards so that each shard is scheduled.
+	placementShardCountAnnotation = "operator.open-cluster-management.io/placement-shard-count"
+	// clusterMigrationAnnotation on the ClusterManager set to "true" enables the migration of the ManagedClusters
+	// annotated with a target hub to the target hub.
+	clusterMigrationAnnotation = "operator.open-cluster-management.io/enable-cluster-migration"
+	// pendingApprovalAnnotation on the ClusterManager set to "true" enables the PendingApproval condition of the
+	// ManagedClusters waiting for the hub to accept them.
+	pendingApprovalAnnotation = "operator.open-cluster-management.io/enable-pending-approval"
+	// workAuthorRolesAnnotation on the ClusterManager set to "true" enables the provisioning of the roles authoring
+	// the ManifestWorks for the groups in the work-author-groups annotation of the ManagedClusterSetBindings.
+	workAuthorRolesAnnotation = "operator.open-cluster-management.io/enable-work-author-roles"
+
 	defaultWebhookPort       = int32(9443)
 	clusterManagerReSyncTime = 5 * time.Second
 )
@@ -100,12 +174,15 @@ func NewClusterManagerController(
 		skipRemoveCRDs:            skipRemoveCRDs,
 	}
 
-	return factory.New().WithSync(controller.sync).
+	return factory.New().WithSync(logging.WithControllerLogger("ClusterManagerController", controller.sync)).
 		ResyncEvery(3*time.Minute).
 		WithInformersQueueKeysFunc(helpers.ClusterManagerDeploymentQueueKeyFunc(controller.clusterManagerLister), deploymentInformer.Informer()).
 		WithFilteredEventsInformersQueueKeysFunc(
 			helpers.ClusterManagerQueueKeyFunc(controller.clusterManagerLister),
-			queue.FilterByNames(helpers.CaBundleConfigmap),
+			func(obj interface{}) bool {
+				return queue.FilterByNames(helpers.CaBundleConfigmap)(obj) ||
+					isTrustedCABundle(controller.clusterManagerLister, obj)
+			},
 			configMapInformer.Informer()).
 		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterManagerInformer.Informer()).
 		ToController("ClusterManagerController", recorder)
@@ -113,7 +190,8 @@ func NewClusterManagerController(
 
 func (n *clusterManagerController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
 	clusterManagerName := controllerContext.QueueKey()
-	klog.V(4).Infof("Reconciling ClusterManager %q", clusterManagerName)
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling ClusterManager", "clusterManager", clusterManagerName)
 
 	originalClusterManager, err := n.clusterManagerLister.Get(clusterManagerName)
 	if errors.IsNotFound(err) {
@@ -173,6 +251,67 @@ func (n *clusterManagerController) sync(ctx context.Context, controllerContext f
 	// Check if addon management is enabled by the feature gate
 	config.AddOnManagerEnabled = helpers.FeatureGateEnabled(addonFeatureGates, ocmfeature.DefaultHubAddonManagerFeatureGates, ocmfeature.AddonManagement)
 
+	// The invalid TLS options are ignored, so the webhook servers are still able to start with the default ones.
+	config.WebhookTLSMinVersion, config.WebhookTLSCipherSuites, err = convertWebhookTLSAnnotations(clusterManager.Annotations)
+	if err != nil {
+		n.recorder.Warningf("InvalidWebhookTLSOptions", "The webhook TLS options of %s are ignored: %v", clusterManagerName, err)
+	}
+
+	config.StatusAggregationEnabled = clusterManager.Annotations[statusAggregationAnnotation] == "true"
+	config.ClusterProfileNamespace = clusterManager.Annotations[clusterProfileNamespaceAnnotation]
+	config.WorkAuthorRolesEnabled = clusterManager.Annotations[workAuthorRolesAnnotation] == "true"
+	config.PendingApprovalEnabled = clusterManager.Annotations[pendingApprovalAnnotation] == "true"
+	config.ClusterMigrationEnabled = clusterManager.Annotations[clusterMigrationAnnotation] == "true"
+	if expiration := clusterManager.Annotations[clusterApprovalExpirationAnnotation]; len(expiration) > 0 {
+		if duration, err := time.ParseDuration(expiration); err != nil || duration < 0 {
+			n.recorder.Warningf("InvalidClusterApprovalExpiration",
+				"The cluster approval expiration %q of %s is ignored", expiration, clusterManagerName)
+		} else {
+			config.ClusterApprovalExpiration = duration.String()
+		}
+	}
+	config.CSRApprovalSigners, err = convertCSRApprovalSigners(clusterManager.Annotations[csrApprovalSignersAnnotation])
+	if err != nil {
+		n.recorder.Warningf("InvalidCSRApprovalSigners", "The CSR approval signers of %s are ignored: %v", clusterManagerName, err)
+	}
+	config.ClusterSetBindingRulesConfigMap = clusterManager.Annotations[clusterSetBindingRulesAnnotation]
+	config.ImportBootstrapKubeconfigSecret = clusterManager.Annotations[importBootstrapSecretAnnotation]
+	config.ClusterClaimLabelRulesConfigMap = clusterManager.Annotations[clusterClaimLabelRulesAnnotation]
+	if len(clusterManager.Annotations[bootstrapHubAPIServerAnnotation]) > 0 {
+		config.BootstrapTokenServiceAccount = clusterManager.Annotations[bootstrapTokenServiceAccountAnnotation]
+		config.BootstrapHubAPIServer = clusterManager.Annotations[bootstrapHubAPIServerAnnotation]
+	}
+
+	// The invalid naming policy is ignored, so the registration webhook server is still able to start.
+	config.ClusterNamePattern, config.ClusterNameMaxLength, config.ClusterNameReservedPrefixes, err =
+		convertClusterNamingAnnotations(clusterManager.Annotations)
+	if err != nil {
+		n.recorder.Warningf("InvalidClusterNamingPolicy", "The cluster naming policy of %s is ignored: %v", clusterManagerName, err)
+	}
+	config.ClusterLeaseDurationSeconds, config.ClusterDefaultClusterSet, err =
+		convertClusterDefaultingAnnotations(clusterManager.Annotations)
+	if err != nil {
+		n.recorder.Warningf("InvalidClusterDefaults", "The cluster defaults of %s are ignored: %v", clusterManagerName, err)
+	}
+	config.NormalizeClusterClientURLs = clusterManager.Annotations[normalizeClusterClientURLsAnnotation] == "true"
+	if value := clusterManager.Annotations[placementShardCountAnnotation]; len(value) > 0 {
+		if count, err := strconv.Atoi(value); err != nil || count < 1 {
+			n.recorder.Warningf("InvalidPlacementShardCount",
+				"The placement shard count %q of %s is ignored", value, clusterManagerName)
+		} else {
+			config.PlacementShardCount = count
+		}
+	}
+
+	// The trusted CA bundle is not mounted until the ConfigMap exists, otherwise the hub controllers are not able to
+	// start. The hash of the bundle rolls out the hub controllers once the bundle is changed.
+	if name := clusterManager.Annotations[trustedCABundleAnnotation]; len(name) > 0 {
+		config.TrustedCABundle, config.TrustedCABundleHash, err = n.trustedCABundle(clusterManagerNamespace, name)
+		if err != nil {
+			n.recorder.Warningf("TrustedCABundleUnavailable", "The trusted CA bundle of %s is ignored: %v", clusterManagerName, err)
+		}
+	}
+
 	// If we are deploying in the hosted mode, it requires us to create webhook in a different way with the default mode.
 	// In the hosted mode, the webhook servers is running in the management cluster but the users are accessing the hub cluster.
 	// So we need to add configuration to make the apiserver of the hub cluster could access the webhook servers on the management cluster.
@@ -335,6 +474,89 @@ func convertWebhookConfiguration(webhookConfiguration operatorapiv1.WebhookConfi
 	}
 }
 
+// convertWebhookTLSAnnotations returns the minimum TLS version and the cipher suites of the webhook servers set by
+// the annotations, an error is returned if any of them is not valid.
+func convertWebhookTLSAnnotations(annotations map[string]string) (string, string, error) {
+	minVersion := annotations[webhookTLSMinVersionAnnotation]
+	if _, err := cliflag.TLSVersion(minVersion); err != nil {
+		return "", "", err
+	}
+
+	var cipherSuites []string
+	for _, cipherSuite := range strings.Split(annotations[webhookTLSCipherSuitesAnnotation], ",") {
+		if cipherSuite = strings.TrimSpace(cipherSuite); len(cipherSuite) > 0 {
+			cipherSuites = append(cipherSuites, cipherSuite)
+		}
+	}
+	if _, err := cliflag.TLSCipherSuites(cipherSuites); err != nil {
+		return "", "", err
+	}
+
+	return minVersion, strings.Join(cipherSuites, ","), nil
+}
+
+// convertClusterNamingAnnotations returns the pattern, the maximum length and the reserved prefixes of the cluster
+// names set by the annotations, an error is returned if any of them is not valid. The single quotes in the pattern
+// are escaped, since the pattern is rendered in a single-quoted arg of the registration webhook.
+func convertClusterNamingAnnotations(annotations map[string]string) (string, int, string, error) {
+	pattern := annotations[clusterNamePatternAnnotation]
+	if _, err := regexp.Compile(pattern); err != nil {
+		return "", 0, "", fmt.Errorf("invalid cluster name pattern %q: %w", pattern, err)
+	}
+
+	maxLength := 0
+	if value := annotations[clusterNameMaxLengthAnnotation]; len(value) > 0 {
+		var err error
+		if maxLength, err = strconv.Atoi(value); err != nil || maxLength < 0 {
+			return "", 0, "", fmt.Errorf("invalid cluster name max length %q", value)
+		}
+	}
+
+	var prefixes []string
+	for _, prefix := range strings.Split(annotations[clusterNameReservedPrefixesAnnotation], ",") {
+		if prefix = strings.TrimSpace(prefix); len(prefix) > 0 {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+
+	return strings.ReplaceAll(pattern, "'", "''"), maxLength, strings.Join(prefixes, ","), nil
+}
+
+// convertClusterDefaultingAnnotations returns the default lease duration and ManagedClusterSet of the ManagedClusters,
+// an error is returned if any of them is invalid.
+func convertClusterDefaultingAnnotations(annotations map[string]string) (int, string, error) {
+	leaseDurationSeconds := 0
+	if value := annotations[clusterDefaultLeaseDurationAnnotation]; len(value) > 0 {
+		seconds, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || seconds < 0 {
+			return 0, "", fmt.Errorf("invalid cluster default lease duration %q", value)
+		}
+		leaseDurationSeconds = int(seconds)
+	}
+
+	clusterSet := annotations[clusterDefaultClusterSetAnnotation]
+	if errs := validation.IsValidLabelValue(clusterSet); len(errs) > 0 {
+		return 0, "", fmt.Errorf("invalid cluster default clusterset %q: %s", clusterSet, strings.Join(errs, ", "))
+	}
+	return leaseDurationSeconds, clusterSet, nil
+}
+
+// convertCSRApprovalSigners returns the signers in the comma-separated value, an error is returned if any of them is
+// not a valid signer name.
+func convertCSRApprovalSigners(value string) ([]string, error) {
+	var signers []string
+	for _, signer := range strings.Split(value, ",") {
+		if signer = strings.TrimSpace(signer); len(signer) == 0 {
+			continue
+		}
+		if err := helpers.ValidateSignerName(signer); err != nil {
+			return nil, err
+		}
+		signers = append(signers, signer)
+	}
+	return signers, nil
+}
+
 // clean specified resources
 func cleanResources(ctx context.Context, kubeClient kubernetes.Interface, cm *operatorapiv1.ClusterManager,
 	config manifests.HubConfig, resources ...string) (*operatorapiv1.ClusterManager, reconcileState, error) {
@@ -359,3 +581,36 @@ func cleanResources(ctx context.Context, kubeClient kubernetes.Interface, cm *op
 	}
 	return cm, reconcileContinue, nil
 }
+
+// trustedCABundle returns the name and the hash of the ConfigMap of the trusted CA bundle.
+func (n *clusterManagerController) trustedCABundle(namespace, name string) (string, string, error) {
+	configMap, err := n.configMapLister.ConfigMaps(namespace).Get(name)
+	if err != nil {
+		return "", "", err
+	}
+
+	// the keys of a map are sorted when it is marshaled, so the hash is stable
+	data, err := json.Marshal(configMap.Data)
+	if err != nil {
+		return "", "", err
+	}
+	return name, fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// isTrustedCABundle returns true if the ConfigMap is the trusted CA bundle of any ClusterManager.
+func isTrustedCABundle(clusterManagerLister operatorlister.ClusterManagerLister, obj interface{}) bool {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	clusterManagers, err := clusterManagerLister.List(labels.Everything())
+	if err != nil {
+		return false
+	}
+	for _, clusterManager := range clusterManagers {
+		if name := clusterManager.Annotations[trustedCABundleAnnotation]; len(name) > 0 && name == accessor.GetName() {
+			return true
+		}
+	}
+	return false
+}
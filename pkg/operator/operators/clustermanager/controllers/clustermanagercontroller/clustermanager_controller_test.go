@@ -2,6 +2,8 @@ package clustermanagercontroller
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -11,6 +13,7 @@ import (
 	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	fakeapiextensions "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
@@ -18,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	fakekube "k8s.io/client-go/kubernetes/fake"
@@ -292,6 +296,105 @@ func ensureObject(t *testing.T, object runtime.Object, hubCore *operatorapiv1.Cl
 		if strings.Contains(o.Name, "addon-manager") && hubCore.Spec.AddOnManagerImagePullSpec != o.Spec.Template.Spec.Containers[0].Image {
 			t.Errorf("AddOnManager image does not match to the expected.")
 		}
+		if strings.HasSuffix(o.Name, "registration-controller") {
+			enabled := hubCore.Annotations[statusAggregationAnnotation] == "true"
+			if hasArg := sets.New(o.Spec.Template.Spec.Containers[0].Args...).Has("--enable-status-aggregation"); hasArg != enabled {
+				t.Errorf("Expected status aggregation enabled %v, but got args %v", enabled, o.Spec.Template.Spec.Containers[0].Args)
+			}
+			migrationEnabled := hubCore.Annotations[clusterMigrationAnnotation] == "true"
+			if hasArg := sets.New(o.Spec.Template.Spec.Containers[0].Args...).Has("--enable-cluster-migration"); hasArg != migrationEnabled {
+				t.Errorf("Expected migration enabled %v, but got args %v", migrationEnabled, o.Spec.Template.Spec.Containers[0].Args)
+			}
+			secretNamespaceArg := fmt.Sprintf("--cluster-migration-secret-namespace=%s", o.Namespace)
+			if hasArg := sets.New(o.Spec.Template.Spec.Containers[0].Args...).Has(secretNamespaceArg); hasArg != migrationEnabled {
+				t.Errorf("Expected migration secret namespace %s, but got args %v", o.Namespace, o.Spec.Template.Spec.Containers[0].Args)
+			}
+			pendingEnabled := hubCore.Annotations[pendingApprovalAnnotation] == "true"
+			if hasArg := sets.New(o.Spec.Template.Spec.Containers[0].Args...).Has("--enable-pending-approval"); hasArg != pendingEnabled {
+				t.Errorf("Expected pending approval enabled %v, but got args %v", pendingEnabled, o.Spec.Template.Spec.Containers[0].Args)
+			}
+			workEnabled := hubCore.Annotations[workAuthorRolesAnnotation] == "true"
+			if hasArg := sets.New(o.Spec.Template.Spec.Containers[0].Args...).Has("--enable-work-author-roles"); hasArg != workEnabled {
+				t.Errorf("Expected work author enabled %v, but got args %v", workEnabled, o.Spec.Template.Spec.Containers[0].Args)
+			}
+			profileNamespace := hubCore.Annotations[clusterProfileNamespaceAnnotation]
+			profileArg := fmt.Sprintf("--cluster-profile-namespace=%s", profileNamespace)
+			if hasArg := sets.New(o.Spec.Template.Spec.Containers[0].Args...).Has(profileArg); hasArg != (len(profileNamespace) > 0) {
+				t.Errorf("Expected cluster profile namespace %q, but got args %v", profileNamespace, o.Spec.Template.Spec.Containers[0].Args)
+			}
+			expirationArg := "--cluster-approval-expiration=72h0m0s"
+			if hasArg := sets.New(o.Spec.Template.Spec.Containers[0].Args...).Has(expirationArg); hasArg !=
+				(len(hubCore.Annotations[clusterApprovalExpirationAnnotation]) > 0) {
+				t.Errorf("Expected cluster approval expiration %q, but got args %v",
+					hubCore.Annotations[clusterApprovalExpirationAnnotation], o.Spec.Template.Spec.Containers[0].Args)
+			}
+			signerArg := "--csr-approval-signers=example.com/corporate-ca"
+			if hasArg := sets.New(o.Spec.Template.Spec.Containers[0].Args...).Has(signerArg); hasArg !=
+				(len(hubCore.Annotations[csrApprovalSignersAnnotation]) > 0) {
+				t.Errorf("Expected CSR approval signers %q, but got args %v",
+					hubCore.Annotations[csrApprovalSignersAnnotation], o.Spec.Template.Spec.Containers[0].Args)
+			}
+			importSecret := hubCore.Annotations[importBootstrapSecretAnnotation]
+			importArg := fmt.Sprintf("--import-bootstrap-kubeconfig-secret=%s", importSecret)
+			if hasArg := sets.New(o.Spec.Template.Spec.Containers[0].Args...).Has(importArg); hasArg != (len(importSecret) > 0) {
+				t.Errorf("Expected import bootstrap secret %q, but got args %v", importSecret, o.Spec.Template.Spec.Containers[0].Args)
+			}
+			tokenServiceAccount := hubCore.Annotations[bootstrapTokenServiceAccountAnnotation]
+			tokenArg := fmt.Sprintf("--bootstrap-token-service-account=%s", tokenServiceAccount)
+			if hasArg := sets.New(o.Spec.Template.Spec.Containers[0].Args...).Has(tokenArg); hasArg != (len(tokenServiceAccount) > 0) {
+				t.Errorf("Expected bootstrap token service account %q, but got args %v",
+					tokenServiceAccount, o.Spec.Template.Spec.Containers[0].Args)
+			}
+			claimRules := hubCore.Annotations[clusterClaimLabelRulesAnnotation]
+			claimRulesArg := fmt.Sprintf("--cluster-claim-label-rules-configmap=%s/%s", o.Namespace, claimRules)
+			if hasArg := sets.New(o.Spec.Template.Spec.Containers[0].Args...).Has(claimRulesArg); hasArg != (len(claimRules) > 0) {
+				t.Errorf("Expected cluster claim label rules configmap %q, but got args %v",
+					claimRules, o.Spec.Template.Spec.Containers[0].Args)
+			}
+		}
+		if strings.HasSuffix(o.Name, "placement-controller") {
+			shardCount := hubCore.Annotations[placementShardCountAnnotation]
+			shardArg := fmt.Sprintf("--shard-count=%s", shardCount)
+			if hasArg := sets.New(o.Spec.Template.Spec.Containers[0].Args...).Has(shardArg); hasArg != (len(shardCount) > 0) {
+				t.Errorf("Expected placement shard count %q, but got args %v", shardCount, o.Spec.Template.Spec.Containers[0].Args)
+			}
+			if len(shardCount) > 0 && fmt.Sprintf("%d", *o.Spec.Replicas) != shardCount {
+				t.Errorf("Expected %s placement controller replicas, but got %d", shardCount, *o.Spec.Replicas)
+			}
+		}
+		if strings.HasSuffix(o.Name, "registration-webhook") {
+			rulesConfigMap := hubCore.Annotations[clusterSetBindingRulesAnnotation]
+			rulesArg := fmt.Sprintf("--clustersetbinding-rules-configmap=%s/%s", o.Namespace, rulesConfigMap)
+			if hasArg := sets.New(o.Spec.Template.Spec.Containers[0].Args...).Has(rulesArg); hasArg != (len(rulesConfigMap) > 0) {
+				t.Errorf("Expected clustersetbinding rules configmap %q, but got args %v", rulesConfigMap, o.Spec.Template.Spec.Containers[0].Args)
+			}
+			namePattern := hubCore.Annotations[clusterNamePatternAnnotation]
+			patternArg := fmt.Sprintf("--cluster-name-pattern=%s", namePattern)
+			if hasArg := sets.New(o.Spec.Template.Spec.Containers[0].Args...).Has(patternArg); hasArg != (len(namePattern) > 0) {
+				t.Errorf("Expected cluster name pattern %q, but got args %v", namePattern, o.Spec.Template.Spec.Containers[0].Args)
+			}
+			maxLength := hubCore.Annotations[clusterNameMaxLengthAnnotation]
+			maxLengthArg := fmt.Sprintf("--cluster-name-max-length=%s", maxLength)
+			if hasArg := sets.New(o.Spec.Template.Spec.Containers[0].Args...).Has(maxLengthArg); hasArg != (len(maxLength) > 0) {
+				t.Errorf("Expected cluster name max length %q, but got args %v", maxLength, o.Spec.Template.Spec.Containers[0].Args)
+			}
+			leaseDuration := hubCore.Annotations[clusterDefaultLeaseDurationAnnotation]
+			leaseDurationArg := fmt.Sprintf("--cluster-default-lease-duration-seconds=%s", leaseDuration)
+			if hasArg := sets.New(o.Spec.Template.Spec.Containers[0].Args...).Has(leaseDurationArg); hasArg != (len(leaseDuration) > 0) {
+				t.Errorf("Expected cluster default lease duration %q, but got args %v", leaseDuration, o.Spec.Template.Spec.Containers[0].Args)
+			}
+			clusterSet := hubCore.Annotations[clusterDefaultClusterSetAnnotation]
+			clusterSetArg := fmt.Sprintf("--cluster-default-clusterset=%s", clusterSet)
+			if hasArg := sets.New(o.Spec.Template.Spec.Containers[0].Args...).Has(clusterSetArg); hasArg != (len(clusterSet) > 0) {
+				t.Errorf("Expected cluster default clusterset %q, but got args %v", clusterSet, o.Spec.Template.Spec.Containers[0].Args)
+			}
+			normalizeArg := "--normalize-cluster-client-urls"
+			if hasArg := sets.New(o.Spec.Template.Spec.Containers[0].Args...).Has(normalizeArg); hasArg !=
+				(hubCore.Annotations[normalizeClusterClientURLsAnnotation] == "true") {
+				t.Errorf("Expected normalizing cluster client urls %q, but got args %v",
+					hubCore.Annotations[normalizeClusterClientURLsAnnotation], o.Spec.Template.Spec.Containers[0].Args)
+			}
+		}
 	}
 }
 
@@ -321,7 +424,7 @@ func TestSyncDeploy(t *testing.T) {
 
 	// Check if resources are created as expected
 	// We expect create the namespace twice respectively in the management cluster and the hub cluster.
-	testingcommon.AssertEqualNumber(t, len(createKubeObjects), 29)
+	testingcommon.AssertEqualNumber(t, len(createKubeObjects), 31)
 	for _, object := range createKubeObjects {
 		ensureObject(t, object, clusterManager)
 	}
@@ -338,6 +441,244 @@ func TestSyncDeploy(t *testing.T) {
 	testingcommon.AssertEqualNumber(t, len(createCRDObjects), 12)
 }
 
+func TestSyncDeployRegistrationControllerOptions(t *testing.T) {
+	clusterManager := newClusterManager("testhub")
+	clusterManager.Annotations = map[string]string{
+		statusAggregationAnnotation:            "true",
+		clusterProfileNamespaceAnnotation:      "open-cluster-management",
+		clusterSetBindingRulesAnnotation:       "binding-rules",
+		clusterNamePatternAnnotation:           "prod-'[a-z]+'",
+		clusterNameMaxLengthAnnotation:         "20",
+		clusterApprovalExpirationAnnotation:    "72h",
+		csrApprovalSignersAnnotation:           "example.com/corporate-ca",
+		importBootstrapSecretAnnotation:        "open-cluster-management/bootstrap-hub-kubeconfig",
+		bootstrapTokenServiceAccountAnnotation: "open-cluster-management/cluster-bootstrap",
+		bootstrapHubAPIServerAnnotation:        "https://hub.example.com:6443",
+		clusterClaimLabelRulesAnnotation:       "claim-label-rules",
+		clusterDefaultLeaseDurationAnnotation:  "120",
+		clusterDefaultClusterSetAnnotation:     "prod",
+		normalizeClusterClientURLsAnnotation:   "true",
+		placementShardCountAnnotation:          "3",
+		workAuthorRolesAnnotation:              "true",
+		pendingApprovalAnnotation:              "true",
+		clusterMigrationAnnotation:             "true",
+	}
+	tc := newTestController(t, clusterManager)
+	clusterManagerNamespace := helpers.ClusterManagerNamespace(clusterManager.Name, clusterManager.Spec.DeployOption.Mode)
+	cd := setDeployment(clusterManager.Name, clusterManagerNamespace)
+	setup(t, tc, cd)
+
+	syncContext := testingcommon.NewFakeSyncContext(t, "testhub")
+	if err := tc.clusterManagerController.sync(ctx, syncContext); err != nil {
+		t.Fatalf("Expected no error when sync, %v", err)
+	}
+
+	registrationDeployments, webhookDeployments, placementDeployments := 0, 0, 0
+	for _, action := range tc.managementKubeClient.Actions() {
+		objectAction, ok := action.(interface{ GetObject() runtime.Object })
+		if !ok {
+			continue
+		}
+		object := objectAction.GetObject()
+		if deployment, ok := object.(*appsv1.Deployment); ok && strings.HasSuffix(deployment.Name, "registration-controller") {
+			registrationDeployments++
+		}
+		if deployment, ok := object.(*appsv1.Deployment); ok && strings.HasSuffix(deployment.Name, "registration-webhook") {
+			webhookDeployments++
+		}
+		if deployment, ok := object.(*appsv1.Deployment); ok && strings.HasSuffix(deployment.Name, "placement-controller") {
+			placementDeployments++
+		}
+		ensureObject(t, object, clusterManager)
+	}
+	testingcommon.AssertEqualNumber(t, registrationDeployments, 1)
+	testingcommon.AssertEqualNumber(t, webhookDeployments, 1)
+	testingcommon.AssertEqualNumber(t, placementDeployments, 1)
+
+	// the registration controller is allowed to approve the CSRs of the signer
+	approvable := false
+	for _, action := range tc.hubKubeClient.Actions() {
+		createAction, ok := action.(clienttesting.CreateActionImpl)
+		if !ok {
+			continue
+		}
+		clusterRole, ok := createAction.Object.(*rbacv1.ClusterRole)
+		if !ok || !strings.HasSuffix(clusterRole.Name, "registration:controller") {
+			continue
+		}
+		for _, rule := range clusterRole.Rules {
+			if sets.New(rule.Resources...).Has("signers") && sets.New(rule.ResourceNames...).Has("example.com/corporate-ca") {
+				approvable = true
+			}
+		}
+	}
+	if !approvable {
+		t.Errorf("Expected the registration controller is allowed to approve the CSRs of example.com/corporate-ca")
+	}
+
+	// the registration controller is only allowed to read the migration secrets in the cluster manager namespace
+	migrationRoles := 0
+	for _, action := range tc.hubKubeClient.Actions() {
+		createAction, ok := action.(clienttesting.CreateActionImpl)
+		if !ok {
+			continue
+		}
+		switch o := createAction.Object.(type) {
+		case *rbacv1.ClusterRole:
+			for _, rule := range o.Rules {
+				if sets.New(rule.Resources...).Has("secrets") && sets.New(rule.Verbs...).Has("get") {
+					t.Errorf("Expected no cluster wide access to the secrets in the clusterrole %s", o.Name)
+				}
+			}
+		case *rbacv1.Role:
+			if o.Namespace == clusterManagerNamespace && strings.HasSuffix(o.Name, "registration:migration") {
+				migrationRoles++
+			}
+		}
+	}
+	testingcommon.AssertEqualNumber(t, migrationRoles, 1)
+}
+
+func TestConvertCSRApprovalSigners(t *testing.T) {
+	cases := []struct {
+		name            string
+		value           string
+		expectedSigners []string
+		expectedErr     bool
+	}{
+		{
+			name: "no signers",
+		},
+		{
+			name:            "valid signers",
+			value:           "example.com/corporate-ca, example.com/team-ca,",
+			expectedSigners: []string{"example.com/corporate-ca", "example.com/team-ca"},
+		},
+		{
+			name:        "signer without path",
+			value:       "example.com",
+			expectedErr: true,
+		},
+		{
+			name:        "signer with invalid domain",
+			value:       "Example_com/ca",
+			expectedErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			signers, err := convertCSRApprovalSigners(c.value)
+			if (err != nil) != c.expectedErr {
+				t.Errorf("expected error %v, but got %v", c.expectedErr, err)
+			}
+			if !reflect.DeepEqual(signers, c.expectedSigners) {
+				t.Errorf("expected signers %v, but got %v", c.expectedSigners, signers)
+			}
+		})
+	}
+}
+
+func TestSyncDeployTrustedCABundle(t *testing.T) {
+	cases := []struct {
+		name               string
+		configMap          *corev1.ConfigMap
+		expectedMounted    bool
+		expectedAnnotation string
+	}{
+		{
+			name:      "bundle not found",
+			configMap: nil,
+		},
+		{
+			name: "bundle found",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "trusted-ca", Namespace: helpers.ClusterManagerDefaultNamespace},
+				Data:       map[string]string{"ca.crt": "cert"},
+			},
+			expectedMounted: true,
+			// sha256 of {"ca.crt":"cert"}
+			expectedAnnotation: "51a26e1e6fff020c0311b205d43d8229deec986918de9b16a9a653cf59b30411",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusterManager := newClusterManager("testhub")
+			clusterManager.Annotations = map[string]string{trustedCABundleAnnotation: "trusted-ca"}
+			tc := newTestController(t, clusterManager)
+			kubeInformers := kubeinformers.NewSharedInformerFactory(fakekube.NewSimpleClientset(), 5*time.Minute)
+			if c.configMap != nil {
+				if err := kubeInformers.Core().V1().ConfigMaps().Informer().GetStore().Add(c.configMap); err != nil {
+					t.Fatal(err)
+				}
+			}
+			tc.clusterManagerController.configMapLister = kubeInformers.Core().V1().ConfigMaps().Lister()
+			clusterManagerNamespace := helpers.ClusterManagerNamespace(clusterManager.Name, clusterManager.Spec.DeployOption.Mode)
+			cd := setDeployment(clusterManager.Name, clusterManagerNamespace)
+			setup(t, tc, cd)
+
+			syncContext := testingcommon.NewFakeSyncContext(t, "testhub")
+			if err := tc.clusterManagerController.sync(ctx, syncContext); err != nil {
+				t.Fatalf("Expected no error when sync, %v", err)
+			}
+
+			controllers := 0
+			for _, action := range tc.managementKubeClient.Actions() {
+				objectAction, ok := action.(interface{ GetObject() runtime.Object })
+				if !ok {
+					continue
+				}
+				deployment, ok := objectAction.GetObject().(*appsv1.Deployment)
+				if !ok || !strings.HasSuffix(deployment.Name, "controller") {
+					continue
+				}
+				controllers++
+
+				mounted := false
+				for _, volume := range deployment.Spec.Template.Spec.Volumes {
+					if volume.ConfigMap != nil && volume.ConfigMap.Name == "trusted-ca" {
+						mounted = true
+					}
+				}
+				if mounted != c.expectedMounted {
+					t.Errorf("Expected the trusted CA bundle mounted %v in %s, but got %v", c.expectedMounted, deployment.Name, mounted)
+				}
+				hasEnv := false
+				for _, env := range deployment.Spec.Template.Spec.Containers[0].Env {
+					if env.Name == "SSL_CERT_DIR" && strings.HasSuffix(env.Value, ":/etc/ocm/trusted-ca-bundle") {
+						hasEnv = true
+					}
+				}
+				if hasEnv != c.expectedMounted {
+					t.Errorf("Expected the SSL_CERT_DIR env set %v in %s, but got %v", c.expectedMounted, deployment.Name, hasEnv)
+				}
+				annotation := deployment.Spec.Template.Annotations["operator.open-cluster-management.io/trusted-ca-bundle-hash"]
+				if annotation != c.expectedAnnotation {
+					t.Errorf("Expected the trusted CA bundle hash %q in %s, but got %q", c.expectedAnnotation, deployment.Name, annotation)
+				}
+			}
+			if controllers == 0 {
+				t.Errorf("Expected the hub controllers are deployed")
+			}
+		})
+	}
+}
+
+func TestIsTrustedCABundle(t *testing.T) {
+	clusterManager := newClusterManager("testhub")
+	clusterManager.Annotations = map[string]string{trustedCABundleAnnotation: "trusted-ca"}
+	tc := newTestController(t, clusterManager)
+
+	if !isTrustedCABundle(tc.clusterManagerController.clusterManagerLister,
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "trusted-ca"}}) {
+		t.Errorf("Expected the configmap is the trusted CA bundle")
+	}
+	if isTrustedCABundle(tc.clusterManagerController.clusterManagerLister,
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other"}}) {
+		t.Errorf("Expected the configmap is not the trusted CA bundle")
+	}
+}
+
 func TestSyncDeployNoWebhook(t *testing.T) {
 	clusterManager := newClusterManager("testhub")
 	tc := newTestController(t, clusterManager)
@@ -403,7 +744,7 @@ func TestSyncDelete(t *testing.T) {
 			deleteKubeActions = append(deleteKubeActions, deleteKubeAction)
 		}
 	}
-	testingcommon.AssertEqualNumber(t, len(deleteKubeActions), 29) // delete namespace both from the hub cluster and the mangement cluster
+	testingcommon.AssertEqualNumber(t, len(deleteKubeActions), 31) // delete namespace both from the hub cluster and the mangement cluster
 
 	var deleteCRDActions []clienttesting.DeleteActionImpl
 	crdActions := tc.apiExtensionClient.Actions()
@@ -481,3 +822,151 @@ func TestIsIPFormat(t *testing.T) {
 		}
 	}
 }
+
+func TestConvertWebhookTLSAnnotations(t *testing.T) {
+	cases := []struct {
+		name                 string
+		annotations          map[string]string
+		expectedMinVersion   string
+		expectedCipherSuites string
+		expectedErr          bool
+	}{
+		{
+			name: "no annotations",
+		},
+		{
+			name: "valid options",
+			annotations: map[string]string{
+				webhookTLSMinVersionAnnotation: "VersionTLS13",
+				webhookTLSCipherSuitesAnnotation: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, " +
+					"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+			},
+			expectedMinVersion:   "VersionTLS13",
+			expectedCipherSuites: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+		},
+		{
+			name:        "invalid min version",
+			annotations: map[string]string{webhookTLSMinVersionAnnotation: "TLS13"},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid cipher suite",
+			annotations: map[string]string{webhookTLSCipherSuitesAnnotation: "TLS_FOO"},
+			expectedErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			minVersion, cipherSuites, err := convertWebhookTLSAnnotations(c.annotations)
+			if c.expectedErr != (err != nil) {
+				t.Errorf("expected error %v, but got %v", c.expectedErr, err)
+			}
+			if minVersion != c.expectedMinVersion {
+				t.Errorf("expected min version %q, but got %q", c.expectedMinVersion, minVersion)
+			}
+			if cipherSuites != c.expectedCipherSuites {
+				t.Errorf("expected cipher suites %q, but got %q", c.expectedCipherSuites, cipherSuites)
+			}
+		})
+	}
+}
+
+func TestConvertClusterNamingAnnotations(t *testing.T) {
+	cases := []struct {
+		name              string
+		annotations       map[string]string
+		expectedPattern   string
+		expectedMaxLength int
+		expectedPrefixes  string
+		expectedErr       bool
+	}{
+		{
+			name: "no annotations",
+		},
+		{
+			name: "valid annotations",
+			annotations: map[string]string{
+				clusterNamePatternAnnotation:          "prod-'[a-z]+'",
+				clusterNameMaxLengthAnnotation:        "20",
+				clusterNameReservedPrefixesAnnotation: "local-, hub-,",
+			},
+			expectedPattern:   "prod-''[a-z]+''",
+			expectedMaxLength: 20,
+			expectedPrefixes:  "local-,hub-",
+		},
+		{
+			name:        "invalid pattern",
+			annotations: map[string]string{clusterNamePatternAnnotation: "prod-["},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid max length",
+			annotations: map[string]string{clusterNameMaxLengthAnnotation: "-1"},
+			expectedErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			pattern, maxLength, prefixes, err := convertClusterNamingAnnotations(c.annotations)
+			if c.expectedErr != (err != nil) {
+				t.Errorf("expected error %v, but got %v", c.expectedErr, err)
+			}
+			if pattern != c.expectedPattern {
+				t.Errorf("expected pattern %q, but got %q", c.expectedPattern, pattern)
+			}
+			if maxLength != c.expectedMaxLength {
+				t.Errorf("expected max length %d, but got %d", c.expectedMaxLength, maxLength)
+			}
+			if prefixes != c.expectedPrefixes {
+				t.Errorf("expected reserved prefixes %q, but got %q", c.expectedPrefixes, prefixes)
+			}
+		})
+	}
+}
+
+func TestConvertClusterDefaultingAnnotations(t *testing.T) {
+	cases := []struct {
+		name                  string
+		annotations           map[string]string
+		expectedLeaseDuration int
+		expectedClusterSet    string
+		expectedErr           bool
+	}{
+		{
+			name: "no annotations",
+		},
+		{
+			name: "valid annotations",
+			annotations: map[string]string{
+				clusterDefaultLeaseDurationAnnotation: "120",
+				clusterDefaultClusterSetAnnotation:    "prod",
+			},
+			expectedLeaseDuration: 120,
+			expectedClusterSet:    "prod",
+		},
+		{
+			name:        "invalid lease duration",
+			annotations: map[string]string{clusterDefaultLeaseDurationAnnotation: "-1"},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid clusterset",
+			annotations: map[string]string{clusterDefaultClusterSetAnnotation: "prod/east"},
+			expectedErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			leaseDuration, clusterSet, err := convertClusterDefaultingAnnotations(c.annotations)
+			if c.expectedErr != (err != nil) {
+				t.Errorf("expected error %v, but got %v", c.expectedErr, err)
+			}
+			if leaseDuration != c.expectedLeaseDuration {
+				t.Errorf("expected lease duration %d, but got %d", c.expectedLeaseDuration, leaseDuration)
+			}
+			if clusterSet != c.expectedClusterSet {
+				t.Errorf("expected clusterset %q, but got %q", c.expectedClusterSet, clusterSet)
+			}
+		})
+	}
+}
@@ -54,6 +54,12 @@ var (
 		"cluster-manager/hub/cluster-manager-manifestworkreplicaset-serviceaccount.yaml",
 	}
 
+	// the migration secrets are only readable when the cluster migration is enabled.
+	clusterMigrationRbacResourceFiles = []string{
+		"cluster-manager/hub/cluster-manager-registration-migration-role.yaml",
+		"cluster-manager/hub/cluster-manager-registration-migration-rolebinding.yaml",
+	}
+
 	hubAddOnManagerRbacResourceFiles = []string{
 		// addon-manager
 		"cluster-manager/hub/cluster-manager-addon-manager-clusterrole.yaml",
@@ -101,6 +107,14 @@ func (c *hubReoncile) reconcile(ctx context.Context, cm *operatorapiv1.ClusterMa
 		}
 	}
 
+	// Remove the access to the migration secrets if the cluster migration is not enabled
+	if !config.ClusterMigrationEnabled {
+		_, _, err := cleanResources(ctx, c.hubKubeClient, cm, config, clusterMigrationRbacResourceFiles...)
+		if err != nil {
+			return cm, reconcileStop, err
+		}
+	}
+
 	hubResources := getHubResources(cm.Spec.DeployOption.Mode, config)
 	var appliedErrs []error
 
@@ -156,6 +170,9 @@ func getHubResources(mode operatorapiv1.InstallMode, config manifests.HubConfig)
 	if config.MWReplicaSetEnabled {
 		hubResources = append(hubResources, mwReplicaSetResourceFiles...)
 	}
+	if config.ClusterMigrationEnabled {
+		hubResources = append(hubResources, clusterMigrationRbacResourceFiles...)
+	}
 	// the hubHostedWebhookServiceFiles are only used in hosted mode
 	if helpers.IsHosted(mode) {
 		hubResources = append(hubResources, hubHostedWebhookServiceFiles...)
@@ -30,6 +30,8 @@ var (
 		"cluster-manager/hub/cluster-manager-registration-webhook-mutatingconfiguration.yaml",
 		"cluster-manager/hub/cluster-manager-registration-webhook-clustersetbinding-validatingconfiguration.yaml",
 		"cluster-manager/hub/cluster-manager-registration-webhook-clustersetbinding-validatingconfiguration-v1beta1.yaml",
+		"cluster-manager/hub/cluster-manager-registration-webhook-clustersetbinding-mutatingconfiguration.yaml",
+		"cluster-manager/hub/cluster-manager-registration-webhook-addon-validatingconfiguration.yaml",
 	}
 	hubWorkWebhookResourceFiles = []string{
 		"cluster-manager/hub/cluster-manager-work-webhook-validatingconfiguration.yaml",
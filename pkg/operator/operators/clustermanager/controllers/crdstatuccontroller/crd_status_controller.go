@@ -21,6 +21,7 @@ import (
 	operatorinformer "open-cluster-management.io/api/client/operator/informers/externalversions/operator/v1"
 	operatorlister "open-cluster-management.io/api/client/operator/listers/operator/v1"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/operator/helpers"
 	"open-cluster-management.io/ocm/pkg/operator/operators/clustermanager/controllers/migrationcontroller"
@@ -55,14 +56,15 @@ func NewCRDStatusController(
 		generateHubClusterClients: generateHubClients,
 	}
 
-	return factory.New().WithSync(controller.sync).
+	return factory.New().WithSync(logging.WithControllerLogger("CRDStatusController", controller.sync)).
 		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterManagerInformer.Informer()).
 		ToController("CRDStatusController", recorder)
 }
 
 func (c *crdStatusController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
 	clusterManagerName := controllerContext.QueueKey()
-	klog.V(4).Infof("Reconciling ClusterManager %q", clusterManagerName)
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling ClusterManager", "clusterManager", clusterManagerName)
 
 	clusterManager, err := c.clusterManagerLister.Get(clusterManagerName)
 	if errors.IsNotFound(err) {
@@ -80,7 +82,7 @@ func (c *crdStatusController) sync(ctx context.Context, controllerContext factor
 	// need to wait storage version migrations succeed.
 	if succeeded := meta.IsStatusConditionTrue(clusterManager.Status.Conditions, migrationcontroller.MigrationSucceeded); !succeeded {
 		controllerContext.Queue().AddRateLimited(clusterManagerName)
-		klog.V(4).Info("Wait storage version migration succeed.")
+		logger.V(4).Info("Wait storage version migration succeed")
 		return nil
 	}
 
@@ -104,6 +106,7 @@ func (c *crdStatusController) sync(ctx context.Context, controllerContext factor
 
 // updateStoredVersion update(remove) deleted api version from CRD status.StoredVersions
 func updateStoredVersion(ctx context.Context, hubAPIExtensionClient apiextensionsclient.Interface) error {
+	logger := klog.FromContext(ctx)
 	for name, desiredVersions := range desiredCRDStoredVersions {
 		// retrieve CRD
 		crd, err := hubAPIExtensionClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, name, metav1.GetOptions{})
@@ -113,13 +116,14 @@ func updateStoredVersion(ctx context.Context, hubAPIExtensionClient apiextension
 		if !reflect.DeepEqual(crd.Status.StoredVersions, desiredVersions) {
 			crd.Status.StoredVersions = desiredVersions
 			// update the status sub-resource
-			klog.V(4).Infof("Need update stored versions. oldStoredVersions: %v, newStoredVersions: %v", crd.Status.StoredVersions, desiredVersions)
+			logger.V(4).Info("Need update stored versions",
+				"crd", name, "oldStoredVersions", crd.Status.StoredVersions, "newStoredVersions", desiredVersions)
 			crd, err = hubAPIExtensionClient.ApiextensionsV1().CustomResourceDefinitions().UpdateStatus(ctx, crd, metav1.UpdateOptions{})
 			if err != nil {
-				klog.Errorf("Failed to update storedversion:%v", err)
+				logger.Error(err, "Failed to update stored versions", "crd", name)
 				return err
 			}
-			klog.V(4).Infof("updated CRD %v status storedVersions: %v", crd.Name, crd.Status.StoredVersions)
+			logger.V(4).Info("Updated CRD status stored versions", "crd", crd.Name, "storedVersions", crd.Status.StoredVersions)
 		}
 	}
 
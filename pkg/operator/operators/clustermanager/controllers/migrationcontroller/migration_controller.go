@@ -31,6 +31,7 @@ import (
 	operatorapiv1 "open-cluster-management.io/api/operator/v1"
 
 	"open-cluster-management.io/ocm/manifests"
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/operator/helpers"
@@ -85,14 +86,15 @@ func NewCRDMigrationController(
 		generateHubClusterClients: generateHubClients,
 	}
 
-	return factory.New().WithSync(controller.sync).
+	return factory.New().WithSync(logging.WithControllerLogger("CRDMigrationController", controller.sync)).
 		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterManagerInformer.Informer()).
 		ToController("CRDMigrationController", recorder)
 }
 
 func (c *crdMigrationController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
 	clusterManagerName := controllerContext.QueueKey()
-	klog.V(4).Infof("Reconciling ClusterManager %q", clusterManagerName)
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling ClusterManager", "clusterManager", clusterManagerName)
 
 	if len(migrationRequestFiles) == 0 {
 		return nil
@@ -148,13 +150,13 @@ func (c *crdMigrationController) sync(ctx context.Context, controllerContext fac
 
 	err = applyStorageVersionMigrations(ctx, migrationClient, c.recorder)
 	if err != nil {
-		klog.Errorf("Failed to apply StorageVersionMigrations. %v", err)
+		logger.Error(err, "Failed to apply StorageVersionMigrations")
 		return err
 	}
 
 	migrationCond, err := syncStorageVersionMigrationsCondition(ctx, migrationClient)
 	if err != nil {
-		klog.Errorf("Failed to sync StorageVersionMigrations condition. %v", err)
+		logger.Error(err, "Failed to sync StorageVersionMigrations condition")
 		return err
 	}
 
@@ -167,7 +169,7 @@ func (c *crdMigrationController) sync(ctx context.Context, controllerContext fac
 
 	//If migration not succeed, wait for all StorageVersionMigrations succeed.
 	if migrationCond.Status != metav1.ConditionTrue {
-		klog.V(4).Infof("Wait all StorageVersionMigrations succeed. migrationCond: %v. error: %v", migrationCond, err)
+		logger.V(4).Info("Wait all StorageVersionMigrations succeed", "migrationCondition", migrationCond)
 		controllerContext.Queue().AddRateLimited(clusterManagerName)
 	}
 
@@ -6,10 +6,12 @@ import (
 
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	appsinformer "k8s.io/client-go/informers/apps/v1"
+	"k8s.io/client-go/kubernetes"
 	appslister "k8s.io/client-go/listers/apps/v1"
 	"k8s.io/klog/v2"
 
@@ -18,6 +20,7 @@ import (
 	operatorlister "open-cluster-management.io/api/client/operator/listers/operator/v1"
 	operatorapiv1 "open-cluster-management.io/api/operator/v1"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/operator/helpers"
@@ -30,18 +33,26 @@ const (
 )
 
 type clusterManagerStatusController struct {
+	kubeClient           kubernetes.Interface
 	deploymentLister     appslister.DeploymentLister
 	patcher              patcher.Patcher[*operatorapiv1.ClusterManager, operatorapiv1.ClusterManagerSpec, operatorapiv1.ClusterManagerStatus]
 	clusterManagerLister operatorlister.ClusterManagerLister
+	// prober probes the pods of the components if it is set, so a component which is running but not healthy is
+	// reported as degraded.
+	prober helpers.HealthProber
 }
 
 // NewClusterManagerStatusController creates hub cluster manager status controller
 func NewClusterManagerStatusController(
+	kubeClient kubernetes.Interface,
 	clusterManagerClient operatorv1client.ClusterManagerInterface,
 	clusterManagerInformer operatorinformer.ClusterManagerInformer,
 	deploymentInformer appsinformer.DeploymentInformer,
+	prober helpers.HealthProber,
 	recorder events.Recorder) factory.Controller {
 	controller := &clusterManagerStatusController{
+		kubeClient:           kubeClient,
+		prober:               prober,
 		deploymentLister:     deploymentInformer.Lister(),
 		clusterManagerLister: clusterManagerInformer.Lister(),
 		patcher: patcher.NewPatcher[
@@ -49,11 +60,14 @@ func NewClusterManagerStatusController(
 			clusterManagerClient),
 	}
 
-	return factory.New().WithSync(controller.sync).
+	controllerFactory := factory.New().WithSync(logging.WithControllerLogger("ClusterManagerStatusController", controller.sync)).
 		WithInformersQueueKeysFunc(
 			helpers.ClusterManagerDeploymentQueueKeyFunc(controller.clusterManagerLister), deploymentInformer.Informer()).
-		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterManagerInformer.Informer()).
-		ToController("ClusterManagerStatusController", recorder)
+		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterManagerInformer.Informer())
+	if prober != nil {
+		controllerFactory = controllerFactory.ResyncEvery(helpers.HealthProbeResyncInterval)
+	}
+	return controllerFactory.ToController("ClusterManagerStatusController", recorder)
 }
 
 func (s *clusterManagerStatusController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
@@ -62,7 +76,8 @@ func (s *clusterManagerStatusController) sync(ctx context.Context, controllerCon
 		return nil
 	}
 
-	klog.Infof("Reconciling ClusterManager %q", clusterManagerName)
+	logger := klog.FromContext(ctx)
+	logger.Info("Reconciling ClusterManager", "clusterManager", clusterManagerName)
 
 	clusterManager, err := s.clusterManagerLister.Get(clusterManagerName)
 	// ClusterManager not found, could have been deleted, do nothing.
@@ -80,10 +95,10 @@ func (s *clusterManagerStatusController) sync(ctx context.Context, controllerCon
 	clusterManagerNamespace := helpers.ClusterManagerNamespace(clusterManagerName, clusterManager.Spec.DeployOption.Mode)
 	newClusterManager := clusterManager.DeepCopy()
 
-	registrationCond := s.updateStatusOfRegistration(clusterManager.Name, clusterManagerNamespace)
+	registrationCond := s.updateStatusOfRegistration(ctx, clusterManager.Name, clusterManagerNamespace)
 	registrationCond.ObservedGeneration = clusterManager.Generation
 	meta.SetStatusCondition(&newClusterManager.Status.Conditions, registrationCond)
-	placementCond := s.updateStatusOfPlacement(clusterManager.Name, clusterManagerNamespace)
+	placementCond := s.updateStatusOfPlacement(ctx, clusterManager.Name, clusterManagerNamespace)
 	placementCond.ObservedGeneration = clusterManager.Generation
 	meta.SetStatusCondition(&newClusterManager.Status.Conditions, placementCond)
 
@@ -92,7 +107,8 @@ func (s *clusterManagerStatusController) sync(ctx context.Context, controllerCon
 }
 
 // updateStatusOfRegistration checks registration deployment status and updates condition of clustermanager
-func (s *clusterManagerStatusController) updateStatusOfRegistration(clusterManagerName, clusterManagerNamespace string) metav1.Condition {
+func (s *clusterManagerStatusController) updateStatusOfRegistration(ctx context.Context,
+	clusterManagerName, clusterManagerNamespace string) metav1.Condition {
 	// Check registration deployment status
 	registrationDeploymentName := fmt.Sprintf("%s-registration-controller", clusterManagerName)
 	registrationDeployment, err := s.deploymentLister.Deployments(clusterManagerNamespace).Get(registrationDeploymentName)
@@ -115,6 +131,16 @@ func (s *clusterManagerStatusController) updateStatusOfRegistration(clusterManag
 		}
 	}
 
+	if unhealthyPod, message := s.probeDeploymentPods(ctx, registrationDeployment); unhealthyPod > 0 {
+		return metav1.Condition{
+			Type:   registrationDegraded,
+			Status: metav1.ConditionTrue,
+			Reason: "UnhealthyRegistrationPod",
+			Message: fmt.Sprintf("%v of running instances are unhealthy of registration deployment %q %q: %s",
+				unhealthyPod, clusterManagerNamespace, registrationDeploymentName, message),
+		}
+	}
+
 	return metav1.Condition{
 		Type:    registrationDegraded,
 		Status:  metav1.ConditionFalse,
@@ -124,7 +150,8 @@ func (s *clusterManagerStatusController) updateStatusOfRegistration(clusterManag
 }
 
 // updateStatusOfRegistration checks placement deployment status and updates condition of clustermanager
-func (s *clusterManagerStatusController) updateStatusOfPlacement(clusterManagerName, clusterManagerNamespace string) metav1.Condition {
+func (s *clusterManagerStatusController) updateStatusOfPlacement(ctx context.Context,
+	clusterManagerName, clusterManagerNamespace string) metav1.Condition {
 	// Check registration deployment status
 	placementDeploymentName := fmt.Sprintf("%s-placement-controller", clusterManagerName)
 	placementDeployment, err := s.deploymentLister.Deployments(clusterManagerNamespace).Get(placementDeploymentName)
@@ -147,6 +174,16 @@ func (s *clusterManagerStatusController) updateStatusOfPlacement(clusterManagerN
 		}
 	}
 
+	if unhealthyPod, message := s.probeDeploymentPods(ctx, placementDeployment); unhealthyPod > 0 {
+		return metav1.Condition{
+			Type:   placementDegraded,
+			Status: metav1.ConditionTrue,
+			Reason: "UnhealthyPlacementPod",
+			Message: fmt.Sprintf("%v of running instances are unhealthy of placement deployment %q %q: %s",
+				unhealthyPod, clusterManagerNamespace, placementDeploymentName, message),
+		}
+	}
+
 	return metav1.Condition{
 		Type:    placementDegraded,
 		Status:  metav1.ConditionFalse,
@@ -154,3 +191,18 @@ func (s *clusterManagerStatusController) updateStatusOfPlacement(clusterManagerN
 		Message: "Placement is scheduling placement decisions",
 	}
 }
+
+// probeDeploymentPods returns the number of the unhealthy pods of the deployment and the message of the failed probes,
+// the pods are not probed if the prober is not set.
+func (s *clusterManagerStatusController) probeDeploymentPods(ctx context.Context, deployment *appsv1.Deployment) (int, string) {
+	if s.prober == nil {
+		return 0, ""
+	}
+	unhealthyPod, message, err := helpers.ProbeDeploymentPods(ctx, s.kubeClient, s.prober, deployment)
+	if err != nil {
+		// the replicas of the deployment are still checked, do not report degraded if the pods are not probed.
+		klog.FromContext(ctx).Error(err, "Failed to probe the pods", "deployment", klog.KObj(deployment))
+		return 0, ""
+	}
+	return unhealthyPod, message
+}
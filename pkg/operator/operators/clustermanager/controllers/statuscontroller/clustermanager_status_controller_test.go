@@ -8,8 +8,10 @@ import (
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	kubeinformers "k8s.io/client-go/informers"
 	fakekube "k8s.io/client-go/kubernetes/fake"
 	clienttesting "k8s.io/client-go/testing"
@@ -20,6 +22,7 @@ import (
 
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/operator/helpers"
 	testinghelper "open-cluster-management.io/ocm/pkg/operator/helpers/testing"
 )
 
@@ -52,6 +55,9 @@ func newRegistrationDeployment(desiredReplica, availableReplica int32) *appsv1.D
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: &desiredReplica,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": fmt.Sprintf("%s-registration-controller", testClusterManagerName)},
+			},
 		},
 		Status: appsv1.DeploymentStatus{
 			AvailableReplicas: availableReplica,
@@ -67,6 +73,9 @@ func newPlacementDeployment(desiredReplica, availableReplica int32) *appsv1.Depl
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: &desiredReplica,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": fmt.Sprintf("%s-placement-controller", testClusterManagerName)},
+			},
 		},
 		Status: appsv1.DeploymentStatus{
 			AvailableReplicas: availableReplica,
@@ -74,6 +83,31 @@ func newPlacementDeployment(desiredReplica, availableReplica int32) *appsv1.Depl
 	}
 }
 
+func newPod(name, app string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "open-cluster-management-hub",
+			Labels:    map[string]string{"app": app},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			PodIP: "10.0.0.1",
+		},
+	}
+}
+
+type fakeProber struct {
+	unhealthyPods sets.Set[string]
+}
+
+func (p *fakeProber) Probe(_ context.Context, pod *corev1.Pod) error {
+	if p.unhealthyPods.Has(pod.Name) {
+		return fmt.Errorf("pod %q returned 500: healthz check failed", pod.Name)
+	}
+	return nil
+}
+
 func TestSyncStatus(t *testing.T) {
 	appliedCond := metav1.Condition{
 		Type:   clusterManagerApplied,
@@ -84,6 +118,8 @@ func TestSyncStatus(t *testing.T) {
 		queueKey        string
 		clusterManagers []runtime.Object
 		deployments     []runtime.Object
+		pods            []runtime.Object
+		prober          helpers.HealthProber
 		validateActions func(t *testing.T, actions []clienttesting.Action)
 	}{
 		{
@@ -145,6 +181,32 @@ func TestSyncStatus(t *testing.T) {
 				testinghelper.AssertOnlyConditions(t, klusterlet, appliedCond, expectedCondition1, expectedCondition2)
 			},
 		},
+		{
+			name:            "unhealthy registration pods and placement functional",
+			queueKey:        testClusterManagerName,
+			clusterManagers: []runtime.Object{newClusterManager()},
+			deployments: []runtime.Object{
+				newRegistrationDeployment(1, 1),
+				newPlacementDeployment(1, 1),
+			},
+			pods: []runtime.Object{
+				newPod("registration", fmt.Sprintf("%s-registration-controller", testClusterManagerName)),
+				newPod("placement", fmt.Sprintf("%s-placement-controller", testClusterManagerName)),
+			},
+			prober: &fakeProber{unhealthyPods: sets.New[string]("registration")},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch")
+				klusterlet := &operatorapiv1.Klusterlet{}
+				patchData := actions[0].(clienttesting.PatchActionImpl).Patch
+				err := json.Unmarshal(patchData, klusterlet)
+				if err != nil {
+					t.Fatal(err)
+				}
+				expectedCondition1 := testinghelper.NamedCondition(registrationDegraded, "UnhealthyRegistrationPod", metav1.ConditionTrue)
+				expectedCondition2 := testinghelper.NamedCondition(placementDegraded, "PlacementFunctional", metav1.ConditionFalse)
+				testinghelper.AssertOnlyConditions(t, klusterlet, appliedCond, expectedCondition1, expectedCondition2)
+			},
+		},
 		{
 			name:            "registration functional and no placement deployment",
 			queueKey:        testClusterManagerName,
@@ -167,7 +229,7 @@ func TestSyncStatus(t *testing.T) {
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			fakeKubeClient := fakekube.NewSimpleClientset(c.deployments...)
+			fakeKubeClient := fakekube.NewSimpleClientset(append(c.deployments, c.pods...)...)
 			kubeInformers := kubeinformers.NewSharedInformerFactory(fakeKubeClient, 5*time.Minute)
 			deployStore := kubeInformers.Apps().V1().Deployments().Informer().GetStore()
 			for _, deployment := range c.deployments {
@@ -186,6 +248,8 @@ func TestSyncStatus(t *testing.T) {
 			}
 
 			controller := &clusterManagerStatusController{
+				kubeClient:           fakeKubeClient,
+				prober:               c.prober,
 				deploymentLister:     kubeInformers.Apps().V1().Deployments().Lister(),
 				clusterManagerLister: operatorInformers.Operator().V1().ClusterManagers().Lister(),
 				patcher: patcher.NewPatcher[
@@ -24,6 +24,9 @@ import (
 
 type Options struct {
 	SkipRemoveCRDs bool
+	// EnableHealthProbe probes the /healthz endpoint of the pods of the hub components, so a component which is
+	// running but not healthy is reported as degraded.
+	EnableHealthProbe bool
 }
 
 // RunClusterManagerOperator starts a new cluster manager operator
@@ -76,10 +79,16 @@ func (o *Options) RunClusterManagerOperator(ctx context.Context, controllerConte
 		controllerContext.EventRecorder,
 		o.SkipRemoveCRDs)
 
+	var prober helpers.HealthProber
+	if o.EnableHealthProbe {
+		prober = helpers.NewHTTPHealthProber(helpers.ComponentHealthPort, helpers.HealthProbeTimeout)
+	}
 	statusController := clustermanagerstatuscontroller.NewClusterManagerStatusController(
+		kubeClient,
 		operatorClient.OperatorV1().ClusterManagers(),
 		operatorInformer.Operator().V1().ClusterManagers(),
 		kubeInformer.Apps().V1().Deployments(),
+		prober,
 		controllerContext.EventRecorder)
 
 	certRotationController := certrotationcontroller.NewCertRotationController(
@@ -208,7 +208,7 @@ func (m *Manager[T]) applyOne(ctx context.Context, required T) error {
 	existing, err := m.client.Get(ctx, accessor.GetName(), metav1.GetOptions{})
 	if apierrors.IsNotFound(err) {
 		_, err := m.client.Create(ctx, required, metav1.CreateOptions{})
-		klog.Infof("crd %s is created", accessor.GetName())
+		klog.FromContext(ctx).Info("CRD is created", "crd", accessor.GetName())
 		return err
 	}
 	if err != nil {
@@ -242,7 +242,7 @@ func (m *Manager[T]) applyOne(ctx context.Context, required T) error {
 		return err
 	}
 
-	klog.Infof("crd %s is updated to version %s", accessor.GetName(), m.version.String())
+	klog.FromContext(ctx).Info("CRD is updated", "crd", accessor.GetName(), "version", m.version.String())
 
 	return nil
 }
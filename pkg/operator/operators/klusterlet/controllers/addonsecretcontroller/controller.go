@@ -10,6 +10,7 @@ import (
 	coreinformer "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/operator/helpers"
 )
@@ -42,7 +43,9 @@ func NewAddonPullImageSecretController(kubeClient kubernetes.Interface, operator
 	return factory.New().WithFilteredEventsInformersQueueKeysFunc(
 		queue.QueueKeyByMetaName,
 		queue.FileterByLabelKeyValue(addonInstallNamespaceLabelKey, "true"),
-		namespaceInformer.Informer()).WithSync(ac.sync).ToController("AddonPullImageSecretController", recorder)
+		namespaceInformer.Informer()).
+		WithSync(logging.WithControllerLogger("AddonPullImageSecretController", ac.sync)).
+		ToController("AddonPullImageSecretController", recorder)
 }
 
 func (c *addonPullImageSecretController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
@@ -27,6 +27,7 @@ import (
 	operatorlister "open-cluster-management.io/api/client/operator/listers/operator/v1"
 	operatorapiv1 "open-cluster-management.io/api/operator/v1"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/operator/helpers"
 )
@@ -39,7 +40,11 @@ var BootstrapControllerSyncInterval = 5 * time.Minute
 // bootstrapController watches bootstrap-hub-kubeconfig and hub-kubeconfig-secret secrets, if the bootstrap-hub-kubeconfig secret
 // is changed with hub kube-apiserver ca or apiserver endpoints, or the hub-kubeconfig-secret secret is expired, this controller
 // will make the klusterlet re-bootstrap to get the new hub kubeconfig from hub cluster by deleting the current hub kubeconfig
-// secret and restart the klusterlet agents
+// secret and restart the klusterlet agents.
+//
+// It also watches the hub-reconnection secret, when the hub is restored onto a new cluster, the kubeconfig of the restored
+// hub in a signed hub-reconnection secret replaces the one in the bootstrap-hub-kubeconfig secret, so the klusterlet
+// re-bootstraps with the restored hub.
 type bootstrapController struct {
 	kubeClient       kubernetes.Interface
 	klusterletLister operatorlister.KlusterletLister
@@ -63,11 +68,12 @@ func NewBootstrapController(
 		patcher: patcher.NewPatcher[
 			*operatorapiv1.Klusterlet, operatorapiv1.KlusterletSpec, operatorapiv1.KlusterletStatus](klusterletClient),
 	}
-	return factory.New().WithSync(controller.sync).
+	return factory.New().WithSync(logging.WithControllerLogger("BootstrapController", controller.sync)).
 		WithInformersQueueKeysFunc(bootstrapSecretQueueKeyFunc(controller.klusterletLister),
 			secretInformers[helpers.HubKubeConfig].Informer(),
 			secretInformers[helpers.BootstrapHubKubeConfig].Informer(),
-			secretInformers[helpers.ExternalManagedKubeConfig].Informer()).
+			secretInformers[helpers.ExternalManagedKubeConfig].Informer(),
+			secretInformers[helpers.HubReconnection].Informer()).
 		ResyncEvery(BootstrapControllerSyncInterval).
 		ToController("BootstrapController", recorder)
 }
@@ -78,7 +84,8 @@ func (k *bootstrapController) sync(ctx context.Context, controllerContext factor
 		return nil
 	}
 
-	klog.V(4).Infof("Reconciling klusterlet kubeconfig secrets %q", queueKey)
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling klusterlet kubeconfig secrets", "secret", queueKey)
 
 	agentNamespace, klusterletName, err := cache.SplitMetaNamespaceKey(queueKey)
 	if err != nil {
@@ -131,6 +138,13 @@ func (k *bootstrapController) sync(ctx context.Context, controllerContext factor
 		return nil
 	}
 
+	reconnected, err := k.processHubReconnection(ctx, klusterlet, bootstrapHubKubeconfigSecret, bootstrapKubeconfig,
+		controllerContext.Recorder())
+	if err != nil || reconnected {
+		// the bootstrap secret is updated, the klusterlet will be requeued and start rebootstrapping.
+		return err
+	}
+
 	// #nosec G101
 	hubKubeconfigSecret, err := k.secretInformers[helpers.HubKubeConfig].Lister().Secrets(agentNamespace).Get(helpers.HubKubeConfig)
 	switch {
@@ -248,6 +262,59 @@ func (k *bootstrapController) completeRebootstrap(ctx context.Context, agentName
 	return nil
 }
 
+// processHubReconnection replaces the kubeconfig in the bootstrap secret with the one in the hub reconnection secret, if
+// the reconnection secret of the cluster is signed by the reconnection signer trusted by the current bootstrap
+// kubeconfig and not expired. It returns true if the bootstrap secret is updated.
+func (k *bootstrapController) processHubReconnection(ctx context.Context, klusterlet *operatorapiv1.Klusterlet,
+	bootstrapHubKubeconfigSecret *corev1.Secret, bootstrapKubeconfig *clientcmdapi.Cluster, recorder events.Recorder) (bool, error) {
+	namespace := bootstrapHubKubeconfigSecret.Namespace
+	reconnectionSecret, err := k.secretInformers[helpers.HubReconnection].Lister().Secrets(namespace).Get(helpers.HubReconnection)
+	switch {
+	case errors.IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	// the name of the cluster is generated by the registration agent if it is not set in the klusterlet.
+	clusterName := klusterlet.Spec.ClusterName
+	if len(clusterName) == 0 {
+		hubKubeconfigSecret, err := k.secretInformers[helpers.HubKubeConfig].Lister().Secrets(namespace).Get(helpers.HubKubeConfig)
+		switch {
+		case errors.IsNotFound(err):
+		case err != nil:
+			return false, err
+		default:
+			clusterName = string(hubKubeconfigSecret.Data["cluster-name"])
+		}
+	}
+
+	kubeconfig, err := helpers.VerifyHubReconnection(reconnectionSecret, clusterName, time.Now(),
+		bootstrapKubeconfig.CertificateAuthorityData)
+	if err != nil {
+		// an untrusted reconnection secret, ignore it
+		recorder.Warningf("InvalidHubReconnection",
+			fmt.Sprintf("unable to verify the hub reconnection secret %s/%s: %v", namespace, helpers.HubReconnection, err))
+		return false, nil
+	}
+
+	if !bytes.Equal(bootstrapHubKubeconfigSecret.Data["kubeconfig"], kubeconfig) {
+		secret := bootstrapHubKubeconfigSecret.DeepCopy()
+		secret.Data["kubeconfig"] = kubeconfig
+		if _, err := k.kubeClient.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			return false, err
+		}
+		recorder.Eventf("HubReconnectionAccepted", fmt.Sprintf("Secret %s/%s is updated with the kubeconfig of the restored hub",
+			namespace, helpers.BootstrapHubKubeConfig))
+	}
+
+	if err := k.kubeClient.CoreV1().Secrets(namespace).Delete(ctx, helpers.HubReconnection, metav1.DeleteOptions{}); err != nil &&
+		!errors.IsNotFound(err) {
+		return false, err
+	}
+	return true, nil
+}
+
 func (k *bootstrapController) loadKubeConfig(secret *corev1.Secret) (*clientcmdapi.Cluster, error) {
 	kubeconfig, ok := secret.Data["kubeconfig"]
 	if !ok {
@@ -275,7 +342,7 @@ func bootstrapSecretQueueKeyFunc(klusterletLister operatorlister.KlusterletListe
 			return []string{}
 		}
 		name := accessor.GetName()
-		if name != helpers.BootstrapHubKubeConfig {
+		if name != helpers.BootstrapHubKubeConfig && name != helpers.HubReconnection {
 			return []string{}
 		}
 
@@ -1,6 +1,7 @@
 package bootstrapcontroller
 
 import (
+	"bytes"
 	"context"
 	cryptorand "crypto/rand"
 	"crypto/rsa"
@@ -36,6 +37,18 @@ import (
 )
 
 func TestSync(t *testing.T) {
+	restoredKubeConfig := newKubeConfig("https://10.0.118.49:6443", "")
+	caPEM, signerPEM, signerKey := newHubReconnectionSigner()
+	_, untrustedSignerPEM, untrustedSignerKey := newHubReconnectionSigner()
+	reconnectionSecret := newHubReconnectionSecret("test", "cluster1", time.Now().Add(time.Hour), restoredKubeConfig,
+		signerPEM, signerKey)
+	untrustedReconnectionSecret := newHubReconnectionSecret("test", "cluster1", time.Now().Add(time.Hour), restoredKubeConfig,
+		untrustedSignerPEM, untrustedSignerKey)
+	otherClusterReconnectionSecret := newHubReconnectionSecret("test", "cluster2", time.Now().Add(time.Hour), restoredKubeConfig,
+		signerPEM, signerKey)
+	expiredReconnectionSecret := newHubReconnectionSecret("test", "cluster1", time.Now().Add(-time.Minute), restoredKubeConfig,
+		signerPEM, signerKey)
+
 	cases := []struct {
 		name                    string
 		queueKey                string
@@ -119,6 +132,62 @@ func TestSync(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:     "hub reconnection is accepted",
+			queueKey: "test/test",
+			objects: []runtime.Object{
+				newSecret("bootstrap-hub-kubeconfig", "test", newKubeConfigWithCA("https://10.0.118.47:6443", caPEM)),
+				newHubKubeConfigSecret("test", time.Now().Add(60*time.Second).UTC()),
+				reconnectionSecret,
+			},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "update", "delete")
+				secret := actions[0].(clienttesting.UpdateAction).GetObject().(*corev1.Secret)
+				if !bytes.Equal(secret.Data["kubeconfig"], newKubeConfig("https://10.0.118.49:6443", "")) {
+					t.Errorf("expected the bootstrap kubeconfig is replaced, but got %s", secret.Data["kubeconfig"])
+				}
+				testingcommon.AssertDelete(t, actions[1], "secrets", "test", helpers.HubReconnection)
+			},
+		},
+		{
+			name:     "hub reconnection is not trusted",
+			queueKey: "test/test",
+			objects: []runtime.Object{
+				newSecret("bootstrap-hub-kubeconfig", "test", newKubeConfigWithCA("https://10.0.118.47:6443", caPEM)),
+				newHubKubeConfigSecret("test", time.Now().Add(60*time.Second).UTC()),
+				untrustedReconnectionSecret,
+			},
+			expectedRebootstrapping: true,
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+		{
+			name:     "hub reconnection is for another cluster",
+			queueKey: "test/test",
+			objects: []runtime.Object{
+				newSecret("bootstrap-hub-kubeconfig", "test", newKubeConfigWithCA("https://10.0.118.47:6443", caPEM)),
+				newHubKubeConfigSecret("test", time.Now().Add(60*time.Second).UTC()),
+				otherClusterReconnectionSecret,
+			},
+			expectedRebootstrapping: true,
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+		{
+			name:     "hub reconnection is expired",
+			queueKey: "test/test",
+			objects: []runtime.Object{
+				newSecret("bootstrap-hub-kubeconfig", "test", newKubeConfigWithCA("https://10.0.118.47:6443", caPEM)),
+				newHubKubeConfigSecret("test", time.Now().Add(60*time.Second).UTC()),
+				expiredReconnectionSecret,
+			},
+			expectedRebootstrapping: true,
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
 		{
 			name:                  "wait for scaling down",
 			queueKey:              "test/test",
@@ -179,6 +248,7 @@ func TestSync(t *testing.T) {
 				helpers.HubKubeConfig:             newOnTermInformer(helpers.HubKubeConfig).Core().V1().Secrets(),
 				helpers.BootstrapHubKubeConfig:    newOnTermInformer(helpers.BootstrapHubKubeConfig).Core().V1().Secrets(),
 				helpers.ExternalManagedKubeConfig: newOnTermInformer(helpers.ExternalManagedKubeConfig).Core().V1().Secrets(),
+				helpers.HubReconnection:           newOnTermInformer(helpers.HubReconnection).Core().V1().Secrets(),
 			}
 
 			for _, o := range c.objects {
@@ -200,6 +270,11 @@ func TestSync(t *testing.T) {
 						if err := secretStore.Add(object); err != nil {
 							t.Fatal(err)
 						}
+					case helpers.HubReconnection:
+						secretStore := secretInformers[helpers.HubReconnection].Informer().GetStore()
+						if err := secretStore.Add(object); err != nil {
+							t.Fatal(err)
+						}
 					}
 				}
 			}
@@ -246,6 +321,12 @@ func TestBootstrapSecretQueueKeyFunc(t *testing.T) {
 			klusterlet:  newKlusterlet("testklusterlet", "test", ""),
 			expectedKey: []string{"test/testklusterlet"},
 		},
+		{
+			name:        "key by hub reconnection secret",
+			object:      newSecret("hub-reconnection", "test", []byte{}),
+			klusterlet:  newKlusterlet("testklusterlet", "test", ""),
+			expectedKey: []string{"test/testklusterlet"},
+		},
 		{
 			name:        "key by wrong secret",
 			object:      newSecret("dummy", "test", []byte{}),
@@ -318,6 +399,71 @@ func newKubeConfig(host, proxyURL string) []byte {
 	return configData
 }
 
+func newKubeConfigWithCA(host string, caData []byte) []byte {
+	configData, _ := runtime.Encode(clientcmdlatest.Codec, &clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{"default-cluster": {
+			Server:                   host,
+			CertificateAuthorityData: caData,
+		}},
+		Contexts: map[string]*clientcmdapi.Context{"default-context": {
+			Cluster: "default-cluster",
+		}},
+		CurrentContext: "default-context",
+	})
+	return configData
+}
+
+// newHubReconnectionSigner returns a CA, and the cert and the key of a hub reconnection signer issued by it.
+func newHubReconnectionSigner() ([]byte, []byte, *rsa.PrivateKey) {
+	caKey, err := rsa.GenerateKey(cryptorand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	caCert, err := certutil.NewSelfSignedCACert(certutil.Config{CommonName: "open-cluster-management.io"}, caKey)
+	if err != nil {
+		panic(err)
+	}
+
+	key, err := rsa.GenerateKey(cryptorand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	certDERBytes, err := x509.CreateCertificate(
+		cryptorand.Reader,
+		&x509.Certificate{
+			Subject: pkix.Name{
+				CommonName: helpers.HubReconnectionSignerCommonName,
+			},
+			SerialNumber: big.NewInt(1),
+			NotBefore:    caCert.NotBefore,
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		},
+		caCert,
+		key.Public(),
+		caKey,
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: caCert.Raw}),
+		pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: certDERBytes}), key
+}
+
+// newHubReconnectionSecret returns a hub reconnection secret of the kubeconfig for the cluster signed by the signer.
+func newHubReconnectionSecret(namespace, clusterName string, expiration time.Time, kubeconfig, signerPEM []byte,
+	key *rsa.PrivateKey) *corev1.Secret {
+	secret, err := helpers.SignHubReconnection(namespace, clusterName, expiration, kubeconfig, signerPEM, key)
+	if err != nil {
+		panic(err)
+	}
+	return secret
+}
+
 func newHubKubeConfigSecret(namespace string, notAfter time.Time) *corev1.Secret {
 	caKey, err := rsa.GenerateKey(cryptorand.Reader, 2048)
 	if err != nil {
@@ -365,7 +511,8 @@ func newHubKubeConfigSecret(namespace string, notAfter time.Time) *corev1.Secret
 			Namespace: namespace,
 		},
 		Data: map[string][]byte{
-			"kubeconfig": newKubeConfig("https://10.0.118.47:6443", ""),
+			"kubeconfig":   newKubeConfig("https://10.0.118.47:6443", ""),
+			"cluster-name": []byte("cluster1"),
 			"tls.crt": pem.EncodeToMemory(&pem.Block{
 				Type:  certutil.CertificateBlockType,
 				Bytes: cert.Raw,
@@ -26,6 +26,7 @@ import (
 	operatorapiv1 "open-cluster-management.io/api/operator/v1"
 
 	"open-cluster-management.io/ocm/manifests"
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/operator/helpers"
@@ -63,7 +64,7 @@ func NewKlusterletCleanupController(
 		managedClusterClientsBuilder: newManagedClusterClientsBuilder(kubeClient, apiExtensionClient, appliedManifestWorkClient, recorder),
 	}
 
-	return factory.New().WithSync(controller.sync).
+	return factory.New().WithSync(logging.WithControllerLogger("KlusterletCleanupController", controller.sync)).
 		WithInformersQueueKeysFunc(helpers.KlusterletSecretQueueKeyFunc(controller.klusterletLister),
 			secretInformers[helpers.HubKubeConfig].Informer(),
 			secretInformers[helpers.BootstrapHubKubeConfig].Informer(),
@@ -75,7 +76,8 @@ func NewKlusterletCleanupController(
 
 func (n *klusterletCleanupController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
 	klusterletName := controllerContext.QueueKey()
-	klog.V(4).Infof("Reconciling Klusterlet %q", klusterletName)
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling Klusterlet", "klusterlet", klusterletName)
 	originalKlusterlet, err := n.klusterletLister.Get(klusterletName)
 	if errors.IsNotFound(err) {
 		// Klusterlet not found, could have been deleted, do nothing.
@@ -209,8 +211,9 @@ func (n *klusterletCleanupController) checkConnectivity(ctx context.Context,
 	// if the managed cluster is destroyed, the returned err is TCP timeout or TCP no such host,
 	// the k8s.io/apimachinery/pkg/api/errors.IsTimeout,IsServerTimeout can not match this error
 	if isTCPTimeOutError(err) || isTCPNoSuchHostError(err) || isTCPConnectionRefusedError(err) {
-		klog.V(4).Infof("Check the connectivity for klusterlet %s, annotation: %s, err: %v",
-			klusterlet.Name, klusterlet.Annotations, err)
+		logger := klog.FromContext(ctx)
+		logger.V(4).Info("Check the connectivity for klusterlet",
+			"klusterlet", klusterlet.Name, "annotations", klusterlet.Annotations, "err", err)
 		if klusterlet.Annotations == nil {
 			klusterlet.Annotations = make(map[string]string, 0)
 		}
@@ -222,14 +225,14 @@ func (n *klusterletCleanupController) checkConnectivity(ctx context.Context,
 		}
 		evictionTime, perr := time.Parse(time.RFC3339, evictionTimeStr)
 		if perr != nil {
-			klog.Infof("Parse eviction time %v for klusterlet %s error %s", evictionTimeStr, klusterlet.Name, perr)
+			logger.Info("Parse eviction time error", "evictionTime", evictionTimeStr, "klusterlet", klusterlet.Name, "err", perr)
 			klusterlet.Annotations[managedResourcesEvictionTimestampAnno] = time.Now().Format(time.RFC3339)
 			return true, err
 		}
 
 		if evictionTime.Add(5 * time.Minute).Before(time.Now()) {
-			klog.Infof("Try to connect managed cluster timed out for 5 minutes, klusterlet %s, ignore the resources",
-				klusterlet.Name)
+			logger.Info("Try to connect managed cluster timed out for 5 minutes, ignore the resources",
+				"klusterlet", klusterlet.Name)
 			// ignore the resources on the managed cluster, return false here
 			return false, nil
 		}
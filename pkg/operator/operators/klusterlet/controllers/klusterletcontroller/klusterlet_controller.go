@@ -29,9 +29,11 @@ import (
 	operatorapiv1 "open-cluster-management.io/api/operator/v1"
 
 	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/operator/helpers"
+	workhelper "open-cluster-management.io/ocm/pkg/work/helper"
 )
 
 const (
@@ -45,6 +47,30 @@ const (
 	hubConnectionDegraded                 = "HubConnectionDegraded"
 	hubKubeConfigSecretMissing            = "HubKubeConfigSecretMissing" // #nosec G101
 	managedResourcesEvictionTimestampAnno = "operator.open-cluster-management.io/managed-resources-eviction-timestamp"
+
+	// agentPriorityClassNameAnnotation on the Klusterlet overrides the priority class of the agents, which is
+	// system-cluster-critical by default, so the agents are the last ones evicted under node pressure. The agents
+	// are deployed without a priority class if it is set to empty.
+	agentPriorityClassNameAnnotation = "operator.open-cluster-management.io/agent-priority-class-name"
+	defaultAgentPriorityClassName    = "system-cluster-critical"
+
+	// workAllowedResourcesAnnotation and workDeniedResourcesAnnotation on the Klusterlet restrict the kinds of the
+	// resources the works are allowed to apply on the managed cluster, the values are the comma-separated rules in
+	// the format of <Kind>.<group>, e.g. "Secret,*.rbac.authorization.k8s.io". A work with any resource not allowed
+	// is blocked by the work agent.
+	workAllowedResourcesAnnotation = "operator.open-cluster-management.io/work-allowed-resources"
+	workDeniedResourcesAnnotation  = "operator.open-cluster-management.io/work-denied-resources"
+
+	// csrSignerNameAnnotation on the Klusterlet is the signer name of the CSRs the registration agent creates for its
+	// client certificate, e.g. example.com/corporate-ca. The hub must be configured to approve the CSRs of the signer,
+	// and the signer must issue certificates trusted by the hub apiserver.
+	csrSignerNameAnnotation = "operator.open-cluster-management.io/csr-signer-name"
+
+	// dynamicFeatureGatesAnnotation on the Klusterlet set to "true" delivers the feature gates to the agents with a
+	// configmap in the agent namespace instead of the args of the agents, so the changed feature gates are reloaded by
+	// the agents without redeploying them. The feature gates active in the agents are reported in the
+	// FeatureGatesActive condition of the Klusterlet.
+	dynamicFeatureGatesAnnotation = "operator.open-cluster-management.io/dynamic-feature-gates"
 )
 
 type klusterletController struct {
@@ -95,7 +121,7 @@ func NewKlusterletController(
 		managedClusterClientsBuilder: newManagedClusterClientsBuilder(kubeClient, apiExtensionClient, appliedManifestWorkClient, recorder),
 	}
 
-	return factory.New().WithSync(controller.sync).
+	return factory.New().WithSync(logging.WithControllerLogger("KlusterletController", controller.sync)).
 		WithInformersQueueKeysFunc(helpers.KlusterletSecretQueueKeyFunc(controller.klusterletLister),
 			secretInformers[helpers.HubKubeConfig].Informer(),
 			secretInformers[helpers.BootstrapHubKubeConfig].Informer(),
@@ -146,11 +172,23 @@ type klusterletConfig struct {
 	WorkFeatureGates         []string
 
 	HubApiServerHostAlias *operatorapiv1.HubApiServerHostAlias
+
+	PriorityClassName string
+
+	WorkAllowedResources string
+	WorkDeniedResources  string
+
+	CSRSignerName string
+
+	// FeatureGatesConfigMap is the name of the configmap with the feature gates of the agents, the feature gates are
+	// passed to the agents with the args if it is empty.
+	FeatureGatesConfigMap string
 }
 
 func (n *klusterletController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
 	klusterletName := controllerContext.QueueKey()
-	klog.V(4).Infof("Reconciling Klusterlet %q", klusterletName)
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling Klusterlet", "klusterlet", klusterletName)
 	originalKlusterlet, err := n.klusterletLister.Get(klusterletName)
 	if errors.IsNotFound(err) {
 		// Klusterlet not found, could have been deleted, do nothing.
@@ -187,6 +225,19 @@ func (n *klusterletController) sync(ctx context.Context, controllerContext facto
 		WorkServiceAccount:         serviceAccountName("work-sa", klusterlet),
 	}
 
+	config.PriorityClassName = defaultAgentPriorityClassName
+	if priorityClassName, ok := klusterlet.Annotations[agentPriorityClassNameAnnotation]; ok {
+		config.PriorityClassName = priorityClassName
+	}
+	config.WorkAllowedResources, config.WorkDeniedResources = workResourcePolicy(klusterlet, controllerContext.Recorder())
+	if signerName := klusterlet.Annotations[csrSignerNameAnnotation]; len(signerName) > 0 {
+		if err := helpers.ValidateSignerName(signerName); err != nil {
+			controllerContext.Recorder().Warningf("InvalidCSRSignerName", "The CSR signer name is ignored: %v", err)
+		} else {
+			config.CSRSignerName = signerName
+		}
+	}
+
 	managedClusterClients, err := n.managedClusterClientsBuilder.
 		withMode(config.InstallMode).
 		withKubeConfigSecret(config.AgentNamespace, config.ExternalManagedKubeConfigSecret).
@@ -254,6 +305,9 @@ func (n *klusterletController) sync(ctx context.Context, controllerContext facto
 	}
 	config.WorkFeatureGates, workFeatureMsgs = helpers.ConvertToFeatureGateFlags("Work", workFeatureGates, ocmfeature.DefaultSpokeWorkFeatureGates)
 	meta.SetStatusCondition(&klusterlet.Status.Conditions, helpers.BuildFeatureCondition(registrationFeatureMsgs, workFeatureMsgs))
+	if klusterlet.Annotations[dynamicFeatureGatesAnnotation] == "true" {
+		config.FeatureGatesConfigMap = klusterlet.Name + helpers.FeatureGatesConfigMapSuffix
+	}
 
 	reconcilers := []klusterletReconcile{
 		&crdReconcile{
@@ -335,6 +389,28 @@ func getManagedKubeConfig(ctx context.Context, kubeClient kubernetes.Interface,
 	return helpers.LoadClientConfigFromSecret(managedKubeconfigSecret)
 }
 
+// workResourcePolicy returns the allowed and the denied resource rules of the work agent from the annotations of the
+// klusterlet, both of them are ignored if any rule is invalid.
+func workResourcePolicy(klusterlet *operatorapiv1.Klusterlet, recorder events.Recorder) (string, string) {
+	allowed := splitResourceRules(klusterlet.Annotations[workAllowedResourcesAnnotation])
+	denied := splitResourceRules(klusterlet.Annotations[workDeniedResourcesAnnotation])
+	if _, err := workhelper.NewResourcePolicy(allowed, denied); err != nil {
+		recorder.Warningf("InvalidWorkResourcePolicy", "The resource policy of the work agent is ignored: %v", err)
+		return "", ""
+	}
+	return strings.Join(allowed, ","), strings.Join(denied, ",")
+}
+
+func splitResourceRules(value string) []string {
+	var rules []string
+	for _, rule := range strings.Split(value, ",") {
+		if rule = strings.TrimSpace(rule); len(rule) > 0 {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
 // ensureAgentNamespace create agent namespace if it is not exist
 func ensureAgentNamespace(ctx context.Context, kubeClient kubernetes.Interface, namespace string, recorder events.Recorder) error {
 	_, _, err := resourceapply.ApplyNamespace(ctx, kubeClient.CoreV1(), recorder, &corev1.Namespace{
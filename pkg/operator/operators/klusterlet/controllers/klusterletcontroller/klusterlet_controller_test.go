@@ -451,6 +451,16 @@ func ensureObject(t *testing.T, object runtime.Object, klusterlet *operatorapiv1
 	namespace := helpers.AgentNamespace(klusterlet)
 	switch o := object.(type) {
 	case *appsv1.Deployment:
+		expectedPriorityClassName := defaultAgentPriorityClassName
+		if priorityClassName, ok := klusterlet.Annotations[agentPriorityClassNameAnnotation]; ok {
+			expectedPriorityClassName = priorityClassName
+		}
+		if o.Spec.Template.Spec.PriorityClassName != expectedPriorityClassName {
+			t.Errorf("Expect priority class %q, but got %q", expectedPriorityClassName, o.Spec.Template.Spec.PriorityClassName)
+		}
+		if len(o.Spec.Template.Spec.Tolerations) != len(klusterlet.Spec.NodePlacement.Tolerations)+len(agentTolerations) {
+			t.Errorf("Expect the agent tolerations are added, but got %v", o.Spec.Template.Spec.Tolerations)
+		}
 		if strings.Contains(access.GetName(), "registration") {
 			testingcommon.AssertEqualNameNamespace(
 				t, access.GetName(), access.GetNamespace(),
@@ -482,6 +492,252 @@ func ensureObject(t *testing.T, object runtime.Object, klusterlet *operatorapiv1
 	}
 }
 
+func TestSyncDeployAgentPriorityClass(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+	}{
+		{
+			name: "default priority class",
+		},
+		{
+			name:        "custom priority class",
+			annotations: map[string]string{agentPriorityClassNameAnnotation: "klusterlet-critical"},
+		},
+		{
+			name:        "no priority class",
+			annotations: map[string]string{agentPriorityClassNameAnnotation: ""},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			klusterlet := newKlusterlet("klusterlet", "testns", "cluster1")
+			klusterlet.Annotations = c.annotations
+			klusterlet.Spec.NodePlacement.Tolerations = []corev1.Toleration{
+				{Key: "node-role.kubernetes.io/infra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+			}
+			bootStrapSecret := newSecret(helpers.BootstrapHubKubeConfig, "testns")
+			hubKubeConfigSecret := newSecret(helpers.HubKubeConfig, "testns")
+			hubKubeConfigSecret.Data["kubeconfig"] = []byte("dummuykubeconnfig")
+			namespace := newNamespace("testns")
+			syncContext := testingcommon.NewFakeSyncContext(t, "klusterlet")
+			controller := newTestController(t, klusterlet, syncContext.Recorder(), nil, bootStrapSecret, hubKubeConfigSecret, namespace)
+
+			if err := controller.controller.sync(context.TODO(), syncContext); err != nil {
+				t.Errorf("Expected non error when sync, %v", err)
+			}
+
+			for _, suffix := range []string{"registration-agent", "work-agent"} {
+				deployment := getDeployments(controller.kubeClient.Actions(), createVerb, suffix)
+				if deployment == nil {
+					t.Fatalf("%s deployment not found", suffix)
+				}
+				ensureObject(t, deployment, klusterlet)
+			}
+		})
+	}
+}
+
+func TestSyncDeployCSRSignerName(t *testing.T) {
+	cases := []struct {
+		name         string
+		signerName   string
+		expectedArgs []string
+		missingArgs  []string
+	}{
+		{
+			name:        "no signer name",
+			missingArgs: []string{"--csr-signer-name"},
+		},
+		{
+			name:         "signer name",
+			signerName:   "example.com/corporate-ca",
+			expectedArgs: []string{"--csr-signer-name=example.com/corporate-ca"},
+		},
+		{
+			name:        "invalid signer name",
+			signerName:  "corporate-ca",
+			missingArgs: []string{"--csr-signer-name"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			klusterlet := newKlusterlet("klusterlet", "testns", "cluster1")
+			if len(c.signerName) > 0 {
+				klusterlet.Annotations = map[string]string{csrSignerNameAnnotation: c.signerName}
+			}
+			bootStrapSecret := newSecret(helpers.BootstrapHubKubeConfig, "testns")
+			hubKubeConfigSecret := newSecret(helpers.HubKubeConfig, "testns")
+			hubKubeConfigSecret.Data["kubeconfig"] = []byte("dummuykubeconnfig")
+			namespace := newNamespace("testns")
+			syncContext := testingcommon.NewFakeSyncContext(t, "klusterlet")
+			controller := newTestController(t, klusterlet, syncContext.Recorder(), nil, bootStrapSecret, hubKubeConfigSecret, namespace)
+
+			if err := controller.controller.sync(context.TODO(), syncContext); err != nil {
+				t.Errorf("Expected non error when sync, %v", err)
+			}
+
+			deployment := getDeployments(controller.kubeClient.Actions(), createVerb, "registration-agent")
+			if deployment == nil {
+				t.Fatalf("registration deployment not found")
+			}
+			args := strings.Join(deployment.Spec.Template.Spec.Containers[0].Args, " ")
+			for _, arg := range c.expectedArgs {
+				if !strings.Contains(args, arg) {
+					t.Errorf("Expect arg %q, but got %v", arg, args)
+				}
+			}
+			for _, arg := range c.missingArgs {
+				if strings.Contains(args, arg) {
+					t.Errorf("Expect no arg %q, but got %v", arg, args)
+				}
+			}
+		})
+	}
+}
+
+func TestSyncDeployWorkResourcePolicy(t *testing.T) {
+	cases := []struct {
+		name         string
+		annotations  map[string]string
+		expectedArgs []string
+		missingArgs  []string
+	}{
+		{
+			name: "resource policy",
+			annotations: map[string]string{
+				workAllowedResourcesAnnotation: "Deployment.apps, ConfigMap",
+				workDeniedResourcesAnnotation:  "*.rbac.authorization.k8s.io",
+			},
+			expectedArgs: []string{
+				"--allowed-resources=Deployment.apps,ConfigMap",
+				"--denied-resources=*.rbac.authorization.k8s.io",
+			},
+		},
+		{
+			name: "invalid resource policy",
+			annotations: map[string]string{
+				workAllowedResourcesAnnotation: "Deployment.apps",
+				workDeniedResourcesAnnotation:  "rbac.authorization.k8s.io/v1",
+			},
+			missingArgs: []string{"--allowed-resources", "--denied-resources"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			klusterlet := newKlusterlet("klusterlet", "testns", "cluster1")
+			klusterlet.Annotations = c.annotations
+			bootStrapSecret := newSecret(helpers.BootstrapHubKubeConfig, "testns")
+			hubKubeConfigSecret := newSecret(helpers.HubKubeConfig, "testns")
+			hubKubeConfigSecret.Data["kubeconfig"] = []byte("dummuykubeconnfig")
+			namespace := newNamespace("testns")
+			syncContext := testingcommon.NewFakeSyncContext(t, "klusterlet")
+			controller := newTestController(t, klusterlet, syncContext.Recorder(), nil, bootStrapSecret, hubKubeConfigSecret, namespace)
+
+			if err := controller.controller.sync(context.TODO(), syncContext); err != nil {
+				t.Errorf("Expected non error when sync, %v", err)
+			}
+
+			deployment := getDeployments(controller.kubeClient.Actions(), createVerb, "work-agent")
+			if deployment == nil {
+				t.Fatalf("work deployment not found")
+			}
+			args := strings.Join(deployment.Spec.Template.Spec.Containers[0].Args, " ")
+			for _, arg := range c.expectedArgs {
+				if !strings.Contains(args, arg) {
+					t.Errorf("Expect arg %q, but got %v", arg, args)
+				}
+			}
+			for _, arg := range c.missingArgs {
+				if strings.Contains(args, arg) {
+					t.Errorf("Expect no arg %q, but got %v", arg, args)
+				}
+			}
+		})
+	}
+}
+
+func TestSyncDeployDynamicFeatureGates(t *testing.T) {
+	klusterlet := newKlusterlet("klusterlet", "testns", "cluster1")
+	klusterlet.Annotations = map[string]string{dynamicFeatureGatesAnnotation: "true"}
+	klusterlet.Spec.RegistrationConfiguration.FeatureGates = []operatorapiv1.FeatureGate{
+		{Feature: "ClusterClaim", Mode: operatorapiv1.FeatureGateModeTypeDisable},
+		{Feature: "AddonManagement", Mode: operatorapiv1.FeatureGateModeTypeDisable},
+	}
+	klusterlet.Spec.WorkConfiguration = &operatorapiv1.WorkConfiguration{
+		FeatureGates: []operatorapiv1.FeatureGate{
+			{Feature: "RawFeedbackJsonString", Mode: operatorapiv1.FeatureGateModeTypeEnable},
+		},
+	}
+	bootStrapSecret := newSecret(helpers.BootstrapHubKubeConfig, "testns")
+	hubKubeConfigSecret := newSecret(helpers.HubKubeConfig, "testns")
+	hubKubeConfigSecret.Data["kubeconfig"] = []byte("dummuykubeconnfig")
+	namespace := newNamespace("testns")
+	syncContext := testingcommon.NewFakeSyncContext(t, "klusterlet")
+	controller := newTestController(t, klusterlet, syncContext.Recorder(), nil, bootStrapSecret, hubKubeConfigSecret, namespace)
+
+	if err := controller.controller.sync(context.TODO(), syncContext); err != nil {
+		t.Errorf("Expected non error when sync, %v", err)
+	}
+
+	for _, suffix := range []string{"registration-agent", "work-agent"} {
+		deployment := getDeployments(controller.kubeClient.Actions(), createVerb, suffix)
+		if deployment == nil {
+			t.Fatalf("%s deployment not found", suffix)
+		}
+		args := strings.Join(deployment.Spec.Template.Spec.Containers[0].Args, " ")
+		if !strings.Contains(args, "--feature-gates-configmap=klusterlet-feature-gates") {
+			t.Errorf("Expect the feature gates configmap arg, but got %v", args)
+		}
+		if strings.Contains(args, "--feature-gates=") {
+			t.Errorf("Expect no feature gates arg, but got %v", args)
+		}
+	}
+
+	configMap, err := controller.kubeClient.CoreV1().ConfigMaps("testns").Get(
+		context.TODO(), "klusterlet"+helpers.FeatureGatesConfigMapSuffix, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected the feature gates configmap, but got %v", err)
+	}
+	if configMap.Data["registration"] != "AddonManagement=false,ClusterClaim=false" ||
+		configMap.Data["work"] != "RawFeedbackJsonString=true" {
+		t.Errorf("Unexpected feature gates %v", configMap.Data)
+	}
+
+	// the configmap is removed once the annotation is removed
+	klusterlet.Annotations = nil
+	if err := controller.operatorStore.Update(klusterlet); err != nil {
+		t.Fatal(err)
+	}
+	if err := controller.controller.sync(context.TODO(), syncContext); err != nil {
+		t.Errorf("Expected non error when sync, %v", err)
+	}
+	_, err = controller.kubeClient.CoreV1().ConfigMaps("testns").Get(
+		context.TODO(), "klusterlet"+helpers.FeatureGatesConfigMapSuffix, metav1.GetOptions{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("Expected the feature gates configmap is removed, but got %v", err)
+	}
+}
+
+func TestAgentNodePlacement(t *testing.T) {
+	nodePlacement := operatorapiv1.NodePlacement{
+		Tolerations: []corev1.Toleration{
+			{Key: corev1.TaintNodeMemoryPressure, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+		},
+	}
+
+	placement := agentNodePlacement(nodePlacement)
+	if len(placement.Tolerations) != len(agentTolerations) {
+		t.Errorf("Expect the existing toleration is not duplicated, but got %v", placement.Tolerations)
+	}
+	if len(nodePlacement.Tolerations) != 1 {
+		t.Errorf("Expect the node placement is not changed, but got %v", nodePlacement.Tolerations)
+	}
+}
+
 // TestSyncDeploy test deployment of klusterlet components
 func TestSyncDeploy(t *testing.T) {
 	klusterlet := newKlusterlet("klusterlet", "testns", "cluster1")
@@ -508,8 +764,8 @@ func TestSyncDeploy(t *testing.T) {
 	}
 
 	// Check if resources are created as expected
-	// 11 managed static manifests + 12 management static manifests - 2 duplicated service account manifests + 1 addon namespace + 2 deployments
-	if len(createObjects) != 24 {
+	// 13 managed static manifests + 12 management static manifests - 2 duplicated service account manifests + 1 addon namespace + 2 deployments
+	if len(createObjects) != 26 {
 		t.Errorf("Expect 24 objects created in the sync loop, actual %d", len(createObjects))
 	}
 	for _, object := range createObjects {
@@ -569,8 +825,8 @@ func TestSyncDeploySingleton(t *testing.T) {
 	}
 
 	// Check if resources are created as expected
-	// 10 managed static manifests + 11 management static manifests - 1 service account manifests + 1 addon namespace + 1 deployments
-	if len(createObjects) != 22 {
+	// 12 managed static manifests + 11 management static manifests - 1 service account manifests + 1 addon namespace + 1 deployments
+	if len(createObjects) != 24 {
 		t.Errorf("Expect 21 objects created in the sync loop, actual %d", len(createObjects))
 	}
 	for _, object := range createObjects {
@@ -658,9 +914,9 @@ func TestSyncDeployHosted(t *testing.T) {
 		}
 	}
 	// Check if resources are created as expected on the managed cluster
-	// 12 static manifests + 2 namespaces + 1 pull secret in the addon namespace
-	if len(createObjectsManaged) != 15 {
-		t.Errorf("Expect 15 objects created in the sync loop, actual %d", len(createObjectsManaged))
+	// 14 static manifests + 2 namespaces + 1 pull secret in the addon namespace
+	if len(createObjectsManaged) != 17 {
+		t.Errorf("Expect 17 objects created in the sync loop, actual %d", len(createObjectsManaged))
 	}
 	for _, object := range createObjectsManaged {
 		ensureObject(t, object, klusterlet)
@@ -1014,10 +1270,10 @@ func TestDeployOnKube111(t *testing.T) {
 	}
 
 	// Check if resources are created as expected
-	// 12 managed static manifests + 11 management static manifests -
+	// 14 managed static manifests + 11 management static manifests -
 	// 2 duplicated service account manifests + 1 addon namespace + 2 deployments + 2 kube111 clusterrolebindings
-	if len(createObjects) != 26 {
-		t.Errorf("Expect 26 objects created in the sync loop, actual %d", len(createObjects))
+	if len(createObjects) != 28 {
+		t.Errorf("Expect 28 objects created in the sync loop, actual %d", len(createObjects))
 	}
 	for _, object := range createObjects {
 		ensureObject(t, object, klusterlet)
@@ -1059,8 +1315,8 @@ func TestDeployOnKube111(t *testing.T) {
 		}
 	}
 
-	// 12 managed static manifests + 11 management static manifests + 1 hub kubeconfig + 2 namespaces + 2 deployments + 2 kube111 clusterrolebindings
-	if len(deleteActions) != 31 {
+	// 14 managed static manifests + 11 management static manifests + 1 hub kubeconfig + 2 namespaces + 2 deployments + 2 kube111 clusterrolebindings
+	if len(deleteActions) != 33 {
 		t.Errorf("Expected 30 delete actions, but got %d", len(deleteActions))
 	}
 }
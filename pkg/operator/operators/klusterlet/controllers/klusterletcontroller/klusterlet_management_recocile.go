@@ -7,10 +7,13 @@ package klusterletcontroller
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/openshift/library-go/pkg/assets"
 	"github.com/openshift/library-go/pkg/operator/events"
 	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -20,6 +23,7 @@ import (
 	operatorapiv1 "open-cluster-management.io/api/operator/v1"
 
 	"open-cluster-management.io/ocm/manifests"
+	"open-cluster-management.io/ocm/pkg/features"
 	"open-cluster-management.io/ocm/pkg/operator/helpers"
 )
 
@@ -93,9 +97,63 @@ func (r *managementReconcile) reconcile(ctx context.Context, klusterlet *operato
 		return klusterlet, reconcileStop, applyErrors
 	}
 
+	if err := r.reconcileFeatureGatesConfigMap(ctx, klusterlet, config); err != nil {
+		meta.SetStatusCondition(&klusterlet.Status.Conditions, metav1.Condition{
+			Type: klusterletApplied, Status: metav1.ConditionFalse, Reason: "ManagementClusterResourceApplyFailed",
+			Message: fmt.Sprintf("Failed to apply the feature gates configmap: %v", err),
+		})
+		return klusterlet, reconcileStop, err
+	}
+
 	return klusterlet, reconcileContinue, nil
 }
 
+// reconcileFeatureGatesConfigMap applies the configmap with the feature gates of the agents if the feature gates are
+// reloaded by the agents, otherwise removes it. The annotations of the active feature gates set by the agents on the
+// configmap are kept.
+func (r *managementReconcile) reconcileFeatureGatesConfigMap(ctx context.Context, klusterlet *operatorapiv1.Klusterlet,
+	config klusterletConfig) error {
+	if len(config.FeatureGatesConfigMap) == 0 {
+		name := klusterlet.Name + helpers.FeatureGatesConfigMapSuffix
+		_, err := r.kubeClient.CoreV1().ConfigMaps(config.AgentNamespace).Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case errors.IsNotFound(err):
+			return nil
+		case err != nil:
+			return err
+		}
+		err = r.kubeClient.CoreV1().ConfigMaps(config.AgentNamespace).Delete(ctx, name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		r.recorder.Eventf("FeatureGatesConfigMapDeleted", "configmap %s/%s is deleted", config.AgentNamespace, name)
+		return nil
+	}
+
+	_, _, err := resourceapply.ApplyConfigMap(ctx, r.kubeClient.CoreV1(), r.recorder, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.FeatureGatesConfigMap,
+			Namespace: config.AgentNamespace,
+			Labels:    map[string]string{"createdBy": "klusterlet"},
+		},
+		Data: map[string]string{
+			features.FeatureGatesConfigMapRegistrationKey: featureGatesFromFlags(config.RegistrationFeatureGates),
+			features.FeatureGatesConfigMapWorkKey:         featureGatesFromFlags(config.WorkFeatureGates),
+		},
+	})
+	return err
+}
+
+// featureGatesFromFlags returns the feature gates of the --feature-gates flags in the format of A=true,B=false.
+func featureGatesFromFlags(flags []string) string {
+	var featureGates []string
+	for _, flag := range flags {
+		featureGates = append(featureGates, strings.TrimPrefix(flag, "--feature-gates="))
+	}
+	sort.Strings(featureGates)
+	return strings.Join(featureGates, ",")
+}
+
 func (r *managementReconcile) clean(ctx context.Context, klusterlet *operatorapiv1.Klusterlet,
 	config klusterletConfig) (*operatorapiv1.Klusterlet, reconcileState, error) {
 	// Remove secrets
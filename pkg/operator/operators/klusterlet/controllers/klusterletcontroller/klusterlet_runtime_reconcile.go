@@ -12,6 +12,7 @@ import (
 	"github.com/openshift/library-go/pkg/assets"
 	"github.com/openshift/library-go/pkg/operator/events"
 	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -68,7 +69,7 @@ func (r *runtimeReconcile) installAgent(ctx context.Context, klusterlet *operato
 		ctx,
 		r.kubeClient,
 		klusterlet.Status.Generations,
-		klusterlet.Spec.NodePlacement,
+		agentNodePlacement(klusterlet.Spec.NodePlacement),
 		func(name string) ([]byte, error) {
 			template, err := manifests.KlusterletManifestFiles.ReadFile(name)
 			if err != nil {
@@ -119,7 +120,7 @@ func (r *runtimeReconcile) installAgent(ctx context.Context, klusterlet *operato
 		ctx,
 		r.kubeClient,
 		klusterlet.Status.Generations,
-		klusterlet.Spec.NodePlacement,
+		agentNodePlacement(klusterlet.Spec.NodePlacement),
 		func(name string) ([]byte, error) {
 			template, err := manifests.KlusterletManifestFiles.ReadFile(name)
 			if err != nil {
@@ -169,7 +170,7 @@ func (r *runtimeReconcile) installSingletonAgent(ctx context.Context, klusterlet
 		ctx,
 		r.kubeClient,
 		klusterlet.Status.Generations,
-		klusterlet.Spec.NodePlacement,
+		agentNodePlacement(klusterlet.Spec.NodePlacement),
 		func(name string) ([]byte, error) {
 			template, err := manifests.KlusterletManifestFiles.ReadFile(name)
 			if err != nil {
@@ -260,3 +261,35 @@ func (r *runtimeReconcile) clean(ctx context.Context, klusterlet *operatorapiv1.
 
 	return klusterlet, reconcileContinue, nil
 }
+
+// agentTolerations are added to the tolerations of the node placement, so the agents are still able to be
+// scheduled onto the nodes under memory pressure and the nodes dedicated to the critical addons.
+var agentTolerations = []corev1.Toleration{
+	{
+		Key:      "CriticalAddonsOnly",
+		Operator: corev1.TolerationOpExists,
+	},
+	{
+		Key:      corev1.TaintNodeMemoryPressure,
+		Operator: corev1.TolerationOpExists,
+		Effect:   corev1.TaintEffectNoSchedule,
+	},
+}
+
+// agentNodePlacement returns the node placement of the agents with the agent tolerations.
+func agentNodePlacement(nodePlacement operatorapiv1.NodePlacement) operatorapiv1.NodePlacement {
+	placement := *nodePlacement.DeepCopy()
+	for _, toleration := range agentTolerations {
+		found := false
+		for _, existing := range placement.Tolerations {
+			if existing.Key == toleration.Key && existing.Effect == toleration.Effect {
+				found = true
+				break
+			}
+		}
+		if !found {
+			placement.Tolerations = append(placement.Tolerations, toleration)
+		}
+	}
+	return placement
+}
@@ -22,6 +22,7 @@ import (
 	operatorlister "open-cluster-management.io/api/client/operator/listers/operator/v1"
 	operatorapiv1 "open-cluster-management.io/api/operator/v1"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/operator/helpers"
@@ -65,7 +66,7 @@ func NewKlusterletSSARController(
 		},
 	}
 
-	return factory.New().WithSync(controller.sync).
+	return factory.New().WithSync(logging.WithControllerLogger("KlusterletSSARController", controller.sync)).
 		WithInformersQueueKeysFunc(helpers.KlusterletSecretQueueKeyFunc(controller.klusterletLister),
 			secretInformers[helpers.HubKubeConfig].Informer(),
 			secretInformers[helpers.BootstrapHubKubeConfig].Informer(),
@@ -106,10 +107,11 @@ func (c *ssarController) sync(ctx context.Context, controllerContext factory.Syn
 		return err
 	}
 	klusterlet = klusterlet.DeepCopy()
+	logger := klog.FromContext(ctx)
 
 	// if the ssar checking is already processing, requeue it after 30s.
 	if c.inSSARChecking(klusterletName) {
-		klog.V(4).Infof("Reconciling Klusterlet %q is already processing now", klusterletName)
+		logger.V(4).Info("Reconciling Klusterlet is already processing now", "klusterlet", klusterletName)
 		controllerContext.Queue().AddAfter(klusterletName, SSARReSyncTime)
 		return nil
 	}
@@ -120,7 +122,7 @@ func (c *ssarController) sync(ctx context.Context, controllerContext factory.Syn
 
 		newKlusterlet := klusterlet.DeepCopy()
 
-		klog.V(4).Infof("Checking hub kubeconfig for klusterlet %q", klusterletName)
+		logger.V(4).Info("Checking hub kubeconfig for klusterlet", "klusterlet", klusterletName)
 		agentNamespace := helpers.AgentNamespace(klusterlet)
 
 		hubConfigDegradedCondition := checkAgentDegradedCondition(
@@ -140,7 +142,7 @@ func (c *ssarController) sync(ctx context.Context, controllerContext factory.Syn
 			meta.SetStatusCondition(&newKlusterlet.Status.Conditions, hubConfigDegradedCondition)
 			_, err := c.patcher.PatchStatus(ctx, newKlusterlet, newKlusterlet.Status, klusterlet.Status)
 			if err != nil {
-				klog.Errorf("Update Klusterlet Status Failed: %v", err)
+				logger.Error(err, "Update Klusterlet Status Failed", "klusterlet", klusterletName)
 				controllerContext.Queue().AddAfter(klusterletName, SSARReSyncTime)
 			}
 
@@ -169,7 +171,7 @@ func (c *ssarController) sync(ctx context.Context, controllerContext factory.Syn
 		})
 		_, err := c.patcher.PatchStatus(ctx, newKlusterlet, newKlusterlet.Status, klusterlet.Status)
 		if err != nil {
-			klog.Errorf("Update Klusterlet Status Failed: %v", err)
+			logger.Error(err, "Update Klusterlet Status Failed", "klusterlet", klusterletName)
 			controllerContext.Queue().AddAfter(klusterletName, SSARReSyncTime)
 		}
 	}(klusterlet)
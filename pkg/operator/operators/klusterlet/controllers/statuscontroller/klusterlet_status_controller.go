@@ -10,26 +10,37 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	appsinformer "k8s.io/client-go/informers/apps/v1"
+	coreinformer "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	appslister "k8s.io/client-go/listers/apps/v1"
+	corelister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/component-base/featuregate"
 	"k8s.io/klog/v2"
 
 	operatorv1client "open-cluster-management.io/api/client/operator/clientset/versioned/typed/operator/v1"
 	operatorinformer "open-cluster-management.io/api/client/operator/informers/externalversions/operator/v1"
 	operatorlister "open-cluster-management.io/api/client/operator/listers/operator/v1"
+	ocmfeature "open-cluster-management.io/api/feature"
 	operatorapiv1 "open-cluster-management.io/api/operator/v1"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
+	"open-cluster-management.io/ocm/pkg/features"
 	"open-cluster-management.io/ocm/pkg/operator/helpers"
 )
 
 type klusterletStatusController struct {
 	kubeClient       kubernetes.Interface
 	deploymentLister appslister.DeploymentLister
+	configMapLister  corelister.ConfigMapLister
 	patcher          patcher.Patcher[*operatorapiv1.Klusterlet, operatorapiv1.KlusterletSpec, operatorapiv1.KlusterletStatus]
 	klusterletLister operatorlister.KlusterletLister
+	// prober probes the pods of the agents if it is set, so an agent which is running but not healthy is reported
+	// as degraded.
+	prober helpers.HealthProber
 }
 
 const (
@@ -37,26 +48,41 @@ const (
 	klusterletWorkDesiredDegraded         = "WorkDesiredDegraded"
 	klusterletAvailable                   = "Available"
 	klusterletApplied                     = "Applied"
+	klusterletFeatureGatesActive          = "FeatureGatesActive"
 )
 
+// agentFeatureGates are the feature gates of the agents in each key of the feature gates configmap.
+var agentFeatureGates = map[string]map[featuregate.Feature]featuregate.FeatureSpec{
+	features.FeatureGatesConfigMapRegistrationKey: ocmfeature.DefaultSpokeRegistrationFeatureGates,
+	features.FeatureGatesConfigMapWorkKey:         ocmfeature.DefaultSpokeWorkFeatureGates,
+}
+
 // NewKlusterletStatusController returns a klusterletStatusController
 func NewKlusterletStatusController(
 	kubeClient kubernetes.Interface,
 	klusterletClient operatorv1client.KlusterletInterface,
 	klusterletInformer operatorinformer.KlusterletInformer,
 	deploymentInformer appsinformer.DeploymentInformer,
+	configMapInformer coreinformer.ConfigMapInformer,
+	prober helpers.HealthProber,
 	recorder events.Recorder) factory.Controller {
 	controller := &klusterletStatusController{
 		kubeClient: kubeClient,
+		prober:     prober,
 		patcher: patcher.NewPatcher[
 			*operatorapiv1.Klusterlet, operatorapiv1.KlusterletSpec, operatorapiv1.KlusterletStatus](klusterletClient),
 		deploymentLister: deploymentInformer.Lister(),
+		configMapLister:  configMapInformer.Lister(),
 		klusterletLister: klusterletInformer.Lister(),
 	}
-	return factory.New().WithSync(controller.sync).
+	controllerFactory := factory.New().WithSync(logging.WithControllerLogger("KlusterletStatusController", controller.sync)).
 		WithInformersQueueKeysFunc(helpers.KlusterletDeploymentQueueKeyFunc(controller.klusterletLister), deploymentInformer.Informer()).
-		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, klusterletInformer.Informer()).
-		ToController("KlusterletStatusController", recorder)
+		WithInformersQueueKeysFunc(featureGatesConfigMapQueueKey, configMapInformer.Informer()).
+		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, klusterletInformer.Informer())
+	if prober != nil {
+		controllerFactory = controllerFactory.ResyncEvery(helpers.HealthProbeResyncInterval)
+	}
+	return controllerFactory.ToController("KlusterletStatusController", recorder)
 }
 
 func (k *klusterletStatusController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
@@ -64,7 +90,8 @@ func (k *klusterletStatusController) sync(ctx context.Context, controllerContext
 	if klusterletName == "" {
 		return nil
 	}
-	klog.V(4).Infof("Reconciling Klusterlet %q", klusterletName)
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling Klusterlet", "klusterlet", klusterletName)
 
 	klusterlet, err := k.klusterletLister.Get(klusterletName)
 	switch {
@@ -107,25 +134,93 @@ func (k *klusterletStatusController) sync(ctx context.Context, controllerContext
 	meta.SetStatusCondition(&newKlusterlet.Status.Conditions, availableCondition)
 
 	registrationDesiredCondition := checkAgentDeploymentDesired(ctx,
-		k.kubeClient, agentNamespace, registrationDeploymentName, klusterletRegistrationDesiredDegraded)
+		k.kubeClient, k.prober, agentNamespace, registrationDeploymentName, klusterletRegistrationDesiredDegraded)
 	registrationDesiredCondition.ObservedGeneration = klusterlet.Generation
 	meta.SetStatusCondition(&newKlusterlet.Status.Conditions, registrationDesiredCondition)
 
-	workDesiredCondition := checkAgentDeploymentDesired(ctx, k.kubeClient, agentNamespace, workDeploymentName, klusterletWorkDesiredDegraded)
+	workDesiredCondition := checkAgentDeploymentDesired(ctx,
+		k.kubeClient, k.prober, agentNamespace, workDeploymentName, klusterletWorkDesiredDegraded)
 	workDesiredCondition.ObservedGeneration = klusterlet.Generation
 	meta.SetStatusCondition(&newKlusterlet.Status.Conditions, workDesiredCondition)
 
+	featureGatesCondition, err := k.checkFeatureGatesActive(klusterlet, agentNamespace)
+	if err != nil {
+		return err
+	}
+	if featureGatesCondition == nil {
+		meta.RemoveStatusCondition(&newKlusterlet.Status.Conditions, klusterletFeatureGatesActive)
+	} else {
+		featureGatesCondition.ObservedGeneration = klusterlet.Generation
+		meta.SetStatusCondition(&newKlusterlet.Status.Conditions, *featureGatesCondition)
+	}
+
 	_, err = k.patcher.PatchStatus(ctx, newKlusterlet, newKlusterlet.Status, klusterlet.Status)
 	return err
 }
 
+// checkFeatureGatesActive compares the feature gates in the feature gates configmap with the ones the agents report
+// active on the configmap, it returns nil if the feature gates are not delivered with the configmap.
+func (k *klusterletStatusController) checkFeatureGatesActive(
+	klusterlet *operatorapiv1.Klusterlet, agentNamespace string) (*metav1.Condition, error) {
+	configMap, err := k.configMapLister.ConfigMaps(agentNamespace).Get(klusterlet.Name + helpers.FeatureGatesConfigMapSuffix)
+	switch {
+	case errors.IsNotFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	var messages []string
+	for _, key := range []string{features.FeatureGatesConfigMapRegistrationKey, features.FeatureGatesConfigMapWorkKey} {
+		enabled, err := features.ParseFeatureGates(configMap.Data[key], agentFeatureGates[key])
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("%s: %v", key, err))
+			continue
+		}
+		desired := features.FormatFeatureGates(enabled, agentFeatureGates[key])
+		active, ok := configMap.Annotations[features.ActiveFeatureGatesAnnotationPrefix+key]
+		switch {
+		case !ok:
+			messages = append(messages, fmt.Sprintf("%s: the feature gates are not loaded", key))
+		case active != desired:
+			messages = append(messages, fmt.Sprintf("%s: the active feature gates %q are not %q", key, active, desired))
+		}
+	}
+
+	if len(messages) > 0 {
+		return &metav1.Condition{
+			Type:    klusterletFeatureGatesActive,
+			Status:  metav1.ConditionFalse,
+			Reason:  "FeatureGatesNotActive",
+			Message: strings.Join(messages, "; "),
+		}, nil
+	}
+	return &metav1.Condition{
+		Type:    klusterletFeatureGatesActive,
+		Status:  metav1.ConditionTrue,
+		Reason:  "FeatureGatesActive",
+		Message: "The feature gates are active in the agents",
+	}, nil
+}
+
+// featureGatesConfigMapQueueKey returns the name of the klusterlet of a feature gates configmap.
+func featureGatesConfigMapQueueKey(obj runtime.Object) []string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil || !strings.HasSuffix(accessor.GetName(), helpers.FeatureGatesConfigMapSuffix) {
+		return []string{}
+	}
+	return []string{strings.TrimSuffix(accessor.GetName(), helpers.FeatureGatesConfigMapSuffix)}
+}
+
 type klusterletAgent struct {
 	deploymentName string
 	namespace      string
 }
 
-// Check agent deployment, if the desired replicas is not equal to available replicas, return degraded condition
-func checkAgentDeploymentDesired(ctx context.Context, kubeClient kubernetes.Interface, namespace, deploymentName, conditionType string) metav1.Condition {
+// Check agent deployment, if the desired replicas is not equal to available replicas, or any of the running pods is
+// not healthy when the prober is set, return degraded condition
+func checkAgentDeploymentDesired(ctx context.Context, kubeClient kubernetes.Interface, prober helpers.HealthProber,
+	namespace, deploymentName, conditionType string) metav1.Condition {
 	deployment, err := kubeClient.AppsV1().Deployments(namespace).Get(ctx, deploymentName, metav1.GetOptions{})
 	if err != nil {
 		return metav1.Condition{
@@ -144,6 +239,22 @@ func checkAgentDeploymentDesired(ctx context.Context, kubeClient kubernetes.Inte
 				unavailablePod, namespace, deploymentName),
 		}
 	}
+	if prober != nil {
+		unhealthyPod, message, err := helpers.ProbeDeploymentPods(ctx, kubeClient, prober, deployment)
+		switch {
+		case err != nil:
+			// the replicas of the deployment are still checked, do not report degraded if the pods are not probed.
+			klog.FromContext(ctx).Error(err, "Failed to probe the pods", "deployment", klog.KObj(deployment))
+		case unhealthyPod > 0:
+			return metav1.Condition{
+				Type:   conditionType,
+				Status: metav1.ConditionTrue,
+				Reason: "UnhealthyPods",
+				Message: fmt.Sprintf("%v of running instances are unhealthy of deployment %q %q: %s",
+					unhealthyPod, namespace, deploymentName, message),
+			}
+		}
+	}
 	return metav1.Condition{
 		Type:    conditionType,
 		Status:  metav1.ConditionFalse,
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -20,6 +21,8 @@ import (
 
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/features"
+	"open-cluster-management.io/ocm/pkg/operator/helpers"
 	testinghelper "open-cluster-management.io/ocm/pkg/operator/helpers/testing"
 )
 
@@ -66,6 +69,20 @@ func newDeployment(name, namespace string, desiredReplica, availableReplica int3
 	}
 }
 
+func newFeatureGatesConfigMap(namespace, registration, work string, annotations map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "testklusterlet" + helpers.FeatureGatesConfigMapSuffix,
+			Namespace:   namespace,
+			Annotations: annotations,
+		},
+		Data: map[string]string{
+			features.FeatureGatesConfigMapRegistrationKey: registration,
+			features.FeatureGatesConfigMapWorkKey:         work,
+		},
+	}
+}
+
 func newTestController(t *testing.T, klusterlet *operatorapiv1.Klusterlet, objects ...runtime.Object) *testController {
 	fakeKubeClient := fakekube.NewSimpleClientset(objects...)
 	fakeOperatorClient := fakeoperatorclient.NewSimpleClientset(klusterlet)
@@ -77,6 +94,7 @@ func newTestController(t *testing.T, klusterlet *operatorapiv1.Klusterlet, objec
 		patcher: patcher.NewPatcher[
 			*operatorapiv1.Klusterlet, operatorapiv1.KlusterletSpec, operatorapiv1.KlusterletStatus](fakeOperatorClient.OperatorV1().Klusterlets()),
 		deploymentLister: kubeInformers.Apps().V1().Deployments().Lister(),
+		configMapLister:  kubeInformers.Core().V1().ConfigMaps().Lister(),
 		klusterletLister: operatorInformers.Operator().V1().Klusterlets().Lister(),
 	}
 
@@ -84,6 +102,13 @@ func newTestController(t *testing.T, klusterlet *operatorapiv1.Klusterlet, objec
 	if err := store.Add(klusterlet); err != nil {
 		t.Fatal(err)
 	}
+	for _, obj := range objects {
+		if configMap, ok := obj.(*corev1.ConfigMap); ok {
+			if err := kubeInformers.Core().V1().ConfigMaps().Informer().GetStore().Add(configMap); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
 
 	return &testController{
 		controller:     klusterletController,
@@ -181,6 +206,41 @@ func TestSync(t *testing.T) {
 				testinghelper.NamedCondition(klusterletWorkDesiredDegraded, "DeploymentsFunctional", metav1.ConditionFalse),
 			},
 		},
+		{
+			name: "Feature gates are active",
+			object: []runtime.Object{
+				newDeployment("testklusterlet-registration-agent", "test", 3, 3),
+				newDeployment("testklusterlet-work-agent", "test", 3, 3),
+				newFeatureGatesConfigMap("test", "AddonManagement=false", "RawFeedbackJsonString=true", map[string]string{
+					features.ActiveFeatureGatesAnnotationPrefix + features.FeatureGatesConfigMapRegistrationKey: "AddonManagement=false",
+					features.ActiveFeatureGatesAnnotationPrefix + features.FeatureGatesConfigMapWorkKey:         "RawFeedbackJsonString=true",
+				}),
+			},
+			klusterlet: newKlusterlet("testklusterlet", "test", "cluster1"),
+			expectedConditions: []metav1.Condition{
+				testinghelper.NamedCondition(klusterletAvailable, "klusterletAvailable", metav1.ConditionTrue),
+				testinghelper.NamedCondition(klusterletRegistrationDesiredDegraded, "DeploymentsFunctional", metav1.ConditionFalse),
+				testinghelper.NamedCondition(klusterletWorkDesiredDegraded, "DeploymentsFunctional", metav1.ConditionFalse),
+				testinghelper.NamedCondition(klusterletFeatureGatesActive, "FeatureGatesActive", metav1.ConditionTrue),
+			},
+		},
+		{
+			name: "Feature gates are not active",
+			object: []runtime.Object{
+				newDeployment("testklusterlet-registration-agent", "test", 3, 3),
+				newDeployment("testklusterlet-work-agent", "test", 3, 3),
+				newFeatureGatesConfigMap("test", "AddonManagement=false", "RawFeedbackJsonString=true", map[string]string{
+					features.ActiveFeatureGatesAnnotationPrefix + features.FeatureGatesConfigMapRegistrationKey: "",
+				}),
+			},
+			klusterlet: newKlusterlet("testklusterlet", "test", "cluster1"),
+			expectedConditions: []metav1.Condition{
+				testinghelper.NamedCondition(klusterletAvailable, "klusterletAvailable", metav1.ConditionTrue),
+				testinghelper.NamedCondition(klusterletRegistrationDesiredDegraded, "DeploymentsFunctional", metav1.ConditionFalse),
+				testinghelper.NamedCondition(klusterletWorkDesiredDegraded, "DeploymentsFunctional", metav1.ConditionFalse),
+				testinghelper.NamedCondition(klusterletFeatureGatesActive, "FeatureGatesNotActive", metav1.ConditionFalse),
+			},
+		},
 	}
 
 	for _, c := range cases {
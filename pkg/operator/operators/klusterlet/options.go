@@ -31,6 +31,9 @@ const defaultComponentNamespace = "open-cluster-management"
 
 type Options struct {
 	SkipPlaceholderHubSecret bool
+	// EnableHealthProbe probes the /healthz endpoint of the pods of the agents, so an agent which is running but not
+	// healthy is reported as degraded.
+	EnableHealthProbe bool
 }
 
 // RunKlusterletOperator starts a new klusterlet operator
@@ -67,11 +70,13 @@ func (o *Options) RunKlusterletOperator(ctx context.Context, controllerContext *
 	hubConfigSecretInformer := newOneTermInformer(helpers.HubKubeConfig)
 	bootstrapConfigSecretInformer := newOneTermInformer(helpers.BootstrapHubKubeConfig)
 	externalConfigSecretInformer := newOneTermInformer(helpers.WorkWebhookSecret)
+	hubReconnectionSecretInformer := newOneTermInformer(helpers.HubReconnection)
 
 	secretInformers := map[string]corev1informers.SecretInformer{
 		helpers.HubKubeConfig:             hubConfigSecretInformer.Core().V1().Secrets(),
 		helpers.BootstrapHubKubeConfig:    bootstrapConfigSecretInformer.Core().V1().Secrets(),
 		helpers.ExternalManagedKubeConfig: externalConfigSecretInformer.Core().V1().Secrets(),
+		helpers.HubReconnection:           hubReconnectionSecretInformer.Core().V1().Secrets(),
 	}
 
 	deploymentInformer := informers.NewSharedInformerFactoryWithOptions(kubeClient, 5*time.Minute,
@@ -131,11 +136,17 @@ func (o *Options) RunKlusterletOperator(ctx context.Context, controllerContext *
 		controllerContext.EventRecorder,
 	)
 
+	var prober helpers.HealthProber
+	if o.EnableHealthProbe {
+		prober = helpers.NewHTTPHealthProber(helpers.ComponentHealthPort, helpers.HealthProbeTimeout)
+	}
 	statusController := statuscontroller.NewKlusterletStatusController(
 		kubeClient,
 		operatorClient.OperatorV1().Klusterlets(),
 		operatorInformer.Operator().V1().Klusterlets(),
 		deploymentInformer.Apps().V1().Deployments(),
+		deploymentInformer.Core().V1().ConfigMaps(),
+		prober,
 		controllerContext.EventRecorder,
 	)
 
@@ -159,6 +170,7 @@ func (o *Options) RunKlusterletOperator(ctx context.Context, controllerContext *
 	go hubConfigSecretInformer.Start(ctx.Done())
 	go bootstrapConfigSecretInformer.Start(ctx.Done())
 	go externalConfigSecretInformer.Start(ctx.Done())
+	go hubReconnectionSecretInformer.Start(ctx.Done())
 	go deploymentInformer.Start(ctx.Done())
 	go klusterletController.Run(ctx, 1)
 	go klusterletCleanupController.Run(ctx, 1)
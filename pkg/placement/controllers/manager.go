@@ -2,24 +2,66 @@ package hub
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
+	"github.com/spf13/pflag"
 	"k8s.io/apiserver/pkg/server/mux"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/events"
 
+	addonclient "open-cluster-management.io/api/client/addon/clientset/versioned"
+	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
 	clusterclient "open-cluster-management.io/api/client/cluster/clientset/versioned"
-	clusterscheme "open-cluster-management.io/api/client/cluster/clientset/versioned/scheme"
 	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
 
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/placement/controllers/metrics"
 	"open-cluster-management.io/ocm/pkg/placement/controllers/scheduling"
 	"open-cluster-management.io/ocm/pkg/placement/debugger"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/extender"
 )
 
-// RunControllerManager starts the controllers on hub to make placement decisions.
+// PlacementManagerOptions holds configuration for the placement controller manager
+type PlacementManagerOptions struct {
+	ExtenderOptions     *extender.Options
+	ScoreDebounceWindow time.Duration
+	ShardCount          int
+	ProfilesFile        string
+}
+
+// NewPlacementManagerOptions returns a PlacementManagerOptions
+func NewPlacementManagerOptions() *PlacementManagerOptions {
+	return &PlacementManagerOptions{
+		ExtenderOptions: extender.NewOptions(),
+		ShardCount:      1,
+	}
+}
+
+// AddFlags registers flags for manager
+func (o *PlacementManagerOptions) AddFlags(fs *pflag.FlagSet) {
+	o.ExtenderOptions.AddFlags(fs)
+	fs.DurationVar(&o.ScoreDebounceWindow, "score-debounce-window", o.ScoreDebounceWindow,
+		"The window to merge the changes of the AddOnPlacementScores into one rescheduling of the placements using them. "+
+			"The placements are rescheduled immediately on each change if it is 0.")
+	fs.IntVar(&o.ShardCount, "shard-count", o.ShardCount,
+		"The number of shards the placements are split into. The placements are assigned to the shards by the hash of "+
+			"their namespaces, or by the label "+scheduling.PlacementShardLabel+". Each replica schedules the shard whose "+
+			"lease placement-shard-<index> it holds, so there should be at least as many replicas as shards. The global "+
+			"leader election is disabled when it is greater than 1.")
+	fs.StringVar(&o.ProfilesFile, "scheduler-profiles", o.ProfilesFile,
+		"The yaml file of the scheduler profiles, each of which is a named set of the filters and the default prioritizer weights. "+
+			"A placement selects a profile with the annotation "+scheduling.SchedulerProfileAnnotationKey+".")
+}
+
+// RunControllerManager starts the controllers on hub to make placement decisions with the default options.
 func RunControllerManager(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
+	return NewPlacementManagerOptions().RunControllerManager(ctx, controllerContext)
+}
+
+// RunControllerManager starts the controllers on hub to make placement decisions.
+func (o *PlacementManagerOptions) RunControllerManager(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
 	clusterClient, err := clusterclient.NewForConfig(controllerContext.KubeConfig)
 	if err != nil {
 		return err
@@ -30,23 +72,51 @@ func RunControllerManager(ctx context.Context, controllerContext *controllercmd.
 		return err
 	}
 
+	addOnClient, err := addonclient.NewForConfig(controllerContext.KubeConfig)
+	if err != nil {
+		return err
+	}
+
 	clusterInformers := clusterinformers.NewSharedInformerFactory(clusterClient, 10*time.Minute)
+	addOnInformers := addoninformers.NewSharedInformerFactory(addOnClient, 10*time.Minute)
 
-	return RunControllerManagerWithInformers(ctx, controllerContext, kubeClient, clusterClient, clusterInformers)
+	return o.RunControllerManagerWithInformers(ctx, controllerContext, kubeClient, clusterClient, clusterInformers, addOnInformers)
 }
 
-func RunControllerManagerWithInformers(
+func (o *PlacementManagerOptions) RunControllerManagerWithInformers(
 	ctx context.Context,
 	controllerContext *controllercmd.ControllerContext,
 	kubeClient kubernetes.Interface,
 	clusterClient clusterclient.Interface,
 	clusterInformers clusterinformers.SharedInformerFactory,
+	addOnInformers addoninformers.SharedInformerFactory,
 ) error {
-	broadcaster := events.NewBroadcaster(&events.EventSinkImpl{Interface: kubeClient.EventsV1()})
+	if err := o.ExtenderOptions.Validate(); err != nil {
+		return err
+	}
+	if o.ScoreDebounceWindow < 0 {
+		return fmt.Errorf("score-debounce-window should not be negative")
+	}
+	if o.ShardCount < 1 {
+		return fmt.Errorf("shard-count should be at least 1")
+	}
+	schedulerExtender, err := extender.New(o.ExtenderOptions)
+	if err != nil {
+		return err
+	}
+	if schedulerExtender != nil {
+		defer schedulerExtender.Close()
+	}
 
-	broadcaster.StartRecordingToSink(ctx.Done())
+	var profiles map[string]scheduling.SchedulerProfile
+	if len(o.ProfilesFile) > 0 {
+		profiles, err = scheduling.LoadSchedulerProfiles(o.ProfilesFile)
+		if err != nil {
+			return err
+		}
+	}
 
-	recorder := broadcaster.NewRecorder(clusterscheme.Scheme, "placementController")
+	recorder := commonhelpers.NewEventRecorder(ctx, kubeClient, "placementController")
 
 	scheduler := scheduling.NewPluginScheduler(
 		scheduling.NewSchedulerHandler(
@@ -54,12 +124,18 @@ func RunControllerManagerWithInformers(
 			clusterInformers.Cluster().V1beta1().PlacementDecisions().Lister(),
 			clusterInformers.Cluster().V1alpha1().AddOnPlacementScores().Lister(),
 			clusterInformers.Cluster().V1().ManagedClusters().Lister(),
+			addOnInformers.Addon().V1alpha1().ManagedClusterAddOns().Lister(),
+			clusterInformers.Cluster().V1beta2().ManagedClusterSets().Lister(),
 			recorder),
-	)
+	).WithExtender(schedulerExtender, o.ExtenderOptions.Weight).WithProfiles(profiles)
+
+	metrics.Register()
+	resultCache := scheduling.NewScheduleResultCache()
 
 	if controllerContext.Server != nil {
 		debug := debugger.NewDebugger(
 			scheduler,
+			resultCache,
 			clusterInformers.Cluster().V1beta1().Placements(),
 			clusterInformers.Cluster().V1().ManagedClusters(),
 		)
@@ -67,6 +143,7 @@ func RunControllerManagerWithInformers(
 		installDebugger(controllerContext.Server.Handler.NonGoRestfulMux, debug)
 	}
 
+	shard := &scheduling.Shard{Count: o.ShardCount}
 	schedulingController := scheduling.NewSchedulingController(
 		ctx,
 		clusterClient,
@@ -76,17 +153,42 @@ func RunControllerManagerWithInformers(
 		clusterInformers.Cluster().V1beta1().Placements(),
 		clusterInformers.Cluster().V1beta1().PlacementDecisions(),
 		clusterInformers.Cluster().V1alpha1().AddOnPlacementScores(),
+		addOnInformers.Addon().V1alpha1().ManagedClusterAddOns(),
 		scheduler,
+		resultCache,
+		o.ScoreDebounceWindow,
+		shard,
 		controllerContext.EventRecorder, recorder,
 	)
 
-	go clusterInformers.Start(ctx.Done())
+	if err := commonhelpers.SetInformerTransform(commonhelpers.TrimManagedFieldsAndLastApplied,
+		clusterInformers.Cluster().V1().ManagedClusters().Informer(),
+		clusterInformers.Cluster().V1beta2().ManagedClusterSets().Informer(),
+		clusterInformers.Cluster().V1beta2().ManagedClusterSetBindings().Informer(),
+		clusterInformers.Cluster().V1beta1().Placements().Informer(),
+		clusterInformers.Cluster().V1beta1().PlacementDecisions().Informer(),
+		clusterInformers.Cluster().V1alpha1().AddOnPlacementScores().Informer(),
+		addOnInformers.Addon().V1alpha1().ManagedClusterAddOns().Informer(),
+	); err != nil {
+		return err
+	}
+
+	run := func(ctx context.Context) {
+		go clusterInformers.Start(ctx.Done())
+		go addOnInformers.Start(ctx.Done())
+
+		go schedulingController.Run(ctx, 1)
 
-	go schedulingController.Run(ctx, 1)
+		<-ctx.Done()
+	}
 
-	<-ctx.Done()
+	if o.ShardCount < 2 {
+		run(ctx)
+		return nil
+	}
 
-	return nil
+	// each replica schedules the shard whose lease it holds, so a shard is scheduled by only one replica at a time.
+	return scheduling.NewShardElector(kubeClient, controllerContext.OperatorNamespace, shard).Run(ctx, run)
 }
 
 func installDebugger(mux *mux.PathRecorderMux, d *debugger.Debugger) {
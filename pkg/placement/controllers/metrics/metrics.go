@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"sync"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const subsystem = "placement"
+
+var (
+	// SchedulingDuration is the duration of scheduling a placement, including filtering, scoring and
+	// selecting the clusters.
+	SchedulingDuration = metrics.NewHistogram(
+		&metrics.HistogramOpts{
+			Subsystem:      subsystem,
+			Name:           "scheduling_duration_seconds",
+			Help:           "Duration in seconds of scheduling a placement.",
+			Buckets:        metrics.ExponentialBuckets(0.001, 2, 15),
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	// PrioritizerScore is the distribution of the cluster scores given by each prioritizer.
+	PrioritizerScore = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      subsystem,
+			Name:           "prioritizer_score",
+			Help:           "Distribution of the cluster scores given by each prioritizer in the scheduling passes.",
+			Buckets:        metrics.LinearBuckets(-100, 20, 11),
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"prioritizer"},
+	)
+
+	// FilteredOutClusters is the number of clusters filtered out by each filter.
+	FilteredOutClusters = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      subsystem,
+			Name:           "filtered_out_clusters_total",
+			Help:           "Number of clusters filtered out by each filter in the scheduling passes.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"filter"},
+	)
+
+	registerOnce sync.Once
+)
+
+// Register registers the placement metrics to the legacy registry, which is served by the controller
+// on the /metrics endpoint.
+func Register() {
+	registerOnce.Do(func() {
+		legacyregistry.MustRegister(SchedulingDuration)
+		legacyregistry.MustRegister(PrioritizerScore)
+		legacyregistry.MustRegister(FilteredOutClusters)
+	})
+}
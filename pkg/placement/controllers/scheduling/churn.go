@@ -0,0 +1,67 @@
+package scheduling
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+// PlacementConditionRescheduled is the condition type of the placement reporting the last scheduling which
+// changed the decisions, including the time, the number of the added and removed clusters, and the number of
+// the removed clusters which no longer matched the placement (misplaced).
+const PlacementConditionRescheduled = "Rescheduled"
+
+// decisionChurn is the change of the decisions in a scheduling.
+type decisionChurn struct {
+	added   int
+	removed int
+	// misplaced is the number of the removed clusters which are not feasible for the placement anymore
+	misplaced int
+}
+
+func (d decisionChurn) changed() bool {
+	return d.added > 0 || d.removed > 0
+}
+
+// getDecisionChurn compares the new decisions with the existing ones.
+func getDecisionChurn(existing sets.Set[string], decisions []*clusterapiv1.ManagedCluster, result ScheduleResult) decisionChurn {
+	churn := decisionChurn{}
+	selected := sets.New[string]()
+	for _, cluster := range decisions {
+		selected.Insert(cluster.Name)
+		if !existing.Has(cluster.Name) {
+			churn.added++
+		}
+	}
+
+	// the last filter result has the clusters passing all the filters
+	feasible := sets.New[string]()
+	if filterResults := result.FilterResults(); len(filterResults) > 0 {
+		feasible.Insert(filterResults[len(filterResults)-1].FilteredClusters...)
+	}
+	for name := range existing {
+		if selected.Has(name) {
+			continue
+		}
+		churn.removed++
+		if !feasible.Has(name) {
+			churn.misplaced++
+		}
+	}
+	return churn
+}
+
+// newRescheduledCondition returns a new condition with type PlacementConditionRescheduled
+func newRescheduledCondition(churn decisionChurn, now time.Time) metav1.Condition {
+	return metav1.Condition{
+		Type:   PlacementConditionRescheduled,
+		Status: metav1.ConditionTrue,
+		Reason: "DecisionsChanged",
+		Message: fmt.Sprintf("Rescheduled at %s: %d clusters added, %d clusters removed, %d of the removed clusters are misplaced",
+			now.UTC().Format(time.RFC3339), churn.added, churn.removed, churn.misplaced),
+	}
+}
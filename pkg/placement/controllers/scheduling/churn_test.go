@@ -0,0 +1,75 @@
+package scheduling
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestGetDecisionChurn(t *testing.T) {
+	cases := []struct {
+		name          string
+		existing      sets.Set[string]
+		decisions     []string
+		feasible      []string
+		expectedChurn decisionChurn
+	}{
+		{
+			name:          "first scheduling",
+			existing:      sets.New[string](),
+			decisions:     []string{"cluster1", "cluster2"},
+			feasible:      []string{"cluster1", "cluster2", "cluster3"},
+			expectedChurn: decisionChurn{added: 2},
+		},
+		{
+			name:          "no change",
+			existing:      sets.New[string]("cluster1", "cluster2"),
+			decisions:     []string{"cluster1", "cluster2"},
+			feasible:      []string{"cluster1", "cluster2", "cluster3"},
+			expectedChurn: decisionChurn{},
+		},
+		{
+			name:          "replace misplaced cluster",
+			existing:      sets.New[string]("cluster1", "cluster2"),
+			decisions:     []string{"cluster1", "cluster3"},
+			feasible:      []string{"cluster1", "cluster3"},
+			expectedChurn: decisionChurn{added: 1, removed: 1, misplaced: 1},
+		},
+		{
+			name:          "remove feasible cluster",
+			existing:      sets.New[string]("cluster1", "cluster2"),
+			decisions:     []string{"cluster1"},
+			feasible:      []string{"cluster1", "cluster2"},
+			expectedChurn: decisionChurn{removed: 1},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var decisions []*clusterapiv1.ManagedCluster
+			for _, name := range c.decisions {
+				decisions = append(decisions, testinghelpers.NewManagedCluster(name).Build())
+			}
+			result := &scheduleResult{
+				filteredRecords: map[string][]*clusterapiv1.ManagedCluster{},
+			}
+			var feasible []*clusterapiv1.ManagedCluster
+			for _, name := range c.feasible {
+				feasible = append(feasible, testinghelpers.NewManagedCluster(name).Build())
+			}
+			result.filteredRecords["Predicate"] = feasible
+
+			churn := getDecisionChurn(c.existing, decisions, result)
+			if churn != c.expectedChurn {
+				t.Errorf("expected churn %+v, but got %+v", c.expectedChurn, churn)
+			}
+			if churn.changed() != (c.expectedChurn != decisionChurn{}) {
+				t.Errorf("unexpected changed %v", churn.changed())
+			}
+		})
+	}
+}
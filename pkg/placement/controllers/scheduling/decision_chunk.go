@@ -0,0 +1,141 @@
+package scheduling
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+
+	clusterlisterv1beta1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+)
+
+// DecisionChunkSizeAnnotationKey is the annotation on the placement to set the max number of clusters in each
+// PlacementDecision, it should be in the range [1, 100] and the default is 100.
+//
+// The clusters of a decision group are split into PlacementDecisions in a stable way: a cluster stays in the
+// PlacementDecision it was in as long as it is still in the same decision group and the PlacementDecision
+// still exists, and the new clusters fill the free slots in the order of their names. The clusters in each
+// PlacementDecision are sorted by name.
+const DecisionChunkSizeAnnotationKey = "cluster.open-cluster-management.io/decision-chunk-size"
+
+// decisionChunk is the position of a cluster in the existing PlacementDecisions of a placement.
+type decisionChunk struct {
+	// decisionGroupIndex is the index of the decision group of the PlacementDecision
+	decisionGroupIndex int
+	// index is the index of the PlacementDecision in the decision group
+	index int
+}
+
+// getDecisionChunkSize returns the max number of clusters in each PlacementDecision of the placement.
+func getDecisionChunkSize(placement *clusterapiv1beta1.Placement) (int, *framework.Status) {
+	value, ok := placement.GetAnnotations()[DecisionChunkSizeAnnotationKey]
+	if !ok {
+		return maxNumOfClusterDecisions, framework.NewStatus("", framework.Success, "")
+	}
+
+	size, err := strconv.Atoi(value)
+	if err != nil || size < 1 || size > maxNumOfClusterDecisions {
+		return 0, framework.NewStatus("", framework.Misconfigured,
+			fmt.Sprintf("invalid value %q of annotation %s, it should be an integer in the range [1, %d]",
+				value, DecisionChunkSizeAnnotationKey, maxNumOfClusterDecisions))
+	}
+	return size, framework.NewStatus("", framework.Success, "")
+}
+
+// getExistingDecisionChunks returns the position of each cluster in the existing PlacementDecisions.
+func getExistingDecisionChunks(
+	decisionLister clusterlisterv1beta1.PlacementDecisionLister,
+	placement *clusterapiv1beta1.Placement) (map[string]decisionChunk, error) {
+	requirement, err := labels.NewRequirement(clusterapiv1beta1.PlacementLabel, selection.Equals, []string{placement.Name})
+	if err != nil {
+		return nil, err
+	}
+	decisions, err := decisionLister.PlacementDecisions(placement.Namespace).List(labels.NewSelector().Add(*requirement))
+	if err != nil {
+		return nil, err
+	}
+
+	// order the PlacementDecisions of each decision group by the index in their names
+	groups := map[int][]*clusterapiv1beta1.PlacementDecision{}
+	for _, decision := range decisions {
+		groupIndex, err := strconv.Atoi(decision.Labels[clusterapiv1beta1.DecisionGroupIndexLabel])
+		if err != nil {
+			continue
+		}
+		groups[groupIndex] = append(groups[groupIndex], decision)
+	}
+
+	chunks := map[string]decisionChunk{}
+	for groupIndex, group := range groups {
+		sort.SliceStable(group, func(i, j int) bool {
+			return decisionNameIndex(group[i].Name) < decisionNameIndex(group[j].Name)
+		})
+		for index, decision := range group {
+			for _, d := range decision.Status.Decisions {
+				chunks[d.ClusterName] = decisionChunk{decisionGroupIndex: groupIndex, index: index}
+			}
+		}
+	}
+	return chunks, nil
+}
+
+// decisionNameIndex returns the index in the name of the PlacementDecision, which is <placement>-decision-<index>.
+func decisionNameIndex(name string) int {
+	index, err := strconv.Atoi(name[strings.LastIndex(name, "-")+1:])
+	if err != nil {
+		return -1
+	}
+	return index
+}
+
+// chunkDecisions splits the cluster decisions of a decision group into slices with at most chunkSize clusters.
+// The clusters are kept in the slice with the same index as their existing PlacementDecision if it has room,
+// and the rest of the clusters fill the free slots in the order of their names.
+func chunkDecisions(
+	decisions []clusterapiv1beta1.ClusterDecision,
+	decisionGroupIndex, chunkSize int,
+	existing map[string]decisionChunk,
+) [][]clusterapiv1beta1.ClusterDecision {
+	if len(decisions) == 0 {
+		return nil
+	}
+
+	sorted := make([]clusterapiv1beta1.ClusterDecision, len(decisions))
+	copy(sorted, decisions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ClusterName < sorted[j].ClusterName
+	})
+
+	slices := make([][]clusterapiv1beta1.ClusterDecision, (len(sorted)+chunkSize-1)/chunkSize)
+	var rest []clusterapiv1beta1.ClusterDecision
+	for _, decision := range sorted {
+		chunk, ok := existing[decision.ClusterName]
+		if ok && chunk.decisionGroupIndex == decisionGroupIndex && chunk.index < len(slices) &&
+			len(slices[chunk.index]) < chunkSize {
+			slices[chunk.index] = append(slices[chunk.index], decision)
+			continue
+		}
+		rest = append(rest, decision)
+	}
+
+	index := 0
+	for _, decision := range rest {
+		for len(slices[index]) >= chunkSize {
+			index++
+		}
+		slices[index] = append(slices[index], decision)
+	}
+
+	for _, slice := range slices {
+		sort.SliceStable(slice, func(i, j int) bool {
+			return slice[i].ClusterName < slice[j].ClusterName
+		})
+	}
+	return slices
+}
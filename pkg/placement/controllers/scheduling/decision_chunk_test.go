@@ -0,0 +1,167 @@
+package scheduling
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestGetDecisionChunkSize(t *testing.T) {
+	cases := []struct {
+		name         string
+		annotations  map[string]string
+		expectedSize int
+		expectedCode framework.Code
+	}{
+		{
+			name:         "default size",
+			expectedSize: maxNumOfClusterDecisions,
+			expectedCode: framework.Success,
+		},
+		{
+			name:         "custom size",
+			annotations:  map[string]string{DecisionChunkSizeAnnotationKey: "10"},
+			expectedSize: 10,
+			expectedCode: framework.Success,
+		},
+		{
+			name:         "exceed the max size",
+			annotations:  map[string]string{DecisionChunkSizeAnnotationKey: "101"},
+			expectedCode: framework.Misconfigured,
+		},
+		{
+			name:         "invalid size",
+			annotations:  map[string]string{DecisionChunkSizeAnnotationKey: "ten"},
+			expectedCode: framework.Misconfigured,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			placement := testinghelpers.NewPlacementWithAnnotations("test", "test", c.annotations).Build()
+			size, status := getDecisionChunkSize(placement)
+			if status.Code() != c.expectedCode {
+				t.Errorf("expected status code %v, but got %v", c.expectedCode, status)
+			}
+			if size != c.expectedSize {
+				t.Errorf("expected size %d, but got %d", c.expectedSize, size)
+			}
+		})
+	}
+}
+
+func TestGetExistingDecisionChunks(t *testing.T) {
+	placement := testinghelpers.NewPlacement(placementNamespace, placementName).Build()
+	initObjs := []runtime.Object{
+		testinghelpers.NewPlacementDecision(placementNamespace, testinghelpers.PlacementDecisionName(placementName, 10)).
+			WithLabel(clusterapiv1beta1.PlacementLabel, placementName).
+			WithLabel(clusterapiv1beta1.DecisionGroupIndexLabel, "0").
+			WithDecisions("cluster3").Build(),
+		testinghelpers.NewPlacementDecision(placementNamespace, testinghelpers.PlacementDecisionName(placementName, 2)).
+			WithLabel(clusterapiv1beta1.PlacementLabel, placementName).
+			WithLabel(clusterapiv1beta1.DecisionGroupIndexLabel, "0").
+			WithDecisions("cluster1", "cluster2").Build(),
+		testinghelpers.NewPlacementDecision(placementNamespace, testinghelpers.PlacementDecisionName(placementName, 11)).
+			WithLabel(clusterapiv1beta1.PlacementLabel, placementName).
+			WithLabel(clusterapiv1beta1.DecisionGroupIndexLabel, "1").
+			WithDecisions("cluster4").Build(),
+	}
+	clusterClient := clusterfake.NewSimpleClientset(initObjs...)
+	clusterInformerFactory := newClusterInformerFactory(t, clusterClient, initObjs...)
+
+	chunks, err := getExistingDecisionChunks(clusterInformerFactory.Cluster().V1beta1().PlacementDecisions().Lister(), placement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]decisionChunk{
+		"cluster1": {decisionGroupIndex: 0, index: 0},
+		"cluster2": {decisionGroupIndex: 0, index: 0},
+		"cluster3": {decisionGroupIndex: 0, index: 1},
+		"cluster4": {decisionGroupIndex: 1, index: 0},
+	}
+	if !reflect.DeepEqual(chunks, expected) {
+		t.Errorf("expected chunks %v, but got %v", expected, chunks)
+	}
+}
+
+func TestChunkDecisions(t *testing.T) {
+	cases := []struct {
+		name           string
+		clusters       []string
+		chunkSize      int
+		existing       map[string]decisionChunk
+		expectedChunks [][]string
+	}{
+		{
+			name:           "no cluster",
+			chunkSize:      2,
+			expectedChunks: [][]string{},
+		},
+		{
+			name:           "sorted by name",
+			clusters:       []string{"cluster5", "cluster3", "cluster1", "cluster4", "cluster2"},
+			chunkSize:      2,
+			expectedChunks: [][]string{{"cluster1", "cluster2"}, {"cluster3", "cluster4"}, {"cluster5"}},
+		},
+		{
+			name:      "keep the existing chunks",
+			clusters:  []string{"cluster0", "cluster2", "cluster3", "cluster4"},
+			chunkSize: 2,
+			existing: map[string]decisionChunk{
+				"cluster1": {index: 0},
+				"cluster2": {index: 0},
+				"cluster3": {index: 1},
+				"cluster4": {index: 1},
+			},
+			expectedChunks: [][]string{{"cluster0", "cluster2"}, {"cluster3", "cluster4"}},
+		},
+		{
+			name:      "existing chunk of another decision group",
+			clusters:  []string{"cluster1", "cluster2"},
+			chunkSize: 1,
+			existing: map[string]decisionChunk{
+				"cluster1": {decisionGroupIndex: 1, index: 1},
+				"cluster2": {index: 0},
+			},
+			expectedChunks: [][]string{{"cluster2"}, {"cluster1"}},
+		},
+		{
+			name:      "shrink the chunks",
+			clusters:  []string{"cluster1", "cluster5"},
+			chunkSize: 2,
+			existing: map[string]decisionChunk{
+				"cluster1": {index: 0},
+				"cluster5": {index: 2},
+			},
+			expectedChunks: [][]string{{"cluster1", "cluster5"}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var decisions []clusterapiv1beta1.ClusterDecision
+			for _, name := range c.clusters {
+				decisions = append(decisions, clusterapiv1beta1.ClusterDecision{ClusterName: name})
+			}
+
+			chunks := [][]string{}
+			for _, slice := range chunkDecisions(decisions, 0, c.chunkSize, c.existing) {
+				var names []string
+				for _, d := range slice {
+					names = append(names, d.ClusterName)
+				}
+				chunks = append(chunks, names)
+			}
+			if !reflect.DeepEqual(chunks, c.expectedChunks) {
+				t.Errorf("expected chunks %v, but got %v", c.expectedChunks, chunks)
+			}
+		})
+	}
+}
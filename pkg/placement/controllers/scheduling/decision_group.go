@@ -0,0 +1,131 @@
+package scheduling
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+)
+
+const (
+	// DecisionGroupKeyAnnotationKey is the annotation on the placement to group the clusters not matching any
+	// of the decision groups by the value of a label or a cluster claim, for example "label:region" or
+	// "claim:region.open-cluster-management.io". Each value forms a decision group named after the value, and
+	// the clusters without the label or claim are put into the group with an empty name.
+	DecisionGroupKeyAnnotationKey = "cluster.open-cluster-management.io/decision-group-key"
+
+	// DecisionGroupOrderAnnotationKey is the annotation on the placement to order the decision groups, for
+	// example "canary,prod". The groups are indexed in the listed order and the other groups follow, so the
+	// consumers following the decision group index, like ManifestWorkReplicaSet and addon rollouts, roll out
+	// to the listed groups first.
+	DecisionGroupOrderAnnotationKey = "cluster.open-cluster-management.io/decision-group-order"
+
+	// DecisionGroupSizesAnnotationKey is the annotation on the placement to limit the number of clusters in each
+	// decision group, for example "canary=1,prod=25%". It overrides the ClustersPerDecisionGroup of the placement
+	// for the listed groups, and a percentage is calculated against all the selected clusters. An empty group
+	// name, like "=10", refers to the group of the clusters not in any named group.
+	DecisionGroupSizesAnnotationKey = "cluster.open-cluster-management.io/decision-group-sizes"
+
+	decisionGroupKeyLabelPrefix = "label:"
+	decisionGroupKeyClaimPrefix = "claim:"
+)
+
+// decisionGroupOptions is the customization of the decision groups parsed from the annotations of the placement.
+type decisionGroupOptions struct {
+	// keyFunc returns the value of the cluster for the group key, it is nil if no group key is set.
+	keyFunc func(cluster *clusterapiv1.ManagedCluster) string
+	order   []string
+	lengths map[string]int
+}
+
+func getDecisionGroupOptions(placement *clusterapiv1beta1.Placement, total int) (*decisionGroupOptions, *framework.Status) {
+	annotations := placement.GetAnnotations()
+	options := &decisionGroupOptions{lengths: map[string]int{}}
+
+	if key, ok := annotations[DecisionGroupKeyAnnotationKey]; ok {
+		switch {
+		case strings.HasPrefix(key, decisionGroupKeyLabelPrefix) && len(key) > len(decisionGroupKeyLabelPrefix):
+			name := strings.TrimPrefix(key, decisionGroupKeyLabelPrefix)
+			options.keyFunc = func(cluster *clusterapiv1.ManagedCluster) string {
+				return cluster.Labels[name]
+			}
+		case strings.HasPrefix(key, decisionGroupKeyClaimPrefix) && len(key) > len(decisionGroupKeyClaimPrefix):
+			name := strings.TrimPrefix(key, decisionGroupKeyClaimPrefix)
+			options.keyFunc = func(cluster *clusterapiv1.ManagedCluster) string {
+				for _, claim := range cluster.Status.ClusterClaims {
+					if claim.Name == name {
+						return claim.Value
+					}
+				}
+				return ""
+			}
+		default:
+			return nil, framework.NewStatus("", framework.Misconfigured, fmt.Sprintf(
+				"invalid value %q of annotation %s, it should be label:<label key> or claim:<claim name>", key, DecisionGroupKeyAnnotationKey))
+		}
+	}
+
+	for _, name := range strings.Split(annotations[DecisionGroupOrderAnnotationKey], ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			options.order = append(options.order, name)
+		}
+	}
+
+	for _, item := range strings.Split(annotations[DecisionGroupSizesAnnotationKey], ",") {
+		if item = strings.TrimSpace(item); len(item) == 0 {
+			continue
+		}
+		name, size, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, framework.NewStatus("", framework.Misconfigured, fmt.Sprintf(
+				"invalid value %q of annotation %s, it should be <group name>=<size>", item, DecisionGroupSizesAnnotationKey))
+		}
+		value := intstr.Parse(strings.TrimSpace(size))
+		length, status := calculateLength(&value, total)
+		if status.IsError() {
+			return nil, status
+		}
+		options.lengths[strings.TrimSpace(name)] = length
+	}
+
+	return options, framework.NewStatus("", framework.Success, "")
+}
+
+// groupLength returns the max number of clusters in the decision group.
+func (o *decisionGroupOptions) groupLength(groupName string, defaultLength int) int {
+	if length, ok := o.lengths[groupName]; ok {
+		return length
+	}
+	return defaultLength
+}
+
+// orderDecisionGroups moves the groups listed in the order to the front, the groups with the same name keep
+// their relative order.
+func (o *decisionGroupOptions) orderDecisionGroups(groups []clusterDecisionGroup) []clusterDecisionGroup {
+	if len(o.order) == 0 {
+		return groups
+	}
+
+	rank := map[string]int{}
+	for i, name := range o.order {
+		if _, ok := rank[name]; !ok {
+			rank[name] = i
+		}
+	}
+	rankOf := func(name string) int {
+		if r, ok := rank[name]; ok {
+			return r
+		}
+		return len(o.order)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return rankOf(groups[i].decisionGroupName) < rankOf(groups[j].decisionGroupName)
+	})
+	return groups
+}
@@ -0,0 +1,126 @@
+package scheduling
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestGenerateDecisionGroupsWithOptions(t *testing.T) {
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").WithLabel("canary", "true").WithLabel("region", "east").Build(),
+		testinghelpers.NewManagedCluster("cluster2").WithLabel("region", "east").Build(),
+		testinghelpers.NewManagedCluster("cluster3").WithLabel("region", "west").Build(),
+		testinghelpers.NewManagedCluster("cluster4").WithClaim("region", "west").Build(),
+		testinghelpers.NewManagedCluster("cluster5").Build(),
+	}
+	canaryGroup := clusterapiv1beta1.GroupStrategy{
+		DecisionGroups: []clusterapiv1beta1.DecisionGroup{
+			{
+				GroupName: "canary",
+				ClusterSelector: clusterapiv1beta1.ClusterSelector{
+					LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		name           string
+		annotations    map[string]string
+		groupStrategy  clusterapiv1beta1.GroupStrategy
+		expectedGroups []clusterDecisionGroup
+		expectedCode   framework.Code
+	}{
+		{
+			name:          "group the rest clusters by label",
+			annotations:   map[string]string{DecisionGroupKeyAnnotationKey: "label:region"},
+			groupStrategy: canaryGroup,
+			expectedGroups: []clusterDecisionGroup{
+				newTestDecisionGroup("canary", "cluster1"),
+				newTestDecisionGroup("", "cluster4", "cluster5"),
+				newTestDecisionGroup("east", "cluster2"),
+				newTestDecisionGroup("west", "cluster3"),
+			},
+		},
+		{
+			name:        "group by claim",
+			annotations: map[string]string{DecisionGroupKeyAnnotationKey: "claim:region"},
+			expectedGroups: []clusterDecisionGroup{
+				newTestDecisionGroup("", "cluster1", "cluster2", "cluster3", "cluster5"),
+				newTestDecisionGroup("west", "cluster4"),
+			},
+		},
+		{
+			name: "order and limit the size of the groups",
+			annotations: map[string]string{
+				DecisionGroupKeyAnnotationKey:   "label:region",
+				DecisionGroupOrderAnnotationKey: "west, canary",
+				DecisionGroupSizesAnnotationKey: "=1",
+			},
+			groupStrategy: canaryGroup,
+			expectedGroups: []clusterDecisionGroup{
+				newTestDecisionGroup("west", "cluster3"),
+				newTestDecisionGroup("canary", "cluster1"),
+				newTestDecisionGroup("", "cluster4"),
+				newTestDecisionGroup("", "cluster5"),
+				newTestDecisionGroup("east", "cluster2"),
+			},
+		},
+		{
+			name:          "size limit in percentage overrides clusters per decision group",
+			annotations:   map[string]string{DecisionGroupSizesAnnotationKey: "=40%"},
+			groupStrategy: clusterapiv1beta1.GroupStrategy{ClustersPerDecisionGroup: intstr.FromInt(1)},
+			expectedGroups: []clusterDecisionGroup{
+				newTestDecisionGroup("", "cluster1", "cluster2"),
+				newTestDecisionGroup("", "cluster3", "cluster4"),
+				newTestDecisionGroup("", "cluster5"),
+			},
+		},
+		{
+			name:         "invalid group key",
+			annotations:  map[string]string{DecisionGroupKeyAnnotationKey: "region"},
+			expectedCode: framework.Misconfigured,
+		},
+		{
+			name:         "invalid group sizes",
+			annotations:  map[string]string{DecisionGroupSizesAnnotationKey: "canary"},
+			expectedCode: framework.Misconfigured,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			placement := testinghelpers.NewPlacementWithAnnotations("test", "test", c.annotations).
+				WithGroupStrategy(c.groupStrategy).Build()
+			ctrl := &schedulingController{}
+
+			groups, status := ctrl.generateDecisionGroups(placement, clusters)
+			if status.Code() != c.expectedCode {
+				t.Fatalf("expected status code %v, but got %v", c.expectedCode, status)
+			}
+			if status.IsError() {
+				return
+			}
+			if !reflect.DeepEqual(groups, clusterDecisionGroups(c.expectedGroups)) {
+				t.Errorf("expected decision groups %v, but got %v", c.expectedGroups, groups)
+			}
+		})
+	}
+}
+
+func newTestDecisionGroup(name string, clusterNames ...string) clusterDecisionGroup {
+	group := clusterDecisionGroup{decisionGroupName: name}
+	for _, clusterName := range clusterNames {
+		group.clusterDecisions = append(group.clusterDecisions, clusterapiv1beta1.ClusterDecision{ClusterName: clusterName})
+	}
+	return group
+}
@@ -3,21 +3,30 @@ package scheduling
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 	clusterinformerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
 	clusterinformerv1beta1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1beta1"
 	clusterinformerv1beta2 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1beta2"
 	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
 	clusterlisterv1beta2 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta2"
 	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
 	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	clusterapiv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/addonhealth"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/antiaffinity"
 )
 
 const (
@@ -27,6 +36,8 @@ const (
 	placementsByClusterSetBinding  = "placementsByClusterSet"
 	clustersetBindingsByClusterSet = "clustersetBindingsByClusterSet"
 	placementsByScore              = "placementsByScore"
+	placementsByAntiAffinity       = "placementsByAntiAffinity"
+	placementsByRequiredAddOn      = "placementsByRequiredAddOn"
 )
 
 type enqueuer struct {
@@ -34,6 +45,10 @@ type enqueuer struct {
 	queue                workqueue.RateLimitingInterface
 	enqueuePlacementFunc func(obj interface{}, queue workqueue.RateLimitingInterface)
 
+	// scoreDebounce delays the placements enqueued because of the score changes, so the frequent
+	// changes of the scores in the window only trigger one scheduling of the placement.
+	scoreDebounce time.Duration
+
 	clusterLister            clusterlisterv1.ManagedClusterLister
 	clusterSetLister         clusterlisterv1beta2.ManagedClusterSetLister
 	placementIndexer         cache.Indexer
@@ -50,6 +65,8 @@ func newEnqueuer(
 	err := placementInformer.Informer().AddIndexers(cache.Indexers{
 		placementsByScore:             indexPlacementsByScore,
 		placementsByClusterSetBinding: indexPlacementByClusterSetBinding,
+		placementsByAntiAffinity:      indexPlacementsByAntiAffinity,
+		placementsByRequiredAddOn:     indexPlacementsByRequiredAddOn,
 	})
 	if err != nil {
 		runtime.HandleError(err)
@@ -130,6 +147,21 @@ func (e *enqueuer) enqueueClusterSet(obj interface{}) {
 		return
 	}
 
+	// the clusters of the clusterset are also members of the clustersets it is nested in
+	ancestors, err := commonhelpers.GetAncestorClusterSetNames(key, e.clusterSetLister)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, ancestor := range ancestors {
+		ancestorObjs, err := e.clusterSetBindingIndexer.ByIndex(clustersetBindingsByClusterSet, ancestor)
+		if err != nil {
+			runtime.HandleError(err)
+			return
+		}
+		objs = append(objs, ancestorObjs...)
+	}
+
 	for _, o := range objs {
 		clusterSetBinding := o.(*clusterapiv1beta2.ManagedClusterSetBinding)
 		e.logger.V(4).Info("Enqueue clustersetbinding because of clusterset", "clusterSetBinding", klog.KObj(clusterSetBinding), "clustersetKey", key)
@@ -157,6 +189,52 @@ func (e *enqueuer) enqueueCluster(obj interface{}) {
 }
 
 func (e *enqueuer) enqueuePlacementScore(obj interface{}) {
+	e.enqueuePlacementsByScore(obj, nil)
+}
+
+// enqueuePlacementScoreUpdate enqueues the placements only when the scores or the valid time of the score
+// changes, and skips the placements not using any of the changed scores.
+func (e *enqueuer) enqueuePlacementScoreUpdate(oldObj, newObj interface{}) {
+	oldScore, ok := oldObj.(*clusterapiv1alpha1.AddOnPlacementScore)
+	if !ok {
+		e.enqueuePlacementScore(newObj)
+		return
+	}
+	newScore, ok := newObj.(*clusterapiv1alpha1.AddOnPlacementScore)
+	if !ok {
+		e.enqueuePlacementScore(newObj)
+		return
+	}
+
+	if !reflect.DeepEqual(oldScore.Status.ValidUntil, newScore.Status.ValidUntil) {
+		e.enqueuePlacementScore(newObj)
+		return
+	}
+
+	changed := sets.New[string]()
+	oldValues := map[string]int32{}
+	for _, score := range oldScore.Status.Scores {
+		oldValues[score.Name] = score.Value
+	}
+	for _, score := range newScore.Status.Scores {
+		if value, ok := oldValues[score.Name]; !ok || value != score.Value {
+			changed.Insert(score.Name)
+		}
+		delete(oldValues, score.Name)
+	}
+	for name := range oldValues {
+		changed.Insert(name)
+	}
+	if changed.Len() == 0 {
+		return
+	}
+
+	e.enqueuePlacementsByScore(newObj, changed)
+}
+
+// enqueuePlacementsByScore enqueues the placements using the score resource. If the scoreNames is not nil,
+// only the placements using one of the scores are enqueued.
+func (e *enqueuer) enqueuePlacementsByScore(obj interface{}, scoreNames sets.Set[string]) {
 	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
 	if err != nil {
 		runtime.HandleError(err)
@@ -190,10 +268,21 @@ func (e *enqueuer) enqueuePlacementScore(obj interface{}) {
 		return
 	}
 
+	clusterSetNames := sets.NewString()
 	for _, clusterset := range clusterSets {
-		bindingObjs, err := e.clusterSetBindingIndexer.ByIndex(clustersetBindingsByClusterSet, clusterset.Name)
+		clusterSetNames.Insert(clusterset.Name)
+		ancestors, err := commonhelpers.GetAncestorClusterSetNames(clusterset.Name, e.clusterSetLister)
 		if err != nil {
-			e.logger.V(4).Error(err, "Unable to get clusterSetBindings of clusterset", "clustersetName", clusterset.Name)
+			e.logger.V(4).Error(err, "Unable to get ancestors of clusterset", "clustersetName", clusterset.Name)
+			continue
+		}
+		clusterSetNames.Insert(ancestors...)
+	}
+
+	for _, clusterSetName := range clusterSetNames.List() {
+		bindingObjs, err := e.clusterSetBindingIndexer.ByIndex(clustersetBindingsByClusterSet, clusterSetName)
+		if err != nil {
+			e.logger.V(4).Error(err, "Unable to get clusterSetBindings of clusterset", "clustersetName", clusterSetName)
 			continue
 		}
 
@@ -205,13 +294,123 @@ func (e *enqueuer) enqueuePlacementScore(obj interface{}) {
 
 	for _, o := range objs {
 		placement := o.(*clusterapiv1beta1.Placement)
-		if filteredBindingNamespaces.Has(placement.Namespace) {
-			e.logger.V(4).Info("Enqueue placement because of score", "placementNamespace", placement.Namespace, "placementName", placement.Name, "scoreKey", key)
-			e.enqueuePlacementFunc(placement, e.queue)
+		if !filteredBindingNamespaces.Has(placement.Namespace) {
+			continue
+		}
+		if scoreNames != nil && !usesScores(placement, name, scoreNames) {
+			continue
 		}
+		e.logger.V(4).Info("Enqueue placement because of score", "placementNamespace", placement.Namespace, "placementName", placement.Name, "scoreKey", key)
+		if e.scoreDebounce > 0 {
+			enqueuePlacementAfter(placement, e.queue, e.scoreDebounce)
+			continue
+		}
+		e.enqueuePlacementFunc(placement, e.queue)
 	}
 }
 
+// enqueuePlacementAfter adds the placement to the queue after the delay. The placement waiting in the
+// queue is not added again, so the changes in the delay are merged into one scheduling.
+func enqueuePlacementAfter(obj interface{}, queue workqueue.RateLimitingInterface, delay time.Duration) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	queue.AddAfter(key, delay)
+}
+
+// usesScores returns true if the placement uses any of the scores in the score resource.
+func usesScores(placement *clusterapiv1beta1.Placement, resourceName string, scoreNames sets.Set[string]) bool {
+	for _, config := range placement.Spec.PrioritizerPolicy.Configurations {
+		if config.ScoreCoordinate == nil || config.ScoreCoordinate.AddOn == nil {
+			continue
+		}
+		if config.ScoreCoordinate.AddOn.ResourceName == resourceName && scoreNames.Has(config.ScoreCoordinate.AddOn.ScoreName) {
+			return true
+		}
+	}
+	return false
+}
+
+// enqueuePlacementDecision enqueues the placements declaring anti-affinity to the placement of the decision,
+// so they are rescheduled once the decisions of the placement change.
+func (e *enqueuer) enqueuePlacementDecision(obj interface{}) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			accessor, err = meta.Accessor(tombstone.Obj)
+		}
+		if err != nil {
+			runtime.HandleError(err)
+			return
+		}
+	}
+
+	placementName, ok := accessor.GetLabels()[clusterapiv1beta1.PlacementLabel]
+	if !ok {
+		return
+	}
+
+	key := fmt.Sprintf("%s/%s", accessor.GetNamespace(), placementName)
+	objs, err := e.placementIndexer.ByIndex(placementsByAntiAffinity, key)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	for _, o := range objs {
+		placement := o.(*clusterapiv1beta1.Placement)
+		e.logger.V(4).Info("Enqueue placement because of anti-affinity", "placementNamespace", placement.Namespace, "placementName", placement.Name, "placementKey", key)
+		e.enqueuePlacementFunc(placement, e.queue)
+	}
+}
+
+// enqueueAddOn enqueues the placements requiring the addon, so they are rescheduled once the addon
+// is installed, removed or changes its availability on a cluster.
+func (e *enqueuer) enqueueAddOn(obj interface{}) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			accessor, err = meta.Accessor(tombstone.Obj)
+		}
+		if err != nil {
+			runtime.HandleError(err)
+			return
+		}
+	}
+
+	objs, err := e.placementIndexer.ByIndex(placementsByRequiredAddOn, accessor.GetName())
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	for _, o := range objs {
+		placement := o.(*clusterapiv1beta1.Placement)
+		e.logger.V(4).Info("Enqueue placement because of addon", "placementNamespace", placement.Namespace, "placementName", placement.Name, "addonName", accessor.GetName())
+		e.enqueuePlacementFunc(placement, e.queue)
+	}
+}
+
+// enqueueAddOnUpdate enqueues the placements requiring the addon only when the availability of the addon changes.
+func (e *enqueuer) enqueueAddOnUpdate(oldObj, newObj interface{}) {
+	oldAddOn, ok := oldObj.(*addonapiv1alpha1.ManagedClusterAddOn)
+	if !ok {
+		return
+	}
+	newAddOn, ok := newObj.(*addonapiv1alpha1.ManagedClusterAddOn)
+	if !ok {
+		return
+	}
+
+	if meta.IsStatusConditionTrue(oldAddOn.Status.Conditions, addonapiv1alpha1.ManagedClusterAddOnConditionAvailable) ==
+		meta.IsStatusConditionTrue(newAddOn.Status.Conditions, addonapiv1alpha1.ManagedClusterAddOnConditionAvailable) {
+		return
+	}
+	e.enqueueAddOn(newObj)
+}
+
 func indexPlacementByClusterSetBinding(obj interface{}) ([]string, error) {
 	placement, ok := obj.(*clusterapiv1beta1.Placement)
 	if !ok {
@@ -254,6 +453,29 @@ func indexPlacementsByScore(obj interface{}) ([]string, error) {
 	return keys, nil
 }
 
+func indexPlacementsByAntiAffinity(obj interface{}) ([]string, error) {
+	placement, ok := obj.(*clusterapiv1beta1.Placement)
+	if !ok {
+		return []string{}, fmt.Errorf("obj %T is not a Placement", obj)
+	}
+
+	var keys []string
+	for _, name := range antiaffinity.GetAntiAffinityPlacements(placement) {
+		keys = append(keys, fmt.Sprintf("%s/%s", placement.Namespace, name))
+	}
+
+	return keys, nil
+}
+
+func indexPlacementsByRequiredAddOn(obj interface{}) ([]string, error) {
+	placement, ok := obj.(*clusterapiv1beta1.Placement)
+	if !ok {
+		return []string{}, fmt.Errorf("obj %T is not a Placement", obj)
+	}
+
+	return addonhealth.GetRequiredAddOns(placement), nil
+}
+
 func indexClusterSetBindingByClusterSet(obj interface{}) ([]string, error) {
 	binding, ok := obj.(*clusterapiv1beta2.ManagedClusterSetBinding)
 	if !ok {
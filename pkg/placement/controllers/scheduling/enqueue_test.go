@@ -12,6 +12,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2/ktesting"
 
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 	clusterclient "open-cluster-management.io/api/client/cluster/clientset/versioned"
 	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
 	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
@@ -22,6 +23,8 @@ import (
 
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
 	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/addonhealth"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/antiaffinity"
 )
 
 func newClusterInformerFactory(t *testing.T, clusterClient clusterclient.Interface, objects ...runtime.Object) clusterinformers.SharedInformerFactory {
@@ -30,6 +33,8 @@ func newClusterInformerFactory(t *testing.T, clusterClient clusterclient.Interfa
 	err := clusterInformerFactory.Cluster().V1beta1().Placements().Informer().AddIndexers(cache.Indexers{
 		placementsByScore:             indexPlacementsByScore,
 		placementsByClusterSetBinding: indexPlacementByClusterSetBinding,
+		placementsByAntiAffinity:      indexPlacementsByAntiAffinity,
+		placementsByRequiredAddOn:     indexPlacementsByRequiredAddOn,
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -412,3 +417,271 @@ func TestEnqueuePlacementsByScore(t *testing.T) {
 		})
 	}
 }
+
+func TestEnqueuePlacementsByScoreUpdate(t *testing.T) {
+	initObjs := []runtime.Object{
+		testinghelpers.NewPlacement("ns1", "placement1").WithScoreCoordinateAddOn("score1", "cpu", 1).Build(),
+		testinghelpers.NewPlacement("ns1", "placement2").WithScoreCoordinateAddOn("score1", "memory", 1).Build(),
+		testinghelpers.NewManagedCluster("cluster1").WithLabel(clusterapiv1beta2.ClusterSetLabel, "clusterset1").Build(),
+		testinghelpers.NewClusterSet("clusterset1").Build(),
+		testinghelpers.NewClusterSetBinding("ns1", "clusterset1"),
+	}
+	validUntil := time.Now()
+
+	cases := []struct {
+		name       string
+		oldScore   interface{}
+		newScore   interface{}
+		queuedKeys []string
+	}{
+		{
+			name:     "scores not changed",
+			oldScore: testinghelpers.NewAddOnPlacementScore("cluster1", "score1").WithScore("cpu", 10).WithScore("memory", 10).Build(),
+			newScore: testinghelpers.NewAddOnPlacementScore("cluster1", "score1").WithScore("cpu", 10).WithScore("memory", 10).Build(),
+		},
+		{
+			name:       "one score changed",
+			oldScore:   testinghelpers.NewAddOnPlacementScore("cluster1", "score1").WithScore("cpu", 10).WithScore("memory", 10).Build(),
+			newScore:   testinghelpers.NewAddOnPlacementScore("cluster1", "score1").WithScore("cpu", 20).WithScore("memory", 10).Build(),
+			queuedKeys: []string{"ns1/placement1"},
+		},
+		{
+			name:       "one score removed",
+			oldScore:   testinghelpers.NewAddOnPlacementScore("cluster1", "score1").WithScore("cpu", 10).WithScore("memory", 10).Build(),
+			newScore:   testinghelpers.NewAddOnPlacementScore("cluster1", "score1").WithScore("cpu", 10).Build(),
+			queuedKeys: []string{"ns1/placement2"},
+		},
+		{
+			name:       "valid until changed",
+			oldScore:   testinghelpers.NewAddOnPlacementScore("cluster1", "score1").WithScore("cpu", 10).Build(),
+			newScore:   testinghelpers.NewAddOnPlacementScore("cluster1", "score1").WithScore("cpu", 10).WithValidUntil(validUntil).Build(),
+			queuedKeys: []string{"ns1/placement1", "ns1/placement2"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			clusterClient := clusterfake.NewSimpleClientset(initObjs...)
+			clusterInformerFactory := newClusterInformerFactory(t, clusterClient, initObjs...)
+
+			syncCtx := testingcommon.NewFakeSyncContext(t, "fake")
+			q := newEnqueuer(
+				ctx,
+				syncCtx.Queue(),
+				clusterInformerFactory.Cluster().V1().ManagedClusters(),
+				clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets(),
+				clusterInformerFactory.Cluster().V1beta1().Placements(),
+				clusterInformerFactory.Cluster().V1beta2().ManagedClusterSetBindings(),
+			)
+			queuedKeys := sets.NewString()
+			q.enqueuePlacementFunc = func(obj interface{}, queue workqueue.RateLimitingInterface) {
+				key, _ := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+				queuedKeys.Insert(key)
+			}
+			q.enqueuePlacementScoreUpdate(c.oldScore, c.newScore)
+
+			expectedQueuedKeys := sets.NewString(c.queuedKeys...)
+			if !queuedKeys.Equal(expectedQueuedKeys) {
+				t.Errorf("expected queued placements %q, but got %s", strings.Join(expectedQueuedKeys.List(), ","), strings.Join(queuedKeys.List(), ","))
+			}
+		})
+	}
+}
+
+func TestEnqueuePlacementsByScoreWithDebounce(t *testing.T) {
+	initObjs := []runtime.Object{
+		testinghelpers.NewPlacement("ns1", "placement1").WithScoreCoordinateAddOn("score1", "cpu", 1).Build(),
+		testinghelpers.NewManagedCluster("cluster1").WithLabel(clusterapiv1beta2.ClusterSetLabel, "clusterset1").Build(),
+		testinghelpers.NewClusterSet("clusterset1").Build(),
+		testinghelpers.NewClusterSetBinding("ns1", "clusterset1"),
+	}
+
+	_, ctx := ktesting.NewTestContext(t)
+	clusterClient := clusterfake.NewSimpleClientset(initObjs...)
+	clusterInformerFactory := newClusterInformerFactory(t, clusterClient, initObjs...)
+
+	syncCtx := testingcommon.NewFakeSyncContext(t, "fake")
+	q := newEnqueuer(
+		ctx,
+		syncCtx.Queue(),
+		clusterInformerFactory.Cluster().V1().ManagedClusters(),
+		clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets(),
+		clusterInformerFactory.Cluster().V1beta1().Placements(),
+		clusterInformerFactory.Cluster().V1beta2().ManagedClusterSetBindings(),
+	)
+	q.scoreDebounce = 100 * time.Millisecond
+
+	// the changes in the debounce window are merged into one
+	for i := 0; i < 3; i++ {
+		q.enqueuePlacementScore(testinghelpers.NewAddOnPlacementScore("cluster1", "score1").WithScore("cpu", int32(i)).Build())
+	}
+	if syncCtx.Queue().Len() != 0 {
+		t.Errorf("expected no placement queued in the debounce window, but got %d", syncCtx.Queue().Len())
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if syncCtx.Queue().Len() != 1 {
+		t.Errorf("expected 1 placement queued after the debounce window, but got %d", syncCtx.Queue().Len())
+	}
+}
+
+func TestEnqueuePlacementsByAntiAffinity(t *testing.T) {
+	initObjs := []runtime.Object{
+		testinghelpers.NewPlacementWithAnnotations("ns1", "placement1", map[string]string{
+			antiaffinity.AntiAffinityAnnotationKey: "placement2",
+		}).Build(),
+		testinghelpers.NewPlacement("ns1", "placement2").Build(),
+		testinghelpers.NewPlacementWithAnnotations("ns2", "placement3", map[string]string{
+			antiaffinity.AntiAffinityAnnotationKey: "placement2",
+		}).Build(),
+	}
+
+	cases := []struct {
+		name       string
+		decision   interface{}
+		queuedKeys []string
+	}{
+		{
+			name: "decision of the placement with anti-affinity",
+			decision: testinghelpers.NewPlacementDecision("ns1", testinghelpers.PlacementDecisionName("placement2", 1)).
+				WithLabel(clusterapiv1beta1.PlacementLabel, "placement2").Build(),
+			queuedKeys: []string{"ns1/placement1"},
+		},
+		{
+			name: "decision of the placement without anti-affinity",
+			decision: testinghelpers.NewPlacementDecision("ns1", testinghelpers.PlacementDecisionName("placement1", 1)).
+				WithLabel(clusterapiv1beta1.PlacementLabel, "placement1").Build(),
+		},
+		{
+			name: "tombstone",
+			decision: cache.DeletedFinalStateUnknown{
+				Key: "ns2/placement2-decision-1",
+				Obj: testinghelpers.NewPlacementDecision("ns2", testinghelpers.PlacementDecisionName("placement2", 1)).
+					WithLabel(clusterapiv1beta1.PlacementLabel, "placement2").Build(),
+			},
+			queuedKeys: []string{"ns2/placement3"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			clusterClient := clusterfake.NewSimpleClientset(initObjs...)
+			clusterInformerFactory := newClusterInformerFactory(t, clusterClient, initObjs...)
+
+			syncCtx := testingcommon.NewFakeSyncContext(t, "fake")
+			q := newEnqueuer(
+				ctx,
+				syncCtx.Queue(),
+				clusterInformerFactory.Cluster().V1().ManagedClusters(),
+				clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets(),
+				clusterInformerFactory.Cluster().V1beta1().Placements(),
+				clusterInformerFactory.Cluster().V1beta2().ManagedClusterSetBindings(),
+			)
+			queuedKeys := sets.NewString()
+			q.enqueuePlacementFunc = func(obj interface{}, queue workqueue.RateLimitingInterface) {
+				key, _ := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+				queuedKeys.Insert(key)
+			}
+			q.enqueuePlacementDecision(c.decision)
+
+			expectedQueuedKeys := sets.NewString(c.queuedKeys...)
+			if !queuedKeys.Equal(expectedQueuedKeys) {
+				t.Errorf("expected queued placements %q, but got %s", strings.Join(expectedQueuedKeys.List(), ","), strings.Join(queuedKeys.List(), ","))
+			}
+		})
+	}
+}
+
+func TestEnqueuePlacementsByAddOn(t *testing.T) {
+	initObjs := []runtime.Object{
+		testinghelpers.NewPlacementWithAnnotations("ns1", "placement1", map[string]string{
+			addonhealth.RequiredAddOnsAnnotationKey: "addon1,addon2",
+		}).Build(),
+		testinghelpers.NewPlacementWithAnnotations("ns2", "placement2", map[string]string{
+			addonhealth.RequiredAddOnsAnnotationKey: "addon2",
+		}).Build(),
+		testinghelpers.NewPlacement("ns1", "placement3").Build(),
+	}
+
+	newAddOn := func(name string, available metav1.ConditionStatus) *addonapiv1alpha1.ManagedClusterAddOn {
+		return &addonapiv1alpha1.ManagedClusterAddOn{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "cluster1", Name: name},
+			Status: addonapiv1alpha1.ManagedClusterAddOnStatus{
+				Conditions: []metav1.Condition{
+					{Type: addonapiv1alpha1.ManagedClusterAddOnConditionAvailable, Status: available},
+				},
+			},
+		}
+	}
+
+	cases := []struct {
+		name       string
+		oldAddOn   *addonapiv1alpha1.ManagedClusterAddOn
+		newAddOn   interface{}
+		queuedKeys []string
+	}{
+		{
+			name:       "addon is added",
+			newAddOn:   newAddOn("addon2", metav1.ConditionTrue),
+			queuedKeys: []string{"ns1/placement1", "ns2/placement2"},
+		},
+		{
+			name:     "addon not required is added",
+			newAddOn: newAddOn("addon3", metav1.ConditionTrue),
+		},
+		{
+			name:       "addon becomes unavailable",
+			oldAddOn:   newAddOn("addon1", metav1.ConditionTrue),
+			newAddOn:   newAddOn("addon1", metav1.ConditionFalse),
+			queuedKeys: []string{"ns1/placement1"},
+		},
+		{
+			name:     "availability of addon is not changed",
+			oldAddOn: newAddOn("addon1", metav1.ConditionFalse),
+			newAddOn: newAddOn("addon1", metav1.ConditionUnknown),
+		},
+		{
+			name: "tombstone",
+			newAddOn: cache.DeletedFinalStateUnknown{
+				Key: "cluster1/addon2",
+				Obj: newAddOn("addon2", metav1.ConditionTrue),
+			},
+			queuedKeys: []string{"ns1/placement1", "ns2/placement2"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			clusterClient := clusterfake.NewSimpleClientset(initObjs...)
+			clusterInformerFactory := newClusterInformerFactory(t, clusterClient, initObjs...)
+
+			syncCtx := testingcommon.NewFakeSyncContext(t, "fake")
+			q := newEnqueuer(
+				ctx,
+				syncCtx.Queue(),
+				clusterInformerFactory.Cluster().V1().ManagedClusters(),
+				clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets(),
+				clusterInformerFactory.Cluster().V1beta1().Placements(),
+				clusterInformerFactory.Cluster().V1beta2().ManagedClusterSetBindings(),
+			)
+			queuedKeys := sets.NewString()
+			q.enqueuePlacementFunc = func(obj interface{}, queue workqueue.RateLimitingInterface) {
+				key, _ := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+				queuedKeys.Insert(key)
+			}
+			if c.oldAddOn != nil {
+				q.enqueueAddOnUpdate(c.oldAddOn, c.newAddOn)
+			} else {
+				q.enqueueAddOn(c.newAddOn)
+			}
+
+			expectedQueuedKeys := sets.NewString(c.queuedKeys...)
+			if !queuedKeys.Equal(expectedQueuedKeys) {
+				t.Errorf("expected queued placements %q, but got %s", strings.Join(expectedQueuedKeys.List(), ","), strings.Join(queuedKeys.List(), ","))
+			}
+		})
+	}
+}
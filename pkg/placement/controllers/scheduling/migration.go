@@ -0,0 +1,206 @@
+package scheduling
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+)
+
+const (
+	// MigrationMaxDecisionsAnnotationKey is the annotation on the placement to migrate the decisions gradually.
+	// When the optimal clusters change, for example a clusterset gains clusters, at most this number of the
+	// existing decisions which are still feasible are moved to the new optimal clusters in each interval. The
+	// decisions on the clusters which are not feasible anymore are always removed at once.
+	MigrationMaxDecisionsAnnotationKey = "cluster.open-cluster-management.io/migration-max-decisions"
+
+	// MigrationIntervalAnnotationKey is the annotation on the placement to set the interval between two
+	// migration batches, for example "10m". The default is 5 minutes.
+	MigrationIntervalAnnotationKey = "cluster.open-cluster-management.io/migration-interval"
+
+	// PlacementConditionMigrating is the condition type of the placement reporting the progress of a gradual
+	// migration. It is only reported when the placement has the migration-max-decisions annotation.
+	PlacementConditionMigrating = "Migrating"
+
+	defaultMigrationInterval = 5 * time.Minute
+)
+
+// migrationPolicy limits the number of the decisions moved in each interval.
+type migrationPolicy struct {
+	maxDecisions int
+	interval     time.Duration
+}
+
+// getMigrationPolicy returns the migration policy of the placement, it returns nil if the placement does not
+// migrate the decisions gradually.
+func getMigrationPolicy(placement *clusterapiv1beta1.Placement) (*migrationPolicy, *framework.Status) {
+	annotations := placement.GetAnnotations()
+	value, ok := annotations[MigrationMaxDecisionsAnnotationKey]
+	if !ok {
+		return nil, framework.NewStatus("", framework.Success, "")
+	}
+
+	maxDecisions, err := strconv.Atoi(value)
+	if err != nil || maxDecisions < 1 {
+		return nil, framework.NewStatus("", framework.Misconfigured,
+			fmt.Sprintf("invalid value %q of annotation %s, it should be a positive integer",
+				value, MigrationMaxDecisionsAnnotationKey))
+	}
+
+	policy := &migrationPolicy{maxDecisions: maxDecisions, interval: defaultMigrationInterval}
+	if value, ok := annotations[MigrationIntervalAnnotationKey]; ok {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			return nil, framework.NewStatus("", framework.Misconfigured,
+				fmt.Sprintf("invalid value %q of annotation %s, it should be a positive duration",
+					value, MigrationIntervalAnnotationKey))
+		}
+		policy.interval = interval
+	}
+	return policy, framework.NewStatus("", framework.Success, "")
+}
+
+// migrationTracker keeps the time of the last migration batch of each placement. It is kept in memory, so
+// a restart of the controller allows one more batch at most.
+type migrationTracker struct {
+	lock      sync.Mutex
+	lastBatch map[string]time.Time
+}
+
+func newMigrationTracker() *migrationTracker {
+	return &migrationTracker{
+		lastBatch: map[string]time.Time{},
+	}
+}
+
+func (t *migrationTracker) get(key string) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.lastBatch[key]
+}
+
+func (t *migrationTracker) set(key string, batch time.Time) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.lastBatch[key] = batch
+}
+
+func (t *migrationTracker) delete(key string) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.lastBatch, key)
+}
+
+// migrationProgress is the result of limiting the migration of the decisions.
+type migrationProgress struct {
+	// migrated is the number of the decisions moved in this scheduling
+	migrated int
+	// pending is the number of the decisions which are still to be moved
+	pending int
+	// next is the time when the next batch is allowed, it is zero if nothing is pending
+	next time.Time
+}
+
+// limitMigration returns the decisions with at most policy.maxDecisions existing feasible clusters replaced
+// by the new ones, if the last batch was at least policy.interval ago. The rest of the existing feasible
+// clusters are kept in place of the lowest ranked new clusters until the next batch. The existing clusters
+// which are not feasible anymore and the clusters filling the free slots are not limited.
+func limitMigration(
+	policy *migrationPolicy,
+	lastBatch, now time.Time,
+	existing sets.Set[string],
+	decisions []*clusterapiv1.ManagedCluster,
+	feasible []*clusterapiv1.ManagedCluster,
+) ([]*clusterapiv1.ManagedCluster, migrationProgress) {
+	selected := sets.New[string]()
+	var kept, added []*clusterapiv1.ManagedCluster
+	for _, cluster := range decisions {
+		selected.Insert(cluster.Name)
+		if existing.Has(cluster.Name) {
+			kept = append(kept, cluster)
+		} else {
+			added = append(added, cluster)
+		}
+	}
+
+	// the existing clusters which are still feasible but not selected anymore are the ones to be moved
+	var replaced []*clusterapiv1.ManagedCluster
+	for _, cluster := range feasible {
+		if existing.Has(cluster.Name) && !selected.Has(cluster.Name) {
+			replaced = append(replaced, cluster)
+		}
+	}
+	sort.SliceStable(replaced, func(i, j int) bool {
+		return replaced[i].Name < replaced[j].Name
+	})
+
+	// the moves are only those replaced clusters which have a new cluster taking their place
+	moves := len(replaced)
+	if moves > len(added) {
+		moves = len(added)
+	}
+	if moves == 0 {
+		return decisions, migrationProgress{}
+	}
+
+	allowed := 0
+	next := lastBatch.Add(policy.interval)
+	if !now.Before(next) {
+		allowed = policy.maxDecisions
+		next = now.Add(policy.interval)
+	}
+	if allowed > moves {
+		allowed = moves
+	}
+
+	pending := moves - allowed
+	progress := migrationProgress{migrated: allowed, pending: pending}
+	if pending == 0 {
+		return decisions, progress
+	}
+	progress.next = next
+
+	// keep the pending clusters in place of the lowest ranked new clusters
+	limited := make([]*clusterapiv1.ManagedCluster, 0, len(decisions))
+	limited = append(limited, kept...)
+	limited = append(limited, replaced[allowed:moves]...)
+	limited = append(limited, added[:len(added)-pending]...)
+	return limited, progress
+}
+
+// newMigratingCondition returns a new condition with type PlacementConditionMigrating
+func newMigratingCondition(progress migrationProgress) metav1.Condition {
+	if progress.pending == 0 {
+		return metav1.Condition{
+			Type:    PlacementConditionMigrating,
+			Status:  metav1.ConditionFalse,
+			Reason:  "MigrationCompleted",
+			Message: "No decisions are pending to be migrated",
+		}
+	}
+	return metav1.Condition{
+		Type:   PlacementConditionMigrating,
+		Status: metav1.ConditionTrue,
+		Reason: "MigrationInProgress",
+		Message: fmt.Sprintf("%d decisions migrated in this batch, %d decisions pending, next batch at %s",
+			progress.migrated, progress.pending, progress.next.UTC().Format(time.RFC3339)),
+	}
+}
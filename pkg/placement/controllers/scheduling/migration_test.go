@@ -0,0 +1,172 @@
+package scheduling
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestGetMigrationPolicy(t *testing.T) {
+	cases := []struct {
+		name           string
+		annotations    map[string]string
+		expectedPolicy *migrationPolicy
+		expectedCode   framework.Code
+	}{
+		{
+			name:         "no annotation",
+			expectedCode: framework.Success,
+		},
+		{
+			name:           "default interval",
+			annotations:    map[string]string{MigrationMaxDecisionsAnnotationKey: "2"},
+			expectedPolicy: &migrationPolicy{maxDecisions: 2, interval: defaultMigrationInterval},
+			expectedCode:   framework.Success,
+		},
+		{
+			name: "custom interval",
+			annotations: map[string]string{
+				MigrationMaxDecisionsAnnotationKey: "1",
+				MigrationIntervalAnnotationKey:     "30s",
+			},
+			expectedPolicy: &migrationPolicy{maxDecisions: 1, interval: 30 * time.Second},
+			expectedCode:   framework.Success,
+		},
+		{
+			name:         "invalid max decisions",
+			annotations:  map[string]string{MigrationMaxDecisionsAnnotationKey: "0"},
+			expectedCode: framework.Misconfigured,
+		},
+		{
+			name: "invalid interval",
+			annotations: map[string]string{
+				MigrationMaxDecisionsAnnotationKey: "1",
+				MigrationIntervalAnnotationKey:     "soon",
+			},
+			expectedCode: framework.Misconfigured,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			placement := testinghelpers.NewPlacementWithAnnotations("test", "test", c.annotations).Build()
+			policy, status := getMigrationPolicy(placement)
+			if status.Code() != c.expectedCode {
+				t.Errorf("expected status code %v, but got %v", c.expectedCode, status)
+			}
+			if !reflect.DeepEqual(policy, c.expectedPolicy) {
+				t.Errorf("expected policy %+v, but got %+v", c.expectedPolicy, policy)
+			}
+		})
+	}
+}
+
+func TestLimitMigration(t *testing.T) {
+	now := time.Now()
+	policy := &migrationPolicy{maxDecisions: 1, interval: time.Minute}
+
+	cases := []struct {
+		name              string
+		lastBatch         time.Time
+		existing          sets.Set[string]
+		decisions         []string
+		feasible          []string
+		expectedDecisions []string
+		expectedProgress  migrationProgress
+	}{
+		{
+			name:              "first scheduling",
+			existing:          sets.New[string](),
+			decisions:         []string{"cluster1", "cluster2"},
+			feasible:          []string{"cluster1", "cluster2", "cluster3"},
+			expectedDecisions: []string{"cluster1", "cluster2"},
+		},
+		{
+			name:              "misplaced clusters are replaced at once",
+			existing:          sets.New[string]("cluster1", "cluster2"),
+			decisions:         []string{"cluster3", "cluster4"},
+			feasible:          []string{"cluster3", "cluster4"},
+			expectedDecisions: []string{"cluster3", "cluster4"},
+		},
+		{
+			name:              "move one decision in a batch",
+			existing:          sets.New[string]("cluster1", "cluster2", "cluster3"),
+			decisions:         []string{"cluster4", "cluster5", "cluster3"},
+			feasible:          []string{"cluster4", "cluster5", "cluster3", "cluster1", "cluster2"},
+			expectedDecisions: []string{"cluster3", "cluster2", "cluster4"},
+			expectedProgress:  migrationProgress{migrated: 1, pending: 1, next: now.Add(time.Minute)},
+		},
+		{
+			name:              "wait for the interval",
+			lastBatch:         now.Add(-30 * time.Second),
+			existing:          sets.New[string]("cluster1", "cluster2", "cluster3"),
+			decisions:         []string{"cluster4", "cluster5", "cluster3"},
+			feasible:          []string{"cluster4", "cluster5", "cluster3", "cluster1", "cluster2"},
+			expectedDecisions: []string{"cluster3", "cluster1", "cluster2"},
+			expectedProgress:  migrationProgress{pending: 2, next: now.Add(30 * time.Second)},
+		},
+		{
+			name:              "last batch",
+			lastBatch:         now.Add(-time.Minute),
+			existing:          sets.New[string]("cluster2", "cluster3", "cluster4"),
+			decisions:         []string{"cluster4", "cluster5", "cluster3"},
+			feasible:          []string{"cluster4", "cluster5", "cluster3", "cluster2"},
+			expectedDecisions: []string{"cluster4", "cluster5", "cluster3"},
+			expectedProgress:  migrationProgress{migrated: 1},
+		},
+		{
+			name:              "scale down is not limited",
+			existing:          sets.New[string]("cluster1", "cluster2", "cluster3"),
+			decisions:         []string{"cluster1"},
+			feasible:          []string{"cluster1", "cluster2", "cluster3"},
+			expectedDecisions: []string{"cluster1"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			decisions, progress := limitMigration(
+				policy, c.lastBatch, now, c.existing, newNamedClusters(c.decisions), newNamedClusters(c.feasible))
+			var names []string
+			for _, cluster := range decisions {
+				names = append(names, cluster.Name)
+			}
+			if !reflect.DeepEqual(names, c.expectedDecisions) {
+				t.Errorf("expected decisions %v, but got %v", c.expectedDecisions, names)
+			}
+			if !reflect.DeepEqual(progress, c.expectedProgress) {
+				t.Errorf("expected progress %+v, but got %+v", c.expectedProgress, progress)
+			}
+		})
+	}
+}
+
+func TestNewMigratingCondition(t *testing.T) {
+	condition := newMigratingCondition(migrationProgress{})
+	if condition.Status != metav1.ConditionFalse || condition.Reason != "MigrationCompleted" {
+		t.Errorf("unexpected condition %+v", condition)
+	}
+
+	next := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	condition = newMigratingCondition(migrationProgress{migrated: 1, pending: 2, next: next})
+	expectedMessage := "1 decisions migrated in this batch, 2 decisions pending, next batch at 2024-01-01T00:00:00Z"
+	if condition.Status != metav1.ConditionTrue || condition.Message != expectedMessage {
+		t.Errorf("unexpected condition %+v", condition)
+	}
+}
+
+func newNamedClusters(names []string) []*clusterapiv1.ManagedCluster {
+	var clusters []*clusterapiv1.ManagedCluster
+	for _, name := range names {
+		clusters = append(clusters, testinghelpers.NewManagedCluster(name).Build())
+	}
+	return clusters
+}
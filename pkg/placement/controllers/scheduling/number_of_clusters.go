@@ -0,0 +1,95 @@
+package scheduling
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+)
+
+const (
+	// NumberOfClustersPercentageAnnotationKey is the annotation on the placement to set the number of
+	// clusters as a percentage of the feasible clusters, for example "10%". The number is rounded up and
+	// recomputed on each scheduling, so it follows the growth of the clustersets. It should not be set
+	// together with the numberOfClusters of the placement.
+	NumberOfClustersPercentageAnnotationKey = "cluster.open-cluster-management.io/number-of-clusters-percentage"
+
+	// MinNumberOfClustersAnnotationKey is the annotation on the placement to set the lower bound of the
+	// number of clusters computed from the percentage.
+	MinNumberOfClustersAnnotationKey = "cluster.open-cluster-management.io/min-number-of-clusters"
+
+	// MaxNumberOfClustersAnnotationKey is the annotation on the placement to set the upper bound of the
+	// number of clusters computed from the percentage.
+	MaxNumberOfClustersAnnotationKey = "cluster.open-cluster-management.io/max-number-of-clusters"
+)
+
+// getNumOfDecisions returns the desired number of decisions of the placement with the number of the feasible
+// clusters. It also returns whether the number is bounded, the clusters which are not able to be scheduled are
+// counted as unscheduled only if the number is bounded.
+func getNumOfDecisions(placement *clusterapiv1beta1.Placement, numOfFeasible int) (int, bool, *framework.Status) {
+	annotations := placement.GetAnnotations()
+	percentage, ok := annotations[NumberOfClustersPercentageAnnotationKey]
+	if !ok {
+		if placement.Spec.NumberOfClusters != nil {
+			return int(*placement.Spec.NumberOfClusters), true, framework.NewStatus("", framework.Success, "")
+		}
+		return numOfFeasible, false, framework.NewStatus("", framework.Success, "")
+	}
+
+	if placement.Spec.NumberOfClusters != nil {
+		return 0, false, framework.NewStatus("", framework.Misconfigured,
+			fmt.Sprintf("annotation %s should not be set together with numberOfClusters", NumberOfClustersPercentageAnnotationKey))
+	}
+
+	percent, err := strconv.Atoi(strings.TrimSuffix(percentage, "%"))
+	if err != nil || !strings.HasSuffix(percentage, "%") || percent < 0 || percent > 100 {
+		return 0, false, invalidNumberStatus(NumberOfClustersPercentageAnnotationKey, percentage, "a percentage between 0% and 100%")
+	}
+	value := intstr.FromString(percentage)
+	num, err := intstr.GetScaledValueFromIntOrPercent(&value, numOfFeasible, true)
+	if err != nil {
+		return 0, false, framework.NewStatus("", framework.Misconfigured, err.Error())
+	}
+
+	minimum, status := getNumberBound(annotations, MinNumberOfClustersAnnotationKey)
+	if status.IsError() {
+		return 0, false, status
+	}
+	maximum, status := getNumberBound(annotations, MaxNumberOfClustersAnnotationKey)
+	if status.IsError() {
+		return 0, false, status
+	}
+	if minimum != nil && maximum != nil && *minimum > *maximum {
+		return 0, false, framework.NewStatus("", framework.Misconfigured,
+			fmt.Sprintf("annotation %s should not be greater than %s", MinNumberOfClustersAnnotationKey, MaxNumberOfClustersAnnotationKey))
+	}
+	if minimum != nil && num < *minimum {
+		num = *minimum
+	}
+	if maximum != nil && num > *maximum {
+		num = *maximum
+	}
+	return num, true, framework.NewStatus("", framework.Success, "")
+}
+
+func getNumberBound(annotations map[string]string, key string) (*int, *framework.Status) {
+	value, ok := annotations[key]
+	if !ok {
+		return nil, framework.NewStatus("", framework.Success, "")
+	}
+	num, err := strconv.Atoi(value)
+	if err != nil || num < 0 {
+		return nil, invalidNumberStatus(key, value, "a non-negative integer")
+	}
+	return &num, framework.NewStatus("", framework.Success, "")
+}
+
+func invalidNumberStatus(key, value, expected string) *framework.Status {
+	return framework.NewStatus("", framework.Misconfigured,
+		fmt.Sprintf("invalid value %q of annotation %s, it should be %s", value, key, expected))
+}
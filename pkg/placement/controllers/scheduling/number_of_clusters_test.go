@@ -0,0 +1,111 @@
+package scheduling
+
+import (
+	"testing"
+
+	"k8s.io/utils/pointer"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestGetNumOfDecisions(t *testing.T) {
+	cases := []struct {
+		name            string
+		annotations     map[string]string
+		noc             *int32
+		numOfFeasible   int
+		expectedCode    framework.Code
+		expectedNum     int
+		expectedBounded bool
+	}{
+		{
+			name:          "all feasible clusters",
+			numOfFeasible: 5,
+			expectedNum:   5,
+		},
+		{
+			name:            "number of clusters",
+			noc:             pointer.Int32(3),
+			numOfFeasible:   5,
+			expectedNum:     3,
+			expectedBounded: true,
+		},
+		{
+			name:            "percentage rounded up",
+			annotations:     map[string]string{NumberOfClustersPercentageAnnotationKey: "10%"},
+			numOfFeasible:   25,
+			expectedNum:     3,
+			expectedBounded: true,
+		},
+		{
+			name: "percentage with min bound",
+			annotations: map[string]string{
+				NumberOfClustersPercentageAnnotationKey: "10%",
+				MinNumberOfClustersAnnotationKey:        "5",
+			},
+			numOfFeasible:   25,
+			expectedNum:     5,
+			expectedBounded: true,
+		},
+		{
+			name: "percentage with max bound",
+			annotations: map[string]string{
+				NumberOfClustersPercentageAnnotationKey: "50%",
+				MaxNumberOfClustersAnnotationKey:        "10",
+			},
+			numOfFeasible:   100,
+			expectedNum:     10,
+			expectedBounded: true,
+		},
+		{
+			name:          "percentage with number of clusters",
+			annotations:   map[string]string{NumberOfClustersPercentageAnnotationKey: "10%"},
+			noc:           pointer.Int32(3),
+			numOfFeasible: 25,
+			expectedCode:  framework.Misconfigured,
+		},
+		{
+			name:          "invalid percentage",
+			annotations:   map[string]string{NumberOfClustersPercentageAnnotationKey: "10"},
+			numOfFeasible: 25,
+			expectedCode:  framework.Misconfigured,
+		},
+		{
+			name:          "percentage out of range",
+			annotations:   map[string]string{NumberOfClustersPercentageAnnotationKey: "120%"},
+			numOfFeasible: 25,
+			expectedCode:  framework.Misconfigured,
+		},
+		{
+			name: "min bound greater than max bound",
+			annotations: map[string]string{
+				NumberOfClustersPercentageAnnotationKey: "10%",
+				MinNumberOfClustersAnnotationKey:        "5",
+				MaxNumberOfClustersAnnotationKey:        "3",
+			},
+			numOfFeasible: 25,
+			expectedCode:  framework.Misconfigured,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			builder := testinghelpers.NewPlacementWithAnnotations(placementNamespace, placementName, c.annotations)
+			if c.noc != nil {
+				builder = builder.WithNOC(*c.noc)
+			}
+
+			num, bounded, status := getNumOfDecisions(builder.Build(), c.numOfFeasible)
+			if status.Code() != c.expectedCode {
+				t.Fatalf("expected status code %v, but got %v", c.expectedCode, status)
+			}
+			if c.expectedCode != framework.Success {
+				return
+			}
+			if num != c.expectedNum || bounded != c.expectedBounded {
+				t.Errorf("expected %d bounded %v, but got %d bounded %v", c.expectedNum, c.expectedBounded, num, bounded)
+			}
+		})
+	}
+}
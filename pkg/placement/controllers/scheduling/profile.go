@@ -0,0 +1,120 @@
+package scheduling
+
+import (
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+// SchedulerProfileAnnotationKey is the annotation on the placement to select a scheduler profile configured
+// on the hub. The filters of the profile replace the filters of the scheduler, the prioritizer weights of the
+// profile are merged over the default weights of the scheduler, and the prioritizer policy of the placement is
+// applied on top of them as usual.
+const SchedulerProfileAnnotationKey = "cluster.open-cluster-management.io/scheduler-profile"
+
+// predicateFilterName is the name of the filter selecting the clusters by the predicates of the placement.
+const predicateFilterName = "Predicate"
+
+// SchedulerProfiles is the configuration of the scheduler profiles.
+type SchedulerProfiles struct {
+	Profiles []SchedulerProfile `json:"profiles"`
+}
+
+// SchedulerProfile is a named set of the filter plugins and the default prioritizers with their weights.
+type SchedulerProfile struct {
+	Name string `json:"name"`
+	// Filters are the names of the filter plugins run for the placements, e.g. TaintToleration. The Predicate
+	// filter is always run so the predicates of the placements are respected, and all the filters of the
+	// scheduler are run if it is empty.
+	Filters []string `json:"filters,omitempty"`
+	// Prioritizers override the weights of the default prioritizers, a prioritizer is disabled with weight 0.
+	Prioritizers []clusterapiv1beta1.PrioritizerConfig `json:"prioritizers,omitempty"`
+}
+
+// LoadSchedulerProfiles reads the scheduler profiles from a yaml or json file, and returns the profiles keyed
+// by their names.
+func LoadSchedulerProfiles(file string) (map[string]SchedulerProfile, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := &SchedulerProfiles{}
+	if err := yaml.UnmarshalStrict(data, profiles); err != nil {
+		return nil, fmt.Errorf("failed to decode the scheduler profiles in %s: %v", file, err)
+	}
+
+	result := map[string]SchedulerProfile{}
+	for _, profile := range profiles.Profiles {
+		if len(profile.Name) == 0 {
+			return nil, fmt.Errorf("the name of the scheduler profile is required")
+		}
+		if _, ok := result[profile.Name]; ok {
+			return nil, fmt.Errorf("duplicated scheduler profile %q", profile.Name)
+		}
+		for _, config := range profile.Prioritizers {
+			if config.ScoreCoordinate == nil {
+				return nil, fmt.Errorf("scoreCoordinate is required in scheduler profile %q", profile.Name)
+			}
+			if config.Weight < -10 || config.Weight > 10 {
+				return nil, fmt.Errorf("the weight of the prioritizers in scheduler profile %q should be in the range [-10, 10]", profile.Name)
+			}
+		}
+		result[profile.Name] = profile
+	}
+	return result, nil
+}
+
+// WithProfiles sets the scheduler profiles which the placements are able to select.
+func (s *pluginScheduler) WithProfiles(profiles map[string]SchedulerProfile) *pluginScheduler {
+	s.profiles = profiles
+	return s
+}
+
+// getProfile returns the filters and the default prioritizer weights of the placement. They are the ones of the
+// scheduler profile selected by the placement merged with the scheduler, or the ones of the scheduler if no
+// profile is selected.
+func (s *pluginScheduler) getProfile(placement *clusterapiv1beta1.Placement) (
+	[]plugins.Filter, map[clusterapiv1beta1.ScoreCoordinate]int32, *framework.Status) {
+	name, ok := placement.GetAnnotations()[SchedulerProfileAnnotationKey]
+	if !ok {
+		return s.filters, s.prioritizerWeights, framework.NewStatus("", framework.Success, "")
+	}
+
+	profile, ok := s.profiles[name]
+	if !ok {
+		return nil, nil, framework.NewStatus("", framework.Misconfigured, fmt.Sprintf("scheduler profile %q is not found", name))
+	}
+
+	filters := s.filters
+	if len(profile.Filters) > 0 {
+		names := sets.New[string](profile.Filters...)
+		filters = nil
+		for _, f := range s.filters {
+			if f.Name() == predicateFilterName || names.Has(f.Name()) {
+				filters = append(filters, f)
+				names.Delete(f.Name())
+			}
+		}
+		if names.Len() > 0 {
+			return nil, nil, framework.NewStatus("", framework.Misconfigured,
+				fmt.Sprintf("filters %v of scheduler profile %q are not found", sets.List(names), name))
+		}
+	}
+
+	weights := map[clusterapiv1beta1.ScoreCoordinate]int32{}
+	for sc, w := range s.prioritizerWeights {
+		weights[sc] = w
+	}
+	for _, config := range profile.Prioritizers {
+		weights[*config.ScoreCoordinate] = config.Weight
+	}
+	return filters, weights, framework.NewStatus("", framework.Success, "")
+}
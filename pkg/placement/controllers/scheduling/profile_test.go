@@ -0,0 +1,247 @@
+package scheduling
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/extender"
+)
+
+func TestLoadSchedulerProfiles(t *testing.T) {
+	cases := []struct {
+		name            string
+		content         string
+		expectedErr     bool
+		expectedWeights map[string]int
+	}{
+		{
+			name: "valid profiles",
+			content: `
+profiles:
+- name: memory
+  filters:
+  - TaintToleration
+  prioritizers:
+  - scoreCoordinate:
+      type: BuiltIn
+      builtIn: ResourceAllocatableMemory
+    weight: 2
+  - scoreCoordinate:
+      type: AddOn
+      addOn:
+        resourceName: demo
+        scoreName: demo
+    weight: 1
+- name: none
+`,
+			expectedWeights: map[string]int{"memory": 2, "none": 0},
+		},
+		{
+			name: "duplicated profiles",
+			content: `
+profiles:
+- name: memory
+- name: memory
+`,
+			expectedErr: true,
+		},
+		{
+			name: "invalid weight",
+			content: `
+profiles:
+- name: memory
+  prioritizers:
+  - scoreCoordinate:
+      type: BuiltIn
+      builtIn: ResourceAllocatableMemory
+    weight: 20
+`,
+			expectedErr: true,
+		},
+		{
+			name: "unknown field",
+			content: `
+profiles:
+- name: memory
+  plugins: []
+`,
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "profiles.yaml")
+			if err := os.WriteFile(file, []byte(c.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			profiles, err := LoadSchedulerProfiles(file)
+			if c.expectedErr != (err != nil) {
+				t.Fatalf("expected error %v, but got %v", c.expectedErr, err)
+			}
+			if len(profiles) != len(c.expectedWeights) {
+				t.Errorf("expected %d profiles, but got %v", len(c.expectedWeights), profiles)
+			}
+			for name, num := range c.expectedWeights {
+				if len(profiles[name].Prioritizers) != num {
+					t.Errorf("expected %d prioritizers in profile %s, but got %v", num, name, profiles[name])
+				}
+			}
+		})
+	}
+}
+
+func TestScheduleWithProfile(t *testing.T) {
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").WithResource(clusterapiv1.ResourceMemory, "10", "100").Build(),
+		testinghelpers.NewManagedCluster("cluster2").WithResource(clusterapiv1.ResourceMemory, "90", "100").Build(),
+		testinghelpers.NewManagedCluster("cluster3").WithResource(clusterapiv1.ResourceMemory, "50", "100").
+			WithTaint(&clusterapiv1.Taint{Key: "maintenance", Effect: clusterapiv1.TaintEffectNoSelect}).Build(),
+	}
+	memory := clusterapiv1beta1.ScoreCoordinate{
+		Type: clusterapiv1beta1.ScoreCoordinateTypeBuiltIn, BuiltIn: PrioritizerResourceAllocatableMemory}
+	balance := clusterapiv1beta1.ScoreCoordinate{Type: clusterapiv1beta1.ScoreCoordinateTypeBuiltIn, BuiltIn: PrioritizerBalance}
+	steady := clusterapiv1beta1.ScoreCoordinate{Type: clusterapiv1beta1.ScoreCoordinateTypeBuiltIn, BuiltIn: PrioritizerSteady}
+	profiles := map[string]SchedulerProfile{
+		"memory": {
+			Name:         "memory",
+			Prioritizers: []clusterapiv1beta1.PrioritizerConfig{{ScoreCoordinate: &memory, Weight: 1}},
+		},
+		"memory-only": {
+			Name: "memory-only",
+			Prioritizers: []clusterapiv1beta1.PrioritizerConfig{
+				{ScoreCoordinate: &memory, Weight: 1},
+				{ScoreCoordinate: &balance, Weight: 0},
+				{ScoreCoordinate: &steady, Weight: 0},
+			},
+		},
+		"ignore-taints": {
+			Name:    "ignore-taints",
+			Filters: []string{"AntiAffinity"},
+		},
+		"unknown-filter": {
+			Name:    "unknown-filter",
+			Filters: []string{"Unknown"},
+		},
+	}
+
+	cases := []struct {
+		name                string
+		annotations         map[string]string
+		expectedCode        framework.Code
+		expectedPrioritizer []string
+		expectedClusters    int
+	}{
+		{
+			name:                "default prioritizers",
+			expectedPrioritizer: []string{PrioritizerBalance, PrioritizerSteady},
+			expectedClusters:    2,
+		},
+		{
+			name:                "prioritizers of the profile merged over the default ones",
+			annotations:         map[string]string{SchedulerProfileAnnotationKey: "memory"},
+			expectedPrioritizer: []string{PrioritizerBalance, PrioritizerSteady, PrioritizerResourceAllocatableMemory},
+			expectedClusters:    2,
+		},
+		{
+			name:                "default prioritizers disabled by the profile",
+			annotations:         map[string]string{SchedulerProfileAnnotationKey: "memory-only"},
+			expectedPrioritizer: []string{PrioritizerResourceAllocatableMemory},
+			expectedClusters:    2,
+		},
+		{
+			name:                "filters of the profile",
+			annotations:         map[string]string{SchedulerProfileAnnotationKey: "ignore-taints"},
+			expectedPrioritizer: []string{PrioritizerBalance, PrioritizerSteady},
+			expectedClusters:    3,
+		},
+		{
+			name:         "filter not found",
+			annotations:  map[string]string{SchedulerProfileAnnotationKey: "unknown-filter"},
+			expectedCode: framework.Misconfigured,
+		},
+		{
+			name:         "profile not found",
+			annotations:  map[string]string{SchedulerProfileAnnotationKey: "cpu"},
+			expectedCode: framework.Misconfigured,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			placement := testinghelpers.NewPlacementWithAnnotations(placementNamespace, placementName, c.annotations).Build()
+			initObjs := []runtime.Object{placement}
+			clusterClient := clusterfake.NewSimpleClientset(initObjs...)
+			s := NewPluginScheduler(testinghelpers.NewFakePluginHandle(t, clusterClient, initObjs...)).WithProfiles(profiles)
+
+			result, status := s.Schedule(context.TODO(), placement, clusters)
+			if status.Code() != c.expectedCode {
+				t.Fatalf("expected status code %v, but got %v", c.expectedCode, status)
+			}
+			if c.expectedCode != framework.Success {
+				return
+			}
+
+			prioritizers := map[string]bool{}
+			for _, r := range result.PrioritizerResults() {
+				prioritizers[r.Name] = true
+			}
+			if len(prioritizers) != len(c.expectedPrioritizer) {
+				t.Errorf("expected prioritizers %v, but got %v", c.expectedPrioritizer, prioritizers)
+			}
+			for _, name := range c.expectedPrioritizer {
+				if !prioritizers[name] {
+					t.Errorf("expected prioritizers %v, but got %v", c.expectedPrioritizer, prioritizers)
+				}
+			}
+			if decisions := len(result.Decisions()); decisions != c.expectedClusters {
+				t.Errorf("expected %d clusters selected, but got %d", c.expectedClusters, decisions)
+			}
+		})
+	}
+}
+
+func TestProfileKeepsExtenderWeight(t *testing.T) {
+	placement := testinghelpers.NewPlacementWithAnnotations(placementNamespace, placementName,
+		map[string]string{SchedulerProfileAnnotationKey: "memory"}).Build()
+	clusterClient := clusterfake.NewSimpleClientset(placement)
+	memory := clusterapiv1beta1.ScoreCoordinate{
+		Type: clusterapiv1beta1.ScoreCoordinateTypeBuiltIn, BuiltIn: PrioritizerResourceAllocatableMemory}
+	s := NewPluginScheduler(testinghelpers.NewFakePluginHandle(t, clusterClient, placement)).
+		WithExtender(&extender.Extender{}, 3).
+		WithProfiles(map[string]SchedulerProfile{
+			"memory": {
+				Name:         "memory",
+				Filters:      []string{"Extender"},
+				Prioritizers: []clusterapiv1beta1.PrioritizerConfig{{ScoreCoordinate: &memory, Weight: 2}},
+			},
+		})
+
+	filters, weights, status := s.getProfile(placement)
+	if status.IsError() {
+		t.Fatalf("unexpected status %v", status)
+	}
+	var names []string
+	for _, f := range filters {
+		names = append(names, f.Name())
+	}
+	if !reflect.DeepEqual(names, []string{predicateFilterName, "Extender"}) {
+		t.Errorf("expected the Predicate and Extender filters, but got %v", names)
+	}
+	extenderSC := clusterapiv1beta1.ScoreCoordinate{Type: clusterapiv1beta1.ScoreCoordinateTypeBuiltIn, BuiltIn: PrioritizerExtender}
+	if weights[extenderSC] != 3 || weights[memory] != 2 {
+		t.Errorf("expected the extender weight 3 and the memory weight 2, but got %v", weights)
+	}
+}
@@ -0,0 +1,64 @@
+package scheduling
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/metrics"
+)
+
+// ScheduleResultCache keeps the schedule result of the last scheduling pass of each placement, so it is
+// able to explain why a cluster is or is not selected by a placement.
+type ScheduleResultCache struct {
+	lock    sync.RWMutex
+	results map[string]ScheduleResult
+}
+
+// NewScheduleResultCache returns an empty ScheduleResultCache
+func NewScheduleResultCache() *ScheduleResultCache {
+	return &ScheduleResultCache{
+		results: map[string]ScheduleResult{},
+	}
+}
+
+// Get returns the schedule result of the last scheduling pass of a placement with the namespace/name key.
+func (c *ScheduleResultCache) Get(key string) (ScheduleResult, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	result, ok := c.results[key]
+	return result, ok
+}
+
+func (c *ScheduleResultCache) set(key string, result ScheduleResult) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.results[key] = result
+}
+
+func (c *ScheduleResultCache) delete(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.results, key)
+}
+
+// recordScheduleMetrics exports the duration and the per plugin results of a scheduling pass.
+func recordScheduleMetrics(start time.Time, clusters []*clusterapiv1.ManagedCluster, result ScheduleResult) {
+	metrics.SchedulingDuration.Observe(time.Since(start).Seconds())
+
+	// the filter results are the clusters left after each step of the filter pipeline
+	left := len(clusters)
+	for _, r := range result.FilterResults() {
+		pipeline := strings.Split(r.Name, ",")
+		metrics.FilteredOutClusters.WithLabelValues(pipeline[len(pipeline)-1]).Add(float64(left - len(r.FilteredClusters)))
+		left = len(r.FilteredClusters)
+	}
+
+	for _, r := range result.PrioritizerResults() {
+		for _, score := range r.Scores {
+			metrics.PrioritizerScore.WithLabelValues(r.Name).Observe(float64(score))
+		}
+	}
+}
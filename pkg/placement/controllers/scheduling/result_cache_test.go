@@ -0,0 +1,65 @@
+package scheduling
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/component-base/metrics/testutil"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/metrics"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestScheduleResultCache(t *testing.T) {
+	cache := NewScheduleResultCache()
+	if _, ok := cache.Get("ns/test"); ok {
+		t.Errorf("expected no result in an empty cache")
+	}
+
+	result := &scheduleResult{unscheduledDecisions: 1}
+	cache.set("ns/test", result)
+	actual, ok := cache.Get("ns/test")
+	if !ok || actual.NumOfUnscheduled() != 1 {
+		t.Errorf("expected the recorded result, but got %v", actual)
+	}
+
+	cache.delete("ns/test")
+	if _, ok := cache.Get("ns/test"); ok {
+		t.Errorf("expected the result deleted")
+	}
+}
+
+func TestRecordScheduleMetrics(t *testing.T) {
+	metrics.Register()
+
+	toleratedBefore, err := testutil.GetCounterMetricValue(metrics.FilteredOutClusters.WithLabelValues("TaintToleration"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	placement := testinghelpers.NewPlacement(placementNamespace, placementName).Build()
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").Build(),
+		testinghelpers.NewManagedCluster("cluster2").WithTaint(&clusterapiv1.Taint{
+			Key:    "key",
+			Effect: clusterapiv1.TaintEffectNoSelect,
+		}).Build(),
+	}
+	clusterClient := clusterfake.NewSimpleClientset(placement)
+	s := NewPluginScheduler(testinghelpers.NewFakePluginHandle(t, clusterClient, placement))
+	result, _ := s.Schedule(context.TODO(), placement, clusters)
+
+	recordScheduleMetrics(time.Now(), clusters, result)
+
+	toleratedAfter, err := testutil.GetCounterMetricValue(metrics.FilteredOutClusters.WithLabelValues("TaintToleration"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if toleratedAfter-toleratedBefore != 1 {
+		t.Errorf("expected 1 cluster filtered out by TaintToleration, but got %v", toleratedAfter-toleratedBefore)
+	}
+}
@@ -7,24 +7,33 @@ import (
 	"strings"
 	"time"
 
+	"k8s.io/apimachinery/pkg/util/sets"
 	kevents "k8s.io/client-go/tools/events"
 	"k8s.io/klog/v2"
 
+	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
 	clusterclient "open-cluster-management.io/api/client/cluster/clientset/versioned"
 	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
 	clusterlisterv1alpha1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1alpha1"
 	clusterlisterv1beta1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
+	clusterlisterv1beta2 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta2"
 	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
 	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 
 	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
 	"open-cluster-management.io/ocm/pkg/placement/plugins"
 	"open-cluster-management.io/ocm/pkg/placement/plugins/addon"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/addonhealth"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/antiaffinity"
 	"open-cluster-management.io/ocm/pkg/placement/plugins/balance"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/clustersetpriority"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/extender"
 	"open-cluster-management.io/ocm/pkg/placement/plugins/predicate"
 	"open-cluster-management.io/ocm/pkg/placement/plugins/resource"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/spread"
 	"open-cluster-management.io/ocm/pkg/placement/plugins/steady"
 	"open-cluster-management.io/ocm/pkg/placement/plugins/tainttoleration"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/topology"
 )
 
 const (
@@ -32,8 +41,14 @@ const (
 	PrioritizerSteady                    string = "Steady"
 	PrioritizerResourceAllocatableCPU    string = "ResourceAllocatableCPU"
 	PrioritizerResourceAllocatableMemory string = "ResourceAllocatableMemory"
+	PrioritizerTopology                  string = "Topology"
+	PrioritizerExtender                  string = "Extender"
+	PrioritizerClusterSetPriority        string = "ClusterSetPriority"
 )
 
+// staleScoreFilterName is the name of the filter stage dropping the clusters with stale scores in the filter results.
+const staleScoreFilterName = "StaleScore"
+
 // PrioritizerScore defines the score for each cluster
 type PrioritizerScore map[string]int64
 
@@ -59,9 +74,15 @@ type ScheduleResult interface {
 	// Decisions returns the decision groups of the schedule
 	Decisions() []*clusterapiv1.ManagedCluster
 
+	// FeasibleClusters returns the clusters passing all the filters, sorted by the score in descending order.
+	FeasibleClusters() []*clusterapiv1.ManagedCluster
+
 	// NumOfUnscheduled returns the number of unscheduled.
 	NumOfUnscheduled() int
 
+	// NumOfStaleScoreClusters returns the number of the feasible clusters having stale AddOnPlacementScores.
+	NumOfStaleScoreClusters() int
+
 	// RequeueAfter returns the requeue time interval of the placement
 	RequeueAfter() *time.Duration
 }
@@ -84,6 +105,7 @@ type scheduleResult struct {
 	feasibleClusters     []*clusterapiv1.ManagedCluster
 	scheduledDecisions   []*clusterapiv1.ManagedCluster
 	unscheduledDecisions int
+	staleScoreClusters   int
 
 	filteredRecords map[string][]*clusterapiv1.ManagedCluster
 	scoreRecords    []PrioritizerResult
@@ -96,6 +118,8 @@ type schedulerHandler struct {
 	placementDecisionLister clusterlisterv1beta1.PlacementDecisionLister
 	scoreLister             clusterlisterv1alpha1.AddOnPlacementScoreLister
 	clusterLister           clusterlisterv1.ManagedClusterLister
+	addOnLister             addonlisterv1alpha1.ManagedClusterAddOnLister
+	clusterSetLister        clusterlisterv1beta2.ManagedClusterSetLister
 	clusterClient           clusterclient.Interface
 }
 
@@ -104,6 +128,8 @@ func NewSchedulerHandler(
 	placementDecisionLister clusterlisterv1beta1.PlacementDecisionLister,
 	scoreLister clusterlisterv1alpha1.AddOnPlacementScoreLister,
 	clusterLister clusterlisterv1.ManagedClusterLister,
+	addOnLister addonlisterv1alpha1.ManagedClusterAddOnLister,
+	clusterSetLister clusterlisterv1beta2.ManagedClusterSetLister,
 	recorder kevents.EventRecorder) plugins.Handle {
 
 	return &schedulerHandler{
@@ -111,6 +137,8 @@ func NewSchedulerHandler(
 		placementDecisionLister: placementDecisionLister,
 		scoreLister:             scoreLister,
 		clusterLister:           clusterLister,
+		addOnLister:             addOnLister,
+		clusterSetLister:        clusterSetLister,
 		clusterClient:           clusterClient,
 	}
 }
@@ -131,6 +159,14 @@ func (s *schedulerHandler) ClusterLister() clusterlisterv1.ManagedClusterLister
 	return s.clusterLister
 }
 
+func (s *schedulerHandler) AddOnLister() addonlisterv1alpha1.ManagedClusterAddOnLister {
+	return s.addOnLister
+}
+
+func (s *schedulerHandler) ClusterSetLister() clusterlisterv1beta2.ManagedClusterSetLister {
+	return s.clusterSetLister
+}
+
 func (s *schedulerHandler) ClusterClient() clusterclient.Interface {
 	return s.clusterClient
 }
@@ -149,10 +185,52 @@ var defaultPrioritizerConfig = map[clusterapiv1beta1.ScoreCoordinate]int32{
 	}: 1,
 }
 
+// binPackWeights adjusts the default prioritizer weights for the placements in BinPack mode. Balance is
+// removed since it spreads the decisions, and the resource prioritizers are added if none of them is
+// in the default weights. The weights configured in the placement still take precedence.
+func binPackWeights(defaultWeights map[clusterapiv1beta1.ScoreCoordinate]int32) map[clusterapiv1beta1.ScoreCoordinate]int32 {
+	weights := map[clusterapiv1beta1.ScoreCoordinate]int32{}
+	hasResource := false
+	for sc, w := range defaultWeights {
+		if sc.Type == clusterapiv1beta1.ScoreCoordinateTypeBuiltIn && sc.BuiltIn == PrioritizerBalance {
+			continue
+		}
+		if sc.Type == clusterapiv1beta1.ScoreCoordinateTypeBuiltIn && strings.HasPrefix(sc.BuiltIn, "ResourceAllocatable") {
+			hasResource = true
+		}
+		weights[sc] = w
+	}
+
+	if !hasResource {
+		for _, name := range []string{PrioritizerResourceAllocatableCPU, PrioritizerResourceAllocatableMemory} {
+			weights[clusterapiv1beta1.ScoreCoordinate{Type: clusterapiv1beta1.ScoreCoordinateTypeBuiltIn, BuiltIn: name}] = 1
+		}
+	}
+	return weights
+}
+
+// clusterSetPriorityWeights enables the ClusterSetPriority prioritizer with weight 1 in the default prioritizer
+// weights for the placements having the clusterset priorities, if it is not in the default weights.
+func clusterSetPriorityWeights(defaultWeights map[clusterapiv1beta1.ScoreCoordinate]int32) map[clusterapiv1beta1.ScoreCoordinate]int32 {
+	sc := clusterapiv1beta1.ScoreCoordinate{Type: clusterapiv1beta1.ScoreCoordinateTypeBuiltIn, BuiltIn: PrioritizerClusterSetPriority}
+	if _, ok := defaultWeights[sc]; ok {
+		return defaultWeights
+	}
+
+	weights := map[clusterapiv1beta1.ScoreCoordinate]int32{sc: 1}
+	for k, w := range defaultWeights {
+		weights[k] = w
+	}
+	return weights
+}
+
 type pluginScheduler struct {
 	handle             plugins.Handle
 	filters            []plugins.Filter
+	selector           plugins.Selector
+	extender           *extender.Extender
 	prioritizerWeights map[clusterapiv1beta1.ScoreCoordinate]int32
+	profiles           map[string]SchedulerProfile
 }
 
 func NewPluginScheduler(handle plugins.Handle) *pluginScheduler {
@@ -161,11 +239,36 @@ func NewPluginScheduler(handle plugins.Handle) *pluginScheduler {
 		filters: []plugins.Filter{
 			predicate.New(handle),
 			tainttoleration.New(handle),
+			antiaffinity.New(handle),
+			addonhealth.New(handle),
 		},
+		selector:           spread.New(handle),
 		prioritizerWeights: defaultPrioritizerConfig,
 	}
 }
 
+// WithExtender appends the extender to the filters, and enables the extender prioritizer with the
+// weight by default. The extender prioritizer is also able to be configured as the builtin prioritizer
+// "Extender" in the prioritizer policy of the placement.
+func (s *pluginScheduler) WithExtender(e *extender.Extender, weight int32) *pluginScheduler {
+	if e == nil {
+		return s
+	}
+
+	s.extender = e
+	s.filters = append(s.filters, e)
+	weights := map[clusterapiv1beta1.ScoreCoordinate]int32{}
+	for sc, w := range s.prioritizerWeights {
+		weights[sc] = w
+	}
+	weights[clusterapiv1beta1.ScoreCoordinate{
+		Type:    clusterapiv1beta1.ScoreCoordinateTypeBuiltIn,
+		BuiltIn: PrioritizerExtender,
+	}] = weight
+	s.prioritizerWeights = weights
+	return s
+}
+
 func (s *pluginScheduler) Schedule(
 	ctx context.Context,
 	placement *clusterapiv1beta1.Placement,
@@ -180,10 +283,16 @@ func (s *pluginScheduler) Schedule(
 		scoreRecords:    []PrioritizerResult{},
 	}
 
+	// get the filters and the default prioritizer weights of the scheduler profile
+	filters, defaultWeights, status := s.getProfile(placement)
+	if status.IsError() {
+		return results, status
+	}
+
 	// filter clusters
 	var filterPipline []string
 
-	for _, f := range s.filters {
+	for _, f := range filters {
 		filterResult, status := f.Filter(ctx, placement, filtered)
 		filtered = filterResult.Filtered
 
@@ -200,10 +309,39 @@ func (s *pluginScheduler) Schedule(
 		results.filteredRecords[strings.Join(filterPipline, ",")] = filtered
 	}
 
+	// count the clusters with stale scores, and filter them out if the placement asks for it
+	policy, _, err := addon.GetStaleScorePolicy(placement)
+	if err != nil {
+		return results, framework.NewStatus("", framework.Misconfigured, err.Error())
+	}
+	staleScoreClusters := addon.StaleScoreClusters(s.handle.ScoreLister(), placement, filtered)
+	results.staleScoreClusters = staleScoreClusters.Len()
+	if policy == addon.StaleScorePolicyFilterOut {
+		var fresh []*clusterapiv1.ManagedCluster
+		for _, cluster := range filtered {
+			if !staleScoreClusters.Has(cluster.Name) {
+				fresh = append(fresh, cluster)
+			}
+		}
+		filtered = fresh
+		filterPipline = append(filterPipline, staleScoreFilterName)
+		results.filteredRecords[strings.Join(filterPipline, ",")] = filtered
+	}
+
 	// Prioritize clusters
 	// 1. Get weight for each prioritizers.
 	// For example, weights is {"Steady": 1, "Balance":1, "AddOn/default/ratio":3}.
-	weights, status := getWeights(s.prioritizerWeights, placement)
+	mode, err := resource.GetSchedulingMode(placement)
+	if err != nil {
+		return results, framework.NewStatus("", framework.Misconfigured, err.Error())
+	}
+	if mode == resource.SchedulingModeBinPack {
+		defaultWeights = binPackWeights(defaultWeights)
+	}
+	if _, ok := placement.GetAnnotations()[clustersetpriority.ClusterSetPrioritiesAnnotationKey]; ok {
+		defaultWeights = clusterSetPriorityWeights(defaultWeights)
+	}
+	weights, status := getWeights(defaultWeights, placement)
 	switch {
 	case status.IsError():
 		return results, status
@@ -213,7 +351,7 @@ func (s *pluginScheduler) Schedule(
 	}
 
 	// 2. Generate prioritizers for each placement whose weight != 0.
-	prioritizers, status := getPrioritizers(weights, s.handle)
+	prioritizers, status := getPrioritizers(weights, s.handle, s.extender)
 	switch {
 	case status.IsError():
 		return results, status
@@ -253,12 +391,36 @@ func (s *pluginScheduler) Schedule(
 
 	}
 
-	// 4. Sort clusters by score, if score is equal, sort by name
+	// 4. Sort clusters by score, if score is equal, sort by name.
+	// The clusters in the existing decisions get the rescheduling score threshold as a bonus, and win the tie.
+	threshold, status := getReschedulingScoreThreshold(placement)
+	if status.IsError() {
+		return results, status
+	}
+	sortScores := scoreSum
+	existing := sets.New[string]()
+	if threshold > 0 {
+		var err error
+		existing, err = getDecisionClusters(s.handle.DecisionLister(), placement)
+		if err != nil {
+			return results, framework.NewStatus("", framework.Error, err.Error())
+		}
+		sortScores = PrioritizerScore{}
+		for name, score := range scoreSum {
+			sortScores[name] = score
+			if existing.Has(name) {
+				sortScores[name] = score + threshold
+			}
+		}
+	}
 	sort.SliceStable(filtered, func(i, j int) bool {
-		if scoreSum[filtered[i].Name] == scoreSum[filtered[j].Name] {
+		if sortScores[filtered[i].Name] == sortScores[filtered[j].Name] {
+			if existing.Has(filtered[i].Name) != existing.Has(filtered[j].Name) {
+				return existing.Has(filtered[i].Name)
+			}
 			return filtered[i].Name < filtered[j].Name
 		} else {
-			return scoreSum[filtered[i].Name] > scoreSum[filtered[j].Name]
+			return sortScores[filtered[i].Name] > sortScores[filtered[j].Name]
 		}
 	})
 
@@ -266,16 +428,28 @@ func (s *pluginScheduler) Schedule(
 	results.scoreSum = scoreSum
 
 	// select clusters and generate cluster decisions
-	decisions := selectClusters(placement, filtered)
+	numOfDecisions, bounded, status := getNumOfDecisions(placement, len(filtered))
+	if status.IsError() {
+		return results, status
+	}
+	selectResult, status := s.selector.Select(ctx, placement, filtered, numOfDecisions)
+	switch {
+	case status.IsError():
+		return results, status
+	case status.Code() == framework.Warning:
+		logger.Info("Warning status message", "message", status.Message())
+		finalStatus = status
+	}
+	decisions := selectResult.Selected
 	scheduled, unscheduled := len(decisions), 0
-	if placement.Spec.NumberOfClusters != nil {
-		unscheduled = int(*placement.Spec.NumberOfClusters) - scheduled
+	if bounded {
+		unscheduled = numOfDecisions - scheduled
 	}
 	results.scheduledDecisions = decisions
 	results.unscheduledDecisions = unscheduled
 
 	// set placement requeue time
-	for _, f := range s.filters {
+	for _, f := range filters {
 		if r, _ := f.RequeueAfter(ctx, placement); r.RequeueTime != nil {
 			newRequeueAfter := time.Until(*r.RequeueTime)
 			results.requeueAfter = setRequeueAfter(results.requeueAfter, &newRequeueAfter)
@@ -291,22 +465,6 @@ func (s *pluginScheduler) Schedule(
 	return results, finalStatus
 }
 
-// selects clusters based on given cluster slice and number of clusters
-func selectClusters(placement *clusterapiv1beta1.Placement, clusters []*clusterapiv1.ManagedCluster) []*clusterapiv1.ManagedCluster {
-	numOfDecisions := len(clusters)
-	if placement.Spec.NumberOfClusters != nil {
-		numOfDecisions = int(*placement.Spec.NumberOfClusters)
-	}
-
-	// truncate the cluster slice if the desired number of decisions is less than
-	// the number of the candidate clusters
-	if numOfDecisions < len(clusters) {
-		clusters = clusters[:numOfDecisions]
-	}
-
-	return clusters
-}
-
 // setRequeueAfter selects minimal time.Duration as requeue time
 func setRequeueAfter(requeueAfter, newRequeueAfter *time.Duration) *time.Duration {
 	if newRequeueAfter == nil {
@@ -359,7 +517,7 @@ func mergeWeights(defaultWeight map[clusterapiv1beta1.ScoreCoordinate]int32,
 }
 
 // Generate prioritizers for the placement.
-func getPrioritizers(weights map[clusterapiv1beta1.ScoreCoordinate]int32, handle plugins.Handle,
+func getPrioritizers(weights map[clusterapiv1beta1.ScoreCoordinate]int32, handle plugins.Handle, e *extender.Extender,
 ) (map[clusterapiv1beta1.ScoreCoordinate]plugins.Prioritizer, *framework.Status) {
 	result := make(map[clusterapiv1beta1.ScoreCoordinate]plugins.Prioritizer)
 	status := framework.NewStatus("", framework.Success, "")
@@ -375,6 +533,15 @@ func getPrioritizers(weights map[clusterapiv1beta1.ScoreCoordinate]int32, handle
 				result[k] = steady.New(handle)
 			case k.BuiltIn == PrioritizerResourceAllocatableCPU || k.BuiltIn == PrioritizerResourceAllocatableMemory:
 				result[k] = resource.NewResourcePrioritizerBuilder(handle).WithPrioritizerName(k.BuiltIn).Build()
+			case strings.HasPrefix(k.BuiltIn, resource.ResourceAllocatablePrefix) &&
+				len(k.BuiltIn) > len(resource.ResourceAllocatablePrefix):
+				result[k] = resource.NewResourcePrioritizerBuilder(handle).WithPrioritizerName(k.BuiltIn).Build()
+			case k.BuiltIn == PrioritizerTopology:
+				result[k] = topology.New(handle)
+			case k.BuiltIn == PrioritizerClusterSetPriority:
+				result[k] = clustersetpriority.New(handle)
+			case k.BuiltIn == PrioritizerExtender && e != nil:
+				result[k] = e
 			default:
 				msg := fmt.Sprintf("incorrect builtin prioritizer: %s", k.BuiltIn)
 				return nil, framework.NewStatus("", framework.Misconfigured, msg)
@@ -425,10 +592,18 @@ func (r *scheduleResult) Decisions() []*clusterapiv1.ManagedCluster {
 	return r.scheduledDecisions
 }
 
+func (r *scheduleResult) FeasibleClusters() []*clusterapiv1.ManagedCluster {
+	return r.feasibleClusters
+}
+
 func (r *scheduleResult) NumOfUnscheduled() int {
 	return r.unscheduledDecisions
 }
 
+func (r *scheduleResult) NumOfStaleScoreClusters() int {
+	return r.staleScoreClusters
+}
+
 func (r *scheduleResult) RequeueAfter() *time.Duration {
 	return r.requeueAfter
 }
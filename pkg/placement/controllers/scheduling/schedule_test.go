@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -18,6 +19,9 @@ import (
 
 	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
 	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/addon"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/clustersetpriority"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/resource"
 )
 
 func TestSchedule(t *testing.T) {
@@ -58,6 +62,14 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,AntiAffinity",
+					FilteredClusters: []string{"cluster1"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,AntiAffinity,AddOnHealth",
+					FilteredClusters: []string{"cluster1"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -96,6 +108,14 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,AntiAffinity",
+					FilteredClusters: []string{"cluster1"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,AntiAffinity,AddOnHealth",
+					FilteredClusters: []string{"cluster1"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -169,6 +189,14 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,AntiAffinity",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,AntiAffinity,AddOnHealth",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -204,6 +232,14 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,AntiAffinity",
+					FilteredClusters: []string{"cluster1"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,AntiAffinity,AddOnHealth",
+					FilteredClusters: []string{"cluster1"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -274,6 +310,14 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1", "cluster3"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,AntiAffinity",
+					FilteredClusters: []string{"cluster1", "cluster3"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,AntiAffinity,AddOnHealth",
+					FilteredClusters: []string{"cluster1", "cluster3"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -333,6 +377,14 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,AntiAffinity",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,AntiAffinity,AddOnHealth",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -397,6 +449,14 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,AntiAffinity",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,AntiAffinity,AddOnHealth",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -444,6 +504,14 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1", "cluster2"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,AntiAffinity",
+					FilteredClusters: []string{"cluster1", "cluster2"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,AntiAffinity,AddOnHealth",
+					FilteredClusters: []string{"cluster1", "cluster2"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -490,6 +558,14 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster3", "cluster1", "cluster2"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,AntiAffinity",
+					FilteredClusters: []string{"cluster3", "cluster1", "cluster2"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,AntiAffinity,AddOnHealth",
+					FilteredClusters: []string{"cluster3", "cluster1", "cluster2"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -541,6 +617,14 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster3", "cluster1", "cluster2"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,AntiAffinity",
+					FilteredClusters: []string{"cluster3", "cluster1", "cluster2"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,AntiAffinity,AddOnHealth",
+					FilteredClusters: []string{"cluster3", "cluster1", "cluster2"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -607,3 +691,179 @@ func TestSchedule(t *testing.T) {
 func TestFilterResults(t *testing.T) {
 
 }
+
+func TestScheduleWithStaleScorePolicy(t *testing.T) {
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").Build(),
+		testinghelpers.NewManagedCluster("cluster2").Build(),
+	}
+	scores := []runtime.Object{
+		testinghelpers.NewAddOnPlacementScore("cluster1", "demo").WithScore("demo", 30).WithValidUntil(time.Now().Add(-time.Minute)).Build(),
+		testinghelpers.NewAddOnPlacementScore("cluster2", "demo").WithScore("demo", 40).WithValidUntil(time.Now().Add(time.Hour)).Build(),
+	}
+
+	cases := []struct {
+		name              string
+		annotations       map[string]string
+		expectedDecisions []string
+	}{
+		{
+			name:              "stale scores are zero",
+			expectedDecisions: []string{"cluster2", "cluster1"},
+		},
+		{
+			name:              "filter out the clusters with stale scores",
+			annotations:       map[string]string{addon.StaleScorePolicyAnnotationKey: string(addon.StaleScorePolicyFilterOut)},
+			expectedDecisions: []string{"cluster2"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			placement := testinghelpers.NewPlacementWithAnnotations(placementNamespace, placementName, c.annotations).
+				WithScoreCoordinateAddOn("demo", "demo", 1).Build()
+			initObjs := append([]runtime.Object{placement}, scores...)
+			clusterClient := clusterfake.NewSimpleClientset(initObjs...)
+			s := NewPluginScheduler(testinghelpers.NewFakePluginHandle(t, clusterClient, initObjs...))
+
+			result, status := s.Schedule(context.TODO(), placement, clusters)
+			if status.IsError() {
+				t.Fatalf("unexpected error %v", status.AsError())
+			}
+			if result.NumOfStaleScoreClusters() != 1 {
+				t.Errorf("expected 1 cluster with stale scores, but got %d", result.NumOfStaleScoreClusters())
+			}
+			var decisions []string
+			for _, cluster := range result.Decisions() {
+				decisions = append(decisions, cluster.Name)
+			}
+			if !reflect.DeepEqual(decisions, c.expectedDecisions) {
+				t.Errorf("expected decisions %v, but got %v", c.expectedDecisions, decisions)
+			}
+		})
+	}
+}
+
+func TestScheduleWithSchedulingMode(t *testing.T) {
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").WithResource(clusterapiv1.ResourceCPU, "2", "10").
+			WithResource(clusterapiv1.ResourceMemory, "20", "100").Build(),
+		testinghelpers.NewManagedCluster("cluster2").WithResource(clusterapiv1.ResourceCPU, "5", "10").
+			WithResource(clusterapiv1.ResourceMemory, "50", "100").Build(),
+		testinghelpers.NewManagedCluster("cluster3").WithResource(clusterapiv1.ResourceCPU, "8", "10").
+			WithResource(clusterapiv1.ResourceMemory, "80", "100").Build(),
+	}
+
+	cases := []struct {
+		name              string
+		annotations       map[string]string
+		resourceWeight    int32
+		expectedDecisions []string
+		expectedCode      framework.Code
+	}{
+		{
+			name:              "spread by the resource prioritizers",
+			resourceWeight:    1,
+			expectedDecisions: []string{"cluster3"},
+		},
+		{
+			name:              "bin-pack by the resource prioritizers",
+			annotations:       map[string]string{resource.SchedulingModeAnnotationKey: string(resource.SchedulingModeBinPack)},
+			resourceWeight:    1,
+			expectedDecisions: []string{"cluster1"},
+		},
+		{
+			name:              "bin-pack with the default prioritizers",
+			annotations:       map[string]string{resource.SchedulingModeAnnotationKey: string(resource.SchedulingModeBinPack)},
+			expectedDecisions: []string{"cluster1"},
+		},
+		{
+			name:         "invalid mode",
+			annotations:  map[string]string{resource.SchedulingModeAnnotationKey: "Pack"},
+			expectedCode: framework.Misconfigured,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			builder := testinghelpers.NewPlacementWithAnnotations(placementNamespace, placementName, c.annotations).WithNOC(1)
+			if c.resourceWeight != 0 {
+				builder = builder.WithPrioritizerPolicy(clusterapiv1beta1.PrioritizerPolicyModeAdditive).
+					WithPrioritizerConfig(PrioritizerResourceAllocatableCPU, c.resourceWeight)
+			}
+			placement := builder.Build()
+			clusterClient := clusterfake.NewSimpleClientset(placement)
+			s := NewPluginScheduler(testinghelpers.NewFakePluginHandle(t, clusterClient, placement))
+
+			result, status := s.Schedule(context.TODO(), placement, clusters)
+			if status.Code() != c.expectedCode {
+				t.Fatalf("expected status code %v, but got %v", c.expectedCode, status)
+			}
+			if status.IsError() {
+				return
+			}
+			var decisions []string
+			for _, cluster := range result.Decisions() {
+				decisions = append(decisions, cluster.Name)
+			}
+			if !reflect.DeepEqual(decisions, c.expectedDecisions) {
+				t.Errorf("expected decisions %v, but got %v", c.expectedDecisions, decisions)
+			}
+		})
+	}
+}
+
+func TestScheduleWithClusterSetPriority(t *testing.T) {
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").WithLabel(clusterapiv1beta2.ClusterSetLabel, "cloud").Build(),
+		testinghelpers.NewManagedCluster("cluster2").WithLabel(clusterapiv1beta2.ClusterSetLabel, "onprem").Build(),
+		testinghelpers.NewManagedCluster("cluster3").WithLabel(clusterapiv1beta2.ClusterSetLabel, "cloud").Build(),
+	}
+
+	cases := []struct {
+		name              string
+		annotations       map[string]string
+		noc               int32
+		expectedDecisions []string
+	}{
+		{
+			name:              "no clusterset priorities",
+			noc:               1,
+			expectedDecisions: []string{"cluster1"},
+		},
+		{
+			name:              "prefer the onprem clusterset",
+			annotations:       map[string]string{clustersetpriority.ClusterSetPrioritiesAnnotationKey: "onprem=100,cloud=0"},
+			noc:               1,
+			expectedDecisions: []string{"cluster2"},
+		},
+		{
+			name:              "burst to the cloud clusterset",
+			annotations:       map[string]string{clustersetpriority.ClusterSetPrioritiesAnnotationKey: "onprem=100,cloud=0"},
+			noc:               2,
+			expectedDecisions: []string{"cluster2", "cluster1"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			placement := testinghelpers.NewPlacementWithAnnotations(placementNamespace, placementName, c.annotations).
+				WithNOC(c.noc).Build()
+			clusterClient := clusterfake.NewSimpleClientset(placement)
+			s := NewPluginScheduler(testinghelpers.NewFakePluginHandle(t, clusterClient, placement,
+				testinghelpers.NewClusterSet("cloud").Build(), testinghelpers.NewClusterSet("onprem").Build()))
+
+			result, status := s.Schedule(context.TODO(), placement, clusters)
+			if status.IsError() {
+				t.Fatalf("unexpected status %v", status)
+			}
+			var decisions []string
+			for _, cluster := range result.Decisions() {
+				decisions = append(decisions, cluster.Name)
+			}
+			if !reflect.DeepEqual(decisions, c.expectedDecisions) {
+				t.Errorf("expected decisions %v, but got %v", c.expectedDecisions, decisions)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
@@ -26,6 +27,7 @@ import (
 	kevents "k8s.io/client-go/tools/events"
 	"k8s.io/klog/v2"
 
+	addoninformerv1alpha1 "open-cluster-management.io/api/client/addon/informers/externalversions/addon/v1alpha1"
 	clusterclient "open-cluster-management.io/api/client/cluster/clientset/versioned"
 	clusterinformerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
 	clusterinformerv1alpha1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1alpha1"
@@ -38,6 +40,8 @@ import (
 	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	clusterapiv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
@@ -50,6 +54,10 @@ const (
 	maxEventMessageLength    = 1000 //the event message can have at most 1024 characters, use 1000 as limitation here to keep some buffer
 )
 
+// PlacementConditionStaleScores is the condition type of the placement reporting whether any feasible cluster
+// has stale AddOnPlacementScores used by the placement. It is only set on the placements using the scores.
+const PlacementConditionStaleScores = "StaleScores"
+
 // decisionGroups groups the cluster decisions by group strategy
 type clusterDecisionGroups []clusterDecisionGroup
 
@@ -67,6 +75,9 @@ type schedulingController struct {
 	placementLister         clusterlisterv1beta1.PlacementLister
 	placementDecisionLister clusterlisterv1beta1.PlacementDecisionLister
 	scheduler               Scheduler
+	resultCache             *ScheduleResultCache
+	migrations              *migrationTracker
+	shard                   *Shard
 	recorder                kevents.EventRecorder
 }
 
@@ -80,12 +91,17 @@ func NewSchedulingController(
 	placementInformer clusterinformerv1beta1.PlacementInformer,
 	placementDecisionInformer clusterinformerv1beta1.PlacementDecisionInformer,
 	placementScoreInformer clusterinformerv1alpha1.AddOnPlacementScoreInformer,
+	addOnInformer addoninformerv1alpha1.ManagedClusterAddOnInformer,
 	scheduler Scheduler,
+	resultCache *ScheduleResultCache,
+	scoreDebounce time.Duration,
+	shard *Shard,
 	recorder events.Recorder, krecorder kevents.EventRecorder,
 ) factory.Controller {
 	syncCtx := factory.NewSyncContext(schedulingControllerName, recorder)
 
 	enQueuer := newEnqueuer(ctx, syncCtx.Queue(), clusterInformer, clusterSetInformer, placementInformer, clusterSetBindingInformer)
+	enQueuer.scoreDebounce = scoreDebounce
 
 	// build controller
 	c := &schedulingController{
@@ -97,6 +113,9 @@ func NewSchedulingController(
 		placementDecisionLister: placementDecisionInformer.Lister(),
 		recorder:                krecorder,
 		scheduler:               scheduler,
+		resultCache:             resultCache,
+		migrations:              newMigrationTracker(),
+		shard:                   shard,
 	}
 
 	// setup event handler for cluster informer.
@@ -148,11 +167,33 @@ func NewSchedulingController(
 
 	// setup event handler for placementscore informer
 	_, err = placementScoreInformer.Informer().AddEventHandler(&cache.ResourceEventHandlerFuncs{
-		AddFunc: enQueuer.enqueuePlacementScore,
+		AddFunc:    enQueuer.enqueuePlacementScore,
+		UpdateFunc: enQueuer.enqueuePlacementScoreUpdate,
+		DeleteFunc: enQueuer.enqueuePlacementScore,
+	})
+	if err != nil {
+		utilruntime.HandleError(err)
+	}
+
+	// setup event handler for placementdecision informer
+	// Once the decisions of a placement change, the placements declaring anti-affinity to it are enqueued.
+	_, err = placementDecisionInformer.Informer().AddEventHandler(&cache.ResourceEventHandlerFuncs{
+		AddFunc: enQueuer.enqueuePlacementDecision,
 		UpdateFunc: func(oldObj, newObj interface{}) {
-			enQueuer.enqueuePlacementScore(newObj)
+			enQueuer.enqueuePlacementDecision(newObj)
 		},
-		DeleteFunc: enQueuer.enqueuePlacementScore,
+		DeleteFunc: enQueuer.enqueuePlacementDecision,
+	})
+	if err != nil {
+		utilruntime.HandleError(err)
+	}
+
+	// setup event handler for managedclusteraddon informer
+	// Once an addon is installed, removed or changes its availability, the placements requiring it are enqueued.
+	_, err = addOnInformer.Informer().AddEventHandler(&cache.ResourceEventHandlerFuncs{
+		AddFunc:    enQueuer.enqueueAddOn,
+		UpdateFunc: enQueuer.enqueueAddOnUpdate,
+		DeleteFunc: enQueuer.enqueueAddOn,
 	})
 	if err != nil {
 		utilruntime.HandleError(err)
@@ -170,8 +211,9 @@ func NewSchedulingController(
 		},
 			queue.FileterByLabel(clusterapiv1beta1.PlacementLabel),
 			placementDecisionInformer.Informer()).
-		WithBareInformers(clusterInformer.Informer(), clusterSetInformer.Informer(), clusterSetBindingInformer.Informer(), placementScoreInformer.Informer()).
-		WithSync(c.sync).
+		WithBareInformers(clusterInformer.Informer(), clusterSetInformer.Informer(), clusterSetBindingInformer.Informer(), placementScoreInformer.Informer(),
+			addOnInformer.Informer()).
+		WithSync(logging.WithControllerLogger(schedulingControllerName, c.sync)).
 		ToController(schedulingControllerName, recorder)
 }
 
@@ -183,12 +225,22 @@ func (c *schedulingController) sync(ctx context.Context, syncCtx factory.SyncCon
 	placement, err := c.getPlacement(queueKey)
 	if errors.IsNotFound(err) {
 		// no work if placement is deleted
+		if c.resultCache != nil {
+			c.resultCache.delete(queueKey)
+		}
+		c.migrations.delete(queueKey)
 		return nil
 	}
 	if err != nil {
 		return err
 	}
 
+	// no work if the placement is scheduled by the controller of another shard
+	if !c.shard.Owns(placement) {
+		logger.V(4).Info("Skip placement in another shard", "queueKey", queueKey)
+		return nil
+	}
+
 	return c.syncPlacement(ctx, syncCtx, placement)
 }
 
@@ -221,7 +273,7 @@ func (c *schedulingController) syncPlacement(ctx context.Context, syncCtx factor
 	}
 
 	// get all valid clustersetbindings in the placement namespace
-	bindings, err := c.getValidManagedClusterSetBindings(placement.Namespace)
+	bindings, invalidBindings, err := c.getValidManagedClusterSetBindings(placement.Namespace)
 	if err != nil {
 		return err
 	}
@@ -236,9 +288,41 @@ func (c *schedulingController) syncPlacement(ctx context.Context, syncCtx factor
 	}
 
 	// schedule placement with scheduler
+	start := time.Now()
 	scheduleResult, status := c.scheduler.Schedule(ctx, placement, clusters)
+	recordScheduleMetrics(start, clusters, scheduleResult)
+	if c.resultCache != nil {
+		key, _ := cache.MetaNamespaceKeyFunc(placement)
+		c.resultCache.set(key, scheduleResult)
+	}
+	existing, err := getDecisionClusters(c.placementDecisionLister, placement)
+	if err != nil {
+		return err
+	}
+
+	// move the decisions gradually if the placement has a migration policy
+	selected := scheduleResult.Decisions()
+	policy, s := getMigrationPolicy(placement)
+	if s.IsError() {
+		status = s
+	}
+	var progress migrationProgress
+	if policy != nil {
+		key, _ := cache.MetaNamespaceKeyFunc(placement)
+		now := time.Now()
+		selected, progress = limitMigration(
+			policy, c.migrations.get(key), now, existing, selected, scheduleResult.FeasibleClusters())
+		if progress.migrated > 0 {
+			c.migrations.set(key, now)
+		}
+		if syncCtx != nil && progress.pending > 0 {
+			logger.V(4).Info("Requeue placement for the next migration batch", "placementKey", key, "time", progress.next)
+			syncCtx.Queue().AddAfter(key, progress.next.Sub(now))
+		}
+	}
+
 	// generate placement decision and status
-	decisions, groupStatus, s := c.generatePlacementDecisionsAndStatus(placement, scheduleResult.Decisions())
+	decisions, groupStatus, s := c.generatePlacementDecisionsAndStatus(placement, selected)
 	if s.IsError() {
 		status = s
 	}
@@ -247,12 +331,26 @@ func (c *schedulingController) syncPlacement(ctx context.Context, syncCtx factor
 		placement.Spec.ClusterSets,
 		clusterSetNames,
 		len(bindings),
+		invalidBindings,
 		len(clusters),
-		len(scheduleResult.Decisions()),
+		len(selected),
 		scheduleResult.NumOfUnscheduled(),
 		status,
 	)
 
+	conditions := []metav1.Condition{misconfiguredCondition, satisfiedCondition}
+	if usesAddOnScores(placement) {
+		conditions = append(conditions, newStaleScoresCondition(scheduleResult.NumOfStaleScoreClusters()))
+	}
+	if policy != nil {
+		conditions = append(conditions, newMigratingCondition(progress))
+	}
+
+	// report the change of the decisions
+	if churn := getDecisionChurn(existing, selected, scheduleResult); churn.changed() {
+		conditions = append(conditions, newRescheduledCondition(churn, time.Now()))
+	}
+
 	// requeue placement if requeueAfter is defined in scheduleResult
 	if syncCtx != nil && scheduleResult.RequeueAfter() != nil {
 		key, _ := cache.MetaNamespaceKeyFunc(placement)
@@ -268,38 +366,47 @@ func (c *schedulingController) syncPlacement(ctx context.Context, syncCtx factor
 	}
 
 	// update placement status if necessary to signal no bindings
-	if err := c.updateStatus(ctx, placement, groupStatus, int32(len(scheduleResult.Decisions())), misconfiguredCondition, satisfiedCondition); err != nil {
+	if err := c.updateStatus(ctx, placement, groupStatus, int32(len(selected)), conditions...); err != nil {
 		return err
 	}
 
 	return status.AsError()
 }
 
-// getManagedClusterSetBindings returns all bindings found in the placement namespace.
-func (c *schedulingController) getValidManagedClusterSetBindings(placementNamespace string) ([]*clusterapiv1beta2.ManagedClusterSetBinding, error) {
+// getManagedClusterSetBindings returns all valid bindings found in the placement namespace, and the reasons
+// why the other bindings are ignored.
+func (c *schedulingController) getValidManagedClusterSetBindings(
+	placementNamespace string) ([]*clusterapiv1beta2.ManagedClusterSetBinding, []string, error) {
 	// get all clusterset bindings under the placement namespace
 	bindings, err := c.clusterSetBindingLister.ManagedClusterSetBindings(placementNamespace).List(labels.Everything())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if len(bindings) == 0 {
 		bindings = nil
 	}
 
 	var validBindings []*clusterapiv1beta2.ManagedClusterSetBinding
+	var invalidBindings []string
 	for _, binding := range bindings {
 		// ignore clustersetbinding refers to a non-existent clusterset
 		_, err := c.clusterSetLister.Get(binding.Name)
 		if errors.IsNotFound(err) {
+			invalidBindings = append(invalidBindings, fmt.Sprintf("%s: ManagedClusterSet not found", binding.Name))
 			continue
 		}
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		// ignore clustersetbinding whose creator is not allowed to bind the clusterset anymore
+		if meta.IsStatusConditionTrue(binding.Status.Conditions, commonhelpers.ClusterSetBindingConditionPermissionDenied) {
+			invalidBindings = append(invalidBindings, fmt.Sprintf("%s: permission to bind the ManagedClusterSet denied", binding.Name))
+			continue
 		}
 		validBindings = append(validBindings, binding)
 	}
 
-	return validBindings, nil
+	return validBindings, invalidBindings, nil
 }
 
 // getEligibleClusterSets returns the names of clusterset that eligible for the placement
@@ -338,7 +445,7 @@ func (c *schedulingController) getAvailableClusters(clusterSetNames []string) ([
 		if err != nil {
 			return nil, err
 		}
-		clusters, err := clusterapiv1beta2.GetClustersFromClusterSet(clusterSet, c.clusterLister)
+		clusters, err := commonhelpers.GetClustersFromNestedClusterSet(clusterSet, c.clusterLister, c.clusterSetLister)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get clusterset: %v, clusters, Error: %v", clusterSet.Name, err)
 		}
@@ -375,6 +482,8 @@ func (c *schedulingController) updateStatus(
 		newPlacement.Status.DecisionGroups = append(newPlacement.Status.DecisionGroups, *status)
 	}
 
+	// the stale scores condition is set again if the placement still uses the scores
+	meta.RemoveStatusCondition(&newPlacement.Status.Conditions, PlacementConditionStaleScores)
 	for _, c := range conditions {
 		meta.SetStatusCondition(&newPlacement.Status.Conditions, c)
 	}
@@ -394,7 +503,8 @@ func (c *schedulingController) updateStatus(
 func newSatisfiedCondition(
 	clusterSetsInSpec []string,
 	eligibleClusterSets []string,
-	numOfBindings,
+	numOfBindings int,
+	invalidBindings []string,
 	numOfAvailableClusters,
 	numOfFeasibleClusters,
 	numOfUnscheduledDecisions int,
@@ -404,6 +514,11 @@ func newSatisfiedCondition(
 		Type: clusterapiv1beta1.PlacementConditionSatisfied,
 	}
 	switch {
+	case numOfBindings == 0 && len(invalidBindings) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NoBoundManagedClusterSetBindings"
+		condition.Message = fmt.Sprintf("No valid ManagedClusterSetBindings found in placement namespace, invalid bindings [%s]",
+			strings.Join(invalidBindings, "; "))
 	case numOfBindings == 0:
 		condition.Status = metav1.ConditionFalse
 		condition.Reason = "NoManagedClusterSetBindings"
@@ -412,6 +527,9 @@ func newSatisfiedCondition(
 		condition.Status = metav1.ConditionFalse
 		condition.Reason = "NoIntersection"
 		condition.Message = fmt.Sprintf("None of ManagedClusterSets [%s] is bound to placement namespace", strings.Join(clusterSetsInSpec, ","))
+		if len(invalidBindings) > 0 {
+			condition.Message += fmt.Sprintf(", invalid bindings [%s]", strings.Join(invalidBindings, "; "))
+		}
 	case numOfAvailableClusters == 0:
 		condition.Status = metav1.ConditionFalse
 		condition.Reason = "AllManagedClusterSetsEmpty"
@@ -436,6 +554,34 @@ func newSatisfiedCondition(
 	return condition
 }
 
+// newStaleScoresCondition returns a new condition with type PlacementConditionStaleScores
+func newStaleScoresCondition(numOfStaleScoreClusters int) metav1.Condition {
+	if numOfStaleScoreClusters > 0 {
+		return metav1.Condition{
+			Type:    PlacementConditionStaleScores,
+			Status:  metav1.ConditionTrue,
+			Reason:  "StaleScoresFound",
+			Message: fmt.Sprintf("%d clusters have stale AddOnPlacementScores", numOfStaleScoreClusters),
+		}
+	}
+	return metav1.Condition{
+		Type:    PlacementConditionStaleScores,
+		Status:  metav1.ConditionFalse,
+		Reason:  "NoStaleScores",
+		Message: "No cluster has stale AddOnPlacementScores",
+	}
+}
+
+// usesAddOnScores returns true if the prioritizer policy of the placement uses any AddOnPlacementScore.
+func usesAddOnScores(placement *clusterapiv1beta1.Placement) bool {
+	for _, config := range placement.Spec.PrioritizerPolicy.Configurations {
+		if config.ScoreCoordinate != nil && config.ScoreCoordinate.Type == clusterapiv1beta1.ScoreCoordinateTypeAddOn && config.Weight != 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func newMisconfiguredCondition(status *framework.Status) metav1.Condition {
 	if status.Code() == framework.Misconfigured {
 		return metav1.Condition{
@@ -466,12 +612,26 @@ func (c *schedulingController) generatePlacementDecisionsAndStatus(
 	// generate decision group
 	decisionGroups, status := c.generateDecisionGroups(placement, clusters)
 
+	// fall back to the default chunk size if the annotation is invalid, so the existing decisions are kept
+	chunkSize, s := getDecisionChunkSize(placement)
+	if s.IsError() {
+		chunkSize = maxNumOfClusterDecisions
+		if !status.IsError() {
+			status = s
+		}
+	}
+	existingChunks, err := getExistingDecisionChunks(c.placementDecisionLister, placement)
+	if err != nil {
+		return placementDecisions, decisionGroupStatus, framework.NewStatus("", framework.Error, err.Error())
+	}
+
 	// generate placement decision for each decision group
 	for decisionGroupIndex, decisionGroup := range decisionGroups {
 		// generate placement decisions and status, decision group index starts from 0
 		// placement name index starts from 1 to keep backward compatibility
 		// TODO: should be consistent with index or using a random generate name when version bumps
-		pds, groupStatus := c.generateDecision(placement, decisionGroup, decisionGroupIndex, placementDecisionIndex)
+		decisionSlices := chunkDecisions(decisionGroup.clusterDecisions, decisionGroupIndex, chunkSize, existingChunks)
+		pds, groupStatus := c.generateDecision(placement, decisionGroup, decisionSlices, decisionGroupIndex, placementDecisionIndex)
 
 		placementDecisions = append(placementDecisions, pds...)
 		decisionGroupStatus = append(decisionGroupStatus, groupStatus)
@@ -496,6 +656,12 @@ func (c *schedulingController) generateDecisionGroups(
 		return groups, status
 	}
 
+	// The group key, order and sizes customized by the annotations of the placement.
+	options, status := getDecisionGroupOptions(placement, len(clusters))
+	if status.IsError() {
+		return groups, status
+	}
+
 	// Record the cluster names
 	clusterNameSet := sets.New[string]()
 	for _, cluster := range clusters {
@@ -510,21 +676,39 @@ func (c *schedulingController) generateDecisionGroups(
 			return groups, status
 		}
 		// If matched clusters number meets groupLength, divide into multiple groups.
-		decisionGroups := divideDecisionGroups(d.GroupName, matched, groupLength)
+		decisionGroups := divideDecisionGroups(d.GroupName, matched, options.groupLength(d.GroupName, groupLength))
 		groups = append(groups, decisionGroups...)
 	}
 
-	// The rest of the clusters will also be put into decision groups.
-	var matched []clusterapiv1beta1.ClusterDecision
-	for _, cluster := range clusterNameSet.UnsortedList() {
-		matched = append(matched, clusterapiv1beta1.ClusterDecision{
-			ClusterName: cluster,
-		})
+	// The rest of the clusters will also be put into decision groups, which are keyed by the value of
+	// the group key if it is set.
+	restGroupNames := []string{""}
+	rest := map[string][]clusterapiv1beta1.ClusterDecision{}
+	if options.keyFunc == nil {
+		for _, cluster := range clusterNameSet.UnsortedList() {
+			rest[""] = append(rest[""], clusterapiv1beta1.ClusterDecision{
+				ClusterName: cluster,
+			})
+		}
+	} else {
+		for _, cluster := range clusters {
+			if !clusterNameSet.Has(cluster.Name) {
+				continue
+			}
+			value := options.keyFunc(cluster)
+			rest[value] = append(rest[value], clusterapiv1beta1.ClusterDecision{
+				ClusterName: cluster.Name,
+			})
+		}
+		restGroupNames = sets.List(sets.KeySet(rest))
 	}
 
 	// If the rest of clusters number meets groupLength, divide into multiple groups.
-	decisionGroups := divideDecisionGroups("", matched, groupLength)
-	groups = append(groups, decisionGroups...)
+	for _, name := range restGroupNames {
+		decisionGroups := divideDecisionGroups(name, rest[name], options.groupLength(name, groupLength))
+		groups = append(groups, decisionGroups...)
+	}
+	groups = options.orderDecisionGroups(groups)
 
 	// generate at least on empty decisionGroup, this is to ensure there's an empty placement decision if no cluster selected.
 	if len(groups) == 0 {
@@ -537,25 +721,9 @@ func (c *schedulingController) generateDecisionGroups(
 func (c *schedulingController) generateDecision(
 	placement *clusterapiv1beta1.Placement,
 	clusterDecisionGroup clusterDecisionGroup,
+	decisionSlices [][]clusterapiv1beta1.ClusterDecision,
 	decisionGroupIndex, placementDecisionIndex int,
 ) ([]*clusterapiv1beta1.PlacementDecision, *clusterapiv1beta1.DecisionGroupStatus) {
-	// split the cluster decisions into slices, the size of each slice cannot exceed
-	// maxNumOfClusterDecisions.
-	var decisionSlices [][]clusterapiv1beta1.ClusterDecision
-	remainingDecisions := clusterDecisionGroup.clusterDecisions
-	for index := 0; len(remainingDecisions) > 0; index++ {
-		var decisionSlice []clusterapiv1beta1.ClusterDecision
-		switch {
-		case len(remainingDecisions) > maxNumOfClusterDecisions:
-			decisionSlice = remainingDecisions[0:maxNumOfClusterDecisions]
-			remainingDecisions = remainingDecisions[maxNumOfClusterDecisions:]
-		default:
-			decisionSlice = remainingDecisions
-			remainingDecisions = nil
-		}
-		decisionSlices = append(decisionSlices, decisionSlice)
-	}
-
 	// if decisionSlices is empty, append one empty slice.
 	// so that can create a PlacementDecision with empty decisions in status.
 	if len(decisionSlices) == 0 {
@@ -696,6 +864,13 @@ func (c *schedulingController) createOrUpdatePlacementDecision(
 	// If status has been updated, just return, this is to avoid conflict when updating the label later.
 	// Labels and annotations will still be updated in next reconcile.
 	if updated {
+		if err == nil {
+			c.recorder.Eventf(
+				placement, existPlacementDecision, corev1.EventTypeNormal,
+				commonhelpers.EventReasonPlacementDecisionChanged, commonhelpers.EventActionSchedule,
+				"Decision %s of placement %s in namespace %s is changed to %d clusters",
+				existPlacementDecision.Name, placement.Name, placement.Namespace, len(clusterDecisions))
+		}
 		return err
 	}
 	_, err = placementDecisionPatcher.PatchLabelAnnotations(ctx, newPlacementDecision, newPlacementDecision.ObjectMeta, existPlacementDecision.ObjectMeta)
@@ -22,6 +22,7 @@ import (
 	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	clusterapiv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
 	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
 	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
@@ -584,6 +585,7 @@ func TestGetValidManagedClusterSetBindings(t *testing.T) {
 		name                           string
 		initObjs                       []runtime.Object
 		expectedClusterSetBindingNames []string
+		expectedInvalidBindings        []string
 	}{
 		{
 			name: "no bound clusterset",
@@ -596,6 +598,21 @@ func TestGetValidManagedClusterSetBindings(t *testing.T) {
 			initObjs: []runtime.Object{
 				testinghelpers.NewClusterSetBinding(placementNamespace, "clusterset1"),
 			},
+			expectedInvalidBindings: []string{"clusterset1: ManagedClusterSet not found"},
+		},
+		{
+			name: "permission denied binding",
+			initObjs: []runtime.Object{
+				testinghelpers.NewClusterSet("clusterset1").Build(),
+				func() *clusterapiv1beta2.ManagedClusterSetBinding {
+					binding := testinghelpers.NewClusterSetBinding(placementNamespace, "clusterset1")
+					binding.Status.Conditions = []metav1.Condition{
+						{Type: commonhelpers.ClusterSetBindingConditionPermissionDenied, Status: metav1.ConditionTrue},
+					}
+					return binding
+				}(),
+			},
+			expectedInvalidBindings: []string{"clusterset1: permission to bind the ManagedClusterSet denied"},
 		},
 		{
 			name: "valid binding",
@@ -616,10 +633,13 @@ func TestGetValidManagedClusterSetBindings(t *testing.T) {
 				clusterSetLister:        clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets().Lister(),
 				clusterSetBindingLister: clusterInformerFactory.Cluster().V1beta2().ManagedClusterSetBindings().Lister(),
 			}
-			bindings, err := ctrl.getValidManagedClusterSetBindings(placementNamespace)
+			bindings, invalidBindings, err := ctrl.getValidManagedClusterSetBindings(placementNamespace)
 			if err != nil {
 				t.Errorf("unexpected err: %v", err)
 			}
+			if !reflect.DeepEqual(invalidBindings, c.expectedInvalidBindings) {
+				t.Errorf("expected invalid bindings %v but got %v", c.expectedInvalidBindings, invalidBindings)
+			}
 
 			expectedBindingNames := sets.NewString(c.expectedClusterSetBindingNames...)
 			if len(bindings) != expectedBindingNames.Len() {
@@ -842,6 +862,7 @@ func TestNewSatisfiedCondition(t *testing.T) {
 		clusterSetsInSpec         []string
 		eligibleClusterSets       []string
 		numOfBindings             int
+		invalidBindings           []string
 		numOfAvailableClusters    int
 		numOfFeasibleClusters     int
 		numOfUnscheduledDecisions int
@@ -855,6 +876,14 @@ func TestNewSatisfiedCondition(t *testing.T) {
 			expectedStatus:            metav1.ConditionFalse,
 			expectedReason:            "NoManagedClusterSetBindings",
 		},
+		{
+			name:                      "NoBoundManagedClusterSetBindings",
+			numOfBindings:             0,
+			invalidBindings:           []string{"clusterset1: ManagedClusterSet not found"},
+			numOfUnscheduledDecisions: 5,
+			expectedStatus:            metav1.ConditionFalse,
+			expectedReason:            "NoBoundManagedClusterSetBindings",
+		},
 		{
 			name:                      "NoIntersection",
 			clusterSetsInSpec:         []string{"clusterset1"},
@@ -913,6 +942,7 @@ func TestNewSatisfiedCondition(t *testing.T) {
 				c.clusterSetsInSpec,
 				c.eligibleClusterSets,
 				c.numOfBindings,
+				c.invalidBindings,
 				c.numOfAvailableClusters,
 				c.numOfFeasibleClusters,
 				c.numOfUnscheduledDecisions,
@@ -0,0 +1,168 @@
+package scheduling
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	leaderelectionconverter "github.com/openshift/library-go/pkg/config/leaderelection"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+)
+
+// PlacementShardLabel is the label on the placement to assign it to a shard explicitly. The value is the
+// shard index, it is taken modulo the number of shards. The placements without the label are assigned to
+// the shards by the hash of their namespaces, so all the placements in a namespace are in the same shard.
+const PlacementShardLabel = "cluster.open-cluster-management.io/placement-shard"
+
+// Shard is the shard of the placements scheduled by a placement controller. The placements are split into
+// Count shards, and only the placements in the shard with Index are scheduled. The Index is set by the
+// ShardElector before the controllers are started.
+type Shard struct {
+	Count int
+	Index int
+}
+
+// Owns returns true if the placement is in the shard. A nil shard or a shard with Count less than 2 owns
+// all the placements.
+func (s *Shard) Owns(placement *clusterapiv1beta1.Placement) bool {
+	if s == nil || s.Count < 2 {
+		return true
+	}
+	return s.of(placement) == s.Index
+}
+
+func (s *Shard) of(placement *clusterapiv1beta1.Placement) int {
+	if value, ok := placement.Labels[PlacementShardLabel]; ok {
+		if index, err := strconv.Atoi(value); err == nil && index >= 0 {
+			return index % s.Count
+		}
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(placement.Namespace))
+	return int(h.Sum32() % uint32(s.Count))
+}
+
+// ShardLeaseName returns the name of the lease held by the placement controller scheduling the shard.
+func ShardLeaseName(index int) string {
+	return fmt.Sprintf("placement-shard-%d", index)
+}
+
+// ShardElector elects the shard scheduled by a replica of the placement controller. The replicas share the same
+// arguments, so each replica competes for the leases of all the shards and holds at most one of them. The shards
+// are spread across the replicas in this way, and a replica without a shard takes over the shard of a failed
+// replica once its lease is released or expired.
+type ShardElector struct {
+	kubeClient    kubernetes.Interface
+	namespace     string
+	identity      string
+	shard         *Shard
+	leaseDuration time.Duration
+	renewDeadline time.Duration
+	retryPeriod   time.Duration
+}
+
+// NewShardElector returns the elector of the shard, the leases are in the namespace and the Index of the shard is set
+// once a lease is held.
+func NewShardElector(kubeClient kubernetes.Interface, namespace string, shard *Shard) *ShardElector {
+	config := leaderelectionconverter.LeaderElectionDefaulting(configv1.LeaderElection{}, namespace, "")
+	identity := string(uuid.NewUUID())
+	if hostname, err := os.Hostname(); err == nil {
+		identity = hostname + "_" + identity
+	}
+	return &ShardElector{
+		kubeClient:    kubeClient,
+		namespace:     config.Namespace,
+		identity:      identity,
+		shard:         shard,
+		leaseDuration: config.LeaseDuration.Duration,
+		renewDeadline: config.RenewDeadline.Duration,
+		retryPeriod:   config.RetryPeriod.Duration,
+	}
+}
+
+// Run competes for the leases of the shards until one of them is held, and then runs the controllers of the shard
+// until the lease is lost. An error is returned if the lease is lost before the context is done, so the replica is
+// restarted without scheduling the shard any more.
+func (e *ShardElector) Run(ctx context.Context, run func(ctx context.Context)) error {
+	var lock sync.Mutex
+	elected := -1
+	electors := make([]*leaderelection.LeaderElector, e.shard.Count)
+	cancels := make([]context.CancelFunc, e.shard.Count)
+	contexts := make([]context.Context, e.shard.Count)
+	for i := range electors {
+		index := i
+		contexts[index], cancels[index] = context.WithCancel(ctx)
+		defer cancels[index]()
+
+		elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+			Lock: &resourcelock.LeaseLock{
+				LeaseMeta:  metav1.ObjectMeta{Namespace: e.namespace, Name: ShardLeaseName(index)},
+				Client:     e.kubeClient.CoordinationV1(),
+				LockConfig: resourcelock.ResourceLockConfig{Identity: e.identity},
+			},
+			ReleaseOnCancel: true,
+			LeaseDuration:   e.leaseDuration,
+			RenewDeadline:   e.renewDeadline,
+			RetryPeriod:     e.retryPeriod,
+			Name:            ShardLeaseName(index),
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: func(ctx context.Context) {
+					lock.Lock()
+					if elected >= 0 {
+						lock.Unlock()
+						// release the lease, since the replica schedules another shard.
+						cancels[index]()
+						return
+					}
+					elected = index
+					for i, cancel := range cancels {
+						if i != index {
+							cancel()
+						}
+					}
+					e.shard.Index = index
+					lock.Unlock()
+
+					klog.Infof("Scheduling the placements of shard %d of %d", index, e.shard.Count)
+					run(ctx)
+				},
+				OnStoppedLeading: func() {},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		electors[index] = elector
+	}
+
+	var wg sync.WaitGroup
+	for i := range electors {
+		index := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			electors[index].Run(contexts[index])
+		}()
+	}
+	wg.Wait()
+
+	lock.Lock()
+	defer lock.Unlock()
+	if elected >= 0 && ctx.Err() == nil {
+		return fmt.Errorf("the lease %s is lost", ShardLeaseName(elected))
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package scheduling
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestShardOwns(t *testing.T) {
+	var nilShard *Shard
+	if !nilShard.Owns(testinghelpers.NewPlacement("ns1", "placement1").Build()) {
+		t.Errorf("expected a nil shard to own all the placements")
+	}
+
+	shards := []*Shard{{Count: 3, Index: 0}, {Count: 3, Index: 1}, {Count: 3, Index: 2}}
+	owners := func(namespace, name string, labels map[string]string) []int {
+		placement := testinghelpers.NewPlacement(namespace, name).Build()
+		placement.Labels = labels
+		var owners []int
+		for _, shard := range shards {
+			if shard.Owns(placement) {
+				owners = append(owners, shard.Index)
+			}
+		}
+		return owners
+	}
+
+	// each placement is owned by exactly one shard, and the placements in a namespace are in the same shard
+	for i := 0; i < 10; i++ {
+		namespace := fmt.Sprintf("ns%d", i)
+		o1 := owners(namespace, "placement1", nil)
+		o2 := owners(namespace, "placement2", nil)
+		if len(o1) != 1 || len(o2) != 1 || o1[0] != o2[0] {
+			t.Errorf("expected placements in namespace %s owned by the same shard, but got %v and %v", namespace, o1, o2)
+		}
+	}
+
+	// the shard label assigns the placement explicitly
+	if o := owners("ns1", "placement1", map[string]string{PlacementShardLabel: "4"}); len(o) != 1 || o[0] != 1 {
+		t.Errorf("expected the placement owned by shard 1, but got %v", o)
+	}
+
+	// an invalid shard label is ignored
+	if o := owners("ns1", "placement1", map[string]string{PlacementShardLabel: "invalid"}); len(o) != 1 {
+		t.Errorf("expected the placement owned by one shard, but got %v", o)
+	}
+}
+
+func TestShardElector(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var lock sync.Mutex
+	running := map[string]int{}
+	type replica struct {
+		shard  *Shard
+		cancel context.CancelFunc
+		done   chan error
+	}
+	startReplica := func(name string) *replica {
+		r := &replica{shard: &Shard{Count: 2}, done: make(chan error, 1)}
+		elector := NewShardElector(kubeClient, "open-cluster-management-hub", r.shard)
+		elector.identity = name
+		elector.leaseDuration = 2 * time.Second
+		elector.renewDeadline = time.Second
+		elector.retryPeriod = 100 * time.Millisecond
+
+		var replicaCtx context.Context
+		replicaCtx, r.cancel = context.WithCancel(ctx)
+		go func() {
+			r.done <- elector.Run(replicaCtx, func(ctx context.Context) {
+				lock.Lock()
+				running[name] = r.shard.Index
+				lock.Unlock()
+				<-ctx.Done()
+				lock.Lock()
+				delete(running, name)
+				lock.Unlock()
+			})
+		}()
+		return r
+	}
+	waitRunning := func(name string) int {
+		index := -1
+		if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 10*time.Second, true,
+			func(ctx context.Context) (bool, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				var ok bool
+				index, ok = running[name]
+				return ok, nil
+			}); err != nil {
+			t.Fatalf("expected replica %s scheduling a shard, but got %v", name, running)
+		}
+		return index
+	}
+
+	// the replicas started one by one hold different shards, and the extra replica is standby.
+	replica1 := startReplica("replica1")
+	index1 := waitRunning("replica1")
+	startReplica("replica2")
+	index2 := waitRunning("replica2")
+	if index1 == index2 {
+		t.Fatalf("expected the replicas scheduling different shards, but both got shard %d", index1)
+	}
+	startReplica("replica3")
+	time.Sleep(time.Second)
+	lock.Lock()
+	if index, ok := running["replica3"]; ok {
+		t.Errorf("expected replica3 standby, but it got shard %d", index)
+	}
+	lock.Unlock()
+
+	// the standby replica takes over the shard of the stopped replica.
+	replica1.cancel()
+	if err := <-replica1.done; err != nil {
+		t.Errorf("expected no error after the replica is stopped, but got %v", err)
+	}
+	if index3 := waitRunning("replica3"); index3 != index1 {
+		t.Errorf("expected replica3 scheduling shard %d, but got shard %d", index1, index3)
+	}
+}
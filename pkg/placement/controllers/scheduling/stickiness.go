@@ -0,0 +1,60 @@
+package scheduling
+
+import (
+	"fmt"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterlisterv1beta1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+)
+
+// ReschedulingScoreThresholdAnnotationKey is the annotation on the placement to make the existing decisions
+// sticky. A cluster in the existing decisions is replaced by another cluster only if the total score of the
+// other cluster is greater than the total score of the existing one plus the threshold, or the existing one
+// becomes infeasible. The total score is the sum of the prioritizer scores multiplied by their weights.
+const ReschedulingScoreThresholdAnnotationKey = "cluster.open-cluster-management.io/rescheduling-score-threshold"
+
+// getReschedulingScoreThreshold returns the rescheduling score threshold of the placement, it returns 0 if the
+// annotation is not set.
+func getReschedulingScoreThreshold(placement *clusterapiv1beta1.Placement) (int64, *framework.Status) {
+	value, ok := placement.GetAnnotations()[ReschedulingScoreThresholdAnnotationKey]
+	if !ok {
+		return 0, framework.NewStatus("", framework.Success, "")
+	}
+
+	threshold, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || threshold < 0 {
+		return 0, framework.NewStatus("", framework.Misconfigured,
+			fmt.Sprintf("invalid value %q of annotation %s, it should be a non-negative integer",
+				value, ReschedulingScoreThresholdAnnotationKey))
+	}
+	return threshold, framework.NewStatus("", framework.Success, "")
+}
+
+// getDecisionClusters returns the clusters in the existing decisions of the placement.
+func getDecisionClusters(
+	decisionLister clusterlisterv1beta1.PlacementDecisionLister,
+	placement *clusterapiv1beta1.Placement) (sets.Set[string], error) {
+	requirement, err := labels.NewRequirement(clusterapiv1beta1.PlacementLabel, selection.Equals, []string{placement.Name})
+	if err != nil {
+		return nil, err
+	}
+	decisions, err := decisionLister.PlacementDecisions(placement.Namespace).List(labels.NewSelector().Add(*requirement))
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := sets.New[string]()
+	for _, decision := range decisions {
+		for _, d := range decision.Status.Decisions {
+			clusters.Insert(d.ClusterName)
+		}
+	}
+	return clusters, nil
+}
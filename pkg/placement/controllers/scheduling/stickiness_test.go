@@ -0,0 +1,91 @@
+package scheduling
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestScheduleWithReschedulingScoreThreshold(t *testing.T) {
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").WithResource(clusterapiv1.ResourceMemory, "100", "100").Build(),
+		testinghelpers.NewManagedCluster("cluster2").WithResource(clusterapiv1.ResourceMemory, "0", "100").Build(),
+		testinghelpers.NewManagedCluster("cluster3").WithResource(clusterapiv1.ResourceMemory, "50", "100").Build(),
+	}
+	existingDecision := testinghelpers.NewPlacementDecision(placementNamespace, testinghelpers.PlacementDecisionName(placementName, 1)).
+		WithLabel(clusterapiv1beta1.PlacementLabel, placementName).
+		WithDecisions("cluster3").Build()
+
+	cases := []struct {
+		name              string
+		annotations       map[string]string
+		initObjs          []runtime.Object
+		expectedCode      framework.Code
+		expectedDecisions []string
+	}{
+		{
+			name:              "no threshold",
+			initObjs:          []runtime.Object{existingDecision},
+			expectedDecisions: []string{"cluster1"},
+		},
+		{
+			name:              "the score does not beat the incumbent by the threshold",
+			annotations:       map[string]string{ReschedulingScoreThresholdAnnotationKey: "100"},
+			initObjs:          []runtime.Object{existingDecision},
+			expectedDecisions: []string{"cluster3"},
+		},
+		{
+			name:              "the score beats the incumbent by the threshold",
+			annotations:       map[string]string{ReschedulingScoreThresholdAnnotationKey: "99"},
+			initObjs:          []runtime.Object{existingDecision},
+			expectedDecisions: []string{"cluster1"},
+		},
+		{
+			name:              "no existing decisions",
+			annotations:       map[string]string{ReschedulingScoreThresholdAnnotationKey: "100"},
+			expectedDecisions: []string{"cluster1"},
+		},
+		{
+			name:         "invalid threshold",
+			annotations:  map[string]string{ReschedulingScoreThresholdAnnotationKey: "-1"},
+			initObjs:     []runtime.Object{existingDecision},
+			expectedCode: framework.Misconfigured,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			placement := testinghelpers.NewPlacementWithAnnotations(placementNamespace, placementName, c.annotations).
+				WithNOC(1).
+				WithPrioritizerPolicy(clusterapiv1beta1.PrioritizerPolicyModeExact).
+				WithPrioritizerConfig(PrioritizerResourceAllocatableMemory, 1).Build()
+			initObjs := append([]runtime.Object{placement}, c.initObjs...)
+			clusterClient := clusterfake.NewSimpleClientset(initObjs...)
+			s := NewPluginScheduler(testinghelpers.NewFakePluginHandle(t, clusterClient, initObjs...))
+
+			result, status := s.Schedule(context.TODO(), placement, clusters)
+			if status.Code() != c.expectedCode {
+				t.Fatalf("expected status code %v, but got %v", c.expectedCode, status)
+			}
+			if c.expectedCode != framework.Success {
+				return
+			}
+
+			var decisions []string
+			for _, cluster := range result.Decisions() {
+				decisions = append(decisions, cluster.Name)
+			}
+			if len(decisions) != len(c.expectedDecisions) || decisions[0] != c.expectedDecisions[0] {
+				t.Errorf("expected decisions %v, but got %v", c.expectedDecisions, decisions)
+			}
+		})
+	}
+}
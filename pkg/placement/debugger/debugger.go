@@ -2,6 +2,8 @@ package debugger
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
@@ -12,15 +14,22 @@ import (
 	clusterinformerv1beta1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1beta1"
 	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
 	clusterlisterv1beta1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 
 	"open-cluster-management.io/ocm/pkg/placement/controllers/scheduling"
 )
 
-const DebugPath = "/debug/placements/"
+const (
+	DebugPath = "/debug/placements/"
+
+	// maxDryRunBodySize limits the size of the placement posted to the debugger
+	maxDryRunBodySize = 1 << 20
+)
 
 // Debugger provides a debug http endpoint for scheduler
 type Debugger struct {
 	scheduler       scheduling.Scheduler
+	resultCache     *scheduling.ScheduleResultCache
 	clusterLister   clusterlisterv1.ManagedClusterLister
 	placementLister clusterlisterv1beta1.PlacementLister
 }
@@ -29,20 +38,29 @@ type Debugger struct {
 type DebugResult struct {
 	FilterResults     []scheduling.FilterResult      `json:"filteredPiplieResults,omitempty"`
 	PrioritizeResults []scheduling.PrioritizerResult `json:"prioritizeResults,omitempty"`
+	Decisions         []string                       `json:"decisions,omitempty"`
+	NumOfUnscheduled  int                            `json:"numOfUnscheduled,omitempty"`
 	Error             string                         `json:"error,omitempty"`
 }
 
 func NewDebugger(
 	scheduler scheduling.Scheduler,
+	resultCache *scheduling.ScheduleResultCache,
 	placementInformer clusterinformerv1beta1.PlacementInformer,
 	clusterInformer clusterinformerv1.ManagedClusterInformer) *Debugger {
 	return &Debugger{
 		scheduler:       scheduler,
+		resultCache:     resultCache,
 		clusterLister:   clusterInformer.Lister(),
 		placementLister: placementInformer.Lister(),
 	}
 }
 
+// Handler returns the schedule result of a placement. With a GET request, the placement in the path is
+// scheduled. With a POST request, the placement in the request body is scheduled as a dry run, so the result
+// of a placement could be checked before it is created or changed. The decisions are not created or updated
+// in both cases. With a GET request and the query "last=true", the result of the last scheduling pass of the
+// placement made by the scheduling controller is returned.
 func (d *Debugger) Handler(w http.ResponseWriter, r *http.Request) {
 	namespace, name, err := d.parsePath(r.URL.Path)
 	if err != nil {
@@ -50,7 +68,18 @@ func (d *Debugger) Handler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	placement, err := d.placementLister.Placements(namespace).Get(name)
+	if r.Method != http.MethodPost && r.URL.Query().Get("last") == "true" {
+		d.lastResult(w, namespace, name)
+		return
+	}
+
+	var placement *clusterapiv1beta1.Placement
+	switch r.Method {
+	case http.MethodPost:
+		placement, err = d.decodePlacement(r, namespace, name)
+	default:
+		placement, err = d.placementLister.Placements(namespace).Get(name)
+	}
 	if err != nil {
 		d.reportErr(w, err)
 		return
@@ -62,15 +91,59 @@ func (d *Debugger) Handler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	scheduleResults, _ := d.scheduler.Schedule(r.Context(), placement, clusters)
+	scheduleResults, status := d.scheduler.Schedule(r.Context(), placement, clusters)
 
-	result := DebugResult{FilterResults: scheduleResults.FilterResults(), PrioritizeResults: scheduleResults.PrioritizerResults()}
+	result := newDebugResult(scheduleResults)
+	if status.IsError() {
+		result.Error = status.AsError().Error()
+	}
 
 	resultByte, _ := json.Marshal(result)
 
 	_, _ = w.Write(resultByte)
 }
 
+func (d *Debugger) lastResult(w http.ResponseWriter, namespace, name string) {
+	key := namespace + "/" + name
+	if d.resultCache == nil {
+		d.reportErr(w, fmt.Errorf("the schedule results are not recorded"))
+		return
+	}
+	scheduleResults, ok := d.resultCache.Get(key)
+	if !ok {
+		d.reportErr(w, fmt.Errorf("no schedule result is recorded for placement %s", key))
+		return
+	}
+
+	resultByte, _ := json.Marshal(newDebugResult(scheduleResults))
+
+	_, _ = w.Write(resultByte)
+}
+
+func newDebugResult(scheduleResults scheduling.ScheduleResult) DebugResult {
+	result := DebugResult{
+		FilterResults:     scheduleResults.FilterResults(),
+		PrioritizeResults: scheduleResults.PrioritizerResults(),
+		NumOfUnscheduled:  scheduleResults.NumOfUnscheduled(),
+	}
+	for _, cluster := range scheduleResults.Decisions() {
+		result.Decisions = append(result.Decisions, cluster.Name)
+	}
+	return result
+}
+
+// decodePlacement decodes the placement in the request body, the namespace and name of the placement
+// are always the ones in the path.
+func (d *Debugger) decodePlacement(r *http.Request, namespace, name string) (*clusterapiv1beta1.Placement, error) {
+	placement := &clusterapiv1beta1.Placement{}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxDryRunBodySize)).Decode(placement); err != nil {
+		return nil, fmt.Errorf("failed to decode the placement: %w", err)
+	}
+	placement.Namespace = namespace
+	placement.Name = name
+	return placement, nil
+}
+
 func (d *Debugger) parsePath(path string) (string, string, error) {
 	metaNamespaceKey := strings.TrimPrefix(path, DebugPath)
 	return cache.SplitMetaNamespaceKey(metaNamespaceKey)
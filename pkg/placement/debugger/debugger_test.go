@@ -1,6 +1,7 @@
 package debugger
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -23,13 +24,15 @@ import (
 )
 
 type testScheduler struct {
-	result *testResult
+	result    *testResult
+	placement *clusterapiv1beta1.Placement
 }
 
 type testResult struct {
 	filterResults     []scheduling.FilterResult
 	prioritizeResults []scheduling.PrioritizerResult
 	scoreSum          scheduling.PrioritizerScore
+	decisions         []*clusterapiv1.ManagedCluster
 }
 
 func (r *testResult) FilterResults() []scheduling.FilterResult {
@@ -45,6 +48,10 @@ func (r *testResult) PrioritizerScores() scheduling.PrioritizerScore {
 }
 
 func (r *testResult) Decisions() []*clusterapiv1.ManagedCluster {
+	return r.decisions
+}
+
+func (r *testResult) FeasibleClusters() []*clusterapiv1.ManagedCluster {
 	return []*clusterapiv1.ManagedCluster{}
 }
 
@@ -52,10 +59,15 @@ func (r *testResult) NumOfUnscheduled() int {
 	return 0
 }
 
+func (r *testResult) NumOfStaleScoreClusters() int {
+	return 0
+}
+
 func (s *testScheduler) Schedule(ctx context.Context,
 	placement *clusterapiv1beta1.Placement,
 	clusters []*clusterapiv1.ManagedCluster,
 ) (scheduling.ScheduleResult, *framework.Status) {
+	s.placement = placement
 	return s.result, nil
 }
 
@@ -94,7 +106,7 @@ func TestDebugger(t *testing.T) {
 			clusterInformerFactory := testinghelpers.NewClusterInformerFactory(clusterClient, c.initObjs...)
 			s := &testScheduler{result: &testResult{filterResults: c.filterResults, prioritizeResults: c.prioritizeResults}}
 			debugger := NewDebugger(
-				s, clusterInformerFactory.Cluster().V1beta1().Placements(), clusterInformerFactory.Cluster().V1().ManagedClusters())
+				s, nil, clusterInformerFactory.Cluster().V1beta1().Placements(), clusterInformerFactory.Cluster().V1().ManagedClusters())
 			server := httptest.NewServer(http.HandlerFunc(debugger.Handler))
 			res, err := http.Get(fmt.Sprintf("%s%s%s", server.URL, DebugPath, c.key))
 
@@ -125,3 +137,102 @@ func TestDebugger(t *testing.T) {
 		})
 	}
 }
+
+func TestDebuggerDryRun(t *testing.T) {
+	initObjs := []runtime.Object{
+		testinghelpers.NewPlacement("test", "test").WithNOC(3).Build(),
+		testinghelpers.NewManagedCluster("cluster1").Build(),
+		testinghelpers.NewManagedCluster("cluster2").Build(),
+	}
+	clusterClient := clusterfake.NewSimpleClientset(initObjs...)
+	clusterInformerFactory := testinghelpers.NewClusterInformerFactory(clusterClient, initObjs...)
+	s := &testScheduler{result: &testResult{
+		filterResults: []scheduling.FilterResult{{Name: "filter1", FilteredClusters: []string{"cluster1"}}},
+		decisions:     []*clusterapiv1.ManagedCluster{testinghelpers.NewManagedCluster("cluster1").Build()},
+	}}
+	debugger := NewDebugger(
+		s, nil, clusterInformerFactory.Cluster().V1beta1().Placements(), clusterInformerFactory.Cluster().V1().ManagedClusters())
+	server := httptest.NewServer(http.HandlerFunc(debugger.Handler))
+	defer server.Close()
+
+	// the placement in the body is scheduled instead of the existing one
+	body, _ := json.Marshal(testinghelpers.NewPlacement("other", "other").WithNOC(1).Build())
+	res, err := http.Post(fmt.Sprintf("%s%stest/test", server.URL, DebugPath), "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Expect no error but get %v", err)
+	}
+	defer res.Body.Close()
+
+	responseBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("Unexpected error reading response body: %v", err)
+	}
+	result := &DebugResult{}
+	if err := json.Unmarshal(responseBody, result); err != nil {
+		t.Fatalf("Unexpected error unmarshaling reulst: %v", err)
+	}
+
+	if s.placement.Namespace != "test" || s.placement.Name != "test" || *s.placement.Spec.NumberOfClusters != 1 {
+		t.Errorf("Expect the posted placement to be scheduled, but got %v", s.placement)
+	}
+	if !reflect.DeepEqual(result.Decisions, []string{"cluster1"}) {
+		t.Errorf("Expect decisions to be [cluster1], but got %v", result.Decisions)
+	}
+	if !reflect.DeepEqual(result.FilterResults, s.result.filterResults) {
+		t.Errorf("Expect filter result to be: %v. but got: %v", s.result.filterResults, result.FilterResults)
+	}
+
+	// invalid placement in the body
+	res, err = http.Post(fmt.Sprintf("%s%stest/test", server.URL, DebugPath), "application/json", bytes.NewReader([]byte("{")))
+	if err != nil {
+		t.Fatalf("Expect no error but get %v", err)
+	}
+	defer res.Body.Close()
+	responseBody, err = io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("Unexpected error reading response body: %v", err)
+	}
+	result = &DebugResult{}
+	if err := json.Unmarshal(responseBody, result); err != nil {
+		t.Fatalf("Unexpected error unmarshaling reulst: %v", err)
+	}
+	if len(result.Error) == 0 {
+		t.Errorf("Expect an error for the invalid placement")
+	}
+}
+
+func TestDebuggerLastResult(t *testing.T) {
+	initObjs := []runtime.Object{
+		testinghelpers.NewPlacement("test", "test").Build(),
+	}
+	clusterClient := clusterfake.NewSimpleClientset(initObjs...)
+	clusterInformerFactory := testinghelpers.NewClusterInformerFactory(clusterClient, initObjs...)
+	s := &testScheduler{result: &testResult{}}
+	debugger := NewDebugger(
+		s, scheduling.NewScheduleResultCache(),
+		clusterInformerFactory.Cluster().V1beta1().Placements(), clusterInformerFactory.Cluster().V1().ManagedClusters())
+	server := httptest.NewServer(http.HandlerFunc(debugger.Handler))
+	defer server.Close()
+
+	// no result is recorded by the scheduling controller yet
+	res, err := http.Get(fmt.Sprintf("%s%stest/test?last=true", server.URL, DebugPath))
+	if err != nil {
+		t.Fatalf("Expect no error but get %v", err)
+	}
+	defer res.Body.Close()
+
+	responseBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("Unexpected error reading response body: %v", err)
+	}
+	result := &DebugResult{}
+	if err := json.Unmarshal(responseBody, result); err != nil {
+		t.Fatalf("Unexpected error unmarshaling reulst: %v", err)
+	}
+	if len(result.Error) == 0 {
+		t.Errorf("Expect an error when no schedule result is recorded")
+	}
+	if s.placement != nil {
+		t.Errorf("Expect the placement not to be scheduled, but got %v", s.placement)
+	}
+}
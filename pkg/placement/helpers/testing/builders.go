@@ -260,6 +260,14 @@ func (b *ManagedClusterBuilder) WithLabel(name, value string) *ManagedClusterBui
 	return b
 }
 
+func (b *ManagedClusterBuilder) WithAnnotation(name, value string) *ManagedClusterBuilder {
+	if b.cluster.Annotations == nil {
+		b.cluster.Annotations = map[string]string{}
+	}
+	b.cluster.Annotations[name] = value
+	return b
+}
+
 func (b *ManagedClusterBuilder) WithClaim(name, value string) *ManagedClusterBuilder {
 	claimMap := map[string]string{}
 	for _, claim := range b.cluster.Status.ClusterClaims {
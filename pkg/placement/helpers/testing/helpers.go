@@ -6,11 +6,14 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	kevents "k8s.io/client-go/tools/events"
 
+	addonfake "open-cluster-management.io/api/client/addon/clientset/versioned/fake"
+	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
 	clusterclient "open-cluster-management.io/api/client/cluster/clientset/versioned"
 	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
 	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
 	clusterlisterv1alpha1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1alpha1"
 	clusterlisterv1beta1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
+	clusterlisterv1beta2 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta2"
 )
 
 type FakePluginHandle struct {
@@ -18,6 +21,8 @@ type FakePluginHandle struct {
 	placementDecisionLister clusterlisterv1beta1.PlacementDecisionLister
 	scoreLister             clusterlisterv1alpha1.AddOnPlacementScoreLister
 	clusterLister           clusterlisterv1.ManagedClusterLister
+	addOnLister             addonlisterv1alpha1.ManagedClusterAddOnLister
+	clusterSetLister        clusterlisterv1beta2.ManagedClusterSetLister
 	client                  clusterclient.Interface
 }
 
@@ -31,18 +36,27 @@ func (f *FakePluginHandle) ScoreLister() clusterlisterv1alpha1.AddOnPlacementSco
 func (f *FakePluginHandle) ClusterLister() clusterlisterv1.ManagedClusterLister {
 	return f.clusterLister
 }
+func (f *FakePluginHandle) AddOnLister() addonlisterv1alpha1.ManagedClusterAddOnLister {
+	return f.addOnLister
+}
+func (f *FakePluginHandle) ClusterSetLister() clusterlisterv1beta2.ManagedClusterSetLister {
+	return f.clusterSetLister
+}
 func (f *FakePluginHandle) ClusterClient() clusterclient.Interface {
 	return f.client
 }
 
 func NewFakePluginHandle(
-	t *testing.T, client *clusterfake.Clientset, objects ...runtime.Object) *FakePluginHandle {
+	t testing.TB, client *clusterfake.Clientset, objects ...runtime.Object) *FakePluginHandle {
 	informers := NewClusterInformerFactory(client, objects...)
+	addOnInformers := NewAddOnInformerFactory(addonfake.NewSimpleClientset(), objects...)
 	return &FakePluginHandle{
 		recorder:                kevents.NewFakeRecorder(100),
 		client:                  client,
 		placementDecisionLister: informers.Cluster().V1beta1().PlacementDecisions().Lister(),
 		scoreLister:             informers.Cluster().V1alpha1().AddOnPlacementScores().Lister(),
 		clusterLister:           informers.Cluster().V1().ManagedClusters().Lister(),
+		addOnLister:             addOnInformers.Addon().V1alpha1().ManagedClusterAddOns().Lister(),
+		clusterSetLister:        informers.Cluster().V1beta2().ManagedClusterSets().Lister(),
 	}
 }
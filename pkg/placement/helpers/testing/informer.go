@@ -5,6 +5,9 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonclient "open-cluster-management.io/api/client/addon/clientset/versioned"
+	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
 	clusterclient "open-cluster-management.io/api/client/cluster/clientset/versioned"
 	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
 	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
@@ -41,3 +44,16 @@ func NewClusterInformerFactory(clusterClient clusterclient.Interface, objects ..
 
 	return clusterInformerFactory
 }
+
+func NewAddOnInformerFactory(addOnClient addonclient.Interface, objects ...runtime.Object) addoninformers.SharedInformerFactory {
+	addOnInformerFactory := addoninformers.NewSharedInformerFactory(addOnClient, time.Minute*10)
+	addOnStore := addOnInformerFactory.Addon().V1alpha1().ManagedClusterAddOns().Informer().GetStore()
+
+	for _, obj := range objects {
+		if addOn, ok := obj.(*addonapiv1alpha1.ManagedClusterAddOn); ok {
+			_ = addOnStore.Add(addOn)
+		}
+	}
+
+	return addOnInformerFactory
+}
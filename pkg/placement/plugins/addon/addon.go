@@ -19,7 +19,9 @@ const (
 	description    = `
 	Customize prioritizer get cluster scores from AddOnPlacementScores with sepcific
 	resource name and score name. The clusters which doesn't have corresponding
-	AddOnPlacementScores resource or has expired score is given score 0.
+	AddOnPlacementScores resource is given score 0. The clusters which has expired
+	score is given score 0, or the last value of the score within the hold duration
+	if the stale score policy of the placement is HoldLastValue.
 	`
 )
 
@@ -74,6 +76,12 @@ func (c *AddOn) Score(ctx context.Context, placement *clusterapiv1beta1.Placemen
 	expiredScores := ""
 	status := framework.NewStatus(c.Name(), framework.Success, "")
 
+	policy, hold, err := GetStaleScorePolicy(placement)
+	if err != nil {
+		return plugins.PluginScoreResult{}, framework.NewStatus(c.Name(), framework.Misconfigured, err.Error())
+	}
+	now := AddOnClock.Now()
+
 	for _, cluster := range clusters {
 		namespace := cluster.Name
 		// default score is 0
@@ -86,10 +94,12 @@ func (c *AddOn) Score(ctx context.Context, placement *clusterapiv1beta1.Placemen
 			continue
 		}
 
-		// check score valid time
-		if (addOnScores.Status.ValidUntil != nil) && AddOnClock.Now().After(addOnScores.Status.ValidUntil.Time) {
+		// check score valid time, the last value of the stale score is held within the hold duration
+		if isStale(addOnScores, now) {
 			expiredScores = fmt.Sprintf("%s %s/%s", expiredScores, namespace, c.resourceName)
-			continue
+			if policy != StaleScorePolicyHoldLastValue || !now.Before(addOnScores.Status.ValidUntil.Add(hold)) {
+				continue
+			}
 		}
 
 		// get AddOnPlacementScores score with scoreName
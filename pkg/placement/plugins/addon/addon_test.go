@@ -13,6 +13,7 @@ import (
 	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
 	clusterapivbeta1 "open-cluster-management.io/api/cluster/v1beta1"
 
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
 	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
 )
 
@@ -108,3 +109,81 @@ func TestScoreClusterWithAddOn(t *testing.T) {
 		})
 	}
 }
+
+func TestScoreClusterWithStaleScorePolicy(t *testing.T) {
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").Build(),
+		testinghelpers.NewManagedCluster("cluster2").Build(),
+	}
+	existingAddOnScores := []runtime.Object{
+		testinghelpers.NewAddOnPlacementScore("cluster1", "test").WithScore("score1", 30).WithValidUntil(expiredTime).Build(),
+		testinghelpers.NewAddOnPlacementScore("cluster2", "test").WithScore("score1", 40).WithValidUntil(fakeTime).Build(),
+	}
+
+	cases := []struct {
+		name           string
+		annotations    map[string]string
+		expectedCode   framework.Code
+		expectedScores map[string]int64
+	}{
+		{
+			name:           "stale scores are zero by default",
+			expectedCode:   framework.Warning,
+			expectedScores: map[string]int64{"cluster1": 0, "cluster2": 0},
+		},
+		{
+			name:           "hold the last value of the stale scores",
+			annotations:    map[string]string{StaleScorePolicyAnnotationKey: string(StaleScorePolicyHoldLastValue)},
+			expectedCode:   framework.Warning,
+			expectedScores: map[string]int64{"cluster1": 30, "cluster2": 40},
+		},
+		{
+			name: "hold the last value of the stale scores in the hold duration",
+			annotations: map[string]string{
+				StaleScorePolicyAnnotationKey:       string(StaleScorePolicyHoldLastValue),
+				StaleScoreHoldDurationAnnotationKey: "10s",
+			},
+			expectedCode:   framework.Warning,
+			expectedScores: map[string]int64{"cluster1": 0, "cluster2": 40},
+		},
+		{
+			name:         "invalid policy",
+			annotations:  map[string]string{StaleScorePolicyAnnotationKey: "Invalid"},
+			expectedCode: framework.Misconfigured,
+		},
+		{
+			name: "invalid hold duration",
+			annotations: map[string]string{
+				StaleScorePolicyAnnotationKey:       string(StaleScorePolicyHoldLastValue),
+				StaleScoreHoldDurationAnnotationKey: "10",
+			},
+			expectedCode: framework.Misconfigured,
+		},
+	}
+
+	AddOnClock = testingclock.NewFakeClock(fakeTime)
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			placement := testinghelpers.NewPlacementWithAnnotations("test", "test", c.annotations).
+				WithScoreCoordinateAddOn("test", "score1", 1).Build()
+			addon := NewAddOnPrioritizerBuilder(testinghelpers.NewFakePluginHandle(t, nil, existingAddOnScores...)).
+				WithResourceName("test").WithScoreName("score1").Build()
+
+			scoreResult, status := addon.Score(context.TODO(), placement, clusters)
+			if status.Code() != c.expectedCode {
+				t.Fatalf("expect status code %v but get %v", c.expectedCode, status)
+			}
+			if c.expectedCode == framework.Misconfigured {
+				return
+			}
+			if !apiequality.Semantic.DeepEqual(scoreResult.Scores, c.expectedScores) {
+				t.Errorf("Expect score %v, but got %v", c.expectedScores, scoreResult.Scores)
+			}
+
+			stale := StaleScoreClusters(testinghelpers.NewFakePluginHandle(t, nil, existingAddOnScores...).ScoreLister(), placement, clusters)
+			if stale.Len() != 2 {
+				t.Errorf("Expect 2 clusters with stale scores, but got %v", stale.UnsortedList())
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package addon
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterlisterv1alpha1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1alpha1"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+)
+
+const (
+	// StaleScorePolicyAnnotationKey is the annotation on the placement to set how the AddOnPlacementScores
+	// are handled after their validUntil. The policy is one of Zero, HoldLastValue and FilterOut, the default
+	// policy is Zero.
+	StaleScorePolicyAnnotationKey = "cluster.open-cluster-management.io/stale-score-policy"
+
+	// StaleScoreHoldDurationAnnotationKey is the annotation on the placement to set how long the last value
+	// of a stale score is held with the HoldLastValue policy, for example "10m". The default is 5 minutes.
+	StaleScoreHoldDurationAnnotationKey = "cluster.open-cluster-management.io/stale-score-hold-duration"
+
+	defaultStaleScoreHoldDuration = 5 * time.Minute
+)
+
+type StaleScorePolicy string
+
+const (
+	// StaleScorePolicyZero gives score 0 to the clusters with stale scores.
+	StaleScorePolicyZero StaleScorePolicy = "Zero"
+	// StaleScorePolicyHoldLastValue keeps using the last value of the stale scores for the hold duration,
+	// and gives score 0 after that.
+	StaleScorePolicyHoldLastValue StaleScorePolicy = "HoldLastValue"
+	// StaleScorePolicyFilterOut filters out the clusters with stale scores.
+	StaleScorePolicyFilterOut StaleScorePolicy = "FilterOut"
+)
+
+// GetStaleScorePolicy returns the stale score policy and the hold duration of the placement.
+func GetStaleScorePolicy(placement *clusterapiv1beta1.Placement) (StaleScorePolicy, time.Duration, error) {
+	annotations := placement.GetAnnotations()
+	policy := StaleScorePolicy(annotations[StaleScorePolicyAnnotationKey])
+	switch policy {
+	case "":
+		policy = StaleScorePolicyZero
+	case StaleScorePolicyZero, StaleScorePolicyHoldLastValue, StaleScorePolicyFilterOut:
+	default:
+		return "", 0, fmt.Errorf("invalid value %q of annotation %s, it should be one of %s, %s and %s",
+			policy, StaleScorePolicyAnnotationKey, StaleScorePolicyZero, StaleScorePolicyHoldLastValue, StaleScorePolicyFilterOut)
+	}
+
+	hold := defaultStaleScoreHoldDuration
+	if value, ok := annotations[StaleScoreHoldDurationAnnotationKey]; ok {
+		var err error
+		hold, err = time.ParseDuration(value)
+		if err != nil || hold < 0 {
+			return "", 0, fmt.Errorf("invalid value %q of annotation %s, it should be a non-negative duration",
+				value, StaleScoreHoldDurationAnnotationKey)
+		}
+	}
+	return policy, hold, nil
+}
+
+// isStale returns true if the score is not valid at the time. The score is stale once the validUntil is reached.
+func isStale(score *clusterapiv1alpha1.AddOnPlacementScore, now time.Time) bool {
+	return score.Status.ValidUntil != nil && !now.Before(score.Status.ValidUntil.Time)
+}
+
+// StaleScoreClusters returns the clusters having a stale AddOnPlacementScore used by the prioritizer policy
+// of the placement.
+func StaleScoreClusters(
+	scoreLister clusterlisterv1alpha1.AddOnPlacementScoreLister,
+	placement *clusterapiv1beta1.Placement,
+	clusters []*clusterapiv1.ManagedCluster) sets.Set[string] {
+	resourceNames := sets.New[string]()
+	for _, config := range placement.Spec.PrioritizerPolicy.Configurations {
+		if config.ScoreCoordinate == nil || config.ScoreCoordinate.AddOn == nil || config.Weight == 0 {
+			continue
+		}
+		if config.ScoreCoordinate.Type == clusterapiv1beta1.ScoreCoordinateTypeAddOn {
+			resourceNames.Insert(config.ScoreCoordinate.AddOn.ResourceName)
+		}
+	}
+
+	stale := sets.New[string]()
+	if resourceNames.Len() == 0 {
+		return stale
+	}
+	now := AddOnClock.Now()
+	for _, cluster := range clusters {
+		for resourceName := range resourceNames {
+			score, err := scoreLister.AddOnPlacementScores(cluster.Name).Get(resourceName)
+			if err != nil {
+				continue
+			}
+			if isStale(score, now) {
+				stale.Insert(cluster.Name)
+				break
+			}
+		}
+	}
+	return stale
+}
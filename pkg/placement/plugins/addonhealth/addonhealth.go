@@ -0,0 +1,107 @@
+package addonhealth
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+const (
+	// RequiredAddOnsAnnotationKey is the annotation on the placement to require addons to be available
+	// on the selected clusters. The value is a comma separated list of the addon names, the clusters
+	// without any of the ManagedClusterAddOns or with any of them not Available are not selected.
+	RequiredAddOnsAnnotationKey = "cluster.open-cluster-management.io/required-addons"
+
+	description = `
+	AddOnHealth filters out the clusters on which the addons required by the placement are not
+	installed or not Available, so workloads are not scheduled to the clusters whose prerequisite
+	addons are broken.
+	`
+)
+
+var _ plugins.Filter = &AddOnHealth{}
+
+type AddOnHealth struct {
+	handle plugins.Handle
+}
+
+func New(handle plugins.Handle) *AddOnHealth {
+	return &AddOnHealth{
+		handle: handle,
+	}
+}
+
+func (a *AddOnHealth) Name() string {
+	return reflect.TypeOf(*a).Name()
+}
+
+func (a *AddOnHealth) Description() string {
+	return description
+}
+
+func (a *AddOnHealth) Filter(ctx context.Context, placement *clusterapiv1beta1.Placement,
+	clusters []*clusterapiv1.ManagedCluster) (plugins.PluginFilterResult, *framework.Status) {
+	status := framework.NewStatus(a.Name(), framework.Success, "")
+
+	addOnNames := GetRequiredAddOns(placement)
+	if len(addOnNames) == 0 {
+		return plugins.PluginFilterResult{Filtered: clusters}, status
+	}
+
+	var filtered []*clusterapiv1.ManagedCluster
+	for _, cluster := range clusters {
+		available, err := a.isAddOnsAvailable(cluster.Name, addOnNames)
+		if err != nil {
+			return plugins.PluginFilterResult{}, framework.NewStatus(a.Name(), framework.Error, err.Error())
+		}
+		if available {
+			filtered = append(filtered, cluster)
+		}
+	}
+	return plugins.PluginFilterResult{Filtered: filtered}, status
+}
+
+func (a *AddOnHealth) RequeueAfter(ctx context.Context, placement *clusterapiv1beta1.Placement) (plugins.PluginRequeueResult, *framework.Status) {
+	return plugins.PluginRequeueResult{}, framework.NewStatus(a.Name(), framework.Success, "")
+}
+
+func (a *AddOnHealth) isAddOnsAvailable(clusterName string, addOnNames []string) (bool, error) {
+	for _, name := range addOnNames {
+		addOn, err := a.handle.AddOnLister().ManagedClusterAddOns(clusterName).Get(name)
+		switch {
+		case errors.IsNotFound(err):
+			return false, nil
+		case err != nil:
+			return false, fmt.Errorf("failed to get addon %s of cluster %s: %v", name, clusterName, err)
+		}
+		if !meta.IsStatusConditionTrue(addOn.Status.Conditions, addonapiv1alpha1.ManagedClusterAddOnConditionAvailable) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// GetRequiredAddOns returns the names of the addons required by the placement.
+func GetRequiredAddOns(placement *clusterapiv1beta1.Placement) []string {
+	value := placement.GetAnnotations()[RequiredAddOnsAnnotationKey]
+	names := sets.New[string]()
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if len(name) > 0 {
+			names.Insert(name)
+		}
+	}
+	return sets.List(names)
+}
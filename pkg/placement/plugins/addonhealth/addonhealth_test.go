@@ -0,0 +1,86 @@
+package addonhealth
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func newAddOn(clusterName, name string, available metav1.ConditionStatus) *addonapiv1alpha1.ManagedClusterAddOn {
+	return &addonapiv1alpha1.ManagedClusterAddOn{
+		ObjectMeta: metav1.ObjectMeta{Namespace: clusterName, Name: name},
+		Status: addonapiv1alpha1.ManagedClusterAddOnStatus{
+			Conditions: []metav1.Condition{
+				{Type: addonapiv1alpha1.ManagedClusterAddOnConditionAvailable, Status: available},
+			},
+		},
+	}
+}
+
+func TestFilter(t *testing.T) {
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").Build(),
+		testinghelpers.NewManagedCluster("cluster2").Build(),
+		testinghelpers.NewManagedCluster("cluster3").Build(),
+	}
+	addOns := []runtime.Object{
+		newAddOn("cluster1", "addon1", metav1.ConditionTrue),
+		newAddOn("cluster1", "addon2", metav1.ConditionTrue),
+		newAddOn("cluster2", "addon1", metav1.ConditionTrue),
+		newAddOn("cluster2", "addon2", metav1.ConditionFalse),
+		newAddOn("cluster3", "addon2", metav1.ConditionUnknown),
+	}
+
+	cases := []struct {
+		name             string
+		annotations      map[string]string
+		expectedFiltered []string
+	}{
+		{
+			name:             "no required addons",
+			expectedFiltered: []string{"cluster1", "cluster2", "cluster3"},
+		},
+		{
+			name:             "addon is not installed",
+			annotations:      map[string]string{RequiredAddOnsAnnotationKey: "addon1"},
+			expectedFiltered: []string{"cluster1", "cluster2"},
+		},
+		{
+			name:             "addon is not available",
+			annotations:      map[string]string{RequiredAddOnsAnnotationKey: "addon1, addon2"},
+			expectedFiltered: []string{"cluster1"},
+		},
+		{
+			name:        "addon is not installed on any cluster",
+			annotations: map[string]string{RequiredAddOnsAnnotationKey: "addon3"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			placement := testinghelpers.NewPlacementWithAnnotations("test", "test", c.annotations).Build()
+			p := New(testinghelpers.NewFakePluginHandle(t, nil, addOns...))
+
+			result, status := p.Filter(context.TODO(), placement, clusters)
+			if status.IsError() {
+				t.Fatalf("unexpected error %v", status.AsError())
+			}
+
+			var filtered []string
+			for _, cluster := range result.Filtered {
+				filtered = append(filtered, cluster.Name)
+			}
+			if !reflect.DeepEqual(filtered, c.expectedFiltered) {
+				t.Errorf("expected filtered clusters %v, but got %v", c.expectedFiltered, filtered)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package antiaffinity
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+const (
+	// AntiAffinityAnnotationKey is the annotation on the placement to declare the anti-affinity to other
+	// placements in the same namespace. The value is a comma separated list of the placement names, the
+	// clusters selected by any of them are not selected by the placement.
+	AntiAffinityAnnotationKey = "cluster.open-cluster-management.io/anti-affinity-placements"
+
+	description = `
+	AntiAffinity filters out the clusters already selected by the placements which the placement
+	declares anti-affinity to, so the placements are able to select disjoint clusters.
+	`
+)
+
+var _ plugins.Filter = &AntiAffinity{}
+
+type AntiAffinity struct {
+	handle plugins.Handle
+}
+
+func New(handle plugins.Handle) *AntiAffinity {
+	return &AntiAffinity{
+		handle: handle,
+	}
+}
+
+func (a *AntiAffinity) Name() string {
+	return reflect.TypeOf(*a).Name()
+}
+
+func (a *AntiAffinity) Description() string {
+	return description
+}
+
+func (a *AntiAffinity) Filter(ctx context.Context, placement *clusterapiv1beta1.Placement,
+	clusters []*clusterapiv1.ManagedCluster) (plugins.PluginFilterResult, *framework.Status) {
+	status := framework.NewStatus(a.Name(), framework.Success, "")
+
+	names := GetAntiAffinityPlacements(placement)
+	if len(names) == 0 {
+		return plugins.PluginFilterResult{Filtered: clusters}, status
+	}
+
+	requirement, err := labels.NewRequirement(clusterapiv1beta1.PlacementLabel, selection.In, names)
+	if err != nil {
+		return plugins.PluginFilterResult{}, framework.NewStatus(a.Name(), framework.Misconfigured, err.Error())
+	}
+	decisions, err := a.handle.DecisionLister().PlacementDecisions(placement.Namespace).List(labels.NewSelector().Add(*requirement))
+	if err != nil {
+		return plugins.PluginFilterResult{}, framework.NewStatus(a.Name(), framework.Error, err.Error())
+	}
+
+	selected := sets.New[string]()
+	for _, decision := range decisions {
+		for _, d := range decision.Status.Decisions {
+			selected.Insert(d.ClusterName)
+		}
+	}
+
+	var filtered []*clusterapiv1.ManagedCluster
+	for _, cluster := range clusters {
+		if !selected.Has(cluster.Name) {
+			filtered = append(filtered, cluster)
+		}
+	}
+	return plugins.PluginFilterResult{Filtered: filtered}, status
+}
+
+func (a *AntiAffinity) RequeueAfter(ctx context.Context, placement *clusterapiv1beta1.Placement) (plugins.PluginRequeueResult, *framework.Status) {
+	return plugins.PluginRequeueResult{}, framework.NewStatus(a.Name(), framework.Success, "")
+}
+
+// GetAntiAffinityPlacements returns the names of the placements which the placement declares anti-affinity to.
+// The placement itself is ignored.
+func GetAntiAffinityPlacements(placement *clusterapiv1beta1.Placement) []string {
+	value := placement.GetAnnotations()[AntiAffinityAnnotationKey]
+	names := sets.New[string]()
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if len(name) > 0 && name != placement.Name {
+			names.Insert(name)
+		}
+	}
+	return sets.List(names)
+}
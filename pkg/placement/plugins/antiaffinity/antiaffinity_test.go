@@ -0,0 +1,78 @@
+package antiaffinity
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestFilter(t *testing.T) {
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").Build(),
+		testinghelpers.NewManagedCluster("cluster2").Build(),
+		testinghelpers.NewManagedCluster("cluster3").Build(),
+	}
+	decisions := []runtime.Object{
+		testinghelpers.NewPlacementDecision("test", testinghelpers.PlacementDecisionName("other1", 1)).
+			WithLabel(clusterapiv1beta1.PlacementLabel, "other1").WithDecisions("cluster1").Build(),
+		testinghelpers.NewPlacementDecision("test", testinghelpers.PlacementDecisionName("other2", 1)).
+			WithLabel(clusterapiv1beta1.PlacementLabel, "other2").WithDecisions("cluster2").Build(),
+		testinghelpers.NewPlacementDecision("test", testinghelpers.PlacementDecisionName("test", 1)).
+			WithLabel(clusterapiv1beta1.PlacementLabel, "test").WithDecisions("cluster3").Build(),
+		testinghelpers.NewPlacementDecision("other", testinghelpers.PlacementDecisionName("other1", 1)).
+			WithLabel(clusterapiv1beta1.PlacementLabel, "other1").WithDecisions("cluster3").Build(),
+	}
+
+	cases := []struct {
+		name             string
+		annotations      map[string]string
+		expectedFiltered []string
+	}{
+		{
+			name:             "no anti-affinity",
+			expectedFiltered: []string{"cluster1", "cluster2", "cluster3"},
+		},
+		{
+			name:             "anti-affinity to one placement",
+			annotations:      map[string]string{AntiAffinityAnnotationKey: "other1"},
+			expectedFiltered: []string{"cluster2", "cluster3"},
+		},
+		{
+			name:             "anti-affinity to placements and itself",
+			annotations:      map[string]string{AntiAffinityAnnotationKey: "other1, other2,test"},
+			expectedFiltered: []string{"cluster3"},
+		},
+		{
+			name:             "anti-affinity to a placement without decisions",
+			annotations:      map[string]string{AntiAffinityAnnotationKey: "other3"},
+			expectedFiltered: []string{"cluster1", "cluster2", "cluster3"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			placement := testinghelpers.NewPlacementWithAnnotations("test", "test", c.annotations).Build()
+			p := New(testinghelpers.NewFakePluginHandle(t, nil, decisions...))
+
+			result, status := p.Filter(context.TODO(), placement, clusters)
+			if status.IsError() {
+				t.Fatalf("unexpected error %v", status.AsError())
+			}
+
+			var filtered []string
+			for _, cluster := range result.Filtered {
+				filtered = append(filtered, cluster.Name)
+			}
+			if !reflect.DeepEqual(filtered, c.expectedFiltered) {
+				t.Errorf("expected filtered clusters %v, but got %v", c.expectedFiltered, filtered)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package clustersetpriority
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	clusterapiv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+const (
+	// ClusterSetPrioritiesAnnotationKey is the annotation on the placement to prioritize the clusters by the
+	// clustersets they belong to, for example "onprem=100,cloud=0". The priority of a clusterset is in the
+	// range [-100, 100], and a cluster in several of the listed clustersets gets the highest priority of them.
+	ClusterSetPrioritiesAnnotationKey = "cluster.open-cluster-management.io/clusterset-priorities"
+
+	description = `
+	ClusterSetPriority prioritizer scores the clusters by the priorities of the clustersets they belong to,
+	which are set in the annotation of the placement. The clusters not in any of the listed clustersets are
+	given 0. It expresses the policies like "prefer the on-prem clusterset, and burst to the cloud clusterset"
+	when the bound clustersets overlap or are selected together.
+	`
+)
+
+var _ plugins.Prioritizer = &ClusterSetPriority{}
+
+type ClusterSetPriority struct {
+	handle plugins.Handle
+}
+
+func New(handle plugins.Handle) *ClusterSetPriority {
+	return &ClusterSetPriority{
+		handle: handle,
+	}
+}
+
+func (c *ClusterSetPriority) Name() string {
+	return reflect.TypeOf(*c).Name()
+}
+
+func (c *ClusterSetPriority) Description() string {
+	return description
+}
+
+func (c *ClusterSetPriority) Score(
+	ctx context.Context, placement *clusterapiv1beta1.Placement, clusters []*clusterapiv1.ManagedCluster) (plugins.PluginScoreResult, *framework.Status) {
+	priorities, err := GetClusterSetPriorities(placement)
+	if err != nil {
+		return plugins.PluginScoreResult{}, framework.NewStatus(c.Name(), framework.Misconfigured, err.Error())
+	}
+
+	status := framework.NewStatus(c.Name(), framework.Success, "")
+	selectors := map[string]labels.Selector{}
+	for name := range priorities {
+		clusterSet, err := c.handle.ClusterSetLister().Get(name)
+		switch {
+		case errors.IsNotFound(err):
+			status = framework.NewStatus(c.Name(), framework.Warning, fmt.Sprintf("the clusterset %q is not found", name))
+			continue
+		case err != nil:
+			return plugins.PluginScoreResult{}, framework.NewStatus(c.Name(), framework.Error, err.Error())
+		}
+		selector, err := clusterapiv1beta2.BuildClusterSelector(clusterSet)
+		if err != nil {
+			return plugins.PluginScoreResult{}, framework.NewStatus(c.Name(), framework.Error, err.Error())
+		}
+		selectors[name] = selector
+	}
+
+	scores := map[string]int64{}
+	for _, cluster := range clusters {
+		matched := false
+		for name, selector := range selectors {
+			if !selector.Matches(labels.Set(cluster.Labels)) {
+				continue
+			}
+			if priority := priorities[name]; !matched || priority > scores[cluster.Name] {
+				scores[cluster.Name] = priority
+			}
+			matched = true
+		}
+		if !matched {
+			scores[cluster.Name] = 0
+		}
+	}
+
+	return plugins.PluginScoreResult{
+		Scores: scores,
+	}, status
+}
+
+func (c *ClusterSetPriority) RequeueAfter(ctx context.Context, placement *clusterapiv1beta1.Placement) (plugins.PluginRequeueResult, *framework.Status) {
+	return plugins.PluginRequeueResult{}, framework.NewStatus(c.Name(), framework.Success, "")
+}
+
+// GetClusterSetPriorities returns the priority of each clusterset listed in the annotation of the placement.
+func GetClusterSetPriorities(placement *clusterapiv1beta1.Placement) (map[string]int64, error) {
+	priorities := map[string]int64{}
+	for _, item := range strings.Split(placement.GetAnnotations()[ClusterSetPrioritiesAnnotationKey], ",") {
+		if item = strings.TrimSpace(item); len(item) == 0 {
+			continue
+		}
+		name, value, ok := strings.Cut(item, "=")
+		if !ok || len(strings.TrimSpace(name)) == 0 {
+			return nil, fmt.Errorf("invalid value %q of annotation %s, it should be <clusterset name>=<priority>",
+				item, ClusterSetPrioritiesAnnotationKey)
+		}
+		priority, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil || priority < plugins.MinClusterScore || priority > plugins.MaxClusterScore {
+			return nil, fmt.Errorf("invalid priority %q of clusterset %q in annotation %s, it should be an integer in the range [%d, %d]",
+				value, name, ClusterSetPrioritiesAnnotationKey, plugins.MinClusterScore, plugins.MaxClusterScore)
+		}
+		priorities[strings.TrimSpace(name)] = priority
+	}
+	return priorities, nil
+}
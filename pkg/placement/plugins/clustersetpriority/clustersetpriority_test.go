@@ -0,0 +1,94 @@
+package clustersetpriority
+
+import (
+	"context"
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	clusterapiv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestScoreClusterWithClusterSetPriority(t *testing.T) {
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").WithLabel("location", "onprem").Build(),
+		testinghelpers.NewManagedCluster("cluster2").WithLabel("location", "onprem").WithLabel("burst", "true").Build(),
+		testinghelpers.NewManagedCluster("cluster3").WithLabel("burst", "true").Build(),
+		testinghelpers.NewManagedCluster("cluster4").WithLabel(clusterapiv1beta2.ClusterSetLabel, "edge").Build(),
+	}
+	objects := []runtime.Object{
+		testinghelpers.NewClusterSet("onprem").WithClusterSelector(clusterapiv1beta2.ManagedClusterSelector{
+			SelectorType:  clusterapiv1beta2.LabelSelector,
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"location": "onprem"}},
+		}).Build(),
+		testinghelpers.NewClusterSet("cloud").WithClusterSelector(clusterapiv1beta2.ManagedClusterSelector{
+			SelectorType:  clusterapiv1beta2.LabelSelector,
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"burst": "true"}},
+		}).Build(),
+		testinghelpers.NewClusterSet("edge").Build(),
+	}
+
+	cases := []struct {
+		name           string
+		placement      *clusterapiv1beta1.Placement
+		expectedCode   framework.Code
+		expectedScores map[string]int64
+	}{
+		{
+			name:           "no priorities",
+			placement:      testinghelpers.NewPlacement("test", "test").Build(),
+			expectedScores: map[string]int64{"cluster1": 0, "cluster2": 0, "cluster3": 0, "cluster4": 0},
+		},
+		{
+			name: "prefer onprem and burst to cloud",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				ClusterSetPrioritiesAnnotationKey: "onprem=100, cloud=-50",
+			}).Build(),
+			expectedScores: map[string]int64{"cluster1": 100, "cluster2": 100, "cluster3": -50, "cluster4": 0},
+		},
+		{
+			name: "exclusive clusterset",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				ClusterSetPrioritiesAnnotationKey: "edge=20,cloud=10",
+			}).Build(),
+			expectedScores: map[string]int64{"cluster1": 0, "cluster2": 10, "cluster3": 10, "cluster4": 20},
+		},
+		{
+			name: "clusterset not found",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				ClusterSetPrioritiesAnnotationKey: "onprem=100,missing=50",
+			}).Build(),
+			expectedCode:   framework.Warning,
+			expectedScores: map[string]int64{"cluster1": 100, "cluster2": 100, "cluster3": 0, "cluster4": 0},
+		},
+		{
+			name: "invalid priority",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				ClusterSetPrioritiesAnnotationKey: "onprem=200",
+			}).Build(),
+			expectedCode: framework.Misconfigured,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			priority := New(testinghelpers.NewFakePluginHandle(t, nil, objects...))
+
+			scoreResult, status := priority.Score(context.TODO(), c.placement, clusters)
+			if status.Code() != c.expectedCode {
+				t.Errorf("Expect status code %v, but got %v", c.expectedCode, status.Code())
+			}
+
+			if !apiequality.Semantic.DeepEqual(scoreResult.Scores, c.expectedScores) {
+				t.Errorf("Expect score %v, but got %v", c.expectedScores, scoreResult.Scores)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package extender
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/common/fips"
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+const description = `
+	Extender calls an out-of-tree grpc endpoint to filter and score the clusters, so additional scheduling
+	logic is able to be injected without changing the scheduler. When the call fails or times out, the
+	placement fails to be scheduled, or the extender is skipped if it is configured to fail open.
+	`
+
+var _ plugins.Filter = &Extender{}
+var _ plugins.Prioritizer = &Extender{}
+
+// Options holds the configuration of the extender.
+type Options struct {
+	// Address is the grpc endpoint of the extender, the extender is disabled if it is empty.
+	Address string
+	// CAFile is the CA bundle to verify the extender, the connection is insecure if it is empty.
+	CAFile string
+	// Timeout is the timeout of each call to the extender.
+	Timeout time.Duration
+	// FailOpen skips the extender when the call fails instead of failing the scheduling.
+	FailOpen bool
+	// Weight is the default weight of the extender prioritizer.
+	Weight int32
+}
+
+// NewOptions returns the default extender options
+func NewOptions() *Options {
+	return &Options{
+		Timeout: 5 * time.Second,
+		Weight:  1,
+	}
+}
+
+// AddFlags registers the flags of the extender
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Address, "extender-address", o.Address,
+		"The grpc endpoint of the scheduler extender, which filters and scores the clusters in addition to the builtin plugins.")
+	fs.StringVar(&o.CAFile, "extender-ca-file", o.CAFile,
+		"The CA bundle to verify the scheduler extender. The connection is insecure if it is not set.")
+	fs.DurationVar(&o.Timeout, "extender-timeout", o.Timeout, "The timeout of each call to the scheduler extender.")
+	fs.BoolVar(&o.FailOpen, "extender-fail-open", o.FailOpen,
+		"Skip the scheduler extender when it fails or times out instead of failing the scheduling.")
+	fs.Int32Var(&o.Weight, "extender-weight", o.Weight,
+		"The default weight of the scores of the scheduler extender, it is able to be overridden by the prioritizer policy of the placement.")
+}
+
+// Validate checks the extender options
+func (o *Options) Validate() error {
+	if len(o.Address) == 0 {
+		return nil
+	}
+	if o.Timeout <= 0 {
+		return fmt.Errorf("extender-timeout should be greater than 0")
+	}
+	if o.Weight < -10 || o.Weight > 10 {
+		return fmt.Errorf("extender-weight should be in the range [-10, 10]")
+	}
+	return nil
+}
+
+type Extender struct {
+	conn     *grpc.ClientConn
+	timeout  time.Duration
+	failOpen bool
+}
+
+// New connects to the extender, it returns nil if the extender is not configured.
+func New(o *Options) (*Extender, error) {
+	if len(o.Address) == 0 {
+		return nil, nil
+	}
+
+	creds := insecure.NewCredentials()
+	if len(o.CAFile) > 0 {
+		caData, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		if fips.Enabled() {
+			if err := fips.ValidateCertificatesPEM(caData); err != nil {
+				return nil, fmt.Errorf("the CA of the extender is not valid in FIPS mode: %w", err)
+			}
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("no valid certificate is found in %s", o.CAFile)
+		}
+		tlsConfig := &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+		fips.SecureTLSConfig(tlsConfig)
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	conn, err := grpc.Dial(o.Address, grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(codecName)))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Extender{
+		conn:     conn,
+		timeout:  o.Timeout,
+		failOpen: o.FailOpen,
+	}, nil
+}
+
+// Close closes the connection to the extender.
+func (e *Extender) Close() error {
+	return e.conn.Close()
+}
+
+func (e *Extender) Name() string {
+	return "Extender"
+}
+
+func (e *Extender) Description() string {
+	return description
+}
+
+func (e *Extender) Filter(
+	ctx context.Context, placement *clusterapiv1beta1.Placement, clusters []*clusterapiv1.ManagedCluster) (plugins.PluginFilterResult, *framework.Status) {
+	result := &ExtenderFilterResult{}
+	if err := e.invoke(ctx, filterMethod, placement, clusters, result); err != nil {
+		if e.failOpen {
+			return plugins.PluginFilterResult{Filtered: clusters}, e.failureStatus(framework.Warning, err)
+		}
+		return plugins.PluginFilterResult{}, e.failureStatus(framework.Error, err)
+	}
+
+	passed := sets.New[string](result.Clusters...)
+	filtered := []*clusterapiv1.ManagedCluster{}
+	for _, cluster := range clusters {
+		if passed.Has(cluster.Name) {
+			filtered = append(filtered, cluster)
+		}
+	}
+	return plugins.PluginFilterResult{Filtered: filtered}, framework.NewStatus(e.Name(), framework.Success, "")
+}
+
+func (e *Extender) Score(
+	ctx context.Context, placement *clusterapiv1beta1.Placement, clusters []*clusterapiv1.ManagedCluster) (plugins.PluginScoreResult, *framework.Status) {
+	scores := map[string]int64{}
+	for _, cluster := range clusters {
+		scores[cluster.Name] = 0
+	}
+
+	result := &ExtenderScoreResult{}
+	if err := e.invoke(ctx, scoreMethod, placement, clusters, result); err != nil {
+		if e.failOpen {
+			return plugins.PluginScoreResult{Scores: scores}, e.failureStatus(framework.Warning, err)
+		}
+		return plugins.PluginScoreResult{}, e.failureStatus(framework.Error, err)
+	}
+
+	// ignore the clusters not in the candidates and normalize the scores out of range
+	for name, score := range result.Scores {
+		if _, ok := scores[name]; !ok {
+			continue
+		}
+		switch {
+		case score > plugins.MaxClusterScore:
+			score = plugins.MaxClusterScore
+		case score < plugins.MinClusterScore:
+			score = plugins.MinClusterScore
+		}
+		scores[name] = score
+	}
+	return plugins.PluginScoreResult{Scores: scores}, framework.NewStatus(e.Name(), framework.Success, "")
+}
+
+func (e *Extender) RequeueAfter(ctx context.Context, placement *clusterapiv1beta1.Placement) (plugins.PluginRequeueResult, *framework.Status) {
+	return plugins.PluginRequeueResult{}, framework.NewStatus(e.Name(), framework.Success, "")
+}
+
+func (e *Extender) invoke(
+	ctx context.Context, method string, placement *clusterapiv1beta1.Placement, clusters []*clusterapiv1.ManagedCluster, result interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+	return e.conn.Invoke(ctx, method, &ExtenderArgs{Placement: placement, Clusters: clusters}, result)
+}
+
+func (e *Extender) failureStatus(code framework.Code, err error) *framework.Status {
+	return framework.NewStatus(e.Name(), code, fmt.Sprintf("failed to call the scheduler extender: %v", err))
+}
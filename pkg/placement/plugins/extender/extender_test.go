@@ -0,0 +1,171 @@
+package extender
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+type fakeExtenderServer struct {
+	err       error
+	passed    []string
+	scores    map[string]int64
+	placement string
+}
+
+func (s *fakeExtenderServer) Filter(ctx context.Context, args *ExtenderArgs) (*ExtenderFilterResult, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.placement = args.Placement.Name
+	return &ExtenderFilterResult{Clusters: s.passed}, nil
+}
+
+func (s *fakeExtenderServer) Score(ctx context.Context, args *ExtenderArgs) (*ExtenderScoreResult, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.placement = args.Placement.Name
+	return &ExtenderScoreResult{Scores: s.scores}, nil
+}
+
+func newTestExtender(t *testing.T, srv ExtenderServer, failOpen bool) *Extender {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	RegisterExtenderServer(server, srv)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	e, err := New(&Options{Address: listener.Addr().String(), Timeout: 5 * time.Second, FailOpen: failOpen})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = e.Close()
+	})
+	return e
+}
+
+func TestFilter(t *testing.T) {
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").Build(),
+		testinghelpers.NewManagedCluster("cluster2").Build(),
+		testinghelpers.NewManagedCluster("cluster3").Build(),
+	}
+
+	cases := []struct {
+		name             string
+		server           *fakeExtenderServer
+		failOpen         bool
+		expectedCode     framework.Code
+		expectedFiltered []string
+	}{
+		{
+			name:             "filter clusters",
+			server:           &fakeExtenderServer{passed: []string{"cluster3", "cluster1", "cluster4"}},
+			expectedFiltered: []string{"cluster1", "cluster3"},
+		},
+		{
+			name:         "fail closed",
+			server:       &fakeExtenderServer{err: fmt.Errorf("unavailable")},
+			expectedCode: framework.Error,
+		},
+		{
+			name:             "fail open",
+			server:           &fakeExtenderServer{err: fmt.Errorf("unavailable")},
+			failOpen:         true,
+			expectedCode:     framework.Warning,
+			expectedFiltered: []string{"cluster1", "cluster2", "cluster3"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e := newTestExtender(t, c.server, c.failOpen)
+			placement := testinghelpers.NewPlacement("test", "test").Build()
+
+			result, status := e.Filter(context.TODO(), placement, clusters)
+			if status.Code() != c.expectedCode {
+				t.Fatalf("expected status code %v, but got %v", c.expectedCode, status)
+			}
+
+			var filtered []string
+			for _, cluster := range result.Filtered {
+				filtered = append(filtered, cluster.Name)
+			}
+			if !reflect.DeepEqual(filtered, c.expectedFiltered) {
+				t.Errorf("expected filtered clusters %v, but got %v", c.expectedFiltered, filtered)
+			}
+			if c.expectedCode == framework.Success && c.server.placement != placement.Name {
+				t.Errorf("expected placement %q sent to the extender, but got %q", placement.Name, c.server.placement)
+			}
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").Build(),
+		testinghelpers.NewManagedCluster("cluster2").Build(),
+		testinghelpers.NewManagedCluster("cluster3").Build(),
+	}
+
+	cases := []struct {
+		name           string
+		server         *fakeExtenderServer
+		failOpen       bool
+		expectedCode   framework.Code
+		expectedScores map[string]int64
+	}{
+		{
+			name:           "score clusters",
+			server:         &fakeExtenderServer{scores: map[string]int64{"cluster1": 200, "cluster2": -50, "cluster4": 10}},
+			expectedScores: map[string]int64{"cluster1": 100, "cluster2": -50, "cluster3": 0},
+		},
+		{
+			name:         "fail closed",
+			server:       &fakeExtenderServer{err: fmt.Errorf("unavailable")},
+			expectedCode: framework.Error,
+		},
+		{
+			name:           "fail open",
+			server:         &fakeExtenderServer{err: fmt.Errorf("unavailable")},
+			failOpen:       true,
+			expectedCode:   framework.Warning,
+			expectedScores: map[string]int64{"cluster1": 0, "cluster2": 0, "cluster3": 0},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e := newTestExtender(t, c.server, c.failOpen)
+			placement := testinghelpers.NewPlacement("test", "test").Build()
+
+			result, status := e.Score(context.TODO(), placement, clusters)
+			if status.Code() != c.expectedCode {
+				t.Fatalf("expected status code %v, but got %v", c.expectedCode, status)
+			}
+			if c.expectedCode == framework.Error {
+				return
+			}
+			if !reflect.DeepEqual(result.Scores, c.expectedScores) {
+				t.Errorf("expected scores %v, but got %v", c.expectedScores, result.Scores)
+			}
+		})
+	}
+}
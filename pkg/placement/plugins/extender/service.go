@@ -0,0 +1,85 @@
+package extender
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+)
+
+const (
+	serviceName  = "placement.extender.v1.Extender"
+	filterMethod = "/" + serviceName + "/Filter"
+	scoreMethod  = "/" + serviceName + "/Score"
+)
+
+// ExtenderArgs is the request of the extender, it contains the placement being scheduled and
+// the candidate clusters.
+type ExtenderArgs struct {
+	Placement *clusterapiv1beta1.Placement   `json:"placement"`
+	Clusters  []*clusterapiv1.ManagedCluster `json:"clusters"`
+}
+
+// ExtenderFilterResult is the response of the Filter call, it contains the names of the clusters
+// passing the filter.
+type ExtenderFilterResult struct {
+	Clusters []string `json:"clusters"`
+}
+
+// ExtenderScoreResult is the response of the Score call, it contains the score of each cluster with
+// the cluster name as the key. The scores are expected to be in the range [-100, 100].
+type ExtenderScoreResult struct {
+	Scores map[string]int64 `json:"scores"`
+}
+
+// ExtenderServer is the server API of the extender.
+type ExtenderServer interface {
+	Filter(ctx context.Context, args *ExtenderArgs) (*ExtenderFilterResult, error)
+	Score(ctx context.Context, args *ExtenderArgs) (*ExtenderScoreResult, error)
+}
+
+// RegisterExtenderServer registers the extender service to the grpc server.
+func RegisterExtenderServer(s *grpc.Server, srv ExtenderServer) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*ExtenderServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Filter",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				args := &ExtenderArgs{}
+				if err := dec(args); err != nil {
+					return nil, err
+				}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(ExtenderServer).Filter(ctx, req.(*ExtenderArgs))
+				}
+				if interceptor == nil {
+					return handler(ctx, args)
+				}
+				return interceptor(ctx, args, &grpc.UnaryServerInfo{Server: srv, FullMethod: filterMethod}, handler)
+			},
+		},
+		{
+			MethodName: "Score",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				args := &ExtenderArgs{}
+				if err := dec(args); err != nil {
+					return nil, err
+				}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(ExtenderServer).Score(ctx, req.(*ExtenderArgs))
+				}
+				if interceptor == nil {
+					return handler(ctx, args)
+				}
+				return interceptor(ctx, args, &grpc.UnaryServerInfo{Server: srv, FullMethod: scoreMethod}, handler)
+			},
+		},
+	},
+}
@@ -7,10 +7,12 @@ import (
 
 	"k8s.io/client-go/tools/events"
 
+	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
 	clusterclient "open-cluster-management.io/api/client/cluster/clientset/versioned"
 	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
 	clusterlisterv1alpha1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1alpha1"
 	clusterlisterv1beta1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
+	clusterlisterv1beta2 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta2"
 	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
 	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 
@@ -55,6 +57,14 @@ type Prioritizer interface {
 	Score(ctx context.Context, placement *clusterapiv1beta1.Placement, clusters []*clusterapiv1.ManagedCluster) (PluginScoreResult, *framework.Status)
 }
 
+// Selector defines a selector plugin that selects the decisions from the feasible clusters.
+type Selector interface {
+	Plugin
+
+	// Select returns at most num clusters from the clusters, which are sorted by the scores.
+	Select(ctx context.Context, placement *clusterapiv1beta1.Placement, clusters []*clusterapiv1.ManagedCluster, num int) (PluginSelectResult, *framework.Status)
+}
+
 // Handle provides data and some tools that plugins can use. It is
 // passed to the plugin factories at the time of plugin initialization.
 type Handle interface {
@@ -67,6 +77,12 @@ type Handle interface {
 	// ClusterLister lists all ManagedClusters
 	ClusterLister() clusterlisterv1.ManagedClusterLister
 
+	// AddOnLister lists all ManagedClusterAddOns
+	AddOnLister() addonlisterv1alpha1.ManagedClusterAddOnLister
+
+	// ClusterSetLister lists all ManagedClusterSets
+	ClusterSetLister() clusterlisterv1beta2.ManagedClusterSetLister
+
 	// ClusterClient returns the cluster client
 	ClusterClient() clusterclient.Interface
 
@@ -86,6 +102,12 @@ type PluginScoreResult struct {
 	Scores map[string]int64
 }
 
+// PluginSelectResult contains the details of a selector plugin result.
+type PluginSelectResult struct {
+	// Selected contains the selected ManagedClusters.
+	Selected []*clusterapiv1.ManagedCluster
+}
+
 // PluginRequeueResult contains the requeue result of a placement.
 type PluginRequeueResult struct {
 	// RequeueTime contains the expect requeue time.
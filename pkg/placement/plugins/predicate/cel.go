@@ -0,0 +1,121 @@
+package predicate
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/ext"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/placement/helpers"
+)
+
+const (
+	// CELPredicatesAnnotationKey is a JSON list of CEL expressions on the placement. Besides the predicates
+	// in the spec, a cluster is selected only if all of the expressions are evaluated to true. The expressions
+	// are able to access:
+	//   - managedCluster: the ManagedCluster object, e.g. managedCluster.status.version.kubernetes
+	//   - labels: the labels of the ManagedCluster
+	//   - claims: the cluster claims of the ManagedCluster, keyed by the claim name
+	// Besides the CEL standard and string extension functions, versionAtLeast(version, minVersion) compares
+	// two versions, e.g. versionAtLeast(managedCluster.status.version.kubernetes, "1.28")
+	CELPredicatesAnnotationKey = "cluster.open-cluster-management.io/cel-predicates"
+
+	// celCostLimit limits the cost of evaluating an expression against a cluster.
+	celCostLimit = 1000000
+)
+
+// compileCELPredicates parses the expressions in the annotation value and compiles them to programs.
+func compileCELPredicates(value string) ([]cel.Program, error) {
+	var expressions []string
+	if err := json.Unmarshal([]byte(value), &expressions); err != nil {
+		return nil, fmt.Errorf("the annotation %s is not a list of expressions: %v", CELPredicatesAnnotationKey, err)
+	}
+
+	env, err := cel.NewEnv(
+		cel.Variable("managedCluster", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("labels", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable("claims", cel.MapType(cel.StringType, cel.StringType)),
+		ext.Strings(),
+		cel.Function("versionAtLeast",
+			cel.Overload("version_at_least_string_string", []*cel.Type{cel.StringType, cel.StringType}, cel.BoolType,
+				cel.BinaryBinding(versionAtLeast))),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var programs []cel.Program
+	for _, expression := range expressions {
+		ast, issues := env.Compile(expression)
+		if issues.Err() != nil {
+			return nil, fmt.Errorf("failed to compile expression %q: %v", expression, issues.Err())
+		}
+		if !cel.BoolType.IsAssignableType(ast.OutputType()) {
+			return nil, fmt.Errorf("the expression %q is not evaluated to a bool but %v", expression, ast.OutputType())
+		}
+		program, err := env.Program(ast, cel.CostLimit(celCostLimit))
+		if err != nil {
+			return nil, fmt.Errorf("failed to build program of expression %q: %v", expression, err)
+		}
+		programs = append(programs, program)
+	}
+	return programs, nil
+}
+
+// evaluateCELPredicates returns true if all the programs are evaluated to true against the cluster. An expression
+// failing to be evaluated against a cluster, e.g. referring a field the cluster does not have, is
+// treated as not matched.
+func evaluateCELPredicates(programs []cel.Program, cluster *clusterapiv1.ManagedCluster) (bool, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cluster)
+	if err != nil {
+		return false, err
+	}
+	labels := cluster.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	input := map[string]interface{}{
+		"managedCluster": obj,
+		"labels":         labels,
+		"claims":         helpers.GetClusterClaims(cluster),
+	}
+
+	for _, program := range programs {
+		out, _, err := program.Eval(input)
+		if err != nil {
+			return false, nil
+		}
+		if matched, ok := out.Value().(bool); !ok || !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func versionAtLeast(lhs, rhs ref.Val) ref.Val {
+	current, ok := lhs.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(lhs)
+	}
+	minimum, ok := rhs.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(rhs)
+	}
+
+	currentVersion, err := version.ParseGeneric(string(current))
+	if err != nil {
+		return types.NewErr("invalid version %q: %v", current, err)
+	}
+	minimumVersion, err := version.ParseGeneric(string(minimum))
+	if err != nil {
+		return types.NewErr("invalid version %q: %v", minimum, err)
+	}
+	return types.Bool(currentVersion.AtLeast(minimumVersion))
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"reflect"
 
+	"github.com/google/cel-go/cel"
+
 	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
 	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 
@@ -34,7 +36,8 @@ func (p *Predicate) Filter(
 	ctx context.Context, placement *clusterapiv1beta1.Placement, clusters []*clusterapiv1.ManagedCluster) (plugins.PluginFilterResult, *framework.Status) {
 	status := framework.NewStatus(p.Name(), framework.Success, "")
 
-	if len(placement.Spec.Predicates) == 0 {
+	celPredicates, hasCELPredicates := placement.GetAnnotations()[CELPredicatesAnnotationKey]
+	if len(placement.Spec.Predicates) == 0 && !hasCELPredicates {
 		return plugins.PluginFilterResult{
 			Filtered: clusters,
 		}, status
@@ -59,17 +62,49 @@ func (p *Predicate) Filter(
 		clusterSelectors = append(clusterSelectors, clusterSelector)
 	}
 
+	// compile the CEL expressions
+	var celPrograms []cel.Program
+	if hasCELPredicates {
+		var err error
+		celPrograms, err = compileCELPredicates(celPredicates)
+		if err != nil {
+			return plugins.PluginFilterResult{}, framework.NewStatus(
+				p.Name(),
+				framework.Misconfigured,
+				err.Error(),
+			)
+		}
+	}
+
 	// match cluster with selectors one by one
 	matched := []*clusterapiv1.ManagedCluster{}
 	for _, cluster := range clusters {
 		claims := helpers.GetClusterClaims(cluster)
+		selected := len(clusterSelectors) == 0
 		for _, cs := range clusterSelectors {
-			if ok := cs.Matches(cluster.Labels, claims); !ok {
+			if ok := cs.Matches(cluster.Labels, claims); ok {
+				selected = true
+				break
+			}
+		}
+		if !selected {
+			continue
+		}
+
+		if len(celPrograms) > 0 {
+			ok, err := evaluateCELPredicates(celPrograms, cluster)
+			if err != nil {
+				return plugins.PluginFilterResult{}, framework.NewStatus(
+					p.Name(),
+					framework.Error,
+					err.Error(),
+				)
+			}
+			if !ok {
 				continue
 			}
-			matched = append(matched, cluster)
-			break
 		}
+		matched = append(matched, cluster)
 	}
 
 	return plugins.PluginFilterResult{
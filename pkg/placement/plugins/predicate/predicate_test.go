@@ -11,6 +11,7 @@ import (
 	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
 	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
 	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
 )
 
@@ -131,3 +132,94 @@ func TestMatchWithClusterPredicates(t *testing.T) {
 	}
 
 }
+
+func TestMatchWithCELPredicates(t *testing.T) {
+	newCluster := func(name, kubeVersion, region string) *clusterapiv1.ManagedCluster {
+		cluster := testinghelpers.NewManagedCluster(name).WithLabel("cloud", "Amazon").WithClaim("region", region).Build()
+		cluster.Status.Version.Kubernetes = kubeVersion
+		return cluster
+	}
+	clusters := []*clusterapiv1.ManagedCluster{
+		newCluster("cluster1", "v1.28.2", "us-east-1"),
+		newCluster("cluster2", "v1.27.5+k3s1", "us-east-1"),
+		newCluster("cluster3", "v1.29.0", "eu-west-1"),
+		newCluster("cluster4", "", "us-east-1"),
+	}
+
+	cases := []struct {
+		name                 string
+		placement            *clusterapiv1beta1.Placement
+		expectedMisconfigure bool
+		expectedClusterNames []string
+	}{
+		{
+			name: "match with version and claim",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				CELPredicatesAnnotationKey: `["versionAtLeast(managedCluster.status.version.kubernetes, '1.28')",` +
+					`"claims['region'] in ['us-east-1', 'us-west-1']"]`,
+			}).Build(),
+			expectedClusterNames: []string{"cluster1"},
+		},
+		{
+			name: "match with label and string functions",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				CELPredicatesAnnotationKey: `["labels['cloud'] == 'Amazon' && claims['region'].startsWith('eu-')"]`,
+			}).Build(),
+			expectedClusterNames: []string{"cluster3"},
+		},
+		{
+			name: "match with both spec predicates and expressions",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				CELPredicatesAnnotationKey: `["managedCluster.metadata.name != 'cluster2'"]`,
+			}).AddPredicate(nil, &clusterapiv1beta1.ClusterClaimSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "region", Operator: metav1.LabelSelectorOpIn, Values: []string{"us-east-1"}},
+				},
+			}).Build(),
+			expectedClusterNames: []string{"cluster1", "cluster4"},
+		},
+		{
+			name: "invalid annotation",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				CELPredicatesAnnotationKey: "labels['cloud'] == 'Amazon'",
+			}).Build(),
+			expectedMisconfigure: true,
+		},
+		{
+			name: "invalid expression",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				CELPredicatesAnnotationKey: `["labels['cloud'] =="]`,
+			}).Build(),
+			expectedMisconfigure: true,
+		},
+		{
+			name: "expression not evaluated to bool",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				CELPredicatesAnnotationKey: `["labels['cloud']"]`,
+			}).Build(),
+			expectedMisconfigure: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := &Predicate{}
+			result, status := p.Filter(context.TODO(), c.placement, clusters)
+			if c.expectedMisconfigure != (status.Code() == framework.Misconfigured) {
+				t.Fatalf("expected misconfigured %v, but got status %v", c.expectedMisconfigure, status)
+			}
+			if c.expectedMisconfigure {
+				return
+			}
+
+			expectedClusterNames := sets.NewString(c.expectedClusterNames...)
+			actualClusterNames := sets.NewString()
+			for _, cluster := range result.Filtered {
+				actualClusterNames.Insert(cluster.Name)
+			}
+			if !expectedClusterNames.Equal(actualClusterNames) {
+				t.Errorf("expected clusters %v, but got %v", expectedClusterNames.List(), actualClusterNames.List())
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package resource
+
+import (
+	"fmt"
+
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+)
+
+// SchedulingModeAnnotationKey is the annotation on the placement to choose how the resource prioritizers
+// score the clusters. The mode is either Spread or BinPack, the default mode is Spread.
+const SchedulingModeAnnotationKey = "cluster.open-cluster-management.io/scheduling-mode"
+
+type SchedulingMode string
+
+const (
+	// SchedulingModeSpread prefers the clusters with the most allocatable resources.
+	SchedulingModeSpread SchedulingMode = "Spread"
+	// SchedulingModeBinPack prefers the most utilized clusters, which have the least allocatable resources,
+	// so the workloads are consolidated and the other clusters are able to be drained and turned off.
+	SchedulingModeBinPack SchedulingMode = "BinPack"
+)
+
+// GetSchedulingMode returns the scheduling mode of the placement.
+func GetSchedulingMode(placement *clusterapiv1beta1.Placement) (SchedulingMode, error) {
+	mode := SchedulingMode(placement.GetAnnotations()[SchedulingModeAnnotationKey])
+	switch mode {
+	case "":
+		return SchedulingModeSpread, nil
+	case SchedulingModeSpread, SchedulingModeBinPack:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid value %q of annotation %s, it should be %s or %s",
+			mode, SchedulingModeAnnotationKey, SchedulingModeSpread, SchedulingModeBinPack)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"strings"
 
 	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
 	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
@@ -20,7 +21,16 @@ const (
 	decisions based on the resource allocatable of managed clusters.
 	The clusters that has the most allocatable are given the highest score,
 	while the least is given the lowest score.
+	If the placement is in BinPack mode, the scores are reversed, so the most utilized clusters
+	are preferred.
+	ResourceAllocatable:<resource name> prioritizer, for example ResourceAllocatable:nvidia.com/gpu,
+	does the same for any resource published in the allocatable of managed clusters, and the clusters
+	without the resource are given the lowest score.
 	`
+
+	// ResourceAllocatablePrefix is the prefix of the prioritizer names scoring the clusters with
+	// the allocatable of an arbitrary resource, for example ResourceAllocatable:nvidia.com/gpu.
+	ResourceAllocatablePrefix = "ResourceAllocatable:"
 )
 
 var _ plugins.Prioritizer = &ResourcePrioritizer{}
@@ -35,6 +45,8 @@ type ResourcePrioritizer struct {
 	prioritizerName string
 	algorithm       string
 	resource        clusterapiv1.ResourceName
+	// missingAsZero treats the clusters without the resource as having zero allocatable
+	missingAsZero bool
 }
 
 type ResourcePrioritizerBuilder struct {
@@ -58,12 +70,20 @@ func (r *ResourcePrioritizerBuilder) Build() *ResourcePrioritizer {
 	algorithm, resource := parsePrioritizerName(r.resourcePrioritizer.prioritizerName)
 	r.resourcePrioritizer.algorithm = algorithm
 	r.resourcePrioritizer.resource = resource
+	r.resourcePrioritizer.missingAsZero = strings.HasPrefix(r.resourcePrioritizer.prioritizerName, ResourceAllocatablePrefix)
 	return r.resourcePrioritizer
 }
 
 // parese prioritizerName to algorithm and resource.
-// For example, prioritizerName ResourceAllocatableCPU will return Allocatable, CPU.
+// For example, prioritizerName ResourceAllocatableCPU will return Allocatable, CPU,
+// and ResourceAllocatable:nvidia.com/gpu will return Allocatable, nvidia.com/gpu.
 func parsePrioritizerName(prioritizerName string) (algorithm string, resource clusterapiv1.ResourceName) {
+	if name := strings.TrimPrefix(prioritizerName, ResourceAllocatablePrefix); name != prioritizerName {
+		if len(name) == 0 {
+			return "", ""
+		}
+		return "Allocatable", clusterapiv1.ResourceName(name)
+	}
 	s := regexp.MustCompile("[A-Z]+[a-z]*").FindAllString(prioritizerName, -1)
 	if len(s) == 3 {
 		return s[1], resourceMap[s[2]]
@@ -82,8 +102,19 @@ func (r *ResourcePrioritizer) Description() string {
 func (r *ResourcePrioritizer) Score(ctx context.Context, placement *clusterapiv1beta1.Placement,
 	clusters []*clusterapiv1.ManagedCluster) (plugins.PluginScoreResult, *framework.Status) {
 	status := framework.NewStatus(r.Name(), framework.Success, "")
+	mode, err := GetSchedulingMode(placement)
+	if err != nil {
+		return plugins.PluginScoreResult{}, framework.NewStatus(r.Name(), framework.Misconfigured, err.Error())
+	}
 	if r.algorithm == "Allocatable" {
-		return mostResourceAllocatableScores(r.resource, clusters), status
+		result := mostResourceAllocatableScores(r.resource, clusters, r.missingAsZero)
+		// in BinPack mode, the clusters with the least allocatable are given the highest score
+		if mode == SchedulingModeBinPack {
+			for name, score := range result.Scores {
+				result.Scores[name] = -score
+			}
+		}
+		return result, status
 	}
 	return plugins.PluginScoreResult{}, status
 }
@@ -95,7 +126,8 @@ func (r *ResourcePrioritizer) RequeueAfter(ctx context.Context, placement *clust
 // Calculate clusters scores based on the resource allocatable.
 // The clusters that has the most allocatable are given the highest score, while the least is given the lowest score.
 // The score range is from -100 to 100.
-func mostResourceAllocatableScores(resourceName clusterapiv1.ResourceName, clusters []*clusterapiv1.ManagedCluster) plugins.PluginScoreResult {
+func mostResourceAllocatableScores(resourceName clusterapiv1.ResourceName, clusters []*clusterapiv1.ManagedCluster,
+	missingAsZero bool) plugins.PluginScoreResult {
 	scores := map[string]int64{}
 
 	// get resourceName's min and max allocatable among all the clusters
@@ -105,12 +137,18 @@ func mostResourceAllocatableScores(resourceName clusterapiv1.ResourceName, clust
 			Scores: scores,
 		}
 	}
+	if missingAsZero && minAllocatable > 0 && hasClusterWithoutResource(clusters, resourceName) {
+		minAllocatable = 0
+	}
 
 	for _, cluster := range clusters {
 		// get one cluster resourceName's allocatable
 		allocatable, _, err := getClusterResource(cluster, resourceName)
 		if err != nil {
-			continue
+			if !missingAsZero {
+				continue
+			}
+			allocatable = 0
 		}
 
 		// score = ((resource_x_allocatable - min(resource_x_allocatable)) / (max(resource_x_allocatable) - min(resource_x_allocatable)) - 0.5) * 2 * 100
@@ -165,3 +203,13 @@ func getClustersMinMaxAllocatableResource(clusters []*clusterapiv1.ManagedCluste
 	sort.Float64s(allocatable)
 	return allocatable[0], allocatable[len(allocatable)-1], nil
 }
+
+// hasClusterWithoutResource returns true if any of the clusters does not publish the allocatable of the resourceName.
+func hasClusterWithoutResource(clusters []*clusterapiv1.ManagedCluster, resourceName clusterapiv1.ResourceName) bool {
+	for _, cluster := range clusters {
+		if _, exist := cluster.Status.Allocatable[resourceName]; !exist {
+			return true
+		}
+	}
+	return false
+}
@@ -143,3 +143,55 @@ func TestScoreClusterWithResource(t *testing.T) {
 		})
 	}
 }
+
+func TestScoreClusterWithCustomResource(t *testing.T) {
+	cases := []struct {
+		name            string
+		prioritizerName string
+		clusters        []*clusterapiv1.ManagedCluster
+		expectedScores  map[string]int64
+	}{
+		{
+			name:            "scores of gpu",
+			prioritizerName: ResourceAllocatablePrefix + "nvidia.com/gpu",
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithResource("nvidia.com/gpu", "2", "8").Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithResource("nvidia.com/gpu", "8", "8").Build(),
+				testinghelpers.NewManagedCluster("cluster3").Build(),
+			},
+			expectedScores: map[string]int64{"cluster1": -50, "cluster2": 100, "cluster3": -100},
+		},
+		{
+			name:            "scores of hugepages",
+			prioritizerName: ResourceAllocatablePrefix + "hugepages-2Mi",
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithResource("hugepages-2Mi", "1Gi", "2Gi").Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithResource("hugepages-2Mi", "2Gi", "2Gi").Build(),
+			},
+			expectedScores: map[string]int64{"cluster1": -100, "cluster2": 100},
+		},
+		{
+			name:            "no cluster has the resource",
+			prioritizerName: ResourceAllocatablePrefix + "nvidia.com/gpu",
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").Build(),
+			},
+			expectedScores: map[string]int64{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			resource := NewResourcePrioritizerBuilder(testinghelpers.NewFakePluginHandle(t, nil)).
+				WithPrioritizerName(c.prioritizerName).Build()
+
+			scoreResult, status := resource.Score(context.TODO(), testinghelpers.NewPlacement("test", "test").Build(), c.clusters)
+			if err := status.AsError(); err != nil {
+				t.Errorf("Expect no error, but got %v", err)
+			}
+			if !apiequality.Semantic.DeepEqual(scoreResult.Scores, c.expectedScores) {
+				t.Errorf("Expect score %v, but got %v", c.expectedScores, scoreResult.Scores)
+			}
+		})
+	}
+}
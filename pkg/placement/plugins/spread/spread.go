@@ -0,0 +1,212 @@
+package spread
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/helpers"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+const description = `
+	Spread selector selects the decisions from the clusters sorted by the scores, and ensures the decisions
+	are spread among the topologies defined in the spread policy of the placement. A cluster is skipped
+	if selecting it makes the skew of a topology exceed the MaxSkew of a DoNotSchedule constraint. When
+	no cluster is able to satisfy the ScheduleAnyway constraints, the cluster with the least skew is selected.
+	`
+
+var _ plugins.Selector = &Spread{}
+
+type Spread struct{}
+
+func New(handle plugins.Handle) *Spread {
+	return &Spread{}
+}
+
+func (s *Spread) Name() string {
+	return reflect.TypeOf(*s).Name()
+}
+
+func (s *Spread) Description() string {
+	return description
+}
+
+func (s *Spread) Select(
+	ctx context.Context, placement *clusterapiv1beta1.Placement,
+	clusters []*clusterapiv1.ManagedCluster, num int) (plugins.PluginSelectResult, *framework.Status) {
+	status := framework.NewStatus(s.Name(), framework.Success, "")
+
+	constraints := placement.Spec.SpreadPolicy.SpreadConstraints
+	if len(constraints) == 0 {
+		// truncate the cluster slice if the desired number of decisions is less than
+		// the number of the candidate clusters
+		if num < len(clusters) {
+			clusters = clusters[:num]
+		}
+		return plugins.PluginSelectResult{Selected: clusters}, status
+	}
+
+	for _, constraint := range constraints {
+		if constraint.TopologyKeyType != clusterapiv1beta1.TopologyKeyTypeLabel &&
+			constraint.TopologyKeyType != clusterapiv1beta1.TopologyKeyTypeClaim {
+			return plugins.PluginSelectResult{}, framework.NewStatus(
+				s.Name(),
+				framework.Misconfigured,
+				fmt.Sprintf("incorrect topology key type %q of topology key %q", constraint.TopologyKeyType, constraint.TopologyKey),
+			)
+		}
+	}
+
+	topologies := newTopologies(constraints, clusters)
+	selected := []*clusterapiv1.ManagedCluster{}
+	candidates := clusters
+	for len(selected) < num {
+		index := topologies.pick(candidates)
+		if index < 0 {
+			break
+		}
+		topologies.add(candidates[index])
+		selected = append(selected, candidates[index])
+		candidates = append(candidates[:index:index], candidates[index+1:]...)
+	}
+
+	return plugins.PluginSelectResult{Selected: selected}, status
+}
+
+func (s *Spread) RequeueAfter(ctx context.Context, placement *clusterapiv1beta1.Placement) (plugins.PluginRequeueResult, *framework.Status) {
+	return plugins.PluginRequeueResult{}, framework.NewStatus(s.Name(), framework.Success, "")
+}
+
+// topologies tracks the number of the selected clusters in each topology of the spread constraints.
+type topologies struct {
+	constraints []clusterapiv1beta1.SpreadConstraintsTerm
+	// counts has a map for each constraint with the topology value as the key. All the topologies of
+	// the candidate clusters are in the map, so the global minimum takes the empty topologies into account.
+	counts []map[string]int
+}
+
+func newTopologies(constraints []clusterapiv1beta1.SpreadConstraintsTerm, clusters []*clusterapiv1.ManagedCluster) *topologies {
+	t := &topologies{constraints: constraints}
+	for _, constraint := range constraints {
+		counts := map[string]int{}
+		for _, cluster := range clusters {
+			if value, ok := topologyValue(constraint, cluster); ok {
+				counts[value] = 0
+			}
+		}
+		t.counts = append(t.counts, counts)
+	}
+	return t
+}
+
+// pick returns the index of the cluster to select next, or -1 if none of the clusters is able to be
+// selected. The clusters are in the order of their scores, so the first cluster satisfying all the
+// constraints is picked. Otherwise the cluster satisfying the DoNotSchedule constraints with the least
+// skew of the ScheduleAnyway constraints, in the order of the constraints, is picked.
+func (t *topologies) pick(clusters []*clusterapiv1.ManagedCluster) int {
+	picked := -1
+	var pickedSkews []int
+	for i, cluster := range clusters {
+		skews, ok := t.skews(cluster)
+		if !ok {
+			continue
+		}
+		if picked < 0 || lessSkews(skews, pickedSkews) {
+			picked, pickedSkews = i, skews
+		}
+		if isSatisfied(skews) {
+			break
+		}
+	}
+	return picked
+}
+
+// skews returns how much the skew of each ScheduleAnyway constraint exceeds its MaxSkew after the cluster
+// is selected. It returns false if selecting the cluster violates a DoNotSchedule constraint.
+func (t *topologies) skews(cluster *clusterapiv1.ManagedCluster) ([]int, bool) {
+	var skews []int
+	for i, constraint := range t.constraints {
+		exceeded := 0
+		value, ok := topologyValue(constraint, cluster)
+		if ok {
+			exceeded = t.skewAfterAdding(i, value) - maxSkew(constraint)
+		}
+		if constraint.WhenUnsatisfiable == clusterapiv1beta1.DoNotSchedule {
+			// a cluster without the topology key is not able to satisfy the constraint
+			if !ok || exceeded > 0 {
+				return nil, false
+			}
+			continue
+		}
+		if exceeded < 0 {
+			exceeded = 0
+		}
+		skews = append(skews, exceeded)
+	}
+	return skews, true
+}
+
+// skewAfterAdding returns the skew of the topology after a cluster in it is selected.
+func (t *topologies) skewAfterAdding(index int, value string) int {
+	counts := t.counts[index]
+	minimum := -1
+	for v, count := range counts {
+		if v == value {
+			count++
+		}
+		if minimum < 0 || count < minimum {
+			minimum = count
+		}
+	}
+	return counts[value] + 1 - minimum
+}
+
+func (t *topologies) add(cluster *clusterapiv1.ManagedCluster) {
+	for i, constraint := range t.constraints {
+		if value, ok := topologyValue(constraint, cluster); ok {
+			t.counts[i][value]++
+		}
+	}
+}
+
+func topologyValue(constraint clusterapiv1beta1.SpreadConstraintsTerm, cluster *clusterapiv1.ManagedCluster) (string, bool) {
+	var value string
+	var ok bool
+	switch constraint.TopologyKeyType {
+	case clusterapiv1beta1.TopologyKeyTypeLabel:
+		value, ok = cluster.Labels[constraint.TopologyKey]
+	case clusterapiv1beta1.TopologyKeyTypeClaim:
+		value, ok = helpers.GetClusterClaims(cluster)[constraint.TopologyKey]
+	}
+	return value, ok
+}
+
+func maxSkew(constraint clusterapiv1beta1.SpreadConstraintsTerm) int {
+	if constraint.MaxSkew < 1 {
+		return 1
+	}
+	return int(constraint.MaxSkew)
+}
+
+func isSatisfied(skews []int) bool {
+	for _, skew := range skews {
+		if skew > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func lessSkews(a, b []int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
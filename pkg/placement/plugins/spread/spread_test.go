@@ -0,0 +1,123 @@
+package spread
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestSelect(t *testing.T) {
+	// the clusters are sorted by the scores
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").WithLabel("zone", "a").WithClaim("provider", "aws").Build(),
+		testinghelpers.NewManagedCluster("cluster2").WithLabel("zone", "a").WithClaim("provider", "aws").Build(),
+		testinghelpers.NewManagedCluster("cluster3").WithLabel("zone", "a").WithClaim("provider", "gcp").Build(),
+		testinghelpers.NewManagedCluster("cluster4").WithLabel("zone", "b").WithClaim("provider", "aws").Build(),
+		testinghelpers.NewManagedCluster("cluster5").WithLabel("zone", "c").WithClaim("provider", "gcp").Build(),
+		testinghelpers.NewManagedCluster("cluster6").WithClaim("provider", "azure").Build(),
+	}
+
+	zoneConstraint := func(maxSkew int32, action clusterapiv1beta1.UnsatisfiableMaxSkewAction) clusterapiv1beta1.SpreadConstraintsTerm {
+		return clusterapiv1beta1.SpreadConstraintsTerm{
+			TopologyKey:       "zone",
+			TopologyKeyType:   clusterapiv1beta1.TopologyKeyTypeLabel,
+			MaxSkew:           maxSkew,
+			WhenUnsatisfiable: action,
+		}
+	}
+	providerConstraint := func(maxSkew int32, action clusterapiv1beta1.UnsatisfiableMaxSkewAction) clusterapiv1beta1.SpreadConstraintsTerm {
+		return clusterapiv1beta1.SpreadConstraintsTerm{
+			TopologyKey:       "provider",
+			TopologyKeyType:   clusterapiv1beta1.TopologyKeyTypeClaim,
+			MaxSkew:           maxSkew,
+			WhenUnsatisfiable: action,
+		}
+	}
+
+	cases := []struct {
+		name             string
+		constraints      []clusterapiv1beta1.SpreadConstraintsTerm
+		num              int
+		expectedCode     framework.Code
+		expectedSelected []string
+	}{
+		{
+			name:             "no spread constraints",
+			num:              3,
+			expectedSelected: []string{"cluster1", "cluster2", "cluster3"},
+		},
+		{
+			name:             "no spread constraints with less clusters",
+			num:              10,
+			expectedSelected: []string{"cluster1", "cluster2", "cluster3", "cluster4", "cluster5", "cluster6"},
+		},
+		{
+			name:             "spread among zones",
+			constraints:      []clusterapiv1beta1.SpreadConstraintsTerm{zoneConstraint(1, clusterapiv1beta1.DoNotSchedule)},
+			num:              3,
+			expectedSelected: []string{"cluster1", "cluster4", "cluster5"},
+		},
+		{
+			name:             "spread among zones with max skew",
+			constraints:      []clusterapiv1beta1.SpreadConstraintsTerm{zoneConstraint(2, clusterapiv1beta1.DoNotSchedule)},
+			num:              3,
+			expectedSelected: []string{"cluster1", "cluster2", "cluster4"},
+		},
+		{
+			name:             "do not schedule when max skew is not satisfied",
+			constraints:      []clusterapiv1beta1.SpreadConstraintsTerm{zoneConstraint(1, clusterapiv1beta1.DoNotSchedule)},
+			num:              5,
+			expectedSelected: []string{"cluster1", "cluster4", "cluster5", "cluster2"},
+		},
+		{
+			name:             "schedule anyway when max skew is not satisfied",
+			constraints:      []clusterapiv1beta1.SpreadConstraintsTerm{zoneConstraint(1, clusterapiv1beta1.ScheduleAnyway)},
+			num:              5,
+			expectedSelected: []string{"cluster1", "cluster4", "cluster5", "cluster2", "cluster6"},
+		},
+		{
+			name: "spread among zones and providers",
+			constraints: []clusterapiv1beta1.SpreadConstraintsTerm{
+				zoneConstraint(1, clusterapiv1beta1.ScheduleAnyway),
+				providerConstraint(1, clusterapiv1beta1.DoNotSchedule),
+			},
+			num:              3,
+			expectedSelected: []string{"cluster1", "cluster5", "cluster6"},
+		},
+		{
+			name: "incorrect topology key type",
+			constraints: []clusterapiv1beta1.SpreadConstraintsTerm{
+				{TopologyKey: "zone", TopologyKeyType: "Annotation"},
+			},
+			num:          3,
+			expectedCode: framework.Misconfigured,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			placement := testinghelpers.NewPlacement("test", "test").Build()
+			placement.Spec.SpreadPolicy.SpreadConstraints = c.constraints
+
+			s := &Spread{}
+			result, status := s.Select(context.TODO(), placement, clusters, c.num)
+			if status.Code() != c.expectedCode {
+				t.Fatalf("expected status code %v, but got %v", c.expectedCode, status)
+			}
+
+			var selected []string
+			for _, cluster := range result.Selected {
+				selected = append(selected, cluster.Name)
+			}
+			if !reflect.DeepEqual(selected, c.expectedSelected) {
+				t.Errorf("expected selected clusters %v, but got %v", c.expectedSelected, selected)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package tainttoleration
+
+import (
+	"hash/fnv"
+	"time"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+)
+
+const (
+	// ManagedClusterTaintCordoned is the key of the taint added to a managed cluster to cordon it for
+	// maintenance or decommissioning. The taint is expected to have the effect NoSelectIfNew, so the
+	// placements stop selecting the cluster while the existing decisions are kept. If the value of the
+	// taint is Drain, the existing decisions are replaced as well, spread over the drain window of the
+	// cluster.
+	ManagedClusterTaintCordoned = "cluster.open-cluster-management.io/cordoned"
+
+	// CordonedTaintValueDrain is the value of the cordon taint to drain the existing decisions.
+	CordonedTaintValueDrain = "Drain"
+
+	// DrainWindowAnnotationKey is the annotation on the managed cluster to set the duration over which the
+	// decisions on a draining cluster are replaced, for example "30m". Each placement releases the cluster
+	// at a stable point of the window after the taint is added, so the decisions are moved to the
+	// replacements at a controlled rate rather than all at once. The decisions are replaced immediately if
+	// it is not set.
+	DrainWindowAnnotationKey = "cluster.open-cluster-management.io/drain-window"
+)
+
+// isDrainTaint returns true if the taint is a cordon taint which drains the existing decisions.
+func isDrainTaint(taint clusterapiv1.Taint) bool {
+	return taint.Key == ManagedClusterTaintCordoned &&
+		taint.Value == CordonedTaintValueDrain &&
+		taint.Effect == clusterapiv1.TaintEffectNoSelectIfNew
+}
+
+// drainReleaseTime returns the time from which the placement no longer keeps the draining cluster in its
+// decisions. The offset of the placement in the drain window is derived from a hash of the placement, so
+// it does not change across the schedulings.
+func drainReleaseTime(cluster *clusterapiv1.ManagedCluster, taint clusterapiv1.Taint,
+	placement *clusterapiv1beta1.Placement) time.Time {
+	window, err := time.ParseDuration(cluster.Annotations[DrainWindowAnnotationKey])
+	if err != nil || window <= 0 {
+		return taint.TimeAdded.Time
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(placement.Namespace + "/" + placement.Name))
+	offset := time.Duration(float64(window) * float64(h.Sum32()) / float64(1<<32))
+	return taint.TimeAdded.Add(offset)
+}
@@ -0,0 +1,112 @@
+package tainttoleration
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	testingclock "k8s.io/utils/clock/testing"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func newCordonedCluster(name, value string, timeAdded time.Time) *testinghelpers.ManagedClusterBuilder {
+	return testinghelpers.NewManagedCluster(name).WithTaint(&clusterapiv1.Taint{
+		Key:       ManagedClusterTaintCordoned,
+		Value:     value,
+		Effect:    clusterapiv1.TaintEffectNoSelectIfNew,
+		TimeAdded: metav1.NewTime(timeAdded),
+	})
+}
+
+func TestCordonAndDrain(t *testing.T) {
+	TolerationClock = testingclock.NewFakeClock(fakeTime)
+	placement := testinghelpers.NewPlacement("test", "test").Build()
+	window := 10 * time.Minute
+	release := drainReleaseTime(
+		newCordonedCluster("cluster1", CordonedTaintValueDrain, fakeTime).WithAnnotation(DrainWindowAnnotationKey, window.String()).Build(),
+		clusterapiv1.Taint{TimeAdded: metav1.NewTime(fakeTime)}, placement)
+	if release.Before(fakeTime) || !release.Before(fakeTime.Add(window)) {
+		t.Fatalf("expected release time in the drain window, but got %v", release)
+	}
+
+	cases := []struct {
+		name                 string
+		clusters             []*clusterapiv1.ManagedCluster
+		expectedClusterNames []string
+		expectedRequeueTime  *time.Time
+	}{
+		{
+			name: "cordoned clusters are not selected but the decisions are kept",
+			clusters: []*clusterapiv1.ManagedCluster{
+				newCordonedCluster("cluster1", "", fakeTime).Build(),
+				newCordonedCluster("cluster3", "", fakeTime).Build(),
+			},
+			expectedClusterNames: []string{"cluster1"},
+		},
+		{
+			name: "drain without window",
+			clusters: []*clusterapiv1.ManagedCluster{
+				newCordonedCluster("cluster1", CordonedTaintValueDrain, fakeTime).Build(),
+				testinghelpers.NewManagedCluster("cluster2").Build(),
+			},
+			expectedClusterNames: []string{"cluster2"},
+		},
+		{
+			name: "drain is not started for the placement",
+			clusters: []*clusterapiv1.ManagedCluster{
+				newCordonedCluster("cluster1", CordonedTaintValueDrain, fakeTime).
+					WithAnnotation(DrainWindowAnnotationKey, window.String()).Build(),
+				testinghelpers.NewManagedCluster("cluster2").Build(),
+			},
+			expectedClusterNames: []string{"cluster1", "cluster2"},
+			expectedRequeueTime:  &release,
+		},
+		{
+			name: "drain is started for the placement",
+			clusters: []*clusterapiv1.ManagedCluster{
+				newCordonedCluster("cluster1", CordonedTaintValueDrain, fakeTime.Add(-window)).
+					WithAnnotation(DrainWindowAnnotationKey, window.String()).Build(),
+				testinghelpers.NewManagedCluster("cluster2").Build(),
+			},
+			expectedClusterNames: []string{"cluster2"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			initObjs := []runtime.Object{
+				testinghelpers.NewPlacementDecision("test", "test").
+					WithLabel(placementLabel, "test").
+					WithDecisions("cluster1", "cluster2").
+					Build(),
+			}
+			for _, cluster := range c.clusters {
+				initObjs = append(initObjs, cluster)
+			}
+			p := New(testinghelpers.NewFakePluginHandle(t, nil, initObjs...))
+
+			result, status := p.Filter(context.TODO(), placement, c.clusters)
+			if err := status.AsError(); err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, cluster := range result.Filtered {
+				names = append(names, cluster.Name)
+			}
+			if !reflect.DeepEqual(names, c.expectedClusterNames) {
+				t.Errorf("expected clusters %v, but got %v", c.expectedClusterNames, names)
+			}
+
+			requeue, _ := p.RequeueAfter(context.TODO(), placement)
+			if !reflect.DeepEqual(requeue.RequeueTime, c.expectedRequeueTime) {
+				t.Errorf("expected requeue time %v, but got %v", c.expectedRequeueTime, requeue.RequeueTime)
+			}
+		})
+	}
+}
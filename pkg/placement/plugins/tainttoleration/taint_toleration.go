@@ -78,7 +78,7 @@ func (pl *TaintToleration) Filter(ctx context.Context, placement *clusterapiv1be
 	// filter the clusters
 	matched := []*clusterapiv1.ManagedCluster{}
 	for _, cluster := range clusters {
-		if tolerated, _, _ := isClusterTolerated(cluster, placement.Spec.Tolerations, decisionClusterNames.Has(cluster.Name)); tolerated {
+		if tolerated, _, _ := isClusterTolerated(cluster, placement, decisionClusterNames.Has(cluster.Name)); tolerated {
 			matched = append(matched, cluster)
 		}
 	}
@@ -99,7 +99,7 @@ func (pl *TaintToleration) RequeueAfter(ctx context.Context, placement *clustera
 	var minRequeue *plugins.PluginRequeueResult
 	// filter and record pluginRequeueResults
 	for _, cluster := range decisionClusters {
-		if tolerated, requeue, msg := isClusterTolerated(cluster, placement.Spec.Tolerations, decisionClusterNames.Has(cluster.Name)); tolerated {
+		if tolerated, requeue, msg := isClusterTolerated(cluster, placement, decisionClusterNames.Has(cluster.Name)); tolerated {
 			minRequeue = minRequeueTime(minRequeue, requeue)
 		} else {
 			status.AppendReason(msg)
@@ -114,11 +114,18 @@ func (pl *TaintToleration) RequeueAfter(ctx context.Context, placement *clustera
 }
 
 // isClusterTolerated returns true if a cluster is tolerated by the given toleration array
-func isClusterTolerated(cluster *clusterapiv1.ManagedCluster, tolerations []clusterapiv1beta1.Toleration,
+func isClusterTolerated(cluster *clusterapiv1.ManagedCluster, placement *clusterapiv1beta1.Placement,
 	inDecision bool) (bool, *plugins.PluginRequeueResult, string) {
 	var minRequeue *plugins.PluginRequeueResult
 	for _, taint := range cluster.Spec.Taints {
-		tolerated, requeue, message := isTaintTolerated(taint, tolerations, inDecision)
+		var tolerated bool
+		var requeue *plugins.PluginRequeueResult
+		var message string
+		if isDrainTaint(taint) && inDecision {
+			tolerated, requeue, message = isDrainTaintTolerated(cluster, taint, placement)
+		} else {
+			tolerated, requeue, message = isTaintTolerated(taint, placement.Spec.Tolerations, inDecision)
+		}
 		if !tolerated {
 			return false, nil, message
 		}
@@ -150,6 +157,23 @@ func isTaintTolerated(taint clusterapiv1.Taint, tolerations []clusterapiv1beta1.
 	return false, nil, message
 }
 
+// isDrainTaintTolerated returns true if the draining cluster in the decisions is not released by the placement
+// yet, or the placement tolerates the cordon taint.
+func isDrainTaintTolerated(cluster *clusterapiv1.ManagedCluster, taint clusterapiv1.Taint,
+	placement *clusterapiv1beta1.Placement) (bool, *plugins.PluginRequeueResult, string) {
+	releaseTime := drainReleaseTime(cluster, taint, placement)
+	if TolerationClock.Now().Before(releaseTime) {
+		return true, &plugins.PluginRequeueResult{RequeueTime: &releaseTime}, ""
+	}
+
+	// the draining cluster is treated as a new one once it is released
+	tolerated, requeue, message := isTaintTolerated(taint, placement.Spec.Tolerations, false)
+	if !tolerated {
+		message = fmt.Sprintf("Cluster %s is drained", cluster.Name)
+	}
+	return tolerated, requeue, message
+}
+
 // isTolerated returns true if a taint is tolerated by the given toleration
 func isTolerated(taint clusterapiv1.Taint, toleration clusterapiv1beta1.Toleration) (bool, *plugins.PluginRequeueResult, string) {
 	if len(toleration.Effect) > 0 && toleration.Effect != taint.Effect {
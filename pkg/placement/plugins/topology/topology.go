@@ -0,0 +1,137 @@
+package topology
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+const (
+	// PreferredRegionsAnnotationKey is a comma separated list of the preferred regions of the placement.
+	PreferredRegionsAnnotationKey = "cluster.open-cluster-management.io/preferred-regions"
+	// PreferredZonesAnnotationKey is a comma separated list of the preferred zones of the placement.
+	PreferredZonesAnnotationKey = "cluster.open-cluster-management.io/preferred-zones"
+	// PreferredTopologyClusterAnnotationKey is the name of a managed cluster, the region and zone of which
+	// are preferred by the placement, e.g. the cluster hosting the data the workloads depend on.
+	PreferredTopologyClusterAnnotationKey = "cluster.open-cluster-management.io/preferred-topology-cluster"
+
+	// RegionClusterClaimName and ZoneClusterClaimName are the cluster claims reporting the topology of a
+	// managed cluster. The well-known topology labels on the managed cluster are used if the claims do not exist.
+	RegionClusterClaimName = "region.open-cluster-management.io"
+	ZoneClusterClaimName   = "zone.open-cluster-management.io"
+	regionLabel            = "topology.kubernetes.io/region"
+	zoneLabel              = "topology.kubernetes.io/zone"
+
+	description = `
+	Topology prioritizer scores the clusters by their region and zone relative to the preferred topology
+	of the placement. The clusters in a preferred zone are given the highest score, the clusters in a
+	preferred region are given the half of the highest score, and the other clusters are given 0.
+	`
+)
+
+var _ plugins.Prioritizer = &Topology{}
+
+type Topology struct {
+	handle plugins.Handle
+}
+
+func New(handle plugins.Handle) *Topology {
+	return &Topology{
+		handle: handle,
+	}
+}
+
+func (t *Topology) Name() string {
+	return reflect.TypeOf(*t).Name()
+}
+
+func (t *Topology) Description() string {
+	return description
+}
+
+func (t *Topology) Score(
+	ctx context.Context, placement *clusterapiv1beta1.Placement, clusters []*clusterapiv1.ManagedCluster) (plugins.PluginScoreResult, *framework.Status) {
+	annotations := placement.GetAnnotations()
+	preferredRegions := splitList(annotations[PreferredRegionsAnnotationKey])
+	preferredZones := splitList(annotations[PreferredZonesAnnotationKey])
+
+	status := framework.NewStatus(t.Name(), framework.Success, "")
+	if clusterName := annotations[PreferredTopologyClusterAnnotationKey]; len(clusterName) > 0 {
+		cluster, err := t.handle.ClusterLister().Get(clusterName)
+		switch {
+		case errors.IsNotFound(err):
+			status = framework.NewStatus(t.Name(), framework.Warning,
+				fmt.Sprintf("the preferred topology cluster %q is not found", clusterName))
+		case err != nil:
+			return plugins.PluginScoreResult{}, framework.NewStatus(t.Name(), framework.Error, err.Error())
+		default:
+			region, zone := getTopology(cluster)
+			if len(region) > 0 {
+				preferredRegions.Insert(region)
+			}
+			if len(zone) > 0 {
+				preferredZones.Insert(zone)
+			}
+		}
+	}
+
+	scores := map[string]int64{}
+	for _, cluster := range clusters {
+		region, zone := getTopology(cluster)
+		switch {
+		case len(zone) > 0 && preferredZones.Has(zone):
+			scores[cluster.Name] = plugins.MaxClusterScore
+		case len(region) > 0 && preferredRegions.Has(region):
+			scores[cluster.Name] = plugins.MaxClusterScore / 2
+		default:
+			scores[cluster.Name] = 0
+		}
+	}
+
+	return plugins.PluginScoreResult{
+		Scores: scores,
+	}, status
+}
+
+func (t *Topology) RequeueAfter(ctx context.Context, placement *clusterapiv1beta1.Placement) (plugins.PluginRequeueResult, *framework.Status) {
+	return plugins.PluginRequeueResult{}, framework.NewStatus(t.Name(), framework.Success, "")
+}
+
+// getTopology returns the region and zone of a cluster from its cluster claims or labels.
+func getTopology(cluster *clusterapiv1.ManagedCluster) (region, zone string) {
+	for _, claim := range cluster.Status.ClusterClaims {
+		switch claim.Name {
+		case RegionClusterClaimName:
+			region = claim.Value
+		case ZoneClusterClaimName:
+			zone = claim.Value
+		}
+	}
+	if len(region) == 0 {
+		region = cluster.Labels[regionLabel]
+	}
+	if len(zone) == 0 {
+		zone = cluster.Labels[zoneLabel]
+	}
+	return region, zone
+}
+
+func splitList(value string) sets.Set[string] {
+	items := sets.New[string]()
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			items.Insert(item)
+		}
+	}
+	return items
+}
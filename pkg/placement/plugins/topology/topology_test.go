@@ -0,0 +1,90 @@
+package topology
+
+import (
+	"context"
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestScoreClusterWithTopology(t *testing.T) {
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").
+			WithClaim(RegionClusterClaimName, "us-east").WithClaim(ZoneClusterClaimName, "us-east-1a").Build(),
+		testinghelpers.NewManagedCluster("cluster2").
+			WithClaim(RegionClusterClaimName, "us-east").WithClaim(ZoneClusterClaimName, "us-east-1b").Build(),
+		testinghelpers.NewManagedCluster("cluster3").
+			WithLabel(regionLabel, "eu-west").WithLabel(zoneLabel, "eu-west-1a").Build(),
+		testinghelpers.NewManagedCluster("cluster4").Build(),
+	}
+
+	cases := []struct {
+		name           string
+		placement      *clusterapiv1beta1.Placement
+		objects        []runtime.Object
+		expectedCode   framework.Code
+		expectedScores map[string]int64
+	}{
+		{
+			name:           "no preferred topology",
+			placement:      testinghelpers.NewPlacement("test", "test").Build(),
+			expectedScores: map[string]int64{"cluster1": 0, "cluster2": 0, "cluster3": 0, "cluster4": 0},
+		},
+		{
+			name: "preferred regions",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				PreferredRegionsAnnotationKey: "us-east, eu-west",
+			}).Build(),
+			expectedScores: map[string]int64{"cluster1": 50, "cluster2": 50, "cluster3": 50, "cluster4": 0},
+		},
+		{
+			name: "preferred zones and regions",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				PreferredRegionsAnnotationKey: "us-east",
+				PreferredZonesAnnotationKey:   "us-east-1b,eu-west-1a",
+			}).Build(),
+			expectedScores: map[string]int64{"cluster1": 50, "cluster2": 100, "cluster3": 100, "cluster4": 0},
+		},
+		{
+			name: "preferred topology cluster",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				PreferredTopologyClusterAnnotationKey: "cluster1",
+			}).Build(),
+			objects:        []runtime.Object{clusters[0]},
+			expectedScores: map[string]int64{"cluster1": 100, "cluster2": 50, "cluster3": 0, "cluster4": 0},
+		},
+		{
+			name: "preferred topology cluster not found",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				PreferredTopologyClusterAnnotationKey: "cluster1",
+				PreferredRegionsAnnotationKey:         "eu-west",
+			}).Build(),
+			expectedCode:   framework.Warning,
+			expectedScores: map[string]int64{"cluster1": 0, "cluster2": 0, "cluster3": 50, "cluster4": 0},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			topology := &Topology{
+				handle: testinghelpers.NewFakePluginHandle(t, nil, c.objects...),
+			}
+
+			scoreResult, status := topology.Score(context.TODO(), c.placement, clusters)
+			if status.Code() != c.expectedCode {
+				t.Errorf("Expect status code %v, but got %v", c.expectedCode, status.Code())
+			}
+
+			if !apiequality.Semantic.DeepEqual(scoreResult.Scores, c.expectedScores) {
+				t.Errorf("Expect score %v, but got %v", c.expectedScores, scoreResult.Scores)
+			}
+		})
+	}
+}
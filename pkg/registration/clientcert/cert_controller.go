@@ -4,8 +4,8 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509/pkix"
+	"errors"
 	"fmt"
-	"math/rand"
 	"reflect"
 	"time"
 
@@ -21,6 +21,10 @@ import (
 	certutil "k8s.io/client-go/util/cert"
 	"k8s.io/client-go/util/keyutil"
 	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/ocm/pkg/common/fips"
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/common/logging"
 )
 
 const (
@@ -153,7 +157,7 @@ func NewClientCertificateController(
 		WithFilteredEventsInformersQueueKeyFunc(func(obj runtime.Object) string {
 			return factory.DefaultQueueKey
 		}, c.EventFilterFunc, csrControl.Informer()).
-		WithSync(c.sync).
+		WithSync(logging.WithControllerLogger(controllerName, c.sync)).
 		ResyncEvery(ControllerResyncInterval).
 		ToController(controllerName, recorder)
 }
@@ -210,6 +214,12 @@ func (c *clientCertificateController) sync(ctx context.Context, syncCtx factory.
 			if err != nil {
 				return nil, fmt.Errorf("private key does not match with the certificate in csr: %s", c.csrName)
 			}
+			// reject the certificate issued by a signer with the algorithms not approved in FIPS mode
+			if fips.Enabled() {
+				if err := fips.ValidateCertificatesPEM(certData); err != nil {
+					return nil, fmt.Errorf("the certificate in csr %s is rejected: %w", c.csrName, err)
+				}
+			}
 
 			data := map[string][]byte{
 				TLSCertFile: certData,
@@ -221,10 +231,14 @@ func (c *clientCertificateController) sync(ctx context.Context, syncCtx factory.
 
 		if err != nil {
 			c.reset()
+			reason := "ClientCertificateUpdateFailed"
+			if errors.Is(err, fips.ErrNotCompliant) {
+				reason = "ClientCertificateNotFIPSCompliant"
+			}
 			if updateErr := c.statusUpdater(ctx, metav1.Condition{
 				Type:    "ClusterCertificateRotated",
 				Status:  metav1.ConditionFalse,
-				Reason:  "ClientCertificateUpdateFailed",
+				Reason:  reason,
 				Message: fmt.Sprintf("Failed to rotated client certificate %v", err),
 			}); updateErr != nil {
 				return updateErr
@@ -286,7 +300,7 @@ func (c *clientCertificateController) sync(ctx context.Context, syncCtx factory.
 	// create a csr to request new client certificate if
 	// a. there is no valid client certificate issued for the current cluster/agent;
 	// b. client certificate is sensitive to the additional secret data and the data changes;
-	// c. client certificate exists and has less than a random percentage range from 20% to 25% of its life remaining;
+	// c. client certificate exists and has less than a percentage range from 20% to 25% (jittered per certificate) of its life remaining;
 	shouldCreate, err := shouldCreateCSR(
 		logger,
 		c.controllerName,
@@ -378,9 +392,11 @@ func shouldCreateCSR(
 		remaining := time.Until(*notAfter)
 		logger.V(4).Info("Client certificate for:", "name", controllerName, "time total", total,
 			"remaining", remaining, "remaining/total", remaining.Seconds()/total.Seconds())
-		threshold := jitter(0.2, 0.25)
+		// the jitter is stable for a certificate, so the client certificates issued at the same time, e.g. for the
+		// clusters imported at the same time, are renewed at different times instead of all at once.
+		threshold := 0.2 + 0.05*commonhelpers.CertRenewalJitter(secret.Data[TLSCertFile])
 		if remaining.Seconds()/total.Seconds() > threshold {
-			// Do nothing if the client certificate is valid and has more than a random percentage range from 20% to 25% of its life remaining
+			// Do nothing if the client certificate is valid and has more than a percentage range from 20% to 25% of its life remaining
 			logger.V(4).Info("Client certificate for:", "name", controllerName, "time total", total,
 				"remaining", remaining, "remaining/total", remaining.Seconds()/total.Seconds())
 			return false, nil
@@ -407,14 +423,6 @@ func hasAdditionalSecretData(additionalSecretData map[string][]byte, secret *cor
 	return true
 }
 
-func jitter(percentage float64, maxFactor float64) float64 {
-	if maxFactor <= 0.0 {
-		maxFactor = 1.0
-	}
-	newPercentage := percentage + percentage*rand.Float64()*maxFactor //#nosec G404
-	return newPercentage
-}
-
 func hasValidClientCertificate(logger klog.Logger, subject *pkix.Name, secret *corev1.Secret) bool {
 	if valid, err := IsCertificateValid(logger, secret.Data[TLSCertFile], subject); err == nil {
 		return valid
@@ -21,6 +21,7 @@ import (
 	clusterv1listers "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 )
@@ -64,7 +65,7 @@ func NewAddOnFeatureDiscoveryController(
 		WithInformersQueueKeysFunc(
 			queue.QueueKeyByMetaNamespace,
 			addOnInformers.Informer()).
-		WithSync(c.sync).
+		WithSync(logging.WithControllerLogger("AddOnFeatureDiscoveryController", c.sync)).
 		ToController("AddOnFeatureDiscoveryController", recorder)
 }
 
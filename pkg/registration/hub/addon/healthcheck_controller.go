@@ -19,6 +19,7 @@ import (
 	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 )
@@ -44,7 +45,7 @@ func NewManagedClusterAddOnHealthCheckController(addOnClient addonclient.Interfa
 
 	return factory.New().
 		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterInformer.Informer()).
-		WithSync(c.sync).
+		WithSync(logging.WithControllerLogger("ManagedClusterAddonHealthCheckController", c.sync)).
 		ToController("ManagedClusterAddonHealthCheckController", recorder)
 }
 
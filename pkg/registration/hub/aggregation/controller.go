@@ -0,0 +1,189 @@
+package aggregation
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/discovery"
+	"k8s.io/klog/v2"
+
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterv1alpha1client "open-cluster-management.io/api/client/cluster/clientset/versioned/typed/cluster/v1alpha1"
+	clusterinformerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	workinformerv1 "open-cluster-management.io/api/client/work/informers/externalversions/work/v1"
+	worklisterv1 "open-cluster-management.io/api/client/work/listers/work/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/logging"
+)
+
+// The cluster claims summarizing the fleet of the hub. When the hub is also a managed cluster of a parent hub, the
+// claims are reported to the parent hub in the status of its ManagedCluster by the registration agent on the hub.
+const (
+	ClaimManagedClustersTotal       = "total.managedclusters.hub.open-cluster-management.io"
+	ClaimManagedClustersAvailable   = "available.managedclusters.hub.open-cluster-management.io"
+	ClaimManagedClustersUnavailable = "unavailable.managedclusters.hub.open-cluster-management.io"
+	ClaimManifestWorksTotal         = "total.manifestworks.hub.open-cluster-management.io"
+	ClaimManifestWorksAvailable     = "available.manifestworks.hub.open-cluster-management.io"
+	ClaimManifestWorksDegraded      = "degraded.manifestworks.hub.open-cluster-management.io"
+
+	// HubSummaryLabel is set on the cluster claims created by the controller.
+	HubSummaryLabel = "open-cluster-management.io/hub-summary"
+)
+
+// statusAggregationController counts the ManagedClusters and the ManifestWorks of the hub by their health, and
+// applies the counts as cluster claims of the hub cluster itself. Nothing is applied if the ClusterClaim API is not
+// installed, which means the hub is not a managed cluster.
+type statusAggregationController struct {
+	claimClient   clusterv1alpha1client.ClusterClaimInterface
+	clusterLister clusterlisterv1.ManagedClusterLister
+	workLister    worklisterv1.ManifestWorkLister
+	// applied are the values of the claims applied by the controller, the claims are only applied again when their
+	// values change.
+	applied map[string]string
+	// claimAPIMissing is set once the ClusterClaim API is found not installed, nothing is synced afterwards.
+	claimAPIMissing bool
+}
+
+// ClusterClaimAPIInstalled returns whether the ClusterClaim API is installed on the hub, the status aggregation
+// controller is not needed if it is not installed.
+func ClusterClaimAPIInstalled(discoveryClient discovery.DiscoveryInterface) (bool, error) {
+	resources, err := discoveryClient.ServerResourcesForGroupVersion(clusterv1alpha1.GroupVersion.String())
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == "clusterclaims" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// NewStatusAggregationController creates a new status aggregation controller
+func NewStatusAggregationController(
+	clusterClient clusterclientset.Interface,
+	clusterInformer clusterinformerv1.ManagedClusterInformer,
+	workInformer workinformerv1.ManifestWorkInformer,
+	recorder events.Recorder) factory.Controller {
+	c := &statusAggregationController{
+		claimClient:   clusterClient.ClusterV1alpha1().ClusterClaims(),
+		clusterLister: clusterInformer.Lister(),
+		workLister:    workInformer.Lister(),
+		applied:       map[string]string{},
+	}
+	return factory.New().
+		WithInformers(clusterInformer.Informer(), workInformer.Informer()).
+		WithSync(logging.WithControllerLogger("StatusAggregationController", c.sync)).
+		ToController("StatusAggregationController", recorder)
+}
+
+func (c *statusAggregationController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	if c.claimAPIMissing {
+		return nil
+	}
+
+	summary, err := c.summarize()
+	if err != nil {
+		return err
+	}
+
+	for name, value := range summary {
+		if c.applied[name] == value {
+			continue
+		}
+		installed, err := c.applyClaim(ctx, name, value)
+		if err != nil {
+			return err
+		}
+		if !installed {
+			// the API is not expected to be installed while the controller runs, so it is not checked again.
+			klog.FromContext(ctx).Info("ClusterClaim API is not installed, the hub status is not aggregated")
+			c.claimAPIMissing = true
+			return nil
+		}
+		c.applied[name] = value
+	}
+	return nil
+}
+
+func (c *statusAggregationController) summarize() (map[string]string, error) {
+	clusters, err := c.clusterLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	availableClusters := 0
+	for _, cluster := range clusters {
+		if meta.IsStatusConditionTrue(cluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable) {
+			availableClusters++
+		}
+	}
+
+	works, err := c.workLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	availableWorks, degradedWorks := 0, 0
+	for _, work := range works {
+		if meta.IsStatusConditionTrue(work.Status.Conditions, workapiv1.WorkAvailable) {
+			availableWorks++
+		}
+		// a work is degraded if it is failed to be applied or any of its resources is degraded.
+		if meta.IsStatusConditionFalse(work.Status.Conditions, workapiv1.WorkApplied) ||
+			meta.IsStatusConditionTrue(work.Status.Conditions, workapiv1.WorkDegraded) {
+			degradedWorks++
+		}
+	}
+
+	return map[string]string{
+		ClaimManagedClustersTotal:       strconv.Itoa(len(clusters)),
+		ClaimManagedClustersAvailable:   strconv.Itoa(availableClusters),
+		ClaimManagedClustersUnavailable: strconv.Itoa(len(clusters) - availableClusters),
+		ClaimManifestWorksTotal:         strconv.Itoa(len(works)),
+		ClaimManifestWorksAvailable:     strconv.Itoa(availableWorks),
+		ClaimManifestWorksDegraded:      strconv.Itoa(degradedWorks),
+	}, nil
+}
+
+// applyClaim creates or updates the claim with the value, it returns false if the ClusterClaim API is not installed.
+func (c *statusAggregationController) applyClaim(ctx context.Context, name, value string) (bool, error) {
+	claim, err := c.claimClient.Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		_, err = c.claimClient.Create(ctx, &clusterv1alpha1.ClusterClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{HubSummaryLabel: "true"},
+			},
+			Spec: clusterv1alpha1.ClusterClaimSpec{Value: value},
+		}, metav1.CreateOptions{})
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			// the claim is not found on creation only if the API is not installed.
+			return false, nil
+		}
+		return true, err
+	case meta.IsNoMatchError(err):
+		return false, nil
+	case err != nil:
+		return true, err
+	case claim.Spec.Value == value:
+		return true, nil
+	}
+
+	claim = claim.DeepCopy()
+	claim.Spec.Value = value
+	_, err = c.claimClient.Update(ctx, claim, metav1.UpdateOptions{})
+	return true, err
+}
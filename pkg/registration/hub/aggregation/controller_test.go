@@ -0,0 +1,217 @@
+package aggregation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	workfake "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+)
+
+func newWork(name string, conditions ...metav1.Condition) *workapiv1.ManifestWork {
+	return &workapiv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testinghelpers.TestManagedClusterName},
+		Status:     workapiv1.ManifestWorkStatus{Conditions: conditions},
+	}
+}
+
+func newClaim(name, value string) *clusterv1alpha1.ClusterClaim {
+	return &clusterv1alpha1.ClusterClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       clusterv1alpha1.ClusterClaimSpec{Value: value},
+	}
+}
+
+func TestSync(t *testing.T) {
+	cases := []struct {
+		name           string
+		clusters       []runtime.Object
+		works          []runtime.Object
+		claims         []runtime.Object
+		applied        map[string]string
+		notInstalled   bool
+		expectedVerbs  []string
+		expectedClaims map[string]string
+	}{
+		{
+			name: "create claims",
+			clusters: []runtime.Object{
+				testinghelpers.NewAvailableManagedCluster(),
+			},
+			works: []runtime.Object{
+				newWork("available",
+					metav1.Condition{Type: workapiv1.WorkApplied, Status: metav1.ConditionTrue},
+					metav1.Condition{Type: workapiv1.WorkAvailable, Status: metav1.ConditionTrue}),
+				newWork("failed", metav1.Condition{Type: workapiv1.WorkApplied, Status: metav1.ConditionFalse}),
+				newWork("degraded",
+					metav1.Condition{Type: workapiv1.WorkAvailable, Status: metav1.ConditionTrue},
+					metav1.Condition{Type: workapiv1.WorkDegraded, Status: metav1.ConditionTrue}),
+			},
+			expectedVerbs: []string{"get", "create", "get", "create", "get", "create", "get", "create", "get", "create", "get", "create"},
+			expectedClaims: map[string]string{
+				ClaimManagedClustersTotal:       "1",
+				ClaimManagedClustersAvailable:   "1",
+				ClaimManagedClustersUnavailable: "0",
+				ClaimManifestWorksTotal:         "3",
+				ClaimManifestWorksAvailable:     "2",
+				ClaimManifestWorksDegraded:      "2",
+			},
+		},
+		{
+			name:     "update changed claims",
+			clusters: []runtime.Object{testinghelpers.NewUnAvailableManagedCluster()},
+			claims: []runtime.Object{
+				newClaim(ClaimManagedClustersTotal, "1"),
+				newClaim(ClaimManagedClustersAvailable, "1"),
+				newClaim(ClaimManagedClustersUnavailable, "0"),
+			},
+			applied: map[string]string{
+				ClaimManagedClustersTotal:   "1",
+				ClaimManifestWorksTotal:     "0",
+				ClaimManifestWorksAvailable: "0",
+				ClaimManifestWorksDegraded:  "0",
+			},
+			expectedVerbs: []string{"get", "update", "get", "update"},
+			expectedClaims: map[string]string{
+				ClaimManagedClustersTotal:       "1",
+				ClaimManagedClustersAvailable:   "0",
+				ClaimManagedClustersUnavailable: "1",
+			},
+		},
+		{
+			name:          "claim api is not installed",
+			clusters:      []runtime.Object{testinghelpers.NewAvailableManagedCluster()},
+			notInstalled:  true,
+			expectedVerbs: []string{"get", "create"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusterClient := clusterfake.NewSimpleClientset(append(c.clusters, c.claims...)...)
+			if c.notInstalled {
+				clusterClient.PrependReactor("create", "clusterclaims",
+					func(action clienttesting.Action) (bool, runtime.Object, error) {
+						return true, nil, errors.NewNotFound(schema.GroupResource{}, "")
+					})
+			}
+			clusterInformerFactory := clusterinformers.NewSharedInformerFactory(clusterClient, time.Minute*10)
+			for _, cluster := range c.clusters {
+				if err := clusterInformerFactory.Cluster().V1().ManagedClusters().Informer().GetStore().Add(cluster); err != nil {
+					t.Fatal(err)
+				}
+			}
+			workClient := workfake.NewSimpleClientset(c.works...)
+			workInformerFactory := workinformers.NewSharedInformerFactory(workClient, time.Minute*10)
+			for _, work := range c.works {
+				if err := workInformerFactory.Work().V1().ManifestWorks().Informer().GetStore().Add(work); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			applied := map[string]string{}
+			for name, value := range c.applied {
+				applied[name] = value
+			}
+			ctrl := &statusAggregationController{
+				claimClient:   clusterClient.ClusterV1alpha1().ClusterClaims(),
+				clusterLister: clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+				workLister:    workInformerFactory.Work().V1().ManifestWorks().Lister(),
+				applied:       applied,
+			}
+			clusterClient.ClearActions()
+			if err := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, "key")); err != nil {
+				t.Errorf("unexpected err: %v", err)
+			}
+
+			testingcommon.AssertActions(t, clusterClient.Actions(), c.expectedVerbs...)
+			for name, value := range c.expectedClaims {
+				claim, err := clusterClient.ClusterV1alpha1().ClusterClaims().Get(context.TODO(), name, metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if claim.Spec.Value != value {
+					t.Errorf("expected claim %s to be %s, but got %s", name, value, claim.Spec.Value)
+				}
+				if value != ctrl.applied[name] {
+					t.Errorf("expected applied claim %s to be %s, but got %s", name, value, ctrl.applied[name])
+				}
+			}
+			if !c.notInstalled {
+				return
+			}
+			if len(ctrl.applied) != 0 {
+				t.Errorf("expected no claim applied, but got %v", ctrl.applied)
+			}
+
+			// the missing API is not requested again on the following syncs.
+			clusterClient.ClearActions()
+			if err := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, "key")); err != nil {
+				t.Errorf("unexpected err: %v", err)
+			}
+			testingcommon.AssertNoActions(t, clusterClient.Actions())
+		})
+	}
+}
+
+func TestClusterClaimAPIInstalled(t *testing.T) {
+	cases := []struct {
+		name      string
+		resources []*metav1.APIResourceList
+		expected  bool
+	}{
+		{
+			name: "api is not installed",
+		},
+		{
+			name: "api is installed",
+			resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: clusterv1alpha1.GroupVersion.String(),
+					APIResources: []metav1.APIResource{{Name: "clusterclaims", Kind: "ClusterClaim"}},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "other api of the group is installed",
+			resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: clusterv1alpha1.GroupVersion.String(),
+					APIResources: []metav1.APIResource{{Name: "addonplacementscores", Kind: "AddOnPlacementScore"}},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset()
+			kubeClient.Discovery().(*fakediscovery.FakeDiscovery).Resources = c.resources
+
+			installed, err := ClusterClaimAPIInstalled(kubeClient.Discovery())
+			if err != nil {
+				t.Errorf("unexpected err: %v", err)
+			}
+			if installed != c.expected {
+				t.Errorf("expected installed %v, but got %v", c.expected, installed)
+			}
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package aggregation contains the controller which summarizes the health of the fleet of the hub into cluster
+// claims, so a hub which is also a managed cluster of a parent hub reports the summary upward.
+package aggregation
@@ -0,0 +1,304 @@
+package bootstrapkubeconfig
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	authv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	clusterinformerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	workinformerv1 "open-cluster-management.io/api/client/work/informers/externalversions/work/v1"
+	worklisterv1 "open-cluster-management.io/api/client/work/listers/work/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/logging"
+	"open-cluster-management.io/ocm/pkg/common/patcher"
+	"open-cluster-management.io/ocm/pkg/common/queue"
+	"open-cluster-management.io/ocm/pkg/registration/hub/migration"
+)
+
+const (
+	// BootstrapKubeconfigWorkName is the name of the ManifestWork delivering the bootstrap kubeconfig to the managed
+	// cluster.
+	BootstrapKubeconfigWorkName = "bootstrap-hub-kubeconfig"
+
+	// BootstrapKubeconfigExpirationAnnotation on the ManifestWork is the time the token in the bootstrap kubeconfig
+	// expires, in RFC3339.
+	BootstrapKubeconfigExpirationAnnotation = "cluster.open-cluster-management.io/bootstrap-kubeconfig-expiration"
+
+	// AgentNamespaceAnnotation on a ManagedCluster is the namespace of the klusterlet agents on the managed cluster,
+	// it is open-cluster-management-agent by default.
+	AgentNamespaceAnnotation = "cluster.open-cluster-management.io/agent-namespace"
+
+	defaultAgentNamespace = "open-cluster-management-agent"
+	bootstrapSecretName   = "bootstrap-hub-kubeconfig" // #nosec G101
+	// rootCAConfigMapName is the ConfigMap published in every namespace with the CA of the hub apiserver.
+	rootCAConfigMapName = "kube-root-ca.crt"
+	rootCAConfigMapKey  = "ca.crt"
+)
+
+// bootstrapKubeconfigController delivers a bootstrap kubeconfig to each joined ManagedCluster with a ManifestWork,
+// which authenticates with a token of the bootstrap service account on the hub. The token is requested again and
+// the ManifestWork is updated once 4/5 of the lifetime of the token has passed, so the klusterlet is able to
+// rebootstrap at any time without a long-lived credential. The bootstrap secret is orphaned when the ManifestWork
+// is deleted.
+type bootstrapKubeconfigController struct {
+	kubeClient              kubernetes.Interface
+	workClient              workclientset.Interface
+	clusterLister           clusterlisterv1.ManagedClusterLister
+	workLister              worklisterv1.ManifestWorkLister
+	serviceAccountNamespace string
+	serviceAccountName      string
+	hubAPIServer            string
+	expiration              time.Duration
+	eventRecorder           events.Recorder
+}
+
+// NewBootstrapKubeconfigController creates a new bootstrap kubeconfig rotation controller, the serviceAccount is the
+// namespace/name of the bootstrap service account, and the hubAPIServer is the URL of the hub apiserver reachable from
+// the managed clusters.
+func NewBootstrapKubeconfigController(
+	kubeClient kubernetes.Interface,
+	workClient workclientset.Interface,
+	clusterInformer clusterinformerv1.ManagedClusterInformer,
+	workInformer workinformerv1.ManifestWorkInformer,
+	serviceAccount, hubAPIServer string,
+	expiration time.Duration,
+	recorder events.Recorder) (factory.Controller, error) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(serviceAccount)
+	if err != nil {
+		return nil, err
+	}
+	if len(namespace) == 0 || len(name) == 0 {
+		return nil, fmt.Errorf("the bootstrap service account %q is not in the format of namespace/name", serviceAccount)
+	}
+	if len(hubAPIServer) == 0 {
+		return nil, fmt.Errorf("the hub apiserver of the bootstrap kubeconfig is required")
+	}
+	if expiration < 10*time.Minute {
+		return nil, fmt.Errorf("the expiration of the bootstrap token must be at least 10m, but got %v", expiration)
+	}
+
+	c := &bootstrapKubeconfigController{
+		kubeClient:              kubeClient,
+		workClient:              workClient,
+		clusterLister:           clusterInformer.Lister(),
+		workLister:              workInformer.Lister(),
+		serviceAccountNamespace: namespace,
+		serviceAccountName:      name,
+		hubAPIServer:            hubAPIServer,
+		expiration:              expiration,
+		eventRecorder:           recorder.WithComponentSuffix("bootstrap-kubeconfig-controller"),
+	}
+	return factory.New().
+		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterInformer.Informer()).
+		WithFilteredEventsInformersQueueKeysFunc(
+			queue.QueueKeyByMetaNamespace,
+			queue.FilterByNames(BootstrapKubeconfigWorkName),
+			workInformer.Informer()).
+		WithSync(logging.WithControllerLogger("BootstrapKubeconfigController", c.sync)).
+		ToController("BootstrapKubeconfigController", recorder), nil
+}
+
+func (c *bootstrapKubeconfigController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	clusterName := syncCtx.QueueKey()
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling the bootstrap kubeconfig of ManagedCluster", "managedClusterName", clusterName)
+
+	cluster, err := c.clusterLister.Get(clusterName)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !cluster.DeletionTimestamp.IsZero() || !cluster.Spec.HubAcceptsClient ||
+		!meta.IsStatusConditionTrue(cluster.Status.Conditions, clusterv1.ManagedClusterConditionJoined) {
+		return nil
+	}
+	if _, ok := cluster.Annotations[migration.MigrationAnnotation]; ok {
+		// the bootstrap kubeconfig is replaced with the one of the target hub during the migration.
+		return nil
+	}
+
+	agentNamespace := cluster.Annotations[AgentNamespaceAnnotation]
+	if len(agentNamespace) == 0 {
+		agentNamespace = defaultAgentNamespace
+	}
+
+	work, err := c.workLister.ManifestWorks(clusterName).Get(BootstrapKubeconfigWorkName)
+	switch {
+	case errors.IsNotFound(err):
+		work = nil
+	case err != nil:
+		return err
+	}
+	if work != nil && workAgentNamespace(work) == agentNamespace {
+		if refresh := c.refreshTime(work); time.Now().Before(refresh) {
+			syncCtx.Queue().AddAfter(clusterName, time.Until(refresh))
+			return nil
+		}
+	}
+
+	required, err := c.bootstrapKubeconfigWork(ctx, clusterName, agentNamespace)
+	if err != nil {
+		return err
+	}
+	if work == nil {
+		if _, err := c.workClient.WorkV1().ManifestWorks(clusterName).Create(ctx, required, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	} else if err := c.patchWork(ctx, work, required); err != nil {
+		return err
+	}
+
+	c.eventRecorder.Eventf("BootstrapKubeconfigRotated",
+		"the bootstrap kubeconfig of managed cluster %s is rotated, it expires at %s",
+		clusterName, required.Annotations[BootstrapKubeconfigExpirationAnnotation])
+	syncCtx.Queue().AddAfter(clusterName, time.Until(c.refreshTime(required)))
+	return nil
+}
+
+// patchWork patches the spec and then the expiration annotation of the work, the work in the informer cache is
+// trimmed so it is not updated. If the annotation is not patched, the kubeconfig is rotated again in the next sync.
+func (c *bootstrapKubeconfigController) patchWork(
+	ctx context.Context, work, required *workapiv1.ManifestWork) error {
+	workPatcher := patcher.NewPatcher[
+		*workapiv1.ManifestWork, workapiv1.ManifestWorkSpec, workapiv1.ManifestWorkStatus](
+		c.workClient.WorkV1().ManifestWorks(work.Namespace))
+	if _, err := workPatcher.PatchSpec(ctx, work, required.Spec, work.Spec); err != nil {
+		return err
+	}
+
+	newMeta := work.ObjectMeta.DeepCopy()
+	if newMeta.Annotations == nil {
+		newMeta.Annotations = map[string]string{}
+	}
+	newMeta.Annotations[BootstrapKubeconfigExpirationAnnotation] = required.Annotations[BootstrapKubeconfigExpirationAnnotation]
+	_, err := workPatcher.PatchLabelAnnotations(ctx, work, *newMeta, work.ObjectMeta)
+	return err
+}
+
+// refreshTime returns the time to rotate the bootstrap kubeconfig in the work, which is when 1/5 of the lifetime of
+// the token is left. It is zero if the expiration of the token is unknown.
+func (c *bootstrapKubeconfigController) refreshTime(work *workapiv1.ManifestWork) time.Time {
+	expiration, err := time.Parse(time.RFC3339, work.Annotations[BootstrapKubeconfigExpirationAnnotation])
+	if err != nil {
+		return time.Time{}
+	}
+	return expiration.Add(-c.expiration / 5)
+}
+
+// bootstrapKubeconfigWork returns the ManifestWork with the bootstrap secret holding a new token of the bootstrap
+// service account.
+func (c *bootstrapKubeconfigController) bootstrapKubeconfigWork(
+	ctx context.Context, clusterName, agentNamespace string) (*workapiv1.ManifestWork, error) {
+	rootCA, err := c.kubeClient.CoreV1().ConfigMaps(c.serviceAccountNamespace).Get(ctx, rootCAConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	tokenRequest, err := c.kubeClient.CoreV1().ServiceAccounts(c.serviceAccountNamespace).CreateToken(
+		ctx, c.serviceAccountName, &authv1.TokenRequest{
+			Spec: authv1.TokenRequestSpec{
+				ExpirationSeconds: pointer.Int64(int64(c.expiration.Seconds())),
+			},
+		}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	kubeconfig, err := clientcmd.Write(clientcmdapi.Config{
+		Kind:       "Config",
+		APIVersion: "v1",
+		Clusters: map[string]*clientcmdapi.Cluster{
+			"hub": {
+				Server:                   c.hubAPIServer,
+				CertificateAuthorityData: []byte(rootCA.Data[rootCAConfigMapKey]),
+			},
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			"bootstrap": {
+				Cluster:  "hub",
+				AuthInfo: "bootstrap",
+			},
+		},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			"bootstrap": {
+				Token: tokenRequest.Status.Token,
+			},
+		},
+		CurrentContext: "bootstrap",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstrapSecretName,
+			Namespace: agentNamespace,
+		},
+		Data: map[string][]byte{
+			"kubeconfig": kubeconfig,
+		},
+	}
+	return &workapiv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      BootstrapKubeconfigWorkName,
+			Namespace: clusterName,
+			Annotations: map[string]string{
+				BootstrapKubeconfigExpirationAnnotation: tokenRequest.Status.ExpirationTimestamp.UTC().Format(time.RFC3339),
+			},
+		},
+		Spec: workapiv1.ManifestWorkSpec{
+			Workload: workapiv1.ManifestsTemplate{
+				Manifests: []workapiv1.Manifest{
+					{RawExtension: runtime.RawExtension{Object: secret}},
+				},
+			},
+			DeleteOption: &workapiv1.DeleteOption{
+				PropagationPolicy: workapiv1.DeletePropagationPolicyTypeOrphan,
+			},
+		},
+	}, nil
+}
+
+// workAgentNamespace returns the namespace of the bootstrap secret in the work.
+func workAgentNamespace(work *workapiv1.ManifestWork) string {
+	for _, manifest := range work.Spec.Workload.Manifests {
+		if manifest.Object != nil {
+			if accessor, err := meta.Accessor(manifest.Object); err == nil {
+				return accessor.GetNamespace()
+			}
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(manifest.Raw); err == nil {
+			return obj.GetNamespace()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,199 @@
+package bootstrapkubeconfig
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events/eventstesting"
+	authv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	workfake "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+	"open-cluster-management.io/ocm/pkg/registration/hub/migration"
+)
+
+const testExpiration = 10 * time.Hour
+
+func newBootstrapKubeconfigWork(agentNamespace string, expiration time.Time) *workapiv1.ManifestWork {
+	secret := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Name: bootstrapSecretName, Namespace: agentNamespace},
+	}
+	return &workapiv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      BootstrapKubeconfigWorkName,
+			Namespace: testinghelpers.TestManagedClusterName,
+			Annotations: map[string]string{
+				BootstrapKubeconfigExpirationAnnotation: expiration.UTC().Format(time.RFC3339),
+			},
+		},
+		Spec: workapiv1.ManifestWorkSpec{
+			Workload: workapiv1.ManifestsTemplate{
+				Manifests: []workapiv1.Manifest{
+					{RawExtension: runtime.RawExtension{Raw: []byte(
+						`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"` + secret.Name + `","namespace":"` +
+							secret.Namespace + `"}}`)}},
+				},
+			},
+		},
+	}
+}
+
+func TestSync(t *testing.T) {
+	cases := []struct {
+		name                string
+		cluster             *clusterv1.ManagedCluster
+		works               []runtime.Object
+		expectedWorkActions []string
+		expectedNamespace   string
+	}{
+		{
+			name:    "cluster is not joined",
+			cluster: testinghelpers.NewAcceptedManagedCluster(),
+		},
+		{
+			name: "cluster is migrating",
+			cluster: func() *clusterv1.ManagedCluster {
+				cluster := testinghelpers.NewJoinedManagedCluster()
+				cluster.Annotations = map[string]string{migration.MigrationAnnotation: "migration"}
+				return cluster
+			}(),
+		},
+		{
+			name:                "create bootstrap kubeconfig",
+			cluster:             testinghelpers.NewJoinedManagedCluster(),
+			expectedWorkActions: []string{"create"},
+			expectedNamespace:   defaultAgentNamespace,
+		},
+		{
+			name:    "bootstrap kubeconfig is not expiring",
+			cluster: testinghelpers.NewJoinedManagedCluster(),
+			works:   []runtime.Object{newBootstrapKubeconfigWork(defaultAgentNamespace, time.Now().Add(5*time.Hour))},
+		},
+		{
+			name:                "bootstrap kubeconfig is expiring",
+			cluster:             testinghelpers.NewJoinedManagedCluster(),
+			works:               []runtime.Object{newBootstrapKubeconfigWork(defaultAgentNamespace, time.Now().Add(time.Hour))},
+			expectedWorkActions: []string{"patch", "patch"},
+			expectedNamespace:   defaultAgentNamespace,
+		},
+		{
+			name: "agent namespace is changed",
+			cluster: func() *clusterv1.ManagedCluster {
+				cluster := testinghelpers.NewJoinedManagedCluster()
+				cluster.Annotations = map[string]string{AgentNamespaceAnnotation: "agent"}
+				return cluster
+			}(),
+			works:               []runtime.Object{newBootstrapKubeconfigWork(defaultAgentNamespace, time.Now().Add(5*time.Hour))},
+			expectedWorkActions: []string{"patch", "patch"},
+			expectedNamespace:   "agent",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: rootCAConfigMapName, Namespace: "open-cluster-management"},
+				Data:       map[string]string{rootCAConfigMapKey: "ca-data"},
+			})
+			kubeClient.PrependReactor("create", "serviceaccounts", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				if action.GetSubresource() != "token" {
+					return false, nil, nil
+				}
+				return true, &authv1.TokenRequest{
+					Status: authv1.TokenRequestStatus{
+						Token:               "token",
+						ExpirationTimestamp: metav1.NewTime(time.Now().Add(testExpiration)),
+					},
+				}, nil
+			})
+			clusterClient := clusterfake.NewSimpleClientset(c.cluster)
+			clusterInformerFactory := clusterinformers.NewSharedInformerFactory(clusterClient, time.Minute*10)
+			if err := clusterInformerFactory.Cluster().V1().ManagedClusters().Informer().GetStore().Add(c.cluster); err != nil {
+				t.Fatal(err)
+			}
+			workClient := workfake.NewSimpleClientset(c.works...)
+			workInformerFactory := workinformers.NewSharedInformerFactory(workClient, time.Minute*10)
+			for _, work := range c.works {
+				if err := workInformerFactory.Work().V1().ManifestWorks().Informer().GetStore().Add(work); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctrl := &bootstrapKubeconfigController{
+				kubeClient:              kubeClient,
+				workClient:              workClient,
+				clusterLister:           clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+				workLister:              workInformerFactory.Work().V1().ManifestWorks().Lister(),
+				serviceAccountNamespace: "open-cluster-management",
+				serviceAccountName:      "cluster-bootstrap",
+				hubAPIServer:            "https://hub.example.com:6443",
+				expiration:              testExpiration,
+				eventRecorder:           eventstesting.NewTestingEventRecorder(t),
+			}
+			workClient.ClearActions()
+
+			if err := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, testinghelpers.TestManagedClusterName)); err != nil {
+				t.Errorf("unexpected err: %v", err)
+			}
+
+			testingcommon.AssertActions(t, workClient.Actions(), c.expectedWorkActions...)
+			if len(c.expectedWorkActions) == 0 {
+				return
+			}
+
+			work := &workapiv1.ManifestWork{}
+			for _, action := range workClient.Actions() {
+				switch action := action.(type) {
+				case clienttesting.CreateAction:
+					work = action.GetObject().(*workapiv1.ManifestWork)
+				case clienttesting.PatchAction:
+					// the spec and the annotations are patched separately
+					if err := json.Unmarshal(action.GetPatch(), work); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			if _, err := time.Parse(time.RFC3339, work.Annotations[BootstrapKubeconfigExpirationAnnotation]); err != nil {
+				t.Errorf("unexpected expiration annotation: %v", err)
+			}
+			if work.Spec.DeleteOption == nil || work.Spec.DeleteOption.PropagationPolicy != workapiv1.DeletePropagationPolicyTypeOrphan {
+				t.Errorf("expected the bootstrap secret is orphaned")
+			}
+			secret, ok := work.Spec.Workload.Manifests[0].Object.(*corev1.Secret)
+			if !ok {
+				secret = &corev1.Secret{}
+				if err := json.Unmarshal(work.Spec.Workload.Manifests[0].Raw, secret); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if secret.Namespace != c.expectedNamespace {
+				t.Errorf("expected bootstrap secret in namespace %s, but got %s", c.expectedNamespace, secret.Namespace)
+			}
+			kubeconfig, err := clientcmd.Load(secret.Data["kubeconfig"])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if kubeconfig.Clusters["hub"].Server != "https://hub.example.com:6443" ||
+				string(kubeconfig.Clusters["hub"].CertificateAuthorityData) != "ca-data" ||
+				kubeconfig.AuthInfos["bootstrap"].Token != "token" {
+				t.Errorf("unexpected bootstrap kubeconfig %s", secret.Data["kubeconfig"])
+			}
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package bootstrapkubeconfig contains the controller which rotates the bootstrap kubeconfigs of the managed clusters
+// with the short-lived tokens of a service account on the hub, so the klusterlets are always able to rebootstrap.
+package bootstrapkubeconfig
@@ -0,0 +1,194 @@
+package claimlabel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	clientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterv1informer "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	clusterv1listers "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/logging"
+	"open-cluster-management.io/ocm/pkg/common/patcher"
+	"open-cluster-management.io/ocm/pkg/common/queue"
+)
+
+// ClaimLabelsAnnotation on the ManagedCluster is the comma-separated keys of the labels projected from the cluster
+// claims by the controller. The labels are removed once their rules are removed or the claims are no longer reported.
+const ClaimLabelsAnnotation = "cluster.open-cluster-management.io/claim-labels"
+
+// claimLabelController projects the cluster claims of the ManagedClusters into their labels with the rules in a
+// ConfigMap. The labels set by others with the same keys are left untouched, only the labels recorded in the
+// ClaimLabelsAnnotation are updated or removed. The compiled rules are cached until the ConfigMap changes, it is
+// expected to run with one worker.
+type claimLabelController struct {
+	patcher         patcher.Patcher[*clusterv1.ManagedCluster, clusterv1.ManagedClusterSpec, clusterv1.ManagedClusterStatus]
+	clusterLister   clusterv1listers.ManagedClusterLister
+	configMapLister corev1listers.ConfigMapLister
+	namespace       string
+	name            string
+	recorder        events.Recorder
+
+	resourceVersion string
+	rules           []compiledRule
+	rulesErr        error
+}
+
+// NewClaimLabelController returns a controller projecting the cluster claims into the labels with the rules in the
+// ConfigMap with the key <namespace>/<name>. The configMapInformer is expected to only watch the ConfigMap.
+func NewClaimLabelController(
+	clusterClient clientset.Interface,
+	clusterInformer clusterv1informer.ManagedClusterInformer,
+	configMapInformer corev1informers.ConfigMapInformer,
+	configMapKey string,
+	recorder events.Recorder) (factory.Controller, error) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(configMapKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(namespace) == 0 || len(name) == 0 {
+		return nil, fmt.Errorf("the claim label rules configmap %q is not in the format of <namespace>/<name>", configMapKey)
+	}
+
+	c := &claimLabelController{
+		patcher: patcher.NewPatcher[
+			*clusterv1.ManagedCluster, clusterv1.ManagedClusterSpec, clusterv1.ManagedClusterStatus](
+			clusterClient.ClusterV1().ManagedClusters()),
+		clusterLister:   clusterInformer.Lister(),
+		configMapLister: configMapInformer.Lister(),
+		namespace:       namespace,
+		name:            name,
+		recorder:        recorder,
+	}
+
+	return factory.New().
+		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterInformer.Informer()).
+		WithInformersQueueKeysFunc(c.allClusters, configMapInformer.Informer()).
+		WithSync(logging.WithControllerLogger("ClaimLabelController", c.sync)).
+		ToController("ClaimLabelController", recorder), nil
+}
+
+// allClusters returns the names of all the ManagedClusters once the rules are changed.
+func (c *claimLabelController) allClusters(_ runtime.Object) []string {
+	clusters, err := c.clusterLister.List(labels.Everything())
+	if err != nil {
+		return []string{}
+	}
+	var names []string
+	for _, cluster := range clusters {
+		names = append(names, cluster.Name)
+	}
+	return names
+}
+
+func (c *claimLabelController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	clusterName := syncCtx.QueueKey()
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling the claim labels of ManagedCluster", "managedClusterName", clusterName)
+
+	cluster, err := c.clusterLister.Get(clusterName)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !cluster.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	rules, err := c.loadRules()
+	if err != nil {
+		// keep the labels until the rules are fixed
+		logger.V(4).Info("The claim label rules are invalid", "error", err)
+		return nil
+	}
+
+	projected := sets.New[string]()
+	for _, key := range strings.Split(cluster.Annotations[ClaimLabelsAnnotation], ",") {
+		if len(key) > 0 {
+			projected.Insert(key)
+		}
+	}
+
+	desired := map[string]string{}
+	for _, rule := range rules {
+		if _, ok := cluster.Labels[rule.Label]; ok && !projected.Has(rule.Label) {
+			// the label is set by others
+			continue
+		}
+		for _, claim := range cluster.Status.ClusterClaims {
+			if claim.Name != rule.Claim {
+				continue
+			}
+			if value, ok := rule.labelValue(claim.Value); ok {
+				desired[rule.Label] = value
+			}
+			break
+		}
+	}
+
+	newCluster := cluster.DeepCopy()
+	for key := range projected {
+		if _, ok := desired[key]; !ok {
+			delete(newCluster.Labels, key)
+		}
+	}
+	var keys []string
+	for key, value := range desired {
+		if newCluster.Labels == nil {
+			newCluster.Labels = map[string]string{}
+		}
+		newCluster.Labels[key] = value
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if len(keys) > 0 {
+		if newCluster.Annotations == nil {
+			newCluster.Annotations = map[string]string{}
+		}
+		newCluster.Annotations[ClaimLabelsAnnotation] = strings.Join(keys, ",")
+	} else {
+		delete(newCluster.Annotations, ClaimLabelsAnnotation)
+	}
+
+	_, err = c.patcher.PatchLabelAnnotations(ctx, newCluster, newCluster.ObjectMeta, cluster.ObjectMeta)
+	return err
+}
+
+// loadRules returns the compiled rules in the ConfigMap, there is no rule if the ConfigMap does not exist. A warning
+// event is recorded once for each version of the ConfigMap with invalid rules.
+func (c *claimLabelController) loadRules() ([]compiledRule, error) {
+	configMap, err := c.configMapLister.ConfigMaps(c.namespace).Get(c.name)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if configMap.ResourceVersion == c.resourceVersion {
+		return c.rules, c.rulesErr
+	}
+
+	c.resourceVersion = configMap.ResourceVersion
+	c.rules, c.rulesErr = parseRules(configMap.Data[RulesConfigMapKey])
+	if c.rulesErr != nil {
+		c.recorder.Warningf("InvalidClaimLabelRules", "the claim label rules in configmap %s/%s are invalid: %v",
+			c.namespace, c.name, c.rulesErr)
+	}
+	return c.rules, c.rulesErr
+}
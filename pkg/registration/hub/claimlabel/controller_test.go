@@ -0,0 +1,197 @@
+package claimlabel
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events/eventstesting"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/patcher"
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+)
+
+func newClusterWithClaims(labels, annotations map[string]string, claims ...clusterv1.ManagedClusterClaim) *clusterv1.ManagedCluster {
+	cluster := testinghelpers.NewAvailableManagedCluster()
+	cluster.Labels = labels
+	cluster.Annotations = annotations
+	cluster.Status.ClusterClaims = claims
+	return cluster
+}
+
+func newRulesConfigMap(rules string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "claim-label-rules", Namespace: "open-cluster-management-hub", ResourceVersion: "1"},
+		Data:       map[string]string{RulesConfigMapKey: rules},
+	}
+}
+
+func TestSync(t *testing.T) {
+	platformClaim := clusterv1.ManagedClusterClaim{Name: "platform.open-cluster-management.io", Value: "Amazon"}
+	regionClaim := clusterv1.ManagedClusterClaim{Name: "region.open-cluster-management.io", Value: "us-east-1"}
+
+	cases := []struct {
+		name                string
+		cluster             *clusterv1.ManagedCluster
+		configMap           *corev1.ConfigMap
+		expectedActions     []string
+		expectedLabels      map[string]interface{}
+		expectedAnnotations map[string]interface{}
+	}{
+		{
+			name:    "no rule",
+			cluster: newClusterWithClaims(nil, nil, platformClaim),
+		},
+		{
+			name:            "project the claims",
+			cluster:         newClusterWithClaims(map[string]string{"env": "prod"}, nil, platformClaim, regionClaim),
+			configMap:       newRulesConfigMap(testRules),
+			expectedActions: []string{"patch"},
+			expectedLabels: map[string]interface{}{
+				"cloud.example.com/platform": "aws",
+				"cloud.example.com/region":   "us",
+			},
+			expectedAnnotations: map[string]interface{}{
+				ClaimLabelsAnnotation: "cloud.example.com/platform,cloud.example.com/region",
+			},
+		},
+		{
+			name: "labels set by others are not taken over",
+			cluster: newClusterWithClaims(
+				map[string]string{"cloud.example.com/platform": "manual"}, nil, platformClaim, regionClaim),
+			configMap:       newRulesConfigMap(testRules),
+			expectedActions: []string{"patch"},
+			expectedLabels: map[string]interface{}{
+				"cloud.example.com/region": "us",
+			},
+			expectedAnnotations: map[string]interface{}{
+				ClaimLabelsAnnotation: "cloud.example.com/region",
+			},
+		},
+		{
+			name: "claim value is not mapped",
+			cluster: newClusterWithClaims(nil, nil,
+				clusterv1.ManagedClusterClaim{Name: "region.open-cluster-management.io", Value: "eu-west-1"}),
+			configMap: newRulesConfigMap(testRules),
+		},
+		{
+			name: "labels are projected",
+			cluster: newClusterWithClaims(
+				map[string]string{"cloud.example.com/platform": "aws"},
+				map[string]string{ClaimLabelsAnnotation: "cloud.example.com/platform"},
+				platformClaim),
+			configMap: newRulesConfigMap(testRules),
+		},
+		{
+			name: "claim is no longer reported",
+			cluster: newClusterWithClaims(
+				map[string]string{"cloud.example.com/platform": "aws", "cloud.example.com/region": "us"},
+				map[string]string{ClaimLabelsAnnotation: "cloud.example.com/platform,cloud.example.com/region"},
+				platformClaim),
+			configMap:       newRulesConfigMap(testRules),
+			expectedActions: []string{"patch"},
+			expectedLabels: map[string]interface{}{
+				"cloud.example.com/region": nil,
+			},
+			expectedAnnotations: map[string]interface{}{
+				ClaimLabelsAnnotation: "cloud.example.com/platform",
+			},
+		},
+		{
+			name: "rules are removed",
+			cluster: newClusterWithClaims(
+				map[string]string{"cloud.example.com/platform": "aws", "env": "prod"},
+				map[string]string{ClaimLabelsAnnotation: "cloud.example.com/platform"},
+				platformClaim),
+			expectedActions: []string{"patch"},
+			expectedLabels: map[string]interface{}{
+				"cloud.example.com/platform": nil,
+			},
+			expectedAnnotations: map[string]interface{}{
+				ClaimLabelsAnnotation: nil,
+			},
+		},
+		{
+			name: "rules are invalid",
+			cluster: newClusterWithClaims(
+				map[string]string{"cloud.example.com/platform": "aws"},
+				map[string]string{ClaimLabelsAnnotation: "cloud.example.com/platform"},
+				platformClaim),
+			configMap: newRulesConfigMap("- label: cloud.example.com/platform\n"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusterClient := clusterfake.NewSimpleClientset(c.cluster)
+			clusterInformerFactory := clusterinformers.NewSharedInformerFactory(clusterClient, time.Minute*10)
+			if err := clusterInformerFactory.Cluster().V1().ManagedClusters().Informer().GetStore().Add(c.cluster); err != nil {
+				t.Fatal(err)
+			}
+			kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubefake.NewSimpleClientset(), time.Minute*10)
+			if c.configMap != nil {
+				if err := kubeInformerFactory.Core().V1().ConfigMaps().Informer().GetStore().Add(c.configMap); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctrl := &claimLabelController{
+				patcher: patcher.NewPatcher[
+					*clusterv1.ManagedCluster, clusterv1.ManagedClusterSpec, clusterv1.ManagedClusterStatus](
+					clusterClient.ClusterV1().ManagedClusters()),
+				clusterLister:   clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+				configMapLister: kubeInformerFactory.Core().V1().ConfigMaps().Lister(),
+				namespace:       "open-cluster-management-hub",
+				name:            "claim-label-rules",
+				recorder:        eventstesting.NewTestingEventRecorder(t),
+			}
+			clusterClient.ClearActions()
+
+			if err := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, c.cluster.Name)); err != nil {
+				t.Errorf("unexpected err: %v", err)
+			}
+
+			testingcommon.AssertActions(t, clusterClient.Actions(), c.expectedActions...)
+			if len(c.expectedActions) == 0 {
+				return
+			}
+
+			patch := map[string]map[string]interface{}{}
+			if err := json.Unmarshal(clusterClient.Actions()[0].(clienttesting.PatchActionImpl).Patch, &patch); err != nil {
+				t.Fatal(err)
+			}
+			if labels, _ := patch["metadata"]["labels"].(map[string]interface{}); !reflect.DeepEqual(labels, c.expectedLabels) {
+				t.Errorf("expected labels patch %v, but got %v", c.expectedLabels, labels)
+			}
+			if annotations, _ := patch["metadata"]["annotations"].(map[string]interface{}); !reflect.DeepEqual(
+				annotations, c.expectedAnnotations) {
+				t.Errorf("expected annotations patch %v, but got %v", c.expectedAnnotations, annotations)
+			}
+		})
+	}
+}
+
+func TestNewClaimLabelController(t *testing.T) {
+	clusterInformerFactory := clusterinformers.NewSharedInformerFactory(clusterfake.NewSimpleClientset(), time.Minute*10)
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubefake.NewSimpleClientset(), time.Minute*10)
+
+	for _, key := range []string{"claim-label-rules", "/claim-label-rules", "a/b/c"} {
+		if _, err := NewClaimLabelController(clusterfake.NewSimpleClientset(),
+			clusterInformerFactory.Cluster().V1().ManagedClusters(), kubeInformerFactory.Core().V1().ConfigMaps(),
+			key, eventstesting.NewTestingEventRecorder(t)); err == nil {
+			t.Errorf("expected error of the configmap key %q", key)
+		}
+	}
+}
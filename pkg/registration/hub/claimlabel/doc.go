@@ -0,0 +1,4 @@
+// Package claimlabel contains the controller which projects the cluster claims of the ManagedClusters into their
+// labels with the transformation rules defined on the hub, so the placements select the clusters with consistent
+// label values across heterogeneous fleets.
+package claimlabel
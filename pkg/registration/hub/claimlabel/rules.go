@@ -0,0 +1,137 @@
+package claimlabel
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+// RulesConfigMapKey is the key of the rules in the ConfigMap of the claim label rules. The value is a yaml list of
+// rules, each rule projects a cluster claim into a label of the ManagedClusters. The value of the label is the value
+// of the first mapping matching the value of the claim, or the default if no mapping matches. There is no label if
+// no mapping matches and the default is empty. The labels with the prefix reserved by open-cluster-management.io, e.g.
+// cluster.open-cluster-management.io/clusterset, cannot be projected. For example, the platforms are normalized into
+// the label cloud.example.com/platform:
+//
+//	data:
+//	  rules: |
+//	    - claim: platform.open-cluster-management.io
+//	      label: cloud.example.com/platform
+//	      mappings:
+//	      - values: ["AWS", "Amazon"]
+//	        value: aws
+//	      - regex: "^(GCP|Google.*)$"
+//	        value: gcp
+//	      default: other
+const RulesConfigMapKey = "rules"
+
+// reservedLabelDomain is the domain of the label prefixes which are reserved for open-cluster-management.
+const reservedLabelDomain = "open-cluster-management.io"
+
+// ClaimLabelRule projects the value of a cluster claim into a label of the ManagedClusters.
+type ClaimLabelRule struct {
+	// Claim is the name of the cluster claim.
+	Claim string `json:"claim"`
+	// Label is the key of the label on the ManagedClusters.
+	Label string `json:"label"`
+	// Mappings transform the value of the claim into the value of the label, the first matching one applies.
+	Mappings []ClaimValueMapping `json:"mappings,omitempty"`
+	// Default is the value of the label if no mapping matches the value of the claim.
+	Default string `json:"default,omitempty"`
+}
+
+// ClaimValueMapping maps the values of a claim matching the values or the regex into the value.
+type ClaimValueMapping struct {
+	// Values are compared with the value of the claim case-insensitively, ignoring the leading and trailing spaces.
+	Values []string `json:"values,omitempty"`
+	// Regex is the regular expression the value of the claim matches.
+	Regex string `json:"regex,omitempty"`
+	// Value is the value of the label.
+	Value string `json:"value"`
+}
+
+type compiledMapping struct {
+	ClaimValueMapping
+	regex *regexp.Regexp
+}
+
+type compiledRule struct {
+	ClaimLabelRule
+	mappings []compiledMapping
+}
+
+// parseRules parses and validates the rules in the yaml list, the labels of the rules must be unique.
+func parseRules(data string) ([]compiledRule, error) {
+	var rules []ClaimLabelRule
+	if err := yaml.UnmarshalStrict([]byte(data), &rules); err != nil {
+		return nil, err
+	}
+
+	labels := map[string]bool{}
+	var compiled []compiledRule
+	for i, rule := range rules {
+		if len(rule.Claim) == 0 {
+			return nil, fmt.Errorf("the claim of rule %d is required", i)
+		}
+		if errs := validation.IsQualifiedName(rule.Label); len(errs) > 0 {
+			return nil, fmt.Errorf("the label %q of rule %d is invalid: %s", rule.Label, i, strings.Join(errs, ", "))
+		}
+		if prefix, _, ok := strings.Cut(rule.Label, "/"); ok &&
+			(prefix == reservedLabelDomain || strings.HasSuffix(prefix, "."+reservedLabelDomain)) {
+			return nil, fmt.Errorf("the label %q of rule %d has a reserved prefix", rule.Label, i)
+		}
+		if labels[rule.Label] {
+			return nil, fmt.Errorf("duplicated rules of label %q", rule.Label)
+		}
+		labels[rule.Label] = true
+		if errs := validation.IsValidLabelValue(rule.Default); len(errs) > 0 {
+			return nil, fmt.Errorf("the default %q of rule %d is invalid: %s", rule.Default, i, strings.Join(errs, ", "))
+		}
+
+		c := compiledRule{ClaimLabelRule: rule}
+		for j, mapping := range rule.Mappings {
+			if len(mapping.Values) == 0 && len(mapping.Regex) == 0 {
+				return nil, fmt.Errorf("either the values or the regex of mapping %d of rule %d is required", j, i)
+			}
+			if errs := validation.IsValidLabelValue(mapping.Value); len(errs) > 0 {
+				return nil, fmt.Errorf("the value %q of mapping %d of rule %d is invalid: %s",
+					mapping.Value, j, i, strings.Join(errs, ", "))
+			}
+			m := compiledMapping{ClaimValueMapping: mapping}
+			if len(mapping.Regex) > 0 {
+				regex, err := regexp.Compile(mapping.Regex)
+				if err != nil {
+					return nil, fmt.Errorf("the regex of mapping %d of rule %d is invalid: %w", j, i, err)
+				}
+				m.regex = regex
+			}
+			c.mappings = append(c.mappings, m)
+		}
+		compiled = append(compiled, c)
+	}
+	return compiled, nil
+}
+
+// labelValue returns the value of the label transformed from the value of the claim, it returns false if no
+// mapping matches the value and there is no default.
+func (r compiledRule) labelValue(claimValue string) (string, bool) {
+	normalized := strings.TrimSpace(claimValue)
+	for _, mapping := range r.mappings {
+		for _, v := range mapping.Values {
+			if strings.EqualFold(strings.TrimSpace(v), normalized) {
+				return mapping.Value, true
+			}
+		}
+		if mapping.regex != nil && mapping.regex.MatchString(claimValue) {
+			return mapping.Value, true
+		}
+	}
+
+	if len(r.Default) > 0 {
+		return r.Default, true
+	}
+	return "", false
+}
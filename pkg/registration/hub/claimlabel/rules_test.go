@@ -0,0 +1,158 @@
+package claimlabel
+
+import (
+	"strings"
+	"testing"
+)
+
+const testRules = `
+- claim: platform.open-cluster-management.io
+  label: cloud.example.com/platform
+  mappings:
+  - values: ["AWS", "Amazon"]
+    value: aws
+  - regex: "^(GCP|Google.*)$"
+    value: gcp
+  default: other
+- claim: region.open-cluster-management.io
+  label: cloud.example.com/region
+  mappings:
+  - regex: "^us-"
+    value: us
+`
+
+func TestParseRules(t *testing.T) {
+	cases := []struct {
+		name        string
+		rules       string
+		expectedErr string
+	}{
+		{
+			name: "no rule",
+		},
+		{
+			name:  "valid rules",
+			rules: testRules,
+		},
+		{
+			name:        "unknown field",
+			rules:       "- claim: a\n  label: b\n  values: [c]\n",
+			expectedErr: "unknown field",
+		},
+		{
+			name:        "claim is missing",
+			rules:       "- label: b\n",
+			expectedErr: "the claim of rule 0 is required",
+		},
+		{
+			name:        "invalid label",
+			rules:       "- claim: a\n  label: b/c/d\n",
+			expectedErr: "the label \"b/c/d\" of rule 0 is invalid",
+		},
+		{
+			name:        "reserved label prefix",
+			rules:       "- claim: a\n  label: cluster.open-cluster-management.io/clusterset\n",
+			expectedErr: "the label \"cluster.open-cluster-management.io/clusterset\" of rule 0 has a reserved prefix",
+		},
+		{
+			name:        "reserved label domain",
+			rules:       "- claim: a\n  label: open-cluster-management.io/b\n",
+			expectedErr: "the label \"open-cluster-management.io/b\" of rule 0 has a reserved prefix",
+		},
+		{
+			name:        "duplicated labels",
+			rules:       "- claim: a\n  label: b\n- claim: c\n  label: b\n",
+			expectedErr: "duplicated rules of label \"b\"",
+		},
+		{
+			name:        "invalid default",
+			rules:       "- claim: a\n  label: b\n  default: \"not valid\"\n",
+			expectedErr: "the default \"not valid\" of rule 0 is invalid",
+		},
+		{
+			name:        "mapping without values and regex",
+			rules:       "- claim: a\n  label: b\n  mappings:\n  - value: c\n",
+			expectedErr: "either the values or the regex of mapping 0 of rule 0 is required",
+		},
+		{
+			name:        "invalid mapping value",
+			rules:       "- claim: a\n  label: b\n  mappings:\n  - values: [c]\n    value: \"-c\"\n",
+			expectedErr: "the value \"-c\" of mapping 0 of rule 0 is invalid",
+		},
+		{
+			name:        "invalid regex",
+			rules:       "- claim: a\n  label: b\n  mappings:\n  - regex: \"(\"\n    value: c\n",
+			expectedErr: "the regex of mapping 0 of rule 0 is invalid",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := parseRules(c.rules)
+			switch {
+			case len(c.expectedErr) > 0 && (err == nil || !strings.Contains(err.Error(), c.expectedErr)):
+				t.Errorf("expected error %q, but got %v", c.expectedErr, err)
+			case len(c.expectedErr) == 0 && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestLabelValue(t *testing.T) {
+	rules, err := parseRules(testRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name          string
+		rule          compiledRule
+		claimValue    string
+		expectedValue string
+		expectedOK    bool
+	}{
+		{
+			name:          "match the values case-insensitively",
+			rule:          rules[0],
+			claimValue:    " amazon ",
+			expectedValue: "aws",
+			expectedOK:    true,
+		},
+		{
+			name:          "match the regex",
+			rule:          rules[0],
+			claimValue:    "Google Cloud",
+			expectedValue: "gcp",
+			expectedOK:    true,
+		},
+		{
+			name:          "default",
+			rule:          rules[0],
+			claimValue:    "Azure",
+			expectedValue: "other",
+			expectedOK:    true,
+		},
+		{
+			name:          "match the regex without default",
+			rule:          rules[1],
+			claimValue:    "us-east-1",
+			expectedValue: "us",
+			expectedOK:    true,
+		},
+		{
+			name:       "drop the value without mapping and default",
+			rule:       rules[1],
+			claimValue: "eu-west-1",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			value, ok := c.rule.labelValue(c.claimValue)
+			if value != c.expectedValue || ok != c.expectedOK {
+				t.Errorf("expected %q %v, but got %q %v", c.expectedValue, c.expectedOK, value, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package clusterprofile
+
+import (
+	"context"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	clusterinformerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/logging"
+	"open-cluster-management.io/ocm/pkg/common/queue"
+)
+
+const (
+	// ClusterManagerName is the name of the cluster manager of the ClusterProfiles maintained by the controller.
+	ClusterManagerName = "open-cluster-management"
+	// ClusterManagerLabel is the label of the ClusterProfiles whose value is the name of their cluster manager.
+	ClusterManagerLabel = "x-k8s.io/cluster-manager"
+
+	// The conditions of the ClusterProfiles.
+	ConditionControlPlaneHealthy = "ControlPlaneHealthy"
+	ConditionJoined              = "Joined"
+)
+
+// ClusterProfileGVR is the resource of the ClusterProfiles.
+var ClusterProfileGVR = schema.GroupVersionResource{
+	Group:    "multicluster.x-k8s.io",
+	Version:  "v1alpha1",
+	Resource: "clusterprofiles",
+}
+
+// clusterProfileSpec and clusterProfileStatus mirror the fields of the ClusterProfile API maintained by the controller,
+// the ClusterProfiles are handled as unstructured objects since the API module is not a dependency of ocm.
+type clusterProfileSpec struct {
+	DisplayName    string         `json:"displayName,omitempty"`
+	ClusterManager clusterManager `json:"clusterManager"`
+}
+
+type clusterManager struct {
+	Name string `json:"name"`
+}
+
+type clusterProfileStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	Version    version            `json:"version,omitempty"`
+	Properties []property         `json:"properties,omitempty"`
+}
+
+type version struct {
+	Kubernetes string `json:"kubernetes,omitempty"`
+}
+
+type property struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// clusterProfileController maintains a ClusterProfile in the namespace for each ManagedCluster. The identity, the
+// health, the kubernetes version and the cluster claims of the ManagedCluster are mirrored into the ClusterProfile,
+// and the ClusterProfile is deleted with the ManagedCluster. Nothing is maintained if the ClusterProfile API is not
+// installed.
+type clusterProfileController struct {
+	namespace     string
+	profileClient dynamic.NamespaceableResourceInterface
+	clusterLister clusterlisterv1.ManagedClusterLister
+}
+
+// NewClusterProfileController creates a new cluster profile controller
+func NewClusterProfileController(
+	namespace string,
+	dynamicClient dynamic.Interface,
+	clusterInformer clusterinformerv1.ManagedClusterInformer,
+	recorder events.Recorder) factory.Controller {
+	c := &clusterProfileController{
+		namespace:     namespace,
+		profileClient: dynamicClient.Resource(ClusterProfileGVR),
+		clusterLister: clusterInformer.Lister(),
+	}
+	return factory.New().
+		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterInformer.Informer()).
+		WithSync(logging.WithControllerLogger("ClusterProfileController", c.sync)).
+		ToController("ClusterProfileController", recorder)
+}
+
+func (c *clusterProfileController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	clusterName := syncCtx.QueueKey()
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling ClusterProfile", "managedClusterName", clusterName)
+
+	cluster, err := c.clusterLister.Get(clusterName)
+	if errors.IsNotFound(err) {
+		return c.deleteProfile(ctx, clusterName)
+	}
+	if err != nil {
+		return err
+	}
+	if !cluster.DeletionTimestamp.IsZero() {
+		return c.deleteProfile(ctx, clusterName)
+	}
+
+	profile, err := c.profileClient.Namespace(c.namespace).Get(ctx, clusterName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		profile, err = c.createProfile(ctx, cluster)
+		if errors.IsNotFound(err) {
+			logger.V(4).Info("ClusterProfile API is not installed")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	}
+
+	if profile.GetLabels()[ClusterManagerLabel] != ClusterManagerName {
+		// the profile is maintained by another cluster manager
+		return nil
+	}
+
+	status := &clusterProfileStatus{}
+	if rawStatus, ok := profile.Object["status"].(map[string]interface{}); ok {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawStatus, status); err != nil {
+			return err
+		}
+	}
+	newStatus := desiredStatus(cluster, status)
+	if equality.Semantic.DeepEqual(status, newStatus) {
+		return nil
+	}
+
+	rawStatus, err := runtime.DefaultUnstructuredConverter.ToUnstructured(newStatus)
+	if err != nil {
+		return err
+	}
+	profile = profile.DeepCopy()
+	profile.Object["status"] = rawStatus
+	_, err = c.profileClient.Namespace(c.namespace).UpdateStatus(ctx, profile, metav1.UpdateOptions{})
+	return err
+}
+
+func (c *clusterProfileController) createProfile(ctx context.Context, cluster *clusterv1.ManagedCluster) (*unstructured.Unstructured, error) {
+	spec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&clusterProfileSpec{
+		DisplayName:    cluster.Name,
+		ClusterManager: clusterManager{Name: ClusterManagerName},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	profile.SetAPIVersion(ClusterProfileGVR.GroupVersion().String())
+	profile.SetKind("ClusterProfile")
+	profile.SetNamespace(c.namespace)
+	profile.SetName(cluster.Name)
+	profile.SetLabels(map[string]string{
+		ClusterManagerLabel:           ClusterManagerName,
+		clusterv1.ClusterNameLabelKey: cluster.Name,
+	})
+	return c.profileClient.Namespace(c.namespace).Create(ctx, profile, metav1.CreateOptions{})
+}
+
+func (c *clusterProfileController) deleteProfile(ctx context.Context, clusterName string) error {
+	profile, err := c.profileClient.Namespace(c.namespace).Get(ctx, clusterName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if profile.GetLabels()[ClusterManagerLabel] != ClusterManagerName {
+		return nil
+	}
+
+	err = c.profileClient.Namespace(c.namespace).Delete(ctx, clusterName, metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// desiredStatus returns the status of the ClusterProfile mirrored from the ManagedCluster, the transition time of
+// the existing conditions are kept if their status are not changed.
+func desiredStatus(cluster *clusterv1.ManagedCluster, status *clusterProfileStatus) *clusterProfileStatus {
+	newStatus := &clusterProfileStatus{
+		Conditions: append([]metav1.Condition{}, status.Conditions...),
+		Version:    version{Kubernetes: cluster.Status.Version.Kubernetes},
+	}
+
+	mirrorCondition(&newStatus.Conditions, cluster, clusterv1.ManagedClusterConditionAvailable, ConditionControlPlaneHealthy)
+	mirrorCondition(&newStatus.Conditions, cluster, clusterv1.ManagedClusterConditionJoined, ConditionJoined)
+
+	for _, claim := range cluster.Status.ClusterClaims {
+		newStatus.Properties = append(newStatus.Properties, property{Name: claim.Name, Value: claim.Value})
+	}
+	if len(newStatus.Conditions) == 0 {
+		newStatus.Conditions = nil
+	}
+	return newStatus
+}
+
+func mirrorCondition(conditions *[]metav1.Condition, cluster *clusterv1.ManagedCluster, clusterConditionType, conditionType string) {
+	clusterCondition := meta.FindStatusCondition(cluster.Status.Conditions, clusterConditionType)
+	if clusterCondition == nil {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:    conditionType,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "Unknown",
+			Message: "The status of the managed cluster is unknown",
+		})
+		return
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:    conditionType,
+		Status:  clusterCondition.Status,
+		Reason:  clusterCondition.Reason,
+		Message: clusterCondition.Message,
+	})
+}
@@ -0,0 +1,160 @@
+package clusterprofile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+)
+
+const testNamespace = "open-cluster-management"
+
+func newProfile(manager string) *unstructured.Unstructured {
+	profile := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	profile.SetAPIVersion("multicluster.x-k8s.io/v1alpha1")
+	profile.SetKind("ClusterProfile")
+	profile.SetNamespace(testNamespace)
+	profile.SetName(testinghelpers.TestManagedClusterName)
+	profile.SetLabels(map[string]string{ClusterManagerLabel: manager})
+	return profile
+}
+
+func newClusterWithClaims() *clusterv1.ManagedCluster {
+	cluster := testinghelpers.NewAvailableManagedCluster()
+	cluster.Status.Version = clusterv1.ManagedClusterVersion{Kubernetes: "v1.27.0"}
+	cluster.Status.ClusterClaims = []clusterv1.ManagedClusterClaim{{Name: "region", Value: "us-east-1"}}
+	return cluster
+}
+
+func TestSync(t *testing.T) {
+	cases := []struct {
+		name            string
+		clusters        []runtime.Object
+		profiles        []runtime.Object
+		notInstalled    bool
+		expectedVerbs   []string
+		validateProfile func(t *testing.T, profile *unstructured.Unstructured)
+	}{
+		{
+			name:          "create profile",
+			clusters:      []runtime.Object{newClusterWithClaims()},
+			expectedVerbs: []string{"get", "create", "update"},
+			validateProfile: func(t *testing.T, profile *unstructured.Unstructured) {
+				status := &clusterProfileStatus{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(
+					profile.Object["status"].(map[string]interface{}), status); err != nil {
+					t.Fatal(err)
+				}
+				testingcommon.AssertCondition(t, status.Conditions, metav1.Condition{
+					Type:    ConditionControlPlaneHealthy,
+					Status:  metav1.ConditionTrue,
+					Reason:  "ManagedClusterAvailable",
+					Message: "Managed cluster is available",
+				})
+				testingcommon.AssertCondition(t, status.Conditions, metav1.Condition{
+					Type:    ConditionJoined,
+					Status:  metav1.ConditionUnknown,
+					Reason:  "Unknown",
+					Message: "The status of the managed cluster is unknown",
+				})
+				if status.Version.Kubernetes != "v1.27.0" {
+					t.Errorf("expected version v1.27.0, but got %s", status.Version.Kubernetes)
+				}
+				if len(status.Properties) != 1 || status.Properties[0].Name != "region" || status.Properties[0].Value != "us-east-1" {
+					t.Errorf("unexpected properties %v", status.Properties)
+				}
+				if profile.GetLabels()[clusterv1.ClusterNameLabelKey] != testinghelpers.TestManagedClusterName {
+					t.Errorf("unexpected labels %v", profile.GetLabels())
+				}
+			},
+		},
+		{
+			name:          "profile of another cluster manager",
+			clusters:      []runtime.Object{newClusterWithClaims()},
+			profiles:      []runtime.Object{newProfile("another")},
+			expectedVerbs: []string{"get"},
+		},
+		{
+			name:          "api is not installed",
+			clusters:      []runtime.Object{newClusterWithClaims()},
+			notInstalled:  true,
+			expectedVerbs: []string{"get", "create"},
+		},
+		{
+			name:          "delete profile",
+			profiles:      []runtime.Object{newProfile(ClusterManagerName)},
+			expectedVerbs: []string{"get", "delete"},
+		},
+		{
+			name:          "no profile to delete",
+			expectedVerbs: []string{"get"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), c.profiles...)
+			if c.notInstalled {
+				dynamicClient.PrependReactor("create", "clusterprofiles",
+					func(action clienttesting.Action) (bool, runtime.Object, error) {
+						return true, nil, errors.NewNotFound(ClusterProfileGVR.GroupResource(), "")
+					})
+			}
+			clusterClient := clusterfake.NewSimpleClientset(c.clusters...)
+			clusterInformerFactory := clusterinformers.NewSharedInformerFactory(clusterClient, time.Minute*10)
+			for _, cluster := range c.clusters {
+				if err := clusterInformerFactory.Cluster().V1().ManagedClusters().Informer().GetStore().Add(cluster); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctrl := &clusterProfileController{
+				namespace:     testNamespace,
+				profileClient: dynamicClient.Resource(ClusterProfileGVR),
+				clusterLister: clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+			}
+			if err := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, testinghelpers.TestManagedClusterName)); err != nil {
+				t.Errorf("unexpected err: %v", err)
+			}
+
+			testingcommon.AssertActions(t, dynamicClient.Actions(), c.expectedVerbs...)
+			if c.validateProfile != nil {
+				profile, err := dynamicClient.Resource(ClusterProfileGVR).Namespace(testNamespace).Get(
+					context.TODO(), testinghelpers.TestManagedClusterName, metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				c.validateProfile(t, profile)
+			}
+		})
+	}
+}
+
+func TestDesiredStatusKeepsTransitionTime(t *testing.T) {
+	transitionTime := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	cluster := newClusterWithClaims()
+	status := desiredStatus(cluster, &clusterProfileStatus{})
+	for i := range status.Conditions {
+		status.Conditions[i].LastTransitionTime = transitionTime
+	}
+
+	newStatus := desiredStatus(cluster, status)
+	for _, condition := range newStatus.Conditions {
+		if !condition.LastTransitionTime.Equal(&transitionTime) {
+			t.Errorf("expected the transition time of %s is kept, but got %v", condition.Type, condition.LastTransitionTime)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+// Package clusterprofile contains the controller which mirrors the ManagedClusters into the ClusterProfiles of the
+// SIG-Multicluster cluster inventory API, so the tools built on the inventory API consume the clusters registered
+// to the hub without custom adapters.
+package clusterprofile
@@ -17,6 +17,7 @@ import (
 	clusterv1listers "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
 
 	"open-cluster-management.io/ocm/pkg/common/apply"
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 )
 
@@ -66,7 +67,7 @@ func NewManagedClusterClusterroleController(
 			queue.FilterByNames(registrationClusterRole, workClusterRole),
 			clusterRoleInformer.Informer()).
 		WithInformers(clusterInformer.Informer()).
-		WithSync(c.sync).
+		WithSync(logging.WithControllerLogger("ManagedClusterClusterRoleController", c.sync)).
 		ToController("ManagedClusterClusterRoleController", recorder)
 }
 
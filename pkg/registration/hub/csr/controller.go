@@ -14,6 +14,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/registration/helpers"
 )
@@ -53,7 +54,7 @@ func NewCSRApprovingController[T CSR](
 
 	return factory.New().
 		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, csrInformer).
-		WithSync(c.sync).
+		WithSync(logging.WithControllerLogger("CSRApprovingController", c.sync)).
 		ToController("CSRApprovingController", recorder)
 }
 
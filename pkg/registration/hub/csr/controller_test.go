@@ -44,6 +44,7 @@ func TestSync(t *testing.T) {
 		startingClusters     []runtime.Object
 		startingCSRs         []runtime.Object
 		approvalUsers        []string
+		approvalSigners      []string
 		autoApprovingAllowed bool
 		validateActions      func(t *testing.T, actions []clienttesting.Action)
 	}{
@@ -113,6 +114,33 @@ func TestSync(t *testing.T) {
 				testinghelpers.AssertCSRCondition(t, actual.(*certificatesv1.CertificateSigningRequest).Status.Conditions, expectedCondition)
 			},
 		},
+		{
+			name:             "allow an auto approving csr of an approval signer",
+			startingClusters: []runtime.Object{},
+			startingCSRs: []runtime.Object{func() *certificatesv1.CertificateSigningRequest {
+				csr := testinghelpers.NewCSR(validCSR)
+				csr.Spec.SignerName = "example.com/corporate-ca"
+				return csr
+			}()},
+			approvalSigners:      []string{"example.com/corporate-ca"},
+			autoApprovingAllowed: true,
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "create", "update")
+			},
+		},
+		{
+			name:             "skip a csr of a signer not approved",
+			startingClusters: []runtime.Object{},
+			startingCSRs: []runtime.Object{func() *certificatesv1.CertificateSigningRequest {
+				csr := testinghelpers.NewCSR(validCSR)
+				csr.Spec.SignerName = "example.com/corporate-ca"
+				return csr
+			}()},
+			autoApprovingAllowed: true,
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
 		{
 			name:             "allow an auto approving csr w/o ManagedClusterGroup for backward-compatibility",
 			startingClusters: []runtime.Object{},
@@ -208,13 +236,15 @@ func TestSync(t *testing.T) {
 						kubeClient:    kubeClient,
 						eventRecorder: recorder,
 						approvalUsers: sets.Set[string]{},
+						signers:       sets.New(c.approvalSigners...),
 					},
-					NewCSRRenewalReconciler(kubeClient, recorder),
+					NewCSRRenewalReconciler(kubeClient, c.approvalSigners, recorder),
 					NewCSRBootstrapReconciler(
 						kubeClient,
 						clusterClient,
 						clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
 						c.approvalUsers,
+						c.approvalSigners,
 						recorder,
 					),
 				},
@@ -233,11 +263,12 @@ func TestIsSpokeClusterClientCertRenewal(t *testing.T) {
 	invalidSignerName := "invalidsigner"
 
 	cases := []struct {
-		name        string
-		csr         testinghelpers.CSRHolder
-		isRenewal   bool
-		clusterName string
-		commonName  string
+		name            string
+		csr             testinghelpers.CSRHolder
+		approvalSigners []string
+		isRenewal       bool
+		clusterName     string
+		commonName      string
 	}{
 		{
 			name:      "a spoke cluster csr without labels",
@@ -310,12 +341,35 @@ func TestIsSpokeClusterClientCertRenewal(t *testing.T) {
 			clusterName: "managedcluster1",
 			commonName:  validCSR.CN,
 		},
+		{
+			name: "a renewal csr of an approval signer",
+			csr: func() testinghelpers.CSRHolder {
+				csr := validCSR
+				csr.SignerName = "example.com/corporate-ca"
+				return csr
+			}(),
+			approvalSigners: []string{"example.com/corporate-ca"},
+			isRenewal:       true,
+			clusterName:     "managedcluster1",
+			commonName:      validCSR.CN,
+		},
+		{
+			name: "a renewal csr of a signer not approved",
+			csr: func() testinghelpers.CSRHolder {
+				csr := validCSR
+				csr.SignerName = "example.com/other-ca"
+				return csr
+			}(),
+			approvalSigners: []string{"example.com/corporate-ca"},
+			isRenewal:       false,
+		},
 	}
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
 			logger, _ := ktesting.NewTestContext(t)
-			isRenewal, clusterName, commonName := validateCSR(logger, newCSRInfo(logger, testinghelpers.NewCSR(c.csr)))
+			isRenewal, clusterName, commonName := validateCSR(logger, newCSRInfo(logger, testinghelpers.NewCSR(c.csr)),
+				sets.New(c.approvalSigners...))
 			if isRenewal != c.isRenewal {
 				t.Errorf("expected %t, but failed", c.isRenewal)
 			}
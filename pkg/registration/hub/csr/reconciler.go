@@ -51,12 +51,17 @@ type Reconciler interface {
 
 type csrRenewalReconciler struct {
 	kubeClient    kubernetes.Interface
+	signers       sets.Set[string]
 	eventRecorder events.Recorder
 }
 
-func NewCSRRenewalReconciler(kubeClient kubernetes.Interface, recorder events.Recorder) Reconciler {
+// NewCSRRenewalReconciler returns a reconciler approving the renewal csrs of the accepted managed clusters. Besides
+// the kubernetes.io/kube-apiserver-client, the csrs of the approvalSigners are also approved, and the certificates
+// are issued by the signing controllers of the signers.
+func NewCSRRenewalReconciler(kubeClient kubernetes.Interface, approvalSigners []string, recorder events.Recorder) Reconciler {
 	return &csrRenewalReconciler{
 		kubeClient:    kubeClient,
+		signers:       sets.New(approvalSigners...),
 		eventRecorder: recorder.WithComponentSuffix("csr-approving-controller"),
 	}
 }
@@ -64,7 +69,7 @@ func NewCSRRenewalReconciler(kubeClient kubernetes.Interface, recorder events.Re
 func (r *csrRenewalReconciler) Reconcile(ctx context.Context, csr csrInfo, approveCSR approveCSRFunc) (reconcileState, error) {
 	logger := klog.FromContext(ctx)
 	// Check whether current csr is a valid spoker cluster csr.
-	valid, _, commonName := validateCSR(logger, csr)
+	valid, _, commonName := validateCSR(logger, csr, r.signers)
 	if !valid {
 		logger.V(4).Info("CSR was not recognized", "csrName", csr.name)
 		return reconcileStop, nil
@@ -98,19 +103,25 @@ type csrBootstrapReconciler struct {
 	clusterClient clusterclientset.Interface
 	clusterLister clusterv1listers.ManagedClusterLister
 	approvalUsers sets.Set[string]
+	signers       sets.Set[string]
 	eventRecorder events.Recorder
 }
 
+// NewCSRBootstrapReconciler returns a reconciler accepting the managed clusters and approving their csrs if the
+// csrs are created by the approvalUsers. The csrs of the approvalSigners are approved as well as the
+// kubernetes.io/kube-apiserver-client ones.
 func NewCSRBootstrapReconciler(kubeClient kubernetes.Interface,
 	clusterClient clusterclientset.Interface,
 	clusterLister clusterv1listers.ManagedClusterLister,
 	approvalUsers []string,
+	approvalSigners []string,
 	recorder events.Recorder) Reconciler {
 	return &csrBootstrapReconciler{
 		kubeClient:    kubeClient,
 		clusterClient: clusterClient,
 		clusterLister: clusterLister,
 		approvalUsers: sets.New(approvalUsers...),
+		signers:       sets.New(approvalSigners...),
 		eventRecorder: recorder.WithComponentSuffix("csr-approving-controller"),
 	}
 }
@@ -118,7 +129,7 @@ func NewCSRBootstrapReconciler(kubeClient kubernetes.Interface,
 func (b *csrBootstrapReconciler) Reconcile(ctx context.Context, csr csrInfo, approveCSR approveCSRFunc) (reconcileState, error) {
 	logger := klog.FromContext(ctx)
 	// Check whether current csr is a valid spoker cluster csr.
-	valid, clusterName, _ := validateCSR(logger, csr)
+	valid, clusterName, _ := validateCSR(logger, csr, b.signers)
 	if !valid {
 		logger.V(4).Info("CSR was not recognized", "csrName", csr.name)
 		return reconcileStop, nil
@@ -163,15 +174,15 @@ func (b *csrBootstrapReconciler) accpetCluster(ctx context.Context, managedClust
 }
 
 // To validate a managed cluster csr, we check
-// 1. if the signer name in csr request is valid.
+// 1. if the signer name in csr request is kubernetes.io/kube-apiserver-client or one of the approval signers.
 // 2. if organization field and commonName field in csr request is valid.
-func validateCSR(logger klog.Logger, csr csrInfo) (bool, string, string) {
+func validateCSR(logger klog.Logger, csr csrInfo, approvalSigners sets.Set[string]) (bool, string, string) {
 	spokeClusterName, existed := csr.labels[clusterv1.ClusterNameLabelKey]
 	if !existed {
 		return false, "", ""
 	}
 
-	if csr.signerName != certificatesv1.KubeAPIServerClientSignerName {
+	if csr.signerName != certificatesv1.KubeAPIServerClientSignerName && !approvalSigners.Has(csr.signerName) {
 		return false, "", ""
 	}
 
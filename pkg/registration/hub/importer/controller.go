@@ -0,0 +1,348 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterinformerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	operatorclientset "open-cluster-management.io/api/client/operator/clientset/versioned"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/logging"
+	"open-cluster-management.io/ocm/pkg/common/patcher"
+	"open-cluster-management.io/ocm/pkg/common/queue"
+)
+
+const (
+	// ImportAnnotation on a ManagedCluster starts the import of the cluster. The klusterlet manifests of the cluster
+	// are generated in the import secret in the namespace of the cluster, and applied to the managed cluster if the
+	// value of the annotation is the name of a secret in the namespace of the cluster, which holds the kubeconfig of
+	// the managed cluster. The klusterlet operator is expected to be running on the managed cluster.
+	ImportAnnotation = "cluster.open-cluster-management.io/import"
+
+	// ImportKubeConfigKey is the key of the kubeconfig of the managed cluster in the secret of the import annotation,
+	// and the key of the bootstrap kubeconfig of the hub in the bootstrap secret.
+	ImportKubeConfigKey = "kubeconfig"
+	// ImportAgentNamespaceKey is the optional key of the namespace of the klusterlet agents on the managed cluster
+	// in the secret of the import annotation, it is open-cluster-management-agent by default.
+	ImportAgentNamespaceKey = "agent-namespace"
+	// ImportManifestsKey is the key of the klusterlet manifests in the import secret.
+	ImportManifestsKey = "import.yaml"
+
+	// ManagedClusterConditionImported reports the progress of the import of a ManagedCluster.
+	ManagedClusterConditionImported = "ManagedClusterImported"
+
+	defaultAgentNamespace    = "open-cluster-management-agent"
+	bootstrapSecretName      = "bootstrap-hub-kubeconfig" // #nosec G101
+	klusterletName           = "klusterlet"
+	reasonImportFailed       = "ImportFailed"
+	reasonManifestsGenerated = "ManifestsGenerated"
+	reasonManifestsApplied   = "ManifestsApplied"
+	importSecretNameSuffix   = "-import"
+	importManifestsSeparator = "---\n"
+)
+
+// spokeClients are the clients of the managed cluster to be imported.
+type spokeClients struct {
+	kubeClient     kubernetes.Interface
+	operatorClient operatorclientset.Interface
+}
+
+func newSpokeClients(kubeconfig []byte) (*spokeClients, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	operatorClient, err := operatorclientset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &spokeClients{
+		kubeClient:     kubeClient,
+		operatorClient: operatorClient,
+	}, nil
+}
+
+// importController imports the ManagedClusters with the import annotation. The bootstrap kubeconfig in the
+// klusterlet manifests is the one in the bootstrap secret on the hub, which is provided by the hub admin. A cluster
+// is no longer reconciled once it joins the hub.
+type importController struct {
+	kubeClient               kubernetes.Interface
+	clusterLister            clusterlisterv1.ManagedClusterLister
+	patcher                  patcher.Patcher[*clusterv1.ManagedCluster, clusterv1.ManagedClusterSpec, clusterv1.ManagedClusterStatus]
+	bootstrapSecretNamespace string
+	bootstrapSecretName      string
+	// newSpokeClients builds the clients of the managed cluster, it is replaced in the unit tests.
+	newSpokeClients func(kubeconfig []byte) (*spokeClients, error)
+	eventRecorder   events.Recorder
+}
+
+// NewImportController creates a new cluster import controller, the bootstrapSecret is the namespace/name of the
+// secret holding the bootstrap kubeconfig of the hub.
+func NewImportController(
+	kubeClient kubernetes.Interface,
+	clusterClient clusterclientset.Interface,
+	clusterInformer clusterinformerv1.ManagedClusterInformer,
+	bootstrapSecret string,
+	recorder events.Recorder) (factory.Controller, error) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(bootstrapSecret)
+	if err != nil {
+		return nil, err
+	}
+	if len(namespace) == 0 || len(name) == 0 {
+		return nil, fmt.Errorf("the bootstrap secret %q is not in the format of namespace/name", bootstrapSecret)
+	}
+
+	c := &importController{
+		kubeClient:    kubeClient,
+		clusterLister: clusterInformer.Lister(),
+		patcher: patcher.NewPatcher[
+			*clusterv1.ManagedCluster, clusterv1.ManagedClusterSpec, clusterv1.ManagedClusterStatus](
+			clusterClient.ClusterV1().ManagedClusters()),
+		bootstrapSecretNamespace: namespace,
+		bootstrapSecretName:      name,
+		newSpokeClients:          newSpokeClients,
+		eventRecorder:            recorder.WithComponentSuffix("cluster-import-controller"),
+	}
+	return factory.New().
+		WithFilteredEventsInformersQueueKeysFunc(
+			queue.QueueKeyByMetaName,
+			func(obj interface{}) bool {
+				accessor, err := meta.Accessor(obj)
+				if err != nil {
+					return false
+				}
+				_, ok := accessor.GetAnnotations()[ImportAnnotation]
+				return ok
+			},
+			clusterInformer.Informer()).
+		WithSync(logging.WithControllerLogger("ClusterImportController", c.sync)).
+		ToController("ClusterImportController", recorder), nil
+}
+
+func (c *importController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	clusterName := syncCtx.QueueKey()
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling the import of ManagedCluster", "managedClusterName", clusterName)
+
+	cluster, err := c.clusterLister.Get(clusterName)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	kubeconfigSecretName, ok := cluster.Annotations[ImportAnnotation]
+	if !ok || !cluster.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	if meta.IsStatusConditionTrue(cluster.Status.Conditions, clusterv1.ManagedClusterConditionJoined) {
+		// the cluster is imported, the klusterlet is left to the managed cluster.
+		return nil
+	}
+
+	bootstrapSecret, err := c.kubeClient.CoreV1().Secrets(c.bootstrapSecretNamespace).Get(
+		ctx, c.bootstrapSecretName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		return c.updateCondition(ctx, cluster, metav1.ConditionFalse, reasonImportFailed,
+			fmt.Sprintf("The bootstrap secret %s/%s is not found", c.bootstrapSecretNamespace, c.bootstrapSecretName))
+	case err != nil:
+		return err
+	}
+	bootstrapKubeconfig := bootstrapSecret.Data[ImportKubeConfigKey]
+	if len(bootstrapKubeconfig) == 0 {
+		return c.updateCondition(ctx, cluster, metav1.ConditionFalse, reasonImportFailed,
+			fmt.Sprintf("There is no %q in the bootstrap secret %s/%s",
+				ImportKubeConfigKey, c.bootstrapSecretNamespace, c.bootstrapSecretName))
+	}
+
+	var kubeconfigSecret *corev1.Secret
+	agentNamespace := defaultAgentNamespace
+	if len(kubeconfigSecretName) > 0 {
+		kubeconfigSecret, err = c.kubeClient.CoreV1().Secrets(clusterName).Get(ctx, kubeconfigSecretName, metav1.GetOptions{})
+		switch {
+		case errors.IsNotFound(err):
+			return c.updateCondition(ctx, cluster, metav1.ConditionFalse, reasonImportFailed,
+				fmt.Sprintf("The import secret %s/%s is not found", clusterName, kubeconfigSecretName))
+		case err != nil:
+			return err
+		}
+		if namespace := string(kubeconfigSecret.Data[ImportAgentNamespaceKey]); len(namespace) > 0 {
+			agentNamespace = namespace
+		}
+	}
+
+	// generate the klusterlet manifests in the import secret, so they could also be applied by others.
+	namespace, secret, klusterlet := klusterletManifests(clusterName, agentNamespace, bootstrapKubeconfig)
+	importManifests, err := renderManifests(namespace, secret, klusterlet)
+	if err != nil {
+		return err
+	}
+	importSecretName := clusterName + importSecretNameSuffix
+	if _, _, err := resourceapply.ApplySecret(ctx, c.kubeClient.CoreV1(), c.eventRecorder, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      importSecretName,
+			Namespace: clusterName,
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabelKey: clusterName,
+			},
+		},
+		Data: map[string][]byte{
+			ImportManifestsKey: importManifests,
+		},
+	}); err != nil {
+		return err
+	}
+
+	if kubeconfigSecret == nil {
+		return c.updateCondition(ctx, cluster, metav1.ConditionFalse, reasonManifestsGenerated,
+			fmt.Sprintf("The klusterlet manifests are generated in the secret %s/%s, apply them on the managed cluster "+
+				"to import it", clusterName, importSecretName))
+	}
+
+	spoke, err := c.newSpokeClients(kubeconfigSecret.Data[ImportKubeConfigKey])
+	if err != nil {
+		return c.updateCondition(ctx, cluster, metav1.ConditionFalse, reasonImportFailed,
+			fmt.Sprintf("Unable to connect to the managed cluster with %q of the import secret %s/%s: %v",
+				ImportKubeConfigKey, clusterName, kubeconfigSecretName, err))
+	}
+	if err := c.applyManifests(ctx, spoke, namespace, secret, klusterlet); err != nil {
+		if updateErr := c.updateCondition(ctx, cluster, metav1.ConditionFalse, reasonImportFailed,
+			fmt.Sprintf("Failed to apply the klusterlet manifests on the managed cluster: %v", err)); updateErr != nil {
+			return updateErr
+		}
+		return err
+	}
+
+	return c.updateCondition(ctx, cluster, metav1.ConditionTrue, reasonManifestsApplied,
+		"The klusterlet manifests are applied on the managed cluster")
+}
+
+// klusterletManifests returns the namespace of the klusterlet agents, the bootstrap secret and the Klusterlet to
+// import the cluster.
+func klusterletManifests(clusterName, agentNamespace string, bootstrapKubeconfig []byte) (
+	*corev1.Namespace, *corev1.Secret, *operatorapiv1.Klusterlet) {
+	namespace := &corev1.Namespace{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Namespace",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: agentNamespace,
+		},
+	}
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstrapSecretName,
+			Namespace: agentNamespace,
+		},
+		Data: map[string][]byte{
+			ImportKubeConfigKey: bootstrapKubeconfig,
+		},
+	}
+	klusterlet := &operatorapiv1.Klusterlet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: operatorapiv1.GroupVersion.String(),
+			Kind:       "Klusterlet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: klusterletName,
+		},
+		Spec: operatorapiv1.KlusterletSpec{
+			ClusterName: clusterName,
+			Namespace:   agentNamespace,
+			DeployOption: operatorapiv1.KlusterletDeployOption{
+				Mode: operatorapiv1.InstallModeDefault,
+			},
+		},
+	}
+	return namespace, secret, klusterlet
+}
+
+// renderManifests renders the objects into a multi-document yaml.
+func renderManifests(objs ...runtime.Object) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, obj := range objs {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(importManifestsSeparator)
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// applyManifests applies the klusterlet manifests on the managed cluster. Only the cluster name, the agent namespace
+// and the deploy mode of an existing Klusterlet are updated.
+func (c *importController) applyManifests(ctx context.Context, spoke *spokeClients,
+	namespace *corev1.Namespace, secret *corev1.Secret, klusterlet *operatorapiv1.Klusterlet) error {
+	if _, err := spoke.kubeClient.CoreV1().Namespaces().Create(
+		ctx, namespace, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	if _, _, err := resourceapply.ApplySecret(ctx, spoke.kubeClient.CoreV1(), c.eventRecorder, secret); err != nil {
+		return err
+	}
+
+	existing, err := spoke.operatorClient.OperatorV1().Klusterlets().Get(ctx, klusterlet.Name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		_, err = spoke.operatorClient.OperatorV1().Klusterlets().Create(ctx, klusterlet, metav1.CreateOptions{})
+		return err
+	case err != nil:
+		return err
+	}
+
+	updated := existing.DeepCopy()
+	updated.Spec.ClusterName = klusterlet.Spec.ClusterName
+	updated.Spec.Namespace = klusterlet.Spec.Namespace
+	updated.Spec.DeployOption.Mode = klusterlet.Spec.DeployOption.Mode
+	if equality.Semantic.DeepEqual(updated.Spec, existing.Spec) {
+		return nil
+	}
+	_, err = spoke.operatorClient.OperatorV1().Klusterlets().Update(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
+
+func (c *importController) updateCondition(ctx context.Context, cluster *clusterv1.ManagedCluster,
+	status metav1.ConditionStatus, reason, message string) error {
+	newCluster := cluster.DeepCopy()
+	meta.SetStatusCondition(&newCluster.Status.Conditions, metav1.Condition{
+		Type:    ManagedClusterConditionImported,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+	_, err := c.patcher.PatchStatus(ctx, newCluster, newCluster.Status, cluster.Status)
+	return err
+}
@@ -0,0 +1,261 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events/eventstesting"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	operatorfake "open-cluster-management.io/api/client/operator/clientset/versioned/fake"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/patcher"
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+)
+
+func newImportingCluster(kubeconfigSecret string) *clusterv1.ManagedCluster {
+	cluster := testinghelpers.NewAcceptedManagedCluster()
+	cluster.Annotations = map[string]string{ImportAnnotation: kubeconfigSecret}
+	return cluster
+}
+
+func newBootstrapSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "bootstrap", Namespace: "open-cluster-management"},
+		Data: map[string][]byte{
+			ImportKubeConfigKey: []byte("bootstrap-kubeconfig"),
+		},
+	}
+}
+
+func newKubeconfigSecret(agentNamespace string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "import", Namespace: testinghelpers.TestManagedClusterName},
+		Data: map[string][]byte{
+			ImportKubeConfigKey: []byte("spoke-kubeconfig"),
+		},
+	}
+	if len(agentNamespace) > 0 {
+		secret.Data[ImportAgentNamespaceKey] = []byte(agentNamespace)
+	}
+	return secret
+}
+
+func TestSync(t *testing.T) {
+	cases := []struct {
+		name                  string
+		cluster               *clusterv1.ManagedCluster
+		secrets               []runtime.Object
+		klusterlets           []runtime.Object
+		validateClusterAction func(t *testing.T, actions []clienttesting.Action)
+		validateKubeActions   func(t *testing.T, actions []clienttesting.Action)
+		validateSpokeActions  func(t *testing.T, spoke *spokeFakeClients)
+	}{
+		{
+			name:    "cluster is not importing",
+			cluster: testinghelpers.NewAcceptedManagedCluster(),
+			validateClusterAction: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+			validateKubeActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+		{
+			name: "cluster is joined",
+			cluster: func() *clusterv1.ManagedCluster {
+				cluster := testinghelpers.NewJoinedManagedCluster()
+				cluster.Annotations = map[string]string{ImportAnnotation: "import"}
+				return cluster
+			}(),
+			secrets: []runtime.Object{newBootstrapSecret(), newKubeconfigSecret("")},
+			validateClusterAction: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+			validateKubeActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+		{
+			name:    "bootstrap secret is not found",
+			cluster: newImportingCluster(""),
+			validateClusterAction: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch")
+				assertImportedCondition(t, actions[0], metav1.ConditionFalse, reasonImportFailed)
+			},
+			validateKubeActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "get")
+			},
+		},
+		{
+			name:    "kubeconfig secret is not found",
+			cluster: newImportingCluster("import"),
+			secrets: []runtime.Object{newBootstrapSecret()},
+			validateClusterAction: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch")
+				assertImportedCondition(t, actions[0], metav1.ConditionFalse, reasonImportFailed)
+			},
+			validateKubeActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "get", "get")
+			},
+		},
+		{
+			name:    "generate manifests",
+			cluster: newImportingCluster(""),
+			secrets: []runtime.Object{newBootstrapSecret()},
+			validateClusterAction: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch")
+				assertImportedCondition(t, actions[0], metav1.ConditionFalse, reasonManifestsGenerated)
+			},
+			validateKubeActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "get", "get", "create")
+				secret := actions[2].(clienttesting.CreateAction).GetObject().(*corev1.Secret)
+				if secret.Name != testinghelpers.TestManagedClusterName+importSecretNameSuffix {
+					t.Errorf("unexpected import secret %s", secret.Name)
+				}
+				manifests := string(secret.Data[ImportManifestsKey])
+				for _, expected := range []string{
+					"kind: Namespace", "kind: Secret", "kind: Klusterlet",
+					"name: " + bootstrapSecretName, "clusterName: " + testinghelpers.TestManagedClusterName,
+				} {
+					if !strings.Contains(manifests, expected) {
+						t.Errorf("expected %q in the import manifests, but got %s", expected, manifests)
+					}
+				}
+			},
+			validateSpokeActions: func(t *testing.T, spoke *spokeFakeClients) {
+				testingcommon.AssertNoActions(t, spoke.kubeClient.Actions())
+				testingcommon.AssertNoActions(t, spoke.operatorClient.Actions())
+			},
+		},
+		{
+			name:    "apply manifests",
+			cluster: newImportingCluster("import"),
+			secrets: []runtime.Object{newBootstrapSecret(), newKubeconfigSecret("agent")},
+			validateClusterAction: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch")
+				assertImportedCondition(t, actions[0], metav1.ConditionTrue, reasonManifestsApplied)
+			},
+			validateKubeActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "get", "get", "get", "create")
+			},
+			validateSpokeActions: func(t *testing.T, spoke *spokeFakeClients) {
+				testingcommon.AssertActions(t, spoke.kubeClient.Actions(), "create", "get", "create")
+				secret := spoke.kubeClient.Actions()[2].(clienttesting.CreateAction).GetObject().(*corev1.Secret)
+				if secret.Namespace != "agent" || string(secret.Data[ImportKubeConfigKey]) != "bootstrap-kubeconfig" {
+					t.Errorf("unexpected bootstrap secret %v", secret)
+				}
+				testingcommon.AssertActions(t, spoke.operatorClient.Actions(), "get", "create")
+				klusterlet := spoke.operatorClient.Actions()[1].(clienttesting.CreateAction).GetObject().(*operatorapiv1.Klusterlet)
+				if klusterlet.Spec.ClusterName != testinghelpers.TestManagedClusterName || klusterlet.Spec.Namespace != "agent" {
+					t.Errorf("unexpected klusterlet %v", klusterlet.Spec)
+				}
+			},
+		},
+		{
+			name:    "update klusterlet",
+			cluster: newImportingCluster("import"),
+			secrets: []runtime.Object{newBootstrapSecret(), newKubeconfigSecret("")},
+			klusterlets: []runtime.Object{&operatorapiv1.Klusterlet{
+				ObjectMeta: metav1.ObjectMeta{Name: klusterletName},
+				Spec: operatorapiv1.KlusterletSpec{
+					ClusterName:   "cluster2",
+					Namespace:     defaultAgentNamespace,
+					ImagePullSpec: "quay.io/open-cluster-management/registration-operator",
+				},
+			}},
+			validateClusterAction: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch")
+				assertImportedCondition(t, actions[0], metav1.ConditionTrue, reasonManifestsApplied)
+			},
+			validateKubeActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "get", "get", "get", "create")
+			},
+			validateSpokeActions: func(t *testing.T, spoke *spokeFakeClients) {
+				testingcommon.AssertActions(t, spoke.operatorClient.Actions(), "get", "update")
+				klusterlet := spoke.operatorClient.Actions()[1].(clienttesting.UpdateAction).GetObject().(*operatorapiv1.Klusterlet)
+				if klusterlet.Spec.ClusterName != testinghelpers.TestManagedClusterName ||
+					klusterlet.Spec.ImagePullSpec != "quay.io/open-cluster-management/registration-operator" {
+					t.Errorf("unexpected klusterlet %v", klusterlet.Spec)
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset(c.secrets...)
+			clusterClient := clusterfake.NewSimpleClientset(c.cluster)
+			clusterInformerFactory := clusterinformers.NewSharedInformerFactory(clusterClient, time.Minute*10)
+			if err := clusterInformerFactory.Cluster().V1().ManagedClusters().Informer().GetStore().Add(c.cluster); err != nil {
+				t.Fatal(err)
+			}
+
+			spoke := &spokeFakeClients{
+				kubeClient:     kubefake.NewSimpleClientset(),
+				operatorClient: operatorfake.NewSimpleClientset(c.klusterlets...),
+			}
+			ctrl := &importController{
+				kubeClient:    kubeClient,
+				clusterLister: clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+				patcher: patcher.NewPatcher[*clusterv1.ManagedCluster, clusterv1.ManagedClusterSpec, clusterv1.ManagedClusterStatus](
+					clusterClient.ClusterV1().ManagedClusters()),
+				bootstrapSecretNamespace: "open-cluster-management",
+				bootstrapSecretName:      "bootstrap",
+				newSpokeClients: func(kubeconfig []byte) (*spokeClients, error) {
+					return &spokeClients{
+						kubeClient:     spoke.kubeClient,
+						operatorClient: spoke.operatorClient,
+					}, nil
+				},
+				eventRecorder: eventstesting.NewTestingEventRecorder(t),
+			}
+			clusterClient.ClearActions()
+
+			if err := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, testinghelpers.TestManagedClusterName)); err != nil {
+				t.Errorf("unexpected err: %v", err)
+			}
+
+			c.validateClusterAction(t, clusterClient.Actions())
+			c.validateKubeActions(t, kubeClient.Actions())
+			if c.validateSpokeActions != nil {
+				c.validateSpokeActions(t, spoke)
+			}
+		})
+	}
+}
+
+type spokeFakeClients struct {
+	kubeClient     *kubefake.Clientset
+	operatorClient *operatorfake.Clientset
+}
+
+func assertImportedCondition(t *testing.T, action clienttesting.Action, status metav1.ConditionStatus, reason string) {
+	t.Helper()
+	cluster := &clusterv1.ManagedCluster{}
+	if err := json.Unmarshal(action.(clienttesting.PatchAction).GetPatch(), cluster); err != nil {
+		t.Fatal(err)
+	}
+	for _, condition := range cluster.Status.Conditions {
+		if condition.Type != ManagedClusterConditionImported {
+			continue
+		}
+		if condition.Status != status || condition.Reason != reason {
+			t.Errorf("expected condition %s/%s, but got %s/%s", status, reason, condition.Status, condition.Reason)
+		}
+		return
+	}
+	t.Errorf("expected condition %s, but got %v", ManagedClusterConditionImported, cluster.Status.Conditions)
+}
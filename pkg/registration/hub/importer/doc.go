@@ -0,0 +1,3 @@
+// Package importer contains the controller which generates the klusterlet manifests of the ManagedClusters to be
+// imported to the hub, and applies them to the managed clusters with the kubeconfigs provided on the hub.
+package importer
@@ -15,6 +15,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	rbacv1informers "k8s.io/client-go/informers/rbac/v1"
 	"k8s.io/client-go/kubernetes"
+	kevents "k8s.io/client-go/tools/events"
 	"k8s.io/klog/v2"
 
 	clientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
@@ -23,6 +24,8 @@ import (
 	v1 "open-cluster-management.io/api/cluster/v1"
 
 	"open-cluster-management.io/ocm/pkg/common/apply"
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/registration/helpers"
@@ -31,6 +34,15 @@ import (
 //go:embed manifests
 var manifestFiles embed.FS
 
+const (
+	// restoreNameLabel is added by velero on the resources restored from a backup, its value is the name of the
+	// restore.
+	restoreNameLabel = "velero.io/restore-name"
+	// restoreReconciledAnnotation records the name of the last restore reconciled on a managed cluster, so a
+	// restored cluster is only reconciled once per restore.
+	restoreReconciledAnnotation = "cluster.open-cluster-management.io/restore-reconciled"
+)
+
 var staticFiles = []string{
 	"manifests/managedcluster-clusterrole.yaml",
 	"manifests/managedcluster-clusterrolebinding.yaml",
@@ -45,6 +57,8 @@ type managedClusterController struct {
 	applier       *apply.PermissionApplier
 	patcher       patcher.Patcher[*v1.ManagedCluster, v1.ManagedClusterSpec, v1.ManagedClusterStatus]
 	eventRecorder events.Recorder
+	// krecorder emits the events regarding the managed clusters
+	krecorder kevents.EventRecorder
 }
 
 // NewManagedClusterController creates a new managed cluster controller
@@ -56,7 +70,7 @@ func NewManagedClusterController(
 	clusterRoleInformer rbacv1informers.ClusterRoleInformer,
 	rolebindingInformer rbacv1informers.RoleBindingInformer,
 	clusterRoleBindingInformer rbacv1informers.ClusterRoleBindingInformer,
-	recorder events.Recorder) factory.Controller {
+	recorder events.Recorder, krecorder kevents.EventRecorder) factory.Controller {
 	c := &managedClusterController{
 		kubeClient:    kubeClient,
 		clusterLister: clusterInformer.Lister(),
@@ -71,6 +85,7 @@ func NewManagedClusterController(
 			*v1.ManagedCluster, v1.ManagedClusterSpec, v1.ManagedClusterStatus](
 			clusterClient.ClusterV1().ManagedClusters()),
 		eventRecorder: recorder.WithComponentSuffix("managed-cluster-controller"),
+		krecorder:     krecorder,
 	}
 	return factory.New().
 		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterInformer.Informer()).
@@ -81,7 +96,7 @@ func NewManagedClusterController(
 			rolebindingInformer.Informer(),
 			clusterRoleInformer.Informer(),
 			clusterRoleBindingInformer.Informer()).
-		WithSync(c.sync).
+		WithSync(logging.WithControllerLogger("ManagedClusterController", c.sync)).
 		ToController("ManagedClusterController", recorder)
 }
 
@@ -121,7 +136,11 @@ func (c *managedClusterController) sync(ctx context.Context, syncCtx factory.Syn
 		}
 
 		// Hub cluster-admin denies the current spoke cluster, we remove its related resources and update its condition.
-		c.eventRecorder.Eventf("ManagedClusterDenied", "managed cluster %s is denied by hub cluster admin", managedClusterName)
+		c.eventRecorder.Eventf(commonhelpers.EventReasonManagedClusterDenied,
+			"managed cluster %s is denied by hub cluster admin", managedClusterName)
+		c.krecorder.Eventf(managedCluster, nil, corev1.EventTypeNormal,
+			commonhelpers.EventReasonManagedClusterDenied, commonhelpers.EventActionDeny,
+			"Managed cluster %s is denied by hub cluster admin", managedClusterName)
 
 		if err := c.removeManagedClusterResources(ctx, managedClusterName); err != nil {
 			return err
@@ -189,16 +208,67 @@ func (c *managedClusterController) sync(ctx context.Context, syncCtx factory.Syn
 	}
 
 	meta.SetStatusCondition(&newManagedCluster.Status.Conditions, acceptedCondition)
+
+	// The cluster is restored onto a new hub from a backup, its namespace and permissions are re-applied above. The
+	// status restored from the backup is stale, so the cluster is not available until the agent reconnects and
+	// renews the lease. The client cert of the agent is not validated here: it is authenticated by the apiserver of
+	// the restored hub, which rejects a cert not issued by a signer it trusts, and then the agent only reconnects
+	// with a hub reconnection signed for the cluster.
+	restoreName, restored := restoreToReconcile(managedCluster)
+	if restored {
+		meta.SetStatusCondition(&newManagedCluster.Status.Conditions, metav1.Condition{
+			Type:    v1.ManagedClusterConditionAvailable,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "ManagedClusterRestored",
+			Message: fmt.Sprintf("The managed cluster is restored by %q, waiting for the agent to reconnect", restoreName),
+		})
+	}
+
 	updated, updatedErr := c.patcher.PatchStatus(ctx, newManagedCluster, newManagedCluster.Status, managedCluster.Status)
 	if updatedErr != nil {
 		errs = append(errs, updatedErr)
 	}
 	if updated {
-		c.eventRecorder.Eventf("ManagedClusterAccepted", "managed cluster %s is accepted by hub cluster admin", managedClusterName)
+		c.eventRecorder.Eventf(commonhelpers.EventReasonManagedClusterAccepted,
+			"managed cluster %s is accepted by hub cluster admin", managedClusterName)
+	}
+	// only the transition to accepted is recorded regarding the cluster, the status is also updated when the
+	// resources of the cluster fail to be applied.
+	if updated && !meta.IsStatusConditionTrue(managedCluster.Status.Conditions, v1.ManagedClusterConditionHubAccepted) {
+		c.krecorder.Eventf(managedCluster, nil, corev1.EventTypeNormal,
+			commonhelpers.EventReasonManagedClusterAccepted, commonhelpers.EventActionAccept,
+			"Managed cluster %s is accepted by hub cluster admin", managedClusterName)
+	}
+
+	// record the restore only after the status is reset, so the cluster is reconciled again if it fails.
+	if restored && len(errs) == 0 {
+		if _, err := c.patcher.PatchLabelAnnotations(ctx, managedCluster, reconciledMeta(managedCluster, restoreName),
+			managedCluster.ObjectMeta); err != nil {
+			errs = append(errs, err)
+		}
 	}
 	return operatorhelpers.NewMultiLineAggregate(errs)
 }
 
+// restoreToReconcile returns the name of the restore if the managed cluster is restored from a backup and the
+// restore is not reconciled yet.
+func restoreToReconcile(managedCluster *v1.ManagedCluster) (string, bool) {
+	restoreName := managedCluster.Labels[restoreNameLabel]
+	if len(restoreName) == 0 {
+		return "", false
+	}
+	return restoreName, managedCluster.Annotations[restoreReconciledAnnotation] != restoreName
+}
+
+func reconciledMeta(managedCluster *v1.ManagedCluster, restoreName string) metav1.ObjectMeta {
+	newMeta := *managedCluster.ObjectMeta.DeepCopy()
+	if newMeta.Annotations == nil {
+		newMeta.Annotations = map[string]string{}
+	}
+	newMeta.Annotations[restoreReconciledAnnotation] = restoreName
+	return newMeta
+}
+
 func (c *managedClusterController) removeManagedClusterResources(ctx context.Context, managedClusterName string) error {
 	var errs []error
 	// Clean up managed cluster manifests
@@ -12,12 +12,14 @@ import (
 	kubeinformers "k8s.io/client-go/informers"
 	kubefake "k8s.io/client-go/kubernetes/fake"
 	clienttesting "k8s.io/client-go/testing"
+	kevents "k8s.io/client-go/tools/events"
 
 	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
 	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
 	v1 "open-cluster-management.io/api/cluster/v1"
 
 	"open-cluster-management.io/ocm/pkg/common/apply"
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
 	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
@@ -28,6 +30,7 @@ func TestSyncManagedCluster(t *testing.T) {
 		name            string
 		startingObjects []runtime.Object
 		validateActions func(t *testing.T, actions []clienttesting.Action)
+		expectedEvents  []string
 	}{
 		{
 			name:            "sync a deleted spoke cluster",
@@ -69,6 +72,7 @@ func TestSyncManagedCluster(t *testing.T) {
 				}
 				testingcommon.AssertCondition(t, managedCluster.Status.Conditions, expectedCondition)
 			},
+			expectedEvents: []string{commonhelpers.EventReasonManagedClusterAccepted},
 		},
 		{
 			name:            "sync an accepted spoke cluster",
@@ -77,6 +81,48 @@ func TestSyncManagedCluster(t *testing.T) {
 				testingcommon.AssertNoActions(t, actions)
 			},
 		},
+		{
+			name: "sync a restored spoke cluster",
+			startingObjects: []runtime.Object{func() *v1.ManagedCluster {
+				cluster := testinghelpers.NewAvailableManagedCluster()
+				cluster.Labels = map[string]string{restoreNameLabel: "restore1"}
+				return cluster
+			}()},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				expectedCondition := metav1.Condition{
+					Type:    v1.ManagedClusterConditionAvailable,
+					Status:  metav1.ConditionUnknown,
+					Reason:  "ManagedClusterRestored",
+					Message: `The managed cluster is restored by "restore1", waiting for the agent to reconnect`,
+				}
+				testingcommon.AssertActions(t, actions, "patch", "patch")
+				managedCluster := &v1.ManagedCluster{}
+				if err := json.Unmarshal(actions[0].(clienttesting.PatchAction).GetPatch(), managedCluster); err != nil {
+					t.Fatal(err)
+				}
+				testingcommon.AssertCondition(t, managedCluster.Status.Conditions, expectedCondition)
+
+				managedCluster = &v1.ManagedCluster{}
+				if err := json.Unmarshal(actions[1].(clienttesting.PatchAction).GetPatch(), managedCluster); err != nil {
+					t.Fatal(err)
+				}
+				if managedCluster.Annotations[restoreReconciledAnnotation] != "restore1" {
+					t.Errorf("expected the restore is reconciled, but got %v", managedCluster.Annotations)
+				}
+			},
+		},
+		{
+			name: "sync a reconciled restored spoke cluster",
+			startingObjects: []runtime.Object{func() *v1.ManagedCluster {
+				cluster := testinghelpers.NewAcceptedManagedCluster()
+				cluster.Labels = map[string]string{restoreNameLabel: "restore1"}
+				cluster.Annotations = map[string]string{restoreReconciledAnnotation: "restore1"}
+				return cluster
+			}()},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
 		{
 			name:            "deny an accepted spoke cluster",
 			startingObjects: []runtime.Object{testinghelpers.NewDeniedManagedCluster()},
@@ -96,6 +142,7 @@ func TestSyncManagedCluster(t *testing.T) {
 				}
 				testingcommon.AssertCondition(t, managedCluster.Status.Conditions, expectedCondition)
 			},
+			expectedEvents: []string{commonhelpers.EventReasonManagedClusterDenied},
 		},
 		{
 			name:            "delete a spoke cluster",
@@ -126,6 +173,7 @@ func TestSyncManagedCluster(t *testing.T) {
 				}
 			}
 
+			krecorder := kevents.NewFakeRecorder(10)
 			ctrl := managedClusterController{
 				kubeClient,
 				clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
@@ -137,13 +185,15 @@ func TestSyncManagedCluster(t *testing.T) {
 					kubeInformer.Rbac().V1().ClusterRoleBindings().Lister(),
 				),
 				patcher.NewPatcher[*v1.ManagedCluster, v1.ManagedClusterSpec, v1.ManagedClusterStatus](clusterClient.ClusterV1().ManagedClusters()),
-				eventstesting.NewTestingEventRecorder(t)}
+				eventstesting.NewTestingEventRecorder(t),
+				krecorder}
 			syncErr := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, testinghelpers.TestManagedClusterName))
 			if syncErr != nil {
 				t.Errorf("unexpected err: %v", syncErr)
 			}
 
 			c.validateActions(t, clusterClient.Actions())
+			testingcommon.AssertEventReasons(t, krecorder, c.expectedEvents...)
 		})
 	}
 }
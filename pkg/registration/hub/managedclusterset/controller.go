@@ -10,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
@@ -24,6 +25,8 @@ import (
 	v1 "open-cluster-management.io/api/cluster/v1"
 	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 )
@@ -32,6 +35,9 @@ const (
 	// TODO move these to api repos
 	ReasonClusterSelected   = "ClustersSelected"
 	ReasonNoClusterMatchced = "NoClusterMatched"
+
+	ReasonNestedClusterSetsValid = "NestedClusterSetsValid"
+	ReasonNestedClusterSetsCycle = "NestedClusterSetsCycle"
 )
 
 // managedClusterSetController reconciles instances of ManagedClusterSet on the hub.
@@ -116,9 +122,9 @@ func NewManagedClusterSetController(
 
 	return factory.New().
 		WithSyncContext(syncCtx).
-		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterSetInformer.Informer()).
+		WithInformersQueueKeysFunc(c.clusterSetQueueKeys, clusterSetInformer.Informer()).
 		WithBareInformers(clusterInformer.Informer()).
-		WithSync(c.sync).
+		WithSync(logging.WithControllerLogger("ManagedClusterSetController", c.sync)).
 		ToController("ManagedClusterSetController", recorder)
 }
 
@@ -153,7 +159,7 @@ func (c *managedClusterSetController) sync(ctx context.Context, syncCtx factory.
 // syncClusterSet syncs a particular cluster set
 func (c *managedClusterSetController) syncClusterSet(ctx context.Context, originalClusterSet *clusterv1beta2.ManagedClusterSet) error {
 	clusterSet := originalClusterSet.DeepCopy()
-	clusters, err := clusterv1beta2.GetClustersFromClusterSet(clusterSet, c.clusterLister)
+	clusters, err := commonhelpers.GetClustersFromNestedClusterSet(clusterSet, c.clusterLister, c.clusterSetLister)
 	if err != nil {
 		return err
 	}
@@ -173,6 +179,24 @@ func (c *managedClusterSetController) syncClusterSet(ctx context.Context, origin
 	}
 	meta.SetStatusCondition(&clusterSet.Status.Conditions, emptyCondition)
 
+	// the nested sets in a cycle are still members of the set, the condition only reports the cycle to the admin.
+	if len(commonhelpers.GetNestedClusterSetNames(clusterSet)) == 0 {
+		meta.RemoveStatusCondition(&clusterSet.Status.Conditions, commonhelpers.ClusterSetConditionNestedClusterSetsValid)
+	} else {
+		nestedCondition := metav1.Condition{
+			Type:    commonhelpers.ClusterSetConditionNestedClusterSetsValid,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonNestedClusterSetsValid,
+			Message: "The nested ManagedClusterSets are valid",
+		}
+		if err := commonhelpers.ValidateNestedClusterSets(clusterSet, c.clusterSetLister); err != nil {
+			nestedCondition.Status = metav1.ConditionFalse
+			nestedCondition.Reason = ReasonNestedClusterSetsCycle
+			nestedCondition.Message = err.Error()
+		}
+		meta.SetStatusCondition(&clusterSet.Status.Conditions, nestedCondition)
+	}
+
 	_, err = c.patcher.PatchStatus(ctx, clusterSet, clusterSet.Status, originalClusterSet.Status)
 	if err != nil {
 		return fmt.Errorf("failed to update status of ManagedClusterSet %q: %w", clusterSet.Name, err)
@@ -181,6 +205,21 @@ func (c *managedClusterSetController) syncClusterSet(ctx context.Context, origin
 	return nil
 }
 
+// clusterSetQueueKeys returns the clusterset and the clustersets it is nested in.
+func (c *managedClusterSetController) clusterSetQueueKeys(obj runtime.Object) []string {
+	names := queue.QueueKeyByMetaName(obj)
+	keys := append([]string{}, names...)
+	for _, key := range names {
+		ancestors, err := commonhelpers.GetAncestorClusterSetNames(key, c.clusterSetLister)
+		if err != nil {
+			utilruntime.HandleError(fmt.Errorf("error to get the ancestors of clusterset %s. Error %v", key, err))
+			continue
+		}
+		keys = append(keys, ancestors...)
+	}
+	return keys
+}
+
 // enqueueClusterClusterSet enqueue a cluster related clusterset
 func (c *managedClusterSetController) enqueueClusterClusterSet(cluster *v1.ManagedCluster) {
 	clusterSets, err := clusterv1beta2.GetClusterSetsOfCluster(cluster, c.clusterSetLister)
@@ -189,7 +228,7 @@ func (c *managedClusterSetController) enqueueClusterClusterSet(cluster *v1.Manag
 		return
 	}
 	for _, clusterSet := range clusterSets {
-		c.queue.Add(clusterSet.Name)
+		c.enqueueClusterSetWithAncestors(clusterSet.Name)
 	}
 }
 
@@ -209,7 +248,21 @@ func (c *managedClusterSetController) enqueueUpdateClusterClusterSet(oldCluster,
 	diffClusterSets := getDiffClusterSetsNames(oldClusterSets, newClusterSets)
 
 	for diffSet := range diffClusterSets {
-		c.queue.Add(diffSet)
+		c.enqueueClusterSetWithAncestors(diffSet)
+	}
+}
+
+// enqueueClusterSetWithAncestors enqueues the clusterset and the clustersets it is nested in, since the effective
+// members of the ancestors are changed with the clusterset.
+func (c *managedClusterSetController) enqueueClusterSetWithAncestors(clusterSetName string) {
+	c.queue.Add(clusterSetName)
+	ancestors, err := commonhelpers.GetAncestorClusterSetNames(clusterSetName, c.clusterSetLister)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("error to get the ancestors of clusterset %s. Error %v", clusterSetName, err))
+		return
+	}
+	for _, ancestor := range ancestors {
+		c.queue.Add(ancestor)
 	}
 }
 
@@ -16,6 +16,7 @@ import (
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
 )
@@ -24,8 +25,10 @@ func TestSyncClusterSet(t *testing.T) {
 	cases := []struct {
 		name               string
 		existingClusterSet *clusterv1beta2.ManagedClusterSet
+		nestedClusterSets  []*clusterv1beta2.ManagedClusterSet
 		existingClusters   []*clusterv1.ManagedCluster
 		expectCondition    metav1.Condition
+		expectConditions   []metav1.Condition
 		expectErr          bool
 	}{
 		{
@@ -188,6 +191,83 @@ func TestSyncClusterSet(t *testing.T) {
 				Message: "No ManagedCluster selected",
 			},
 		},
+		{
+			name: "sync a clusterset with nested clustersets",
+			existingClusterSet: &clusterv1beta2.ManagedClusterSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "global",
+					Annotations: map[string]string{
+						commonhelpers.NestedClusterSetsAnnotationKey: "region1",
+					},
+				},
+			},
+			nestedClusterSets: []*clusterv1beta2.ManagedClusterSet{
+				newNestedClusterSet("region1", "site1,site2"),
+				newNestedClusterSet("site1", ""),
+				newNestedClusterSet("site2", ""),
+			},
+			existingClusters: []*clusterv1.ManagedCluster{
+				newManagedCluster("cluster1", map[string]string{
+					clusterv1beta2.ClusterSetLabel: "site1",
+				}),
+				newManagedCluster("cluster2", map[string]string{
+					clusterv1beta2.ClusterSetLabel: "site2",
+				}),
+				newManagedCluster("cluster3", map[string]string{
+					clusterv1beta2.ClusterSetLabel: "region1",
+				}),
+				newManagedCluster("cluster4", map[string]string{
+					clusterv1beta2.ClusterSetLabel: "others",
+				}),
+			},
+			expectCondition: metav1.Condition{
+				Type:    clusterv1beta2.ManagedClusterSetConditionEmpty,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonClusterSelected,
+				Message: "3 ManagedClusters selected",
+			},
+			expectConditions: []metav1.Condition{
+				{
+					Type:    commonhelpers.ClusterSetConditionNestedClusterSetsValid,
+					Status:  metav1.ConditionTrue,
+					Reason:  ReasonNestedClusterSetsValid,
+					Message: "The nested ManagedClusterSets are valid",
+				},
+			},
+		},
+		{
+			name: "sync a clusterset with nested clustersets in a cycle",
+			existingClusterSet: &clusterv1beta2.ManagedClusterSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "global",
+					Annotations: map[string]string{
+						commonhelpers.NestedClusterSetsAnnotationKey: "region1",
+					},
+				},
+			},
+			nestedClusterSets: []*clusterv1beta2.ManagedClusterSet{
+				newNestedClusterSet("region1", "global"),
+			},
+			existingClusters: []*clusterv1.ManagedCluster{
+				newManagedCluster("cluster1", map[string]string{
+					clusterv1beta2.ClusterSetLabel: "region1",
+				}),
+			},
+			expectCondition: metav1.Condition{
+				Type:    clusterv1beta2.ManagedClusterSetConditionEmpty,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonClusterSelected,
+				Message: "1 ManagedClusters selected",
+			},
+			expectConditions: []metav1.Condition{
+				{
+					Type:    commonhelpers.ClusterSetConditionNestedClusterSetsValid,
+					Status:  metav1.ConditionFalse,
+					Reason:  ReasonNestedClusterSetsCycle,
+					Message: "the nested ManagedClusterSets form a cycle: global -> region1 -> global",
+				},
+			},
+		},
 		{
 			name: "ignore any other clusterset",
 			existingClusterSet: &clusterv1beta2.ManagedClusterSet{
@@ -213,6 +293,9 @@ func TestSyncClusterSet(t *testing.T) {
 			if c.existingClusterSet != nil {
 				objects = append(objects, c.existingClusterSet)
 			}
+			for _, clusterSet := range c.nestedClusterSets {
+				objects = append(objects, clusterSet)
+			}
 
 			clusterClient := clusterfake.NewSimpleClientset(objects...)
 
@@ -229,6 +312,12 @@ func TestSyncClusterSet(t *testing.T) {
 					t.Errorf("Failed to add clusterset: %v, error: %v", c.existingClusterSet.Name, err)
 				}
 			}
+			for _, clusterSet := range c.nestedClusterSets {
+				err := informerFactory.Cluster().V1beta2().ManagedClusterSets().Informer().GetStore().Add(clusterSet)
+				if err != nil {
+					t.Errorf("Failed to add clusterset: %v, error: %v", clusterSet.Name, err)
+				}
+			}
 
 			ctrl := managedClusterSetController{
 				patcher: patcher.NewPatcher[
@@ -257,6 +346,11 @@ func TestSyncClusterSet(t *testing.T) {
 			if !hasCondition(updatedSet.Status.Conditions, c.expectCondition) {
 				t.Errorf("expected condition:%v. is not found: %v", c.expectCondition, updatedSet.Status.Conditions)
 			}
+			for _, condition := range c.expectConditions {
+				if !hasCondition(updatedSet.Status.Conditions, condition) {
+					t.Errorf("expected condition:%v. is not found: %v", condition, updatedSet.Status.Conditions)
+				}
+			}
 		})
 	}
 }
@@ -417,6 +511,16 @@ func newManagedClusterSet(name string) *clusterv1beta2.ManagedClusterSet {
 	return clusterSet
 }
 
+func newNestedClusterSet(name, nestedClusterSets string) *clusterv1beta2.ManagedClusterSet {
+	clusterSet := newManagedClusterSet(name)
+	if len(nestedClusterSets) > 0 {
+		clusterSet.Annotations = map[string]string{
+			commonhelpers.NestedClusterSetsAnnotationKey: nestedClusterSets,
+		}
+	}
+	return clusterSet
+}
+
 func hasCondition(conditions []metav1.Condition, expectCondition metav1.Condition) bool {
 	for _, condition := range conditions {
 		if condition.Type != expectCondition.Type {
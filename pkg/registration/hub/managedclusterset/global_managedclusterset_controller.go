@@ -17,6 +17,7 @@ import (
 	clusterlisterv1beta2 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta2"
 	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 )
 
@@ -59,7 +60,7 @@ func NewGlobalManagedClusterSetController(
 			queue.FilterByNames(GlobalManagedClusterSetName),
 			clusterSetInformer.Informer(),
 		).
-		WithSync(c.sync).
+		WithSync(logging.WithControllerLogger("GlobalManagedClusterSetController", c.sync)).
 		// use ResyncEvery to make sure:
 		// 1. create the global clusterset once controller is launched
 		// 2. the global clusterset be recreated once it is deleted for some reason
@@ -3,13 +3,17 @@ package managedclustersetbinding
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
@@ -19,16 +23,23 @@ import (
 	clusterlisterv1beta2 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta2"
 	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 )
 
 const (
 	byClusterSet = "by-clusterset"
+
+	// resyncInterval is the interval to check the bind permission of all the bindings again, since the
+	// change of the RBAC does not trigger the reconciliation.
+	resyncInterval = 5 * time.Minute
 )
 
 // managedClusterSetController reconciles instances of ManagedClusterSet on the hub.
 type managedClusterSetBindingController struct {
+	kubeClient                kubernetes.Interface
 	clusterClient             clientset.Interface
 	clusterSetBindingLister   clusterlisterv1beta2.ManagedClusterSetBindingLister
 	clusterSetLister          clusterlisterv1beta2.ManagedClusterSetLister
@@ -38,6 +49,7 @@ type managedClusterSetBindingController struct {
 }
 
 func NewManagedClusterSetBindingController(
+	kubeClient kubernetes.Interface,
 	clusterClient clientset.Interface,
 	clusterSetInformer clusterinformerv1beta2.ManagedClusterSetInformer,
 	clusterSetBindingInformer clusterinformerv1beta2.ManagedClusterSetBindingInformer,
@@ -55,6 +67,7 @@ func NewManagedClusterSetBindingController(
 	}
 
 	c := &managedClusterSetBindingController{
+		kubeClient:                kubeClient,
 		clusterClient:             clusterClient,
 		clusterSetLister:          clusterSetInformer.Lister(),
 		clusterSetBindingLister:   clusterSetBindingInformer.Lister(),
@@ -80,8 +93,9 @@ func NewManagedClusterSetBindingController(
 		WithSyncContext(syncCtx).
 		WithInformersQueueKeysFunc(queue.QueueKeyByMetaNamespaceName, clusterSetBindingInformer.Informer()).
 		WithBareInformers(clusterSetInformer.Informer()).
-		WithSync(c.sync).
-		ToController("ManagedClusterSetController", recorder)
+		WithSync(logging.WithControllerLogger("ManagedClusterSetBindingController", c.sync)).
+		ResyncEvery(resyncInterval).
+		ToController("ManagedClusterSetBindingController", recorder)
 }
 
 func indexByClusterset(obj interface{}) ([]string, error) {
@@ -138,6 +152,10 @@ func (c *managedClusterSetBindingController) sync(ctx context.Context, syncCtx f
 		return nil
 	}
 
+	if key == factory.DefaultQueueKey {
+		return c.enqueueAllBindings()
+	}
+
 	logger.V(4).Info("Reconciling ManagedClusterSetBinding", "key", key)
 
 	bindingNamespace, bindingName, err := cache.SplitMetaNamespaceKey(key)
@@ -161,7 +179,7 @@ func (c *managedClusterSetBindingController) sync(ctx context.Context, syncCtx f
 		return err
 	}
 
-	_, err = c.clusterSetLister.Get(binding.Spec.ClusterSet)
+	clusterSet, err := c.clusterSetLister.Get(binding.Spec.ClusterSet)
 
 	bindingCopy := binding.DeepCopy()
 	switch {
@@ -185,9 +203,93 @@ func (c *managedClusterSetBindingController) sync(ctx context.Context, syncCtx f
 		Reason: "ClusterSetBound",
 	})
 
+	// reflect the empty condition of the clusterset, so the users without the permission to read the
+	// clusterset know why no cluster is selected by their placements.
+	if emptyCondition := meta.FindStatusCondition(
+		clusterSet.Status.Conditions, clusterv1beta2.ManagedClusterSetConditionEmpty); emptyCondition != nil {
+		meta.SetStatusCondition(&bindingCopy.Status.Conditions, metav1.Condition{
+			Type:    commonhelpers.ClusterSetBindingConditionClusterSetEmpty,
+			Status:  emptyCondition.Status,
+			Reason:  emptyCondition.Reason,
+			Message: emptyCondition.Message,
+		})
+	}
+
+	permissionCondition, err := c.getPermissionDeniedCondition(ctx, binding)
+	if err != nil {
+		return err
+	}
+	if permissionCondition != nil {
+		meta.SetStatusCondition(&bindingCopy.Status.Conditions, *permissionCondition)
+	}
+
 	if _, err := patcher.PatchStatus(ctx, bindingCopy, bindingCopy.Status, binding.Status); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// getPermissionDeniedCondition checks if the user who created the binding is still allowed to bind the
+// clusterset. It returns nil if the creator of the binding is not recorded.
+func (c *managedClusterSetBindingController) getPermissionDeniedCondition(
+	ctx context.Context, binding *clusterv1beta2.ManagedClusterSetBinding) (*metav1.Condition, error) {
+	userInfo, err := commonhelpers.GetClusterSetBindingBoundBy(binding)
+	if err != nil {
+		return &metav1.Condition{
+			Type:    commonhelpers.ClusterSetBindingConditionPermissionDenied,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "InvalidBoundBy",
+			Message: err.Error(),
+		}, nil
+	}
+	if userInfo == nil {
+		return nil, nil
+	}
+
+	sar, err := c.kubeClient.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   userInfo.Username,
+			UID:    userInfo.UID,
+			Groups: userInfo.Groups,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Group:       clusterv1beta2.GroupName,
+				Resource:    "managedclustersets",
+				Subresource: "bind",
+				Verb:        "create",
+				Name:        binding.Spec.ClusterSet,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if !sar.Status.Allowed {
+		return &metav1.Condition{
+			Type:   commonhelpers.ClusterSetBindingConditionPermissionDenied,
+			Status: metav1.ConditionTrue,
+			Reason: "BindPermissionRevoked",
+			Message: fmt.Sprintf("user %q is not allowed to bind cluster set %q anymore",
+				userInfo.Username, binding.Spec.ClusterSet),
+		}, nil
+	}
+	return &metav1.Condition{
+		Type:    commonhelpers.ClusterSetBindingConditionPermissionDenied,
+		Status:  metav1.ConditionFalse,
+		Reason:  "BindPermissionGranted",
+		Message: fmt.Sprintf("user %q is allowed to bind cluster set %q", userInfo.Username, binding.Spec.ClusterSet),
+	}, nil
+}
+
+func (c *managedClusterSetBindingController) enqueueAllBindings() error {
+	bindings, err := c.clusterSetBindingLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, binding := range bindings {
+		key, _ := cache.MetaNamespaceKeyFunc(binding)
+		c.queue.Add(key)
+	}
+	return nil
+}
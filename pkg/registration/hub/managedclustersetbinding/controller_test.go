@@ -7,9 +7,12 @@ import (
 	"time"
 
 	"github.com/openshift/library-go/pkg/operator/events/eventstesting"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 	clienttesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/cache"
 
@@ -17,6 +20,7 @@ import (
 	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
 	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
 )
 
@@ -25,6 +29,7 @@ func TestSync(t *testing.T) {
 		name              string
 		clusterSets       []runtime.Object
 		clusterSetBinding *clusterv1beta2.ManagedClusterSetBinding
+		allowed           bool
 		validateActions   func(t *testing.T, actions []clienttesting.Action)
 	}{
 		{
@@ -87,10 +92,87 @@ func TestSync(t *testing.T) {
 			}(),
 			validateActions: testingcommon.AssertNoActions,
 		},
+		{
+			name: "empty clusterset",
+			clusterSets: []runtime.Object{func() *clusterv1beta2.ManagedClusterSet {
+				clusterSet := newManagedClusterSet("test")
+				meta.SetStatusCondition(&clusterSet.Status.Conditions, metav1.Condition{
+					Type:    clusterv1beta2.ManagedClusterSetConditionEmpty,
+					Status:  metav1.ConditionTrue,
+					Reason:  "NoClusterMatched",
+					Message: "No ManagedCluster selected",
+				})
+				return clusterSet
+			}()},
+			clusterSetBinding: newManagedClusterSetBinding("test", "testns"),
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch")
+				binding := &clusterv1beta2.ManagedClusterSetBinding{}
+				if err := json.Unmarshal(actions[0].(clienttesting.PatchActionImpl).Patch, binding); err != nil {
+					t.Fatal(err)
+				}
+				testingcommon.AssertCondition(t, binding.Status.Conditions, metav1.Condition{
+					Type:    commonhelpers.ClusterSetBindingConditionClusterSetEmpty,
+					Status:  metav1.ConditionTrue,
+					Reason:  "NoClusterMatched",
+					Message: "No ManagedCluster selected",
+				})
+			},
+		},
+		{
+			name:              "bind permission revoked",
+			clusterSets:       []runtime.Object{newManagedClusterSet("test")},
+			clusterSetBinding: newBoundManagedClusterSetBinding(t, "test", "testns"),
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch")
+				binding := &clusterv1beta2.ManagedClusterSetBinding{}
+				if err := json.Unmarshal(actions[0].(clienttesting.PatchActionImpl).Patch, binding); err != nil {
+					t.Fatal(err)
+				}
+				testingcommon.AssertCondition(t, binding.Status.Conditions, metav1.Condition{
+					Type:    commonhelpers.ClusterSetBindingConditionPermissionDenied,
+					Status:  metav1.ConditionTrue,
+					Reason:  "BindPermissionRevoked",
+					Message: `user "user1" is not allowed to bind cluster set "test" anymore`,
+				})
+			},
+		},
+		{
+			name:              "bind permission granted",
+			clusterSets:       []runtime.Object{newManagedClusterSet("test")},
+			clusterSetBinding: newBoundManagedClusterSetBinding(t, "test", "testns"),
+			allowed:           true,
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch")
+				binding := &clusterv1beta2.ManagedClusterSetBinding{}
+				if err := json.Unmarshal(actions[0].(clienttesting.PatchActionImpl).Patch, binding); err != nil {
+					t.Fatal(err)
+				}
+				testingcommon.AssertCondition(t, binding.Status.Conditions, metav1.Condition{
+					Type:    commonhelpers.ClusterSetBindingConditionPermissionDenied,
+					Status:  metav1.ConditionFalse,
+					Reason:  "BindPermissionGranted",
+					Message: `user "user1" is allowed to bind cluster set "test"`,
+				})
+			},
+		},
 	}
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset()
+			kubeClient.PrependReactor(
+				"create",
+				"subjectaccessreviews",
+				func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, &authorizationv1.SubjectAccessReview{
+						Status: authorizationv1.SubjectAccessReviewStatus{
+							Allowed: c.allowed,
+						},
+					}, nil
+				},
+			)
+
 			var objects []runtime.Object
 			objects = append(objects, c.clusterSets...)
 			objects = append(objects, c.clusterSetBinding)
@@ -107,6 +189,7 @@ func TestSync(t *testing.T) {
 			}
 
 			ctrl := managedClusterSetBindingController{
+				kubeClient:              kubeClient,
 				clusterClient:           clusterClient,
 				clusterSetBindingLister: informerFactory.Cluster().V1beta2().ManagedClusterSetBindings().Lister(),
 				clusterSetLister:        informerFactory.Cluster().V1beta2().ManagedClusterSets().Lister(),
@@ -211,3 +294,11 @@ func newManagedClusterSetBinding(name, namespace string) *clusterv1beta2.Managed
 		},
 	}
 }
+
+func newBoundManagedClusterSetBinding(t *testing.T, clusterSetName, namespace string) *clusterv1beta2.ManagedClusterSetBinding {
+	binding := newManagedClusterSetBinding(clusterSetName, namespace)
+	if err := commonhelpers.SetClusterSetBindingBoundBy(binding, authenticationv1.UserInfo{Username: "user1"}); err != nil {
+		t.Fatal(err)
+	}
+	return binding
+}
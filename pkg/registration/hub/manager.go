@@ -11,8 +11,11 @@ import (
 	certv1 "k8s.io/api/certificates/v1"
 	certv1beta1 "k8s.io/api/certificates/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/dynamic"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
 	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
@@ -25,33 +28,123 @@ import (
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	ocmfeature "open-cluster-management.io/api/feature"
 
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
 	"open-cluster-management.io/ocm/pkg/features"
 	"open-cluster-management.io/ocm/pkg/registration/helpers"
 	"open-cluster-management.io/ocm/pkg/registration/hub/addon"
+	"open-cluster-management.io/ocm/pkg/registration/hub/aggregation"
+	"open-cluster-management.io/ocm/pkg/registration/hub/bootstrapkubeconfig"
+	"open-cluster-management.io/ocm/pkg/registration/hub/claimlabel"
+	"open-cluster-management.io/ocm/pkg/registration/hub/clusterprofile"
 	"open-cluster-management.io/ocm/pkg/registration/hub/clusterrole"
 	"open-cluster-management.io/ocm/pkg/registration/hub/csr"
+	"open-cluster-management.io/ocm/pkg/registration/hub/importer"
 	"open-cluster-management.io/ocm/pkg/registration/hub/lease"
 	"open-cluster-management.io/ocm/pkg/registration/hub/managedcluster"
 	"open-cluster-management.io/ocm/pkg/registration/hub/managedclusterset"
 	"open-cluster-management.io/ocm/pkg/registration/hub/managedclustersetbinding"
+	"open-cluster-management.io/ocm/pkg/registration/hub/migration"
+	"open-cluster-management.io/ocm/pkg/registration/hub/pendingapproval"
 	"open-cluster-management.io/ocm/pkg/registration/hub/rbacfinalizerdeletion"
 	"open-cluster-management.io/ocm/pkg/registration/hub/taint"
+	"open-cluster-management.io/ocm/pkg/registration/hub/workauthor"
 )
 
 // HubManagerOptions holds configuration for hub manager controller
 type HubManagerOptions struct {
 	ClusterAutoApprovalUsers []string
+	// EnableStatusAggregation enables the controller summarizing the ManagedClusters and the ManifestWorks of the hub
+	// into cluster claims, which are reported to the parent hub when the hub is also a managed cluster.
+	EnableStatusAggregation bool
+	// ClusterProfileNamespace is the namespace of the ClusterProfiles mirrored from the ManagedClusters, the
+	// ClusterProfiles are not maintained if it is empty.
+	ClusterProfileNamespace string
+	// ClusterApprovalExpiration is how long a ManagedCluster which has never been accepted is kept, the cluster and
+	// its CSRs are deleted afterwards. The clusters never expire if it is 0.
+	ClusterApprovalExpiration time.Duration
+	// CSRApprovalSigners are the signers of the managed cluster csrs approved by the hub besides the
+	// kubernetes.io/kube-apiserver-client, the certificates are issued by the signing controllers of the signers.
+	CSRApprovalSigners []string
+	// ImportBootstrapKubeconfigSecret is the namespace/name of the secret holding the bootstrap kubeconfig of the hub
+	// in the klusterlet manifests of the imported ManagedClusters, the clusters are not imported if it is empty.
+	ImportBootstrapKubeconfigSecret string
+	// BootstrapTokenServiceAccount is the namespace/name of the service account whose tokens authenticate the
+	// bootstrap kubeconfigs rotated on the joined ManagedClusters, the bootstrap kubeconfigs are not rotated if it is
+	// empty. BootstrapHubAPIServer is the URL of the hub apiserver in the bootstrap kubeconfigs, and
+	// BootstrapTokenExpiration is the lifetime of the tokens.
+	BootstrapTokenServiceAccount string
+	BootstrapHubAPIServer        string
+	BootstrapTokenExpiration     time.Duration
+	// ClusterClaimLabelRulesConfigMap is the namespace/name of the configmap holding the rules projecting the cluster
+	// claims into the labels of the ManagedClusters, the claims are not projected if it is empty.
+	ClusterClaimLabelRulesConfigMap string
+	// EnableClusterMigration enables the controller migrating the ManagedClusters annotated with a target hub to the
+	// target hub.
+	EnableClusterMigration bool
+	// ClusterMigrationSecretNamespace is the namespace of the migration secrets named by the migration annotation of
+	// the ManagedClusters.
+	ClusterMigrationSecretNamespace string
+	// EnablePendingApproval enables the controller maintaining the PendingApproval condition of the ManagedClusters.
+	// The controller also runs if ClusterApprovalExpiration is set, since it deletes the expired clusters.
+	EnablePendingApproval bool
+	// EnableWorkAuthorRoles enables the controller provisioning the roles which allow the groups in the
+	// work-author-groups annotation of the ManagedClusterSetBindings to author the ManifestWorks of the bound clusters.
+	EnableWorkAuthorRoles bool
 }
 
 // NewHubManagerOptions returns a HubManagerOptions
 func NewHubManagerOptions() *HubManagerOptions {
-	return &HubManagerOptions{}
+	return &HubManagerOptions{
+		BootstrapTokenExpiration:        30 * 24 * time.Hour,
+		ClusterMigrationSecretNamespace: "open-cluster-management-hub",
+	}
 }
 
 // AddFlags registers flags for manager
 func (m *HubManagerOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringSliceVar(&m.ClusterAutoApprovalUsers, "cluster-auto-approval-users", m.ClusterAutoApprovalUsers,
 		"A bootstrap user list whose cluster registration requests can be automatically approved.")
+	fs.BoolVar(&m.EnableStatusAggregation, "enable-status-aggregation", m.EnableStatusAggregation,
+		"Summarize the health of the ManagedClusters and the ManifestWorks into the cluster claims of the hub cluster, "+
+			"so they are reported to the parent hub when the hub is also a managed cluster.")
+	fs.StringVar(&m.ClusterProfileNamespace, "cluster-profile-namespace", m.ClusterProfileNamespace,
+		"The namespace of the ClusterProfiles of the cluster inventory API mirrored from the ManagedClusters. "+
+			"The ClusterProfiles are not maintained if it is empty.")
+	fs.DurationVar(&m.ClusterApprovalExpiration, "cluster-approval-expiration", m.ClusterApprovalExpiration,
+		"How long a ManagedCluster waits for the hub to accept it. The cluster and its CSRs are deleted if the cluster "+
+			"is not accepted in time. The clusters wait forever if it is 0.")
+	fs.StringSliceVar(&m.CSRApprovalSigners, "csr-approval-signers", m.CSRApprovalSigners,
+		"The signers of the CSRs of the managed clusters approved by the hub besides kubernetes.io/kube-apiserver-client, "+
+			"e.g. example.com/corporate-ca. The certificates are issued by the signing controllers of the signers, and "+
+			"the CSRs of other signers are left to be approved by others.")
+	fs.StringVar(&m.ImportBootstrapKubeconfigSecret, "import-bootstrap-kubeconfig-secret", m.ImportBootstrapKubeconfigSecret,
+		"The namespace/name of the secret holding the bootstrap kubeconfig of the hub in the klusterlet manifests of the "+
+			"ManagedClusters to be imported. The ManagedClusters annotated with cluster.open-cluster-management.io/import "+
+			"are imported only if it is set.")
+	fs.StringVar(&m.BootstrapTokenServiceAccount, "bootstrap-token-service-account", m.BootstrapTokenServiceAccount,
+		"The namespace/name of the service account on the hub whose tokens authenticate the bootstrap kubeconfigs "+
+			"delivered to the joined managed clusters by ManifestWorks. The bootstrap kubeconfigs are rotated before the "+
+			"tokens expire, so the klusterlets are always able to rebootstrap. They are not delivered if it is empty.")
+	fs.StringVar(&m.BootstrapHubAPIServer, "bootstrap-hub-apiserver", m.BootstrapHubAPIServer,
+		"The URL of the hub apiserver reachable from the managed clusters in the rotated bootstrap kubeconfigs.")
+	fs.DurationVar(&m.BootstrapTokenExpiration, "bootstrap-token-expiration", m.BootstrapTokenExpiration,
+		"The lifetime of the tokens in the rotated bootstrap kubeconfigs.")
+	fs.StringVar(&m.ClusterClaimLabelRulesConfigMap, "cluster-claim-label-rules-configmap", m.ClusterClaimLabelRulesConfigMap,
+		"The namespace/name of the configmap holding the rules which transform the cluster claims into the labels of the "+
+			"ManagedClusters, e.g. mapping the spellings of the platforms to a canonical label value. The claims are not "+
+			"projected into the labels if it is empty.")
+	fs.BoolVar(&m.EnableClusterMigration, "enable-cluster-migration", m.EnableClusterMigration,
+		"Migrate the ManagedClusters annotated with cluster.open-cluster-management.io/migrate-to to the target hub. The "+
+			"klusterlets are rebootstrapped against the target hub with the bootstrap kubeconfig in the annotated secret.")
+	fs.StringVar(&m.ClusterMigrationSecretNamespace, "cluster-migration-secret-namespace", m.ClusterMigrationSecretNamespace,
+		"The namespace of the secrets named by the cluster.open-cluster-management.io/migrate-to annotation, which hold "+
+			"the kubeconfigs of the target hubs.")
+	fs.BoolVar(&m.EnablePendingApproval, "enable-pending-approval", m.EnablePendingApproval,
+		"Report the ManagedClusters waiting for the hub to accept them and the users requesting them to join in the "+
+			"PendingApproval condition of the clusters. It is always enabled if --cluster-approval-expiration is set.")
+	fs.BoolVar(&m.EnableWorkAuthorRoles, "enable-work-author-roles", m.EnableWorkAuthorRoles,
+		"Provision the roles in the namespaces of the clusters bound by the ManagedClusterSetBindings annotated with "+
+			"cluster.open-cluster-management.io/work-author-groups, which allow the groups to author the ManifestWorks.")
 
 }
 
@@ -101,7 +194,7 @@ func (m *HubManagerOptions) RunControllerManager(ctx context.Context, controller
 
 	return m.RunControllerManagerWithInformers(
 		ctx, controllerContext,
-		kubeClient, clusterClient, addOnClient,
+		kubeClient, clusterClient, workClient, addOnClient,
 		kubeInfomers, clusterInformers, workInformers, addOnInformers,
 	)
 }
@@ -111,6 +204,7 @@ func (m *HubManagerOptions) RunControllerManagerWithInformers(
 	controllerContext *controllercmd.ControllerContext,
 	kubeClient kubernetes.Interface,
 	clusterClient clusterv1client.Interface,
+	workClient workv1client.Interface,
 	addOnClient addonclient.Interface,
 	kubeInformers kubeinformers.SharedInformerFactory,
 	clusterInformers clusterv1informers.SharedInformerFactory,
@@ -127,6 +221,7 @@ func (m *HubManagerOptions) RunControllerManagerWithInformers(
 		kubeInformers.Rbac().V1().RoleBindings(),
 		kubeInformers.Rbac().V1().ClusterRoleBindings(),
 		controllerContext.EventRecorder,
+		commonhelpers.NewEventRecorder(ctx, kubeClient, "registration-controller"),
 	)
 
 	taintController := taint.NewTaintController(
@@ -135,18 +230,20 @@ func (m *HubManagerOptions) RunControllerManagerWithInformers(
 		controllerContext.EventRecorder,
 	)
 
-	csrReconciles := []csr.Reconciler{csr.NewCSRRenewalReconciler(kubeClient, controllerContext.EventRecorder)}
+	csrReconciles := []csr.Reconciler{csr.NewCSRRenewalReconciler(kubeClient, m.CSRApprovalSigners, controllerContext.EventRecorder)}
 	if features.HubMutableFeatureGate.Enabled(ocmfeature.ManagedClusterAutoApproval) {
 		csrReconciles = append(csrReconciles, csr.NewCSRBootstrapReconciler(
 			kubeClient,
 			clusterClient,
 			clusterInformers.Cluster().V1().ManagedClusters().Lister(),
 			m.ClusterAutoApprovalUsers,
+			m.CSRApprovalSigners,
 			controllerContext.EventRecorder,
 		))
 	}
 
 	var csrController factory.Controller
+	var csrInformer cache.SharedIndexInformer
 	if features.HubMutableFeatureGate.Enabled(ocmfeature.V1beta1CSRAPICompatibility) {
 		v1CSRSupported, v1beta1CSRSupported, err := helpers.IsCSRSupported(kubeClient)
 		if err != nil {
@@ -154,8 +251,9 @@ func (m *HubManagerOptions) RunControllerManagerWithInformers(
 		}
 
 		if !v1CSRSupported && v1beta1CSRSupported {
+			csrInformer = kubeInformers.Certificates().V1beta1().CertificateSigningRequests().Informer()
 			csrController = csr.NewCSRApprovingController[*certv1beta1.CertificateSigningRequest](
-				kubeInformers.Certificates().V1beta1().CertificateSigningRequests().Informer(),
+				csrInformer,
 				kubeInformers.Certificates().V1beta1().CertificateSigningRequests().Lister(),
 				csr.NewCSRV1beta1Approver(kubeClient),
 				csrReconciles,
@@ -165,8 +263,9 @@ func (m *HubManagerOptions) RunControllerManagerWithInformers(
 		}
 	}
 	if csrController == nil {
+		csrInformer = kubeInformers.Certificates().V1().CertificateSigningRequests().Informer()
 		csrController = csr.NewCSRApprovingController[*certv1.CertificateSigningRequest](
-			kubeInformers.Certificates().V1().CertificateSigningRequests().Informer(),
+			csrInformer,
 			kubeInformers.Certificates().V1().CertificateSigningRequests().Lister(),
 			csr.NewCSRV1Approver(kubeClient),
 			csrReconciles,
@@ -199,6 +298,7 @@ func (m *HubManagerOptions) RunControllerManagerWithInformers(
 	)
 
 	managedClusterSetBindingController := managedclustersetbinding.NewManagedClusterSetBindingController(
+		kubeClient,
 		clusterClient,
 		clusterInformers.Cluster().V1beta2().ManagedClusterSets(),
 		clusterInformers.Cluster().V1beta2().ManagedClusterSetBindings(),
@@ -226,6 +326,136 @@ func (m *HubManagerOptions) RunControllerManagerWithInformers(
 		controllerContext.EventRecorder,
 	)
 
+	var migrationController factory.Controller
+	if m.EnableClusterMigration {
+		migrationController = migration.NewMigrationController(
+			kubeClient,
+			clusterClient,
+			workClient,
+			clusterInformers.Cluster().V1().ManagedClusters(),
+			workInformers.Work().V1().ManifestWorks(),
+			addOnInformers.Addon().V1alpha1().ManagedClusterAddOns(),
+			m.ClusterMigrationSecretNamespace,
+			controllerContext.EventRecorder,
+		)
+	}
+
+	var pendingApprovalController factory.Controller
+	if m.EnablePendingApproval || m.ClusterApprovalExpiration > 0 {
+		pendingApprovalController = pendingapproval.NewPendingApprovalController(
+			kubeClient,
+			clusterClient,
+			clusterInformers.Cluster().V1().ManagedClusters(),
+			csrInformer,
+			m.ClusterApprovalExpiration,
+			controllerContext.EventRecorder,
+		)
+	}
+
+	var workAuthorController factory.Controller
+	if m.EnableWorkAuthorRoles {
+		workAuthorController = workauthor.NewWorkAuthorController(
+			kubeClient,
+			clusterClient,
+			clusterInformers.Cluster().V1().ManagedClusters(),
+			clusterInformers.Cluster().V1beta2().ManagedClusterSets(),
+			clusterInformers.Cluster().V1beta2().ManagedClusterSetBindings(),
+			kubeInformers.Rbac().V1().Roles(),
+			kubeInformers.Rbac().V1().RoleBindings(),
+			controllerContext.EventRecorder,
+		)
+	}
+
+	var statusAggregationController factory.Controller
+	if m.EnableStatusAggregation {
+		installed, err := aggregation.ClusterClaimAPIInstalled(kubeClient.Discovery())
+		if err != nil {
+			return err
+		}
+		if installed {
+			statusAggregationController = aggregation.NewStatusAggregationController(
+				clusterClient,
+				clusterInformers.Cluster().V1().ManagedClusters(),
+				workInformers.Work().V1().ManifestWorks(),
+				controllerContext.EventRecorder,
+			)
+		} else {
+			klog.Info("ClusterClaim API is not installed, the status aggregation controller is not started")
+		}
+	}
+
+	var clusterProfileController factory.Controller
+	if len(m.ClusterProfileNamespace) > 0 {
+		dynamicClient, err := dynamic.NewForConfig(controllerContext.KubeConfig)
+		if err != nil {
+			return err
+		}
+		clusterProfileController = clusterprofile.NewClusterProfileController(
+			m.ClusterProfileNamespace,
+			dynamicClient,
+			clusterInformers.Cluster().V1().ManagedClusters(),
+			controllerContext.EventRecorder,
+		)
+	}
+
+	var importController factory.Controller
+	if len(m.ImportBootstrapKubeconfigSecret) > 0 {
+		var err error
+		importController, err = importer.NewImportController(
+			kubeClient,
+			clusterClient,
+			clusterInformers.Cluster().V1().ManagedClusters(),
+			m.ImportBootstrapKubeconfigSecret,
+			controllerContext.EventRecorder,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	var bootstrapKubeconfigController factory.Controller
+	if len(m.BootstrapTokenServiceAccount) > 0 {
+		var err error
+		bootstrapKubeconfigController, err = bootstrapkubeconfig.NewBootstrapKubeconfigController(
+			kubeClient,
+			workClient,
+			clusterInformers.Cluster().V1().ManagedClusters(),
+			workInformers.Work().V1().ManifestWorks(),
+			m.BootstrapTokenServiceAccount,
+			m.BootstrapHubAPIServer,
+			m.BootstrapTokenExpiration,
+			controllerContext.EventRecorder,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	var claimLabelController factory.Controller
+	var claimLabelRulesInformers kubeinformers.SharedInformerFactory
+	if len(m.ClusterClaimLabelRulesConfigMap) > 0 {
+		namespace, name, err := cache.SplitMetaNamespaceKey(m.ClusterClaimLabelRulesConfigMap)
+		if err != nil {
+			return err
+		}
+		// the kubeInformers only watch the labeled resources, the rules configmap is watched by its own informers.
+		claimLabelRulesInformers = kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 10*time.Minute,
+			kubeinformers.WithNamespace(namespace),
+			kubeinformers.WithTweakListOptions(func(listOptions *metav1.ListOptions) {
+				listOptions.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+			}))
+		claimLabelController, err = claimlabel.NewClaimLabelController(
+			clusterClient,
+			clusterInformers.Cluster().V1().ManagedClusters(),
+			claimLabelRulesInformers.Core().V1().ConfigMaps(),
+			m.ClusterClaimLabelRulesConfigMap,
+			controllerContext.EventRecorder,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	var defaultManagedClusterSetController, globalManagedClusterSetController factory.Controller
 	if features.HubMutableFeatureGate.Enabled(ocmfeature.DefaultClusterSet) {
 		defaultManagedClusterSetController = managedclusterset.NewDefaultManagedClusterSetController(
@@ -240,10 +470,34 @@ func (m *HubManagerOptions) RunControllerManagerWithInformers(
 		)
 	}
 
+	if err := commonhelpers.SetInformerTransform(commonhelpers.TrimManagedFieldsAndLastApplied,
+		clusterInformers.Cluster().V1().ManagedClusters().Informer(),
+		clusterInformers.Cluster().V1beta2().ManagedClusterSetBindings().Informer(),
+		workInformers.Work().V1().ManifestWorks().Informer(),
+		kubeInformers.Coordination().V1().Leases().Informer(),
+		kubeInformers.Core().V1().Namespaces().Informer(),
+		csrInformer,
+		addOnInformers.Addon().V1alpha1().ManagedClusterAddOns().Informer(),
+	); err != nil {
+		return err
+	}
+	if err := commonhelpers.SetInformerTransform(commonhelpers.TrimManagedFields,
+		clusterInformers.Cluster().V1beta2().ManagedClusterSets().Informer(),
+		kubeInformers.Rbac().V1().Roles().Informer(),
+		kubeInformers.Rbac().V1().ClusterRoles().Informer(),
+		kubeInformers.Rbac().V1().RoleBindings().Informer(),
+		kubeInformers.Rbac().V1().ClusterRoleBindings().Informer(),
+	); err != nil {
+		return err
+	}
+
 	go clusterInformers.Start(ctx.Done())
 	go workInformers.Start(ctx.Done())
 	go kubeInformers.Start(ctx.Done())
 	go addOnInformers.Start(ctx.Done())
+	if len(m.ClusterClaimLabelRulesConfigMap) > 0 {
+		go claimLabelRulesInformers.Start(ctx.Done())
+	}
 
 	go managedClusterController.Run(ctx, 1)
 	go taintController.Run(ctx, 1)
@@ -259,6 +513,30 @@ func (m *HubManagerOptions) RunControllerManagerWithInformers(
 		go defaultManagedClusterSetController.Run(ctx, 1)
 		go globalManagedClusterSetController.Run(ctx, 1)
 	}
+	if m.EnableClusterMigration {
+		go migrationController.Run(ctx, 1)
+	}
+	if pendingApprovalController != nil {
+		go pendingApprovalController.Run(ctx, 1)
+	}
+	if m.EnableWorkAuthorRoles {
+		go workAuthorController.Run(ctx, 1)
+	}
+	if statusAggregationController != nil {
+		go statusAggregationController.Run(ctx, 1)
+	}
+	if len(m.ClusterProfileNamespace) > 0 {
+		go clusterProfileController.Run(ctx, 1)
+	}
+	if len(m.ImportBootstrapKubeconfigSecret) > 0 {
+		go importController.Run(ctx, 1)
+	}
+	if len(m.BootstrapTokenServiceAccount) > 0 {
+		go bootstrapKubeconfigController.Run(ctx, 1)
+	}
+	if len(m.ClusterClaimLabelRulesConfigMap) > 0 {
+		go claimLabelController.Run(ctx, 1)
+	}
 
 	<-ctx.Done()
 	return nil
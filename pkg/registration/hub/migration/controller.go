@@ -0,0 +1,384 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonclientset "open-cluster-management.io/api/client/addon/clientset/versioned"
+	addoninformerv1alpha1 "open-cluster-management.io/api/client/addon/informers/externalversions/addon/v1alpha1"
+	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterinformerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	workinformerv1 "open-cluster-management.io/api/client/work/informers/externalversions/work/v1"
+	worklisterv1 "open-cluster-management.io/api/client/work/listers/work/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/logging"
+	"open-cluster-management.io/ocm/pkg/common/patcher"
+	"open-cluster-management.io/ocm/pkg/common/queue"
+)
+
+const (
+	// MigrationAnnotation on a ManagedCluster starts the migration of the cluster to another hub, its value is the
+	// name of a secret in the migration secret namespace, which holds the kubeconfigs of the target hub.
+	MigrationAnnotation = "cluster.open-cluster-management.io/migrate-to"
+
+	// MigrationHubKubeConfigKey is the key of the kubeconfig used by the controller to export the cluster to the
+	// target hub.
+	MigrationHubKubeConfigKey = "hub-kubeconfig"
+	// MigrationBootstrapKubeConfigKey is the key of the bootstrap kubeconfig used by the klusterlet to register
+	// the cluster to the target hub.
+	MigrationBootstrapKubeConfigKey = "bootstrap-kubeconfig"
+	// MigrationAgentNamespaceKey is the optional key of the namespace of the klusterlet agents on the managed
+	// cluster, it is open-cluster-management-agent by default.
+	MigrationAgentNamespaceKey = "agent-namespace"
+
+	// ManagedClusterConditionMigrated reports the progress of the migration of a ManagedCluster.
+	ManagedClusterConditionMigrated = "ManagedClusterMigrated"
+
+	// MigrationWorkName is the name of the ManifestWork delivering the bootstrap kubeconfig of the target hub.
+	MigrationWorkName = "cluster-migration"
+
+	defaultAgentNamespace  = "open-cluster-management-agent"
+	bootstrapSecretName    = "bootstrap-hub-kubeconfig" // #nosec G101
+	adoptionCheckInterval  = 10 * time.Second
+	reasonMigrationFailed  = "MigrationFailed"
+	reasonMigrationStarted = "MigrationInProgress"
+	reasonMigrationDone    = "MigrationCompleted"
+)
+
+// hubClients are the clients of the target hub.
+type hubClients struct {
+	kubeClient    kubernetes.Interface
+	clusterClient clusterclientset.Interface
+	workClient    workclientset.Interface
+	addOnClient   addonclientset.Interface
+}
+
+func newHubClients(kubeconfig []byte) (*hubClients, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	workClient, err := workclientset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	addOnClient, err := addonclientset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &hubClients{
+		kubeClient:    kubeClient,
+		clusterClient: clusterClient,
+		workClient:    workClient,
+		addOnClient:   addOnClient,
+	}, nil
+}
+
+// migrationController migrates the ManagedClusters with the migration annotation to the target hubs.
+type migrationController struct {
+	kubeClient    kubernetes.Interface
+	workClient    workclientset.Interface
+	clusterLister clusterlisterv1.ManagedClusterLister
+	workLister    worklisterv1.ManifestWorkLister
+	addOnLister   addonlisterv1alpha1.ManagedClusterAddOnLister
+	// secretNamespace is the namespace of the migration secrets, the controller is only allowed to read the secrets
+	// in it.
+	secretNamespace string
+	patcher         patcher.Patcher[*clusterv1.ManagedCluster, clusterv1.ManagedClusterSpec, clusterv1.ManagedClusterStatus]
+	// newTargetHub builds the clients of the target hub, it is replaced in the unit tests.
+	newTargetHub  func(kubeconfig []byte) (*hubClients, error)
+	eventRecorder events.Recorder
+}
+
+// NewMigrationController creates a new cluster migration controller
+func NewMigrationController(
+	kubeClient kubernetes.Interface,
+	clusterClient clusterclientset.Interface,
+	workClient workclientset.Interface,
+	clusterInformer clusterinformerv1.ManagedClusterInformer,
+	workInformer workinformerv1.ManifestWorkInformer,
+	addOnInformer addoninformerv1alpha1.ManagedClusterAddOnInformer,
+	secretNamespace string,
+	recorder events.Recorder) factory.Controller {
+	c := &migrationController{
+		kubeClient:      kubeClient,
+		workClient:      workClient,
+		clusterLister:   clusterInformer.Lister(),
+		workLister:      workInformer.Lister(),
+		addOnLister:     addOnInformer.Lister(),
+		secretNamespace: secretNamespace,
+		patcher: patcher.NewPatcher[
+			*clusterv1.ManagedCluster, clusterv1.ManagedClusterSpec, clusterv1.ManagedClusterStatus](
+			clusterClient.ClusterV1().ManagedClusters()),
+		newTargetHub:  newHubClients,
+		eventRecorder: recorder.WithComponentSuffix("cluster-migration-controller"),
+	}
+	return factory.New().
+		WithFilteredEventsInformersQueueKeysFunc(
+			queue.QueueKeyByMetaName,
+			func(obj interface{}) bool {
+				accessor, err := meta.Accessor(obj)
+				if err != nil {
+					return false
+				}
+				_, ok := accessor.GetAnnotations()[MigrationAnnotation]
+				return ok
+			},
+			clusterInformer.Informer()).
+		WithSync(logging.WithControllerLogger("ClusterMigrationController", c.sync)).
+		ToController("ClusterMigrationController", recorder)
+}
+
+func (c *migrationController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	clusterName := syncCtx.QueueKey()
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling the migration of ManagedCluster", "managedClusterName", clusterName)
+
+	cluster, err := c.clusterLister.Get(clusterName)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	secretName, ok := cluster.Annotations[MigrationAnnotation]
+	if !ok || !cluster.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	if meta.IsStatusConditionTrue(cluster.Status.Conditions, ManagedClusterConditionMigrated) {
+		// the cluster is migrated, it is up to the hub admin to remove it from this hub.
+		return nil
+	}
+
+	secret, err := c.kubeClient.CoreV1().Secrets(c.secretNamespace).Get(ctx, secretName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		return c.updateCondition(ctx, cluster, metav1.ConditionFalse, reasonMigrationFailed,
+			fmt.Sprintf("The migration secret %s/%s is not found", c.secretNamespace, secretName))
+	case err != nil:
+		return err
+	}
+
+	bootstrapKubeconfig := secret.Data[MigrationBootstrapKubeConfigKey]
+	if len(bootstrapKubeconfig) == 0 {
+		return c.updateCondition(ctx, cluster, metav1.ConditionFalse, reasonMigrationFailed,
+			fmt.Sprintf("There is no %q in the migration secret %s/%s", MigrationBootstrapKubeConfigKey, c.secretNamespace, secretName))
+	}
+	target, err := c.newTargetHub(secret.Data[MigrationHubKubeConfigKey])
+	if err != nil {
+		return c.updateCondition(ctx, cluster, metav1.ConditionFalse, reasonMigrationFailed,
+			fmt.Sprintf("Unable to connect to the target hub with %q of the migration secret %s/%s: %v",
+				MigrationHubKubeConfigKey, c.secretNamespace, secretName, err))
+	}
+
+	// export the cluster to the target hub
+	if err := c.export(ctx, cluster, target); err != nil {
+		return err
+	}
+
+	// trigger the klusterlet to rebootstrap against the target hub
+	agentNamespace := string(secret.Data[MigrationAgentNamespaceKey])
+	if len(agentNamespace) == 0 {
+		agentNamespace = defaultAgentNamespace
+	}
+	if err := c.applyMigrationWork(ctx, clusterName, agentNamespace, bootstrapKubeconfig); err != nil {
+		return err
+	}
+
+	// verify the cluster is adopted by the target hub
+	targetCluster, err := target.clusterClient.ClusterV1().ManagedClusters().Get(ctx, clusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !meta.IsStatusConditionTrue(targetCluster.Status.Conditions, clusterv1.ManagedClusterConditionJoined) ||
+		!meta.IsStatusConditionTrue(targetCluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable) {
+		syncCtx.Queue().AddAfter(clusterName, adoptionCheckInterval)
+		return c.updateCondition(ctx, cluster, metav1.ConditionFalse, reasonMigrationStarted,
+			"The klusterlet is rebootstrapping against the target hub")
+	}
+
+	c.eventRecorder.Eventf("ManagedClusterMigrated", "managed cluster %s is migrated to the target hub", clusterName)
+	return c.updateCondition(ctx, cluster, metav1.ConditionTrue, reasonMigrationDone,
+		"The managed cluster is adopted by the target hub")
+}
+
+// export creates the cluster, its works and addons on the target hub. The objects existing on the target hub are
+// not changed, so the migration could be resumed.
+func (c *migrationController) export(ctx context.Context, cluster *clusterv1.ManagedCluster, target *hubClients) error {
+	targetCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        cluster.Name,
+			Labels:      cluster.Labels,
+			Annotations: withoutMigrationAnnotation(cluster.Annotations),
+		},
+		Spec: *cluster.Spec.DeepCopy(),
+	}
+	// the cluster is accepted on the target hub by the migration
+	targetCluster.Spec.HubAcceptsClient = true
+	if _, err := target.clusterClient.ClusterV1().ManagedClusters().Create(
+		ctx, targetCluster, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: cluster.Name,
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabelKey: cluster.Name,
+			},
+		},
+	}
+	if _, err := target.kubeClient.CoreV1().Namespaces().Create(
+		ctx, namespace, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	works, err := c.workLister.ManifestWorks(cluster.Name).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, work := range works {
+		if work.Name == MigrationWorkName {
+			continue
+		}
+		targetWork := &workapiv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        work.Name,
+				Namespace:   work.Namespace,
+				Labels:      work.Labels,
+				Annotations: work.Annotations,
+			},
+			Spec: *work.Spec.DeepCopy(),
+		}
+		if _, err := target.workClient.WorkV1().ManifestWorks(cluster.Name).Create(
+			ctx, targetWork, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	addOns, err := c.addOnLister.ManagedClusterAddOns(cluster.Name).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, addOn := range addOns {
+		targetAddOn := &addonv1alpha1.ManagedClusterAddOn{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        addOn.Name,
+				Namespace:   addOn.Namespace,
+				Labels:      addOn.Labels,
+				Annotations: addOn.Annotations,
+			},
+			Spec: *addOn.Spec.DeepCopy(),
+		}
+		if _, err := target.addOnClient.AddonV1alpha1().ManagedClusterAddOns(cluster.Name).Create(
+			ctx, targetAddOn, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyMigrationWork creates the ManifestWork which replaces the bootstrap kubeconfig of the klusterlet with the one
+// of the target hub, the klusterlet then rebootstraps against the target hub. The secret is orphaned when the work
+// is deleted, so it is kept after the cluster is removed from this hub.
+func (c *migrationController) applyMigrationWork(ctx context.Context, clusterName, agentNamespace string, bootstrapKubeconfig []byte) error {
+	_, err := c.workLister.ManifestWorks(clusterName).Get(MigrationWorkName)
+	switch {
+	case err == nil:
+		return nil
+	case !errors.IsNotFound(err):
+		return err
+	}
+
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstrapSecretName,
+			Namespace: agentNamespace,
+		},
+		Data: map[string][]byte{
+			"kubeconfig": bootstrapKubeconfig,
+		},
+	}
+	work := &workapiv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      MigrationWorkName,
+			Namespace: clusterName,
+		},
+		Spec: workapiv1.ManifestWorkSpec{
+			Workload: workapiv1.ManifestsTemplate{
+				Manifests: []workapiv1.Manifest{
+					{RawExtension: runtime.RawExtension{Object: secret}},
+				},
+			},
+			DeleteOption: &workapiv1.DeleteOption{
+				PropagationPolicy: workapiv1.DeletePropagationPolicyTypeOrphan,
+			},
+		},
+	}
+	if _, err := c.workClient.WorkV1().ManifestWorks(clusterName).Create(
+		ctx, work, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	c.eventRecorder.Eventf("ManagedClusterMigrationStarted",
+		"managed cluster %s starts rebootstrapping against the target hub", clusterName)
+	return nil
+}
+
+func (c *migrationController) updateCondition(ctx context.Context, cluster *clusterv1.ManagedCluster,
+	status metav1.ConditionStatus, reason, message string) error {
+	newCluster := cluster.DeepCopy()
+	meta.SetStatusCondition(&newCluster.Status.Conditions, metav1.Condition{
+		Type:    ManagedClusterConditionMigrated,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+	_, err := c.patcher.PatchStatus(ctx, newCluster, newCluster.Status, cluster.Status)
+	return err
+}
+
+func withoutMigrationAnnotation(annotations map[string]string) map[string]string {
+	if len(annotations) == 0 {
+		return nil
+	}
+	copied := map[string]string{}
+	for k, v := range annotations {
+		if k == MigrationAnnotation {
+			continue
+		}
+		copied[k] = v
+	}
+	return copied
+}
@@ -0,0 +1,258 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events/eventstesting"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonfake "open-cluster-management.io/api/client/addon/clientset/versioned/fake"
+	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	workfake "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/patcher"
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+)
+
+func newMigratingCluster(conditions ...metav1.Condition) *clusterv1.ManagedCluster {
+	cluster := testinghelpers.NewAvailableManagedCluster()
+	cluster.Annotations = map[string]string{MigrationAnnotation: "migration"}
+	cluster.Status.Conditions = append(cluster.Status.Conditions, conditions...)
+	return cluster
+}
+
+const testSecretNamespace = "open-cluster-management-hub"
+
+func newMigrationSecret(namespace string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "migration", Namespace: namespace},
+		Data: map[string][]byte{
+			MigrationHubKubeConfigKey:       []byte("hub-kubeconfig"),
+			MigrationBootstrapKubeConfigKey: []byte("bootstrap-kubeconfig"),
+		},
+	}
+}
+
+func TestSync(t *testing.T) {
+	cases := []struct {
+		name                  string
+		cluster               *clusterv1.ManagedCluster
+		secrets               []runtime.Object
+		works                 []runtime.Object
+		addOns                []runtime.Object
+		targetClusters        []runtime.Object
+		validateClusterAction func(t *testing.T, actions []clienttesting.Action)
+		validateWorkActions   func(t *testing.T, actions []clienttesting.Action)
+		validateTargetActions func(t *testing.T, target *targetFakeClients)
+	}{
+		{
+			name:    "cluster is not migrating",
+			cluster: testinghelpers.NewAvailableManagedCluster(),
+			validateClusterAction: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+			validateWorkActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+		{
+			name:    "migration secret is not found",
+			cluster: newMigratingCluster(),
+			validateClusterAction: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch")
+				assertMigratedCondition(t, actions[0], metav1.ConditionFalse, reasonMigrationFailed)
+			},
+			validateWorkActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+		{
+			name:    "migration secret is not in the migration secret namespace",
+			cluster: newMigratingCluster(),
+			secrets: []runtime.Object{newMigrationSecret(testinghelpers.TestManagedClusterName)},
+			validateClusterAction: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch")
+				assertMigratedCondition(t, actions[0], metav1.ConditionFalse, reasonMigrationFailed)
+			},
+			validateWorkActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+		{
+			name:    "start migration",
+			cluster: newMigratingCluster(),
+			secrets: []runtime.Object{newMigrationSecret(testSecretNamespace)},
+			works:   []runtime.Object{testinghelpers.NewManifestWork(testinghelpers.TestManagedClusterName, "work1", nil, nil)},
+			addOns: []runtime.Object{&addonv1alpha1.ManagedClusterAddOn{
+				ObjectMeta: metav1.ObjectMeta{Name: "addon1", Namespace: testinghelpers.TestManagedClusterName},
+			}},
+			validateClusterAction: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch")
+				assertMigratedCondition(t, actions[0], metav1.ConditionFalse, reasonMigrationStarted)
+			},
+			validateWorkActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "create")
+				work := actions[0].(clienttesting.CreateAction).GetObject().(*workapiv1.ManifestWork)
+				if work.Name != MigrationWorkName {
+					t.Errorf("expected work %s, but got %s", MigrationWorkName, work.Name)
+				}
+				secret := work.Spec.Workload.Manifests[0].Object.(*corev1.Secret)
+				if secret.Namespace != defaultAgentNamespace || string(secret.Data["kubeconfig"]) != "bootstrap-kubeconfig" {
+					t.Errorf("unexpected bootstrap secret %v", secret)
+				}
+				if work.Spec.DeleteOption.PropagationPolicy != workapiv1.DeletePropagationPolicyTypeOrphan {
+					t.Errorf("expected the bootstrap secret is orphaned")
+				}
+			},
+			validateTargetActions: func(t *testing.T, target *targetFakeClients) {
+				testingcommon.AssertActions(t, target.clusterClient.Actions(), "create", "get")
+				cluster := target.clusterClient.Actions()[0].(clienttesting.CreateAction).GetObject().(*clusterv1.ManagedCluster)
+				if _, ok := cluster.Annotations[MigrationAnnotation]; ok || !cluster.Spec.HubAcceptsClient {
+					t.Errorf("unexpected cluster created on the target hub %v", cluster)
+				}
+				testingcommon.AssertActions(t, target.kubeClient.Actions(), "create")
+				testingcommon.AssertActions(t, target.workClient.Actions(), "create")
+				testingcommon.AssertActions(t, target.addOnClient.Actions(), "create")
+			},
+		},
+		{
+			name:    "cluster is adopted",
+			cluster: newMigratingCluster(),
+			secrets: []runtime.Object{newMigrationSecret(testSecretNamespace)},
+			works:   []runtime.Object{testinghelpers.NewManifestWork(testinghelpers.TestManagedClusterName, MigrationWorkName, nil, nil)},
+			targetClusters: []runtime.Object{func() *clusterv1.ManagedCluster {
+				cluster := testinghelpers.NewAvailableManagedCluster()
+				cluster.Status.Conditions = append(cluster.Status.Conditions, metav1.Condition{
+					Type:   clusterv1.ManagedClusterConditionJoined,
+					Status: metav1.ConditionTrue,
+				})
+				return cluster
+			}()},
+			validateClusterAction: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch")
+				assertMigratedCondition(t, actions[0], metav1.ConditionTrue, reasonMigrationDone)
+			},
+			validateWorkActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+		{
+			name: "cluster is migrated",
+			cluster: newMigratingCluster(metav1.Condition{
+				Type:   ManagedClusterConditionMigrated,
+				Status: metav1.ConditionTrue,
+				Reason: reasonMigrationDone,
+			}),
+			secrets: []runtime.Object{newMigrationSecret(testSecretNamespace)},
+			validateClusterAction: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+			validateWorkActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset(c.secrets...)
+			clusterClient := clusterfake.NewSimpleClientset(c.cluster)
+			clusterInformerFactory := clusterinformers.NewSharedInformerFactory(clusterClient, time.Minute*10)
+			if err := clusterInformerFactory.Cluster().V1().ManagedClusters().Informer().GetStore().Add(c.cluster); err != nil {
+				t.Fatal(err)
+			}
+			workClient := workfake.NewSimpleClientset(c.works...)
+			workInformerFactory := workinformers.NewSharedInformerFactory(workClient, time.Minute*10)
+			for _, work := range c.works {
+				if err := workInformerFactory.Work().V1().ManifestWorks().Informer().GetStore().Add(work); err != nil {
+					t.Fatal(err)
+				}
+			}
+			addOnClient := addonfake.NewSimpleClientset(c.addOns...)
+			addOnInformerFactory := addoninformers.NewSharedInformerFactory(addOnClient, time.Minute*10)
+			for _, addOn := range c.addOns {
+				if err := addOnInformerFactory.Addon().V1alpha1().ManagedClusterAddOns().Informer().GetStore().Add(addOn); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			target := &targetFakeClients{
+				kubeClient:    kubefake.NewSimpleClientset(),
+				clusterClient: clusterfake.NewSimpleClientset(c.targetClusters...),
+				workClient:    workfake.NewSimpleClientset(),
+				addOnClient:   addonfake.NewSimpleClientset(),
+			}
+			ctrl := &migrationController{
+				kubeClient:      kubeClient,
+				workClient:      workClient,
+				clusterLister:   clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+				workLister:      workInformerFactory.Work().V1().ManifestWorks().Lister(),
+				addOnLister:     addOnInformerFactory.Addon().V1alpha1().ManagedClusterAddOns().Lister(),
+				secretNamespace: testSecretNamespace,
+				patcher: patcher.NewPatcher[*clusterv1.ManagedCluster, clusterv1.ManagedClusterSpec, clusterv1.ManagedClusterStatus](
+					clusterClient.ClusterV1().ManagedClusters()),
+				newTargetHub: func(kubeconfig []byte) (*hubClients, error) {
+					return &hubClients{
+						kubeClient:    target.kubeClient,
+						clusterClient: target.clusterClient,
+						workClient:    target.workClient,
+						addOnClient:   target.addOnClient,
+					}, nil
+				},
+				eventRecorder: eventstesting.NewTestingEventRecorder(t),
+			}
+			clusterClient.ClearActions()
+			workClient.ClearActions()
+			target.clusterClient.ClearActions()
+
+			if err := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, testinghelpers.TestManagedClusterName)); err != nil {
+				t.Errorf("unexpected err: %v", err)
+			}
+
+			c.validateClusterAction(t, clusterClient.Actions())
+			c.validateWorkActions(t, workClient.Actions())
+			if c.validateTargetActions != nil {
+				c.validateTargetActions(t, target)
+			}
+		})
+	}
+}
+
+type targetFakeClients struct {
+	kubeClient    *kubefake.Clientset
+	clusterClient *clusterfake.Clientset
+	workClient    *workfake.Clientset
+	addOnClient   *addonfake.Clientset
+}
+
+func assertMigratedCondition(t *testing.T, action clienttesting.Action, status metav1.ConditionStatus, reason string) {
+	t.Helper()
+	cluster := &clusterv1.ManagedCluster{}
+	if err := json.Unmarshal(action.(clienttesting.PatchAction).GetPatch(), cluster); err != nil {
+		t.Fatal(err)
+	}
+	for _, condition := range cluster.Status.Conditions {
+		if condition.Type != ManagedClusterConditionMigrated {
+			continue
+		}
+		if condition.Status != status || condition.Reason != reason {
+			t.Errorf("expected condition %s/%s, but got %s/%s", status, reason, condition.Status, condition.Reason)
+		}
+		return
+	}
+	t.Errorf("expected condition %s, but got %v", ManagedClusterConditionMigrated, cluster.Status.Conditions)
+}
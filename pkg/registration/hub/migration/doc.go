@@ -0,0 +1,5 @@
+// Package migration contains the controller which moves a ManagedCluster from this hub to another hub. The works
+// and the addons of the cluster are exported to the target hub, the klusterlet is then rebootstrapped against the
+// target hub with a bootstrap kubeconfig delivered by a ManifestWork, and the migration is completed once the
+// cluster is adopted by the target hub.
+package migration
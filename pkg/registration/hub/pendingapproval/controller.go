@@ -0,0 +1,208 @@
+package pendingapproval
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	certificatesv1beta1 "k8s.io/api/certificates/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	clientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	informerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	listerv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	v1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/logging"
+	"open-cluster-management.io/ocm/pkg/common/patcher"
+	"open-cluster-management.io/ocm/pkg/common/queue"
+)
+
+const (
+	// ConditionPendingApproval is true if the ManagedCluster is waiting for the hub to accept it.
+	ConditionPendingApproval = "PendingApproval"
+
+	// The reasons of the PendingApproval condition.
+	ReasonWaitingForApproval = "WaitingForApproval"
+	ReasonClusterAccepted    = "ClusterAccepted"
+	ReasonClusterDenied      = "ClusterDenied"
+)
+
+// pendingApprovalController maintains the PendingApproval condition of the ManagedClusters with the identity of the
+// user requesting the cluster to join, which is the requestor of the latest CSR of the cluster. A ManagedCluster
+// which has never been accepted is deleted with its CSRs once it has been pending for longer than the expiration,
+// the clusters never expire if the expiration is 0.
+type pendingApprovalController struct {
+	kubeClient    kubernetes.Interface
+	clusterClient clientset.Interface
+	clusterLister listerv1.ManagedClusterLister
+	csrStore      cache.Store
+	patcher       patcher.Patcher[*v1.ManagedCluster, v1.ManagedClusterSpec, v1.ManagedClusterStatus]
+	expiration    time.Duration
+	eventRecorder events.Recorder
+}
+
+// NewPendingApprovalController creates a new pending approval controller, the csrInformer is the informer of either
+// the v1 or the v1beta1 CSRs labeled with the cluster name.
+func NewPendingApprovalController(
+	kubeClient kubernetes.Interface,
+	clusterClient clientset.Interface,
+	clusterInformer informerv1.ManagedClusterInformer,
+	csrInformer cache.SharedIndexInformer,
+	expiration time.Duration,
+	recorder events.Recorder) factory.Controller {
+	c := &pendingApprovalController{
+		kubeClient:    kubeClient,
+		clusterClient: clusterClient,
+		clusterLister: clusterInformer.Lister(),
+		csrStore:      csrInformer.GetStore(),
+		patcher: patcher.NewPatcher[
+			*v1.ManagedCluster, v1.ManagedClusterSpec, v1.ManagedClusterStatus](
+			clusterClient.ClusterV1().ManagedClusters()),
+		expiration:    expiration,
+		eventRecorder: recorder.WithComponentSuffix("pending-approval-controller"),
+	}
+	return factory.New().
+		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterInformer.Informer()).
+		WithFilteredEventsInformersQueueKeysFunc(
+			queue.QueueKeyByLabel(v1.ClusterNameLabelKey),
+			queue.FileterByLabel(v1.ClusterNameLabelKey),
+			csrInformer).
+		WithSync(logging.WithControllerLogger("PendingApprovalController", c.sync)).
+		ToController("PendingApprovalController", recorder)
+}
+
+func (c *pendingApprovalController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	clusterName := syncCtx.QueueKey()
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling pending approval of ManagedCluster", "managedClusterName", clusterName)
+
+	cluster, err := c.clusterLister.Get(clusterName)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !cluster.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	requester, denied := c.latestRequest(clusterName)
+	condition := metav1.Condition{Type: ConditionPendingApproval}
+	var deadline time.Time
+	switch {
+	case cluster.Spec.HubAcceptsClient:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonClusterAccepted
+		condition.Message = "The cluster is accepted by the hub."
+	case denied || meta.FindStatusCondition(cluster.Status.Conditions, v1.ManagedClusterConditionHubAccepted) != nil:
+		// the cluster is denied after it has been accepted or its CSR is denied, it is left to the admin.
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonClusterDenied
+		condition.Message = "The cluster is denied by the hub."
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonWaitingForApproval
+		condition.Message = "The cluster is waiting for the hub to accept it"
+		if len(requester) > 0 {
+			condition.Message = fmt.Sprintf("%s, requested by %q", condition.Message, requester)
+		}
+		if c.expiration > 0 {
+			deadline = cluster.CreationTimestamp.Add(c.expiration)
+			condition.Message = fmt.Sprintf("%s, the request expires at %s", condition.Message, deadline.UTC().Format(time.RFC3339))
+		}
+		condition.Message += "."
+	}
+
+	if !deadline.IsZero() {
+		if remaining := time.Until(deadline); remaining > 0 {
+			syncCtx.Queue().AddAfter(clusterName, remaining)
+		} else {
+			return c.cleanup(ctx, cluster, requester)
+		}
+	}
+
+	newCluster := cluster.DeepCopy()
+	meta.SetStatusCondition(&newCluster.Status.Conditions, condition)
+	_, err = c.patcher.PatchStatus(ctx, newCluster, newCluster.Status, cluster.Status)
+	return err
+}
+
+// latestRequest returns the requestor of the latest CSR of the cluster, and whether the CSR is denied.
+func (c *pendingApprovalController) latestRequest(clusterName string) (string, bool) {
+	var latest time.Time
+	var requester string
+	var denied bool
+	for _, obj := range c.csrStore.List() {
+		accessor, err := meta.Accessor(obj)
+		if err != nil || accessor.GetLabels()[v1.ClusterNameLabelKey] != clusterName {
+			continue
+		}
+		if created := accessor.GetCreationTimestamp().Time; len(requester) == 0 || created.After(latest) {
+			latest = created
+			requester, denied = csrRequest(obj.(runtime.Object))
+		}
+	}
+	return requester, denied
+}
+
+// cleanup deletes the CSRs and the cluster whose approval is expired.
+func (c *pendingApprovalController) cleanup(ctx context.Context, cluster *v1.ManagedCluster, requester string) error {
+	for _, obj := range c.csrStore.List() {
+		accessor, err := meta.Accessor(obj)
+		if err != nil || accessor.GetLabels()[v1.ClusterNameLabelKey] != cluster.Name {
+			continue
+		}
+		switch obj.(type) {
+		case *certificatesv1.CertificateSigningRequest:
+			err = c.kubeClient.CertificatesV1().CertificateSigningRequests().Delete(ctx, accessor.GetName(), metav1.DeleteOptions{})
+		case *certificatesv1beta1.CertificateSigningRequest:
+			err = c.kubeClient.CertificatesV1beta1().CertificateSigningRequests().Delete(ctx, accessor.GetName(), metav1.DeleteOptions{})
+		}
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	err := c.clusterClient.ClusterV1().ManagedClusters().Delete(ctx, cluster.Name, metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	c.eventRecorder.Eventf("ManagedClusterApprovalExpired",
+		"managed cluster %s requested by %q is deleted since it is not accepted in %s", cluster.Name, requester, c.expiration)
+	return nil
+}
+
+// csrRequest returns the requestor of the CSR, and whether the CSR is denied.
+func csrRequest(obj runtime.Object) (string, bool) {
+	switch csr := obj.(type) {
+	case *certificatesv1.CertificateSigningRequest:
+		for _, condition := range csr.Status.Conditions {
+			if condition.Type == certificatesv1.CertificateDenied {
+				return csr.Spec.Username, true
+			}
+		}
+		return csr.Spec.Username, false
+	case *certificatesv1beta1.CertificateSigningRequest:
+		for _, condition := range csr.Status.Conditions {
+			if condition.Type == certificatesv1beta1.CertificateDenied {
+				return csr.Spec.Username, true
+			}
+		}
+		return csr.Spec.Username, false
+	}
+	return "", false
+}
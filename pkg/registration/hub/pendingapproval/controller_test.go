@@ -0,0 +1,174 @@
+package pendingapproval
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events/eventstesting"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	v1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/patcher"
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+)
+
+func TestSync(t *testing.T) {
+	requestCSR := testinghelpers.CSRHolder{
+		Name:     "csr1",
+		Labels:   map[string]string{v1.ClusterNameLabelKey: testinghelpers.TestManagedClusterName},
+		Username: "system:bootstrap:abcdef",
+	}
+	cases := []struct {
+		name                 string
+		cluster              *v1.ManagedCluster
+		csrs                 []runtime.Object
+		expiration           time.Duration
+		expectedKubeActions  []string
+		expectedClusterVerbs []string
+		expectedCondition    *metav1.Condition
+	}{
+		{
+			name:                 "pending cluster",
+			cluster:              testinghelpers.NewManagedCluster(),
+			csrs:                 []runtime.Object{testinghelpers.NewCSR(requestCSR)},
+			expectedClusterVerbs: []string{"patch"},
+			expectedCondition: &metav1.Condition{
+				Type:    ConditionPendingApproval,
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonWaitingForApproval,
+				Message: "The cluster is waiting for the hub to accept it, requested by \"system:bootstrap:abcdef\".",
+			},
+		},
+		{
+			name: "pending cluster not expired",
+			cluster: func() *v1.ManagedCluster {
+				cluster := testinghelpers.NewManagedCluster()
+				cluster.CreationTimestamp = metav1.NewTime(time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC))
+				return cluster
+			}(),
+			expiration:           time.Hour,
+			expectedClusterVerbs: []string{"patch"},
+			expectedCondition: &metav1.Condition{
+				Type:    ConditionPendingApproval,
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonWaitingForApproval,
+				Message: "The cluster is waiting for the hub to accept it, the request expires at 2100-01-01T01:00:00Z.",
+			},
+		},
+		{
+			name: "pending cluster expired",
+			cluster: func() *v1.ManagedCluster {
+				cluster := testinghelpers.NewManagedCluster()
+				cluster.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+				return cluster
+			}(),
+			csrs:                 []runtime.Object{testinghelpers.NewCSR(requestCSR)},
+			expiration:           time.Hour,
+			expectedKubeActions:  []string{"delete"},
+			expectedClusterVerbs: []string{"delete"},
+		},
+		{
+			name:                 "accepted cluster",
+			cluster:              testinghelpers.NewAcceptedManagedCluster(),
+			expiration:           time.Hour,
+			expectedClusterVerbs: []string{"patch"},
+			expectedCondition: &metav1.Condition{
+				Type:    ConditionPendingApproval,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonClusterAccepted,
+				Message: "The cluster is accepted by the hub.",
+			},
+		},
+		{
+			name:                 "denied cluster",
+			cluster:              testinghelpers.NewDeniedManagedCluster(),
+			expectedClusterVerbs: []string{"patch"},
+			expectedCondition: &metav1.Condition{
+				Type:    ConditionPendingApproval,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonClusterDenied,
+				Message: "The cluster is denied by the hub.",
+			},
+		},
+		{
+			name:                 "cluster with denied csr",
+			cluster:              testinghelpers.NewManagedCluster(),
+			csrs:                 []runtime.Object{testinghelpers.NewDeniedCSR(requestCSR)},
+			expectedClusterVerbs: []string{"patch"},
+			expectedCondition: &metav1.Condition{
+				Type:    ConditionPendingApproval,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonClusterDenied,
+				Message: "The cluster is denied by the hub.",
+			},
+		},
+		{
+			name: "condition is not changed",
+			cluster: func() *v1.ManagedCluster {
+				cluster := testinghelpers.NewManagedCluster()
+				cluster.Status.Conditions = []metav1.Condition{{
+					Type:    ConditionPendingApproval,
+					Status:  metav1.ConditionTrue,
+					Reason:  ReasonWaitingForApproval,
+					Message: "The cluster is waiting for the hub to accept it.",
+				}}
+				return cluster
+			}(),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusterClient := clusterfake.NewSimpleClientset(c.cluster)
+			kubeClient := kubefake.NewSimpleClientset(c.csrs...)
+			clusterInformerFactory := clusterinformers.NewSharedInformerFactory(clusterClient, time.Minute*10)
+			kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, time.Minute*10)
+			if err := clusterInformerFactory.Cluster().V1().ManagedClusters().Informer().GetStore().Add(c.cluster); err != nil {
+				t.Fatal(err)
+			}
+			csrStore := kubeInformerFactory.Certificates().V1().CertificateSigningRequests().Informer().GetStore()
+			for _, csr := range c.csrs {
+				if err := csrStore.Add(csr); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctrl := &pendingApprovalController{
+				kubeClient:    kubeClient,
+				clusterClient: clusterClient,
+				clusterLister: clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+				csrStore:      csrStore,
+				patcher: patcher.NewPatcher[
+					*v1.ManagedCluster, v1.ManagedClusterSpec, v1.ManagedClusterStatus](
+					clusterClient.ClusterV1().ManagedClusters()),
+				expiration:    c.expiration,
+				eventRecorder: eventstesting.NewTestingEventRecorder(t),
+			}
+			if err := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, c.cluster.Name)); err != nil {
+				t.Fatal(err)
+			}
+
+			testingcommon.AssertActions(t, kubeClient.Actions(), c.expectedKubeActions...)
+			testingcommon.AssertActions(t, clusterClient.Actions(), c.expectedClusterVerbs...)
+			if c.expectedCondition == nil {
+				return
+			}
+			patch := clusterClient.Actions()[0].(clienttesting.PatchAction).GetPatch()
+			managedCluster := &v1.ManagedCluster{}
+			if err := json.Unmarshal(patch, managedCluster); err != nil {
+				t.Fatal(err)
+			}
+			testingcommon.AssertCondition(t, managedCluster.Status.Conditions, *c.expectedCondition)
+		})
+	}
+}
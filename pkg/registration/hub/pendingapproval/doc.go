@@ -0,0 +1,3 @@
+// Package pendingapproval contains the controller which tracks the ManagedClusters waiting for the hub to accept
+// them, and cleans up the ones not accepted before their approval expires.
+package pendingapproval
@@ -0,0 +1,429 @@
+package workauthor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	operatorhelpers "github.com/openshift/library-go/pkg/operator/v1helpers"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	rbacv1informers "k8s.io/client-go/informers/rbac/v1"
+	"k8s.io/client-go/kubernetes"
+	rbacv1listers "k8s.io/client-go/listers/rbac/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	clientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterinformerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	clusterinformerv1beta2 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1beta2"
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	clusterlisterv1beta2 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta2"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/common/logging"
+	"open-cluster-management.io/ocm/pkg/common/patcher"
+	"open-cluster-management.io/ocm/pkg/common/queue"
+)
+
+const (
+	// the labels of the roles and the rolebindings recording the ManagedClusterSetBinding they are provisioned for.
+	clusterSetBindingNamespaceLabelKey = "cluster.open-cluster-management.io/clustersetbinding-namespace"
+	clusterSetBindingNameLabelKey      = "cluster.open-cluster-management.io/clustersetbinding-name"
+
+	// The reasons of the WorkAuthorRolesProvisioned condition.
+	ReasonWorkAuthorRolesProvisioned = "WorkAuthorRolesProvisioned"
+	ReasonBoundByUnknown             = "BoundByUnknown"
+	ReasonBindPermissionRevoked      = "BindPermissionRevoked"
+	ReasonClusterSetNotFound         = "ClusterSetNotFound"
+)
+
+// workAuthorController provisions a role and a rolebinding in the namespace of each cluster in the ManagedClusterSet
+// bound by a ManagedClusterSetBinding with the work-author-groups annotation, which allow the groups to author the
+// ManifestWorks in the namespace. The groups are only granted what the user who created the binding is allowed to,
+// so the roles are not provisioned in the namespaces where the user cannot create the ManifestWorks. The roles and
+// the rolebindings are removed once the cluster leaves the set, or the binding or its annotation is removed.
+type workAuthorController struct {
+	kubeClient              kubernetes.Interface
+	clusterClient           clientset.Interface
+	clusterLister           clusterlisterv1.ManagedClusterLister
+	clusterSetLister        clusterlisterv1beta2.ManagedClusterSetLister
+	clusterSetBindingLister clusterlisterv1beta2.ManagedClusterSetBindingLister
+	roleLister              rbacv1listers.RoleLister
+	roleBindingLister       rbacv1listers.RoleBindingLister
+	eventRecorder           events.Recorder
+}
+
+// NewWorkAuthorController creates a new work author controller.
+func NewWorkAuthorController(
+	kubeClient kubernetes.Interface,
+	clusterClient clientset.Interface,
+	clusterInformer clusterinformerv1.ManagedClusterInformer,
+	clusterSetInformer clusterinformerv1beta2.ManagedClusterSetInformer,
+	clusterSetBindingInformer clusterinformerv1beta2.ManagedClusterSetBindingInformer,
+	roleInformer rbacv1informers.RoleInformer,
+	roleBindingInformer rbacv1informers.RoleBindingInformer,
+	recorder events.Recorder) factory.Controller {
+	c := &workAuthorController{
+		kubeClient:              kubeClient,
+		clusterClient:           clusterClient,
+		clusterLister:           clusterInformer.Lister(),
+		clusterSetLister:        clusterSetInformer.Lister(),
+		clusterSetBindingLister: clusterSetBindingInformer.Lister(),
+		roleLister:              roleInformer.Lister(),
+		roleBindingLister:       roleBindingInformer.Lister(),
+		eventRecorder:           recorder.WithComponentSuffix("work-author-controller"),
+	}
+	return factory.New().
+		WithInformersQueueKeysFunc(queue.QueueKeyByMetaNamespaceName, clusterSetBindingInformer.Informer()).
+		WithInformersQueueKeysFunc(c.workAuthorBindingKeys, clusterInformer.Informer(), clusterSetInformer.Informer()).
+		WithFilteredEventsInformersQueueKeysFunc(
+			queueKeyByClusterSetBindingLabels,
+			hasClusterSetBindingLabels,
+			roleInformer.Informer(), roleBindingInformer.Informer()).
+		WithSync(logging.WithControllerLogger("WorkAuthorController", c.sync)).
+		ToController("WorkAuthorController", recorder)
+}
+
+func (c *workAuthorController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	key := syncCtx.QueueKey()
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling work author roles of ManagedClusterSetBinding", "key", key)
+
+	bindingNamespace, bindingName, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		// ignore the key which is not in format of namespace/name
+		return nil
+	}
+
+	binding, err := c.clusterSetBindingLister.ManagedClusterSetBindings(bindingNamespace).Get(bindingName)
+	switch {
+	case errors.IsNotFound(err):
+		binding = nil
+	case err != nil:
+		return err
+	}
+
+	var groups []string
+	if binding != nil && binding.DeletionTimestamp.IsZero() {
+		groups = commonhelpers.GetClusterSetBindingWorkAuthorGroups(binding)
+	}
+
+	clusterNames := sets.New[string]()
+	var condition *metav1.Condition
+	if len(groups) > 0 {
+		clusterNames, condition, err = c.authorizedClusters(ctx, binding, groups)
+		if err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	for _, clusterName := range sets.List(clusterNames) {
+		if err := c.applyRole(ctx, syncCtx.Recorder(), newRole(bindingNamespace, bindingName, clusterName)); err != nil {
+			errs = append(errs, err)
+		}
+		if err := c.applyRoleBinding(ctx, syncCtx.Recorder(),
+			newRoleBinding(bindingNamespace, bindingName, clusterName, groups)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := c.removeStaleRoles(ctx, bindingNamespace, bindingName, clusterNames); err != nil {
+		errs = append(errs, err)
+	}
+
+	if binding == nil {
+		return operatorhelpers.NewMultiLineAggregate(errs)
+	}
+
+	bindingCopy := binding.DeepCopy()
+	if condition == nil {
+		meta.RemoveStatusCondition(&bindingCopy.Status.Conditions,
+			commonhelpers.ClusterSetBindingConditionWorkAuthorRolesProvisioned)
+	} else {
+		meta.SetStatusCondition(&bindingCopy.Status.Conditions, *condition)
+	}
+	patcher := patcher.NewPatcher[
+		*clusterv1beta2.ManagedClusterSetBinding, clusterv1beta2.ManagedClusterSetBindingSpec, clusterv1beta2.ManagedClusterSetBindingStatus](
+		c.clusterClient.ClusterV1beta2().ManagedClusterSetBindings(bindingNamespace))
+	if _, err := patcher.PatchStatus(ctx, bindingCopy, bindingCopy.Status, binding.Status); err != nil {
+		errs = append(errs, err)
+	}
+
+	return operatorhelpers.NewMultiLineAggregate(errs)
+}
+
+// authorizedClusters returns the accepted clusters of the bound ManagedClusterSet, in whose namespaces the user who
+// created the binding is allowed to create the ManifestWorks, and the condition of the binding.
+func (c *workAuthorController) authorizedClusters(ctx context.Context,
+	binding *clusterv1beta2.ManagedClusterSetBinding, groups []string) (sets.Set[string], *metav1.Condition, error) {
+	clusterNames := sets.New[string]()
+	condition := &metav1.Condition{
+		Type:   commonhelpers.ClusterSetBindingConditionWorkAuthorRolesProvisioned,
+		Status: metav1.ConditionFalse,
+	}
+
+	userInfo, err := commonhelpers.GetClusterSetBindingBoundBy(binding)
+	if err != nil || userInfo == nil {
+		condition.Reason = ReasonBoundByUnknown
+		condition.Message = "The user who created the binding is unknown, the work author roles are not provisioned"
+		return clusterNames, condition, nil
+	}
+	if meta.IsStatusConditionTrue(binding.Status.Conditions, commonhelpers.ClusterSetBindingConditionPermissionDenied) {
+		condition.Reason = ReasonBindPermissionRevoked
+		condition.Message = fmt.Sprintf("User %q is not allowed to bind cluster set %q anymore, "+
+			"the work author roles are not provisioned", userInfo.Username, binding.Spec.ClusterSet)
+		return clusterNames, condition, nil
+	}
+
+	clusterSet, err := c.clusterSetLister.Get(binding.Spec.ClusterSet)
+	switch {
+	case errors.IsNotFound(err):
+		condition.Reason = ReasonClusterSetNotFound
+		condition.Message = fmt.Sprintf("Cluster set %q is not found", binding.Spec.ClusterSet)
+		return clusterNames, condition, nil
+	case err != nil:
+		return nil, nil, err
+	}
+
+	clusters, err := commonhelpers.GetClustersFromNestedClusterSet(clusterSet, c.clusterLister, c.clusterSetLister)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// check the permission of the user in the namespaces of the clusters only if the user cannot create the
+	// ManifestWorks in all namespaces.
+	allowedInAllNamespaces, err := c.allowedToCreateWorks(ctx, userInfo, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	denied := 0
+	for _, cluster := range clusters {
+		// the namespace of the cluster is created once the cluster is accepted
+		if !cluster.DeletionTimestamp.IsZero() ||
+			!meta.IsStatusConditionTrue(cluster.Status.Conditions, clusterv1.ManagedClusterConditionHubAccepted) {
+			continue
+		}
+		if !allowedInAllNamespaces {
+			allowed, err := c.allowedToCreateWorks(ctx, userInfo, cluster.Name)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !allowed {
+				denied++
+				continue
+			}
+		}
+		clusterNames.Insert(cluster.Name)
+	}
+
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = ReasonWorkAuthorRolesProvisioned
+	condition.Message = fmt.Sprintf("The work author roles of groups %q are provisioned in %d cluster namespaces",
+		strings.Join(groups, ","), clusterNames.Len())
+	if denied > 0 {
+		condition.Message = fmt.Sprintf("%s, %d cluster namespaces are skipped since user %q is not allowed to create "+
+			"ManifestWorks in them", condition.Message, denied, userInfo.Username)
+	}
+	return clusterNames, condition, nil
+}
+
+func (c *workAuthorController) allowedToCreateWorks(ctx context.Context,
+	userInfo *authenticationv1.UserInfo, namespace string) (bool, error) {
+	sar, err := c.kubeClient.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   userInfo.Username,
+			UID:    userInfo.UID,
+			Groups: userInfo.Groups,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Group:     workv1.GroupName,
+				Resource:  "manifestworks",
+				Verb:      "create",
+				Namespace: namespace,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return sar.Status.Allowed, nil
+}
+
+// applyRole applies the role if it is different from the one in the cache, so the roles are not read from the
+// apiserver each time the binding is reconciled.
+func (c *workAuthorController) applyRole(ctx context.Context, recorder events.Recorder, required *rbacv1.Role) error {
+	existing, err := c.roleLister.Roles(required.Namespace).Get(required.Name)
+	if err == nil && labelsApplied(required.Labels, existing.Labels) &&
+		equality.Semantic.DeepEqual(required.Rules, existing.Rules) {
+		return nil
+	}
+	_, _, err = resourceapply.ApplyRole(ctx, c.kubeClient.RbacV1(), recorder, required)
+	return err
+}
+
+// applyRoleBinding applies the rolebinding if it is different from the one in the cache.
+func (c *workAuthorController) applyRoleBinding(ctx context.Context, recorder events.Recorder, required *rbacv1.RoleBinding) error {
+	existing, err := c.roleBindingLister.RoleBindings(required.Namespace).Get(required.Name)
+	if err == nil && labelsApplied(required.Labels, existing.Labels) &&
+		equality.Semantic.DeepEqual(required.Subjects, existing.Subjects) &&
+		equality.Semantic.DeepEqual(required.RoleRef, existing.RoleRef) {
+		return nil
+	}
+	_, _, err = resourceapply.ApplyRoleBinding(ctx, c.kubeClient.RbacV1(), recorder, required)
+	return err
+}
+
+// removeStaleRoles removes the roles and the rolebindings provisioned for the binding in the namespaces of the
+// clusters which are not authorized anymore.
+func (c *workAuthorController) removeStaleRoles(ctx context.Context,
+	bindingNamespace, bindingName string, clusterNames sets.Set[string]) error {
+	selector := labels.SelectorFromSet(clusterSetBindingLabels(bindingNamespace, bindingName))
+
+	var errs []error
+	roleBindings, err := c.roleBindingLister.List(selector)
+	if err != nil {
+		return err
+	}
+	for _, roleBinding := range roleBindings {
+		if clusterNames.Has(roleBinding.Namespace) {
+			continue
+		}
+		err := c.kubeClient.RbacV1().RoleBindings(roleBinding.Namespace).Delete(ctx, roleBinding.Name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			errs = append(errs, err)
+			continue
+		}
+		c.eventRecorder.Eventf("RoleBindingDeleted", "Deleted rolebinding %s/%s of ManagedClusterSetBinding %s/%s",
+			roleBinding.Namespace, roleBinding.Name, bindingNamespace, bindingName)
+	}
+
+	roles, err := c.roleLister.List(selector)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if clusterNames.Has(role.Namespace) {
+			continue
+		}
+		err := c.kubeClient.RbacV1().Roles(role.Namespace).Delete(ctx, role.Name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			errs = append(errs, err)
+			continue
+		}
+		c.eventRecorder.Eventf("RoleDeleted", "Deleted role %s/%s of ManagedClusterSetBinding %s/%s",
+			role.Namespace, role.Name, bindingNamespace, bindingName)
+	}
+
+	return operatorhelpers.NewMultiLineAggregate(errs)
+}
+
+// workAuthorBindingKeys returns the keys of all the ManagedClusterSetBindings with the work-author-groups
+// annotation, since the change of a cluster or a clusterset may change the clusters bound by any of them.
+func (c *workAuthorController) workAuthorBindingKeys(_ runtime.Object) []string {
+	bindings, err := c.clusterSetBindingLister.List(labels.Everything())
+	if err != nil {
+		return []string{}
+	}
+	var keys []string
+	for _, binding := range bindings {
+		if _, ok := binding.Annotations[commonhelpers.ClusterSetBindingWorkAuthorGroupsAnnotationKey]; !ok {
+			continue
+		}
+		key, _ := cache.MetaNamespaceKeyFunc(binding)
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func queueKeyByClusterSetBindingLabels(obj runtime.Object) []string {
+	accessor, _ := meta.Accessor(obj)
+	objLabels := accessor.GetLabels()
+	return []string{fmt.Sprintf("%s/%s",
+		objLabels[clusterSetBindingNamespaceLabelKey], objLabels[clusterSetBindingNameLabelKey])}
+}
+
+func hasClusterSetBindingLabels(obj interface{}) bool {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	return len(accessor.GetLabels()[clusterSetBindingNamespaceLabelKey]) > 0 &&
+		len(accessor.GetLabels()[clusterSetBindingNameLabelKey]) > 0
+}
+
+func labelsApplied(required, existing map[string]string) bool {
+	for key, value := range required {
+		if existing[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func clusterSetBindingLabels(bindingNamespace, bindingName string) map[string]string {
+	return map[string]string{
+		clusterSetBindingNamespaceLabelKey: bindingNamespace,
+		clusterSetBindingNameLabelKey:      bindingName,
+	}
+}
+
+func workAuthorRoleName(bindingNamespace, bindingName string) string {
+	return fmt.Sprintf("open-cluster-management:work-author:%s:%s", bindingNamespace, bindingName)
+}
+
+func newObjectMeta(bindingNamespace, bindingName, clusterName string) metav1.ObjectMeta {
+	objLabels := clusterSetBindingLabels(bindingNamespace, bindingName)
+	// the rbac resources of the hub cached by the registration have the cluster name label
+	objLabels[clusterv1.ClusterNameLabelKey] = clusterName
+	return metav1.ObjectMeta{
+		Name:      workAuthorRoleName(bindingNamespace, bindingName),
+		Namespace: clusterName,
+		Labels:    objLabels,
+	}
+}
+
+func newRole(bindingNamespace, bindingName, clusterName string) *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: newObjectMeta(bindingNamespace, bindingName, clusterName),
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{workv1.GroupName},
+				Resources: []string{"manifestworks"},
+				Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+			},
+		},
+	}
+}
+
+func newRoleBinding(bindingNamespace, bindingName, clusterName string, groups []string) *rbacv1.RoleBinding {
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: newObjectMeta(bindingNamespace, bindingName, clusterName),
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     workAuthorRoleName(bindingNamespace, bindingName),
+		},
+	}
+	for _, group := range groups {
+		roleBinding.Subjects = append(roleBinding.Subjects, rbacv1.Subject{
+			APIGroup: rbacv1.GroupName,
+			Kind:     rbacv1.GroupKind,
+			Name:     group,
+		})
+	}
+	return roleBinding
+}
@@ -0,0 +1,277 @@
+package workauthor
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events/eventstesting"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+)
+
+func TestSync(t *testing.T) {
+	cases := []struct {
+		name                  string
+		binding               *clusterv1beta2.ManagedClusterSetBinding
+		clusters              []*clusterv1.ManagedCluster
+		roles                 []runtime.Object
+		allowedNamespaces     map[string]bool
+		validateKubeActions   func(t *testing.T, actions []clienttesting.Action)
+		validateStatusActions func(t *testing.T, actions []clienttesting.Action)
+	}{
+		{
+			name:    "binding is deleted",
+			binding: nil,
+			roles: []runtime.Object{
+				newRole("ns1", "set1", "cluster1"),
+				newRoleBinding("ns1", "set1", "cluster1", []string{"team1"}),
+			},
+			validateKubeActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "delete", "delete")
+			},
+			validateStatusActions: testingcommon.AssertNoActions,
+		},
+		{
+			name:    "no work author groups",
+			binding: newBinding(t, "", true),
+			roles: []runtime.Object{
+				newRole("ns1", "set1", "cluster1"),
+				newRoleBinding("ns1", "set1", "cluster1", []string{"team1"}),
+				newRoleBinding("ns2", "set1", "cluster1", []string{"team1"}),
+			},
+			validateKubeActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "delete", "delete")
+			},
+			validateStatusActions: testingcommon.AssertNoActions,
+		},
+		{
+			name:                "binding creator is unknown",
+			binding:             newBinding(t, "team1", false),
+			clusters:            []*clusterv1.ManagedCluster{newCluster("cluster1", true)},
+			validateKubeActions: testingcommon.AssertNoActions,
+			validateStatusActions: func(t *testing.T, actions []clienttesting.Action) {
+				assertBindingCondition(t, actions, metav1.Condition{
+					Type:    commonhelpers.ClusterSetBindingConditionWorkAuthorRolesProvisioned,
+					Status:  metav1.ConditionFalse,
+					Reason:  ReasonBoundByUnknown,
+					Message: "The user who created the binding is unknown, the work author roles are not provisioned",
+				})
+			},
+		},
+		{
+			name:    "provision roles in the namespaces the creator is allowed to",
+			binding: newBinding(t, "team2, team1", true),
+			clusters: []*clusterv1.ManagedCluster{
+				newCluster("cluster1", true),
+				newCluster("cluster2", false),
+				newCluster("cluster3", true),
+			},
+			roles: []runtime.Object{
+				newRole("ns1", "set1", "cluster3"),
+			},
+			allowedNamespaces: map[string]bool{"cluster1": true},
+			validateKubeActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "create", "create", "create", "get", "create", "get", "create", "delete")
+				roleBinding := actions[6].(clienttesting.CreateActionImpl).Object.(*rbacv1.RoleBinding)
+				if roleBinding.Namespace != "cluster1" || len(roleBinding.Subjects) != 2 || roleBinding.Subjects[0].Name != "team1" {
+					t.Errorf("unexpected rolebinding %v", roleBinding)
+				}
+			},
+			validateStatusActions: func(t *testing.T, actions []clienttesting.Action) {
+				assertBindingCondition(t, actions, metav1.Condition{
+					Type:   commonhelpers.ClusterSetBindingConditionWorkAuthorRolesProvisioned,
+					Status: metav1.ConditionTrue,
+					Reason: ReasonWorkAuthorRolesProvisioned,
+					Message: `The work author roles of groups "team1,team2" are provisioned in 1 cluster namespaces, ` +
+						`1 cluster namespaces are skipped since user "user1" is not allowed to create ManifestWorks in them`,
+				})
+			},
+		},
+		{
+			name:     "roles are provisioned already",
+			binding:  newBinding(t, "team1", true),
+			clusters: []*clusterv1.ManagedCluster{newCluster("cluster1", true)},
+			roles: []runtime.Object{
+				newRole("ns1", "set1", "cluster1"),
+				newRoleBinding("ns1", "set1", "cluster1", []string{"team1"}),
+			},
+			allowedNamespaces: map[string]bool{"": true},
+			validateKubeActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "create")
+			},
+			validateStatusActions: func(t *testing.T, actions []clienttesting.Action) {
+				assertBindingCondition(t, actions, metav1.Condition{
+					Type:    commonhelpers.ClusterSetBindingConditionWorkAuthorRolesProvisioned,
+					Status:  metav1.ConditionTrue,
+					Reason:  ReasonWorkAuthorRolesProvisioned,
+					Message: `The work author roles of groups "team1" are provisioned in 1 cluster namespaces`,
+				})
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset(c.roles...)
+			kubeClient.PrependReactor(
+				"create",
+				"subjectaccessreviews",
+				func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+					sar := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+					return true, &authorizationv1.SubjectAccessReview{
+						Status: authorizationv1.SubjectAccessReviewStatus{
+							Allowed: c.allowedNamespaces[sar.Spec.ResourceAttributes.Namespace],
+						},
+					}, nil
+				},
+			)
+			kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, 5*time.Minute)
+			for _, obj := range c.roles {
+				switch obj.(type) {
+				case *rbacv1.Role:
+					if err := kubeInformerFactory.Rbac().V1().Roles().Informer().GetStore().Add(obj); err != nil {
+						t.Fatal(err)
+					}
+				case *rbacv1.RoleBinding:
+					if err := kubeInformerFactory.Rbac().V1().RoleBindings().Informer().GetStore().Add(obj); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			var objects []runtime.Object
+			if c.binding != nil {
+				objects = append(objects, c.binding)
+			}
+			clusterClient := clusterfake.NewSimpleClientset(objects...)
+			clusterInformerFactory := clusterinformers.NewSharedInformerFactory(clusterClient, 5*time.Minute)
+			if err := clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets().Informer().GetStore().Add(
+				&clusterv1beta2.ManagedClusterSet{ObjectMeta: metav1.ObjectMeta{Name: "set1"}}); err != nil {
+				t.Fatal(err)
+			}
+			if c.binding != nil {
+				if err := clusterInformerFactory.Cluster().V1beta2().ManagedClusterSetBindings().Informer().GetStore().Add(c.binding); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, cluster := range c.clusters {
+				if err := clusterInformerFactory.Cluster().V1().ManagedClusters().Informer().GetStore().Add(cluster); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctrl := &workAuthorController{
+				kubeClient:              kubeClient,
+				clusterClient:           clusterClient,
+				clusterLister:           clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+				clusterSetLister:        clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets().Lister(),
+				clusterSetBindingLister: clusterInformerFactory.Cluster().V1beta2().ManagedClusterSetBindings().Lister(),
+				roleLister:              kubeInformerFactory.Rbac().V1().Roles().Lister(),
+				roleBindingLister:       kubeInformerFactory.Rbac().V1().RoleBindings().Lister(),
+				eventRecorder:           eventstesting.NewTestingEventRecorder(t),
+			}
+
+			if err := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, "ns1/set1")); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			c.validateKubeActions(t, kubeClient.Actions())
+			c.validateStatusActions(t, clusterClient.Actions())
+		})
+	}
+}
+
+func TestQueueKeys(t *testing.T) {
+	roleBinding := newRoleBinding("ns1", "set1", "cluster1", []string{"team1"})
+	if !hasClusterSetBindingLabels(roleBinding) {
+		t.Errorf("expected the rolebinding is provisioned for a binding")
+	}
+	if keys := queueKeyByClusterSetBindingLabels(roleBinding); len(keys) != 1 || keys[0] != "ns1/set1" {
+		t.Errorf("expected key ns1/set1, but got %v", keys)
+	}
+	if hasClusterSetBindingLabels(&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "cluster1"}}) {
+		t.Errorf("expected the rolebinding is not provisioned for a binding")
+	}
+
+	clusterClient := clusterfake.NewSimpleClientset()
+	clusterInformerFactory := clusterinformers.NewSharedInformerFactory(clusterClient, 5*time.Minute)
+	bindingStore := clusterInformerFactory.Cluster().V1beta2().ManagedClusterSetBindings().Informer().GetStore()
+	if err := bindingStore.Add(newBinding(t, "team1", true)); err != nil {
+		t.Fatal(err)
+	}
+	if err := bindingStore.Add(&clusterv1beta2.ManagedClusterSetBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "set2", Namespace: "ns1"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	ctrl := &workAuthorController{
+		clusterSetBindingLister: clusterInformerFactory.Cluster().V1beta2().ManagedClusterSetBindings().Lister(),
+	}
+	if keys := ctrl.workAuthorBindingKeys(newCluster("cluster1", true)); len(keys) != 1 || keys[0] != "ns1/set1" {
+		t.Errorf("expected key ns1/set1, but got %v", keys)
+	}
+}
+
+func newBinding(t *testing.T, groups string, boundBy bool) *clusterv1beta2.ManagedClusterSetBinding {
+	binding := &clusterv1beta2.ManagedClusterSetBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "set1",
+			Namespace:   "ns1",
+			Annotations: map[string]string{},
+		},
+		Spec: clusterv1beta2.ManagedClusterSetBindingSpec{
+			ClusterSet: "set1",
+		},
+	}
+	if len(groups) > 0 {
+		binding.Annotations[commonhelpers.ClusterSetBindingWorkAuthorGroupsAnnotationKey] = groups
+	}
+	if boundBy {
+		if err := commonhelpers.SetClusterSetBindingBoundBy(binding, authenticationv1.UserInfo{Username: "user1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return binding
+}
+
+func newCluster(name string, accepted bool) *clusterv1.ManagedCluster {
+	cluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{clusterv1beta2.ClusterSetLabel: "set1"},
+		},
+	}
+	if accepted {
+		cluster.Status.Conditions = []metav1.Condition{
+			{
+				Type:   clusterv1.ManagedClusterConditionHubAccepted,
+				Status: metav1.ConditionTrue,
+			},
+		}
+	}
+	return cluster
+}
+
+func assertBindingCondition(t *testing.T, actions []clienttesting.Action, expected metav1.Condition) {
+	testingcommon.AssertActions(t, actions, "patch")
+	binding := &clusterv1beta2.ManagedClusterSetBinding{}
+	if err := json.Unmarshal(actions[0].(clienttesting.PatchActionImpl).Patch, binding); err != nil {
+		t.Fatal(err)
+	}
+	testingcommon.AssertCondition(t, binding.Status.Conditions, expected)
+}
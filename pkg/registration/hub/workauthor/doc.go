@@ -0,0 +1,3 @@
+// Package workauthor contains the controller which provisions the roles of the groups allowed to author the
+// ManifestWorks in the namespaces of the clusters bound by the ManagedClusterSetBindings.
+package workauthor
@@ -21,6 +21,7 @@ import (
 	addoninformerv1alpha1 "open-cluster-management.io/api/client/addon/informers/externalversions/addon/v1alpha1"
 	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 )
 
@@ -69,7 +70,7 @@ func NewManagedClusterAddOnLeaseController(clusterName string,
 	// informer cache sync and result in fatal exit of this controller. The code will be factored
 	// when we no longer support kubernetes version lower than 1.17.
 	return factory.New().
-		WithSync(c.sync).
+		WithSync(logging.WithControllerLogger("ManagedClusterAddOnLeaseController", c.sync)).
 		ResyncEvery(resyncInterval).
 		ToController("ManagedClusterAddOnLeaseController", recorder)
 }
@@ -3,6 +3,7 @@ package addon
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/openshift/library-go/pkg/controller/factory"
@@ -24,6 +25,7 @@ import (
 	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/registration/clientcert"
@@ -56,8 +58,9 @@ type addOnRegistrationController struct {
 	startRegistrationFunc func(ctx context.Context, config registrationConfig) context.CancelFunc
 
 	// registrationConfigs maps the addon name to a map of registrationConfigs whose key is the hash of
-	// the registrationConfig
+	// the registrationConfig. It is guarded by configsLock since the addons are synced by multiple workers.
 	addOnRegistrationConfigs map[string]map[string]registrationConfig
+	configsLock              sync.RWMutex
 }
 
 // NewAddOnRegistrationController returns an instance of addOnRegistrationController
@@ -101,7 +104,7 @@ func NewAddOnRegistrationController(
 		WithInformersQueueKeysFunc(
 			queue.QueueKeyByMetaName,
 			hubAddOnInformers.Informer()).
-		WithSync(c.sync).
+		WithSync(logging.WithControllerLogger("AddOnRegistrationController", c.sync)).
 		ResyncEvery(10*time.Minute).
 		ToController("AddOnRegistrationController", recorder)
 }
@@ -115,7 +118,7 @@ func (c *addOnRegistrationController) sync(ctx context.Context, syncCtx factory.
 
 	// handle resync
 	var errs []error
-	for addOnName := range c.addOnRegistrationConfigs {
+	for _, addOnName := range c.registeredAddOnNames() {
 		_, err := c.hubAddOnLister.ManagedClusterAddOns(c.clusterName).Get(addOnName)
 		if err == nil {
 			syncCtx.Queue().Add(addOnName)
@@ -151,7 +154,7 @@ func (c *addOnRegistrationController) syncAddOn(ctx context.Context, syncCtx fac
 		return nil
 	}
 
-	cachedConfigs := c.addOnRegistrationConfigs[addOnName]
+	cachedConfigs := c.getRegistrationConfigs(addOnName)
 	configs, err := getRegistrationConfigs(addOn)
 	if err != nil {
 		return err
@@ -185,12 +188,36 @@ func (c *addOnRegistrationController) syncAddOn(ctx context.Context, syncCtx fac
 		syncedConfigs[hash] = config
 	}
 
-	if len(syncedConfigs) == 0 {
+	c.setRegistrationConfigs(addOnName, syncedConfigs)
+	return nil
+}
+
+func (c *addOnRegistrationController) registeredAddOnNames() []string {
+	c.configsLock.RLock()
+	defer c.configsLock.RUnlock()
+	addOnNames := make([]string, 0, len(c.addOnRegistrationConfigs))
+	for addOnName := range c.addOnRegistrationConfigs {
+		addOnNames = append(addOnNames, addOnName)
+	}
+	return addOnNames
+}
+
+func (c *addOnRegistrationController) getRegistrationConfigs(addOnName string) map[string]registrationConfig {
+	c.configsLock.RLock()
+	defer c.configsLock.RUnlock()
+	return c.addOnRegistrationConfigs[addOnName]
+}
+
+// setRegistrationConfigs caches the registration configs of the addon, the addon is removed from the cache if it
+// has no registration config.
+func (c *addOnRegistrationController) setRegistrationConfigs(addOnName string, configs map[string]registrationConfig) {
+	c.configsLock.Lock()
+	defer c.configsLock.Unlock()
+	if len(configs) == 0 {
 		delete(c.addOnRegistrationConfigs, addOnName)
-		return nil
+		return
 	}
-	c.addOnRegistrationConfigs[addOnName] = syncedConfigs
-	return nil
+	c.addOnRegistrationConfigs[addOnName] = configs
 }
 
 // startRegistration starts a client certificate controller with the given config
@@ -316,7 +343,7 @@ func (c *addOnRegistrationController) stopRegistration(ctx context.Context, conf
 // cleanup cleans both the registration configs and client certificate controllers for the addon
 func (c *addOnRegistrationController) cleanup(ctx context.Context, addOnName string) error {
 	var errs []error
-	for _, config := range c.addOnRegistrationConfigs[addOnName] {
+	for _, config := range c.getRegistrationConfigs(addOnName) {
 		if err := c.stopRegistration(ctx, config); err != nil {
 			errs = append(errs, err)
 		}
@@ -326,7 +353,7 @@ func (c *addOnRegistrationController) cleanup(ctx context.Context, addOnName str
 		return err
 	}
 
-	delete(c.addOnRegistrationConfigs, addOnName)
+	c.setRegistrationConfigs(addOnName, nil)
 	return nil
 }
 
@@ -17,6 +17,8 @@ import (
 	clusterv1informer "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
 	clusterv1listers "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/logging"
 )
 
 const leaseUpdateJitterFactor = 0.25
@@ -48,7 +50,7 @@ func NewManagedClusterLeaseController(
 
 	return factory.New().
 		WithInformers(hubClusterInformer.Informer()).
-		WithSync(c.sync).
+		WithSync(logging.WithControllerLogger("ManagedClusterLeaseController", c.sync)).
 		ToController("ManagedClusterLeaseController", recorder)
 }
 
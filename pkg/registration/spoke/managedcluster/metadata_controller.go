@@ -0,0 +1,95 @@
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	clusterv1informer "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	clusterv1listers "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/logging"
+)
+
+const (
+	// ClusterMetadataKeyPrefix is the prefix of the labels and annotations on the ManagedCluster which are mirrored
+	// into the cluster metadata ConfigMap on the managed cluster, the key of the ConfigMap data is the label or the
+	// annotation key without the prefix, e.g. the annotation spoke.open-cluster-management.io/maintenance-window is
+	// mirrored as maintenance-window. The annotation wins if a label and an annotation have the same key.
+	ClusterMetadataKeyPrefix = "spoke.open-cluster-management.io/"
+
+	// ClusterMetadataConfigMapName is the name of the ConfigMap on the managed cluster which the registration agent
+	// mirrors the metadata of the ManagedCluster into.
+	ClusterMetadataConfigMapName = "managed-cluster-metadata"
+
+	metadataResyncInterval = 5 * time.Minute
+)
+
+// managedClusterMetadataController mirrors the labels and the annotations of the ManagedCluster on the hub with the
+// prefix ClusterMetadataKeyPrefix into a ConfigMap on the managed cluster, so the tools on the managed cluster are
+// able to react to the metadata declared on the hub.
+type managedClusterMetadataController struct {
+	clusterName      string
+	namespace        string
+	hubClusterLister clusterv1listers.ManagedClusterLister
+	spokeKubeClient  kubernetes.Interface
+	recorder         events.Recorder
+}
+
+// NewManagedClusterMetadataController creates a new managed cluster metadata controller on the managed cluster.
+func NewManagedClusterMetadataController(
+	clusterName, namespace string,
+	hubClusterInformer clusterv1informer.ManagedClusterInformer,
+	spokeKubeClient kubernetes.Interface,
+	recorder events.Recorder) factory.Controller {
+	c := &managedClusterMetadataController{
+		clusterName:      clusterName,
+		namespace:        namespace,
+		hubClusterLister: hubClusterInformer.Lister(),
+		spokeKubeClient:  spokeKubeClient,
+		recorder:         recorder,
+	}
+
+	return factory.New().
+		WithInformers(hubClusterInformer.Informer()).
+		WithSync(logging.WithControllerLogger("ManagedClusterMetadataController", c.sync)).
+		// the ConfigMap is not watched, resync to restore it once it is changed or deleted on the managed cluster.
+		ResyncEvery(metadataResyncInterval).
+		ToController("ManagedClusterMetadataController", recorder)
+}
+
+func (c *managedClusterMetadataController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	cluster, err := c.hubClusterLister.Get(c.clusterName)
+	if err != nil {
+		return fmt.Errorf("unable to get managed cluster %q from hub: %w", c.clusterName, err)
+	}
+
+	data := map[string]string{}
+	for key, value := range cluster.Labels {
+		if name, ok := strings.CutPrefix(key, ClusterMetadataKeyPrefix); ok && len(name) > 0 {
+			data[name] = value
+		}
+	}
+	for key, value := range cluster.Annotations {
+		if name, ok := strings.CutPrefix(key, ClusterMetadataKeyPrefix); ok && len(name) > 0 {
+			data[name] = value
+		}
+	}
+
+	_, _, err = resourceapply.ApplyConfigMap(ctx, c.spokeKubeClient.CoreV1(), c.recorder, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ClusterMetadataConfigMapName,
+			Namespace: c.namespace,
+		},
+		Data: data,
+	})
+	return err
+}
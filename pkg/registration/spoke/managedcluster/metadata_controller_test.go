@@ -0,0 +1,146 @@
+package managedcluster
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events/eventstesting"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+)
+
+func newMetadataConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ClusterMetadataConfigMapName,
+			Namespace: "open-cluster-management-agent",
+		},
+		Data: data,
+	}
+}
+
+func TestSyncClusterMetadata(t *testing.T) {
+	cases := []struct {
+		name            string
+		cluster         *clusterv1.ManagedCluster
+		configMaps      []runtime.Object
+		expectedActions []string
+		expectedData    map[string]string
+		expectedErr     string
+	}{
+		{
+			name: "sync no managed cluster",
+			expectedErr: "unable to get managed cluster \"testmanagedcluster\" " +
+				"from hub: managedcluster.cluster.open-cluster-management.io \"testmanagedcluster\" not found",
+		},
+		{
+			name:            "create the configmap without metadata",
+			cluster:         testinghelpers.NewAcceptedManagedCluster(),
+			expectedActions: []string{"get", "create"},
+			expectedData:    map[string]string{},
+		},
+		{
+			name: "create the configmap with metadata",
+			cluster: func() *clusterv1.ManagedCluster {
+				cluster := testinghelpers.NewAcceptedManagedCluster()
+				cluster.Labels = map[string]string{
+					"spoke.open-cluster-management.io/tier": "gold",
+					"spoke.open-cluster-management.io/zone": "east",
+					"env":                                   "prod",
+				}
+				cluster.Annotations = map[string]string{
+					"spoke.open-cluster-management.io/maintenance-window": "Sat 02:00-04:00",
+					"spoke.open-cluster-management.io/zone":               "west",
+					"spoke.open-cluster-management.io/":                   "ignored",
+				}
+				return cluster
+			}(),
+			expectedActions: []string{"get", "create"},
+			expectedData: map[string]string{
+				"tier":               "gold",
+				"zone":               "west",
+				"maintenance-window": "Sat 02:00-04:00",
+			},
+		},
+		{
+			name: "update the configmap",
+			cluster: func() *clusterv1.ManagedCluster {
+				cluster := testinghelpers.NewAcceptedManagedCluster()
+				cluster.Labels = map[string]string{"spoke.open-cluster-management.io/tier": "silver"}
+				return cluster
+			}(),
+			configMaps:      []runtime.Object{newMetadataConfigMap(map[string]string{"tier": "gold", "zone": "east"})},
+			expectedActions: []string{"get", "update"},
+			expectedData:    map[string]string{"tier": "silver"},
+		},
+		{
+			name: "the configmap is up to date",
+			cluster: func() *clusterv1.ManagedCluster {
+				cluster := testinghelpers.NewAcceptedManagedCluster()
+				cluster.Labels = map[string]string{"spoke.open-cluster-management.io/tier": "gold"}
+				return cluster
+			}(),
+			configMaps:      []runtime.Object{newMetadataConfigMap(map[string]string{"tier": "gold"})},
+			expectedActions: []string{"get"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var clusters []runtime.Object
+			if c.cluster != nil {
+				clusters = append(clusters, c.cluster)
+			}
+			clusterClient := clusterfake.NewSimpleClientset(clusters...)
+			clusterInformerFactory := clusterinformers.NewSharedInformerFactory(clusterClient, time.Minute*10)
+			clusterStore := clusterInformerFactory.Cluster().V1().ManagedClusters().Informer().GetStore()
+			for _, cluster := range clusters {
+				if err := clusterStore.Add(cluster); err != nil {
+					t.Fatal(err)
+				}
+			}
+			kubeClient := kubefake.NewSimpleClientset(c.configMaps...)
+
+			ctrl := &managedClusterMetadataController{
+				clusterName:      testinghelpers.TestManagedClusterName,
+				namespace:        "open-cluster-management-agent",
+				hubClusterLister: clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+				spokeKubeClient:  kubeClient,
+				recorder:         eventstesting.NewTestingEventRecorder(t),
+			}
+
+			syncErr := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, ""))
+			testingcommon.AssertError(t, syncErr, c.expectedErr)
+
+			actions := kubeClient.Actions()
+			testingcommon.AssertActions(t, actions, c.expectedActions...)
+			if len(actions) == 0 {
+				return
+			}
+			var configMap *corev1.ConfigMap
+			switch action := actions[len(actions)-1].(type) {
+			case clienttesting.CreateActionImpl:
+				configMap = action.Object.(*corev1.ConfigMap)
+			case clienttesting.UpdateActionImpl:
+				configMap = action.Object.(*corev1.ConfigMap)
+			default:
+				return
+			}
+			if !reflect.DeepEqual(configMap.Data, c.expectedData) {
+				t.Errorf("expected data %v, but got %v", c.expectedData, configMap.Data)
+			}
+		})
+	}
+}
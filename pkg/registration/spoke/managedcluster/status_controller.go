@@ -17,6 +17,7 @@ import (
 	clusterv1listers "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 )
 
@@ -64,7 +65,7 @@ func NewManagedClusterStatusController(
 
 	return factory.New().
 		WithInformers(hubClusterInformer.Informer(), nodeInformer.Informer(), claimInformer.Informer()).
-		WithSync(c.sync).
+		WithSync(logging.WithControllerLogger("ManagedClusterStatusController", c.sync)).
 		ResyncEvery(resyncInterval).
 		ToController("ManagedClusterStatusController", recorder)
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
+	certificatesv1 "k8s.io/api/certificates/v1"
 
 	"open-cluster-management.io/ocm/pkg/registration/helpers"
 )
@@ -19,6 +20,12 @@ type SpokeAgentOptions struct {
 	MaxCustomClusterClaims      int
 	ClientCertExpirationSeconds int32
 	ClusterAnnotations          map[string]string
+	ResourceUsageScoreInterval  time.Duration
+	ClusterMetadataNamespace    string
+	CSRSignerName               string
+	ClusterStatusWorkers        int
+	LeaseWorkers                int
+	AddOnRegistrationWorkers    int
 }
 
 func NewSpokeAgentOptions() *SpokeAgentOptions {
@@ -26,6 +33,10 @@ func NewSpokeAgentOptions() *SpokeAgentOptions {
 		HubKubeconfigSecret:      "hub-kubeconfig-secret",
 		ClusterHealthCheckPeriod: 1 * time.Minute,
 		MaxCustomClusterClaims:   20,
+		CSRSignerName:            certificatesv1.KubeAPIServerClientSignerName,
+		ClusterStatusWorkers:     1,
+		LeaseWorkers:             1,
+		AddOnRegistrationWorkers: 1,
 	}
 }
 
@@ -46,6 +57,22 @@ func (o *SpokeAgentOptions) AddFlags(fs *pflag.FlagSet) {
 			"the value of --cluster-signing-duration command-line flag of the kube-controller-manager will be used.")
 	fs.StringToStringVar(&o.ClusterAnnotations, "cluster-annotations", o.ClusterAnnotations, `the annotations with the reserve
 	 prefix "agent.open-cluster-management.io" set on ManagedCluster when creating only, other actors can update it afterwards.`)
+	fs.DurationVar(&o.ResourceUsageScoreInterval, "resource-usage-score-interval", o.ResourceUsageScoreInterval,
+		"The interval to publish the cpu and memory usage of the managed cluster collected from the metrics API as "+
+			"the AddOnPlacementScore \"resource-usage\" on the hub. The scores are not published if it is zero.")
+	fs.StringVar(&o.ClusterMetadataNamespace, "cluster-metadata-namespace", o.ClusterMetadataNamespace,
+		"The namespace on the managed cluster of the configmap which the labels and annotations with the prefix "+
+			"\"spoke.open-cluster-management.io/\" of the ManagedCluster are mirrored into. The component namespace "+
+			"is used if it is not set.")
+	fs.StringVar(&o.CSRSignerName, "csr-signer-name", o.CSRSignerName,
+		"The signer name of the CSRs created for the client certificate of the agent. The hub must approve the CSRs "+
+			"of the signer, and the certificates issued by the signer must be trusted by the hub apiserver.")
+	fs.IntVar(&o.ClusterStatusWorkers, "cluster-status-workers", o.ClusterStatusWorkers,
+		"The number of workers syncing the status of the ManagedCluster, including the cluster claims and resources.")
+	fs.IntVar(&o.LeaseWorkers, "lease-workers", o.LeaseWorkers,
+		"The number of workers updating the leases of the managed cluster and the addons.")
+	fs.IntVar(&o.AddOnRegistrationWorkers, "addon-registration-workers", o.AddOnRegistrationWorkers,
+		"The number of workers registering the addons, each addon is registered by one worker at a time.")
 }
 
 // Validate verifies the inputs.
@@ -67,9 +94,17 @@ func (o *SpokeAgentOptions) Validate() error {
 		return errors.New("cluster healthcheck period must greater than zero")
 	}
 
+	if o.ResourceUsageScoreInterval < 0 {
+		return errors.New("resource usage score interval must not be negative")
+	}
+
 	if o.ClientCertExpirationSeconds != 0 && o.ClientCertExpirationSeconds < 3600 {
 		return errors.New("client certificate expiration seconds must greater or qual to 3600")
 	}
 
+	if o.ClusterStatusWorkers < 1 || o.LeaseWorkers < 1 || o.AddOnRegistrationWorkers < 1 {
+		return errors.New("the number of workers must be greater than zero")
+	}
+
 	return nil
 }
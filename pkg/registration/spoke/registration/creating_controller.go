@@ -16,6 +16,7 @@ import (
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 
 	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/common/logging"
 )
 
 var (
@@ -48,7 +49,7 @@ func NewManagedClusterCreatingController(
 	}
 
 	return factory.New().
-		WithSync(c.sync).
+		WithSync(logging.WithControllerLogger("ManagedClusterCreatingController", c.sync)).
 		ResyncEvery(wait.Jitter(CreatingControllerSyncInterval, 1.0)).
 		ToController("ManagedClusterCreatingController", recorder)
 }
@@ -57,7 +58,7 @@ func (c *managedClusterCreatingController) sync(ctx context.Context, syncCtx fac
 	existingCluster, err := c.hubClusterClient.ClusterV1().ManagedClusters().Get(ctx, c.clusterName, metav1.GetOptions{})
 	// ManagedCluster is only allowed created during bootstrap. After bootstrap secret expired, an unauthorized error will be got, output log at the debug level
 	if err != nil && skipUnauthorizedError(err) == nil {
-		klog.V(4).Infof("unable to get the managed cluster %q from hub: %v", c.clusterName, err)
+		klog.FromContext(ctx).V(4).Info("Unable to get the managed cluster from hub", "clusterName", c.clusterName, "err", err)
 		return nil
 	}
 
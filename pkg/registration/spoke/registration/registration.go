@@ -8,7 +8,6 @@ import (
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
 	"golang.org/x/net/context"
-	certificates "k8s.io/api/certificates/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -46,6 +45,7 @@ func NewClientCertForHubController(
 	kubeconfigData []byte,
 	spokeSecretInformer corev1informers.SecretInformer,
 	csrControl clientcert.CSRControl,
+	csrSignerName string,
 	csrExpirationSeconds int32,
 	spokeKubeClient kubernetes.Interface,
 	statusUpdater clientcert.StatusUpdateFunc,
@@ -87,7 +87,7 @@ func NewClientCertForHubController(
 			},
 			CommonName: fmt.Sprintf("%s%s:%s", user.SubjectPrefix, clusterName, agentName),
 		},
-		SignerName: certificates.KubeAPIServerClientSignerName,
+		SignerName: csrSignerName,
 		EventFilterFunc: func(obj interface{}) bool {
 			accessor, err := meta.Accessor(obj)
 			if err != nil {
@@ -18,6 +18,7 @@ import (
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/klog/v2"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 )
 
@@ -57,7 +58,7 @@ func NewHubKubeconfigSecretController(
 				}
 				return false
 			}, spokeSecretInformer.Informer()).
-		WithSync(s.sync).
+		WithSync(logging.WithControllerLogger("HubKubeconfigSecretController", s.sync)).
 		ResyncEvery(5*time.Minute).
 		ToController("HubKubeconfigSecretController", recorder)
 }
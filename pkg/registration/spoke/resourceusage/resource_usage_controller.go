@@ -0,0 +1,190 @@
+package resourceusage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	corev1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+
+	clientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+
+	"open-cluster-management.io/ocm/pkg/common/logging"
+)
+
+const (
+	// ResourceUsageScoreName is the name of the AddOnPlacementScore published in the cluster namespace on the hub.
+	// It can be referred by the addOn score coordinate of a placement prioritizer.
+	ResourceUsageScoreName = "resource-usage"
+	// CPUAvailableScoreName is the score of the available cpu, it is 100 if the cpu of the cluster is
+	// not used at all and -100 if the cpu is fully used.
+	CPUAvailableScoreName = "cpuAvailable"
+	// MemoryAvailableScoreName is the score of the available memory, it is 100 if the memory of the
+	// cluster is not used at all and -100 if the memory is fully used.
+	MemoryAvailableScoreName = "memoryAvailable"
+
+	nodeMetricsPath = "/apis/metrics.k8s.io/v1beta1/nodes"
+
+	// the scores are valid in a few collection intervals, so placement ignores them once the
+	// agent stops publishing.
+	scoreValidIntervals = 3
+)
+
+// nodeMetrics is the subset of the metrics.k8s.io NodeMetrics used by the controller.
+type nodeMetrics struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Usage             corev1.ResourceList `json:"usage"`
+}
+
+type nodeMetricsList struct {
+	Items []nodeMetrics `json:"items"`
+}
+
+// resourceUsageScoreController periodically collects the cpu and memory usage of the nodes on the managed
+// cluster from the metrics API and publishes them as an AddOnPlacementScore on the hub, so the addOn score
+// prioritizer is able to balance the workloads by the real usage of the clusters.
+type resourceUsageScoreController struct {
+	clusterName      string
+	hubClusterClient clientset.Interface
+	nodeLister       corev1lister.NodeLister
+	listNodeMetrics  func(ctx context.Context) (*nodeMetricsList, error)
+	interval         time.Duration
+}
+
+// NewResourceUsageScoreController creates a new resource usage score controller on the managed cluster.
+func NewResourceUsageScoreController(
+	clusterName string,
+	hubClusterClient clientset.Interface,
+	managedClusterRESTClient rest.Interface,
+	nodeInformer corev1informers.NodeInformer,
+	interval time.Duration,
+	recorder events.Recorder) factory.Controller {
+	c := &resourceUsageScoreController{
+		clusterName:      clusterName,
+		hubClusterClient: hubClusterClient,
+		nodeLister:       nodeInformer.Lister(),
+		listNodeMetrics: func(ctx context.Context) (*nodeMetricsList, error) {
+			data, err := managedClusterRESTClient.Get().AbsPath(nodeMetricsPath).DoRaw(ctx)
+			if err != nil {
+				return nil, err
+			}
+			metrics := &nodeMetricsList{}
+			if err := json.Unmarshal(data, metrics); err != nil {
+				return nil, err
+			}
+			return metrics, nil
+		},
+		interval: interval,
+	}
+
+	return factory.New().
+		WithSync(logging.WithControllerLogger("ResourceUsageScoreController", c.sync)).
+		ResyncEvery(interval).
+		ToController("ResourceUsageScoreController", recorder)
+}
+
+func (c *resourceUsageScoreController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	metrics, err := c.listNodeMetrics(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get node metrics of managed cluster %q: %w", c.clusterName, err)
+	}
+
+	scores, err := c.calculateScores(metrics)
+	if err != nil {
+		return err
+	}
+
+	score, err := c.hubClusterClient.ClusterV1alpha1().AddOnPlacementScores(c.clusterName).Get(
+		ctx, ResourceUsageScoreName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		score, err = c.hubClusterClient.ClusterV1alpha1().AddOnPlacementScores(c.clusterName).Create(
+			ctx, &clusterv1alpha1.AddOnPlacementScore{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: c.clusterName,
+					Name:      ResourceUsageScoreName,
+				},
+			}, metav1.CreateOptions{})
+		if err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	}
+
+	validUntil := metav1.NewTime(time.Now().Add(scoreValidIntervals * c.interval))
+	if equality.Semantic.DeepEqual(score.Status.Scores, scores) && score.Status.ValidUntil != nil &&
+		score.Status.ValidUntil.Sub(validUntil.Time) > -c.interval {
+		return nil
+	}
+
+	score = score.DeepCopy()
+	score.Status.Scores = scores
+	score.Status.ValidUntil = &validUntil
+	_, err = c.hubClusterClient.ClusterV1alpha1().AddOnPlacementScores(c.clusterName).UpdateStatus(
+		ctx, score, metav1.UpdateOptions{})
+	return err
+}
+
+// calculateScores compares the usage reported by the metrics API with the allocatable resources of the
+// schedulable nodes which have metrics.
+func (c *resourceUsageScoreController) calculateScores(
+	metrics *nodeMetricsList) ([]clusterv1alpha1.AddOnPlacementScoreItem, error) {
+	nodes, err := c.nodeLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	usages := map[string]corev1.ResourceList{}
+	for _, m := range metrics.Items {
+		usages[m.Name] = m.Usage
+	}
+
+	cpuAllocatable, cpuUsage := resource.Quantity{}, resource.Quantity{}
+	memAllocatable, memUsage := resource.Quantity{}, resource.Quantity{}
+	for _, node := range nodes {
+		// the node is unschedulable, ignore its resources
+		if node.Spec.Unschedulable {
+			continue
+		}
+		usage, ok := usages[node.Name]
+		if !ok {
+			continue
+		}
+		cpuAllocatable.Add(node.Status.Allocatable[corev1.ResourceCPU])
+		memAllocatable.Add(node.Status.Allocatable[corev1.ResourceMemory])
+		cpuUsage.Add(usage[corev1.ResourceCPU])
+		memUsage.Add(usage[corev1.ResourceMemory])
+	}
+
+	return []clusterv1alpha1.AddOnPlacementScoreItem{
+		{
+			Name:  CPUAvailableScoreName,
+			Value: availableScore(float64(cpuUsage.MilliValue()), float64(cpuAllocatable.MilliValue())),
+		},
+		{
+			Name:  MemoryAvailableScoreName,
+			Value: availableScore(float64(memUsage.Value()), float64(memAllocatable.Value())),
+		},
+	}, nil
+}
+
+// availableScore normalizes the available ratio of a resource to the score range [-100, 100].
+func availableScore(usage, allocatable float64) int32 {
+	if allocatable <= 0 || usage >= allocatable {
+		return -100
+	}
+	return int32(200*(allocatable-usage)/allocatable) - 100
+}
@@ -0,0 +1,169 @@
+package resourceusage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+)
+
+func newNodeMetrics(name string, usage corev1.ResourceList) nodeMetrics {
+	return nodeMetrics{ObjectMeta: metav1.ObjectMeta{Name: name}, Usage: usage}
+}
+
+func newScore(validUntil time.Time, cpu, memory int32) *clusterv1alpha1.AddOnPlacementScore {
+	until := metav1.NewTime(validUntil)
+	return &clusterv1alpha1.AddOnPlacementScore{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testinghelpers.TestManagedClusterName, Name: ResourceUsageScoreName},
+		Status: clusterv1alpha1.AddOnPlacementScoreStatus{
+			Scores: []clusterv1alpha1.AddOnPlacementScoreItem{
+				{Name: CPUAvailableScoreName, Value: cpu},
+				{Name: MemoryAvailableScoreName, Value: memory},
+			},
+			ValidUntil: &until,
+		},
+	}
+}
+
+func TestSync(t *testing.T) {
+	unschedulable := testinghelpers.NewNode("node3", testinghelpers.NewResourceList(16, 32), testinghelpers.NewResourceList(16, 32))
+	unschedulable.Spec.Unschedulable = true
+	nodes := []runtime.Object{
+		testinghelpers.NewNode("node1", testinghelpers.NewResourceList(16, 32), testinghelpers.NewResourceList(16, 32)),
+		testinghelpers.NewNode("node2", testinghelpers.NewResourceList(16, 32), testinghelpers.NewResourceList(16, 32)),
+		unschedulable,
+	}
+	metrics := &nodeMetricsList{
+		Items: []nodeMetrics{
+			newNodeMetrics("node1", testinghelpers.NewResourceList(4, 16)),
+			newNodeMetrics("node2", testinghelpers.NewResourceList(4, 16)),
+			newNodeMetrics("node3", testinghelpers.NewResourceList(16, 32)),
+		},
+	}
+
+	cases := []struct {
+		name            string
+		scores          []runtime.Object
+		metricsErr      error
+		expectedErr     string
+		validateActions func(t *testing.T, actions []clienttesting.Action)
+	}{
+		{
+			name:        "metrics api is not available",
+			metricsErr:  fmt.Errorf("the server could not find the requested resource"),
+			expectedErr: "unable to get node metrics of managed cluster \"testmanagedcluster\": the server could not find the requested resource",
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+		{
+			name: "create the score",
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "get", "create", "update")
+				score := actions[2].(clienttesting.UpdateActionImpl).Object.(*clusterv1alpha1.AddOnPlacementScore)
+				assertScores(t, score, 50, 0)
+			},
+		},
+		{
+			name:   "update the expiring score",
+			scores: []runtime.Object{newScore(time.Now(), 50, 0)},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "get", "update")
+				score := actions[1].(clienttesting.UpdateActionImpl).Object.(*clusterv1alpha1.AddOnPlacementScore)
+				assertScores(t, score, 50, 0)
+			},
+		},
+		{
+			name:   "update the changed score",
+			scores: []runtime.Object{newScore(time.Now().Add(3*time.Minute), 100, 100)},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "get", "update")
+				score := actions[1].(clienttesting.UpdateActionImpl).Object.(*clusterv1alpha1.AddOnPlacementScore)
+				assertScores(t, score, 50, 0)
+			},
+		},
+		{
+			name:   "the score is up to date",
+			scores: []runtime.Object{newScore(time.Now().Add(3*time.Minute), 50, 0)},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "get")
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusterClient := clusterfake.NewSimpleClientset(c.scores...)
+
+			kubeClient := kubefake.NewSimpleClientset(nodes...)
+			kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, time.Minute*10)
+			nodeStore := kubeInformerFactory.Core().V1().Nodes().Informer().GetStore()
+			for _, node := range nodes {
+				if err := nodeStore.Add(node); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctrl := &resourceUsageScoreController{
+				clusterName:      testinghelpers.TestManagedClusterName,
+				hubClusterClient: clusterClient,
+				nodeLister:       kubeInformerFactory.Core().V1().Nodes().Lister(),
+				listNodeMetrics: func(ctx context.Context) (*nodeMetricsList, error) {
+					return metrics, c.metricsErr
+				},
+				interval: time.Minute,
+			}
+
+			syncErr := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, ""))
+			testingcommon.AssertError(t, syncErr, c.expectedErr)
+			c.validateActions(t, clusterClient.Actions())
+		})
+	}
+}
+
+func assertScores(t *testing.T, score *clusterv1alpha1.AddOnPlacementScore, cpu, memory int32) {
+	if score.Status.ValidUntil == nil || !score.Status.ValidUntil.After(time.Now().Add(2*time.Minute)) {
+		t.Errorf("expected the score valid for 3 minutes, but got %v", score.Status.ValidUntil)
+	}
+	expected := map[string]int32{CPUAvailableScoreName: cpu, MemoryAvailableScoreName: memory}
+	if len(score.Status.Scores) != len(expected) {
+		t.Fatalf("expected scores %v, but got %v", expected, score.Status.Scores)
+	}
+	for _, item := range score.Status.Scores {
+		if expected[item.Name] != item.Value {
+			t.Errorf("expected score %s to be %d, but got %d", item.Name, expected[item.Name], item.Value)
+		}
+	}
+}
+
+func TestAvailableScore(t *testing.T) {
+	cases := []struct {
+		usage       float64
+		allocatable float64
+		expected    int32
+	}{
+		{usage: 0, allocatable: 0, expected: -100},
+		{usage: 0, allocatable: 10, expected: 100},
+		{usage: 5, allocatable: 10, expected: 0},
+		{usage: 10, allocatable: 10, expected: -100},
+		{usage: 12, allocatable: 10, expected: -100},
+	}
+	for _, c := range cases {
+		if actual := availableScore(c.usage, c.allocatable); actual != c.expected {
+			t.Errorf("expected score %d for usage %v of %v, but got %d", c.expected, c.usage, c.allocatable, actual)
+		}
+	}
+}
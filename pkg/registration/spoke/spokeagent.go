@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"sync"
 	"time"
 
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
+	certificatesv1 "k8s.io/api/certificates/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -26,6 +28,7 @@ import (
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	ocmfeature "open-cluster-management.io/api/feature"
 
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
 	commonoptions "open-cluster-management.io/ocm/pkg/common/options"
 	"open-cluster-management.io/ocm/pkg/features"
 	"open-cluster-management.io/ocm/pkg/registration/clientcert"
@@ -33,6 +36,7 @@ import (
 	"open-cluster-management.io/ocm/pkg/registration/spoke/lease"
 	"open-cluster-management.io/ocm/pkg/registration/spoke/managedcluster"
 	"open-cluster-management.io/ocm/pkg/registration/spoke/registration"
+	"open-cluster-management.io/ocm/pkg/registration/spoke/resourceusage"
 )
 
 // AddOnLeaseControllerSyncInterval is exposed so that integration tests can crank up the constroller sync speed.
@@ -151,6 +155,21 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 	}
 
+	// load the feature gates in the feature gates configmap before the controllers are built
+	if len(o.agentOptions.FeatureGatesConfigMap) > 0 {
+		if err := features.LoadFeatureGates(ctx, managementKubeClient, o.agentOptions.ComponentNamespace,
+			o.agentOptions.FeatureGatesConfigMap, features.FeatureGatesConfigMapRegistrationKey,
+			features.SpokeMutableFeatureGate, ocmfeature.DefaultSpokeRegistrationFeatureGates); err != nil {
+			return err
+		}
+	}
+
+	// the client certificate is issued by kubernetes.io/kube-apiserver-client unless another signer is specified
+	csrSignerName := o.registrationOption.CSRSignerName
+	if len(csrSignerName) == 0 {
+		csrSignerName = certificatesv1.KubeAPIServerClientSignerName
+	}
+
 	// get spoke cluster CA bundle
 	spokeClusterCABundle, err := o.getSpokeClusterCABundle(spokeClientConfig)
 	if err != nil {
@@ -191,7 +210,27 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 		namespacedManagementKubeInformerFactory.Core().V1().Secrets(),
 		recorder,
 	)
+	if err := commonhelpers.SetInformerTransform(commonhelpers.TrimManagedFields,
+		namespacedManagementKubeInformerFactory.Core().V1().Secrets().Informer()); err != nil {
+		return err
+	}
 	go hubKubeconfigSecretController.Run(ctx, 1)
+
+	// reload the feature gates once the feature gates configmap is changed
+	if len(o.agentOptions.FeatureGatesConfigMap) > 0 {
+		featureGateReloadController := features.NewFeatureGateReloadController(
+			managementKubeClient,
+			namespacedManagementKubeInformerFactory.Core().V1().ConfigMaps(),
+			o.agentOptions.FeatureGatesConfigMap,
+			features.FeatureGatesConfigMapRegistrationKey,
+			features.SpokeMutableFeatureGate,
+			ocmfeature.DefaultSpokeRegistrationFeatureGates,
+			recorder,
+			// the csr control is built once the agent starts
+			ocmfeature.V1beta1CSRAPICompatibility,
+		)
+		go featureGateReloadController.Run(ctx, 1)
+	}
 	go namespacedManagementKubeInformerFactory.Start(ctx.Done())
 
 	// check if there already exists a valid client config for hub
@@ -206,6 +245,13 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 	// in scenario #2 and #3, which results in an error message in log: 'Observed a panic: timeout waiting for
 	// informer cache'
 	if !ok {
+		// the agent is bootstrapped again if there is a hub kubeconfig but it is no longer valid, e.g. the
+		// certificate is expired or the cluster name is changed.
+		if _, err := os.Stat(o.agentOptions.HubKubeconfigFile); err == nil {
+			recorder.Eventf(commonhelpers.EventReasonRebootstrapTriggered,
+				"Rebootstrap is triggered since the hub kubeconfig %s is invalid", o.agentOptions.HubKubeconfigFile)
+		}
+
 		// create a ClientCertForHubController for spoke agent bootstrap
 		// the bootstrap informers are supposed to be terminated after completing the bootstrap process.
 		bootstrapInformerFactory := informers.NewSharedInformerFactory(bootstrapKubeClient, 10*time.Minute)
@@ -236,6 +282,7 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 			// store the secret in the cluster where the agent pod runs
 			bootstrapNamespacedManagementKubeInformerFactory.Core().V1().Secrets(),
 			csrControl,
+			csrSignerName,
 			o.registrationOption.ClientCertExpirationSeconds,
 			managementKubeClient,
 			registration.GenerateBootstrapStatusUpdater(),
@@ -331,6 +378,7 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 		kubeconfigData,
 		namespacedManagementKubeInformerFactory.Core().V1().Secrets(),
 		csrControl,
+		csrSignerName,
 		o.registrationOption.ClientCertExpirationSeconds,
 		managementKubeClient,
 		registration.GenerateStatusUpdater(
@@ -365,10 +413,38 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 		recorder,
 	)
 
-	var addOnLeaseController factory.Controller
-	var addOnRegistrationController factory.Controller
-	if features.SpokeMutableFeatureGate.Enabled(ocmfeature.AddonManagement) {
-		addOnLeaseController = addon.NewManagedClusterAddOnLeaseController(
+	// create ManagedClusterMetadataController to mirror the metadata of the managed cluster on the hub into the
+	// managed cluster
+	clusterMetadataNamespace := o.registrationOption.ClusterMetadataNamespace
+	if len(clusterMetadataNamespace) == 0 {
+		clusterMetadataNamespace = o.agentOptions.ComponentNamespace
+	}
+	managedClusterMetadataController := managedcluster.NewManagedClusterMetadataController(
+		o.agentOptions.SpokeClusterName,
+		clusterMetadataNamespace,
+		hubClusterInformerFactory.Cluster().V1().ManagedClusters(),
+		spokeKubeClient,
+		recorder,
+	)
+
+	var resourceUsageScoreController factory.Controller
+	if o.registrationOption.ResourceUsageScoreInterval > 0 {
+		resourceUsageScoreController = resourceusage.NewResourceUsageScoreController(
+			o.agentOptions.SpokeClusterName,
+			hubClusterClient,
+			spokeKubeClient.Discovery().RESTClient(),
+			spokeKubeInformerFactory.Core().V1().Nodes(),
+			o.registrationOption.ResourceUsageScoreInterval,
+			recorder,
+		)
+	}
+
+	// the addon controllers are started once the AddonManagement feature is enabled, and stopped once it is disabled.
+	// The informer of the addons is started with the context of the agent, since it is not able to be started again
+	// once it is stopped.
+	var addOnInformerTransform sync.Once
+	runAddOnControllers := func(addOnCtx context.Context) {
+		addOnLeaseController := addon.NewManagedClusterAddOnLeaseController(
 			o.agentOptions.SpokeClusterName,
 			addOnClient,
 			addOnInformerFactory.Addon().V1alpha1().ManagedClusterAddOns(),
@@ -379,7 +455,7 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 			recorder,
 		)
 
-		addOnRegistrationController = addon.NewAddOnRegistrationController(
+		addOnRegistrationController := addon.NewAddOnRegistrationController(
 			o.agentOptions.SpokeClusterName,
 			o.agentOptions.AgentID,
 			kubeconfigData,
@@ -390,25 +466,46 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 			addOnInformerFactory.Addon().V1alpha1().ManagedClusterAddOns(),
 			recorder,
 		)
+
+		addOnInformerTransform.Do(func() {
+			if err := commonhelpers.SetInformerTransform(commonhelpers.TrimManagedFieldsAndLastApplied,
+				addOnInformerFactory.Addon().V1alpha1().ManagedClusterAddOns().Informer()); err != nil {
+				logger.Error(err, "Failed to set the transform of the addon informer")
+			}
+		})
+		go addOnInformerFactory.Start(ctx.Done())
+
+		go addOnLeaseController.Run(addOnCtx, o.registrationOption.LeaseWorkers)
+		go addOnRegistrationController.Run(addOnCtx, o.registrationOption.AddOnRegistrationWorkers)
+	}
+
+	if err := commonhelpers.SetInformerTransform(commonhelpers.TrimManagedFieldsAndLastApplied,
+		csrControl.Informer(),
+		hubClusterInformerFactory.Cluster().V1().ManagedClusters().Informer(),
+		spokeClusterInformerFactory.Cluster().V1alpha1().ClusterClaims().Informer(),
+		spokeKubeInformerFactory.Core().V1().Nodes().Informer(),
+	); err != nil {
+		return err
 	}
 
 	go hubKubeInformerFactory.Start(ctx.Done())
 	go hubClusterInformerFactory.Start(ctx.Done())
 	go namespacedManagementKubeInformerFactory.Start(ctx.Done())
-	go addOnInformerFactory.Start(ctx.Done())
 
 	go spokeKubeInformerFactory.Start(ctx.Done())
-	if features.SpokeMutableFeatureGate.Enabled(ocmfeature.ClusterClaim) {
+	// the cluster claims are read by the controllers only if the ClusterClaim feature is enabled
+	go features.RunGated(ctx, features.SpokeMutableFeatureGate, ocmfeature.ClusterClaim, func(context.Context) {
 		go spokeClusterInformerFactory.Start(ctx.Done())
-	}
+	})
 
 	go clientCertForHubController.Run(ctx, 1)
-	go managedClusterLeaseController.Run(ctx, 1)
-	go managedClusterHealthCheckController.Run(ctx, 1)
-	if features.SpokeMutableFeatureGate.Enabled(ocmfeature.AddonManagement) {
-		go addOnLeaseController.Run(ctx, 1)
-		go addOnRegistrationController.Run(ctx, 1)
+	go managedClusterLeaseController.Run(ctx, o.registrationOption.LeaseWorkers)
+	go managedClusterHealthCheckController.Run(ctx, o.registrationOption.ClusterStatusWorkers)
+	go managedClusterMetadataController.Run(ctx, 1)
+	if resourceUsageScoreController != nil {
+		go resourceUsageScoreController.Run(ctx, 1)
 	}
+	go features.RunGated(ctx, features.SpokeMutableFeatureGate, ocmfeature.AddonManagement, runAddOnControllers)
 
 	<-ctx.Done()
 	return nil
@@ -72,9 +72,25 @@ func TestValidate(t *testing.T) {
 				MaxCustomClusterClaims:      20,
 				BootstrapKubeconfig:         "/spoke/bootstrap/kubeconfig",
 				ClientCertExpirationSeconds: 3600,
+				ClusterStatusWorkers:        1,
+				LeaseWorkers:                1,
+				AddOnRegistrationWorkers:    1,
 			},
 			expectedErr: "",
 		},
+		{
+			name: "invalid workers",
+			options: &SpokeAgentOptions{
+				HubKubeconfigSecret:      "hub-kubeconfig-secret",
+				ClusterHealthCheckPeriod: 1 * time.Minute,
+				MaxCustomClusterClaims:   20,
+				BootstrapKubeconfig:      "/spoke/bootstrap/kubeconfig",
+				ClusterStatusWorkers:     2,
+				LeaseWorkers:             0,
+				AddOnRegistrationWorkers: 2,
+			},
+			expectedErr: "the number of workers must be greater than zero",
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
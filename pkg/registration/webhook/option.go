@@ -1,17 +1,35 @@
 package webhook
 
-import "github.com/spf13/pflag"
+import (
+	"github.com/spf13/pflag"
+
+	commonoptions "open-cluster-management.io/ocm/pkg/common/options"
+)
 
 // Config contains the server (the webhook) cert and key.
 type Options struct {
-	Port    int
-	CertDir string
+	Port     int
+	CertDir  string
+	FIPSMode bool
+
+	ClusterSetBindingRulesConfigMap string
+
+	ClusterNamePattern          string
+	ClusterNameMaxLength        int
+	ClusterNameReservedPrefixes []string
+
+	ClusterDefaultLeaseDurationSeconds int32
+	ClusterDefaultClusterSet           string
+	NormalizeClusterClientURLs         bool
+
+	ServingOptions *commonoptions.WebhookServingOptions
 }
 
 // NewOptions constructs a new set of default options for webhook.
 func NewOptions() *Options {
 	return &Options{
-		Port: 9443,
+		Port:           9443,
+		ServingOptions: commonoptions.NewWebhookServingOptions(),
 	}
 }
 
@@ -21,4 +39,25 @@ func (c *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&c.CertDir, "certdir", c.CertDir,
 		"CertDir is the directory that contains the server key and certificate. If not set, "+
 			"webhook server would look up the server key and certificate in {TempDir}/k8s-webhook-server/serving-certs")
+	fs.BoolVar(&c.FIPSMode, "fips-mode", c.FIPSMode,
+		"Restrict the TLS configuration and the serving certificate to the FIPS approved algorithms and key sizes.")
+	fs.StringVar(&c.ClusterSetBindingRulesConfigMap, "clustersetbinding-rules-configmap", c.ClusterSetBindingRulesConfigMap,
+		"The ConfigMap with the key <namespace>/<name> containing the CEL validation rules of the ManagedClusterSetBinding "+
+			"creation. The rules are not enforced if it is not set or the ConfigMap does not exist.")
+	fs.StringVar(&c.ClusterNamePattern, "cluster-name-pattern", c.ClusterNamePattern,
+		"The regular expression the name of a ManagedCluster must match when the cluster joins the hub.")
+	fs.IntVar(&c.ClusterNameMaxLength, "cluster-name-max-length", c.ClusterNameMaxLength,
+		"The maximum length of the name of a ManagedCluster when the cluster joins the hub, 0 means no limit.")
+	fs.StringSliceVar(&c.ClusterNameReservedPrefixes, "cluster-name-reserved-prefixes", c.ClusterNameReservedPrefixes,
+		"The comma-separated prefixes the name of a ManagedCluster must not start with when the cluster joins the hub.")
+	fs.Int32Var(&c.ClusterDefaultLeaseDurationSeconds, "cluster-default-lease-duration-seconds", c.ClusterDefaultLeaseDurationSeconds,
+		"The lease duration set on the ManagedClusters created without a lease duration or with the API default of 60 "+
+			"seconds. The lease duration is not defaulted if it is 0.")
+	fs.StringVar(&c.ClusterDefaultClusterSet, "cluster-default-clusterset", c.ClusterDefaultClusterSet,
+		"The ManagedClusterSet the ManagedClusters without the clusterset label are added to. It takes precedence over "+
+			"the default ManagedClusterSet of the DefaultClusterSet feature.")
+	fs.BoolVar(&c.NormalizeClusterClientURLs, "normalize-cluster-client-urls", c.NormalizeClusterClientURLs,
+		"Normalize the URLs of the ManagedClusterClientConfigs of the ManagedClusters, which lowercases the scheme and "+
+			"the host, removes the default https port and the trailing slashes, and removes the duplicated URLs.")
+	c.ServingOptions.AddFlags(fs)
 }
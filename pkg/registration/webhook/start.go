@@ -3,6 +3,9 @@ package webhook
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
+	"os"
+	"path/filepath"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -13,8 +16,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 
+	internaladdonv1alpha1 "open-cluster-management.io/ocm/pkg/addon/webhook/v1alpha1"
+	"open-cluster-management.io/ocm/pkg/common/conversion"
+	"open-cluster-management.io/ocm/pkg/common/fips"
 	internalv1 "open-cluster-management.io/ocm/pkg/registration/webhook/v1"
 	internalv1beta2 "open-cluster-management.io/ocm/pkg/registration/webhook/v1beta2"
 )
@@ -27,19 +34,39 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(clusterv1.Install(scheme))
 	utilruntime.Must(internalv1beta2.Install(scheme))
+	utilruntime.Must(addonv1alpha1.Install(scheme))
 }
 
 func (c *Options) RunWebhookServer() error {
+	if c.FIPSMode {
+		fips.Enable()
+	}
+	if fips.Enabled() {
+		// the serving certificate is looked up in the same default directory as the webhook server.
+		certDir := c.CertDir
+		if len(certDir) == 0 {
+			certDir = filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
+		}
+		if err := fips.ValidateCertificateFile(filepath.Join(certDir, "tls.crt")); err != nil {
+			return fmt.Errorf("the serving certificate is not valid in FIPS mode: %w", err)
+		}
+	}
+	tlsConfigFunc, err := c.ServingOptions.TLSConfigFunc()
+	if err != nil {
+		return err
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		Port:                   c.Port,
 		HealthProbeBindAddress: ":8000",
-		CertDir:                c.CertDir,
+		// wait for the in-flight admission requests on shutdown
+		GracefulShutdownTimeout: &c.ServingOptions.ShutdownTimeout,
+		CertDir:                 c.CertDir,
 		WebhookServer: webhook.NewServer(webhook.Options{
 			TLSOpts: []func(config *tls.Config){
-				func(config *tls.Config) {
-					config.MinVersion = tls.VersionTLS12
-				},
+				tlsConfigFunc,
+				fips.SecureTLSConfig,
 			},
 		}),
 	})
@@ -61,11 +88,32 @@ func (c *Options) RunWebhookServer() error {
 		return err
 	}
 
-	if err = (&internalv1.ManagedClusterWebhook{}).Init(mgr); err != nil {
+	namingPolicy, err := internalv1.NewNamingPolicy(c.ClusterNamePattern, c.ClusterNameMaxLength, c.ClusterNameReservedPrefixes)
+	if err != nil {
+		logger.Error(err, "invalid cluster naming policy")
+		return err
+	}
+	defaultingPolicy, err := internalv1.NewDefaultingPolicy(
+		c.ClusterDefaultLeaseDurationSeconds, c.ClusterDefaultClusterSet, c.NormalizeClusterClientURLs)
+	if err != nil {
+		logger.Error(err, "invalid cluster defaulting policy")
+		return err
+	}
+	clusterWebhook := &internalv1.ManagedClusterWebhook{}
+	clusterWebhook.SetNamingPolicy(namingPolicy)
+	clusterWebhook.SetDefaultingPolicy(defaultingPolicy)
+	if err = clusterWebhook.Init(mgr); err != nil {
 		logger.Error(err, "unable to create ManagedCluster webhook")
 		return err
 	}
-	if err = (&internalv1beta2.ManagedClusterSetBindingWebhook{}).Init(mgr); err != nil {
+	bindingWebhook := &internalv1beta2.ManagedClusterSetBindingWebhook{}
+	if len(c.ClusterSetBindingRulesConfigMap) > 0 {
+		if err := bindingWebhook.SetBindingRulesConfigMap(c.ClusterSetBindingRulesConfigMap); err != nil {
+			logger.Error(err, "invalid clustersetbinding rules configmap")
+			return err
+		}
+	}
+	if err = bindingWebhook.Init(mgr); err != nil {
 		logger.Error(err, "unable to create ManagedClusterSetBinding webhook", "version", "v1beta2")
 		return err
 	}
@@ -73,6 +121,17 @@ func (c *Options) RunWebhookServer() error {
 		logger.Error(err, "unable to create ManagedClusterSet webhook", "version", "v1beta2")
 		return err
 	}
+	if err = (&internaladdonv1alpha1.ManagedClusterAddOnWebhook{}).Init(mgr); err != nil {
+		logger.Error(err, "unable to create ManagedClusterAddOn webhook", "version", "v1alpha1")
+		return err
+	}
+
+	converter := conversion.NewConverter()
+	if err := internalv1beta2.RegisterConversions(converter); err != nil {
+		logger.Error(err, "unable to register conversions", "version", "v1beta2")
+		return err
+	}
+	mgr.GetWebhookServer().Register(conversion.WebhookPath, converter)
 
 	logger.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -0,0 +1,119 @@
+package v1
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+)
+
+// apiDefaultLeaseDurationSeconds is the lease duration set by the apiserver when it is not specified.
+const apiDefaultLeaseDurationSeconds = 60
+
+// DefaultingPolicy is the defaults set on the ManagedClusters by the webhook, so the ManagedClusters created by
+// different tools are consistent without repeating the same fields.
+type DefaultingPolicy struct {
+	// LeaseDurationSeconds is set on the ManagedClusters created without a lease duration, the lease duration is not
+	// defaulted if it is 0.
+	LeaseDurationSeconds int32
+	// ClusterSet is the ManagedClusterSet the ManagedClusters without the clusterset label are added to, it takes
+	// precedence over the default ManagedClusterSet of the DefaultClusterSet feature.
+	ClusterSet string
+	// NormalizeClientURLs normalizes the URLs of the ManagedClusterClientConfigs and removes the duplicated ones.
+	NormalizeClientURLs bool
+}
+
+// NewDefaultingPolicy returns the defaulting policy of the ManagedClusters, or nil if nothing is defaulted.
+func NewDefaultingPolicy(leaseDurationSeconds int32, clusterSet string, normalizeClientURLs bool) (*DefaultingPolicy, error) {
+	if leaseDurationSeconds < 0 {
+		return nil, fmt.Errorf("the default lease duration must not be negative: %d", leaseDurationSeconds)
+	}
+	if errs := validation.IsValidLabelValue(clusterSet); len(errs) > 0 {
+		return nil, fmt.Errorf("the default clusterset %q is invalid: %s", clusterSet, strings.Join(errs, ", "))
+	}
+
+	if leaseDurationSeconds == 0 && len(clusterSet) == 0 && !normalizeClientURLs {
+		return nil, nil
+	}
+	return &DefaultingPolicy{
+		LeaseDurationSeconds: leaseDurationSeconds,
+		ClusterSet:           clusterSet,
+		NormalizeClientURLs:  normalizeClientURLs,
+	}, nil
+}
+
+// Apply sets the defaults on the ManagedCluster, the oldManagedCluster is nil if the cluster is being created.
+func (p *DefaultingPolicy) Apply(managedCluster, oldManagedCluster *clusterv1.ManagedCluster) {
+	if p == nil {
+		return
+	}
+
+	// the apiserver sets the lease duration to its default before the webhook is called, so the lease duration of
+	// the API default is regarded as not specified. It is defaulted only when the cluster is created, so the lease
+	// duration changed afterwards is kept.
+	if oldManagedCluster == nil && p.LeaseDurationSeconds > 0 && (managedCluster.Spec.LeaseDurationSeconds == 0 ||
+		managedCluster.Spec.LeaseDurationSeconds == apiDefaultLeaseDurationSeconds) {
+		managedCluster.Spec.LeaseDurationSeconds = p.LeaseDurationSeconds
+	}
+
+	if len(p.ClusterSet) > 0 && len(managedCluster.Labels[clusterv1beta2.ClusterSetLabel]) == 0 {
+		if managedCluster.Labels == nil {
+			managedCluster.Labels = map[string]string{}
+		}
+		managedCluster.Labels[clusterv1beta2.ClusterSetLabel] = p.ClusterSet
+	}
+
+	if p.NormalizeClientURLs {
+		managedCluster.Spec.ManagedClusterClientConfigs = normalizeClientConfigs(managedCluster.Spec.ManagedClusterClientConfigs)
+	}
+}
+
+// defaultClusterSet returns the ManagedClusterSet the ManagedClusters are added to by default.
+func (p *DefaultingPolicy) defaultClusterSet() string {
+	if p == nil {
+		return ""
+	}
+	return p.ClusterSet
+}
+
+// normalizeClientConfigs normalizes the URLs of the client configs, the configs with the same URL are merged into the
+// first one, whose CA bundle is set from the others if it is empty.
+func normalizeClientConfigs(configs []clusterv1.ClientConfig) []clusterv1.ClientConfig {
+	var normalized []clusterv1.ClientConfig
+	indexes := map[string]int{}
+	for _, config := range configs {
+		config.URL = normalizeURL(config.URL)
+		if index, ok := indexes[config.URL]; ok {
+			if len(normalized[index].CABundle) == 0 {
+				normalized[index].CABundle = config.CABundle
+			}
+			continue
+		}
+		indexes[config.URL] = len(normalized)
+		normalized = append(normalized, config)
+	}
+	return normalized
+}
+
+// normalizeURL lowercases the scheme and the host of the URL, and removes the default https port and the trailing
+// slashes. The URL which is not able to be parsed is only trimmed, and left to the validation.
+func normalizeURL(rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil || len(u.Host) == 0 {
+		return rawURL
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if u.Scheme == "https" {
+		u.Host = strings.TrimSuffix(u.Host, ":443")
+	}
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+	return u.String()
+}
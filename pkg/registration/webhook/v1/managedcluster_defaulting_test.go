@@ -0,0 +1,149 @@
+package v1
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+)
+
+func TestNewDefaultingPolicy(t *testing.T) {
+	cases := []struct {
+		name                 string
+		leaseDurationSeconds int32
+		clusterSet           string
+		normalizeClientURLs  bool
+		expectedNil          bool
+		expectedErr          bool
+	}{
+		{
+			name:        "no defaults",
+			expectedNil: true,
+		},
+		{
+			name:                 "negative lease duration",
+			leaseDurationSeconds: -1,
+			expectedErr:          true,
+		},
+		{
+			name:        "invalid clusterset",
+			clusterSet:  "-prod",
+			expectedErr: true,
+		},
+		{
+			name:                 "valid defaults",
+			leaseDurationSeconds: 120,
+			clusterSet:           "prod",
+			normalizeClientURLs:  true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			policy, err := NewDefaultingPolicy(c.leaseDurationSeconds, c.clusterSet, c.normalizeClientURLs)
+			if (err != nil) != c.expectedErr {
+				t.Errorf("expected error %v, but got %v", c.expectedErr, err)
+			}
+			if !c.expectedErr && (policy == nil) != c.expectedNil {
+				t.Errorf("expected nil policy %v, but got %v", c.expectedNil, policy)
+			}
+		})
+	}
+}
+
+func TestDefaultingPolicyApply(t *testing.T) {
+	policy := &DefaultingPolicy{LeaseDurationSeconds: 120, ClusterSet: "prod", NormalizeClientURLs: true}
+	cases := []struct {
+		name          string
+		policy        *DefaultingPolicy
+		cluster       *clusterv1.ManagedCluster
+		oldCluster    *clusterv1.ManagedCluster
+		expectCluster *clusterv1.ManagedCluster
+	}{
+		{
+			name:   "no policy",
+			policy: nil,
+			cluster: &clusterv1.ManagedCluster{
+				Spec: clusterv1.ManagedClusterSpec{LeaseDurationSeconds: 60},
+			},
+			expectCluster: &clusterv1.ManagedCluster{
+				Spec: clusterv1.ManagedClusterSpec{LeaseDurationSeconds: 60},
+			},
+		},
+		{
+			name:   "create cluster with the API default",
+			policy: policy,
+			cluster: &clusterv1.ManagedCluster{
+				Spec: clusterv1.ManagedClusterSpec{
+					LeaseDurationSeconds: 60,
+					ManagedClusterClientConfigs: []clusterv1.ClientConfig{
+						{URL: " HTTPS://API.Example.com:443/ "},
+						{URL: "https://api.example.com", CABundle: []byte("ca")},
+						{URL: "https://api.example.com:6443/"},
+						{URL: "://invalid "},
+					},
+				},
+			},
+			expectCluster: &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{clusterv1beta2.ClusterSetLabel: "prod"},
+				},
+				Spec: clusterv1.ManagedClusterSpec{
+					LeaseDurationSeconds: 120,
+					ManagedClusterClientConfigs: []clusterv1.ClientConfig{
+						{URL: "https://api.example.com", CABundle: []byte("ca")},
+						{URL: "https://api.example.com:6443"},
+						{URL: "://invalid"},
+					},
+				},
+			},
+		},
+		{
+			name:   "create cluster with lease duration and clusterset",
+			policy: policy,
+			cluster: &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{clusterv1beta2.ClusterSetLabel: "dev"},
+				},
+				Spec: clusterv1.ManagedClusterSpec{LeaseDurationSeconds: 30},
+			},
+			expectCluster: &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{clusterv1beta2.ClusterSetLabel: "dev"},
+				},
+				Spec: clusterv1.ManagedClusterSpec{LeaseDurationSeconds: 30},
+			},
+		},
+		{
+			name:   "update cluster",
+			policy: policy,
+			cluster: &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"k": "v"},
+				},
+				Spec: clusterv1.ManagedClusterSpec{LeaseDurationSeconds: 60},
+			},
+			oldCluster: &clusterv1.ManagedCluster{
+				Spec: clusterv1.ManagedClusterSpec{LeaseDurationSeconds: 120},
+			},
+			expectCluster: &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"k": "v", clusterv1beta2.ClusterSetLabel: "prod"},
+				},
+				Spec: clusterv1.ManagedClusterSpec{LeaseDurationSeconds: 60},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.policy.Apply(c.cluster, c.oldCluster)
+			if !reflect.DeepEqual(c.cluster, c.expectCluster) {
+				t.Errorf("expected cluster %v, but got %v", c.expectCluster, c.cluster)
+			}
+		})
+	}
+}
@@ -53,6 +53,10 @@ func (r *ManagedClusterWebhook) Default(ctx context.Context, obj runtime.Object)
 		return err
 	}
 
+	// the defaults of the hub are set before the default clusterset label, so the clusterset of the hub takes
+	// precedence.
+	r.defaultingPolicy.Apply(managedCluster, oldManagedCluster)
+
 	//Set default clusterset label
 	if features.HubMutableFeatureGate.Enabled(ocmfeature.DefaultClusterSet) {
 		r.addDefaultClusterSetLabel(managedCluster)
@@ -0,0 +1,81 @@
+package v1
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	v1 "open-cluster-management.io/api/cluster/v1"
+)
+
+// NamingPolicy is the naming convention of the ManagedClusters enforced when the clusters join the hub.
+type NamingPolicy struct {
+	// Pattern is the regular expression the whole cluster name must match.
+	Pattern string
+	// MaxLength is the maximum length of the cluster name, it is not limited if it is 0.
+	MaxLength int
+	// ReservedPrefixes are the prefixes the cluster name must not start with.
+	ReservedPrefixes []string
+
+	regex *regexp.Regexp
+}
+
+// NewNamingPolicy returns the naming policy of the ManagedClusters, or nil if none of the rules is set.
+func NewNamingPolicy(pattern string, maxLength int, reservedPrefixes []string) (*NamingPolicy, error) {
+	if maxLength < 0 {
+		return nil, fmt.Errorf("the max length of the cluster name must not be negative: %d", maxLength)
+	}
+
+	policy := &NamingPolicy{Pattern: pattern, MaxLength: maxLength}
+	for _, prefix := range reservedPrefixes {
+		if prefix = strings.TrimSpace(prefix); len(prefix) > 0 {
+			policy.ReservedPrefixes = append(policy.ReservedPrefixes, prefix)
+		}
+	}
+	if len(pattern) > 0 {
+		// the pattern is anchored, so it matches the whole cluster name rather than a part of it.
+		regex, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("the pattern of the cluster name is invalid: %w", err)
+		}
+		policy.regex = regex
+	}
+
+	if policy.regex == nil && policy.MaxLength == 0 && len(policy.ReservedPrefixes) == 0 {
+		return nil, nil
+	}
+	return policy, nil
+}
+
+// Validate returns a forbidden error describing the violated rule if the cluster name does not follow the policy.
+func (p *NamingPolicy) Validate(clusterName string) error {
+	if p == nil {
+		return nil
+	}
+
+	var reason string
+	switch {
+	case p.MaxLength > 0 && len(clusterName) > p.MaxLength:
+		reason = fmt.Sprintf("the cluster name is longer than %d characters", p.MaxLength)
+	case p.regex != nil && !p.regex.MatchString(clusterName):
+		reason = fmt.Sprintf("the cluster name does not match the pattern %q", p.Pattern)
+	default:
+		for _, prefix := range p.ReservedPrefixes {
+			if strings.HasPrefix(clusterName, prefix) {
+				reason = fmt.Sprintf("the cluster name starts with the reserved prefix %q", prefix)
+				break
+			}
+		}
+	}
+	if len(reason) == 0 {
+		return nil
+	}
+
+	return apierrors.NewForbidden(
+		v1.Resource("managedclusters"),
+		clusterName,
+		fmt.Errorf("%s, which is required by the cluster naming policy of the hub", reason),
+	)
+}
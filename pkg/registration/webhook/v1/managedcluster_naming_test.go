@@ -0,0 +1,111 @@
+package v1
+
+import (
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func newTestNamingPolicy(t *testing.T, pattern string, maxLength int, reservedPrefixes []string) *NamingPolicy {
+	policy, err := NewNamingPolicy(pattern, maxLength, reservedPrefixes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return policy
+}
+
+func TestNewNamingPolicy(t *testing.T) {
+	cases := []struct {
+		name             string
+		pattern          string
+		maxLength        int
+		reservedPrefixes []string
+		expectedNil      bool
+		expectedErr      bool
+	}{
+		{
+			name:             "no rules",
+			reservedPrefixes: []string{" "},
+			expectedNil:      true,
+		},
+		{
+			name:        "invalid pattern",
+			pattern:     "prod-[",
+			expectedErr: true,
+		},
+		{
+			name:        "negative max length",
+			maxLength:   -1,
+			expectedErr: true,
+		},
+		{
+			name:             "valid rules",
+			pattern:          "prod-.*",
+			maxLength:        20,
+			reservedPrefixes: []string{"local-"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			policy, err := NewNamingPolicy(c.pattern, c.maxLength, c.reservedPrefixes)
+			if (err != nil) != c.expectedErr {
+				t.Fatalf("expected error %v, but got %v", c.expectedErr, err)
+			}
+			if err == nil && (policy == nil) != c.expectedNil {
+				t.Errorf("expected nil policy %v, but got %v", c.expectedNil, policy)
+			}
+		})
+	}
+}
+
+func TestNamingPolicyValidate(t *testing.T) {
+	policy := newTestNamingPolicy(t, "[a-z]+-[a-z0-9]+", 12, []string{"local-", "hub-"})
+
+	cases := []struct {
+		name            string
+		clusterName     string
+		expectedMessage string
+	}{
+		{
+			name:        "valid name",
+			clusterName: "prod-east1",
+		},
+		{
+			name:            "too long",
+			clusterName:     "prod-us-east-1",
+			expectedMessage: "the cluster name is longer than 12 characters",
+		},
+		{
+			name:            "not match the whole pattern",
+			clusterName:     "prod-east_1",
+			expectedMessage: "the cluster name does not match the pattern \"[a-z]+-[a-z0-9]+\"",
+		},
+		{
+			name:            "reserved prefix",
+			clusterName:     "hub-east1",
+			expectedMessage: "the cluster name starts with the reserved prefix \"hub-\"",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := policy.Validate(c.clusterName)
+			if len(c.expectedMessage) == 0 {
+				if err != nil {
+					t.Errorf("expected no error, but got %v", err)
+				}
+				return
+			}
+			if !apierrors.IsForbidden(err) || !strings.Contains(err.Error(), c.expectedMessage) {
+				t.Errorf("expected forbidden error with %q, but got %v", c.expectedMessage, err)
+			}
+		})
+	}
+
+	var nilPolicy *NamingPolicy
+	if err := nilPolicy.Validate("any_name"); err != nil {
+		t.Errorf("expected no error of the nil policy, but got %v", err)
+	}
+}
@@ -41,6 +41,12 @@ func (r *ManagedClusterWebhook) ValidateCreate(ctx context.Context, obj runtime.
 		return nil, err
 	}
 
+	// the naming policy is enforced only when the cluster joins, so the existing clusters are not blocked from
+	// being updated once the policy is changed.
+	if err := r.namingPolicy.Validate(managedCluster.Name); err != nil {
+		return nil, err
+	}
+
 	// the HubAcceptsClient field is changed, we need to:
 	// 1. check whether cluster namespace is terminating.
 	// 2. check the request user whether has been allowed to change the HubAcceptsClient field with
@@ -220,7 +226,9 @@ func (r *ManagedClusterWebhook) allowSetClusterSetLabel(userInfo authenticationv
 		}
 	}
 
-	if len(newClusterSet) > 0 {
+	// the clusters without the clusterset label are added to the default clusterset of the hub by the webhook, so
+	// joining it does not require the permission.
+	if len(newClusterSet) > 0 && newClusterSet != r.defaultingPolicy.defaultClusterSet() {
 		err := r.allowUpdateClusterSet(userInfo, newClusterSet)
 		if err != nil {
 			return err
@@ -26,6 +26,8 @@ func TestValidateCreate(t *testing.T) {
 		allowUpdateAcceptField bool
 		allowClusterset        bool
 		allowUpdateClusterSets map[string]bool
+		namingPolicy           *NamingPolicy
+		defaultingPolicy       *DefaultingPolicy
 	}{
 		{
 			name:          "Empty spec cluster",
@@ -180,6 +182,52 @@ func TestValidateCreate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:             "joining the default clusterset without permission",
+			expectedError:    false,
+			defaultingPolicy: &DefaultingPolicy{ClusterSet: "clusterset1"},
+			cluster: &v1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "set",
+					Labels: map[string]string{
+						v1beta2.ClusterSetLabel: "clusterset1",
+					},
+				},
+			},
+		},
+		{
+			name:             "joining other clusterset than the default one without permission",
+			expectedError:    true,
+			defaultingPolicy: &DefaultingPolicy{ClusterSet: "clusterset1"},
+			cluster: &v1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "set",
+					Labels: map[string]string{
+						v1beta2.ClusterSetLabel: "clusterset2",
+					},
+				},
+			},
+		},
+		{
+			name:          "cluster name follows the naming policy",
+			expectedError: false,
+			namingPolicy:  newTestNamingPolicy(t, "prod-[a-z0-9-]+", 0, nil),
+			cluster: &v1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "prod-east-1",
+				},
+			},
+		},
+		{
+			name:          "cluster name violates the naming policy",
+			expectedError: true,
+			namingPolicy:  newTestNamingPolicy(t, "prod-[a-z0-9-]+", 0, nil),
+			cluster: &v1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "dev-east-1",
+				},
+			},
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -206,7 +254,9 @@ func TestValidateCreate(t *testing.T) {
 				},
 			)
 			w := ManagedClusterWebhook{
-				kubeClient: kubeClient,
+				kubeClient:       kubeClient,
+				namingPolicy:     c.namingPolicy,
+				defaultingPolicy: c.defaultingPolicy,
 			}
 			req := admission.Request{
 				AdmissionRequest: admissionv1.AdmissionRequest{
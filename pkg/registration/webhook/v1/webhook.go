@@ -8,7 +8,10 @@ import (
 )
 
 type ManagedClusterWebhook struct {
-	kubeClient kubernetes.Interface
+	kubeClient   kubernetes.Interface
+	namingPolicy *NamingPolicy
+	// defaultingPolicy is the defaults set on the ManagedClusters, nothing is defaulted if it is nil.
+	defaultingPolicy *DefaultingPolicy
 }
 
 func (r *ManagedClusterWebhook) Init(mgr ctrl.Manager) error {
@@ -25,6 +28,16 @@ func (r *ManagedClusterWebhook) SetExternalKubeClientSet(client kubernetes.Inter
 	r.kubeClient = client
 }
 
+// SetNamingPolicy sets the naming policy enforced on the names of the ManagedClusters being created.
+func (r *ManagedClusterWebhook) SetNamingPolicy(policy *NamingPolicy) {
+	r.namingPolicy = policy
+}
+
+// SetDefaultingPolicy sets the defaults set on the ManagedClusters being created or updated.
+func (r *ManagedClusterWebhook) SetDefaultingPolicy(policy *DefaultingPolicy) {
+	r.defaultingPolicy = policy
+}
+
 func (r *ManagedClusterWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		WithValidator(r).
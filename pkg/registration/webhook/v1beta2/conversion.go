@@ -0,0 +1,40 @@
+package v1beta2
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"open-cluster-management.io/api/cluster/v1beta2"
+
+	"open-cluster-management.io/ocm/pkg/common/conversion"
+)
+
+const (
+	// v1beta1Version is the deprecated version of the ManagedClusterSet and the ManagedClusterSetBinding
+	v1beta1Version = "v1beta1"
+	// legacyClusterSetLabel is the v1beta1 selector type renamed to ExclusiveClusterSetLabel in v1beta2
+	legacyClusterSetLabel = "LegacyClusterSetLabel"
+)
+
+// RegisterConversions registers the conversions between v1beta1 and v1beta2 of the ManagedClusterSet and the
+// ManagedClusterSetBinding, v1beta2 is the hub version.
+func RegisterConversions(converter *conversion.Converter) error {
+	if err := converter.Register(v1beta2.GroupVersion.WithKind("ManagedClusterSet").GroupKind(),
+		v1beta2.GroupVersion.Version, v1beta1Version,
+		renameSelectorType(legacyClusterSetLabel, string(v1beta2.ExclusiveClusterSetLabel)),
+		renameSelectorType(string(v1beta2.ExclusiveClusterSetLabel), legacyClusterSetLabel)); err != nil {
+		return err
+	}
+	// the schema of the ManagedClusterSetBinding is not changed in v1beta2
+	return converter.Register(v1beta2.GroupVersion.WithKind("ManagedClusterSetBinding").GroupKind(),
+		v1beta2.GroupVersion.Version, v1beta1Version, nil, nil)
+}
+
+func renameSelectorType(from, to string) conversion.ConvertFunc {
+	return func(obj *unstructured.Unstructured) error {
+		selectorType, found, err := unstructured.NestedString(obj.Object, "spec", "clusterSelector", "selectorType")
+		if err != nil || !found || selectorType != from {
+			return err
+		}
+		return unstructured.SetNestedField(obj.Object, to, "spec", "clusterSelector", "selectorType")
+	}
+}
@@ -0,0 +1,101 @@
+package v1beta2
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"open-cluster-management.io/ocm/pkg/common/conversion"
+)
+
+func newUnstructuredClusterSet(version, selectorType string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cluster.open-cluster-management.io/" + version,
+		"kind":       "ManagedClusterSet",
+		"metadata":   map[string]interface{}{"name": "set-1"},
+		"spec": map[string]interface{}{
+			"clusterSelector": map[string]interface{}{"selectorType": selectorType},
+		},
+	}}
+}
+
+func newUnstructuredClusterSetBinding(version string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cluster.open-cluster-management.io/" + version,
+		"kind":       "ManagedClusterSetBinding",
+		"metadata":   map[string]interface{}{"name": "set-1", "namespace": "ns-1"},
+		"spec":       map[string]interface{}{"clusterSet": "set-1"},
+	}}
+}
+
+func TestConversions(t *testing.T) {
+	converter := conversion.NewConverter()
+	if err := RegisterConversions(converter); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name      string
+		obj       *unstructured.Unstructured
+		toVersion string
+		expected  *unstructured.Unstructured
+	}{
+		{
+			name:      "legacy clusterset to v1beta2",
+			obj:       newUnstructuredClusterSet("v1beta1", "LegacyClusterSetLabel"),
+			toVersion: "v1beta2",
+			expected:  newUnstructuredClusterSet("v1beta2", "ExclusiveClusterSetLabel"),
+		},
+		{
+			name:      "exclusive clusterset to v1beta1",
+			obj:       newUnstructuredClusterSet("v1beta2", "ExclusiveClusterSetLabel"),
+			toVersion: "v1beta1",
+			expected:  newUnstructuredClusterSet("v1beta1", "LegacyClusterSetLabel"),
+		},
+		{
+			name:      "label selector clusterset to v1beta1",
+			obj:       newUnstructuredClusterSet("v1beta2", "LabelSelector"),
+			toVersion: "v1beta1",
+			expected:  newUnstructuredClusterSet("v1beta1", "LabelSelector"),
+		},
+		{
+			name: "clusterset without selector to v1beta2",
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "cluster.open-cluster-management.io/v1beta1",
+				"kind":       "ManagedClusterSet",
+			}},
+			toVersion: "v1beta2",
+			expected: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "cluster.open-cluster-management.io/v1beta2",
+				"kind":       "ManagedClusterSet",
+			}},
+		},
+		{
+			name:      "clustersetbinding to v1beta1",
+			obj:       newUnstructuredClusterSetBinding("v1beta2"),
+			toVersion: "v1beta1",
+			expected:  newUnstructuredClusterSetBinding("v1beta1"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			converted, err := converter.Convert(c.obj, c.toVersion)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equality.Semantic.DeepEqual(converted, c.expected) {
+				t.Errorf("expected %v, but got %v", c.expected, converted)
+			}
+
+			back, err := converter.Convert(converted, c.obj.GroupVersionKind().Version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equality.Semantic.DeepEqual(back, c.obj) {
+				t.Errorf("expected %v is not changed in the round trip, but got %v", c.obj, back)
+			}
+		})
+	}
+}
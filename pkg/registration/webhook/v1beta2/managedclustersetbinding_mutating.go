@@ -0,0 +1,58 @@
+package v1beta2
+
+import (
+	"context"
+	"encoding/json"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"open-cluster-management.io/api/cluster/v1beta2"
+
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+)
+
+var _ webhook.CustomDefaulter = &ManagedClusterSetBindingWebhook{}
+
+// Default records the user who creates the ManagedClusterSetBinding, and keeps the record unchanged on update,
+// so the hub controller is able to check if the user is still allowed to bind the ManagedClusterSet.
+func (b *ManagedClusterSetBindingWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	binding, ok := obj.(*v1beta2.ManagedClusterSetBinding)
+	if !ok {
+		return apierrors.NewBadRequest("Request clustersetbinding obj format is not right")
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+
+	if req.Operation != admissionv1.Update || len(req.OldObject.Raw) == 0 {
+		if err := commonhelpers.SetClusterSetBindingBoundBy(binding, req.UserInfo); err != nil {
+			return apierrors.NewBadRequest(err.Error())
+		}
+		return nil
+	}
+
+	oldBinding := &v1beta2.ManagedClusterSetBinding{}
+	if err := json.Unmarshal(req.OldObject.Raw, oldBinding); err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+	annotations := binding.GetAnnotations()
+	value, ok := oldBinding.GetAnnotations()[commonhelpers.ClusterSetBindingBoundByAnnotationKey]
+	switch {
+	case ok:
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[commonhelpers.ClusterSetBindingBoundByAnnotationKey] = value
+	default:
+		// the binding created before the annotation was introduced has no record of its creator
+		delete(annotations, commonhelpers.ClusterSetBindingBoundByAnnotationKey)
+	}
+	binding.SetAnnotations(annotations)
+	return nil
+}
@@ -0,0 +1,103 @@
+package v1beta2
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"open-cluster-management.io/api/cluster/v1beta2"
+
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+)
+
+func TestDefault(t *testing.T) {
+	cases := []struct {
+		name            string
+		operation       admissionv1.Operation
+		oldAnnotations  map[string]string
+		annotations     map[string]string
+		expectedBoundBy string
+	}{
+		{
+			name:            "record the creator",
+			operation:       admissionv1.Create,
+			expectedBoundBy: "user1",
+		},
+		{
+			name:            "override the creator set by the user",
+			operation:       admissionv1.Create,
+			annotations:     map[string]string{commonhelpers.ClusterSetBindingBoundByAnnotationKey: `{"username":"admin"}`},
+			expectedBoundBy: "user1",
+		},
+		{
+			name:            "keep the creator on update",
+			operation:       admissionv1.Update,
+			oldAnnotations:  map[string]string{commonhelpers.ClusterSetBindingBoundByAnnotationKey: `{"username":"user2"}`},
+			annotations:     map[string]string{commonhelpers.ClusterSetBindingBoundByAnnotationKey: `{"username":"admin"}`},
+			expectedBoundBy: "user2",
+		},
+		{
+			name:        "no creator recorded before",
+			operation:   admissionv1.Update,
+			annotations: map[string]string{commonhelpers.ClusterSetBindingBoundByAnnotationKey: `{"username":"admin"}`},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			binding := newBinding(c.annotations)
+			req := admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: c.operation,
+					UserInfo:  authenticationv1.UserInfo{Username: "user1", Groups: []string{"group1"}},
+				},
+			}
+			if c.operation == admissionv1.Update {
+				raw, err := json.Marshal(newBinding(c.oldAnnotations))
+				if err != nil {
+					t.Fatal(err)
+				}
+				req.OldObject = runtime.RawExtension{Raw: raw}
+			}
+
+			w := ManagedClusterSetBindingWebhook{}
+			if err := w.Default(admission.NewContextWithRequest(context.Background(), req), binding); err != nil {
+				t.Fatal(err)
+			}
+
+			userInfo, err := commonhelpers.GetClusterSetBindingBoundBy(binding)
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch {
+			case len(c.expectedBoundBy) == 0 && userInfo != nil:
+				t.Errorf("expected no creator, but got %v", userInfo)
+			case len(c.expectedBoundBy) > 0 && (userInfo == nil || userInfo.Username != c.expectedBoundBy):
+				t.Errorf("expected creator %q, but got %v", c.expectedBoundBy, userInfo)
+			}
+		})
+	}
+}
+
+func newBinding(annotations map[string]string) *v1beta2.ManagedClusterSetBinding {
+	copied := map[string]string{}
+	for k, v := range annotations {
+		copied[k] = v
+	}
+	return &v1beta2.ManagedClusterSetBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns-1",
+			Name:        "setbinding-1",
+			Annotations: copied,
+		},
+		Spec: v1beta2.ManagedClusterSetBindingSpec{
+			ClusterSet: "setbinding-1",
+		},
+	}
+}
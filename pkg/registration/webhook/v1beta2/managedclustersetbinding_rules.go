@@ -0,0 +1,195 @@
+package v1beta2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/yaml"
+
+	"open-cluster-management.io/api/cluster/v1beta2"
+)
+
+const (
+	// BindingRulesConfigMapKey is the key of the rules in the ConfigMap of the binding rules. The value is a yaml
+	// list of rules, each rule has a CEL expression which must be evaluated to true to create a
+	// ManagedClusterSetBinding, and an optional message returned when the expression is evaluated to false.
+	// The expressions are able to access:
+	//   - object: the ManagedClusterSetBinding to create, e.g. object.metadata.namespace
+	//   - request: the admission request, with the namespace, the operation and the userInfo of the requester,
+	//     e.g. request.userInfo.username
+	// For example, only the namespaces prefixed with "admin-" are able to bind the global cluster set:
+	//   - name: global-binding
+	//     expression: object.spec.clusterSet != "global" || object.metadata.namespace.startsWith("admin-")
+	//     message: only the admin namespaces are able to bind the global cluster set
+	BindingRulesConfigMapKey = "rules"
+
+	// bindingRuleCostLimit limits the cost of evaluating an expression against a binding.
+	bindingRuleCostLimit = 1000000
+)
+
+var bindingsResource = v1beta2.GroupVersion.WithResource("managedclustersetbindings").GroupResource()
+
+// BindingRule is a CEL validation rule of the ManagedClusterSetBinding creation.
+type BindingRule struct {
+	Name       string `json:"name,omitempty"`
+	Expression string `json:"expression"`
+	Message    string `json:"message,omitempty"`
+}
+
+type compiledBindingRule struct {
+	BindingRule
+	program cel.Program
+}
+
+// bindingRuleValidator loads the binding rules from a ConfigMap, the compiled rules are cached until the
+// ConfigMap changes.
+type bindingRuleValidator struct {
+	namespace string
+	name      string
+
+	lock            sync.Mutex
+	resourceVersion string
+	rules           []compiledBindingRule
+	compileErr      error
+}
+
+// newBindingRuleValidator returns a validator of the rules in the ConfigMap with the key <namespace>/<name>.
+func newBindingRuleValidator(configMapKey string) (*bindingRuleValidator, error) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(configMapKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(namespace) == 0 || len(name) == 0 {
+		return nil, fmt.Errorf("the binding rules configmap %q is not in the format of <namespace>/<name>", configMapKey)
+	}
+	return &bindingRuleValidator{namespace: namespace, name: name}, nil
+}
+
+// validate denies the binding if any of the rules is not evaluated to true. The binding is allowed if the
+// ConfigMap does not exist, and denied if the rules in the ConfigMap are invalid.
+func (v *bindingRuleValidator) validate(ctx context.Context, kubeClient kubernetes.Interface,
+	binding *v1beta2.ManagedClusterSetBinding, namespace, operation string, userInfo authenticationv1.UserInfo) error {
+	rules, err := v.loadRules(ctx, kubeClient)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(binding)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	groups := []interface{}{}
+	for _, group := range userInfo.Groups {
+		groups = append(groups, group)
+	}
+	input := map[string]interface{}{
+		"object": obj,
+		"request": map[string]interface{}{
+			"namespace": namespace,
+			"operation": operation,
+			"userInfo": map[string]interface{}{
+				"username": userInfo.Username,
+				"uid":      userInfo.UID,
+				"groups":   groups,
+			},
+		},
+	}
+
+	for _, rule := range rules {
+		out, _, err := rule.program.Eval(input)
+		if err != nil {
+			return apierrors.NewForbidden(bindingsResource, binding.Name,
+				fmt.Errorf("failed to evaluate the rule %s: %v", rule.displayName(), err))
+		}
+		if allowed, ok := out.Value().(bool); !ok || !allowed {
+			return apierrors.NewForbidden(bindingsResource, binding.Name, errors.New(rule.denyMessage()))
+		}
+	}
+	return nil
+}
+
+func (v *bindingRuleValidator) loadRules(ctx context.Context, kubeClient kubernetes.Interface) ([]compiledBindingRule, error) {
+	configMap, err := kubeClient.CoreV1().ConfigMaps(v.namespace).Get(ctx, v.name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, apierrors.NewInternalError(err)
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if configMap.ResourceVersion != v.resourceVersion || len(configMap.ResourceVersion) == 0 {
+		v.rules, v.compileErr = compileBindingRules(configMap.Data[BindingRulesConfigMapKey])
+		v.resourceVersion = configMap.ResourceVersion
+	}
+	if v.compileErr != nil {
+		return nil, apierrors.NewForbidden(bindingsResource, "",
+			fmt.Errorf("the binding rules in configmap %s/%s are invalid: %v", v.namespace, v.name, v.compileErr))
+	}
+	return v.rules, nil
+}
+
+// compileBindingRules parses the rules and compiles their expressions to programs.
+func compileBindingRules(value string) ([]compiledBindingRule, error) {
+	var rules []BindingRule
+	if err := yaml.Unmarshal([]byte(value), &rules); err != nil {
+		return nil, fmt.Errorf("the rules are not a list of rules: %v", err)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	env, err := cel.NewEnv(
+		cel.Variable("object", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("request", cel.MapType(cel.StringType, cel.DynType)),
+		ext.Strings(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var compiled []compiledBindingRule
+	for _, rule := range rules {
+		ast, issues := env.Compile(rule.Expression)
+		if issues.Err() != nil {
+			return nil, fmt.Errorf("failed to compile expression %q: %v", rule.Expression, issues.Err())
+		}
+		if !cel.BoolType.IsAssignableType(ast.OutputType()) {
+			return nil, fmt.Errorf("the expression %q is not evaluated to a bool but %v", rule.Expression, ast.OutputType())
+		}
+		program, err := env.Program(ast, cel.CostLimit(bindingRuleCostLimit))
+		if err != nil {
+			return nil, fmt.Errorf("failed to build program of expression %q: %v", rule.Expression, err)
+		}
+		compiled = append(compiled, compiledBindingRule{BindingRule: rule, program: program})
+	}
+	return compiled, nil
+}
+
+func (r compiledBindingRule) displayName() string {
+	if len(r.Name) > 0 {
+		return r.Name
+	}
+	return fmt.Sprintf("%q", r.Expression)
+}
+
+func (r compiledBindingRule) denyMessage() string {
+	if len(r.Message) > 0 {
+		return r.Message
+	}
+	return fmt.Sprintf("the binding is denied by the rule %s", r.displayName())
+}
@@ -0,0 +1,129 @@
+package v1beta2
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"open-cluster-management.io/api/cluster/v1beta2"
+)
+
+const testRules = `
+- name: global-binding
+  expression: object.spec.clusterSet != "global" || object.metadata.namespace.startsWith("admin-")
+  message: only the admin namespaces are able to bind the global cluster set
+- expression: request.userInfo.username != "blocked"
+`
+
+func newRulesConfigMap(rules string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "open-cluster-management-hub",
+			Name:            "binding-rules",
+			ResourceVersion: "1",
+		},
+		Data: map[string]string{BindingRulesConfigMapKey: rules},
+	}
+}
+
+func newRuleBinding(namespace, clusterSet string) *v1beta2.ManagedClusterSetBinding {
+	return &v1beta2.ManagedClusterSetBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: clusterSet},
+		Spec:       v1beta2.ManagedClusterSetBindingSpec{ClusterSet: clusterSet},
+	}
+}
+
+func TestValidateCreateWithRules(t *testing.T) {
+	cases := []struct {
+		name          string
+		configMaps    []runtime.Object
+		binding       *v1beta2.ManagedClusterSetBinding
+		username      string
+		expectedError string
+	}{
+		{
+			name:     "no configmap",
+			binding:  newRuleBinding("ns-1", "global"),
+			username: "user",
+		},
+		{
+			name:       "allowed by rules",
+			configMaps: []runtime.Object{newRulesConfigMap(testRules)},
+			binding:    newRuleBinding("admin-1", "global"),
+			username:   "user",
+		},
+		{
+			name:          "denied with message",
+			configMaps:    []runtime.Object{newRulesConfigMap(testRules)},
+			binding:       newRuleBinding("ns-1", "global"),
+			username:      "user",
+			expectedError: "only the admin namespaces are able to bind the global cluster set",
+		},
+		{
+			name:          "denied without message",
+			configMaps:    []runtime.Object{newRulesConfigMap(testRules)},
+			binding:       newRuleBinding("ns-1", "set-1"),
+			username:      "blocked",
+			expectedError: `the binding is denied by the rule "request.userInfo.username != \"blocked\""`,
+		},
+		{
+			name:          "invalid rules",
+			configMaps:    []runtime.Object{newRulesConfigMap(`- expression: object.spec.clusterSet`)},
+			binding:       newRuleBinding("ns-1", "set-1"),
+			username:      "user",
+			expectedError: "the binding rules in configmap open-cluster-management-hub/binding-rules are invalid",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset(c.configMaps...)
+			kubeClient.PrependReactor(
+				"create",
+				"subjectaccessreviews",
+				func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, &authorizationv1.SubjectAccessReview{
+						Status: authorizationv1.SubjectAccessReviewStatus{Allowed: true},
+					}, nil
+				},
+			)
+			w := ManagedClusterSetBindingWebhook{kubeClient: kubeClient}
+			if err := w.SetBindingRulesConfigMap("open-cluster-management-hub/binding-rules"); err != nil {
+				t.Fatal(err)
+			}
+			req := admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Namespace: c.binding.Namespace,
+					Operation: admissionv1.Create,
+					UserInfo:  authenticationv1.UserInfo{Username: c.username},
+				},
+			}
+
+			_, err := w.ValidateCreate(admission.NewContextWithRequest(context.Background(), req), c.binding)
+			switch {
+			case len(c.expectedError) == 0 && err != nil:
+				t.Errorf("expect nil error but got %v", err)
+			case len(c.expectedError) > 0 && err == nil:
+				t.Errorf("expect error %q but got nil", c.expectedError)
+			case len(c.expectedError) > 0 && !strings.Contains(err.Error(), c.expectedError):
+				t.Errorf("expect error %q but got %v", c.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestSetBindingRulesConfigMap(t *testing.T) {
+	w := ManagedClusterSetBindingWebhook{}
+	if err := w.SetBindingRulesConfigMap("binding-rules"); err == nil {
+		t.Errorf("expect error of the configmap without namespace but got nil")
+	}
+}
@@ -36,7 +36,14 @@ func (b *ManagedClusterSetBindingWebhook) ValidateCreate(ctx context.Context, ob
 	if err != nil {
 		return nil, apierrors.NewBadRequest(err.Error())
 	}
-	return nil, AllowBindingToClusterSet(b.kubeClient, binding.Spec.ClusterSet, req.UserInfo)
+	if err := AllowBindingToClusterSet(b.kubeClient, binding.Spec.ClusterSet, req.UserInfo); err != nil {
+		return nil, err
+	}
+
+	if b.ruleValidator == nil {
+		return nil, nil
+	}
+	return nil, b.ruleValidator.validate(ctx, b.kubeClient, binding, req.Namespace, string(req.Operation), req.UserInfo)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -30,7 +30,8 @@ type ManagedClusterSet struct {
 }
 
 type ManagedClusterSetBindingWebhook struct {
-	kubeClient kubernetes.Interface
+	kubeClient    kubernetes.Interface
+	ruleValidator *bindingRuleValidator
 }
 
 func (src *ManagedClusterSet) SetupWebhookWithManager(mgr ctrl.Manager) error {
@@ -53,9 +54,21 @@ func (b *ManagedClusterSetBindingWebhook) SetExternalKubeClientSet(client kubern
 	b.kubeClient = client
 }
 
+// SetBindingRulesConfigMap enables the CEL validation rules of the binding creation in the ConfigMap with the
+// key <namespace>/<name>.
+func (b *ManagedClusterSetBindingWebhook) SetBindingRulesConfigMap(configMapKey string) error {
+	validator, err := newBindingRuleValidator(configMapKey)
+	if err != nil {
+		return err
+	}
+	b.ruleValidator = validator
+	return nil
+}
+
 func (b *ManagedClusterSetBindingWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		WithValidator(b).
+		WithDefaulter(b).
 		For(&v1beta2.ManagedClusterSetBinding{}).
 		Complete()
 }
@@ -0,0 +1,61 @@
+package testing
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+// HasCondition returns true if the conditions have the expected type with the expected status and reason, the
+// reason is not checked if it is empty.
+func HasCondition(
+	conditions []metav1.Condition,
+	expectedType, expectedReason string,
+	expectedStatus metav1.ConditionStatus,
+) bool {
+
+	for _, condition := range conditions {
+		if condition.Type != expectedType {
+			continue
+		}
+
+		if condition.Status != expectedStatus {
+			return false
+		}
+
+		// skip checking reason
+		if len(expectedReason) == 0 {
+			return true
+		}
+
+		if condition.Reason != expectedReason {
+			return false
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// HaveManifestCondition returns true if the condition of the expected type of each manifest has the expected
+// status in order, the manifest is skipped if its expected status is empty.
+func HaveManifestCondition(conditions []workapiv1.ManifestCondition, expectedType string, expectedStatuses []metav1.ConditionStatus) bool {
+	if len(conditions) != len(expectedStatuses) {
+		return false
+	}
+
+	for index, condition := range conditions {
+		expectedStatus := expectedStatuses[index]
+		if expectedStatus == "" {
+			continue
+		}
+
+		if ok := meta.IsStatusConditionPresentAndEqual(condition.Conditions, expectedType, expectedStatus); !ok {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,7 @@
+// Package testing provides the helpers to run an in-process hub and agents of ocm on a kube-apiserver started by
+// envtest, so the addon and the downstream developers could run the registration and work components in their own
+// test suites. The integration tests of this repo are built on the same helpers.
+//
+// The kube-apiserver and etcd binaries are required by envtest, they are located by the KUBEBUILDER_ASSETS
+// environment variable.
+package testing
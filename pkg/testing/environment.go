@@ -0,0 +1,164 @@
+package testing
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/openshift/library-go/pkg/controller/controllercmd"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	addonclientset "open-cluster-management.io/api/client/addon/clientset/versioned"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+
+	commonoptions "open-cluster-management.io/ocm/pkg/common/options"
+	registrationhub "open-cluster-management.io/ocm/pkg/registration/hub"
+	registrationspoke "open-cluster-management.io/ocm/pkg/registration/spoke"
+	workhub "open-cluster-management.io/ocm/pkg/work/hub"
+	workspoke "open-cluster-management.io/ocm/pkg/work/spoke"
+)
+
+// HubCRDFiles are the CRD files of the hub APIs, relative to the root of the open-cluster-management.io/api module.
+var HubCRDFiles = []string{
+	"cluster/v1/0000_00_clusters.open-cluster-management.io_managedclusters.crd.yaml",
+	"cluster/v1beta2/0000_00_clusters.open-cluster-management.io_managedclustersets.crd.yaml",
+	"cluster/v1beta2/0000_01_clusters.open-cluster-management.io_managedclustersetbindings.crd.yaml",
+	"cluster/v1beta1/0000_02_clusters.open-cluster-management.io_placements.crd.yaml",
+	"cluster/v1beta1/0000_03_clusters.open-cluster-management.io_placementdecisions.crd.yaml",
+	"cluster/v1alpha1/0000_05_clusters.open-cluster-management.io_addonplacementscores.crd.yaml",
+	"work/v1/0000_00_work.open-cluster-management.io_manifestworks.crd.yaml",
+	"work/v1alpha1/0000_00_work.open-cluster-management.io_manifestworkreplicasets.crd.yaml",
+	"addon/v1alpha1/0000_00_addon.open-cluster-management.io_clustermanagementaddons.crd.yaml",
+	"addon/v1alpha1/0000_01_addon.open-cluster-management.io_managedclusteraddons.crd.yaml",
+	"addon/v1alpha1/0000_02_addon.open-cluster-management.io_addondeploymentconfigs.crd.yaml",
+	"addon/v1alpha1/0000_03_addon.open-cluster-management.io_addontemplates.crd.yaml",
+}
+
+// SpokeCRDFiles are the CRD files of the spoke APIs, relative to the root of the open-cluster-management.io/api
+// module.
+var SpokeCRDFiles = []string{
+	"cluster/v1alpha1/0000_02_clusters.open-cluster-management.io_clusterclaims.crd.yaml",
+	"work/v1/0000_01_work.open-cluster-management.io_appliedmanifestworks.crd.yaml",
+}
+
+// CRDPaths returns the paths of the CRD files under apiDir, which is the root of the open-cluster-management.io/api
+// module, e.g. "vendor/open-cluster-management.io/api" or the directory of the module in the module cache.
+func CRDPaths(apiDir string, files []string) []string {
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, filepath.Join(apiDir, file))
+	}
+	return paths
+}
+
+// NewEnvironment returns an envtest environment which installs the hub and spoke CRDs under apiDir, so the hub and
+// the agents could run against the same kube-apiserver.
+func NewEnvironment(apiDir string) *envtest.Environment {
+	return &envtest.Environment{
+		ErrorIfCRDPathMissing: true,
+		CRDDirectoryPaths:     append(CRDPaths(apiDir, HubCRDFiles), CRDPaths(apiDir, SpokeCRDFiles)...),
+	}
+}
+
+// Hub is a kube-apiserver started by envtest with the clients of the ocm APIs.
+type Hub struct {
+	Env    *envtest.Environment
+	Config *rest.Config
+
+	KubeClient    kubernetes.Interface
+	ClusterClient clusterclientset.Interface
+	WorkClient    workclientset.Interface
+	AddOnClient   addonclientset.Interface
+}
+
+// StartHub starts the kube-apiserver of the env and builds the clients, the env is stopped if the clients fail to
+// be built.
+func StartHub(env *envtest.Environment) (*Hub, error) {
+	cfg, err := env.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	hub, err := newHub(env, cfg)
+	if err != nil {
+		_ = env.Stop()
+		return nil, err
+	}
+	return hub, nil
+}
+
+func newHub(env *envtest.Environment, cfg *rest.Config) (*Hub, error) {
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	workClient, err := workclientset.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	addOnClient, err := addonclientset.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Hub{
+		Env:           env,
+		Config:        cfg,
+		KubeClient:    kubeClient,
+		ClusterClient: clusterClient,
+		WorkClient:    workClient,
+		AddOnClient:   addOnClient,
+	}, nil
+}
+
+// Stop stops the kube-apiserver of the hub.
+func (h *Hub) Stop() error {
+	return h.Env.Stop()
+}
+
+// RunRegistrationHub runs the registration controllers of the hub with the options until the ctx is done, the
+// default options are used if opts is nil.
+func RunRegistrationHub(ctx context.Context, cfg *rest.Config, opts *registrationhub.HubManagerOptions,
+	recorder events.Recorder) error {
+	if opts == nil {
+		opts = registrationhub.NewHubManagerOptions()
+	}
+	return opts.RunControllerManager(ctx, newControllerContext(cfg, recorder))
+}
+
+// RunWorkHub runs the work controllers of the hub with the options until the ctx is done, the default options are
+// used if opts is nil.
+func RunWorkHub(ctx context.Context, cfg *rest.Config, opts *workhub.WorkHubManagerOptions, recorder events.Recorder) error {
+	if opts == nil {
+		opts = workhub.NewWorkHubManagerOptions()
+	}
+	return opts.RunWorkHubManager(ctx, newControllerContext(cfg, recorder))
+}
+
+// RunRegistrationAgent runs the registration agent with the options until the ctx is done, cfg is the config of
+// the managed cluster, and the agent registers to the hub with the bootstrap kubeconfig of the options.
+func RunRegistrationAgent(ctx context.Context, cfg *rest.Config, commonOpts *commonoptions.AgentOptions,
+	opts *registrationspoke.SpokeAgentOptions, recorder events.Recorder) error {
+	return registrationspoke.NewSpokeAgentConfig(commonOpts, opts).RunSpokeAgent(ctx, newControllerContext(cfg, recorder))
+}
+
+// RunWorkAgent runs the work agent with the options until the ctx is done, cfg is the config of the managed
+// cluster, and the agent connects to the hub with the hub kubeconfig of the options.
+func RunWorkAgent(ctx context.Context, cfg *rest.Config, commonOpts *commonoptions.AgentOptions,
+	opts *workspoke.WorkloadAgentOptions, recorder events.Recorder) error {
+	return workspoke.NewWorkAgentConfig(commonOpts, opts).RunWorkloadAgent(ctx, newControllerContext(cfg, recorder))
+}
+
+func newControllerContext(cfg *rest.Config, recorder events.Recorder) *controllercmd.ControllerContext {
+	return &controllercmd.ControllerContext{
+		KubeConfig:    cfg,
+		EventRecorder: recorder,
+	}
+}
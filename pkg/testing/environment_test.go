@@ -0,0 +1,64 @@
+package testing
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCRDPaths(t *testing.T) {
+	apiDir := "../../vendor/open-cluster-management.io/api"
+	env := NewEnvironment(apiDir)
+	if len(env.CRDDirectoryPaths) != len(HubCRDFiles)+len(SpokeCRDFiles) {
+		t.Fatalf("expected %d crds, but got %d", len(HubCRDFiles)+len(SpokeCRDFiles), len(env.CRDDirectoryPaths))
+	}
+	for _, path := range env.CRDDirectoryPaths {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected crd %s exists, but got %v", path, err)
+		}
+	}
+}
+
+func TestEventRecorder(t *testing.T) {
+	out := &bytes.Buffer{}
+	var recorder events.Recorder = NewEventRecorder("hub", out)
+	recorder.WithComponentSuffix("registration").Eventf("ClusterAccepted", "cluster %s is accepted", "cluster1")
+	recorder.Warning("ClusterDenied", "cluster2 is denied")
+
+	expected := "Event: [hub-registration] ClusterAccepted: cluster cluster1 is accepted \n" +
+		"Warning: [hub] ClusterDenied: cluster2 is denied \n"
+	if out.String() != expected {
+		t.Errorf("expected %q, but got %q", expected, out.String())
+	}
+}
+
+func TestHasCondition(t *testing.T) {
+	conditions := []metav1.Condition{
+		{Type: "Available", Status: metav1.ConditionTrue, Reason: "Ready"},
+	}
+
+	cases := []struct {
+		name           string
+		expectedType   string
+		expectedReason string
+		expectedStatus metav1.ConditionStatus
+		expected       bool
+	}{
+		{name: "matched", expectedType: "Available", expectedReason: "Ready", expectedStatus: metav1.ConditionTrue, expected: true},
+		{name: "reason skipped", expectedType: "Available", expectedStatus: metav1.ConditionTrue, expected: true},
+		{name: "reason mismatched", expectedType: "Available", expectedReason: "NotReady", expectedStatus: metav1.ConditionTrue},
+		{name: "status mismatched", expectedType: "Available", expectedStatus: metav1.ConditionFalse},
+		{name: "type missing", expectedType: "Degraded", expectedStatus: metav1.ConditionTrue},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := HasCondition(conditions, c.expectedType, c.expectedReason, c.expectedStatus)
+			if actual != c.expected {
+				t.Errorf("expected %v, but got %v", c.expected, actual)
+			}
+		})
+	}
+}
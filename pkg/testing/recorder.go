@@ -0,0 +1,57 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+// EventRecorder is an events.Recorder which writes the events to a writer instead of the apiserver, e.g. the
+// GinkgoWriter, so the events of the in-process components are shown with the output of the tests.
+type EventRecorder struct {
+	component string
+	out       io.Writer
+	ctx       context.Context
+}
+
+// NewEventRecorder returns an EventRecorder of the component which writes the events to out.
+func NewEventRecorder(component string, out io.Writer) events.Recorder {
+	return &EventRecorder{component: component, out: out}
+}
+
+func (r *EventRecorder) ComponentName() string {
+	return r.component
+}
+
+func (r *EventRecorder) ForComponent(c string) events.Recorder {
+	return &EventRecorder{component: c, out: r.out}
+}
+
+func (r *EventRecorder) WithComponentSuffix(suffix string) events.Recorder {
+	return r.ForComponent(fmt.Sprintf("%s-%s", r.ComponentName(), suffix))
+}
+
+func (r *EventRecorder) WithContext(ctx context.Context) events.Recorder {
+	r.ctx = ctx
+	return r
+}
+
+func (r *EventRecorder) Event(reason, message string) {
+	fmt.Fprintf(r.out, "Event: [%s] %v: %v \n", r.component, reason, message)
+}
+
+func (r *EventRecorder) Eventf(reason, messageFmt string, args ...interface{}) {
+	r.Event(reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *EventRecorder) Warning(reason, message string) {
+	fmt.Fprintf(r.out, "Warning: [%s] %v: %v \n", r.component, reason, message)
+}
+
+func (r *EventRecorder) Warningf(reason, messageFmt string, args ...interface{}) {
+	r.Warning(reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *EventRecorder) Shutdown() {}
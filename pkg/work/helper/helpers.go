@@ -162,7 +162,8 @@ func DeleteAppliedResources(
 			Namespace(resource.Namespace).
 			Get(ctx, resource.Name, metav1.GetOptions{})
 		if errors.IsNotFound(err) {
-			klog.V(2).Infof("Resource %v with key %s/%s is removed Successfully", gvr, resource.Namespace, resource.Name)
+			klog.FromContext(ctx).V(2).Info("Resource is removed successfully",
+				"gvr", gvr, "namespace", resource.Namespace, "name", resource.Name)
 			continue
 		}
 
@@ -352,7 +353,7 @@ func FindManifestConiguration(resourceMeta workapiv1.ManifestResourceMeta, manif
 }
 
 func ApplyOwnerReferences(ctx context.Context, dynamicClient dynamic.Interface, gvr schema.GroupVersionResource,
-	existing runtime.Object, requiredOwner metav1.OwnerReference) error {
+	existing runtime.Object, requiredOwners ...metav1.OwnerReference) error {
 	accessor, err := meta.Accessor(existing)
 	if err != nil {
 		return fmt.Errorf("type %t cannot be accessed: %v", existing, err)
@@ -360,11 +361,11 @@ func ApplyOwnerReferences(ctx context.Context, dynamicClient dynamic.Interface,
 	patch := &unstructured.Unstructured{}
 	patch.SetUID(accessor.GetUID())
 	patch.SetResourceVersion(accessor.GetResourceVersion())
-	patch.SetOwnerReferences([]metav1.OwnerReference{requiredOwner})
+	patch.SetOwnerReferences(requiredOwners)
 
 	modified := false
 	patchedOwner := accessor.GetOwnerReferences()
-	resourcemerge.MergeOwnerRefs(&modified, &patchedOwner, []metav1.OwnerReference{requiredOwner})
+	resourcemerge.MergeOwnerRefs(&modified, &patchedOwner, requiredOwners)
 	patch.SetOwnerReferences(patchedOwner)
 
 	if !modified {
@@ -376,7 +377,8 @@ func ApplyOwnerReferences(ctx context.Context, dynamicClient dynamic.Interface,
 		return err
 	}
 
-	klog.V(2).Infof("Patching resource %v %s/%s with patch %s", gvr, accessor.GetNamespace(), accessor.GetName(), string(patchData))
+	klog.FromContext(ctx).V(2).Info("Patching resource",
+		"gvr", gvr, "namespace", accessor.GetNamespace(), "name", accessor.GetName(), "patch", string(patchData))
 	_, err = dynamicClient.Resource(gvr).Namespace(accessor.GetNamespace()).Patch(ctx, accessor.GetName(), types.MergePatchType, patchData, metav1.PatchOptions{})
 	return err
 }
@@ -0,0 +1,21 @@
+package helper
+
+import (
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+const (
+	// PriorityClassAnnotationKey is the annotation on the ManifestWork to set its priority class. The works of the
+	// Critical priority class, e.g. the security patches, are applied by a dedicated worker of the work agent, so they
+	// are not queued behind the bulk application rollouts during a large fan-out. The works are of the Normal
+	// priority class if it is not set. The annotation on a ManifestWorkReplicaSet is propagated to its works.
+	PriorityClassAnnotationKey = "work.open-cluster-management.io/priority-class"
+
+	PriorityClassCritical = "Critical"
+	PriorityClassNormal   = "Normal"
+)
+
+// IsCriticalManifestWork returns if the manifestwork is of the Critical priority class.
+func IsCriticalManifestWork(work *workapiv1.ManifestWork) bool {
+	return work.Annotations[PriorityClassAnnotationKey] == PriorityClassCritical
+}
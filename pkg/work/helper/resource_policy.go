@@ -0,0 +1,82 @@
+package helper
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ResourcePolicy restricts the kinds of the resources the hub is allowed to apply on the managed cluster. Each rule
+// is in the format of <Kind>.<group>, e.g. ClusterRole.rbac.authorization.k8s.io, the group is omitted for the core
+// group, e.g. Secret, and either of them could be * to match any, e.g. *.rbac.authorization.k8s.io or *.* for all
+// the resources. A resource is allowed if it matches none of the denied rules, and any of the allowed rules if there
+// is one.
+type ResourcePolicy struct {
+	allowed []resourceRule
+	denied  []resourceRule
+}
+
+type resourceRule struct {
+	kind  string
+	group string
+}
+
+// NewResourcePolicy returns the ResourcePolicy with the allowed and the denied rules, it returns nil if there is no
+// rule at all, which allows any resource.
+func NewResourcePolicy(allowed, denied []string) (*ResourcePolicy, error) {
+	policy := &ResourcePolicy{}
+	var err error
+	if policy.allowed, err = parseResourceRules(allowed); err != nil {
+		return nil, err
+	}
+	if policy.denied, err = parseResourceRules(denied); err != nil {
+		return nil, err
+	}
+	if len(policy.allowed) == 0 && len(policy.denied) == 0 {
+		return nil, nil
+	}
+	return policy, nil
+}
+
+func parseResourceRules(rules []string) ([]resourceRule, error) {
+	var parsed []resourceRule
+	for _, rule := range rules {
+		rule = strings.TrimSpace(rule)
+		if len(rule) == 0 {
+			continue
+		}
+		kind, group, _ := strings.Cut(rule, ".")
+		if len(kind) == 0 || strings.ContainsAny(rule, " /\"") || (strings.Contains(rule, ".") && len(group) == 0) {
+			return nil, fmt.Errorf("invalid resource rule %q, it should be in the format of <Kind>.<group>", rule)
+		}
+		parsed = append(parsed, resourceRule{kind: kind, group: group})
+	}
+	return parsed, nil
+}
+
+// Allowed returns if the resource of the kind is allowed to be applied by the policy. Any resource is allowed if
+// the policy is nil.
+func (p *ResourcePolicy) Allowed(gvk schema.GroupVersionKind) bool {
+	if p == nil {
+		return true
+	}
+	for _, rule := range p.denied {
+		if rule.matches(gvk) {
+			return false
+		}
+	}
+	if len(p.allowed) == 0 {
+		return true
+	}
+	for _, rule := range p.allowed {
+		if rule.matches(gvk) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r resourceRule) matches(gvk schema.GroupVersionKind) bool {
+	return (r.kind == "*" || r.kind == gvk.Kind) && (r.group == "*" || r.group == gvk.Group)
+}
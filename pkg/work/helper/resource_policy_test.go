@@ -0,0 +1,82 @@
+package helper
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestResourcePolicy(t *testing.T) {
+	secret := schema.GroupVersionKind{Version: "v1", Kind: "Secret"}
+	configMap := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	clusterRole := schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}
+	deployment := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+
+	cases := []struct {
+		name         string
+		allowed      []string
+		denied       []string
+		expectedErr  bool
+		expectedNil  bool
+		allowedKinds []schema.GroupVersionKind
+		deniedKinds  []schema.GroupVersionKind
+	}{
+		{
+			name:         "no rules",
+			allowed:      []string{" "},
+			expectedNil:  true,
+			allowedKinds: []schema.GroupVersionKind{secret, clusterRole},
+		},
+		{
+			name:         "deny rules",
+			denied:       []string{"Secret", "*.rbac.authorization.k8s.io"},
+			allowedKinds: []schema.GroupVersionKind{configMap, deployment},
+			deniedKinds:  []schema.GroupVersionKind{secret, clusterRole},
+		},
+		{
+			name:         "allow rules",
+			allowed:      []string{"*", "Deployment.*"},
+			allowedKinds: []schema.GroupVersionKind{secret, configMap, deployment},
+			deniedKinds:  []schema.GroupVersionKind{clusterRole},
+		},
+		{
+			name:         "deny rules take precedence",
+			allowed:      []string{"*.*"},
+			denied:       []string{"Secret"},
+			allowedKinds: []schema.GroupVersionKind{configMap, clusterRole},
+			deniedKinds:  []schema.GroupVersionKind{secret},
+		},
+		{
+			name:        "invalid group",
+			denied:      []string{"Secret."},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid rule",
+			allowed:     []string{"apps/v1"},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			policy, err := NewResourcePolicy(c.allowed, c.denied)
+			if c.expectedErr != (err != nil) {
+				t.Fatalf("expected error %v, but got %v", c.expectedErr, err)
+			}
+			if c.expectedNil != (policy == nil) && !c.expectedErr {
+				t.Errorf("expected nil policy %v, but got %v", c.expectedNil, policy)
+			}
+			for _, gvk := range c.allowedKinds {
+				if !policy.Allowed(gvk) {
+					t.Errorf("expected %s is allowed", gvk)
+				}
+			}
+			for _, gvk := range c.deniedKinds {
+				if policy.Allowed(gvk) {
+					t.Errorf("expected %s is denied", gvk)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package manifestworkcleanupcontroller
+
+import (
+	"context"
+	"time"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+
+	clusterinformerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/logging"
+	"open-cluster-management.io/ocm/pkg/common/patcher"
+	"open-cluster-management.io/ocm/pkg/common/queue"
+	"open-cluster-management.io/ocm/pkg/work/helper"
+)
+
+// staleRecheckInterval is the maximum interval to recheck the manifestworks of a stale cluster, so the
+// manifestworks deleted after the last check are cleaned up in time.
+var staleRecheckInterval = 5 * time.Minute
+
+// ManifestWorkCleanupController force cleans up the manifestworks of the clusters which are detached from the
+// hub or unreachable beyond a grace period. The work agent of such a cluster is not able to remove the finalizer
+// of its manifestworks, so they are stuck in terminating forever without the cleanup.
+//
+// Once a cluster is stale, the manifestworks being deleted longer than the grace period have their finalizer
+// removed. The manifestworks of a detached (deleted or not found) cluster are deleted as well, while the
+// manifestworks of an unreachable cluster are left to the users. The AppliedManifestWorks on the managed cluster
+// are evicted by the work agent if the cluster comes back.
+type ManifestWorkCleanupController struct {
+	workClient    workclientset.Interface
+	clusterLister clusterlisterv1.ManagedClusterLister
+	gracePeriod   time.Duration
+	now           func() time.Time
+}
+
+// NewManifestWorkCleanupController returns a ManifestWorkCleanupController
+func NewManifestWorkCleanupController(
+	recorder events.Recorder,
+	workClient workclientset.Interface,
+	clusterInformer clusterinformerv1.ManagedClusterInformer,
+	gracePeriod time.Duration) factory.Controller {
+	controller := &ManifestWorkCleanupController{
+		workClient:    workClient,
+		clusterLister: clusterInformer.Lister(),
+		gracePeriod:   gracePeriod,
+		now:           time.Now,
+	}
+
+	return factory.New().
+		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterInformer.Informer()).
+		WithSync(logging.WithControllerLogger("ManifestWorkCleanupController", controller.sync)).
+		ToController("ManifestWorkCleanupController", recorder)
+}
+
+func (c *ManifestWorkCleanupController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
+	clusterName := controllerContext.QueueKey()
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling manifestworks of cluster", "cluster", clusterName)
+
+	cluster, err := c.clusterLister.Get(clusterName)
+	switch {
+	case errors.IsNotFound(err):
+		cluster = nil
+	case err != nil:
+		return err
+	}
+
+	staleSince, detached := c.staleSince(cluster)
+	if staleSince == nil {
+		return nil
+	}
+
+	// the cluster is unreachable, but not beyond the grace period yet
+	now := c.now()
+	if remaining := staleSince.Add(c.gracePeriod).Sub(now); remaining > 0 {
+		controllerContext.Queue().AddAfter(clusterName, remaining)
+		return nil
+	}
+
+	works, err := c.workClient.WorkV1().ManifestWorks(clusterName).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	workPatcher := patcher.NewPatcher[
+		*workapiv1.ManifestWork, workapiv1.ManifestWorkSpec, workapiv1.ManifestWorkStatus](
+		c.workClient.WorkV1().ManifestWorks(clusterName))
+
+	var errs []error
+	requeue := false
+	for i := range works.Items {
+		work := &works.Items[i]
+		if work.DeletionTimestamp.IsZero() {
+			if !detached {
+				continue
+			}
+			err := c.workClient.WorkV1().ManifestWorks(clusterName).Delete(ctx, work.Name, metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				errs = append(errs, err)
+				continue
+			}
+			controllerContext.Recorder().Eventf("ManifestWorkForceDeleted",
+				"Deleted manifestwork %s/%s since cluster %s is detached", clusterName, work.Name, clusterName)
+			requeue = true
+			continue
+		}
+
+		if !helper.HasFinalizer(work.Finalizers, workapiv1.ManifestWorkFinalizer) {
+			continue
+		}
+		if work.DeletionTimestamp.Add(c.gracePeriod).After(now) {
+			requeue = true
+			continue
+		}
+		if err := workPatcher.RemoveFinalizer(ctx, work, workapiv1.ManifestWorkFinalizer); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		controllerContext.Recorder().Eventf("ManifestWorkFinalizerForceRemoved",
+			"Removed finalizer of manifestwork %s/%s since cluster %s is stale for more than %v",
+			clusterName, work.Name, clusterName, c.gracePeriod)
+	}
+
+	// recheck the manifestworks of the cluster while it is still stale
+	if requeue || cluster != nil {
+		recheck := staleRecheckInterval
+		if c.gracePeriod < recheck {
+			recheck = c.gracePeriod
+		}
+		controllerContext.Queue().AddAfter(clusterName, recheck)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// staleSince returns the time since the cluster is stale, and whether the cluster is detached from the hub.
+// Nil is returned if the cluster is not stale.
+func (c *ManifestWorkCleanupController) staleSince(cluster *clusterv1.ManagedCluster) (*time.Time, bool) {
+	switch {
+	case cluster == nil:
+		// the cluster is removed, the grace period is counted from the deletion time of each manifestwork.
+		zero := time.Time{}
+		return &zero, true
+	case !cluster.DeletionTimestamp.IsZero():
+		return &cluster.DeletionTimestamp.Time, true
+	}
+
+	available := meta.FindStatusCondition(cluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable)
+	if available == nil || available.Status == metav1.ConditionTrue {
+		return nil, false
+	}
+	return &available.LastTransitionTime.Time, false
+}
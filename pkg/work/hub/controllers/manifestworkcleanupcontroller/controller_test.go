@@ -0,0 +1,143 @@
+package manifestworkcleanupcontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	fakeworkclient "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+)
+
+const clusterName = "cluster1"
+
+func newCluster(available metav1.ConditionStatus, since time.Time) *clusterv1.ManagedCluster {
+	return &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterName},
+		Status: clusterv1.ManagedClusterStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:               clusterv1.ManagedClusterConditionAvailable,
+					Status:             available,
+					LastTransitionTime: metav1.NewTime(since),
+				},
+			},
+		},
+	}
+}
+
+func newWork(name string, deletedAt *time.Time) *workapiv1.ManifestWork {
+	work := &workapiv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  clusterName,
+			Finalizers: []string{workapiv1.ManifestWorkFinalizer},
+		},
+	}
+	if deletedAt != nil {
+		deletionTimestamp := metav1.NewTime(*deletedAt)
+		work.DeletionTimestamp = &deletionTimestamp
+	}
+	return work
+}
+
+func TestSync(t *testing.T) {
+	now := time.Now()
+	gracePeriod := time.Hour
+	longAgo := now.Add(-2 * gracePeriod)
+	recently := now.Add(-time.Minute)
+
+	cases := []struct {
+		name            string
+		cluster         *clusterv1.ManagedCluster
+		works           []runtime.Object
+		expectedActions []string
+		validateActions func(t *testing.T, actions []clienttesting.Action)
+	}{
+		{
+			name:            "cluster is available",
+			cluster:         newCluster(metav1.ConditionTrue, longAgo),
+			works:           []runtime.Object{newWork("work1", &longAgo)},
+			expectedActions: []string{},
+		},
+		{
+			name:            "cluster is unreachable within the grace period",
+			cluster:         newCluster(metav1.ConditionUnknown, recently),
+			works:           []runtime.Object{newWork("work1", &longAgo)},
+			expectedActions: []string{},
+		},
+		{
+			name:    "cluster is unreachable beyond the grace period",
+			cluster: newCluster(metav1.ConditionUnknown, longAgo),
+			works: []runtime.Object{
+				newWork("work1", &longAgo),
+				newWork("work2", &recently),
+				newWork("work3", nil),
+			},
+			expectedActions: []string{"list", "patch"},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				if name := actions[1].(clienttesting.PatchActionImpl).Name; name != "work1" {
+					t.Errorf("expected work1 patched, but got %s", name)
+				}
+			},
+		},
+		{
+			name: "cluster is removed",
+			works: []runtime.Object{
+				newWork("work1", &longAgo),
+				newWork("work2", nil),
+			},
+			expectedActions: []string{"list", "patch", "delete"},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				if name := actions[1].(clienttesting.PatchActionImpl).Name; name != "work1" {
+					t.Errorf("expected work1 patched, but got %s", name)
+				}
+				if name := actions[2].(clienttesting.DeleteActionImpl).Name; name != "work2" {
+					t.Errorf("expected work2 deleted, but got %s", name)
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var clusters []runtime.Object
+			if c.cluster != nil {
+				clusters = append(clusters, c.cluster)
+			}
+			clusterClient := clusterfake.NewSimpleClientset(clusters...)
+			clusterInformerFactory := clusterinformers.NewSharedInformerFactory(clusterClient, 10*time.Minute)
+			for _, cluster := range clusters {
+				if err := clusterInformerFactory.Cluster().V1().ManagedClusters().Informer().GetStore().Add(cluster); err != nil {
+					t.Fatal(err)
+				}
+			}
+			workClient := fakeworkclient.NewSimpleClientset(c.works...)
+
+			controller := &ManifestWorkCleanupController{
+				workClient:    workClient,
+				clusterLister: clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+				gracePeriod:   gracePeriod,
+				now:           func() time.Time { return now },
+			}
+			if err := controller.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, clusterName)); err != nil {
+				t.Errorf("unexpected err: %v", err)
+			}
+
+			actions := workClient.Actions()
+			testingcommon.AssertActions(t, actions, c.expectedActions...)
+			if c.validateActions != nil {
+				c.validateActions(t, actions)
+			}
+		})
+	}
+}
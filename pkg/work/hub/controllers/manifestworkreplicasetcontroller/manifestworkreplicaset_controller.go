@@ -27,6 +27,7 @@ import (
 	workapiv1 "open-cluster-management.io/api/work/v1"
 	workapiv1alpha1 "open-cluster-management.io/api/work/v1alpha1"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 )
@@ -100,7 +101,8 @@ func NewManifestWorkReplicaSetController(
 			manifestWorkInformer.Informer()).
 		WithInformersQueueKeysFunc(controller.placementDecisionQueueKeysFunc, placeDecisionInformer.Informer()).
 		WithInformersQueueKeysFunc(controller.placementQueueKeysFunc, placementInformer.Informer()).
-		WithSync(controller.sync).ToController("ManifestWorkReplicaSetController", recorder)
+		WithSync(logging.WithControllerLogger("ManifestWorkReplicaSetController", controller.sync)).
+		ToController("ManifestWorkReplicaSetController", recorder)
 }
 
 func newController(workClient workclientset.Interface,
@@ -127,7 +129,8 @@ func newController(workClient workclientset.Interface,
 // sync is the main reconcile loop for placeManifest work. It is triggered every 15sec
 func (m *ManifestWorkReplicaSetController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
 	key := controllerContext.QueueKey()
-	klog.V(4).Infof("Reconciling ManifestWorkReplicaSet %q", key)
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling ManifestWorkReplicaSet", "manifestWorkReplicaSet", key)
 
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
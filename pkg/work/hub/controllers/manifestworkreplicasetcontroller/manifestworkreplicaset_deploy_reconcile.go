@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -18,6 +19,7 @@ import (
 	workapiv1alpha1 "open-cluster-management.io/api/work/v1alpha1"
 
 	"open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/work/helper"
 )
 
 // deployReconciler is to manage ManifestWork based on the placement.
@@ -51,8 +53,10 @@ func (d *deployReconciler) reconcile(ctx context.Context, mwrSet *workapiv1alpha
 
 	var errs []error
 	addedClusters, deletedClusters, existingClusters := sets.New[string](), sets.New[string](), sets.New[string]()
+	existingWorks := map[string]*workv1.ManifestWork{}
 	for _, mw := range manifestWorks {
 		existingClusters.Insert(mw.Namespace)
+		existingWorks[mw.Namespace] = mw
 	}
 
 	for _, placement := range placements {
@@ -67,6 +71,17 @@ func (d *deployReconciler) reconcile(ctx context.Context, mwrSet *workapiv1alpha
 		deletedClusters = deletedClusters.Union(deleted)
 	}
 
+	// The manifestworks are not created or updated on the clusters gated by the rollout controls.
+	gate := getRolloutGate(mwrSet)
+	clusterGroups := map[string]int32{}
+	if gate.requireApproval {
+		clusterGroups, err = getClusterGroupIndexes(d.placeDecisionLister, placements)
+		if err != nil {
+			return mwrSet, reconcileContinue, err
+		}
+	}
+	gatedGroups := sets.New[int32]()
+
 	// Create manifestWork for added clusters
 	for cls := range addedClusters {
 		mw, err := CreateManifestWork(mwrSet, cls)
@@ -75,6 +90,11 @@ func (d *deployReconciler) reconcile(ctx context.Context, mwrSet *workapiv1alpha
 			continue
 		}
 
+		if gated, groupIndex := gate.gated(cls, clusterGroups); gated {
+			gatedGroups.Insert(groupIndex)
+			continue
+		}
+
 		_, err = d.workApplier.Apply(ctx, mw)
 		if err != nil {
 			errs = append(errs, err)
@@ -98,12 +118,23 @@ func (d *deployReconciler) reconcile(ctx context.Context, mwrSet *workapiv1alpha
 			continue
 		}
 
+		if gated, groupIndex := gate.gated(cls, clusterGroups); gated {
+			if !equality.Semantic.DeepEqual(existingWorks[cls].Spec, mw.Spec) {
+				gatedGroups.Insert(groupIndex)
+			}
+			continue
+		}
+
 		_, err = d.workApplier.Apply(ctx, mw)
 		if err != nil {
 			errs = append(errs, err)
 		}
 	}
 
+	if gate.enabled() || apimeta.FindStatusCondition(mwrSet.Status.Conditions, ManifestWorkReplicaSetConditionRolloutGated) != nil {
+		apimeta.SetStatusCondition(&mwrSet.Status.Conditions, gate.condition(gatedGroups))
+	}
+
 	// Set the Summary
 	if mwrSet.Status.Summary == (workapiv1alpha1.ManifestWorkReplicaSetSummary{}) {
 		mwrSet.Status.Summary = workapiv1alpha1.ManifestWorkReplicaSetSummary{}
@@ -161,11 +192,17 @@ func CreateManifestWork(mwrSet *workapiv1alpha1.ManifestWorkReplicaSet, clusterN
 		return nil, fmt.Errorf("invalid cluster namespace")
 	}
 
-	return &workv1.ManifestWork{
+	mw := &workv1.ManifestWork{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      mwrSet.Name,
 			Namespace: clusterNS,
 			Labels:    map[string]string{ManifestWorkReplicaSetControllerNameLabelKey: manifestWorkReplicaSetKey(mwrSet)},
 		},
-		Spec: mwrSet.Spec.ManifestWorkTemplate}, nil
+		Spec: mwrSet.Spec.ManifestWorkTemplate}
+
+	// propagate the priority class, so the agents apply the works of a critical rollout first
+	if priorityClass, ok := mwrSet.Annotations[helper.PriorityClassAnnotationKey]; ok {
+		mw.Annotations = map[string]string{helper.PriorityClassAnnotationKey: priorityClass}
+	}
+	return mw, nil
 }
@@ -15,6 +15,7 @@ import (
 	"open-cluster-management.io/api/utils/work/v1/workapplier"
 	workapiv1alpha1 "open-cluster-management.io/api/work/v1alpha1"
 
+	"open-cluster-management.io/ocm/pkg/work/helper"
 	helpertest "open-cluster-management.io/ocm/pkg/work/hub/test"
 )
 
@@ -200,3 +201,23 @@ func TestDeployReconcileAsPlacementNotExist(t *testing.T) {
 		t.Fatal("Placement condition Reason not match PlacementDecisionEmpty ", placeCondition)
 	}
 }
+
+func TestCreateManifestWorkWithPriorityClass(t *testing.T) {
+	mwrSet := helpertest.CreateTestManifestWorkReplicaSet("mwrSet-test", "default", "place-test")
+	mw, err := CreateManifestWork(mwrSet, "cls1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := mw.Annotations[helper.PriorityClassAnnotationKey]; ok {
+		t.Errorf("expected no priority class, but got %v", mw.Annotations)
+	}
+
+	mwrSet.Annotations = map[string]string{helper.PriorityClassAnnotationKey: helper.PriorityClassCritical}
+	mw, err = CreateManifestWork(mwrSet, "cls1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mw.Annotations[helper.PriorityClassAnnotationKey] != helper.PriorityClassCritical {
+		t.Errorf("expected the Critical priority class, but got %v", mw.Annotations)
+	}
+}
@@ -0,0 +1,121 @@
+package manifestworkreplicasetcontroller
+
+import (
+	"fmt"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	clusterlister "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	workapiv1alpha1 "open-cluster-management.io/api/work/v1alpha1"
+
+	"open-cluster-management.io/ocm/pkg/common/helpers"
+)
+
+const (
+	// RolloutPausedAnnotationKey is the annotation on the ManifestWorkReplicaSet to pause its rollout. When it is
+	// set to "true", the manifestworks are neither created nor updated until the annotation is removed or set to
+	// other values.
+	RolloutPausedAnnotationKey = "work.open-cluster-management.io/rollout-paused"
+
+	// RolloutRequireApprovalAnnotationKey is the annotation on the ManifestWorkReplicaSet to require a manual
+	// approval before the rollout proceeds to the next decision group. When it is set to "true", the manifestworks
+	// are only created or updated on the clusters of the approved decision groups.
+	RolloutRequireApprovalAnnotationKey = "work.open-cluster-management.io/rollout-require-approval"
+
+	// RolloutApprovedGroupAnnotationKey is the annotation on the ManifestWorkReplicaSet to approve the rollout to
+	// the decision groups with index equal to or less than its value. The first decision group is always approved.
+	RolloutApprovedGroupAnnotationKey = "work.open-cluster-management.io/rollout-approved-group-index"
+
+	// ManifestWorkReplicaSetConditionRolloutGated is the condition type of the ManifestWorkReplicaSet which
+	// represents the rollout is paused or waiting for the approval of a decision group.
+	ManifestWorkReplicaSetConditionRolloutGated = "RolloutGated"
+
+	ReasonRolloutPaused      = "RolloutPaused"
+	ReasonWaitingForApproval = "WaitingForApproval"
+	ReasonRolloutNotGated    = "RolloutNotGated"
+)
+
+// rolloutGate is the rollout controls set on a ManifestWorkReplicaSet.
+type rolloutGate struct {
+	paused          bool
+	requireApproval bool
+	approvedGroup   int32
+}
+
+func getRolloutGate(mwrSet *workapiv1alpha1.ManifestWorkReplicaSet) rolloutGate {
+	gate := rolloutGate{
+		paused:          mwrSet.Annotations[RolloutPausedAnnotationKey] == "true",
+		requireApproval: mwrSet.Annotations[RolloutRequireApprovalAnnotationKey] == "true",
+	}
+
+	if value, ok := mwrSet.Annotations[RolloutApprovedGroupAnnotationKey]; ok {
+		index, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || index < 0 {
+			klog.Warningf("Ignore the invalid annotation %s of manifestworkreplicaset %s/%s: %q",
+				RolloutApprovedGroupAnnotationKey, mwrSet.Namespace, mwrSet.Name, value)
+		} else {
+			gate.approvedGroup = int32(index)
+		}
+	}
+	return gate
+}
+
+// enabled returns true if any rollout control is set.
+func (g rolloutGate) enabled() bool {
+	return g.paused || g.requireApproval
+}
+
+// gated returns whether the manifestwork on the cluster could not be created or updated, and the decision
+// group index of the cluster.
+func (g rolloutGate) gated(cluster string, clusterGroups map[string]int32) (bool, int32) {
+	groupIndex := clusterGroups[cluster]
+	if g.paused {
+		return true, groupIndex
+	}
+	return g.requireApproval && groupIndex > g.approvedGroup, groupIndex
+}
+
+// condition returns the RolloutGated condition with the indexes of the decision groups which have pending changes
+// blocked by the gate.
+func (g rolloutGate) condition(gatedGroups sets.Set[int32]) metav1.Condition {
+	switch {
+	case g.paused:
+		return getCondition(ManifestWorkReplicaSetConditionRolloutGated, ReasonRolloutPaused,
+			"The rollout is paused", metav1.ConditionTrue)
+	case gatedGroups.Len() > 0:
+		next := sets.List[int32](gatedGroups)[0]
+		return getCondition(ManifestWorkReplicaSetConditionRolloutGated, ReasonWaitingForApproval,
+			fmt.Sprintf("Decision group %d is waiting for approval, set annotation %s to %d to proceed",
+				next, RolloutApprovedGroupAnnotationKey, next), metav1.ConditionTrue)
+	default:
+		return getCondition(ManifestWorkReplicaSetConditionRolloutGated, ReasonRolloutNotGated,
+			"The rollout is not gated", metav1.ConditionFalse)
+	}
+}
+
+// getClusterGroupIndexes returns the decision group index of each cluster selected by the placements. If a
+// cluster is selected by multiple placements, the smallest group index is used.
+func getClusterGroupIndexes(placeDecisionLister clusterlister.PlacementDecisionLister,
+	placements []*clusterv1beta1.Placement) (map[string]int32, error) {
+	clusterGroups := map[string]int32{}
+	for _, placement := range placements {
+		pdTracker := clusterv1beta1.NewPlacementDecisionClustersTracker(
+			placement, helpers.PlacementDecisionGetter{Client: placeDecisionLister}, nil)
+		if err := pdTracker.Refresh(); err != nil {
+			return nil, err
+		}
+
+		for groupKey, clusters := range pdTracker.ExistingClusterGroupsBesides() {
+			for cluster := range clusters {
+				if index, ok := clusterGroups[cluster]; !ok || groupKey.GroupIndex < index {
+					clusterGroups[cluster] = groupKey.GroupIndex
+				}
+			}
+		}
+	}
+	return clusterGroups, nil
+}
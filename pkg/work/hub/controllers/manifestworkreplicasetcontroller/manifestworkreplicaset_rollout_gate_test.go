@@ -0,0 +1,166 @@
+package manifestworkreplicasetcontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clienttesting "k8s.io/client-go/testing"
+
+	fakeclusterclient "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	fakeworkclient "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	"open-cluster-management.io/api/utils/work/v1/workapplier"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	helpertest "open-cluster-management.io/ocm/pkg/work/hub/test"
+)
+
+func TestDeployReconcileWithRolloutGate(t *testing.T) {
+	cases := []struct {
+		name                string
+		annotations         map[string]string
+		existingClusters    []string
+		existingSpecChanged bool
+		expectedCreated     sets.Set[string]
+		expectedUpdated     sets.Set[string]
+		expectedGatedStatus metav1.ConditionStatus
+		expectedGatedReason string
+	}{
+		{
+			name:            "no rollout gate",
+			annotations:     map[string]string{},
+			expectedCreated: sets.New[string]("cls1", "cls2"),
+		},
+		{
+			name:                "rollout is paused",
+			annotations:         map[string]string{RolloutPausedAnnotationKey: "true"},
+			expectedCreated:     sets.New[string](),
+			expectedGatedStatus: metav1.ConditionTrue,
+			expectedGatedReason: ReasonRolloutPaused,
+		},
+		{
+			name:                "the second group is waiting for approval",
+			annotations:         map[string]string{RolloutRequireApprovalAnnotationKey: "true"},
+			expectedCreated:     sets.New[string]("cls1"),
+			expectedGatedStatus: metav1.ConditionTrue,
+			expectedGatedReason: ReasonWaitingForApproval,
+		},
+		{
+			name: "the second group is approved",
+			annotations: map[string]string{
+				RolloutRequireApprovalAnnotationKey: "true",
+				RolloutApprovedGroupAnnotationKey:   "1",
+			},
+			expectedCreated:     sets.New[string]("cls1", "cls2"),
+			expectedGatedStatus: metav1.ConditionFalse,
+			expectedGatedReason: ReasonRolloutNotGated,
+		},
+		{
+			name:                "the changed manifestworks are not updated in the gated group",
+			annotations:         map[string]string{RolloutRequireApprovalAnnotationKey: "true"},
+			existingClusters:    []string{"cls1", "cls2"},
+			existingSpecChanged: true,
+			expectedCreated:     sets.New[string](),
+			expectedUpdated:     sets.New[string]("cls1"),
+			expectedGatedStatus: metav1.ConditionTrue,
+			expectedGatedReason: ReasonWaitingForApproval,
+		},
+		{
+			name:                "the unchanged manifestworks are not gated",
+			annotations:         map[string]string{RolloutRequireApprovalAnnotationKey: "true"},
+			existingClusters:    []string{"cls1", "cls2"},
+			expectedCreated:     sets.New[string](),
+			expectedGatedStatus: metav1.ConditionFalse,
+			expectedGatedReason: ReasonRolloutNotGated,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mwrSet := helpertest.CreateTestManifestWorkReplicaSet("mwrSet-test", "default", "place-test")
+			mwrSet.Annotations = c.annotations
+
+			var works []runtime.Object
+			for _, cluster := range c.existingClusters {
+				mw, _ := CreateManifestWork(mwrSet, cluster)
+				if c.existingSpecChanged {
+					mw.Spec.DeleteOption = &workapiv1.DeleteOption{PropagationPolicy: workapiv1.DeletePropagationPolicyTypeOrphan}
+				}
+				works = append(works, mw)
+			}
+			fWorkClient := fakeworkclient.NewSimpleClientset(append(works, mwrSet)...)
+			workInformerFactory := workinformers.NewSharedInformerFactoryWithOptions(fWorkClient, 1*time.Minute)
+			for _, mw := range works {
+				if err := workInformerFactory.Work().V1().ManifestWorks().Informer().GetStore().Add(mw); err != nil {
+					t.Fatal(err)
+				}
+			}
+			mwLister := workInformerFactory.Work().V1().ManifestWorks().Lister()
+
+			// cls1 is in the decision group 0 and cls2 is in the decision group 1
+			placement, decision0 := helpertest.CreateTestPlacement("place-test", "default", "cls1")
+			_, decision1 := helpertest.CreateTestPlacement("place-test", "default", "cls2")
+			decision1.Name = "place-test-decision-1"
+			decision1.Labels[clusterv1beta1.DecisionGroupIndexLabel] = "1"
+			fClusterClient := fakeclusterclient.NewSimpleClientset(placement, decision0, decision1)
+			clusterInformerFactory := clusterinformers.NewSharedInformerFactoryWithOptions(fClusterClient, 1*time.Minute)
+			if err := clusterInformerFactory.Cluster().V1beta1().Placements().Informer().GetStore().Add(placement); err != nil {
+				t.Fatal(err)
+			}
+			for _, decision := range []*clusterv1beta1.PlacementDecision{decision0, decision1} {
+				if err := clusterInformerFactory.Cluster().V1beta1().PlacementDecisions().Informer().GetStore().Add(decision); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			pmwDeployController := deployReconciler{
+				workApplier:         workapplier.NewWorkApplierWithTypedClient(fWorkClient, mwLister),
+				manifestWorkLister:  mwLister,
+				placeDecisionLister: clusterInformerFactory.Cluster().V1beta1().PlacementDecisions().Lister(),
+				placementLister:     clusterInformerFactory.Cluster().V1beta1().Placements().Lister(),
+			}
+
+			mwrSet, _, err := pmwDeployController.reconcile(context.TODO(), mwrSet)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			created, updated := sets.New[string](), sets.New[string]()
+			for _, action := range fWorkClient.Actions() {
+				switch action.GetVerb() {
+				case "create":
+					created.Insert(action.(clienttesting.CreateActionImpl).Object.(*workapiv1.ManifestWork).Namespace)
+				case "update", "patch":
+					updated.Insert(action.GetNamespace())
+				}
+			}
+			if !created.Equal(c.expectedCreated) {
+				t.Errorf("expected manifestworks created on %v, but got %v", sets.List(c.expectedCreated), sets.List(created))
+			}
+			if c.expectedUpdated == nil {
+				c.expectedUpdated = sets.New[string]()
+			}
+			if !updated.Equal(c.expectedUpdated) {
+				t.Errorf("expected manifestworks updated on %v, but got %v", sets.List(c.expectedUpdated), sets.List(updated))
+			}
+
+			cond := apimeta.FindStatusCondition(mwrSet.Status.Conditions, ManifestWorkReplicaSetConditionRolloutGated)
+			if len(c.expectedGatedReason) == 0 {
+				if cond != nil {
+					t.Errorf("expected no rollout gated condition, but got %v", cond)
+				}
+				return
+			}
+			if cond == nil || cond.Status != c.expectedGatedStatus || cond.Reason != c.expectedGatedReason {
+				t.Errorf("expected rollout gated condition %s/%s, but got %v", c.expectedGatedStatus, c.expectedGatedReason, cond)
+			}
+		})
+	}
+}
@@ -5,18 +5,39 @@ import (
 	"time"
 
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"github.com/spf13/pflag"
 
 	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
 	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
 	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
 	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
 
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/work/hub/controllers/manifestworkcleanupcontroller"
 	"open-cluster-management.io/ocm/pkg/work/hub/controllers/manifestworkreplicasetcontroller"
 )
 
+// WorkHubManagerOptions holds configuration for the work hub manager
+type WorkHubManagerOptions struct {
+	// ForceCleanupGracePeriod is how long a cluster could be detached or unreachable before its terminating
+	// manifestworks are force cleaned up. The force cleanup is disabled if it is 0.
+	ForceCleanupGracePeriod time.Duration
+}
+
+// NewWorkHubManagerOptions returns a WorkHubManagerOptions
+func NewWorkHubManagerOptions() *WorkHubManagerOptions {
+	return &WorkHubManagerOptions{}
+}
+
+// AddFlags registers flags for the work hub manager
+func (o *WorkHubManagerOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&o.ForceCleanupGracePeriod, "force-cleanup-grace-period", o.ForceCleanupGracePeriod,
+		"The duration a cluster could be detached or unreachable before the finalizers of its terminating "+
+			"manifestworks are removed. The manifestworks are not force cleaned up if it is 0.")
+}
+
 // RunWorkHubManager starts the controllers on hub.
-func RunWorkHubManager(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
+func (o *WorkHubManagerOptions) RunWorkHubManager(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
 	hubWorkClient, err := workclientset.NewForConfig(controllerContext.KubeConfig)
 	if err != nil {
 		return err
@@ -30,32 +51,36 @@ func RunWorkHubManager(ctx context.Context, controllerContext *controllercmd.Con
 	clusterInformerFactory := clusterinformers.NewSharedInformerFactory(hubClusterClient, 30*time.Minute)
 
 	// we need a separated filtered manifestwork informers so we only watch the manifestworks that manifestworkreplicaset cares.
-	// This could reduce a lot of memory consumptions
-	manifestWorkInformerFactory := workinformers.NewSharedInformerFactoryWithOptions(hubWorkClient, 30*time.Minute, workinformers.WithTweakListOptions(
-		func(listOptions *metav1.ListOptions) {
-			selector := &metav1.LabelSelector{
-				MatchExpressions: []metav1.LabelSelectorRequirement{
-					{
-						Key:      manifestworkreplicasetcontroller.ManifestWorkReplicaSetControllerNameLabelKey,
-						Operator: metav1.LabelSelectorOpExists,
-					},
-				},
-			}
-			listOptions.LabelSelector = metav1.FormatLabelSelector(selector)
-		},
-	))
-
-	return RunControllerManagerWithInformers(ctx, controllerContext, hubWorkClient, manifestWorkInformerFactory, clusterInformerFactory)
+	// This could reduce a lot of memory consumptions. The manifestworks are listed in pages to avoid loading all of them
+	// in a single response.
+	manifestWorkInformerFactory := workinformers.NewSharedInformerFactoryWithOptions(hubWorkClient, 30*time.Minute,
+		workinformers.WithTweakListOptions(commonhelpers.PaginatedListOptions(
+			commonhelpers.DefaultListPageSize,
+			commonhelpers.LabelExistsListOptions(manifestworkreplicasetcontroller.ManifestWorkReplicaSetControllerNameLabelKey),
+		)))
+
+	return o.RunControllerManagerWithInformers(ctx, controllerContext, hubWorkClient, manifestWorkInformerFactory, clusterInformerFactory)
 }
 
-func RunControllerManagerWithInformers(
+func (o *WorkHubManagerOptions) RunControllerManagerWithInformers(
 	ctx context.Context,
 	controllerContext *controllercmd.ControllerContext,
 	hubWorkClient workclientset.Interface,
 	manifestWorkInformers workinformers.SharedInformerFactory,
 	clusterInformers clusterinformers.SharedInformerFactory,
 ) error {
-	workInformerFactory := workinformers.NewSharedInformerFactory(hubWorkClient, 30*time.Minute)
+	workInformerFactory := workinformers.NewSharedInformerFactoryWithOptions(hubWorkClient, 30*time.Minute,
+		workinformers.WithTweakListOptions(commonhelpers.PaginatedListOptions(commonhelpers.DefaultListPageSize)))
+
+	if err := commonhelpers.SetInformerTransform(commonhelpers.TrimManagedFieldsAndLastApplied,
+		manifestWorkInformers.Work().V1().ManifestWorks().Informer(),
+		workInformerFactory.Work().V1alpha1().ManifestWorkReplicaSets().Informer(),
+		clusterInformers.Cluster().V1beta1().Placements().Informer(),
+		clusterInformers.Cluster().V1beta1().PlacementDecisions().Informer(),
+	); err != nil {
+		return err
+	}
+
 	manifestWorkReplicaSetController := manifestworkreplicasetcontroller.NewManifestWorkReplicaSetController(
 		controllerContext.EventRecorder,
 		hubWorkClient,
@@ -64,6 +89,20 @@ func RunControllerManagerWithInformers(
 		clusterInformers.Cluster().V1beta1().Placements(),
 		clusterInformers.Cluster().V1beta1().PlacementDecisions(),
 	)
+	if o.ForceCleanupGracePeriod > 0 {
+		if err := commonhelpers.SetInformerTransform(commonhelpers.TrimManagedFieldsAndLastApplied,
+			clusterInformers.Cluster().V1().ManagedClusters().Informer()); err != nil {
+			return err
+		}
+		manifestWorkCleanupController := manifestworkcleanupcontroller.NewManifestWorkCleanupController(
+			controllerContext.EventRecorder,
+			hubWorkClient,
+			clusterInformers.Cluster().V1().ManagedClusters(),
+			o.ForceCleanupGracePeriod,
+		)
+		go manifestWorkCleanupController.Run(ctx, 1)
+	}
+
 	go clusterInformers.Start(ctx.Done())
 	go workInformerFactory.Start(ctx.Done())
 	go manifestWorkInformers.Start(ctx.Done())
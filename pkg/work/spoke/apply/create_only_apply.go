@@ -36,10 +36,13 @@ func (c *CreateOnlyApply) Apply(ctx context.Context,
 		Namespace(required.GetNamespace()).
 		Get(ctx, required.GetName(), metav1.GetOptions{})
 	if apierrors.IsNotFound(err) {
-		required.SetOwnerReferences([]metav1.OwnerReference{owner})
+		// merge the owner so the owner to be removed (with the "-" uid suffix) is not set on the resource.
+		owners := []metav1.OwnerReference{}
+		resourcemerge.MergeOwnerRefs(new(bool), &owners, []metav1.OwnerReference{owner})
+		required.SetOwnerReferences(owners)
 		obj, err = c.client.Resource(gvr).Namespace(required.GetNamespace()).Create(
 			ctx, resourcemerge.WithCleanLabelsAndAnnotations(required).(*unstructured.Unstructured), metav1.CreateOptions{})
-		if err != nil {
+		if err == nil {
 			recorder.Eventf(fmt.Sprintf(
 				"%s Created", required.GetKind()), "Created %s/%s because it was missing", required.GetNamespace(), required.GetName())
 		}
@@ -45,6 +45,21 @@ func TestCreateOnlyApply(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:     "create a non exist object with the owner to be removed",
+			owner:    metav1.OwnerReference{APIVersion: "v1", Name: "test", UID: defaultOwner + "-"},
+			existing: nil,
+			required: spoketesting.NewUnstructured("v1", "Secret", "ns1", "test"),
+			gvr:      schema.GroupVersionResource{Version: "v1", Resource: "secrets"},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "get", "create")
+
+				obj := actions[1].(clienttesting.CreateActionImpl).Object.(*unstructured.Unstructured)
+				if owners := obj.GetOwnerReferences(); len(owners) != 0 {
+					t.Errorf("Expect no owners, but have %v", owners)
+				}
+			},
+		},
 		{
 			name:     "create an already existing object",
 			owner:    metav1.OwnerReference{APIVersion: "v1", Name: "test", UID: defaultOwner},
@@ -0,0 +1,149 @@
+package apply
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ExternalOperationApply and ExternalOperationDelete are the operations passed to the external appliers as the
+	// last argument.
+	ExternalOperationApply  = "apply"
+	ExternalOperationDelete = "delete"
+)
+
+// ExternalApplier applies the manifests of the kinds which are not served by the apiserver of the managed cluster,
+// e.g. the configs of network devices or VMs.
+type ExternalApplier interface {
+	// Apply applies the manifest, and returns the object reported in the status of the work.
+	Apply(ctx context.Context, required *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	// Delete deletes the resource of the manifest once the work is deleted. It might be called more than once for a
+	// manifest until the work is finalized, so it is expected to succeed if the resource is not found.
+	Delete(ctx context.Context, required *unstructured.Unstructured) error
+}
+
+// ExternalApplierConfig is the config of an external applier of a kind. The command is run with the args and the
+// operation, either apply or delete, and the manifest in JSON on the stdin. The stdout of the apply operation is
+// the object reported in the status of the work, which is the manifest itself if the stdout is empty.
+type ExternalApplierConfig struct {
+	Group   string          `json:"group"`
+	Kind    string          `json:"kind"`
+	Command string          `json:"command"`
+	Args    []string        `json:"args,omitempty"`
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+}
+
+// ExternalAppliers are the external appliers of the kinds.
+type ExternalAppliers struct {
+	appliers map[schema.GroupKind]ExternalApplier
+}
+
+// NewExternalAppliers returns the external appliers of the configs, it returns nil if there is no config.
+func NewExternalAppliers(configs []ExternalApplierConfig) (*ExternalAppliers, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+
+	appliers := &ExternalAppliers{appliers: map[schema.GroupKind]ExternalApplier{}}
+	for _, config := range configs {
+		groupKind := schema.GroupKind{Group: config.Group, Kind: config.Kind}
+		if len(config.Kind) == 0 || len(config.Command) == 0 {
+			return nil, fmt.Errorf("the kind and the command of the external applier of %q are required", groupKind.String())
+		}
+		if _, ok := appliers.appliers[groupKind]; ok {
+			return nil, fmt.Errorf("duplicated external appliers of %q", groupKind.String())
+		}
+		appliers.appliers[groupKind] = &execApplier{config: config}
+	}
+	return appliers, nil
+}
+
+// LoadExternalAppliers returns the external appliers in the yaml config file, which is a list of the configs of the
+// external appliers. It returns nil if the file is not set.
+func LoadExternalAppliers(configFile string) (*ExternalAppliers, error) {
+	if len(configFile) == 0 {
+		return nil, nil
+	}
+	data, err := os.ReadFile(configFile) // #nosec G304
+	if err != nil {
+		return nil, err
+	}
+	var configs []ExternalApplierConfig
+	if err := yaml.UnmarshalStrict(data, &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse the external appliers config %s: %w", configFile, err)
+	}
+	return NewExternalAppliers(configs)
+}
+
+// Get returns the external applier of the kind, it returns nil if the kind is applied on the managed cluster.
+func (a *ExternalAppliers) Get(groupKind schema.GroupKind) ExternalApplier {
+	if a == nil {
+		return nil
+	}
+	return a.appliers[groupKind]
+}
+
+// execApplier runs the command of the config to apply or delete a manifest.
+type execApplier struct {
+	config ExternalApplierConfig
+}
+
+func (e *execApplier) Apply(ctx context.Context, required *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	output, err := e.run(ctx, ExternalOperationApply, required)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(output)) == 0 {
+		return required, nil
+	}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(output); err != nil {
+		return nil, fmt.Errorf("the output of the external applier %s is not an object: %w", e.config.Command, err)
+	}
+	return obj, nil
+}
+
+func (e *execApplier) Delete(ctx context.Context, required *unstructured.Unstructured) error {
+	_, err := e.run(ctx, ExternalOperationDelete, required)
+	return err
+}
+
+func (e *execApplier) run(ctx context.Context, operation string, required *unstructured.Unstructured) ([]byte, error) {
+	data, err := required.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	if e.config.Timeout.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.config.Timeout.Duration)
+		defer cancel()
+	}
+
+	args := append(append([]string{}, e.config.Args...), operation)
+	cmd := exec.CommandContext(ctx, e.config.Command, args...) // #nosec G204
+	cmd.Stdin = bytes.NewReader(data)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("the external applier %s failed to %s %s %s: %v: %s", e.config.Command, operation,
+			required.GroupVersionKind().Kind, externalResourceName(required), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+func externalResourceName(required *unstructured.Unstructured) string {
+	if len(required.GetNamespace()) == 0 {
+		return required.GetName()
+	}
+	return fmt.Sprintf("%s/%s", required.GetNamespace(), required.GetName())
+}
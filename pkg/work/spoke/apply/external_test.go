@@ -0,0 +1,171 @@
+package apply
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"open-cluster-management.io/ocm/pkg/work/spoke/spoketesting"
+)
+
+var deviceGroupKind = schema.GroupKind{Group: "network.example.com", Kind: "Device"}
+
+func TestNewExternalAppliers(t *testing.T) {
+	cases := []struct {
+		name        string
+		configs     []ExternalApplierConfig
+		expectedErr string
+	}{
+		{
+			name: "no config",
+		},
+		{
+			name:    "valid configs",
+			configs: []ExternalApplierConfig{{Group: deviceGroupKind.Group, Kind: deviceGroupKind.Kind, Command: "apply"}},
+		},
+		{
+			name:        "command is missing",
+			configs:     []ExternalApplierConfig{{Group: deviceGroupKind.Group, Kind: deviceGroupKind.Kind}},
+			expectedErr: "the kind and the command of the external applier of \"Device.network.example.com\" are required",
+		},
+		{
+			name: "duplicated configs",
+			configs: []ExternalApplierConfig{
+				{Group: deviceGroupKind.Group, Kind: deviceGroupKind.Kind, Command: "apply"},
+				{Group: deviceGroupKind.Group, Kind: deviceGroupKind.Kind, Command: "apply2"},
+			},
+			expectedErr: "duplicated external appliers of \"Device.network.example.com\"",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			appliers, err := NewExternalAppliers(c.configs)
+			switch {
+			case len(c.expectedErr) > 0 && (err == nil || err.Error() != c.expectedErr):
+				t.Fatalf("expected error %q, but got %v", c.expectedErr, err)
+			case len(c.expectedErr) == 0 && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+			if (appliers.Get(deviceGroupKind) != nil) != (len(c.configs) > 0) {
+				t.Errorf("unexpected external applier of %s", deviceGroupKind)
+			}
+			if appliers.Get(schema.GroupKind{Kind: "Secret"}) != nil {
+				t.Errorf("expected no external applier of secrets")
+			}
+		})
+	}
+}
+
+func TestLoadExternalAppliers(t *testing.T) {
+	appliers, err := LoadExternalAppliers("")
+	if err != nil || appliers != nil {
+		t.Fatalf("expected no external appliers, but got %v, %v", appliers, err)
+	}
+
+	configFile := filepath.Join(t.TempDir(), "appliers.yaml")
+	if err := os.WriteFile(configFile, []byte(`
+- group: network.example.com
+  kind: Device
+  command: /bin/device-applier
+  args: ["--endpoint", "10.0.0.1"]
+  timeout: 30s
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	appliers, err = LoadExternalAppliers(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	applier, ok := appliers.Get(deviceGroupKind).(*execApplier)
+	if !ok {
+		t.Fatalf("expected the external applier of %s", deviceGroupKind)
+	}
+	if applier.config.Command != "/bin/device-applier" || len(applier.config.Args) != 2 ||
+		applier.config.Timeout.Duration != 30*time.Second {
+		t.Errorf("unexpected external applier config %v", applier.config)
+	}
+
+	if err := os.WriteFile(configFile, []byte(`- kind: Device
+  cmd: /bin/device-applier
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadExternalAppliers(configFile); err == nil {
+		t.Errorf("expected error of the unknown field")
+	}
+}
+
+func TestExecApplier(t *testing.T) {
+	cases := []struct {
+		name              string
+		script            string
+		timeout           time.Duration
+		expectedErr       string
+		expectedStatus    string
+		expectedDeleteErr bool
+	}{
+		{
+			name:           "apply with the output object",
+			script:         `cat > /dev/null; echo '{"apiVersion":"v1","kind":"Device","metadata":{"name":"test"},"status":"'$0'"}'`,
+			expectedStatus: ExternalOperationApply,
+		},
+		{
+			name:   "apply without output",
+			script: `cat > /dev/null`,
+		},
+		{
+			name:              "applier failed",
+			script:            `echo "device $0 failed" >&2; exit 1`,
+			expectedErr:       "device apply failed",
+			expectedDeleteErr: true,
+		},
+		{
+			name:              "applier timed out",
+			script:            `exec sleep 5`,
+			timeout:           100 * time.Millisecond,
+			expectedErr:       "killed",
+			expectedDeleteErr: true,
+		},
+		{
+			name:        "output is not an object",
+			script:      `echo "applied"`,
+			expectedErr: "is not an object",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			applier := &execApplier{config: ExternalApplierConfig{
+				Kind:    "Device",
+				Command: "sh",
+				Args:    []string{"-c", c.script},
+				Timeout: metav1.Duration{Duration: c.timeout},
+			}}
+			required := spoketesting.NewUnstructured("v1", "Device", "", "test")
+
+			obj, err := applier.Apply(context.TODO(), required)
+			switch {
+			case len(c.expectedErr) > 0 && (err == nil || !strings.Contains(err.Error(), c.expectedErr)):
+				t.Errorf("expected error %q, but got %v", c.expectedErr, err)
+			case len(c.expectedErr) == 0 && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case err == nil && obj.Object["status"] != nil && obj.Object["status"] != c.expectedStatus:
+				t.Errorf("expected status %q, but got %v", c.expectedStatus, obj.Object["status"])
+			}
+
+			if err := applier.Delete(context.TODO(), required); (err != nil) != c.expectedDeleteErr {
+				t.Errorf("expected delete error %v, but got %v", c.expectedDeleteErr, err)
+			}
+		})
+	}
+}
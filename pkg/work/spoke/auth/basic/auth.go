@@ -152,8 +152,8 @@ func (v *SarValidator) CheckEscalation(ctx context.Context, sa *workapiv1.Manife
 		DryRun: []string{"All"},
 	})
 	if apierrors.IsForbidden(err) {
-		klog.Infof("not allowed to apply the resource %s %s, %s %s, error: %s",
-			gvr.Group, gvr.Resource, namespace, name, err.Error())
+		klog.FromContext(ctx).Info("Not allowed to apply the resource",
+			"gvr", gvr, "namespace", namespace, "name", name, "err", err)
 		return &NotAllowedError{
 			Err: fmt.Errorf("not allowed to apply the resource %s %s, %s %s, error: permission escalation",
 				gvr.Group, gvr.Resource, namespace, name),
@@ -19,6 +19,7 @@ import (
 
 	workapiv1 "open-cluster-management.io/api/work/v1"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/work/spoke/auth/store"
 )
 
@@ -135,7 +136,7 @@ func newControllerInner(controller *CacheController,
 			controller.clusterRoleEnqueueFu(rbInformer.Informer().GetIndexer(), crbInformer.Informer().GetIndexer()),
 			crInformer.Informer()).
 		WithBareInformers(rbInformer.Informer(), crbInformer.Informer()).
-		WithSync(controller.sync).
+		WithSync(logging.WithControllerLogger(cacheControllerName, controller.sync)).
 		ResyncEvery(ResyncInterval). // cleanup unnecessary cache every ResyncInterval
 		ToController(cacheControllerName, recorder)
 }
@@ -256,12 +257,13 @@ func getInterestedExecutors(subjects []rbacapiv1.Subject, executorCaches *store.
 // role, rolebinding, clusterrole, clusterrolebinding) for the executor changed
 func (c *CacheController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
 	executorKey := controllerContext.QueueKey()
-	klog.V(4).Infof("Executor cache sync, executorKey: %v", executorKey)
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Executor cache sync", "executorKey", executorKey)
 	if executorKey == "key" {
 		// cleanup unnecessary cache
-		klog.V(4).Infof("There are %v cache items before cleanup", c.executorCaches.Count())
+		logger.V(4).Info("Cache items before cleanup", "count", c.executorCaches.Count())
 		c.cleanupUnnecessaryCache()
-		klog.V(4).Infof("There are %v cache items after cleanup", c.executorCaches.Count())
+		logger.V(4).Info("Cache items after cleanup", "count", c.executorCaches.Count())
 		return nil
 	}
 
@@ -288,8 +290,8 @@ func (c *CacheController) iterateCacheItemsFn(ctx context.Context,
 		},
 			v.Dimension.Namespace, v.Dimension.Name, store.GetOwnedByWork(v.Dimension.ExecuteAction))
 
-		klog.V(4).Infof("Update executor cache for executorKey: %s, dimension: %+v result: %v",
-			executorKey, v.Dimension, err)
+		klog.FromContext(ctx).V(4).Info("Update executor cache",
+			"executorKey", executorKey, "dimension", v.Dimension, "result", err)
 		updateSARCheckResultToCache(c.executorCaches, executorKey, v.Dimension, err)
 		return nil
 	}
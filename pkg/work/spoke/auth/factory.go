@@ -55,7 +55,7 @@ func NewFactory(
 }
 
 func (f *validatorFactory) NewExecutorValidator(ctx context.Context, isCacheValidator bool) ExecutorValidator {
-	klog.Infof("Executor caches enabled: %v", isCacheValidator)
+	klog.FromContext(ctx).Info("Executor caches enabled", "enabled", isCacheValidator)
 	sarValidator := basic.NewSARValidator(f.config, f.kubeClient)
 	if !isCacheValidator {
 		return sarValidator
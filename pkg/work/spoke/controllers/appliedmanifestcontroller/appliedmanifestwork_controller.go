@@ -23,9 +23,11 @@ import (
 	worklister "open-cluster-management.io/api/client/work/listers/work/v1"
 	workapiv1 "open-cluster-management.io/api/work/v1"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/work/helper"
+	"open-cluster-management.io/ocm/pkg/work/spoke/resumecache"
 )
 
 // AppliedManifestWorkController is to sync the applied resources of appliedmanifestwork with related
@@ -36,6 +38,7 @@ type AppliedManifestWorkController struct {
 	appliedManifestWorkLister worklister.AppliedManifestWorkLister
 	spokeDynamicClient        dynamic.Interface
 	hubHash                   string
+	resumeCache               *resumecache.Cache
 	rateLimiter               workqueue.RateLimiter
 }
 
@@ -47,7 +50,8 @@ func NewAppliedManifestWorkController(
 	manifestWorkLister worklister.ManifestWorkNamespaceLister,
 	appliedManifestWorkClient workv1client.AppliedManifestWorkInterface,
 	appliedManifestWorkInformer workinformer.AppliedManifestWorkInformer,
-	hubHash string) factory.Controller {
+	hubHash string,
+	resumeCache *resumecache.Cache) factory.Controller {
 
 	controller := &AppliedManifestWorkController{
 		patcher: patcher.NewPatcher[
@@ -57,6 +61,7 @@ func NewAppliedManifestWorkController(
 		appliedManifestWorkLister: appliedManifestWorkInformer.Lister(),
 		spokeDynamicClient:        spokeDynamicClient,
 		hubHash:                   hubHash,
+		resumeCache:               resumeCache,
 		rateLimiter:               workqueue.NewItemExponentialFailureRateLimiter(5*time.Millisecond, 1000*time.Second),
 	}
 
@@ -66,12 +71,14 @@ func NewAppliedManifestWorkController(
 			helper.AppliedManifestworkQueueKeyFunc(hubHash),
 			helper.AppliedManifestworkHubHashFilter(hubHash),
 			appliedManifestWorkInformer.Informer()).
-		WithSync(controller.sync).ToController("AppliedManifestWorkController", recorder)
+		WithSync(logging.WithControllerLogger("AppliedManifestWorkController", controller.sync)).
+		ToController("AppliedManifestWorkController", recorder)
 }
 
 func (m *AppliedManifestWorkController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
 	manifestWorkName := controllerContext.QueueKey()
-	klog.V(4).Infof("Reconciling ManifestWork %q", manifestWorkName)
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling ManifestWork", "manifestWork", manifestWorkName)
 
 	manifestWork, err := m.manifestWorkLister.Get(manifestWorkName)
 	if errors.IsNotFound(err) {
@@ -109,6 +116,13 @@ func (m *AppliedManifestWorkController) sync(ctx context.Context, controllerCont
 		return nil
 	}
 
+	// The applied resources were synced before the agent restarted, and neither the manifestwork nor the
+	// appliedmanifestwork changed since then, so skip getting all the applied resources again on the first sync.
+	if m.resumeCache.Resumed(manifestWork, appliedManifestWork) {
+		logger.V(4).Info("Resume the applied resources from the cache", "manifestWork", manifestWorkName)
+		return nil
+	}
+
 	return m.syncManifestWork(ctx, controllerContext, manifestWork, appliedManifestWork)
 }
 
@@ -141,9 +155,8 @@ func (m *AppliedManifestWorkController) syncManifestWork(
 			Namespace(resourceStatus.ResourceMeta.Namespace).
 			Get(context.TODO(), resourceStatus.ResourceMeta.Name, metav1.GetOptions{})
 		if errors.IsNotFound(err) {
-			klog.V(2).Infof(
-				"Resource %v with key %s/%s does not exist",
-				gvr, resourceStatus.ResourceMeta.Namespace, resourceStatus.ResourceMeta.Name)
+			klog.FromContext(ctx).V(2).Info("Resource does not exist", "gvr", gvr,
+				"namespace", resourceStatus.ResourceMeta.Namespace, "name", resourceStatus.ResourceMeta.Name)
 			continue
 		}
 
@@ -3,6 +3,8 @@ package appliedmanifestcontroller
 import (
 	"context"
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
@@ -24,6 +26,7 @@ import (
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
 	"open-cluster-management.io/ocm/pkg/work/helper"
+	"open-cluster-management.io/ocm/pkg/work/spoke/resumecache"
 	"open-cluster-management.io/ocm/pkg/work/spoke/spoketesting"
 )
 
@@ -47,6 +50,7 @@ func TestSyncManifestWork(t *testing.T) {
 	cases := []struct {
 		name                               string
 		applied                            bool
+		resumed                            bool
 		existingResources                  []runtime.Object
 		appliedResources                   []workapiv1.AppliedManifestResourceMeta
 		manifests                          []workapiv1.ManifestCondition
@@ -76,6 +80,24 @@ func TestSyncManifestWork(t *testing.T) {
 			validateAppliedManifestWorkActions: testingcommon.AssertNoActions,
 			expectedDeleteActions:              []clienttesting.DeleteActionImpl{},
 		},
+		{
+			name:    "skip getting the resources resumed from the cache",
+			applied: true,
+			resumed: true,
+			existingResources: []runtime.Object{
+				spoketesting.NewUnstructuredSecret("ns1", "n1", false, "ns1-n1", *owner),
+				spoketesting.NewUnstructuredSecret("ns2", "n2", false, "ns2-n2", *owner),
+			},
+			appliedResources: []workapiv1.AppliedManifestResourceMeta{
+				{Version: "v1", ResourceIdentifier: workapiv1.ResourceIdentifier{Resource: "secrets", Namespace: "ns1", Name: "n1"}, UID: "ns1-n1"},
+			},
+			manifests: []workapiv1.ManifestCondition{
+				newManifest("", "v1", "secrets", "ns1", "n1"),
+				newManifest("", "v1", "secrets", "ns2", "n2"),
+			},
+			validateAppliedManifestWorkActions: testingcommon.AssertNoActions,
+			expectedDeleteActions:              []clienttesting.DeleteActionImpl{},
+		},
 		{
 			name:    "delete untracked resources",
 			applied: true,
@@ -234,6 +256,11 @@ func TestSyncManifestWork(t *testing.T) {
 				t.Fatal(err)
 			}
 
+			var resumeCache *resumecache.Cache
+			if c.resumed {
+				resumeCache = newResumeCache(t, testingWork, testingAppliedWork)
+			}
+
 			controller := AppliedManifestWorkController{
 				manifestWorkLister: informerFactory.Work().V1().ManifestWorks().Lister().ManifestWorks("cluster1"),
 				patcher: patcher.NewPatcher[
@@ -242,6 +269,7 @@ func TestSyncManifestWork(t *testing.T) {
 				appliedManifestWorkLister: informerFactory.Work().V1().AppliedManifestWorks().Lister(),
 				spokeDynamicClient:        fakeDynamicClient,
 				hubHash:                   "test",
+				resumeCache:               resumeCache,
 				rateLimiter:               workqueue.NewItemExponentialFailureRateLimiter(0, 1*time.Second),
 			}
 
@@ -261,6 +289,9 @@ func TestSyncManifestWork(t *testing.T) {
 			if !reflect.DeepEqual(c.expectedDeleteActions, deleteActions) {
 				t.Fatal(spew.Sdump(deleteActions))
 			}
+			if c.resumed {
+				testingcommon.AssertNoActions(t, fakeDynamicClient.Actions())
+			}
 
 			queueLen := controllerContext.Queue().Len()
 			if queueLen != c.expectedQueueLen {
@@ -271,6 +302,22 @@ func TestSyncManifestWork(t *testing.T) {
 
 }
 
+func newResumeCache(t *testing.T, work *workapiv1.ManifestWork, appliedWork *workapiv1.AppliedManifestWork) *resumecache.Cache {
+	dir := t.TempDir()
+	data, err := json.Marshal(resumecache.Record{ManifestWork: work, AppliedManifestWork: appliedWork})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, work.Name+".json"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	resumeCache, err := resumecache.NewCache(dir, work.Namespace, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resumeCache
+}
+
 func TestFindUntrackedResources(t *testing.T) {
 	cases := []struct {
 		name                       string
@@ -3,12 +3,18 @@ package finalizercontroller
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
@@ -17,17 +23,30 @@ import (
 	worklister "open-cluster-management.io/api/client/work/listers/work/v1"
 	workapiv1 "open-cluster-management.io/api/work/v1"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/work/helper"
+	"open-cluster-management.io/ocm/pkg/work/spoke/apply"
+)
+
+const (
+	// manifestDeletedConditionType on the manifests of a deleting ManifestWork reports whether the resource is deleted
+	// from the managed cluster, the message of a terminating resource tells the finalizers and owners it waits for.
+	manifestDeletedConditionType = "Deleted"
+	// workDeletingConditionType on a deleting ManifestWork summarizes the resources pending deletion.
+	workDeletingConditionType = "Deleting"
 )
 
 // ManifestWorkFinalizeController handles cleanup of manifestwork resources before deletion is allowed.
+// The deletion progress of the resources is reported in the status of the manifestwork until it is finalized.
 type ManifestWorkFinalizeController struct {
 	patcher                   patcher.Patcher[*workapiv1.ManifestWork, workapiv1.ManifestWorkSpec, workapiv1.ManifestWorkStatus]
 	manifestWorkLister        worklister.ManifestWorkNamespaceLister
+	spokeDynamicClient        dynamic.Interface
 	appliedManifestWorkClient workv1client.AppliedManifestWorkInterface
 	appliedManifestWorkLister worklister.AppliedManifestWorkLister
+	externalAppliers          *apply.ExternalAppliers
 	hubHash                   string
 	rateLimiter               workqueue.RateLimiter
 }
@@ -37,8 +56,10 @@ func NewManifestWorkFinalizeController(
 	manifestWorkClient workv1client.ManifestWorkInterface,
 	manifestWorkInformer workinformer.ManifestWorkInformer,
 	manifestWorkLister worklister.ManifestWorkNamespaceLister,
+	spokeDynamicClient dynamic.Interface,
 	appliedManifestWorkClient workv1client.AppliedManifestWorkInterface,
 	appliedManifestWorkInformer workinformer.AppliedManifestWorkInformer,
+	externalAppliers *apply.ExternalAppliers,
 	hubHash string,
 ) factory.Controller {
 
@@ -47,8 +68,10 @@ func NewManifestWorkFinalizeController(
 			*workapiv1.ManifestWork, workapiv1.ManifestWorkSpec, workapiv1.ManifestWorkStatus](
 			manifestWorkClient),
 		manifestWorkLister:        manifestWorkLister,
+		spokeDynamicClient:        spokeDynamicClient,
 		appliedManifestWorkClient: appliedManifestWorkClient,
 		appliedManifestWorkLister: appliedManifestWorkInformer.Lister(),
+		externalAppliers:          externalAppliers,
 		hubHash:                   hubHash,
 		rateLimiter:               workqueue.NewItemExponentialFailureRateLimiter(5*time.Millisecond, 1000*time.Second),
 	}
@@ -59,13 +82,15 @@ func NewManifestWorkFinalizeController(
 			helper.AppliedManifestworkQueueKeyFunc(hubHash),
 			helper.AppliedManifestworkHubHashFilter(hubHash),
 			appliedManifestWorkInformer.Informer()).
-		WithSync(controller.sync).ToController("ManifestWorkFinalizer", recorder)
+		WithSync(logging.WithControllerLogger("ManifestWorkFinalizer", controller.sync)).
+		ToController("ManifestWorkFinalizer", recorder)
 }
 
 func (m *ManifestWorkFinalizeController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
 	manifestWorkName := controllerContext.QueueKey()
 	appliedManifestWorkName := fmt.Sprintf("%s-%s", m.hubHash, manifestWorkName)
-	klog.V(4).Infof("Reconciling ManifestWork %q", manifestWorkName)
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling ManifestWork", "manifestWork", manifestWorkName)
 
 	manifestWork, err := m.manifestWorkLister.Get(manifestWorkName)
 
@@ -77,6 +102,9 @@ func (m *ManifestWorkFinalizeController) sync(ctx context.Context, controllerCon
 	case err != nil:
 		return err
 	case !manifestWork.DeletionTimestamp.IsZero():
+		if err := m.deleteExternalResources(ctx, manifestWork); err != nil {
+			return err
+		}
 		err := m.deleteAppliedManifestWork(ctx, appliedManifestWorkName)
 		if err != nil {
 			return err
@@ -85,14 +113,18 @@ func (m *ManifestWorkFinalizeController) sync(ctx context.Context, controllerCon
 		return nil
 	}
 
-	_, err = m.appliedManifestWorkLister.Get(appliedManifestWorkName)
+	appliedManifestWork, err := m.appliedManifestWorkLister.Get(appliedManifestWorkName)
 	switch {
 	case errors.IsNotFound(err):
 		// if the instance is not found, then we simply continue below this block to remove the finalizer
 	case err != nil:
 		return err
 	default:
-		// appliedmanifestwork still exists, requeue the manifestwork to check in the next loop.
+		// appliedmanifestwork still exists, report the deletion progress and requeue the manifestwork to check in
+		// the next loop.
+		if err := m.syncDeletionStatus(ctx, manifestWork, appliedManifestWork); err != nil {
+			return err
+		}
 		controllerContext.Queue().AddAfter(manifestWorkName, m.rateLimiter.When(manifestWorkName))
 		return nil
 
@@ -113,6 +145,31 @@ func (m *ManifestWorkFinalizeController) sync(ctx context.Context, controllerCon
 	return nil
 }
 
+// deleteExternalResources deletes the resources of the manifests applied by the external appliers, unless they are
+// orphaned by the delete option of the work.
+func (m *ManifestWorkFinalizeController) deleteExternalResources(ctx context.Context, manifestWork *workapiv1.ManifestWork) error {
+	if m.externalAppliers == nil || (manifestWork.Spec.DeleteOption != nil &&
+		manifestWork.Spec.DeleteOption.PropagationPolicy == workapiv1.DeletePropagationPolicyTypeOrphan) {
+		return nil
+	}
+
+	var errs []error
+	for _, manifest := range manifestWork.Spec.Workload.Manifests {
+		required := &unstructured.Unstructured{}
+		if err := required.UnmarshalJSON(manifest.Raw); err != nil {
+			continue
+		}
+		externalApplier := m.externalAppliers.Get(required.GroupVersionKind().GroupKind())
+		if externalApplier == nil {
+			continue
+		}
+		if err := externalApplier.Delete(ctx, required); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
 func (m *ManifestWorkFinalizeController) deleteAppliedManifestWork(ctx context.Context, appliedManifestWorkName string) error {
 	appliedManifestWork, err := m.appliedManifestWorkLister.Get(appliedManifestWorkName)
 	switch {
@@ -126,3 +183,104 @@ func (m *ManifestWorkFinalizeController) deleteAppliedManifestWork(ctx context.C
 
 	return m.appliedManifestWorkClient.Delete(ctx, appliedManifestWorkName, metav1.DeleteOptions{})
 }
+
+// syncDeletionStatus reports the deletion progress of the resources in the status of the deleting manifestwork, so
+// the reason a manifestwork is stuck terminating is visible on the hub.
+func (m *ManifestWorkFinalizeController) syncDeletionStatus(ctx context.Context,
+	originalManifestWork *workapiv1.ManifestWork, appliedManifestWork *workapiv1.AppliedManifestWork) error {
+	// the status is not maintained by the agent without the finalizer.
+	if !helper.HasFinalizer(originalManifestWork.Finalizers, workapiv1.ManifestWorkFinalizer) {
+		return nil
+	}
+
+	manifestWork := originalManifestWork.DeepCopy()
+	owner := helper.NewAppliedManifestWorkOwner(appliedManifestWork)
+	pending := 0
+	for index, manifest := range manifestWork.Status.ResourceStatus.Manifests {
+		if len(manifest.ResourceMeta.Resource) == 0 {
+			// the manifest is never applied.
+			continue
+		}
+		condition := m.buildDeletedCondition(ctx, manifest.ResourceMeta, *owner)
+		condition.ObservedGeneration = manifestWork.Generation
+		if condition.Status != metav1.ConditionTrue {
+			pending++
+		}
+		meta.SetStatusCondition(&manifestWork.Status.ResourceStatus.Manifests[index].Conditions, condition)
+	}
+
+	deletingCondition := metav1.Condition{
+		Type:               workDeletingConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             "ResourcesPendingDeletion",
+		ObservedGeneration: manifestWork.Generation,
+		Message: fmt.Sprintf("%d of %d resources are pending deletion",
+			pending, len(manifestWork.Status.ResourceStatus.Manifests)),
+	}
+	if pending == 0 {
+		deletingCondition.Reason = "ResourcesDeleted"
+		deletingCondition.Message = fmt.Sprintf(
+			"the resources are deleted, waiting for the AppliedManifestWork %s to be finalized", appliedManifestWork.Name)
+	}
+	meta.SetStatusCondition(&manifestWork.Status.Conditions, deletingCondition)
+
+	_, err := m.patcher.PatchStatus(ctx, manifestWork, manifestWork.Status, originalManifestWork.Status)
+	return err
+}
+
+// buildDeletedCondition returns the Deleted condition of a resource of the deleting manifestwork.
+func (m *ManifestWorkFinalizeController) buildDeletedCondition(ctx context.Context,
+	resourceMeta workapiv1.ManifestResourceMeta, owner metav1.OwnerReference) metav1.Condition {
+	gvr := schema.GroupVersionResource{Group: resourceMeta.Group, Version: resourceMeta.Version, Resource: resourceMeta.Resource}
+	obj, err := m.spokeDynamicClient.Resource(gvr).Namespace(resourceMeta.Namespace).Get(ctx, resourceMeta.Name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		return metav1.Condition{
+			Type:    manifestDeletedConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "ResourceDeleted",
+			Message: "Resource is deleted",
+		}
+	case err != nil:
+		return metav1.Condition{
+			Type:    manifestDeletedConditionType,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "FetchingResourceFailed",
+			Message: fmt.Sprintf("Failed to get resource: %v", err),
+		}
+	case !helper.IsOwnedBy(owner, obj.GetOwnerReferences()):
+		return metav1.Condition{
+			Type:    manifestDeletedConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "ResourceOrphaned",
+			Message: "Resource is not owned by the work and kept on the managed cluster",
+		}
+	case obj.GetDeletionTimestamp().IsZero():
+		return metav1.Condition{
+			Type:    manifestDeletedConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  "ResourceDeleting",
+			Message: "Resource is being deleted",
+		}
+	}
+
+	message := fmt.Sprintf("Resource is terminating since %s", obj.GetDeletionTimestamp().UTC().Format(time.RFC3339))
+	if finalizers := obj.GetFinalizers(); len(finalizers) > 0 {
+		message += fmt.Sprintf(", waiting for the finalizers %s", strings.Join(finalizers, ", "))
+	}
+	var owners []string
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID != owner.UID {
+			owners = append(owners, fmt.Sprintf("%s %s", ref.Kind, ref.Name))
+		}
+	}
+	if len(owners) > 0 {
+		message += fmt.Sprintf(", owned by %s", strings.Join(owners, ", "))
+	}
+	return metav1.Condition{
+		Type:    manifestDeletedConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "ResourceTerminating",
+		Message: message,
+	}
+}
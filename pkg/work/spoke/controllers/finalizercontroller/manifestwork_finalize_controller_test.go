@@ -4,10 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
 	clienttesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/util/workqueue"
 
@@ -17,6 +24,9 @@ import (
 
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/work/helper"
+	"open-cluster-management.io/ocm/pkg/work/spoke/apply"
+	"open-cluster-management.io/ocm/pkg/work/spoke/spoketesting"
 )
 
 func TestSyncManifestWorkController(t *testing.T) {
@@ -88,7 +98,7 @@ func TestSyncManifestWorkController(t *testing.T) {
 			validateAppliedManifestWorkActions: func(t *testing.T, actions []clienttesting.Action) {
 				testingcommon.AssertActions(t, actions, "delete")
 			},
-			validateManifestWorkActions: testingcommon.AssertNoActions,
+			validateManifestWorkActions: assertDeletingCondition,
 			expectedQueueLen:            1,
 		},
 		{
@@ -109,7 +119,7 @@ func TestSyncManifestWorkController(t *testing.T) {
 				},
 			},
 			validateAppliedManifestWorkActions: testingcommon.AssertNoActions,
-			validateManifestWorkActions:        testingcommon.AssertNoActions,
+			validateManifestWorkActions:        assertDeletingCondition,
 			expectedQueueLen:                   1,
 		},
 		{
@@ -209,3 +219,169 @@ func TestSyncManifestWorkController(t *testing.T) {
 		})
 	}
 }
+
+func assertDeletingCondition(t *testing.T, actions []clienttesting.Action) {
+	testingcommon.AssertActions(t, actions, "patch")
+	work := &workapiv1.ManifestWork{}
+	if err := json.Unmarshal(actions[0].(clienttesting.PatchActionImpl).Patch, work); err != nil {
+		t.Fatal(err)
+	}
+	if !meta.IsStatusConditionTrue(work.Status.Conditions, workDeletingConditionType) {
+		t.Errorf("expected deleting condition, but got %v", work.Status.Conditions)
+	}
+}
+
+func TestSyncDeletionStatus(t *testing.T) {
+	appliedWork := spoketesting.NewAppliedManifestWork("test", 0, types.UID("applied"))
+	owner := helper.NewAppliedManifestWorkOwner(appliedWork)
+	otherOwner := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "holder", UID: types.UID("holder")}
+	terminating := spoketesting.NewUnstructuredSecret("ns1", "terminating", true, "", *owner, otherOwner)
+	terminating.SetFinalizers([]string{"example.com/cleanup"})
+
+	resourceMeta := func(name string) workapiv1.ManifestCondition {
+		return workapiv1.ManifestCondition{ResourceMeta: workapiv1.ManifestResourceMeta{
+			Version: "v1", Resource: "secrets", Kind: "Secret", Namespace: "ns1", Name: name,
+		}}
+	}
+
+	cases := []struct {
+		name               string
+		finalizers         []string
+		existingResources  []runtime.Object
+		manifests          []workapiv1.ManifestCondition
+		expectedReasons    []string
+		expectedMessage    string
+		expectedWorkReason string
+	}{
+		{
+			name:       "no finalizer",
+			manifests:  []workapiv1.ManifestCondition{resourceMeta("deleted")},
+			finalizers: []string{},
+		},
+		{
+			name:               "resources are deleted",
+			finalizers:         []string{workapiv1.ManifestWorkFinalizer},
+			manifests:          []workapiv1.ManifestCondition{resourceMeta("deleted"), {}},
+			expectedReasons:    []string{"ResourceDeleted", ""},
+			expectedWorkReason: "ResourcesDeleted",
+		},
+		{
+			name:       "resources are pending deletion",
+			finalizers: []string{workapiv1.ManifestWorkFinalizer},
+			existingResources: []runtime.Object{
+				spoketesting.NewUnstructuredSecret("ns1", "orphaned", false, ""),
+				spoketesting.NewUnstructuredSecret("ns1", "deleting", false, "", *owner),
+				terminating,
+			},
+			manifests: []workapiv1.ManifestCondition{
+				resourceMeta("deleted"), resourceMeta("orphaned"), resourceMeta("deleting"), resourceMeta("terminating"),
+			},
+			expectedReasons:    []string{"ResourceDeleted", "ResourceOrphaned", "ResourceDeleting", "ResourceTerminating"},
+			expectedMessage:    "waiting for the finalizers example.com/cleanup, owned by ConfigMap holder",
+			expectedWorkReason: "ResourcesPendingDeletion",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			now := metav1.Now()
+			work, _ := spoketesting.NewManifestWork(0)
+			work.Finalizers = c.finalizers
+			work.DeletionTimestamp = &now
+			work.Status.ResourceStatus.Manifests = c.manifests
+
+			fakeClient := fakeworkclient.NewSimpleClientset(work)
+			controller := &ManifestWorkFinalizeController{
+				patcher: patcher.NewPatcher[
+					*workapiv1.ManifestWork, workapiv1.ManifestWorkSpec, workapiv1.ManifestWorkStatus](
+					fakeClient.WorkV1().ManifestWorks("cluster1")),
+				spokeDynamicClient: fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), c.existingResources...),
+			}
+			if err := controller.syncDeletionStatus(context.TODO(), work, appliedWork); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(c.expectedWorkReason) == 0 {
+				testingcommon.AssertNoActions(t, fakeClient.Actions())
+				return
+			}
+			testingcommon.AssertActions(t, fakeClient.Actions(), "patch")
+			updated := &workapiv1.ManifestWork{}
+			if err := json.Unmarshal(fakeClient.Actions()[0].(clienttesting.PatchActionImpl).Patch, updated); err != nil {
+				t.Fatal(err)
+			}
+
+			cond := meta.FindStatusCondition(updated.Status.Conditions, workDeletingConditionType)
+			if cond == nil || cond.Reason != c.expectedWorkReason {
+				t.Errorf("expected deleting condition with reason %q, but got %v", c.expectedWorkReason, cond)
+			}
+			for i, reason := range c.expectedReasons {
+				cond := meta.FindStatusCondition(updated.Status.ResourceStatus.Manifests[i].Conditions, manifestDeletedConditionType)
+				switch {
+				case len(reason) == 0 && cond != nil:
+					t.Errorf("expected no deleted condition of manifest %d, but got %v", i, cond)
+				case len(reason) > 0 && (cond == nil || cond.Reason != reason):
+					t.Errorf("expected deleted condition of manifest %d with reason %q, but got %v", i, reason, cond)
+				}
+			}
+			if len(c.expectedMessage) > 0 {
+				cond := meta.FindStatusCondition(
+					updated.Status.ResourceStatus.Manifests[len(c.manifests)-1].Conditions, manifestDeletedConditionType)
+				if !strings.Contains(cond.Message, c.expectedMessage) {
+					t.Errorf("expected message containing %q, but got %q", c.expectedMessage, cond.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteExternalResources(t *testing.T) {
+	now := metav1.Now()
+	cases := []struct {
+		name           string
+		deleteOption   *workapiv1.DeleteOption
+		expectedOutput string
+	}{
+		{
+			name:           "delete external resources",
+			expectedOutput: "delete test\n",
+		},
+		{
+			name:         "orphan external resources",
+			deleteOption: &workapiv1.DeleteOption{PropagationPolicy: workapiv1.DeletePropagationPolicyTypeOrphan},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "output")
+			externalAppliers, err := apply.NewExternalAppliers([]apply.ExternalApplierConfig{{
+				Group:   "network.example.com",
+				Kind:    "Device",
+				Command: "sh",
+				Args:    []string{"-c", fmt.Sprintf(`cat > /dev/null; echo "$0 test" >> %s`, output)},
+			}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			work, _ := spoketesting.NewManifestWork(0,
+				spoketesting.NewUnstructured("network.example.com/v1", "Device", "", "test"),
+				spoketesting.NewUnstructured("v1", "Secret", "ns1", "test"))
+			work.DeletionTimestamp = &now
+			work.Spec.DeleteOption = c.deleteOption
+
+			controller := &ManifestWorkFinalizeController{externalAppliers: externalAppliers}
+			if err := controller.deleteExternalResources(context.TODO(), work); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := os.ReadFile(output)
+			if err != nil && !os.IsNotExist(err) {
+				t.Fatal(err)
+			}
+			if string(data) != c.expectedOutput {
+				t.Errorf("expected output %q, but got %q", c.expectedOutput, string(data))
+			}
+		})
+	}
+}
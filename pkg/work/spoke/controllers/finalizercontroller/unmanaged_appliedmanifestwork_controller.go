@@ -12,6 +12,10 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
@@ -20,19 +24,41 @@ import (
 	worklister "open-cluster-management.io/api/client/work/listers/work/v1"
 	workapiv1 "open-cluster-management.io/api/work/v1"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/work/helper"
 )
 
+// StaleAppliedWorkPolicy is the policy of the work agent to handle the appliedmanifestworks applied with a previous
+// hub hash, e.g. after the managed cluster is re-registered to a restored hub.
+type StaleAppliedWorkPolicy string
+
+const (
+	// StaleAppliedWorkPolicyEvict evicts the stale appliedmanifestworks of the work agent after the eviction grace
+	// period, together with their applied resources.
+	StaleAppliedWorkPolicyEvict StaleAppliedWorkPolicy = "Evict"
+
+	// StaleAppliedWorkPolicyAdopt hands the applied resources of the stale appliedmanifestworks over to the
+	// appliedmanifestworks of the current hub if the manifestworks exist on the current hub, and deletes the stale
+	// ones without deleting their resources. Only the appliedmanifestworks of a previous hub hash applied by this
+	// work agent or by its previous identity, e.g. the agent ID defaulted to the hash of the previous hub, are
+	// adopted. The stale appliedmanifestworks of this work agent whose manifestworks are missing on the current hub
+	// are still evicted after the eviction grace period, and the ones of other agents are never evicted.
+	StaleAppliedWorkPolicyAdopt StaleAppliedWorkPolicy = "Adopt"
+)
+
 type unmanagedAppliedWorkController struct {
 	manifestWorkLister        worklister.ManifestWorkNamespaceLister
+	spokeDynamicClient        dynamic.Interface
 	appliedManifestWorkClient workv1client.AppliedManifestWorkInterface
 	patcher                   patcher.Patcher[*workapiv1.AppliedManifestWork, workapiv1.AppliedManifestWorkSpec, workapiv1.AppliedManifestWorkStatus]
 	appliedManifestWorkLister worklister.AppliedManifestWorkLister
 	hubHash                   string
 	agentID                   string
+	previousAgentID           string
 	evictionGracePeriod       time.Duration
+	stalePolicy               StaleAppliedWorkPolicy
 	rateLimiter               workqueue.RateLimiter
 }
 
@@ -45,17 +71,24 @@ type unmanagedAppliedWorkController struct {
 // One unmanaged appliedmanifestwork will be evicted from the managed cluster after a grace period (by
 // default, 10 minutes), after one appliedmanifestwork is evicted from the managed cluster, its owned
 // resources will also be evicted from the managed cluster with Kubernetes garbage collection.
+//
+// With the Adopt stale policy, the appliedmanifestworks of a previous hub hash applied by this work agent or by the
+// agent with the previousAgentID are adopted by the current appliedmanifestworks instead if their manifestworks exist
+// on the current hub, see StaleAppliedWorkPolicyAdopt.
 func NewUnManagedAppliedWorkController(
 	recorder events.Recorder,
+	spokeDynamicClient dynamic.Interface,
 	manifestWorkInformer workinformer.ManifestWorkInformer,
 	manifestWorkLister worklister.ManifestWorkNamespaceLister,
 	appliedManifestWorkClient workv1client.AppliedManifestWorkInterface,
 	appliedManifestWorkInformer workinformer.AppliedManifestWorkInformer,
 	evictionGracePeriod time.Duration,
-	hubHash, agentID string,
+	stalePolicy StaleAppliedWorkPolicy,
+	hubHash, agentID, previousAgentID string,
 ) factory.Controller {
 	controller := &unmanagedAppliedWorkController{
 		manifestWorkLister:        manifestWorkLister,
+		spokeDynamicClient:        spokeDynamicClient,
 		appliedManifestWorkClient: appliedManifestWorkClient,
 		patcher: patcher.NewPatcher[
 			*workapiv1.AppliedManifestWork, workapiv1.AppliedManifestWorkSpec, workapiv1.AppliedManifestWorkStatus](
@@ -63,7 +96,9 @@ func NewUnManagedAppliedWorkController(
 		appliedManifestWorkLister: appliedManifestWorkInformer.Lister(),
 		hubHash:                   hubHash,
 		agentID:                   agentID,
+		previousAgentID:           previousAgentID,
 		evictionGracePeriod:       evictionGracePeriod,
+		stalePolicy:               stalePolicy,
 		rateLimiter:               workqueue.NewItemExponentialFailureRateLimiter(1*time.Minute, evictionGracePeriod),
 	}
 
@@ -74,13 +109,38 @@ func NewUnManagedAppliedWorkController(
 		}, manifestWorkInformer.Informer()).
 		WithFilteredEventsInformersQueueKeysFunc(
 			queue.QueueKeyByMetaName,
-			helper.AppliedManifestworkAgentIDFilter(agentID), appliedManifestWorkInformer.Informer()).
-		WithSync(controller.sync).ToController("UnManagedAppliedManifestWork", recorder)
+			controller.appliedManifestWorkFilter(), appliedManifestWorkInformer.Informer()).
+		WithSync(logging.WithControllerLogger("UnManagedAppliedManifestWork", controller.sync)).
+		ToController("UnManagedAppliedManifestWork", recorder)
+}
+
+// appliedManifestWorkFilter filters the appliedmanifestworks of the work agent, and also the adoptable ones of its
+// previous identity.
+func (m *unmanagedAppliedWorkController) appliedManifestWorkFilter() factory.EventFilterFunc {
+	agentIDFilter := helper.AppliedManifestworkAgentIDFilter(m.agentID)
+	return func(obj interface{}) bool {
+		if agentIDFilter(obj) {
+			return true
+		}
+		appliedWork, ok := obj.(*workapiv1.AppliedManifestWork)
+		return ok && m.adoptable(appliedWork)
+	}
+}
+
+// adoptable returns true if the appliedmanifestwork is of a previous hub hash, and it is applied by this work agent
+// or by the previous identity of the work agent, with the Adopt stale policy.
+func (m *unmanagedAppliedWorkController) adoptable(appliedWork *workapiv1.AppliedManifestWork) bool {
+	if m.stalePolicy != StaleAppliedWorkPolicyAdopt || strings.HasPrefix(appliedWork.Name, m.hubHash) {
+		return false
+	}
+	return appliedWork.Spec.AgentID == m.agentID ||
+		(len(m.previousAgentID) > 0 && appliedWork.Spec.AgentID == m.previousAgentID)
 }
 
 func (m *unmanagedAppliedWorkController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
 	appliedManifestWorkName := controllerContext.QueueKey()
-	klog.V(4).Infof("Reconciling AppliedManifestWork %q", appliedManifestWorkName)
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling AppliedManifestWork", "appliedManifestWork", appliedManifestWorkName)
 
 	appliedManifestWork, err := m.appliedManifestWorkLister.Get(appliedManifestWorkName)
 	if errors.IsNotFound(err) {
@@ -91,8 +151,17 @@ func (m *unmanagedAppliedWorkController) sync(ctx context.Context, controllerCon
 		return err
 	}
 
+	// the appliedmanifestworks of other agents are never evicted, they are only adopted if they are applied by the
+	// previous identity of this work agent.
+	if appliedManifestWork.Spec.AgentID != m.agentID && !m.adoptable(appliedManifestWork) {
+		return nil
+	}
+
 	_, err = m.manifestWorkLister.Get(appliedManifestWork.Spec.ManifestWorkName)
 	if errors.IsNotFound(err) {
+		if appliedManifestWork.Spec.AgentID != m.agentID {
+			return nil
+		}
 		// evict the current appliedmanifestwork when its relating manifestwork is missing on the hub
 		return m.evictAppliedManifestWork(ctx, controllerContext, appliedManifestWork)
 	}
@@ -102,6 +171,9 @@ func (m *unmanagedAppliedWorkController) sync(ctx context.Context, controllerCon
 
 	// manifestwork exists but hub changed
 	if !strings.HasPrefix(appliedManifestWork.Name, m.hubHash) {
+		if m.adoptable(appliedManifestWork) {
+			return m.adoptAppliedManifestWork(ctx, controllerContext, appliedManifestWork)
+		}
 		return m.evictAppliedManifestWork(ctx, controllerContext, appliedManifestWork)
 	}
 
@@ -123,10 +195,103 @@ func (m *unmanagedAppliedWorkController) evictAppliedManifestWork(ctx context.Co
 		return nil
 	}
 
-	klog.V(2).Infof("Delete appliedWork %s by agent %s after eviction grace periodby", appliedManifestWork.Name, m.agentID)
+	klog.FromContext(ctx).V(2).Info("Delete appliedWork after eviction grace period",
+		"appliedManifestWork", appliedManifestWork.Name, "agentID", m.agentID)
 	return m.appliedManifestWorkClient.Delete(ctx, appliedManifestWork.Name, metav1.DeleteOptions{})
 }
 
+// adoptAppliedManifestWork moves the owner references of the applied resources from the stale appliedmanifestwork to
+// the appliedmanifestwork of the current hub and merges them into its applied resources, so the resources no longer
+// in the manifestwork are deleted by the appliedmanifestwork controller later. The stale appliedmanifestwork is
+// deleted at last without any resource owned by it.
+func (m *unmanagedAppliedWorkController) adoptAppliedManifestWork(ctx context.Context,
+	controllerContext factory.SyncContext, staleAppliedWork *workapiv1.AppliedManifestWork) error {
+	currentAppliedWorkName := fmt.Sprintf("%s-%s", m.hubHash, staleAppliedWork.Spec.ManifestWorkName)
+	currentAppliedWork, err := m.appliedManifestWorkLister.Get(currentAppliedWorkName)
+	switch {
+	case errors.IsNotFound(err):
+		// the manifestwork is not applied by the work agent yet, wait until the appliedmanifestwork is created
+		controllerContext.Queue().AddAfter(staleAppliedWork.Name, m.rateLimiter.When(staleAppliedWork.Name))
+		return nil
+	case err != nil:
+		return err
+	case !currentAppliedWork.DeletionTimestamp.IsZero():
+		controllerContext.Queue().AddAfter(staleAppliedWork.Name, m.rateLimiter.When(staleAppliedWork.Name))
+		return nil
+	}
+
+	staleOwner := helper.NewAppliedManifestWorkOwner(staleAppliedWork)
+	currentOwner := helper.NewAppliedManifestWorkOwner(currentAppliedWork)
+	// set the stale owner to be removed
+	removedOwner := staleOwner.DeepCopy()
+	removedOwner.UID = types.UID(fmt.Sprintf("%s-", staleOwner.UID))
+
+	var adoptedResources []workapiv1.AppliedManifestResourceMeta
+	var errs []error
+	for _, resource := range staleAppliedWork.Status.AppliedResources {
+		gvr := schema.GroupVersionResource{Group: resource.Group, Version: resource.Version, Resource: resource.Resource}
+		u, err := m.spokeDynamicClient.Resource(gvr).Namespace(resource.Namespace).Get(ctx, resource.Name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf(
+				"failed to get resource %v with key %s/%s: %w", gvr, resource.Namespace, resource.Name, err))
+			continue
+		}
+
+		// the resource may be adopted already in a previous reconcile
+		existingOwners := u.GetOwnerReferences()
+		if !helper.IsOwnedBy(*staleOwner, existingOwners) && !helper.IsOwnedBy(*currentOwner, existingOwners) {
+			continue
+		}
+		if err := helper.ApplyOwnerReferences(ctx, m.spokeDynamicClient, gvr, u, *currentOwner, *removedOwner); err != nil {
+			errs = append(errs, fmt.Errorf(
+				"failed to adopt resource %v with key %s/%s: %w", gvr, resource.Namespace, resource.Name, err))
+			continue
+		}
+		adoptedResources = append(adoptedResources, resource)
+	}
+	if len(errs) != 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+
+	newAppliedWork := currentAppliedWork.DeepCopy()
+	for _, resource := range adoptedResources {
+		if !hasAppliedResource(newAppliedWork.Status.AppliedResources, resource) {
+			newAppliedWork.Status.AppliedResources = append(newAppliedWork.Status.AppliedResources, resource)
+		}
+	}
+	if _, err := m.patcher.PatchStatus(ctx, newAppliedWork, newAppliedWork.Status, currentAppliedWork.Status); err != nil {
+		return err
+	}
+
+	// the stale appliedmanifestwork owns no resource now, so it is safe to remove its finalizer even if it is not
+	// applied by this work agent.
+	if err := m.patcher.RemoveFinalizer(ctx, staleAppliedWork, workapiv1.AppliedManifestWorkFinalizer); err != nil {
+		return err
+	}
+	if err := m.appliedManifestWorkClient.Delete(ctx, staleAppliedWork.Name, metav1.DeleteOptions{}); err != nil &&
+		!errors.IsNotFound(err) {
+		return err
+	}
+
+	m.rateLimiter.Forget(staleAppliedWork.Name)
+	controllerContext.Recorder().Eventf("AppliedManifestWorkAdopted",
+		"Adopted %d resources of the stale appliedmanifestwork %s by %s",
+		len(adoptedResources), staleAppliedWork.Name, currentAppliedWork.Name)
+	return nil
+}
+
+func hasAppliedResource(resources []workapiv1.AppliedManifestResourceMeta, resource workapiv1.AppliedManifestResourceMeta) bool {
+	for _, r := range resources {
+		if r.ResourceIdentifier == resource.ResourceIdentifier && r.UID == resource.UID {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *unmanagedAppliedWorkController) stopToEvictAppliedManifestWork(
 	ctx context.Context, appliedManifestWork *workapiv1.AppliedManifestWork) error {
 	if appliedManifestWork.Status.EvictionStartTime == nil {
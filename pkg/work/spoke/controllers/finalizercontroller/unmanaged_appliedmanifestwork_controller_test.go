@@ -2,11 +2,14 @@ package finalizercontroller
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
 	clienttesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/util/workqueue"
 
@@ -16,19 +19,58 @@ import (
 
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/work/helper"
+	"open-cluster-management.io/ocm/pkg/work/spoke/spoketesting"
 )
 
 func TestSyncUnamanagedAppliedWork(t *testing.T) {
+	staleAppliedWork := &workapiv1.AppliedManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "hubhash-test",
+			UID:        "stale-uid",
+			Finalizers: []string{workapiv1.AppliedManifestWorkFinalizer},
+		},
+		Spec: workapiv1.AppliedManifestWorkSpec{
+			ManifestWorkName: "test",
+			HubHash:          "hubhash",
+			AgentID:          "old-agent",
+		},
+		Status: workapiv1.AppliedManifestWorkStatus{
+			AppliedResources: []workapiv1.AppliedManifestResourceMeta{
+				{Version: "v1", ResourceIdentifier: workapiv1.ResourceIdentifier{
+					Resource: "secrets", Namespace: "ns1", Name: "n1"}, UID: "ns1-n1"},
+				{Version: "v1", ResourceIdentifier: workapiv1.ResourceIdentifier{
+					Resource: "secrets", Namespace: "ns2", Name: "n2"}, UID: "ns2-n2"},
+			},
+		},
+	}
+	currentAppliedWork := &workapiv1.AppliedManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "hubhash-new-test",
+			UID:  "current-uid",
+		},
+		Spec: workapiv1.AppliedManifestWorkSpec{
+			ManifestWorkName: "test",
+			HubHash:          "hubhash-new",
+			AgentID:          "test-agent",
+		},
+	}
+	staleOwner := helper.NewAppliedManifestWorkOwner(staleAppliedWork)
+
 	cases := []struct {
 		name                               string
 		appliedManifestWorkName            string
 		hubHash                            string
 		agentID                            string
+		previousAgentID                    string
 		evictionGracePeriod                time.Duration
+		stalePolicy                        StaleAppliedWorkPolicy
 		works                              []runtime.Object
 		appliedWorks                       []runtime.Object
+		existingResources                  []runtime.Object
 		expectedQueueLen                   int
 		validateAppliedManifestWorkActions func(t *testing.T, actions []clienttesting.Action)
+		validateDynamicActions             func(t *testing.T, actions []clienttesting.Action)
 	}{
 		{
 			name:                               "appliedmanifestwork is not found",
@@ -186,6 +228,92 @@ func TestSyncUnamanagedAppliedWork(t *testing.T) {
 			expectedQueueLen:                   1,
 			validateAppliedManifestWorkActions: testingcommon.AssertNoActions,
 		},
+		{
+			name:                    "adopt appliedmanifestwork of the previous hub hash",
+			appliedManifestWorkName: "hubhash-test",
+			hubHash:                 "hubhash-new",
+			agentID:                 "test-agent",
+			previousAgentID:         "old-agent",
+			stalePolicy:             StaleAppliedWorkPolicyAdopt,
+			works: []runtime.Object{
+				&workapiv1.ManifestWork{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test",
+						Namespace: "test",
+					},
+				},
+			},
+			appliedWorks: []runtime.Object{staleAppliedWork, currentAppliedWork},
+			existingResources: []runtime.Object{
+				spoketesting.NewUnstructuredSecret("ns1", "n1", false, "ns1-n1", *staleOwner),
+			},
+			validateAppliedManifestWorkActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch", "patch", "delete")
+				appliedWork := &workapiv1.AppliedManifestWork{}
+				if err := json.Unmarshal(actions[0].(clienttesting.PatchActionImpl).Patch, appliedWork); err != nil {
+					t.Fatal(err)
+				}
+				if len(appliedWork.Status.AppliedResources) != 1 || appliedWork.Status.AppliedResources[0].Name != "n1" {
+					t.Errorf("expected the secret ns1/n1 adopted, but got %v", appliedWork.Status.AppliedResources)
+				}
+			},
+			validateDynamicActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "get", "patch", "get")
+				patch := string(actions[1].(clienttesting.PatchActionImpl).Patch)
+				if !strings.Contains(patch, "current-uid") || strings.Contains(patch, "stale-uid") {
+					t.Errorf("expected the owner replaced with the current appliedmanifestwork, but got %s", patch)
+				}
+			},
+		},
+		{
+			name:                    "requeue adoption until the appliedmanifestwork of the current hub is created",
+			appliedManifestWorkName: "hubhash-test",
+			hubHash:                 "hubhash-new",
+			agentID:                 "test-agent",
+			previousAgentID:         "old-agent",
+			stalePolicy:             StaleAppliedWorkPolicyAdopt,
+			works: []runtime.Object{
+				&workapiv1.ManifestWork{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test",
+						Namespace: "test",
+					},
+				},
+			},
+			appliedWorks:                       []runtime.Object{staleAppliedWork},
+			expectedQueueLen:                   1,
+			validateAppliedManifestWorkActions: testingcommon.AssertNoActions,
+			validateDynamicActions:             testingcommon.AssertNoActions,
+		},
+		{
+			name:                    "do not adopt appliedmanifestwork of other agents",
+			appliedManifestWorkName: "hubhash-test",
+			hubHash:                 "hubhash-new",
+			agentID:                 "test-agent",
+			previousAgentID:         "another-agent",
+			stalePolicy:             StaleAppliedWorkPolicyAdopt,
+			works: []runtime.Object{
+				&workapiv1.ManifestWork{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test",
+						Namespace: "test",
+					},
+				},
+			},
+			appliedWorks:                       []runtime.Object{staleAppliedWork, currentAppliedWork},
+			validateAppliedManifestWorkActions: testingcommon.AssertNoActions,
+			validateDynamicActions:             testingcommon.AssertNoActions,
+		},
+		{
+			name:                               "do not evict appliedmanifestwork of other agents",
+			appliedManifestWorkName:            "hubhash-test",
+			hubHash:                            "hubhash-new",
+			agentID:                            "test-agent",
+			previousAgentID:                    "old-agent",
+			stalePolicy:                        StaleAppliedWorkPolicyAdopt,
+			appliedWorks:                       []runtime.Object{staleAppliedWork},
+			validateAppliedManifestWorkActions: testingcommon.AssertNoActions,
+		},
 	}
 
 	for _, c := range cases {
@@ -203,8 +331,10 @@ func TestSyncUnamanagedAppliedWork(t *testing.T) {
 				}
 			}
 
+			fakeDynamicClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), c.existingResources...)
 			controller := &unmanagedAppliedWorkController{
 				manifestWorkLister:        informerFactory.Work().V1().ManifestWorks().Lister().ManifestWorks("test"),
+				spokeDynamicClient:        fakeDynamicClient,
 				appliedManifestWorkClient: fakeClient.WorkV1().AppliedManifestWorks(),
 				patcher: patcher.NewPatcher[
 					*workapiv1.AppliedManifestWork, workapiv1.AppliedManifestWorkSpec, workapiv1.AppliedManifestWorkStatus](
@@ -212,7 +342,9 @@ func TestSyncUnamanagedAppliedWork(t *testing.T) {
 				appliedManifestWorkLister: informerFactory.Work().V1().AppliedManifestWorks().Lister(),
 				hubHash:                   c.hubHash,
 				agentID:                   c.agentID,
+				previousAgentID:           c.previousAgentID,
 				evictionGracePeriod:       c.evictionGracePeriod,
+				stalePolicy:               c.stalePolicy,
 				rateLimiter:               workqueue.NewItemExponentialFailureRateLimiter(0, c.evictionGracePeriod),
 			}
 
@@ -223,6 +355,9 @@ func TestSyncUnamanagedAppliedWork(t *testing.T) {
 
 			appliedWorkAction := fakeClient.Actions()
 			c.validateAppliedManifestWorkActions(t, appliedWorkAction)
+			if c.validateDynamicActions != nil {
+				c.validateDynamicActions(t, fakeDynamicClient.Actions())
+			}
 
 			queueLen := controllerContext.Queue().Len()
 			if queueLen != c.expectedQueueLen {
@@ -0,0 +1,166 @@
+package manifestcontroller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+// AdoptionPolicy defines how the work agent handles the resources which already exist on the managed cluster
+// but are not owned by any AppliedManifestWork when applying a manifest.
+type AdoptionPolicy string
+
+const (
+	// AdoptionPolicyAnnotationKey is the annotation on the ManifestWork to set the adoption policy of its manifests.
+	// If the annotation is not set, the existing resources are updated without any check.
+	AdoptionPolicyAnnotationKey = "work.open-cluster-management.io/adoption-policy"
+
+	// AdoptedByAnnotationKey is the annotation set on the adopted resources, its value is the name of the
+	// AppliedManifestWork which adopted the resource.
+	AdoptedByAnnotationKey = "work.open-cluster-management.io/adopted-by"
+
+	// AdoptedManifestsAnnotationKey is the annotation on the AppliedManifestWork recording the resources adopted by
+	// the work, so the adopted-by annotation is kept on them without getting the resources again.
+	AdoptedManifestsAnnotationKey = "work.open-cluster-management.io/adopted-manifests"
+
+	// DefaultAdoptionLabelKey is the default label which marks an existing resource could be adopted with the
+	// AdoptionPolicyLabeled policy.
+	DefaultAdoptionLabelKey = "work.open-cluster-management.io/adoptable"
+
+	// AdoptionPolicyAlways adopts the existing resources.
+	AdoptionPolicyAlways AdoptionPolicy = "Always"
+	// AdoptionPolicyLabeled only adopts the existing resources with the adoption label set to "true".
+	AdoptionPolicyLabeled AdoptionPolicy = "Labeled"
+	// AdoptionPolicyNever never adopts the existing resources, the apply of the manifest fails instead.
+	AdoptionPolicyNever AdoptionPolicy = "Never"
+)
+
+// NotAdoptableError is returned when the resource exists on the managed cluster but could not be adopted by the work.
+type NotAdoptableError struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+	name      string
+	policy    AdoptionPolicy
+}
+
+func (e *NotAdoptableError) Error() string {
+	return fmt.Sprintf("%s %s/%s already exists and is not adoptable with adoption policy %s",
+		e.gvr.Resource, e.namespace, e.name, e.policy)
+}
+
+func getAdoptionPolicy(work *workapiv1.ManifestWork) AdoptionPolicy {
+	switch policy := AdoptionPolicy(work.Annotations[AdoptionPolicyAnnotationKey]); policy {
+	case AdoptionPolicyAlways, AdoptionPolicyLabeled, AdoptionPolicyNever:
+		return policy
+	}
+	return ""
+}
+
+// ownedByAppliedManifestWork checks whether the object is owned by any AppliedManifestWork.
+func ownedByAppliedManifestWork(obj metav1.Object) bool {
+	for _, owner := range obj.GetOwnerReferences() {
+		if owner.APIVersion == workapiv1.GroupVersion.String() && owner.Kind == "AppliedManifestWork" {
+			return true
+		}
+	}
+	return false
+}
+
+// trackedByAppliedManifestWork checks whether the resource is recorded as an applied resource of the appliedmanifestwork.
+func trackedByAppliedManifestWork(
+	appliedManifestWork *workapiv1.AppliedManifestWork,
+	gvr schema.GroupVersionResource,
+	resourceMeta workapiv1.ManifestResourceMeta) bool {
+	for _, applied := range appliedManifestWork.Status.AppliedResources {
+		if applied.Group == gvr.Group && applied.Resource == gvr.Resource &&
+			applied.Namespace == resourceMeta.Namespace && applied.Name == resourceMeta.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// appliedByTheWork checks whether the resource is reported applied in the status of the work. It covers the
+// resources which are neither owned by the appliedmanifestwork because of the orphan delete option, nor tracked by
+// it until its status is updated.
+func appliedByTheWork(manifestWork *workapiv1.ManifestWork, resourceMeta workapiv1.ManifestResourceMeta) bool {
+	for _, manifest := range manifestWork.Status.ResourceStatus.Manifests {
+		applied := manifest.ResourceMeta
+		if applied.Group == resourceMeta.Group && applied.Resource == resourceMeta.Resource &&
+			applied.Namespace == resourceMeta.Namespace && applied.Name == resourceMeta.Name {
+			return meta.IsStatusConditionTrue(manifest.Conditions, workapiv1.ManifestApplied)
+		}
+	}
+	return false
+}
+
+// adopt checks whether the existing resource of the required object should be adopted by the work. The resources
+// already applied by the work are not checked, so the resource is only got when the work applies it for the first
+// time. If the resource exists and is not owned by any AppliedManifestWork, it is adopted based on the adoption
+// policy, and the adopted-by annotation is set on the required object so the adoption is recorded on the resource
+// once it is applied. It returns true if the resource is adopted by the work.
+func (m *ManifestWorkController) adopt(
+	ctx context.Context,
+	applyCtx *workApplyContext,
+	gvr schema.GroupVersionResource,
+	resourceMeta workapiv1.ManifestResourceMeta,
+	required *unstructured.Unstructured,
+	recorder events.Recorder) (bool, error) {
+	owner := applyCtx.owner
+	// keep the adoption record if the resource is adopted by this work before
+	if applyCtx.adopted.Has(appliedManifestHashKey(gvr, resourceMeta.Namespace, resourceMeta.Name)) {
+		setAdoptedBy(required, owner.Name)
+		return true, nil
+	}
+	if trackedByAppliedManifestWork(applyCtx.appliedManifestWork, gvr, resourceMeta) ||
+		appliedByTheWork(applyCtx.manifestWork, resourceMeta) {
+		return false, nil
+	}
+
+	existing, err := m.spokeDynamicClient.Resource(gvr).Namespace(required.GetNamespace()).Get(
+		ctx, required.GetName(), metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	if ownedByAppliedManifestWork(existing) {
+		return false, nil
+	}
+
+	adoptable := false
+	switch applyCtx.adoptionPolicy {
+	case AdoptionPolicyAlways:
+		adoptable = true
+	case AdoptionPolicyLabeled:
+		adoptable = existing.GetLabels()[m.adoptionLabelKey] == "true"
+	}
+	if !adoptable {
+		return false, &NotAdoptableError{
+			gvr: gvr, namespace: required.GetNamespace(), name: required.GetName(), policy: applyCtx.adoptionPolicy}
+	}
+
+	setAdoptedBy(required, owner.Name)
+	recorder.Eventf("ResourceAdopted", "Existing %s %s/%s is adopted by %s",
+		gvr.Resource, required.GetNamespace(), required.GetName(), owner.Name)
+	return true, nil
+}
+
+func setAdoptedBy(required *unstructured.Unstructured, adoptedBy string) {
+	annotations := required.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AdoptedByAnnotationKey] = adoptedBy
+	required.SetAnnotations(annotations)
+}
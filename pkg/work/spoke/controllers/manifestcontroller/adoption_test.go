@@ -0,0 +1,162 @@
+package manifestcontroller
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/work/helper"
+	"open-cluster-management.io/ocm/pkg/work/spoke/spoketesting"
+)
+
+func TestAdopt(t *testing.T) {
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "newobjects"}
+	appliedWork := spoketesting.NewAppliedManifestWork("hub", 0, "appliedwork-uid")
+	owner := *helper.NewAppliedManifestWorkOwner(appliedWork)
+	otherOwner := *helper.NewAppliedManifestWorkOwner(spoketesting.NewAppliedManifestWork("hub", 1, "other-uid"))
+
+	newExisting := func(labels, annotations map[string]string) *unstructured.Unstructured {
+		obj := spoketesting.NewUnstructured("v1", "NewObject", "ns1", "n1")
+		obj.SetLabels(labels)
+		obj.SetAnnotations(annotations)
+		return obj
+	}
+
+	cases := []struct {
+		name              string
+		policy            AdoptionPolicy
+		existing          *unstructured.Unstructured
+		tracked           bool
+		appliedByTheWork  bool
+		recordedAdopted   bool
+		expectedGet       bool
+		expectedErr       bool
+		expectedAdopted   bool
+		expectedAdoptedBy string
+	}{
+		{
+			name:        "resource does not exist",
+			policy:      AdoptionPolicyNever,
+			expectedGet: true,
+		},
+		{
+			name:        "resource owned by another work",
+			policy:      AdoptionPolicyNever,
+			existing:    spoketesting.NewUnstructured("v1", "NewObject", "ns1", "n1", otherOwner),
+			expectedGet: true,
+		},
+		{
+			name:     "resource tracked by the work",
+			policy:   AdoptionPolicyNever,
+			existing: newExisting(nil, nil),
+			tracked:  true,
+		},
+		{
+			name:             "orphaned resource applied by the work",
+			policy:           AdoptionPolicyNever,
+			existing:         newExisting(nil, nil),
+			appliedByTheWork: true,
+		},
+		{
+			name:              "keep the adoption record",
+			policy:            AdoptionPolicyNever,
+			existing:          newExisting(nil, map[string]string{AdoptedByAnnotationKey: owner.Name}),
+			tracked:           true,
+			recordedAdopted:   true,
+			expectedAdopted:   true,
+			expectedAdoptedBy: owner.Name,
+		},
+		{
+			name:        "never adopt",
+			policy:      AdoptionPolicyNever,
+			existing:    newExisting(nil, nil),
+			expectedGet: true,
+			expectedErr: true,
+		},
+		{
+			name:              "always adopt",
+			policy:            AdoptionPolicyAlways,
+			existing:          newExisting(nil, nil),
+			expectedGet:       true,
+			expectedAdopted:   true,
+			expectedAdoptedBy: owner.Name,
+		},
+		{
+			name:        "labeled adopt without label",
+			policy:      AdoptionPolicyLabeled,
+			existing:    newExisting(nil, nil),
+			expectedGet: true,
+			expectedErr: true,
+		},
+		{
+			name:              "labeled adopt with label",
+			policy:            AdoptionPolicyLabeled,
+			existing:          newExisting(map[string]string{DefaultAdoptionLabelKey: "true"}, nil),
+			expectedGet:       true,
+			expectedAdopted:   true,
+			expectedAdoptedBy: owner.Name,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			work, _ := spoketesting.NewManifestWork(0)
+			resourceMeta := workapiv1.ManifestResourceMeta{Version: "v1", Resource: "newobjects", Namespace: "ns1", Name: "n1"}
+			if c.appliedByTheWork {
+				work.Status.ResourceStatus.Manifests = []workapiv1.ManifestCondition{{
+					ResourceMeta: resourceMeta,
+					Conditions:   []metav1.Condition{{Type: workapiv1.ManifestApplied, Status: metav1.ConditionTrue}},
+				}}
+			}
+			controller := newController(t, work, nil, spoketesting.NewFakeRestMapper())
+			if c.existing != nil {
+				controller.withUnstructuredObject(c.existing)
+			} else {
+				controller.withUnstructuredObject()
+			}
+			controller.controller.adoptionLabelKey = DefaultAdoptionLabelKey
+
+			appliedWork := appliedWork.DeepCopy()
+			if c.tracked {
+				appliedWork.Status.AppliedResources = []workapiv1.AppliedManifestResourceMeta{
+					{ResourceIdentifier: workapiv1.ResourceIdentifier{Resource: "newobjects", Namespace: "ns1", Name: "n1"}, Version: "v1"},
+				}
+			}
+			applyCtx := &workApplyContext{
+				manifestWork:        work,
+				appliedManifestWork: appliedWork,
+				owner:               owner,
+				adoptionPolicy:      c.policy,
+				adopted:             sets.New[string](),
+			}
+			if c.recordedAdopted {
+				applyCtx.adopted.Insert(appliedManifestHashKey(gvr, "ns1", "n1"))
+			}
+			required := spoketesting.NewUnstructured("v1", "NewObject", "ns1", "n1")
+
+			adopted, err := controller.controller.adopt(context.TODO(), applyCtx, gvr, resourceMeta, required,
+				testingcommon.NewFakeSyncContext(t, "test").Recorder())
+			if c.expectedErr != (err != nil) {
+				t.Errorf("expected error %v, but got %v", c.expectedErr, err)
+			}
+			if adopted != c.expectedAdopted {
+				t.Errorf("expected adopted %v, but got %v", c.expectedAdopted, adopted)
+			}
+			if c.expectedGet {
+				testingcommon.AssertActions(t, controller.dynamicClient.Actions(), "get")
+			} else {
+				testingcommon.AssertNoActions(t, controller.dynamicClient.Actions())
+			}
+			if actual := required.GetAnnotations()[AdoptedByAnnotationKey]; actual != c.expectedAdoptedBy {
+				t.Errorf("expected adopted by %q, but got %q", c.expectedAdoptedBy, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package manifestcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourcemerge"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+// ManifestLifecycle defines how the work agent maintains a manifest after it is created on the managed cluster.
+type ManifestLifecycle string
+
+const (
+	// ManifestLifecycleAnnotationKey is the annotation set on a manifest in the ManifestWork to define its lifecycle.
+	// If the annotation is not set, the manifest is maintained by its update strategy.
+	ManifestLifecycleAnnotationKey = "work.open-cluster-management.io/lifecycle"
+
+	// AppliedOnceManifestsAnnotationKey is the annotation on the AppliedManifestWork recording the resources of the
+	// ApplyOnce manifests which have been created by the work.
+	AppliedOnceManifestsAnnotationKey = "work.open-cluster-management.io/applied-once-manifests"
+
+	// ManifestLifecycleApplyOnce creates the resource if it has never been created by the work. The resource is
+	// never updated afterwards, and it is not recreated if it is deleted on the managed cluster. It is for the
+	// bootstrap resources which the local operators are expected to take over. The resource is still deleted
+	// when the work is deleted.
+	ManifestLifecycleApplyOnce ManifestLifecycle = "ApplyOnce"
+	// ManifestLifecycleApplyOnceOrphan is the same as ApplyOnce, except the resource is orphaned and left on the
+	// managed cluster when the work is deleted.
+	ManifestLifecycleApplyOnceOrphan ManifestLifecycle = "ApplyOnceOrphan"
+)
+
+func getManifestLifecycle(required *unstructured.Unstructured) ManifestLifecycle {
+	switch lifecycle := ManifestLifecycle(required.GetAnnotations()[ManifestLifecycleAnnotationKey]); lifecycle {
+	case ManifestLifecycleApplyOnce, ManifestLifecycleApplyOnceOrphan:
+		return lifecycle
+	}
+	return ""
+}
+
+// getAppliedOnceManifests reads the created ApplyOnce resources from the appliedmanifestwork. A malformed
+// annotation is treated as empty.
+func getAppliedOnceManifests(appliedManifestWork *workapiv1.AppliedManifestWork) sets.Set[string] {
+	return getRecordedManifests(appliedManifestWork, AppliedOnceManifestsAnnotationKey)
+}
+
+// setAppliedOnceManifests sets the applied once annotation on the appliedmanifestwork meta.
+func setAppliedOnceManifests(objectMeta *metav1.ObjectMeta, keys sets.Set[string]) error {
+	return setRecordedManifests(objectMeta, AppliedOnceManifestsAnnotationKey, keys)
+}
+
+// getRecordedManifests reads the keys of the resources recorded in the annotation of the appliedmanifestwork.
+func getRecordedManifests(appliedManifestWork *workapiv1.AppliedManifestWork, annotationKey string) sets.Set[string] {
+	data, ok := appliedManifestWork.Annotations[annotationKey]
+	if !ok || len(data) == 0 {
+		return sets.New[string]()
+	}
+	var keys []string
+	if err := json.Unmarshal([]byte(data), &keys); err != nil {
+		klog.Warningf("Ignore the malformed annotation %s on appliedmanifestwork %s: %v",
+			annotationKey, appliedManifestWork.Name, err)
+		return sets.New[string]()
+	}
+	return sets.New[string](keys...)
+}
+
+// setRecordedManifests records the keys of the resources in the annotation of the appliedmanifestwork meta, the
+// annotation is removed if there is no key.
+func setRecordedManifests(objectMeta *metav1.ObjectMeta, annotationKey string, keys sets.Set[string]) error {
+	if len(keys) == 0 {
+		delete(objectMeta.Annotations, annotationKey)
+		return nil
+	}
+	data, err := json.Marshal(sets.List[string](keys))
+	if err != nil {
+		return err
+	}
+	if objectMeta.Annotations == nil {
+		objectMeta.Annotations = map[string]string{}
+	}
+	objectMeta.Annotations[annotationKey] = string(data)
+	return nil
+}
+
+// applyOnce creates the resource of an ApplyOnce manifest if it does not exist and it has never been created by the
+// work, otherwise the live resource is returned without any update. The returned bool is true if the resource has
+// been created by the work, a resource which already exists before the work creates it is not regarded as created
+// by the work. A nil object is returned if the resource created by the work has been deleted on the managed cluster.
+func (m *ManifestWorkController) applyOnce(
+	ctx context.Context,
+	applyCtx *workApplyContext,
+	gvr schema.GroupVersionResource,
+	required *unstructured.Unstructured,
+	owner metav1.OwnerReference,
+	recorder events.Recorder) (*unstructured.Unstructured, bool, error) {
+	created := applyCtx.appliedOnce.Has(appliedManifestHashKey(gvr, required.GetNamespace(), required.GetName()))
+	live, err := m.spokeDynamicClient.Resource(gvr).Namespace(required.GetNamespace()).Get(
+		ctx, required.GetName(), metav1.GetOptions{})
+	switch {
+	case err == nil:
+		return live, created, nil
+	case !apierrors.IsNotFound(err):
+		return nil, created, err
+	case created:
+		klog.FromContext(ctx).V(4).Info("Skip recreating the applied once resource",
+			"gvr", gvr, "namespace", required.GetNamespace(), "name", required.GetName())
+		return nil, true, nil
+	}
+
+	// merge the owner so the owner to be removed (with the "-" uid suffix) is not set on the resource.
+	owners := []metav1.OwnerReference{}
+	resourcemerge.MergeOwnerRefs(new(bool), &owners, []metav1.OwnerReference{owner})
+	required.SetOwnerReferences(owners)
+	obj, err := m.spokeDynamicClient.Resource(gvr).Namespace(required.GetNamespace()).Create(
+		ctx, resourcemerge.WithCleanLabelsAndAnnotations(required).(*unstructured.Unstructured), metav1.CreateOptions{})
+	if err != nil {
+		return nil, false, err
+	}
+	recorder.Eventf(fmt.Sprintf(
+		"%s Created", required.GetKind()), "Created %s/%s because it was missing", required.GetNamespace(), required.GetName())
+	return obj, true, nil
+}
@@ -0,0 +1,196 @@
+package manifestcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clienttesting "k8s.io/client-go/testing"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/work/spoke/spoketesting"
+)
+
+func TestSyncApplyOnceManifests(t *testing.T) {
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "newobjects"}
+	key := appliedManifestHashKey(gvr, "ns1", "n1")
+
+	newManifest := func(lifecycle ManifestLifecycle) *unstructured.Unstructured {
+		obj := spoketesting.NewUnstructuredWithContent(
+			"v1", "NewObject", "ns1", "n1",
+			map[string]interface{}{"spec": map[string]interface{}{"key1": "val1"}})
+		obj.SetAnnotations(map[string]string{ManifestLifecycleAnnotationKey: string(lifecycle)})
+		return obj
+	}
+
+	cases := []struct {
+		name                      string
+		lifecycle                 ManifestLifecycle
+		recorded                  bool
+		existing                  *unstructured.Unstructured
+		expectedDynamicAction     []string
+		expectedAppliedWorkAction []string
+		expectedOwners            int
+		expectedRecorded          bool
+		expectedAppliedReason     string
+	}{
+		{
+			name:                      "create the resource the first time",
+			lifecycle:                 ManifestLifecycleApplyOnce,
+			expectedDynamicAction:     []string{"get", "create"},
+			expectedAppliedWorkAction: []string{"patch"},
+			expectedOwners:            1,
+			expectedRecorded:          true,
+			expectedAppliedReason:     "AppliedManifestComplete",
+		},
+		{
+			name:                      "create the resource without owner if it is orphaned",
+			lifecycle:                 ManifestLifecycleApplyOnceOrphan,
+			expectedDynamicAction:     []string{"get", "create"},
+			expectedAppliedWorkAction: []string{"patch"},
+			expectedRecorded:          true,
+		},
+		{
+			name:      "do not update the created resource",
+			lifecycle: ManifestLifecycleApplyOnce,
+			recorded:  true,
+			existing: spoketesting.NewUnstructuredWithContent(
+				"v1", "NewObject", "ns1", "n1",
+				map[string]interface{}{"spec": map[string]interface{}{"key1": "changed"}}),
+			expectedDynamicAction: []string{"get", "patch"},
+			expectedRecorded:      true,
+			expectedAppliedReason: "AppliedManifestComplete",
+		},
+		{
+			name:                  "do not recreate the deleted resource",
+			lifecycle:             ManifestLifecycleApplyOnce,
+			recorded:              true,
+			expectedDynamicAction: []string{"get"},
+			expectedRecorded:      true,
+			expectedAppliedReason: "AppliedOnceResourceDeleted",
+		},
+		{
+			name:      "do not record or own the resource not created by the work",
+			lifecycle: ManifestLifecycleApplyOnce,
+			existing: spoketesting.NewUnstructuredWithContent(
+				"v1", "NewObject", "ns1", "n1",
+				map[string]interface{}{"spec": map[string]interface{}{"key1": "existing"}}),
+			expectedDynamicAction: []string{"get"},
+			expectedAppliedReason: "AppliedManifestComplete",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			work, workKey := spoketesting.NewManifestWork(0, newManifest(c.lifecycle))
+			work.Finalizers = []string{workapiv1.ManifestWorkFinalizer}
+
+			appliedWork := spoketesting.NewAppliedManifestWork("", 0, "appliedwork-uid")
+			if c.recorded {
+				appliedWork.Annotations = map[string]string{AppliedOnceManifestsAnnotationKey: `["` + key + `"]`}
+			}
+
+			var objects []runtime.Object
+			if c.existing != nil {
+				objects = append(objects, c.existing)
+			}
+			controller := newController(t, work, appliedWork, spoketesting.NewFakeRestMapper()).
+				withKubeObject().
+				withUnstructuredObject(objects...)
+			if err := controller.workClient.Tracker().Add(appliedWork); err != nil {
+				t.Fatal(err)
+			}
+
+			syncContext := testingcommon.NewFakeSyncContext(t, workKey)
+			if err := controller.toController().sync(context.TODO(), syncContext); err != nil {
+				t.Errorf("Should be success with no err: %v", err)
+			}
+
+			dynamicActions := controller.dynamicClient.Actions()
+			testingcommon.AssertActions(t, dynamicActions, c.expectedDynamicAction...)
+			for _, action := range dynamicActions {
+				if create, ok := action.(clienttesting.CreateActionImpl); ok {
+					obj := create.Object.(*unstructured.Unstructured)
+					if len(obj.GetOwnerReferences()) != c.expectedOwners {
+						t.Errorf("expected %d owners, but got %v", c.expectedOwners, obj.GetOwnerReferences())
+					}
+				}
+			}
+
+			var appliedWorkActions []clienttesting.Action
+			for _, action := range controller.workClient.Actions() {
+				if action.GetResource().Resource == "appliedmanifestworks" {
+					appliedWorkActions = append(appliedWorkActions, action)
+				}
+			}
+			testingcommon.AssertActions(t, appliedWorkActions, c.expectedAppliedWorkAction...)
+
+			updated, err := controller.workClient.WorkV1().AppliedManifestWorks().Get(
+				context.TODO(), appliedWork.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if recorded := getAppliedOnceManifests(updated).Has(key); recorded != c.expectedRecorded {
+				t.Errorf("expected recorded %v, but got %v", c.expectedRecorded, recorded)
+			}
+
+			if len(c.expectedAppliedReason) == 0 {
+				return
+			}
+			updatedWork, err := controller.workClient.WorkV1().ManifestWorks(work.Namespace).Get(
+				context.TODO(), work.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(updatedWork.Status.ResourceStatus.Manifests) != 1 {
+				t.Fatalf("expected 1 manifest condition, but got %v", updatedWork.Status.ResourceStatus.Manifests)
+			}
+			applied := meta.FindStatusCondition(
+				updatedWork.Status.ResourceStatus.Manifests[0].Conditions, workapiv1.ManifestApplied)
+			if applied == nil || applied.Status != metav1.ConditionTrue || applied.Reason != c.expectedAppliedReason {
+				t.Errorf("expected applied condition with reason %s, but got %v", c.expectedAppliedReason, applied)
+			}
+			if !meta.IsStatusConditionTrue(updatedWork.Status.Conditions, workapiv1.WorkApplied) {
+				t.Errorf("expected work applied, but got %v", updatedWork.Status.Conditions)
+			}
+		})
+	}
+}
+
+func TestAppliedOnceManifests(t *testing.T) {
+	appliedWork := spoketesting.NewAppliedManifestWork("", 0, "appliedwork-uid")
+	if keys := getAppliedOnceManifests(appliedWork); keys.Len() != 0 {
+		t.Errorf("expected no keys, but got %v", keys)
+	}
+
+	if err := setAppliedOnceManifests(&appliedWork.ObjectMeta, sets.New[string]("b", "a")); err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	if err := json.Unmarshal([]byte(appliedWork.Annotations[AppliedOnceManifestsAnnotationKey]), &keys); err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
+		t.Errorf("expected sorted keys, but got %v", keys)
+	}
+
+	if err := setAppliedOnceManifests(&appliedWork.ObjectMeta, sets.New[string]()); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := appliedWork.Annotations[AppliedOnceManifestsAnnotationKey]; ok {
+		t.Errorf("expected the annotation removed")
+	}
+
+	appliedWork.Annotations[AppliedOnceManifestsAnnotationKey] = "invalid"
+	if keys := getAppliedOnceManifests(appliedWork); keys.Len() != 0 {
+		t.Errorf("expected no keys for the malformed annotation, but got %v", keys)
+	}
+}
@@ -0,0 +1,213 @@
+package manifestcontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/work/helper"
+)
+
+// WorkCRDsEstablished is the work condition type which represents whether the CRDs in the work, which other
+// manifests of the work depend on, are established. It is only set when the work has such dependencies.
+const WorkCRDsEstablished = "CRDsEstablished"
+
+var (
+	// CRDEstablishedTimeout is the maximum time to wait for the CRDs in a work to be established before
+	// applying the custom resources of these CRDs in the same work.
+	CRDEstablishedTimeout = 10 * time.Second
+
+	crdEstablishedPollInterval = time.Second
+)
+
+// CRDNotEstablishedError is returned for the manifests which are not applied since the CRDs they depend on are
+// not established.
+type CRDNotEstablishedError struct {
+	crds []string
+}
+
+func (e *CRDNotEstablishedError) Error() string {
+	return fmt.Sprintf("waiting for the custom resource definitions to be established: %s", strings.Join(e.crds, ", "))
+}
+
+// crdDependencies records the CRDs in a work and the manifests of their custom resources.
+type crdDependencies struct {
+	// crdNames is keyed by the index of the CRD manifest
+	crdNames map[int]string
+	// dependents is keyed by the index of the custom resource manifest, and the value is the indexes of its CRDs
+	dependents map[int][]int
+	// established records whether the CRD of the index is established once it is checked in a reconcile
+	established map[int]bool
+}
+
+// getCRDDependencies finds the CRDs in the manifests and the manifests depending on them by the group and kind.
+func getCRDDependencies(manifests []workapiv1.Manifest) *crdDependencies {
+	deps := &crdDependencies{
+		crdNames:    map[int]string{},
+		dependents:  map[int][]int{},
+		established: map[int]bool{},
+	}
+
+	objects := make([]*unstructured.Unstructured, len(manifests))
+	crdKinds := map[schema.GroupKind][]int{}
+	for index, manifest := range manifests {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(manifest.Raw); err != nil {
+			continue
+		}
+		objects[index] = obj
+
+		gvk := obj.GroupVersionKind()
+		if gvk.Group != crdGVR.Group || gvk.Kind != "CustomResourceDefinition" {
+			continue
+		}
+		group, _, _ := unstructured.NestedString(obj.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(obj.Object, "spec", "names", "kind")
+		if len(group) == 0 || len(kind) == 0 {
+			continue
+		}
+		deps.crdNames[index] = obj.GetName()
+		gk := schema.GroupKind{Group: group, Kind: kind}
+		crdKinds[gk] = append(crdKinds[gk], index)
+	}
+	if len(crdKinds) == 0 {
+		return deps
+	}
+
+	for index, obj := range objects {
+		if obj == nil {
+			continue
+		}
+		if crds, ok := crdKinds[obj.GroupVersionKind().GroupKind()]; ok {
+			deps.dependents[index] = crds
+		}
+	}
+	return deps
+}
+
+func crdNotEstablishedResult(index int, manifest workapiv1.Manifest, crds []string) applyResult {
+	result := applyResult{Error: &CRDNotEstablishedError{crds: crds}}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(manifest.Raw); err == nil {
+		result.resourceMeta, _, _ = helper.BuildResourceMeta(index, obj, nil)
+	}
+	return result
+}
+
+// notEstablished returns the names of the CRDs which the manifest depends on and are not established. It waits
+// for the applied CRDs to be established up to CRDEstablishedTimeout the first time they are checked.
+func (m *ManifestWorkController) notEstablished(
+	ctx context.Context, deps *crdDependencies, index int, results []applyResult) []string {
+	var pending []int
+	for _, crdIndex := range deps.dependents[index] {
+		if _, checked := deps.established[crdIndex]; checked {
+			continue
+		}
+		// do not wait for the CRD failing to apply
+		result := results[crdIndex]
+		if result.Error != nil || result.Result == nil {
+			deps.established[crdIndex] = false
+			continue
+		}
+		pending = append(pending, crdIndex)
+	}
+
+	if len(pending) > 0 {
+		err := wait.PollUntilContextTimeout(ctx, crdEstablishedPollInterval, CRDEstablishedTimeout, true,
+			func(ctx context.Context) (bool, error) {
+				for _, crdIndex := range pending {
+					if !deps.established[crdIndex] {
+						deps.established[crdIndex] = m.crdEstablished(ctx, deps.crdNames[crdIndex], results[crdIndex])
+					}
+				}
+				for _, crdIndex := range pending {
+					if !deps.established[crdIndex] {
+						return false, nil
+					}
+				}
+				return true, nil
+			})
+		if err != nil {
+			klog.FromContext(ctx).V(2).Info("Timeout waiting for the custom resource definitions to be established", "err", err)
+		}
+	}
+
+	var crds []string
+	for _, crdIndex := range deps.dependents[index] {
+		if !deps.established[crdIndex] {
+			crds = append(crds, deps.crdNames[crdIndex])
+		}
+	}
+	return crds
+}
+
+// crdEstablished checks whether the applied CRD is established. The applied object is checked first, and the
+// CRD is fetched from the managed cluster if the applied object is not established.
+func (m *ManifestWorkController) crdEstablished(ctx context.Context, name string, result applyResult) bool {
+	if applied, ok := result.Result.(*unstructured.Unstructured); ok && hasEstablishedCondition(applied) {
+		return true
+	}
+
+	obj, err := m.spokeDynamicClient.Resource(crdGVR).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false
+	}
+	return hasEstablishedCondition(obj)
+}
+
+func hasEstablishedCondition(crd *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == "Established" && condition["status"] == string(metav1.ConditionTrue) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildCRDsEstablishedCondition returns the CRDsEstablished condition based on the results of the manifests
+// depending on the CRDs. Nil is returned if there is no such manifest.
+func buildCRDsEstablishedCondition(generation int64, deps *crdDependencies) *metav1.Condition {
+	if len(deps.dependents) == 0 || len(deps.established) == 0 {
+		return nil
+	}
+
+	var notEstablished []string
+	for index, established := range deps.established {
+		if !established {
+			notEstablished = append(notEstablished, deps.crdNames[index])
+		}
+	}
+	sort.Strings(notEstablished)
+	if len(notEstablished) > 0 {
+		return &metav1.Condition{
+			Type:               WorkCRDsEstablished,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             "WaitTimeout",
+			Message: fmt.Sprintf("The custom resource definitions are not established: %s",
+				strings.Join(notEstablished, ", ")),
+		}
+	}
+	return &metav1.Condition{
+		Type:               WorkCRDsEstablished,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             "CRDsEstablished",
+		Message:            "All custom resource definitions are established",
+	}
+}
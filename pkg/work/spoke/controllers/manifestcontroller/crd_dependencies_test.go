@@ -0,0 +1,162 @@
+package manifestcontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/restmapper"
+	clienttesting "k8s.io/client-go/testing"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/work/spoke/spoketesting"
+)
+
+func newCRD(established bool) *unstructured.Unstructured {
+	crd := spoketesting.NewUnstructuredWithContent(
+		"apiextensions.k8s.io/v1", "CustomResourceDefinition", "", "foos.example.com",
+		map[string]interface{}{
+			"spec": map[string]interface{}{
+				"group": "example.com",
+				"names": map[string]interface{}{"kind": "Foo", "plural": "foos"},
+			},
+		})
+	if established {
+		_ = unstructured.SetNestedSlice(crd.Object, []interface{}{
+			map[string]interface{}{"type": "Established", "status": "True"},
+		}, "status", "conditions")
+	}
+	return crd
+}
+
+func newCRDTestRestMapper() meta.RESTMapper {
+	return restmapper.NewDiscoveryRESTMapper([]*restmapper.APIGroupResources{
+		{
+			Group: metav1.APIGroup{
+				Name:             "apiextensions.k8s.io",
+				Versions:         []metav1.GroupVersionForDiscovery{{Version: "v1", GroupVersion: "apiextensions.k8s.io/v1"}},
+				PreferredVersion: metav1.GroupVersionForDiscovery{Version: "v1", GroupVersion: "apiextensions.k8s.io/v1"},
+			},
+			VersionedResources: map[string][]metav1.APIResource{
+				"v1": {{Name: "customresourcedefinitions", Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}},
+			},
+		},
+		{
+			Group: metav1.APIGroup{
+				Name:             "example.com",
+				Versions:         []metav1.GroupVersionForDiscovery{{Version: "v1", GroupVersion: "example.com/v1"}},
+				PreferredVersion: metav1.GroupVersionForDiscovery{Version: "v1", GroupVersion: "example.com/v1"},
+			},
+			VersionedResources: map[string][]metav1.APIResource{
+				"v1": {{Name: "foos", Group: "example.com", Namespaced: true, Kind: "Foo"}},
+			},
+		},
+	})
+}
+
+func TestGetCRDDependencies(t *testing.T) {
+	manifests := []workapiv1.Manifest{
+		{RawExtension: runtime.RawExtension{Object: spoketesting.NewUnstructured("example.com/v1", "Foo", "ns1", "foo1")}},
+		{RawExtension: runtime.RawExtension{Object: newCRD(false)}},
+		{RawExtension: runtime.RawExtension{Object: spoketesting.NewUnstructured("v1", "Secret", "ns1", "secret1")}},
+		{RawExtension: runtime.RawExtension{Object: spoketesting.NewUnstructured("example.com/v2", "Foo", "ns1", "foo2")}},
+	}
+	for i := range manifests {
+		raw, err := manifests[i].Object.(*unstructured.Unstructured).MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		manifests[i].Raw = raw
+	}
+
+	deps := getCRDDependencies(manifests)
+	if len(deps.crdNames) != 1 || deps.crdNames[1] != "foos.example.com" {
+		t.Errorf("unexpected crds %v", deps.crdNames)
+	}
+	if len(deps.dependents) != 2 || len(deps.dependents[0]) != 1 || len(deps.dependents[3]) != 1 {
+		t.Errorf("unexpected dependents %v", deps.dependents)
+	}
+}
+
+func TestSyncWithCRDDependencies(t *testing.T) {
+	defaultTimeout, defaultInterval := CRDEstablishedTimeout, crdEstablishedPollInterval
+	CRDEstablishedTimeout, crdEstablishedPollInterval = 100*time.Millisecond, 10*time.Millisecond
+	defer func() {
+		CRDEstablishedTimeout, crdEstablishedPollInterval = defaultTimeout, defaultInterval
+	}()
+
+	cases := []struct {
+		name                    string
+		established             bool
+		expectedFooApplied      bool
+		expectedConditionStatus metav1.ConditionStatus
+	}{
+		{
+			name:                    "apply the custom resource after the crd is established",
+			established:             true,
+			expectedFooApplied:      true,
+			expectedConditionStatus: metav1.ConditionTrue,
+		},
+		{
+			name:                    "do not apply the custom resource if the crd is not established",
+			expectedConditionStatus: metav1.ConditionFalse,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// the custom resource is listed before its crd
+			work, workKey := spoketesting.NewManifestWork(0,
+				spoketesting.NewUnstructured("example.com/v1", "Foo", "ns1", "foo1"), newCRD(false))
+			work.Finalizers = []string{workapiv1.ManifestWorkFinalizer}
+			ssa := &workapiv1.UpdateStrategy{Type: workapiv1.UpdateStrategyTypeServerSideApply}
+			work.Spec.ManifestConfigs = []workapiv1.ManifestConfigOption{
+				newManifestConfigOption("apiextensions.k8s.io", "customresourcedefinitions", "", "foos.example.com", ssa),
+				newManifestConfigOption("example.com", "foos", "ns1", "foo1", ssa),
+			}
+
+			controller := newController(t, work, nil, newCRDTestRestMapper()).
+				withKubeObject().
+				withUnstructuredObject()
+			controller.dynamicClient.PrependReactor("patch", "customresourcedefinitions",
+				func(action clienttesting.Action) (bool, runtime.Object, error) {
+					return true, newCRD(c.established), nil
+				})
+			controller.dynamicClient.PrependReactor("patch", "foos",
+				func(action clienttesting.Action) (bool, runtime.Object, error) {
+					return true, spoketesting.NewUnstructured("example.com/v1", "Foo", "ns1", "foo1"), nil
+				})
+
+			syncContext := testingcommon.NewFakeSyncContext(t, workKey)
+			err := controller.toController().sync(context.TODO(), syncContext)
+			if c.expectedFooApplied && err != nil {
+				t.Errorf("Should be success with no err: %v", err)
+			}
+			if !c.expectedFooApplied && err == nil {
+				t.Errorf("expected error when the crd is not established")
+			}
+
+			fooApplied := false
+			for _, action := range controller.dynamicClient.Actions() {
+				if action.GetVerb() == "patch" && action.GetResource().Resource == "foos" {
+					fooApplied = true
+				}
+			}
+			if fooApplied != c.expectedFooApplied {
+				t.Errorf("expected custom resource applied %v, but got %v", c.expectedFooApplied, fooApplied)
+			}
+
+			updated, err := controller.workClient.WorkV1().ManifestWorks(work.Namespace).Get(context.TODO(), work.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertCondition(t, updated.Status.Conditions, WorkCRDsEstablished, c.expectedConditionStatus)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package manifestcontroller
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+// AppliedManifestHashesAnnotationKey is the annotation on the AppliedManifestWork recording, for each
+// applied resource, the hash of the manifest last applied successfully together with the generation
+// and resource version of the live object returned by that apply.
+const AppliedManifestHashesAnnotationKey = "work.open-cluster-management.io/applied-manifest-hashes"
+
+// appliedManifestHash is the record of the last successful apply of one manifest.
+type appliedManifestHash struct {
+	Hash            string `json:"hash"`
+	Generation      int64  `json:"generation,omitempty"`
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+}
+
+// appliedManifestHashes is keyed by group/version/resource/namespace/name of the applied resource.
+type appliedManifestHashes map[string]appliedManifestHash
+
+func appliedManifestHashKey(gvr schema.GroupVersionResource, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s", gvr.Group, gvr.Version, gvr.Resource, namespace, name)
+}
+
+// getAppliedManifestHashes reads the recorded hashes from the appliedmanifestwork. A missing or
+// malformed annotation is treated as empty so that every manifest is applied again.
+func getAppliedManifestHashes(appliedManifestWork *workapiv1.AppliedManifestWork) appliedManifestHashes {
+	hashes := appliedManifestHashes{}
+	data, ok := appliedManifestWork.Annotations[AppliedManifestHashesAnnotationKey]
+	if !ok || len(data) == 0 {
+		return hashes
+	}
+	if err := json.Unmarshal([]byte(data), &hashes); err != nil {
+		klog.Warningf("Ignore the malformed annotation %s on appliedmanifestwork %s: %v",
+			AppliedManifestHashesAnnotationKey, appliedManifestWork.Name, err)
+		return appliedManifestHashes{}
+	}
+	return hashes
+}
+
+// setAppliedManifestHashes returns a copy of the appliedmanifestwork meta with the hashes annotation set.
+func setAppliedManifestHashes(objectMeta metav1.ObjectMeta, hashes appliedManifestHashes) (metav1.ObjectMeta, error) {
+	newMeta := *objectMeta.DeepCopy()
+	if newMeta.Annotations == nil {
+		newMeta.Annotations = map[string]string{}
+	}
+	if len(hashes) == 0 {
+		delete(newMeta.Annotations, AppliedManifestHashesAnnotationKey)
+		return newMeta, nil
+	}
+	data, err := json.Marshal(hashes)
+	if err != nil {
+		return newMeta, err
+	}
+	newMeta.Annotations[AppliedManifestHashesAnnotationKey] = string(data)
+	return newMeta, nil
+}
+
+// manifestHash computes the hash of everything that determines the result of an apply: the required
+// object, the owner to set on it and the manifest config option.
+func manifestHash(required *unstructured.Unstructured, owner metav1.OwnerReference,
+	option *workapiv1.ManifestConfigOption) (string, error) {
+	data, err := json.Marshal(struct {
+		Required *unstructured.Unstructured      `json:"required"`
+		Owner    metav1.OwnerReference           `json:"owner"`
+		Option   *workapiv1.ManifestConfigOption `json:"option,omitempty"`
+	}{required, owner, option})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// newAppliedManifestHash builds the record for a successfully applied object.
+func newAppliedManifestHash(hash string, obj runtime.Object) (appliedManifestHash, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return appliedManifestHash{}, err
+	}
+	return appliedManifestHash{
+		Hash:            hash,
+		Generation:      accessor.GetGeneration(),
+		ResourceVersion: accessor.GetResourceVersion(),
+	}, nil
+}
+
+// unchanged returns true if the live object has not been changed since the recorded apply. The
+// generation is compared when the resource maintains one, so that status updates do not trigger an
+// apply, otherwise the resource version is compared. Since the generation is not bumped by the changes
+// of the metadata, the labels and annotations of the required object are also compared with the live ones.
+func (h appliedManifestHash) unchanged(hash string, required, live *unstructured.Unstructured) bool {
+	if h.Hash != hash || !live.GetDeletionTimestamp().IsZero() {
+		return false
+	}
+	if h.Generation > 0 {
+		return h.Generation == live.GetGeneration() &&
+			containsAll(live.GetLabels(), required.GetLabels()) &&
+			containsAll(live.GetAnnotations(), required.GetAnnotations())
+	}
+	return len(h.ResourceVersion) > 0 && h.ResourceVersion == live.GetResourceVersion()
+}
+
+// containsAll returns true if all the entries of the required map are in the live map.
+func containsAll(live, required map[string]string) bool {
+	for key, value := range required {
+		if actual, ok := live[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
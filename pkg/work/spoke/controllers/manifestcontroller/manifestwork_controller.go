@@ -3,21 +3,26 @@ package manifestcontroller
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	kevents "k8s.io/client-go/tools/events"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 
@@ -26,6 +31,8 @@ import (
 	worklister "open-cluster-management.io/api/client/work/listers/work/v1"
 	workapiv1 "open-cluster-management.io/api/work/v1"
 
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/work/helper"
@@ -37,6 +44,8 @@ import (
 var (
 	ResyncInterval     = 5 * time.Minute
 	MaxRequeueDuration = 24 * time.Hour
+	// PreconditionRecheckInterval is the interval to evaluate the preconditions of a blocked work again.
+	PreconditionRecheckInterval = time.Minute
 )
 
 // ManifestWorkController is to reconcile the workload resources
@@ -52,7 +61,26 @@ type ManifestWorkController struct {
 	agentID                    string
 	restMapper                 meta.RESTMapper
 	appliers                   *apply.Appliers
-	validator                  auth.ExecutorValidator
+	// externalAppliers apply the manifests of the kinds which are not served by the spoke apiserver.
+	externalAppliers *apply.ExternalAppliers
+	validator        auth.ExecutorValidator
+	// skipUnchangedManifests skips applying a manifest if neither the manifest nor the live
+	// resource has changed since its last successful apply.
+	skipUnchangedManifests bool
+	// adoptionLabelKey is the label marking an existing resource adoptable with the Labeled adoption policy.
+	adoptionLabelKey string
+	// resourcePolicy restricts the kinds of the resources the works are allowed to apply on the cluster.
+	resourcePolicy *helper.ResourcePolicy
+	// applyTimeout and syncDeadline are the default timeout of applying a manifest and the default deadline of
+	// applying all the manifests of a work, they are overridden by the annotations of the work.
+	applyTimeout  time.Duration
+	syncDeadline  time.Duration
+	backoffs      *manifestBackoffTracker
+	preconditions *preconditionEvaluator
+	// eventRecorder emits the events regarding the manifestworks on the hub
+	eventRecorder kevents.EventRecorder
+	// critical is set on the controller handling the works of the Critical priority class
+	critical bool
 }
 
 type applyResult struct {
@@ -60,11 +88,42 @@ type applyResult struct {
 	Error  error
 
 	resourceMeta workapiv1.ManifestResourceMeta
+	gvr          schema.GroupVersionResource
+	hash         string
+	// backoff is set when the apply is skipped since the manifest is waiting for its next retry
+	backoff *manifestFailure
+	// appliedOnce is set when the resource of an ApplyOnce manifest has been created by the work
+	appliedOnce bool
+	// adopted is set when the existing resource of the manifest has been adopted by the work
+	adopted bool
+	// appliedOnceDeleted is set when the resource of an ApplyOnce manifest created by the work has been deleted on
+	// the managed cluster, the apply is skipped since the resource is not recreated
+	appliedOnceDeleted bool
+	// duration is how long the apply of the manifest takes
+	duration time.Duration
+}
+
+// workApplyContext is the state of a manifestwork shared by the applies of its manifests in one reconcile.
+type workApplyContext struct {
+	manifestWork        *workapiv1.ManifestWork
+	appliedManifestWork *workapiv1.AppliedManifestWork
+	owner               metav1.OwnerReference
+	appliedHashes       appliedManifestHashes
+	retryBackoff        *RetryBackoff
+	adoptionPolicy      AdoptionPolicy
+	appliedOnce         sets.Set[string]
+	adopted             sets.Set[string]
+	crdDeps             *crdDependencies
+	applyTimeout        time.Duration
+	syncDeadline        time.Duration
+	// deadline is the time before which the manifests are applied, it is zero if there is no sync deadline.
+	deadline time.Time
 }
 
 // NewManifestWorkController returns a ManifestWorkController
 func NewManifestWorkController(
 	recorder events.Recorder,
+	krecorder kevents.EventRecorder,
 	spokeDynamicClient dynamic.Interface,
 	spokeKubeClient kubernetes.Interface,
 	spokeAPIExtensionClient apiextensionsclient.Interface,
@@ -75,7 +134,12 @@ func NewManifestWorkController(
 	appliedManifestWorkInformer workinformer.AppliedManifestWorkInformer,
 	hubHash, agentID string,
 	restMapper meta.RESTMapper,
-	validator auth.ExecutorValidator) factory.Controller {
+	validator auth.ExecutorValidator,
+	skipUnchangedManifests bool,
+	adoptionLabelKey string,
+	resourcePolicy *helper.ResourcePolicy,
+	externalAppliers *apply.ExternalAppliers,
+	applyTimeout, syncDeadline time.Duration) factory.Controller {
 
 	controller := &ManifestWorkController{
 		manifestWorkPatcher: patcher.NewPatcher[
@@ -92,16 +156,55 @@ func NewManifestWorkController(
 		agentID:                   agentID,
 		restMapper:                restMapper,
 		appliers:                  apply.NewAppliers(spokeDynamicClient, spokeKubeClient, spokeAPIExtensionClient),
+		externalAppliers:          externalAppliers,
 		validator:                 validator,
+		skipUnchangedManifests:    skipUnchangedManifests,
+		adoptionLabelKey:          adoptionLabelKey,
+		resourcePolicy:            resourcePolicy,
+		applyTimeout:              applyTimeout,
+		syncDeadline:              syncDeadline,
+		backoffs:                  newManifestBackoffTracker(),
+		eventRecorder:             krecorder,
+		preconditions: &preconditionEvaluator{
+			dynamicClient:   spokeDynamicClient,
+			discoveryClient: spokeKubeClient.Discovery(),
+		},
+	}
+
+	criticalController := *controller
+	criticalController.critical = true
+
+	return &prioritizedController{
+		critical: newPriorityClassController(&criticalController, manifestWorkInformer, appliedManifestWorkInformer, recorder),
+		normal:   newPriorityClassController(controller, manifestWorkInformer, appliedManifestWorkInformer, recorder),
+	}
+}
+
+// newPriorityClassController returns the controller handling the works of the priority class of the controller.
+func newPriorityClassController(controller *ManifestWorkController,
+	manifestWorkInformer workinformer.ManifestWorkInformer,
+	appliedManifestWorkInformer workinformer.AppliedManifestWorkInformer,
+	recorder events.Recorder) factory.Controller {
+	hubHashFilter := helper.AppliedManifestworkHubHashFilter(controller.hubHash)
+	priorityClassFilter := controller.priorityClassFilter(controller.critical)
+	name := "ManifestWorkAgent"
+	if controller.critical {
+		name = "CriticalManifestWorkAgent"
 	}
 
 	return factory.New().
-		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, manifestWorkInformer.Informer()).
+		WithFilteredEventsInformersQueueKeysFunc(
+			queue.QueueKeyByMetaName,
+			priorityClassFilter,
+			manifestWorkInformer.Informer()).
 		WithFilteredEventsInformersQueueKeyFunc(
-			helper.AppliedManifestworkQueueKeyFunc(hubHash),
-			helper.AppliedManifestworkHubHashFilter(hubHash),
+			helper.AppliedManifestworkQueueKeyFunc(controller.hubHash),
+			func(obj interface{}) bool {
+				return hubHashFilter(obj) && priorityClassFilter(obj)
+			},
 			appliedManifestWorkInformer.Informer()).
-		WithSync(controller.sync).ResyncEvery(ResyncInterval).ToController("ManifestWorkAgent", recorder)
+		WithSync(logging.WithControllerLogger(name, controller.sync)).
+		ResyncEvery(ResyncInterval).ToController(name, recorder)
 }
 
 // sync is the main reconcile loop for manifest work. It is triggered in two scenarios
@@ -109,16 +212,22 @@ func NewManifestWorkController(
 // 2. Resources defined in manifest changed on spoke
 func (m *ManifestWorkController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
 	manifestWorkName := controllerContext.QueueKey()
-	klog.V(4).Infof("Reconciling ManifestWork %q", manifestWorkName)
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling ManifestWork", "manifestWork", manifestWorkName)
 
 	oldManifestWork, err := m.manifestWorkLister.Get(manifestWorkName)
 	if apierrors.IsNotFound(err) {
 		// work not found, could have been deleted, do nothing.
+		m.backoffs.forget(manifestWorkName)
 		return nil
 	}
 	if err != nil {
 		return err
 	}
+	// the work is handled by the controller of its priority class
+	if helper.IsCriticalManifestWork(oldManifestWork) != m.critical {
+		return nil
+	}
 	manifestWork := oldManifestWork.DeepCopy()
 
 	// no work to do if we're deleted
@@ -132,24 +241,65 @@ func (m *ManifestWorkController) sync(ctx context.Context, controllerContext fac
 		return nil
 	}
 
+	// the work is blocked until the resource policy of the cluster is changed, which restarts the agent.
+	if m.evaluateResourcePolicy(manifestWork) {
+		if _, err := m.manifestWorkPatcher.PatchStatus(ctx, manifestWork, manifestWork.Status, oldManifestWork.Status); err != nil {
+			return fmt.Errorf("failed to update work status with err %w", err)
+		}
+		return nil
+	}
+
+	// evaluate the preconditions of the work before applying anything
+	blocked, err := m.evaluatePreconditions(ctx, manifestWork)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		if _, err := m.manifestWorkPatcher.PatchStatus(ctx, manifestWork, manifestWork.Status, oldManifestWork.Status); err != nil {
+			return fmt.Errorf("failed to update work status with err %w", err)
+		}
+		controllerContext.Queue().AddAfter(manifestWorkName, PreconditionRecheckInterval)
+		return nil
+	}
+
 	// Apply appliedManifestWork
 	appliedManifestWork, err := m.applyAppliedManifestWork(ctx, manifestWork.Name, m.hubHash, m.agentID)
 	if err != nil {
 		return err
 	}
 
-	// We creat a ownerref instead of controller ref since multiple controller can declare the ownership of a manifests
-	owner := helper.NewAppliedManifestWorkOwner(appliedManifestWork)
+	applyCtx := &workApplyContext{
+		manifestWork:        manifestWork,
+		appliedManifestWork: appliedManifestWork,
+		// We creat a ownerref instead of controller ref since multiple controller can declare the ownership of a manifests
+		owner:          *helper.NewAppliedManifestWorkOwner(appliedManifestWork),
+		retryBackoff:   getRetryBackoff(manifestWork),
+		adoptionPolicy: getAdoptionPolicy(manifestWork),
+		applyTimeout:   getWorkDuration(manifestWork, ApplyTimeoutAnnotationKey, m.applyTimeout),
+		syncDeadline:   getWorkDuration(manifestWork, SyncDeadlineAnnotationKey, m.syncDeadline),
+	}
+	if applyCtx.syncDeadline > 0 {
+		applyCtx.deadline = time.Now().Add(applyCtx.syncDeadline)
+	}
+	if m.skipUnchangedManifests {
+		applyCtx.appliedHashes = getAppliedManifestHashes(appliedManifestWork)
+	}
+	applyCtx.appliedOnce = getAppliedOnceManifests(appliedManifestWork)
+	applyCtx.adopted = getRecordedManifests(appliedManifestWork, AdoptedManifestsAnnotationKey)
+	applyCtx.crdDeps = getCRDDependencies(manifestWork.Spec.Workload.Manifests)
+	retryBackoff := applyCtx.retryBackoff
+	if retryBackoff == nil {
+		m.backoffs.forget(manifestWorkName)
+	}
 
 	var errs []error
 	// Apply resources on spoke cluster.
 	resourceResults := make([]applyResult, len(manifestWork.Spec.Workload.Manifests))
 	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-		resourceResults = m.applyManifests(
-			ctx, manifestWork.Spec.Workload.Manifests, manifestWork.Spec, controllerContext.Recorder(), *owner, resourceResults)
+		resourceResults = m.applyManifests(ctx, applyCtx, controllerContext.Recorder(), resourceResults)
 
 		for _, result := range resourceResults {
-			if apierrors.IsConflict(result.Error) {
+			if result.backoff == nil && apierrors.IsConflict(result.Error) {
 				return result.Error
 			}
 		}
@@ -157,7 +307,7 @@ func (m *ManifestWorkController) sync(ctx context.Context, controllerContext fac
 		return nil
 	})
 	if err != nil {
-		klog.Errorf("failed to apply resource with error %v", err)
+		logger.Error(err, "Failed to apply resource", "manifestWork", manifestWorkName)
 	}
 
 	var newManifestConditions []workapiv1.ManifestCondition
@@ -171,13 +321,38 @@ func (m *ManifestWorkController) sync(ctx context.Context, controllerContext fac
 		// Add applied status condition
 		manifestCondition.Conditions = append(manifestCondition.Conditions, buildAppliedStatusCondition(result))
 
+		// Record the failure and postpone the next retry of the manifest if retry backoff is configured. The
+		// error is set to nil after the condition is constructed, so the work is requeued at the next retry
+		// time instead of being rate limited by the queue.
+		if retryBackoff != nil {
+			backoffKey := manifestBackoffKey(manifestWorkName, result.resourceMeta)
+			failure := result.backoff
+			switch {
+			case failure == nil && result.Error != nil:
+				recorded := m.backoffs.failed(backoffKey, retryBackoff, result.Error)
+				failure = &recorded
+			case failure == nil:
+				m.backoffs.succeeded(backoffKey)
+			}
+			manifestCondition.Conditions = append(manifestCondition.Conditions, buildRetryBackoffCondition(failure))
+
+			if failure != nil {
+				logger.V(2).Info("Apply work fails", "manifestWork", manifestWorkName, "err", result.Error,
+					"retryAt", failure.nextRetry)
+				result.Error = nil
+				if retryAfter := time.Until(failure.nextRetry); retryAfter < requeueTime {
+					requeueTime = retryAfter
+				}
+			}
+		}
+
 		newManifestConditions = append(newManifestConditions, manifestCondition)
 
 		// If it is a forbidden error, after the condition is constructed, we set the error to nil
 		// and requeue the item
 		var authError *basic.NotAllowedError
 		if errors.As(result.Error, &authError) {
-			klog.V(2).Infof("apply work %s fails with err: %v", manifestWorkName, result.Error)
+			logger.V(2).Info("Apply work fails", "manifestWork", manifestWorkName, "err", result.Error)
 			result.Error = nil
 
 			if authError.RequeueTime < requeueTime {
@@ -191,6 +366,10 @@ func (m *ManifestWorkController) sync(ctx context.Context, controllerContext fac
 			errs = append(errs, result.Error)
 		}
 	}
+	if err := m.updateAppliedManifestRecords(ctx, appliedManifestWork, resourceResults); err != nil {
+		errs = append(errs, fmt.Errorf("failed to update applied manifest records with err %w", err))
+	}
+
 	manifestWork.Status.ResourceStatus.Manifests = helper.MergeManifestConditions(
 		manifestWork.Status.ResourceStatus.Manifests, newManifestConditions)
 	// handle condition type Applied
@@ -210,12 +389,21 @@ func (m *ManifestWorkController) sync(ctx context.Context, controllerContext fac
 		}
 		meta.SetStatusCondition(&manifestWork.Status.Conditions, appliedCondition)
 	}
+	setSyncDeadlineCondition(manifestWork, applyCtx, resourceResults)
+	if crdsEstablishedCondition := buildCRDsEstablishedCondition(manifestWork.Generation, applyCtx.crdDeps); crdsEstablishedCondition != nil {
+		meta.SetStatusCondition(&manifestWork.Status.Conditions, *crdsEstablishedCondition)
+	} else {
+		meta.RemoveStatusCondition(&manifestWork.Status.Conditions, WorkCRDsEstablished)
+	}
 
 	// Update work status
 	updated, err := m.manifestWorkPatcher.PatchStatus(ctx, manifestWork, manifestWork.Status, oldManifestWork.Status)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to update work status with err %w", err))
 	}
+	if updated && err == nil {
+		m.recordAppliedEvent(oldManifestWork, manifestWork, newManifestConditions)
+	}
 
 	if !updated && requeueTime < MaxRequeueDuration {
 		controllerContext.Queue().AddAfter(manifestWorkName, requeueTime)
@@ -223,7 +411,7 @@ func (m *ManifestWorkController) sync(ctx context.Context, controllerContext fac
 
 	if len(errs) > 0 {
 		err = utilerrors.NewAggregate(errs)
-		klog.Errorf("Reconcile work %s fails with err: %v", manifestWorkName, err)
+		logger.Error(err, "Reconcile work fails", "manifestWork", manifestWorkName)
 	}
 
 	return err
@@ -256,23 +444,151 @@ func (m *ManifestWorkController) applyAppliedManifestWork(ctx context.Context, w
 	return appliedManifestWork, err
 }
 
+// evaluatePreconditions evaluates the preconditions of the work and sets the Blocked condition on the work
+// status accordingly. It returns true if the preconditions are not met.
+func (m *ManifestWorkController) evaluatePreconditions(ctx context.Context, manifestWork *workapiv1.ManifestWork) (bool, error) {
+	preconditions, err := getPreconditions(manifestWork)
+	if err != nil {
+		meta.SetStatusCondition(&manifestWork.Status.Conditions,
+			buildBlockedCondition(manifestWork.Generation, []string{err.Error()}))
+		return true, nil
+	}
+	if preconditions == nil {
+		meta.RemoveStatusCondition(&manifestWork.Status.Conditions, WorkBlocked)
+		return false, nil
+	}
+
+	unmet, err := m.preconditions.evaluate(ctx, preconditions)
+	if err != nil {
+		return false, err
+	}
+	meta.SetStatusCondition(&manifestWork.Status.Conditions, buildBlockedCondition(manifestWork.Generation, unmet))
+	return len(unmet) > 0, nil
+}
+
+// evaluateResourcePolicy sets the Blocked condition on the work status if any of its manifests is not allowed by the
+// resource policy of the cluster, none of the manifests is applied then. It returns true if the work is blocked.
+func (m *ManifestWorkController) evaluateResourcePolicy(manifestWork *workapiv1.ManifestWork) bool {
+	if m.resourcePolicy == nil {
+		return false
+	}
+
+	var notAllowed []string
+	for _, manifest := range manifestWork.Spec.Workload.Manifests {
+		obj := &unstructured.Unstructured{}
+		// the invalid manifests are reported once they are applied
+		if err := obj.UnmarshalJSON(manifest.Raw); err != nil {
+			continue
+		}
+		gvk := obj.GroupVersionKind()
+		if m.resourcePolicy.Allowed(gvk) {
+			continue
+		}
+		name := obj.GetName()
+		if len(obj.GetNamespace()) > 0 {
+			name = fmt.Sprintf("%s/%s", obj.GetNamespace(), name)
+		}
+		notAllowed = append(notAllowed, fmt.Sprintf("%s %s", gvk.GroupKind().String(), name))
+	}
+	if len(notAllowed) == 0 {
+		return false
+	}
+
+	meta.SetStatusCondition(&manifestWork.Status.Conditions, metav1.Condition{
+		Type:               WorkBlocked,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: manifestWork.Generation,
+		Reason:             "ResourcesNotAllowed",
+		Message:            fmt.Sprintf("Resources are not allowed on the cluster: %s", strings.Join(notAllowed, "; ")),
+	})
+	return true
+}
+
+// updateAppliedManifestRecords records the hashes of the successfully applied manifests and the created
+// ApplyOnce resources on the appliedmanifestwork. The hashes are used by the next reconcile to skip the
+// manifests that are not changed, and the ApplyOnce resources are not created again.
+func (m *ManifestWorkController) updateAppliedManifestRecords(
+	ctx context.Context, appliedManifestWork *workapiv1.AppliedManifestWork, results []applyResult) error {
+	newMeta := *appliedManifestWork.ObjectMeta.DeepCopy()
+
+	if m.skipUnchangedManifests {
+		hashes := appliedManifestHashes{}
+		for _, result := range results {
+			if result.Error != nil || result.Result == nil || len(result.hash) == 0 {
+				continue
+			}
+			appliedHash, err := newAppliedManifestHash(result.hash, result.Result)
+			if err != nil {
+				return err
+			}
+			hashes[appliedManifestHashKey(result.gvr, result.resourceMeta.Namespace, result.resourceMeta.Name)] = appliedHash
+		}
+
+		var err error
+		newMeta, err = setAppliedManifestHashes(newMeta, hashes)
+		if err != nil {
+			return err
+		}
+	}
+
+	appliedOnce := sets.New[string]()
+	for _, result := range results {
+		if result.appliedOnce {
+			appliedOnce.Insert(appliedManifestHashKey(result.gvr, result.resourceMeta.Namespace, result.resourceMeta.Name))
+		}
+	}
+	if err := setAppliedOnceManifests(&newMeta, appliedOnce); err != nil {
+		return err
+	}
+
+	adopted := sets.New[string]()
+	for _, result := range results {
+		if result.adopted {
+			adopted.Insert(appliedManifestHashKey(result.gvr, result.resourceMeta.Namespace, result.resourceMeta.Name))
+		}
+	}
+	if err := setRecordedManifests(&newMeta, AdoptedManifestsAnnotationKey, adopted); err != nil {
+		return err
+	}
+
+	_, err := m.appliedManifestWorkPatcher.PatchLabelAnnotations(ctx, appliedManifestWork, newMeta, appliedManifestWork.ObjectMeta)
+	return err
+}
+
 func (m *ManifestWorkController) applyManifests(
 	ctx context.Context,
-	manifests []workapiv1.Manifest,
-	workSpec workapiv1.ManifestWorkSpec,
+	applyCtx *workApplyContext,
 	recorder events.Recorder,
-	owner metav1.OwnerReference,
 	existingResults []applyResult) []applyResult {
 
-	for index, manifest := range manifests {
+	manifests := applyCtx.manifestWork.Spec.Workload.Manifests
+	apply := func(index int) {
 		switch {
-		case existingResults[index].Result == nil:
+		case existingResults[index].Result == nil && existingResults[index].backoff == nil &&
+			!existingResults[index].appliedOnceDeleted:
 			// Apply if there is no result.
-			existingResults[index] = m.applyOneManifest(ctx, index, manifest, workSpec, recorder, owner)
-		case apierrors.IsConflict(existingResults[index].Error):
+			existingResults[index] = m.applyWithDeadline(ctx, index, manifests[index], applyCtx, recorder)
+		case existingResults[index].backoff == nil && apierrors.IsConflict(existingResults[index].Error):
 			// Apply if there is a resource conflict error.
-			existingResults[index] = m.applyOneManifest(ctx, index, manifest, workSpec, recorder, owner)
+			existingResults[index] = m.applyWithDeadline(ctx, index, manifests[index], applyCtx, recorder)
+		}
+	}
+
+	// apply the custom resources after the other manifests, so the CRDs in the work are applied first.
+	for index := range manifests {
+		if _, ok := applyCtx.crdDeps.dependents[index]; !ok {
+			apply(index)
+		}
+	}
+	for index := range manifests {
+		if _, ok := applyCtx.crdDeps.dependents[index]; !ok {
+			continue
+		}
+		if crds := m.notEstablished(ctx, applyCtx.crdDeps, index, existingResults); len(crds) > 0 {
+			existingResults[index] = crdNotEstablishedResult(index, manifests[index], crds)
+			continue
 		}
+		apply(index)
 	}
 
 	return existingResults
@@ -282,11 +598,11 @@ func (m *ManifestWorkController) applyOneManifest(
 	ctx context.Context,
 	index int,
 	manifest workapiv1.Manifest,
-	workSpec workapiv1.ManifestWorkSpec,
-	recorder events.Recorder,
-	owner metav1.OwnerReference) applyResult {
+	applyCtx *workApplyContext,
+	recorder events.Recorder) applyResult {
 
 	result := applyResult{}
+	workSpec := applyCtx.manifestWork.Spec
 
 	// parse the required and set resource meta
 	required := &unstructured.Unstructured{}
@@ -297,19 +613,61 @@ func (m *ManifestWorkController) applyOneManifest(
 
 	// ignore the required object UID to avoid UID precondition failed error
 	if len(required.GetUID()) != 0 {
-		klog.Warningf("Ignore the UID %s for the manifest index %d", required.GetUID(), index)
+		klog.FromContext(ctx).Info("Ignore the UID of the manifest", "uid", required.GetUID(), "index", index)
 		required.SetUID("")
 	}
 
+	// the manifests of the external kinds are neither served by the spoke apiserver nor tracked by the
+	// appliedmanifestwork, they are deleted by the external appliers once the work is deleted.
+	if externalApplier := m.externalAppliers.Get(required.GroupVersionKind().GroupKind()); externalApplier != nil {
+		result.resourceMeta, _, result.Error = helper.BuildResourceMeta(index, required, nil)
+		if result.Error != nil {
+			return result
+		}
+		// the Executor subject is checked against the resource guessed from the kind, and the resource is regarded as
+		// owned by the work since it is deleted with the work.
+		externalGVR, _ := meta.UnsafeGuessKindToResource(required.GroupVersionKind())
+		result.Error = m.validator.Validate(ctx, workSpec.Executor, externalGVR,
+			result.resourceMeta.Namespace, result.resourceMeta.Name, true, required)
+		if result.Error != nil {
+			return result
+		}
+		if obj, err := externalApplier.Apply(ctx, required); err != nil {
+			result.Error = err
+		} else {
+			result.Result = obj
+		}
+		return result
+	}
+
 	resMeta, gvr, err := helper.BuildResourceMeta(index, required, m.restMapper)
 	result.resourceMeta = resMeta
+	result.gvr = gvr
 	if err != nil {
 		result.Error = err
 		return result
 	}
 
+	// keep the record of the created ApplyOnce resource and the adopted resource even if the apply is skipped or
+	// fails below
+	lifecycle := getManifestLifecycle(required)
+	if len(lifecycle) > 0 {
+		result.appliedOnce = applyCtx.appliedOnce.Has(appliedManifestHashKey(gvr, resMeta.Namespace, resMeta.Name))
+	}
+	result.adopted = applyCtx.adopted.Has(appliedManifestHashKey(gvr, resMeta.Namespace, resMeta.Name))
+
+	// do not apply the manifest until its next retry time if it failed before
+	if applyCtx.retryBackoff != nil {
+		if failure, waiting := m.backoffs.waiting(manifestBackoffKey(applyCtx.manifestWork.Name, resMeta)); waiting {
+			result.Error = failure.lastError
+			result.backoff = failure
+			return result
+		}
+	}
+
 	// check if the resource to be applied should be owned by the manifest work
-	ownedByTheWork := helper.OwnedByTheWork(gvr, resMeta.Namespace, resMeta.Name, workSpec.DeleteOption)
+	ownedByTheWork := helper.OwnedByTheWork(gvr, resMeta.Namespace, resMeta.Name, workSpec.DeleteOption) &&
+		lifecycle != ManifestLifecycleApplyOnceOrphan
 
 	// check the Executor subject permission before applying
 	err = m.validator.Validate(ctx, workSpec.Executor, gvr, resMeta.Namespace, resMeta.Name, ownedByTheWork, required)
@@ -319,7 +677,29 @@ func (m *ManifestWorkController) applyOneManifest(
 	}
 
 	// compute required ownerrefs based on delete option
-	requiredOwner := manageOwnerRef(ownedByTheWork, owner)
+	requiredOwner := manageOwnerRef(ownedByTheWork, applyCtx.owner)
+
+	// the ApplyOnce manifests are never updated regardless of the update strategy
+	if len(lifecycle) > 0 {
+		if result.Error = m.adoptIfEnabled(ctx, applyCtx, gvr, &result, required, recorder); result.Error != nil {
+			return result
+		}
+		obj, created, err := m.applyOnce(ctx, applyCtx, gvr, required, requiredOwner, recorder)
+		result.appliedOnce = result.appliedOnce || created
+		result.Error = err
+		switch {
+		case err != nil:
+		case obj == nil:
+			result.appliedOnceDeleted = true
+		case created:
+			// only the resource created by the work is owned by the work
+			result.Result = obj
+			result.Error = helper.ApplyOwnerReferences(ctx, m.spokeDynamicClient, gvr, obj, requiredOwner)
+		default:
+			result.Result = obj
+		}
+		return result
+	}
 
 	// find update strategy option.
 	option := helper.FindManifestConiguration(resMeta, workSpec.ManifestConfigs)
@@ -329,6 +709,28 @@ func (m *ManifestWorkController) applyOneManifest(
 		strategy = *option.UpdateStrategy
 	}
 
+	if m.skipUnchangedManifests {
+		hash, err := manifestHash(required, requiredOwner, option)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		result.hash = hash
+
+		if live := m.getUnchangedResource(ctx, gvr, required, hash, applyCtx.appliedHashes); live != nil {
+			klog.FromContext(ctx).V(4).Info("Skip applying the unchanged manifest",
+				"gvr", gvr, "namespace", resMeta.Namespace, "name", resMeta.Name)
+			result.Result = live
+			return result
+		}
+	}
+
+	// the adoption is checked after the unchanged manifests are skipped, since the adopted-by annotation set on the
+	// required object is not a part of the manifest hash.
+	if result.Error = m.adoptIfEnabled(ctx, applyCtx, gvr, &result, required, recorder); result.Error != nil {
+		return result
+	}
+
 	applier := m.appliers.GetApplier(strategy.Type)
 	result.Result, result.Error = applier.Apply(ctx, gvr, required, requiredOwner, option, recorder)
 
@@ -340,6 +742,44 @@ func (m *ManifestWorkController) applyOneManifest(
 	return result
 }
 
+// adoptIfEnabled checks whether the resource could be adopted if it already exists and is not applied by the work,
+// when the work has an adoption policy.
+func (m *ManifestWorkController) adoptIfEnabled(ctx context.Context, applyCtx *workApplyContext,
+	gvr schema.GroupVersionResource, result *applyResult, required *unstructured.Unstructured,
+	recorder events.Recorder) error {
+	if len(applyCtx.adoptionPolicy) == 0 {
+		return nil
+	}
+	adopted, err := m.adopt(ctx, applyCtx, gvr, result.resourceMeta, required, recorder)
+	if err != nil {
+		return err
+	}
+	result.adopted = result.adopted || adopted
+	return nil
+}
+
+// getUnchangedResource returns the live resource if the manifest hash matches the recorded one and the
+// live resource is not changed since the last apply, otherwise it returns nil and the manifest should
+// be applied.
+func (m *ManifestWorkController) getUnchangedResource(ctx context.Context, gvr schema.GroupVersionResource,
+	required *unstructured.Unstructured, hash string, appliedHashes appliedManifestHashes) *unstructured.Unstructured {
+	appliedHash, ok := appliedHashes[appliedManifestHashKey(gvr, required.GetNamespace(), required.GetName())]
+	if !ok || appliedHash.Hash != hash {
+		return nil
+	}
+
+	live, err := m.spokeDynamicClient.Resource(gvr).Namespace(required.GetNamespace()).Get(
+		ctx, required.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+
+	if !appliedHash.unchanged(hash, required, live) {
+		return nil
+	}
+	return live
+}
+
 // manageOwnerRef return a ownerref based on the resource and the ownedByTheWork indicating whether the owneref
 // should be removed or added. If the resource is not owned by the work, the owner's UID is updated for removal.
 func manageOwnerRef(
@@ -354,6 +794,37 @@ func manageOwnerRef(
 	return *ownerCopy
 }
 
+// recordAppliedEvent emits an event regarding the manifestwork when its Applied condition transitions, or the
+// manifests of a new generation of the work are applied.
+func (m *ManifestWorkController) recordAppliedEvent(
+	oldWork, newWork *workapiv1.ManifestWork, manifestConditions []workapiv1.ManifestCondition) {
+	newCond := meta.FindStatusCondition(newWork.Status.Conditions, workapiv1.WorkApplied)
+	if newCond == nil {
+		return
+	}
+	oldCond := meta.FindStatusCondition(oldWork.Status.Conditions, workapiv1.WorkApplied)
+	if oldCond != nil && oldCond.Status == newCond.Status && oldCond.ObservedGeneration == newCond.ObservedGeneration {
+		return
+	}
+
+	if newCond.Status == metav1.ConditionTrue {
+		m.eventRecorder.Eventf(newWork, nil, corev1.EventTypeNormal,
+			commonhelpers.EventReasonManifestWorkApplied, commonhelpers.EventActionApply,
+			"Applied %d manifests of work %s", len(manifestConditions), newWork.Name)
+		return
+	}
+
+	failed := 0
+	for _, manifestCondition := range manifestConditions {
+		if meta.IsStatusConditionFalse(manifestCondition.Conditions, workapiv1.ManifestApplied) {
+			failed++
+		}
+	}
+	m.eventRecorder.Eventf(newWork, nil, corev1.EventTypeWarning,
+		commonhelpers.EventReasonManifestWorkApplyFailed, commonhelpers.EventActionApply,
+		"Failed to apply %d of %d manifests of work %s", failed, len(manifestConditions), newWork.Name)
+}
+
 // allInCondition checks status of conditions with a particular type in ManifestCondition array.
 // Return true only if conditions with the condition type exist and they are all in condition.
 func allInCondition(conditionType string, manifests []workapiv1.ManifestCondition) (inCondition bool, exists bool) {
@@ -382,6 +853,15 @@ func buildAppliedStatusCondition(result applyResult) metav1.Condition {
 		}
 	}
 
+	if result.appliedOnceDeleted {
+		return metav1.Condition{
+			Type:    workapiv1.ManifestApplied,
+			Status:  metav1.ConditionTrue,
+			Reason:  "AppliedOnceResourceDeleted",
+			Message: "The resource was applied once and is not recreated since it was deleted on the managed cluster",
+		}
+	}
+
 	return metav1.Condition{
 		Type:    workapiv1.ManifestApplied,
 		Status:  metav1.ConditionTrue,
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -19,11 +21,13 @@ import (
 	fakedynamic "k8s.io/client-go/dynamic/fake"
 	fakekube "k8s.io/client-go/kubernetes/fake"
 	clienttesting "k8s.io/client-go/testing"
+	kevents "k8s.io/client-go/tools/events"
 
 	fakeworkclient "open-cluster-management.io/api/client/work/clientset/versioned/fake"
 	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
 	workapiv1 "open-cluster-management.io/api/work/v1"
 
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
 	"open-cluster-management.io/ocm/pkg/work/helper"
@@ -39,12 +43,14 @@ type testController struct {
 	dynamicClient *fakedynamic.FakeDynamicClient
 	workClient    *fakeworkclient.Clientset
 	kubeClient    *fakekube.Clientset
+	eventRecorder *kevents.FakeRecorder
 }
 
 func newController(t *testing.T, work *workapiv1.ManifestWork, appliedWork *workapiv1.AppliedManifestWork, mapper meta.RESTMapper) *testController {
 	fakeWorkClient := fakeworkclient.NewSimpleClientset(work)
 	workInformerFactory := workinformers.NewSharedInformerFactoryWithOptions(fakeWorkClient, 5*time.Minute, workinformers.WithNamespace("cluster1"))
 	spokeKubeClient := fakekube.NewSimpleClientset()
+	eventRecorder := kevents.NewFakeRecorder(100)
 	controller := &ManifestWorkController{
 		manifestWorkPatcher: patcher.NewPatcher[
 			*workapiv1.ManifestWork, workapiv1.ManifestWorkSpec, workapiv1.ManifestWorkStatus](
@@ -57,6 +63,8 @@ func newController(t *testing.T, work *workapiv1.ManifestWork, appliedWork *work
 		appliedManifestWorkLister: workInformerFactory.Work().V1().AppliedManifestWorks().Lister(),
 		restMapper:                mapper,
 		validator:                 basic.NewSARValidator(nil, spokeKubeClient),
+		backoffs:                  newManifestBackoffTracker(),
+		eventRecorder:             eventRecorder,
 	}
 
 	if err := workInformerFactory.Work().V1().ManifestWorks().Informer().GetStore().Add(work); err != nil {
@@ -69,8 +77,9 @@ func newController(t *testing.T, work *workapiv1.ManifestWork, appliedWork *work
 	}
 
 	return &testController{
-		controller: controller,
-		workClient: fakeWorkClient,
+		controller:    controller,
+		workClient:    fakeWorkClient,
+		eventRecorder: eventRecorder,
 	}
 }
 
@@ -343,6 +352,7 @@ func TestSync(t *testing.T) {
 			}
 
 			c.validate(t, controller.dynamicClient, controller.workClient, controller.kubeClient)
+			testingcommon.AssertEventReasons(t, controller.eventRecorder, commonhelpers.EventReasonManifestWorkApplied)
 		})
 	}
 }
@@ -387,6 +397,7 @@ func TestFailedToApplyResource(t *testing.T) {
 	}
 
 	tc.validate(t, controller.dynamicClient, controller.workClient, controller.kubeClient)
+	testingcommon.AssertEventReasons(t, controller.eventRecorder, commonhelpers.EventReasonManifestWorkApplyFailed)
 }
 
 func TestUpdateStrategy(t *testing.T) {
@@ -783,3 +794,244 @@ func TestManageOwner(t *testing.T) {
 		})
 	}
 }
+
+func TestSkipUnchangedManifests(t *testing.T) {
+	manifest := spoketesting.NewUnstructuredWithContent(
+		"v1", "NewObject", "ns1", "n1",
+		map[string]interface{}{"spec": map[string]interface{}{"key1": "val1"}})
+	manifest.SetLabels(map[string]string{"app": "test"})
+	option := newManifestConfigOption(
+		"", "newobjects", "ns1", "n1",
+		&workapiv1.UpdateStrategy{Type: workapiv1.UpdateStrategyTypeServerSideApply})
+	appliedWork := spoketesting.NewAppliedManifestWork("", 0, "appliedwork-uid")
+	hash, err := manifestHash(manifest, *helper.NewAppliedManifestWorkOwner(appliedWork), &option)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "newobjects"}
+
+	cases := []struct {
+		name                      string
+		recordedHash              appliedManifestHash
+		liveGeneration            int64
+		liveLabels                map[string]string
+		adoptionPolicy            AdoptionPolicy
+		expectedDynamicAction     []string
+		expectedAppliedWorkAction []string
+	}{
+		{
+			name:                  "skip unchanged manifest",
+			recordedHash:          appliedManifestHash{Hash: hash, Generation: 1},
+			liveGeneration:        1,
+			liveLabels:            map[string]string{"app": "test", "other": "value"},
+			expectedDynamicAction: []string{"get"},
+		},
+		{
+			name:                  "skip unchanged manifest without checking the adoption",
+			recordedHash:          appliedManifestHash{Hash: hash, Generation: 1},
+			liveGeneration:        1,
+			liveLabels:            map[string]string{"app": "test"},
+			adoptionPolicy:        AdoptionPolicyNever,
+			expectedDynamicAction: []string{"get"},
+		},
+		{
+			name:                      "apply changed manifest",
+			recordedHash:              appliedManifestHash{Hash: "changed", Generation: 1},
+			liveGeneration:            1,
+			expectedDynamicAction:     []string{"patch", "patch"},
+			expectedAppliedWorkAction: []string{"patch"},
+		},
+		{
+			name:                      "apply unchanged manifest when live resource changed",
+			recordedHash:              appliedManifestHash{Hash: hash, Generation: 1},
+			liveGeneration:            2,
+			liveLabels:                map[string]string{"app": "test"},
+			expectedDynamicAction:     []string{"get", "patch", "patch"},
+			expectedAppliedWorkAction: []string{"patch"},
+		},
+		{
+			name:                      "apply unchanged manifest when live labels changed",
+			recordedHash:              appliedManifestHash{Hash: hash, Generation: 1},
+			liveGeneration:            1,
+			liveLabels:                map[string]string{"app": "changed"},
+			expectedDynamicAction:     []string{"get", "patch", "patch"},
+			expectedAppliedWorkAction: []string{"patch"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			work, workKey := spoketesting.NewManifestWork(0, manifest)
+			work.Spec.ManifestConfigs = []workapiv1.ManifestConfigOption{option}
+			work.Finalizers = []string{workapiv1.ManifestWorkFinalizer}
+			if len(c.adoptionPolicy) > 0 {
+				work.Annotations = map[string]string{AdoptionPolicyAnnotationKey: string(c.adoptionPolicy)}
+			}
+
+			appliedWork := appliedWork.DeepCopy()
+			data, err := json.Marshal(appliedManifestHashes{
+				appliedManifestHashKey(gvr, "ns1", "n1"): c.recordedHash,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			appliedWork.Annotations = map[string]string{AppliedManifestHashesAnnotationKey: string(data)}
+
+			live := manifest.DeepCopy()
+			live.SetGeneration(c.liveGeneration)
+			live.SetLabels(c.liveLabels)
+			controller := newController(t, work, appliedWork, spoketesting.NewFakeRestMapper()).
+				withKubeObject().
+				withUnstructuredObject(live)
+			if err := controller.workClient.Tracker().Add(appliedWork); err != nil {
+				t.Fatal(err)
+			}
+			controller.controller.skipUnchangedManifests = true
+
+			// The default reactor doesn't support apply, so we need our own (trivial) reactor
+			controller.dynamicClient.PrependReactor("patch", "newobjects",
+				func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+					applied := manifest.DeepCopy()
+					applied.SetGeneration(c.liveGeneration + 1)
+					return true, applied, nil
+				})
+			syncContext := testingcommon.NewFakeSyncContext(t, workKey)
+			if err := controller.toController().sync(context.TODO(), syncContext); err != nil {
+				t.Errorf("Should be success with no err: %v", err)
+			}
+
+			testingcommon.AssertActions(t, controller.dynamicClient.Actions(), c.expectedDynamicAction...)
+			var appliedWorkActions []clienttesting.Action
+			for _, action := range controller.workClient.Actions() {
+				if action.GetResource().Resource == "appliedmanifestworks" {
+					appliedWorkActions = append(appliedWorkActions, action)
+				}
+			}
+			testingcommon.AssertActions(t, appliedWorkActions, c.expectedAppliedWorkAction...)
+		})
+	}
+}
+
+func TestSyncBlockedByResourcePolicy(t *testing.T) {
+	work, workKey := spoketesting.NewManifestWork(0,
+		spoketesting.NewUnstructured("v1", "ConfigMap", "ns1", "test"),
+		spoketesting.NewUnstructured("v1", "Secret", "ns1", "test"))
+	work.Finalizers = []string{workapiv1.ManifestWorkFinalizer}
+	controller := newController(t, work, nil, spoketesting.NewFakeRestMapper()).
+		withKubeObject().
+		withUnstructuredObject()
+	policy, err := helper.NewResourcePolicy(nil, []string{"Secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	controller.controller.resourcePolicy = policy
+
+	syncContext := testingcommon.NewFakeSyncContext(t, workKey)
+	if err := controller.toController().sync(context.TODO(), syncContext); err != nil {
+		t.Errorf("expected no error, but got %v", err)
+	}
+
+	testingcommon.AssertNoActions(t, controller.dynamicClient.Actions())
+	testingcommon.AssertActions(t, controller.workClient.Actions(), "patch")
+	patchedWork := &workapiv1.ManifestWork{}
+	if err := json.Unmarshal(controller.workClient.Actions()[0].(clienttesting.PatchActionImpl).Patch, patchedWork); err != nil {
+		t.Fatal(err)
+	}
+	testingcommon.AssertCondition(t, patchedWork.Status.Conditions, metav1.Condition{
+		Type:    WorkBlocked,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ResourcesNotAllowed",
+		Message: "Resources are not allowed on the cluster: Secret ns1/test",
+	})
+}
+
+func TestSyncExternalManifests(t *testing.T) {
+	work, workKey := spoketesting.NewManifestWork(0,
+		spoketesting.NewUnstructured("network.example.com/v1", "Device", "", "test"),
+		spoketesting.NewUnstructured("v1", "Secret", "ns1", "test"))
+	work.Finalizers = []string{workapiv1.ManifestWorkFinalizer}
+	controller := newController(t, work, nil, spoketesting.NewFakeRestMapper()).
+		withKubeObject().
+		withUnstructuredObject()
+	externalAppliers, err := apply.NewExternalAppliers([]apply.ExternalApplierConfig{{
+		Group:   "network.example.com",
+		Kind:    "Device",
+		Command: "sh",
+		Args:    []string{"-c", "cat > /dev/null"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	controller.controller.externalAppliers = externalAppliers
+
+	syncContext := testingcommon.NewFakeSyncContext(t, workKey)
+	if err := controller.toController().sync(context.TODO(), syncContext); err != nil {
+		t.Errorf("expected no error, but got %v", err)
+	}
+
+	for _, action := range controller.dynamicClient.Actions() {
+		if action.GetResource().Group == "network.example.com" {
+			t.Errorf("expected the device not applied on the cluster, but got %v", action)
+		}
+	}
+	patchedWork := &workapiv1.ManifestWork{}
+	for _, action := range controller.workClient.Actions() {
+		if action.GetResource().Resource != "manifestworks" || action.GetVerb() != "patch" {
+			continue
+		}
+		if err := json.Unmarshal(action.(clienttesting.PatchActionImpl).Patch, patchedWork); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertManifestCondition(t, patchedWork.Status.ResourceStatus.Manifests, 0, workapiv1.ManifestApplied, metav1.ConditionTrue)
+	assertManifestCondition(t, patchedWork.Status.ResourceStatus.Manifests, 1, workapiv1.ManifestApplied, metav1.ConditionTrue)
+}
+
+func TestSyncExternalManifestsNotAllowed(t *testing.T) {
+	work, workKey := spoketesting.NewManifestWork(0,
+		spoketesting.NewUnstructured("network.example.com/v1", "Device", "", "test"))
+	work.Finalizers = []string{workapiv1.ManifestWorkFinalizer}
+	work.Spec.Executor = &workapiv1.ManifestWorkExecutor{
+		Subject: workapiv1.ManifestWorkExecutorSubject{
+			Type: workapiv1.ExecutorSubjectTypeServiceAccount,
+			ServiceAccount: &workapiv1.ManifestWorkSubjectServiceAccount{
+				Namespace: "ns1",
+				Name:      "executor",
+			},
+		},
+	}
+	controller := newController(t, work, nil, spoketesting.NewFakeRestMapper()).
+		withKubeObject().
+		withUnstructuredObject()
+	appliedFile := filepath.Join(t.TempDir(), "applied")
+	externalAppliers, err := apply.NewExternalAppliers([]apply.ExternalApplierConfig{{
+		Group:   "network.example.com",
+		Kind:    "Device",
+		Command: "sh",
+		Args:    []string{"-c", "touch " + appliedFile},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	controller.controller.externalAppliers = externalAppliers
+
+	// the subject access reviews of the fake kube client are never allowed
+	syncContext := testingcommon.NewFakeSyncContext(t, workKey)
+	if err := controller.toController().sync(context.TODO(), syncContext); err != nil {
+		t.Errorf("expected no error, but got %v", err)
+	}
+
+	if _, err := os.Stat(appliedFile); !os.IsNotExist(err) {
+		t.Errorf("expected the device not applied by the external applier, but got %v", err)
+	}
+	patchedWork := &workapiv1.ManifestWork{}
+	for _, action := range controller.workClient.Actions() {
+		if action.GetResource().Resource != "manifestworks" || action.GetVerb() != "patch" {
+			continue
+		}
+		if err := json.Unmarshal(action.(clienttesting.PatchActionImpl).Patch, patchedWork); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertManifestCondition(t, patchedWork.Status.ResourceStatus.Manifests, 0, workapiv1.ManifestApplied, metav1.ConditionFalse)
+}
@@ -0,0 +1,171 @@
+package manifestcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+const (
+	// PreconditionsAnnotationKey is the annotation on the ManifestWork to define the preconditions which must be met
+	// on the managed cluster before any manifest of the work is applied. The value is a json string, e.g.
+	// {"clusterClaims":[{"name":"platform.open-cluster-management.io","values":["AWS"]}],
+	//  "crds":["foos.example.com"],"namespaces":["ns1"],"minKubeVersion":"v1.24.0"}
+	PreconditionsAnnotationKey = "work.open-cluster-management.io/preconditions"
+
+	// WorkBlocked is the work condition type which represents the manifests of the work are not applied
+	// since the preconditions of the work are not met.
+	WorkBlocked = "Blocked"
+)
+
+var (
+	clusterClaimGVR = schema.GroupVersionResource{
+		Group: "cluster.open-cluster-management.io", Version: "v1alpha1", Resource: "clusterclaims"}
+	crdGVR = schema.GroupVersionResource{
+		Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	namespaceGVR = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+)
+
+// Preconditions are the rules evaluated on the managed cluster before the manifests of a work are applied.
+type Preconditions struct {
+	// ClusterClaims requires the cluster claims to exist, and if values are set, the claim value to be one of them.
+	ClusterClaims []ClusterClaimPrecondition `json:"clusterClaims,omitempty"`
+	// CRDs requires the custom resource definitions with the given names to exist.
+	CRDs []string `json:"crds,omitempty"`
+	// Namespaces requires the namespaces to exist.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// MinKubeVersion requires the kube version of the managed cluster to be equal to or greater than it.
+	MinKubeVersion string `json:"minKubeVersion,omitempty"`
+}
+
+type ClusterClaimPrecondition struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values,omitempty"`
+}
+
+// getPreconditions returns the preconditions defined on the manifestwork, nil is returned if there is none.
+func getPreconditions(work *workapiv1.ManifestWork) (*Preconditions, error) {
+	data, ok := work.Annotations[PreconditionsAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	preconditions := &Preconditions{}
+	if err := json.Unmarshal([]byte(data), preconditions); err != nil {
+		return nil, fmt.Errorf("invalid annotation %s: %v", PreconditionsAnnotationKey, err)
+	}
+	return preconditions, nil
+}
+
+// preconditionEvaluator evaluates the preconditions of works against the managed cluster.
+type preconditionEvaluator struct {
+	dynamicClient   dynamic.Interface
+	discoveryClient discovery.ServerVersionInterface
+}
+
+// evaluate returns the messages of the unmet preconditions, an empty list means all preconditions are met.
+func (e *preconditionEvaluator) evaluate(ctx context.Context, preconditions *Preconditions) ([]string, error) {
+	var unmet []string
+
+	for _, claim := range preconditions.ClusterClaims {
+		obj, err := e.dynamicClient.Resource(clusterClaimGVR).Get(ctx, claim.Name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			unmet = append(unmet, fmt.Sprintf("cluster claim %q does not exist", claim.Name))
+			continue
+		case err != nil:
+			return nil, err
+		}
+
+		if len(claim.Values) == 0 {
+			continue
+		}
+		value, _, _ := unstructured.NestedString(obj.Object, "spec", "value")
+		if !sets.New[string](claim.Values...).Has(value) {
+			unmet = append(unmet, fmt.Sprintf("cluster claim %q has value %q, expected one of %v", claim.Name, value, claim.Values))
+		}
+	}
+
+	for _, crd := range preconditions.CRDs {
+		exists, err := e.exists(ctx, crdGVR, crd)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			unmet = append(unmet, fmt.Sprintf("custom resource definition %q does not exist", crd))
+		}
+	}
+
+	for _, ns := range preconditions.Namespaces {
+		exists, err := e.exists(ctx, namespaceGVR, ns)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			unmet = append(unmet, fmt.Sprintf("namespace %q does not exist", ns))
+		}
+	}
+
+	if len(preconditions.MinKubeVersion) > 0 {
+		minVersion, err := utilversion.ParseGeneric(preconditions.MinKubeVersion)
+		if err != nil {
+			return append(unmet, fmt.Sprintf("invalid minKubeVersion %q: %v", preconditions.MinKubeVersion, err)), nil
+		}
+		serverVersion, err := e.discoveryClient.ServerVersion()
+		if err != nil {
+			return nil, err
+		}
+		kubeVersion, err := utilversion.ParseGeneric(serverVersion.GitVersion)
+		if err != nil {
+			return nil, err
+		}
+		if kubeVersion.LessThan(minVersion) {
+			unmet = append(unmet, fmt.Sprintf("kube version %s is less than %s", serverVersion.GitVersion, preconditions.MinKubeVersion))
+		}
+	}
+
+	return unmet, nil
+}
+
+func (e *preconditionEvaluator) exists(ctx context.Context, gvr schema.GroupVersionResource, name string) (bool, error) {
+	_, err := e.dynamicClient.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return true, nil
+}
+
+func buildBlockedCondition(generation int64, unmet []string) metav1.Condition {
+	if len(unmet) == 0 {
+		return metav1.Condition{
+			Type:               WorkBlocked,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             "PreconditionsMet",
+			Message:            "All preconditions are met",
+		}
+	}
+
+	return metav1.Condition{
+		Type:               WorkBlocked,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             "PreconditionsNotMet",
+		Message:            fmt.Sprintf("Preconditions are not met: %s", strings.Join(unmet, "; ")),
+	}
+}
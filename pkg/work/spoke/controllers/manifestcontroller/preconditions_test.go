@@ -0,0 +1,145 @@
+package manifestcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/work/spoke/spoketesting"
+)
+
+func newClusterClaim(name, value string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cluster.open-cluster-management.io/v1alpha1",
+		"kind":       "ClusterClaim",
+		"metadata":   map[string]interface{}{"name": name},
+		"spec":       map[string]interface{}{"value": value},
+	}}
+}
+
+func newPreconditionEvaluator(kubeVersion string, objects ...runtime.Object) *preconditionEvaluator {
+	scheme := runtime.NewScheme()
+	dynamicClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(scheme, map[schema.GroupVersionResource]string{
+		clusterClaimGVR: "ClusterClaimList",
+		crdGVR:          "CustomResourceDefinitionList",
+		namespaceGVR:    "NamespaceList",
+	}, objects...)
+	discoveryClient := fakekube.NewSimpleClientset().Discovery().(*fakediscovery.FakeDiscovery)
+	discoveryClient.FakedServerVersion = &version.Info{GitVersion: kubeVersion}
+	return &preconditionEvaluator{dynamicClient: dynamicClient, discoveryClient: discoveryClient}
+}
+
+func TestEvaluatePreconditions(t *testing.T) {
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "foos.example.com"},
+	}}
+	ns := spoketesting.NewUnstructured("v1", "Namespace", "", "ns1")
+
+	cases := []struct {
+		name          string
+		preconditions *Preconditions
+		expectedUnmet int
+	}{
+		{
+			name: "all met",
+			preconditions: &Preconditions{
+				ClusterClaims:  []ClusterClaimPrecondition{{Name: "platform", Values: []string{"AWS"}}, {Name: "region"}},
+				CRDs:           []string{"foos.example.com"},
+				Namespaces:     []string{"ns1"},
+				MinKubeVersion: "v1.24.0",
+			},
+		},
+		{
+			name: "claim value mismatch",
+			preconditions: &Preconditions{
+				ClusterClaims: []ClusterClaimPrecondition{{Name: "platform", Values: []string{"GCP"}}},
+			},
+			expectedUnmet: 1,
+		},
+		{
+			name: "missing resources",
+			preconditions: &Preconditions{
+				ClusterClaims: []ClusterClaimPrecondition{{Name: "missing"}},
+				CRDs:          []string{"bars.example.com"},
+				Namespaces:    []string{"ns2"},
+			},
+			expectedUnmet: 3,
+		},
+		{
+			name:          "kube version too low",
+			preconditions: &Preconditions{MinKubeVersion: "v1.28.0"},
+			expectedUnmet: 1,
+		},
+		{
+			name:          "invalid kube version",
+			preconditions: &Preconditions{MinKubeVersion: "invalid"},
+			expectedUnmet: 1,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			evaluator := newPreconditionEvaluator("v1.27.2",
+				newClusterClaim("platform", "AWS"), newClusterClaim("region", "us-east-1"), crd, ns)
+			unmet, err := evaluator.evaluate(context.TODO(), c.preconditions)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(unmet) != c.expectedUnmet {
+				t.Errorf("expected %d unmet preconditions, but got %v", c.expectedUnmet, unmet)
+			}
+		})
+	}
+}
+
+func TestSyncBlockedByPreconditions(t *testing.T) {
+	work, workKey := spoketesting.NewManifestWork(0, spoketesting.NewUnstructured("v1", "Secret", "ns1", "test"))
+	work.Finalizers = []string{workapiv1.ManifestWorkFinalizer}
+	work.Annotations = map[string]string{PreconditionsAnnotationKey: `{"namespaces":["ns1"]}`}
+	controller := newController(t, work, nil, spoketesting.NewFakeRestMapper()).
+		withKubeObject().
+		withUnstructuredObject()
+	controller.controller.preconditions = newPreconditionEvaluator("v1.27.2")
+
+	syncContext := testingcommon.NewFakeSyncContext(t, workKey)
+	if err := controller.toController().sync(context.TODO(), syncContext); err != nil {
+		t.Errorf("expected no error, but got %v", err)
+	}
+
+	testingcommon.AssertNoActions(t, controller.kubeClient.Actions())
+	testingcommon.AssertNoActions(t, controller.dynamicClient.Actions())
+
+	var workActions []string
+	for _, action := range controller.workClient.Actions() {
+		workActions = append(workActions, action.GetResource().Resource+":"+action.GetVerb())
+	}
+	if len(workActions) != 1 || workActions[0] != "manifestworks:patch" {
+		t.Fatalf("expected only manifestwork status to be patched, but got %v", workActions)
+	}
+
+	patchedWork := &workapiv1.ManifestWork{}
+	if err := json.Unmarshal(controller.workClient.Actions()[0].(clienttesting.PatchActionImpl).Patch, patchedWork); err != nil {
+		t.Fatal(err)
+	}
+	if !meta.IsStatusConditionTrue(patchedWork.Status.Conditions, WorkBlocked) {
+		t.Errorf("expected work to be blocked, but got %v", patchedWork.Status.Conditions)
+	}
+	if meta.FindStatusCondition(patchedWork.Status.Conditions, workapiv1.WorkApplied) != nil {
+		t.Errorf("expected no applied condition, but got %v", patchedWork.Status.Conditions)
+	}
+}
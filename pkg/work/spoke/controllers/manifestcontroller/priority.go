@@ -0,0 +1,50 @@
+package manifestcontroller
+
+import (
+	"context"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/work/helper"
+)
+
+// prioritizedController runs the controllers of the Critical and the Normal priority classes, each of them has its
+// own queue and workers, so the critical works are not queued behind the others.
+type prioritizedController struct {
+	critical factory.Controller
+	normal   factory.Controller
+}
+
+func (c *prioritizedController) Run(ctx context.Context, workers int) {
+	go c.critical.Run(ctx, workers)
+	c.normal.Run(ctx, workers)
+}
+
+func (c *prioritizedController) Sync(ctx context.Context, controllerContext factory.SyncContext) error {
+	return c.normal.Sync(ctx, controllerContext)
+}
+
+func (c *prioritizedController) Name() string {
+	return c.normal.Name()
+}
+
+// priorityClassFilter filters the manifestworks, and the appliedmanifestworks of the manifestworks, handled by the
+// controller of the priority class.
+func (m *ManifestWorkController) priorityClassFilter(critical bool) factory.EventFilterFunc {
+	return func(obj interface{}) bool {
+		switch o := obj.(type) {
+		case *workapiv1.ManifestWork:
+			return helper.IsCriticalManifestWork(o) == critical
+		case *workapiv1.AppliedManifestWork:
+			work, err := m.manifestWorkLister.Get(o.Spec.ManifestWorkName)
+			if err != nil {
+				// the work is not found, let the Normal controller handle it
+				return !critical
+			}
+			return helper.IsCriticalManifestWork(work) == critical
+		}
+		return !critical
+	}
+}
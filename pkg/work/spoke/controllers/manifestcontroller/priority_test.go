@@ -0,0 +1,84 @@
+package manifestcontroller
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/work/helper"
+	"open-cluster-management.io/ocm/pkg/work/spoke/spoketesting"
+)
+
+func TestPriorityClassFilter(t *testing.T) {
+	criticalWork, _ := spoketesting.NewManifestWork(0)
+	criticalWork.Annotations = map[string]string{helper.PriorityClassAnnotationKey: helper.PriorityClassCritical}
+	normalWork, _ := spoketesting.NewManifestWork(1)
+
+	cases := []struct {
+		name             string
+		obj              interface{}
+		expectedCritical bool
+	}{
+		{
+			name:             "critical work",
+			obj:              criticalWork,
+			expectedCritical: true,
+		},
+		{
+			name: "normal work",
+			obj:  normalWork,
+		},
+		{
+			name: "appliedmanifestwork of critical work",
+			obj: &workapiv1.AppliedManifestWork{
+				ObjectMeta: metav1.ObjectMeta{Name: "hubhash-" + criticalWork.Name},
+				Spec:       workapiv1.AppliedManifestWorkSpec{ManifestWorkName: criticalWork.Name},
+			},
+			expectedCritical: true,
+		},
+		{
+			name: "appliedmanifestwork of missing work",
+			obj: &workapiv1.AppliedManifestWork{
+				ObjectMeta: metav1.ObjectMeta{Name: "hubhash-missing"},
+				Spec:       workapiv1.AppliedManifestWorkSpec{ManifestWorkName: "missing"},
+			},
+		},
+	}
+
+	controller := newController(t, criticalWork, nil, spoketesting.NewFakeRestMapper()).toController()
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if actual := controller.priorityClassFilter(true)(c.obj); actual != c.expectedCritical {
+				t.Errorf("expected critical %v, but got %v", c.expectedCritical, actual)
+			}
+			if actual := controller.priorityClassFilter(false)(c.obj); actual == c.expectedCritical {
+				t.Errorf("expected normal %v, but got %v", !c.expectedCritical, actual)
+			}
+		})
+	}
+}
+
+func TestSyncByPriorityClass(t *testing.T) {
+	work, workKey := spoketesting.NewManifestWork(0, spoketesting.NewUnstructured("v1", "Secret", "ns1", "test"))
+	work.Finalizers = []string{workapiv1.ManifestWorkFinalizer}
+	work.Annotations = map[string]string{helper.PriorityClassAnnotationKey: helper.PriorityClassCritical}
+
+	// the critical work is skipped by the controller of the Normal priority class
+	controller := newController(t, work, nil, spoketesting.NewFakeRestMapper()).
+		withKubeObject().
+		withUnstructuredObject()
+	if err := controller.toController().sync(context.TODO(), testingcommon.NewFakeSyncContext(t, workKey)); err != nil {
+		t.Fatal(err)
+	}
+	testingcommon.AssertNoActions(t, controller.workClient.Actions())
+
+	controller.controller.critical = true
+	if err := controller.toController().sync(context.TODO(), testingcommon.NewFakeSyncContext(t, workKey)); err != nil {
+		t.Fatal(err)
+	}
+	testingcommon.AssertActions(t, controller.workClient.Actions(), "create", "patch")
+}
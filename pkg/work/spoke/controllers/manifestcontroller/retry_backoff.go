@@ -0,0 +1,161 @@
+package manifestcontroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+const (
+	// RetryBackoffAnnotationKey is the annotation on the ManifestWork to configure the retry backoff of the failing
+	// manifests, the value is a json string, e.g. {"initial":"10s","max":"10m","multiplier":2}
+	RetryBackoffAnnotationKey = "work.open-cluster-management.io/retry-backoff"
+
+	// ManifestRetryBackoff is the manifest condition type which records the consecutive failure count and the next
+	// retry time of a failing manifest. It is only set when the retry backoff is configured on the ManifestWork.
+	ManifestRetryBackoff = "RetryBackoff"
+
+	defaultRetryBackoffInitial    = 10 * time.Second
+	defaultRetryBackoffMax        = 5 * time.Minute
+	defaultRetryBackoffMultiplier = 2.0
+)
+
+// RetryBackoff is the retry backoff policy of the failing manifests in a ManifestWork.
+type RetryBackoff struct {
+	Initial    metav1.Duration `json:"initial,omitempty"`
+	Max        metav1.Duration `json:"max,omitempty"`
+	Multiplier float64         `json:"multiplier,omitempty"`
+}
+
+// getRetryBackoff returns the retry backoff configured on the manifestwork with default values set,
+// nil is returned if the retry backoff is not configured or it is invalid.
+func getRetryBackoff(work *workapiv1.ManifestWork) *RetryBackoff {
+	data, ok := work.Annotations[RetryBackoffAnnotationKey]
+	if !ok {
+		return nil
+	}
+
+	backoff := &RetryBackoff{}
+	if err := json.Unmarshal([]byte(data), backoff); err != nil {
+		klog.Warningf("Ignore the invalid annotation %s of manifestwork %s/%s: %v",
+			RetryBackoffAnnotationKey, work.Namespace, work.Name, err)
+		return nil
+	}
+	if backoff.Initial.Duration <= 0 {
+		backoff.Initial.Duration = defaultRetryBackoffInitial
+	}
+	if backoff.Max.Duration <= 0 {
+		backoff.Max.Duration = defaultRetryBackoffMax
+	}
+	if backoff.Multiplier < 1 {
+		backoff.Multiplier = defaultRetryBackoffMultiplier
+	}
+	return backoff
+}
+
+// delay returns the time to wait before the next retry after the given number of consecutive failures.
+func (b *RetryBackoff) delay(failures int) time.Duration {
+	d := float64(b.Initial.Duration) * math.Pow(b.Multiplier, float64(failures-1))
+	if d > float64(b.Max.Duration) {
+		return b.Max.Duration
+	}
+	return time.Duration(d)
+}
+
+type manifestFailure struct {
+	failures  int
+	nextRetry time.Time
+	lastError error
+}
+
+// manifestBackoffTracker tracks the consecutive apply failures of the manifests. It is kept in memory, so the
+// failures are counted from zero again after the agent restarts.
+type manifestBackoffTracker struct {
+	sync.Mutex
+	failures map[string]*manifestFailure
+	now      func() time.Time
+}
+
+func newManifestBackoffTracker() *manifestBackoffTracker {
+	return &manifestBackoffTracker{
+		failures: map[string]*manifestFailure{},
+		now:      time.Now,
+	}
+}
+
+func manifestBackoffKey(workName string, resourceMeta workapiv1.ManifestResourceMeta) string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s/%s", workName,
+		resourceMeta.Group, resourceMeta.Resource, resourceMeta.Namespace, resourceMeta.Name, resourceMeta.Kind)
+}
+
+// waiting returns the recorded failure if the manifest is still in its backoff period.
+func (t *manifestBackoffTracker) waiting(key string) (*manifestFailure, bool) {
+	t.Lock()
+	defer t.Unlock()
+	failure, ok := t.failures[key]
+	if !ok || !t.now().Before(failure.nextRetry) {
+		return nil, false
+	}
+	copied := *failure
+	return &copied, true
+}
+
+// failed records a failure of the manifest and returns the updated failure record.
+func (t *manifestBackoffTracker) failed(key string, backoff *RetryBackoff, err error) manifestFailure {
+	t.Lock()
+	defer t.Unlock()
+	failure, ok := t.failures[key]
+	if !ok {
+		failure = &manifestFailure{}
+		t.failures[key] = failure
+	}
+	failure.failures++
+	failure.nextRetry = t.now().Add(backoff.delay(failure.failures))
+	failure.lastError = err
+	return *failure
+}
+
+// succeeded clears the failure record of the manifest.
+func (t *manifestBackoffTracker) succeeded(key string) {
+	t.Lock()
+	defer t.Unlock()
+	delete(t.failures, key)
+}
+
+// forget clears all the failure records of the manifestwork.
+func (t *manifestBackoffTracker) forget(workName string) {
+	t.Lock()
+	defer t.Unlock()
+	for key := range t.failures {
+		if strings.HasPrefix(key, workName+"/") {
+			delete(t.failures, key)
+		}
+	}
+}
+
+func buildRetryBackoffCondition(failure *manifestFailure) metav1.Condition {
+	if failure == nil {
+		return metav1.Condition{
+			Type:    ManifestRetryBackoff,
+			Status:  metav1.ConditionFalse,
+			Reason:  "NoBackoff",
+			Message: "The manifest is applied without failure",
+		}
+	}
+
+	return metav1.Condition{
+		Type:   ManifestRetryBackoff,
+		Status: metav1.ConditionTrue,
+		Reason: "RetryBackoff",
+		Message: fmt.Sprintf("Failed to apply the manifest %d consecutive times, next retry at %s",
+			failure.failures, failure.nextRetry.UTC().Format(time.RFC3339)),
+	}
+}
@@ -0,0 +1,171 @@
+package manifestcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/work/spoke/spoketesting"
+)
+
+func TestGetRetryBackoff(t *testing.T) {
+	cases := []struct {
+		name       string
+		annotation string
+		expected   *RetryBackoff
+	}{
+		{
+			name:     "no annotation",
+			expected: nil,
+		},
+		{
+			name:       "invalid annotation",
+			annotation: "invalid",
+			expected:   nil,
+		},
+		{
+			name:       "default values",
+			annotation: "{}",
+			expected: &RetryBackoff{
+				Initial:    metav1.Duration{Duration: defaultRetryBackoffInitial},
+				Max:        metav1.Duration{Duration: defaultRetryBackoffMax},
+				Multiplier: defaultRetryBackoffMultiplier,
+			},
+		},
+		{
+			name:       "configured values",
+			annotation: `{"initial":"1s","max":"1m","multiplier":3}`,
+			expected: &RetryBackoff{
+				Initial:    metav1.Duration{Duration: time.Second},
+				Max:        metav1.Duration{Duration: time.Minute},
+				Multiplier: 3,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			work, _ := spoketesting.NewManifestWork(0)
+			if len(c.annotation) > 0 {
+				work.Annotations = map[string]string{RetryBackoffAnnotationKey: c.annotation}
+			}
+			actual := getRetryBackoff(work)
+			if c.expected == nil && actual == nil {
+				return
+			}
+			if c.expected == nil || actual == nil || *c.expected != *actual {
+				t.Errorf("expected %v, but got %v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRetryBackoffDelay(t *testing.T) {
+	backoff := &RetryBackoff{
+		Initial:    metav1.Duration{Duration: time.Second},
+		Max:        metav1.Duration{Duration: 10 * time.Second},
+		Multiplier: 2,
+	}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second}
+	for i, e := range expected {
+		if actual := backoff.delay(i + 1); actual != e {
+			t.Errorf("expected delay %v after %d failures, but got %v", e, i+1, actual)
+		}
+	}
+}
+
+func TestManifestBackoffTracker(t *testing.T) {
+	now := time.Now()
+	tracker := newManifestBackoffTracker()
+	tracker.now = func() time.Time { return now }
+	backoff := &RetryBackoff{
+		Initial:    metav1.Duration{Duration: time.Second},
+		Max:        metav1.Duration{Duration: time.Minute},
+		Multiplier: 2,
+	}
+	key := manifestBackoffKey("work", workapiv1.ManifestResourceMeta{Resource: "secrets", Namespace: "ns1", Name: "test"})
+
+	if _, waiting := tracker.waiting(key); waiting {
+		t.Errorf("expected not waiting without failures")
+	}
+
+	tracker.failed(key, backoff, fmt.Errorf("failed"))
+	failure := tracker.failed(key, backoff, fmt.Errorf("failed"))
+	if failure.failures != 2 || !failure.nextRetry.Equal(now.Add(2*time.Second)) {
+		t.Errorf("unexpected failure %v", failure)
+	}
+	if _, waiting := tracker.waiting(key); !waiting {
+		t.Errorf("expected waiting for the next retry")
+	}
+
+	tracker.now = func() time.Time { return now.Add(2 * time.Second) }
+	if _, waiting := tracker.waiting(key); waiting {
+		t.Errorf("expected not waiting after the next retry time")
+	}
+
+	tracker.forget("work")
+	if len(tracker.failures) != 0 {
+		t.Errorf("expected failures to be forgot, but got %v", tracker.failures)
+	}
+}
+
+func TestSyncWithRetryBackoff(t *testing.T) {
+	work, workKey := spoketesting.NewManifestWork(0, spoketesting.NewUnstructuredWithContent(
+		"v1", "NewObject", "ns1", "n1",
+		map[string]interface{}{"spec": map[string]interface{}{"key1": "val1"}}))
+	work.Finalizers = []string{workapiv1.ManifestWorkFinalizer}
+	work.Annotations = map[string]string{RetryBackoffAnnotationKey: `{"initial":"1h"}`}
+	appliedWork := spoketesting.NewAppliedManifestWork("", 0, "appliedwork-uid")
+	controller := newController(t, work, appliedWork, spoketesting.NewFakeRestMapper()).
+		withKubeObject().
+		withUnstructuredObject()
+	if err := controller.workClient.Tracker().Add(appliedWork); err != nil {
+		t.Fatal(err)
+	}
+	controller.dynamicClient.PrependReactor("create", "newobjects",
+		func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, nil, fmt.Errorf("create failed")
+		})
+
+	ctrl := controller.toController()
+	syncContext := testingcommon.NewFakeSyncContext(t, workKey)
+	if err := ctrl.sync(context.TODO(), syncContext); err != nil {
+		t.Errorf("expected no error, but got %v", err)
+	}
+	testingcommon.AssertActions(t, controller.dynamicClient.Actions(), "get", "create")
+
+	var patchedWork *workapiv1.ManifestWork
+	for _, action := range controller.workClient.Actions() {
+		if action.GetResource().Resource == "manifestworks" && action.GetVerb() == "patch" {
+			patchedWork = &workapiv1.ManifestWork{}
+			if err := json.Unmarshal(action.(clienttesting.PatchActionImpl).Patch, patchedWork); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if patchedWork == nil {
+		t.Fatalf("expected work status to be patched")
+	}
+	cond := meta.FindStatusCondition(patchedWork.Status.ResourceStatus.Manifests[0].Conditions, ManifestRetryBackoff)
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		t.Errorf("expected retry backoff condition, but got %v", patchedWork.Status.ResourceStatus.Manifests[0].Conditions)
+	}
+
+	// the manifest should not be applied again before the next retry time
+	controller.dynamicClient.ClearActions()
+	if err := ctrl.sync(context.TODO(), syncContext); err != nil {
+		t.Errorf("expected no error, but got %v", err)
+	}
+	testingcommon.AssertNoActions(t, controller.dynamicClient.Actions())
+}
@@ -0,0 +1,150 @@
+package manifestcontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/work/helper"
+)
+
+const (
+	// ApplyTimeoutAnnotationKey is the annotation on the ManifestWork to override the timeout of applying each of
+	// its manifests, e.g. "30s". The manifests are applied without timeout if it is 0.
+	ApplyTimeoutAnnotationKey = "work.open-cluster-management.io/apply-timeout"
+
+	// SyncDeadlineAnnotationKey is the annotation on the ManifestWork to override the deadline of applying all its
+	// manifests in one reconcile, e.g. "2m". The manifests not applied before the deadline are retried later, and
+	// the work is Degraded. There is no deadline if it is 0.
+	SyncDeadlineAnnotationKey = "work.open-cluster-management.io/sync-deadline"
+
+	// ReasonSyncDeadlineExceeded is the reason of the Degraded condition of the work if it is not synced before the
+	// sync deadline.
+	ReasonSyncDeadlineExceeded = "SyncDeadlineExceeded"
+
+	// slowestManifestsLimit is the number of the slowest manifests listed in the Degraded condition.
+	slowestManifestsLimit = 3
+)
+
+// SyncDeadlineExceededError is the error of the manifest which is not applied since the sync deadline of the work
+// is exceeded.
+type SyncDeadlineExceededError struct {
+	deadline time.Duration
+}
+
+func (e *SyncDeadlineExceededError) Error() string {
+	return fmt.Sprintf("the manifest is not applied since the sync deadline %v of the work is exceeded", e.deadline)
+}
+
+// getWorkDuration returns the duration in the annotation of the manifestwork, the default duration is returned if
+// the annotation is not set or it is invalid.
+func getWorkDuration(work *workapiv1.ManifestWork, key string, defaultDuration time.Duration) time.Duration {
+	value, ok := work.Annotations[key]
+	if !ok {
+		return defaultDuration
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		klog.Warningf("Ignore the invalid annotation %s of manifestwork %s/%s: %q", key, work.Namespace, work.Name, value)
+		return defaultDuration
+	}
+	return duration
+}
+
+// applyWithDeadline applies the manifest within the apply timeout and the sync deadline of the work, and records
+// how long the apply takes. The manifest is not applied at all if the sync deadline is exceeded already.
+func (m *ManifestWorkController) applyWithDeadline(
+	ctx context.Context,
+	index int,
+	manifest workapiv1.Manifest,
+	applyCtx *workApplyContext,
+	recorder events.Recorder) applyResult {
+	if !applyCtx.deadline.IsZero() {
+		if !time.Now().Before(applyCtx.deadline) {
+			return syncDeadlineExceededResult(index, manifest, applyCtx.syncDeadline)
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, applyCtx.deadline)
+		defer cancel()
+	}
+	if applyCtx.applyTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, applyCtx.applyTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	result := m.applyOneManifest(ctx, index, manifest, applyCtx, recorder)
+	result.duration = time.Since(start)
+	return result
+}
+
+func syncDeadlineExceededResult(index int, manifest workapiv1.Manifest, deadline time.Duration) applyResult {
+	result := applyResult{Error: &SyncDeadlineExceededError{deadline: deadline}}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(manifest.Raw); err == nil {
+		result.resourceMeta, _, _ = helper.BuildResourceMeta(index, obj, nil)
+	}
+	return result
+}
+
+// setSyncDeadlineCondition sets the Degraded condition of the work with the slowest manifests if the sync deadline
+// is exceeded, and removes the condition once the work is synced in time.
+func setSyncDeadlineCondition(work *workapiv1.ManifestWork, applyCtx *workApplyContext, results []applyResult) {
+	if applyCtx.deadline.IsZero() || time.Now().Before(applyCtx.deadline) {
+		if condition := meta.FindStatusCondition(work.Status.Conditions, workapiv1.WorkDegraded); condition != nil &&
+			condition.Reason == ReasonSyncDeadlineExceeded {
+			meta.RemoveStatusCondition(&work.Status.Conditions, workapiv1.WorkDegraded)
+		}
+		return
+	}
+
+	slowest := make([]applyResult, 0, len(results))
+	for _, result := range results {
+		if result.duration > 0 {
+			slowest = append(slowest, result)
+		}
+	}
+	sort.SliceStable(slowest, func(i, j int) bool {
+		return slowest[i].duration > slowest[j].duration
+	})
+	if len(slowest) > slowestManifestsLimit {
+		slowest = slowest[:slowestManifestsLimit]
+	}
+	var manifests []string
+	for _, result := range slowest {
+		manifests = append(manifests, fmt.Sprintf("%s (%v)",
+			formatResourceMeta(result.resourceMeta), result.duration.Round(time.Millisecond)))
+	}
+
+	message := fmt.Sprintf("The manifests are not applied within the sync deadline %v", applyCtx.syncDeadline)
+	if len(manifests) > 0 {
+		message = fmt.Sprintf("%s, the slowest manifests: %s", message, strings.Join(manifests, "; "))
+	}
+	meta.SetStatusCondition(&work.Status.Conditions, metav1.Condition{
+		Type:               workapiv1.WorkDegraded,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: work.Generation,
+		Reason:             ReasonSyncDeadlineExceeded,
+		Message:            message,
+	})
+}
+
+func formatResourceMeta(resourceMeta workapiv1.ManifestResourceMeta) string {
+	name := resourceMeta.Name
+	if len(resourceMeta.Namespace) > 0 {
+		name = fmt.Sprintf("%s/%s", resourceMeta.Namespace, name)
+	}
+	return fmt.Sprintf("%s %s", schema.GroupKind{Group: resourceMeta.Group, Kind: resourceMeta.Kind}.String(), name)
+}
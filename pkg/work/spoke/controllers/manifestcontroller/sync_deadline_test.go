@@ -0,0 +1,135 @@
+package manifestcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clienttesting "k8s.io/client-go/testing"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/work/spoke/spoketesting"
+)
+
+func TestGetWorkDuration(t *testing.T) {
+	cases := []struct {
+		name       string
+		annotation string
+		expected   time.Duration
+	}{
+		{
+			name:     "no annotation",
+			expected: time.Minute,
+		},
+		{
+			name:       "invalid annotation",
+			annotation: "invalid",
+			expected:   time.Minute,
+		},
+		{
+			name:       "negative duration",
+			annotation: "-10s",
+			expected:   time.Minute,
+		},
+		{
+			name:       "zero duration",
+			annotation: "0",
+			expected:   0,
+		},
+		{
+			name:       "configured duration",
+			annotation: "30s",
+			expected:   30 * time.Second,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			work, _ := spoketesting.NewManifestWork(0)
+			if len(c.annotation) > 0 {
+				work.Annotations = map[string]string{SyncDeadlineAnnotationKey: c.annotation}
+			}
+			if actual := getWorkDuration(work, SyncDeadlineAnnotationKey, time.Minute); actual != c.expected {
+				t.Errorf("expected %v, but got %v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSyncDeadlineExceeded(t *testing.T) {
+	work, workKey := spoketesting.NewManifestWork(0, spoketesting.NewUnstructured("v1", "Secret", "ns1", "test"))
+	work.Finalizers = []string{workapiv1.ManifestWorkFinalizer}
+	work.Annotations = map[string]string{SyncDeadlineAnnotationKey: "1ns"}
+	controller := newController(t, work, nil, spoketesting.NewFakeRestMapper()).
+		withKubeObject().
+		withUnstructuredObject()
+
+	syncContext := testingcommon.NewFakeSyncContext(t, workKey)
+	if err := controller.toController().sync(context.TODO(), syncContext); err == nil {
+		t.Errorf("expected error, but got nil")
+	}
+
+	testingcommon.AssertNoActions(t, controller.dynamicClient.Actions())
+	testingcommon.AssertActions(t, controller.workClient.Actions(), "create", "patch")
+	patchedWork := &workapiv1.ManifestWork{}
+	if err := json.Unmarshal(controller.workClient.Actions()[1].(clienttesting.PatchActionImpl).Patch, patchedWork); err != nil {
+		t.Fatal(err)
+	}
+	testingcommon.AssertCondition(t, patchedWork.Status.Conditions, metav1.Condition{
+		Type:    workapiv1.WorkDegraded,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonSyncDeadlineExceeded,
+		Message: "The manifests are not applied within the sync deadline 1ns",
+	})
+}
+
+func TestSetSyncDeadlineCondition(t *testing.T) {
+	results := []applyResult{
+		{resourceMeta: workapiv1.ManifestResourceMeta{Kind: "Secret", Namespace: "ns1", Name: "s1"}, duration: time.Second},
+		{resourceMeta: workapiv1.ManifestResourceMeta{Kind: "Secret", Namespace: "ns1", Name: "s2"}},
+		{resourceMeta: workapiv1.ManifestResourceMeta{Group: "apps", Kind: "Deployment", Namespace: "ns1", Name: "d1"},
+			duration: 3 * time.Second},
+		{resourceMeta: workapiv1.ManifestResourceMeta{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "r1"},
+			duration: 2 * time.Second},
+		{resourceMeta: workapiv1.ManifestResourceMeta{Kind: "ConfigMap", Namespace: "ns1", Name: "c1"},
+			duration: time.Millisecond},
+	}
+
+	work, _ := spoketesting.NewManifestWork(0)
+	setSyncDeadlineCondition(work, &workApplyContext{
+		syncDeadline: time.Minute,
+		deadline:     time.Now().Add(-time.Second),
+	}, results)
+	testingcommon.AssertCondition(t, work.Status.Conditions, metav1.Condition{
+		Type:   workapiv1.WorkDegraded,
+		Status: metav1.ConditionTrue,
+		Reason: ReasonSyncDeadlineExceeded,
+		Message: "The manifests are not applied within the sync deadline 1m0s, the slowest manifests: " +
+			"Deployment.apps ns1/d1 (3s); ClusterRole.rbac.authorization.k8s.io r1 (2s); Secret ns1/s1 (1s)",
+	})
+
+	// the condition is removed once the work is synced before the deadline
+	setSyncDeadlineCondition(work, &workApplyContext{
+		syncDeadline: time.Minute,
+		deadline:     time.Now().Add(time.Minute),
+	}, results)
+	if meta.FindStatusCondition(work.Status.Conditions, workapiv1.WorkDegraded) != nil {
+		t.Errorf("expected the degraded condition is removed, but got %v", work.Status.Conditions)
+	}
+
+	// the degraded condition set by others is kept
+	meta.SetStatusCondition(&work.Status.Conditions, metav1.Condition{
+		Type:   workapiv1.WorkDegraded,
+		Status: metav1.ConditionTrue,
+		Reason: "Others",
+	})
+	setSyncDeadlineCondition(work, &workApplyContext{}, results)
+	if meta.FindStatusCondition(work.Status.Conditions, workapiv1.WorkDegraded) == nil {
+		t.Errorf("expected the degraded condition is kept, but got %v", work.Status.Conditions)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	worklister "open-cluster-management.io/api/client/work/listers/work/v1"
 	workapiv1 "open-cluster-management.io/api/work/v1"
 
+	"open-cluster-management.io/ocm/pkg/common/logging"
 	"open-cluster-management.io/ocm/pkg/common/patcher"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/work/helper"
@@ -50,11 +51,16 @@ func NewAvailableStatusController(
 	manifestWorkInformer workinformer.ManifestWorkInformer,
 	manifestWorkLister worklister.ManifestWorkNamespaceLister,
 	syncInterval time.Duration,
+	statusPatchCoalesceWindow time.Duration,
 ) factory.Controller {
 	controller := &AvailableStatusController{
+		// the status is synced periodically, coalesce the updates of the status in the window to reduce the
+		// patches to the hub.
 		patcher: patcher.NewPatcher[
 			*workapiv1.ManifestWork, workapiv1.ManifestWorkSpec, workapiv1.ManifestWorkStatus](
-			manifestWorkClient),
+			manifestWorkClient).WithOptions(patcher.PatchOptions{
+			Coalescer: patcher.NewCoalescer(statusPatchCoalesceWindow),
+		}),
 		manifestWorkLister: manifestWorkLister,
 		spokeDynamicClient: spokeDynamicClient,
 		statusReader:       statusfeedback.NewStatusReader(),
@@ -62,7 +68,8 @@ func NewAvailableStatusController(
 
 	return factory.New().
 		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, manifestWorkInformer.Informer()).
-		WithSync(controller.sync).ResyncEvery(syncInterval).ToController("AvailableStatusController", recorder)
+		WithSync(logging.WithControllerLogger("AvailableStatusController", controller.sync)).
+		ResyncEvery(syncInterval).ToController("AvailableStatusController", recorder)
 }
 
 func (c *AvailableStatusController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
@@ -86,7 +93,7 @@ func (c *AvailableStatusController) sync(ctx context.Context, controllerContext
 	}
 
 	// resync all manifestworks
-	klog.V(4).Infof("Resync all ManifestWorks by adding them to the queue")
+	klog.FromContext(ctx).V(4).Info("Resync all ManifestWorks by adding them to the queue")
 	manifestWorks, err := c.manifestWorkLister.List(labels.Everything())
 	if err != nil {
 		return fmt.Errorf("unable to list manifestworks: %w", err)
@@ -100,7 +107,8 @@ func (c *AvailableStatusController) sync(ctx context.Context, controllerContext
 }
 
 func (c *AvailableStatusController) syncManifestWork(ctx context.Context, originalManifestWork *workapiv1.ManifestWork) error {
-	klog.V(4).Infof("Reconciling ManifestWork %q", originalManifestWork.Name)
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling ManifestWork", "manifestWork", originalManifestWork.Name)
 	manifestWork := originalManifestWork.DeepCopy()
 
 	// do nothing when finalizer is not added.
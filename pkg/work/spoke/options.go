@@ -4,12 +4,26 @@ import (
 	"time"
 
 	"github.com/spf13/pflag"
+
+	"open-cluster-management.io/ocm/pkg/work/spoke/controllers/finalizercontroller"
+	"open-cluster-management.io/ocm/pkg/work/spoke/controllers/manifestcontroller"
 )
 
 // WorkloadAgentOptions defines the flags for workload agent
 type WorkloadAgentOptions struct {
 	StatusSyncInterval                     time.Duration
 	AppliedManifestWorkEvictionGracePeriod time.Duration
+	SkipUnchangedManifests                 bool
+	AdoptionLabelKey                       string
+	ResumeCacheDir                         string
+	StatusPatchCoalesceWindow              time.Duration
+	AllowedResources                       []string
+	DeniedResources                        []string
+	ManifestApplyTimeout                   time.Duration
+	WorkSyncDeadline                       time.Duration
+	StaleAppliedManifestWorkPolicy         string
+	PreviousAgentID                        string
+	ExternalAppliersConfig                 string
 }
 
 // NewWorkloadAgentOptions returns the flags with default value set
@@ -17,6 +31,8 @@ func NewWorkloadAgentOptions() *WorkloadAgentOptions {
 	return &WorkloadAgentOptions{
 		StatusSyncInterval:                     10 * time.Second,
 		AppliedManifestWorkEvictionGracePeriod: 60 * time.Minute,
+		AdoptionLabelKey:                       manifestcontroller.DefaultAdoptionLabelKey,
+		StaleAppliedManifestWorkPolicy:         string(finalizercontroller.StaleAppliedWorkPolicyEvict),
 	}
 }
 
@@ -25,4 +41,41 @@ func (o *WorkloadAgentOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&o.StatusSyncInterval, "status-sync-interval", o.StatusSyncInterval, "Interval to sync resource status to hub.")
 	fs.DurationVar(&o.AppliedManifestWorkEvictionGracePeriod, "appliedmanifestwork-eviction-grace-period",
 		o.AppliedManifestWorkEvictionGracePeriod, "Grace period for appliedmanifestwork eviction")
+	fs.BoolVar(&o.SkipUnchangedManifests, "skip-unchanged-manifests", o.SkipUnchangedManifests,
+		"Skip applying a manifest if neither the manifest nor the applied resource changed since the last successful apply.")
+	fs.StringVar(&o.AdoptionLabelKey, "adoption-label-key", o.AdoptionLabelKey,
+		"The label with value \"true\" marking an existing resource adoptable by works with the Labeled adoption policy.")
+	fs.StringVar(&o.ResumeCacheDir, "resume-cache-dir", o.ResumeCacheDir,
+		"The local directory to cache the manifestworks with their status feedback and appliedmanifestworks, so "+
+			"the agent could resume from the cache after restart if the hub is not reachable, without getting all "+
+			"the applied resources again. The cache is disabled if it is empty.")
+	fs.DurationVar(&o.StatusPatchCoalesceWindow, "status-patch-coalesce-window", o.StatusPatchCoalesceWindow,
+		"The window to coalesce the status updates of a manifestwork into one patch to the hub, the status "+
+			"updates are not coalesced if it is zero.")
+	fs.StringSliceVar(&o.AllowedResources, "allowed-resources", o.AllowedResources,
+		"The kinds of the resources the works are allowed to apply on the cluster, in the format of <Kind>.<group>, "+
+			"e.g. Deployment.apps, * matches any kind or group. All the resources are allowed if it is empty.")
+	fs.StringSliceVar(&o.DeniedResources, "denied-resources", o.DeniedResources,
+		"The kinds of the resources the works are not allowed to apply on the cluster, in the format of <Kind>.<group>, "+
+			"e.g. Secret or *.rbac.authorization.k8s.io. A work with any denied resource is blocked.")
+	fs.DurationVar(&o.ManifestApplyTimeout, "manifest-apply-timeout", o.ManifestApplyTimeout,
+		"The timeout of applying a manifest of the works, it is overridden by the annotation "+
+			"work.open-cluster-management.io/apply-timeout of the work. There is no timeout if it is zero.")
+	fs.DurationVar(&o.WorkSyncDeadline, "work-sync-deadline", o.WorkSyncDeadline,
+		"The deadline of applying all the manifests of a work, the work is Degraded with the slowest manifests "+
+			"if it is exceeded. It is overridden by the annotation work.open-cluster-management.io/sync-deadline of "+
+			"the work. There is no deadline if it is zero.")
+	fs.StringVar(&o.StaleAppliedManifestWorkPolicy, "stale-appliedmanifestwork-policy", o.StaleAppliedManifestWorkPolicy,
+		"The policy to handle the appliedmanifestworks of a previous hub hash, e.g. after the cluster is re-registered "+
+			"to a restored hub. Evict removes the ones of this agent with their resources after the eviction grace "+
+			"period, Adopt hands the resources of the ones of this agent or of the previous agent ID over to the works "+
+			"of the current hub with the same names. The ones of other agents are never removed.")
+	fs.StringVar(&o.PreviousAgentID, "previous-agent-id", o.PreviousAgentID,
+		"The agent ID of the work agent before the cluster is re-registered, which is the hash of the previous hub if "+
+			"the agent ID was not set. The appliedmanifestworks of it are adopted with the Adopt stale "+
+			"appliedmanifestwork policy, in addition to the ones of the current agent ID.")
+	fs.StringVar(&o.ExternalAppliersConfig, "external-appliers-config", o.ExternalAppliersConfig,
+		"The yaml file listing the external appliers of the kinds not served by the apiserver of the cluster, e.g. "+
+			"the configs of network devices. Each item has the group and kind, and the command run with the args, the "+
+			"operation apply or delete and the manifest on the stdin, with an optional timeout.")
 }
@@ -0,0 +1,305 @@
+package resumecache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	workinformer "open-cluster-management.io/api/client/work/informers/externalversions/work/v1"
+	worklister "open-cluster-management.io/api/client/work/listers/work/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/work/helper"
+)
+
+const fileSuffix = ".json"
+
+// Record is the state of a manifestwork persisted in the cache.
+type Record struct {
+	// ManifestWork is the last known manifestwork from the hub. Its status carries the manifest conditions and
+	// the status feedback reported by the agent.
+	ManifestWork *workapiv1.ManifestWork `json:"manifestWork"`
+	// AppliedManifestWork is the last known appliedmanifestwork of the manifestwork with the applied resources.
+	AppliedManifestWork *workapiv1.AppliedManifestWork `json:"appliedManifestWork,omitempty"`
+}
+
+// Cache persists the state of the manifestworks in a local directory, e.g. an emptyDir or a PVC, so that the
+// work agent could resume from the cached state after it restarts, even if the hub is not reachable, without
+// getting all the applied resources again.
+//
+// The informer event handlers only mark the manifestworks changed, the records are written in batches by Run.
+type Cache struct {
+	dir       string
+	namespace string
+	hubHash   string
+
+	// the lock protects the dirty and resumed, the files are only written by Run.
+	sync.Mutex
+	dirty sets.Set[string]
+	// resumed are the records loaded when the cache is created, each of them is consumed by Resumed once.
+	resumed map[string]Record
+
+	workLister    worklister.ManifestWorkNamespaceLister
+	appliedLister worklister.AppliedManifestWorkLister
+}
+
+// NewCache returns a Cache persisting the state of the manifestworks of the cluster namespace in the given
+// directory, and loads the records persisted before.
+func NewCache(dir, namespace, hubHash string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create resume cache dir %s: %w", dir, err)
+	}
+	c := &Cache{
+		dir:       dir,
+		namespace: namespace,
+		hubHash:   hubHash,
+		dirty:     sets.New[string](),
+		resumed:   map[string]Record{},
+	}
+	records, err := c.list()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		c.resumed[record.ManifestWork.Name] = record
+	}
+	return c, nil
+}
+
+// Resumed returns true if neither the manifestwork nor its appliedmanifestwork changed since they were
+// persisted before the agent restarted, so the applied resources recorded in the appliedmanifestwork are still
+// up to date. It returns true at most once for each manifestwork, and always false on a nil Cache.
+func (c *Cache) Resumed(work *workapiv1.ManifestWork, appliedWork *workapiv1.AppliedManifestWork) bool {
+	if c == nil {
+		return false
+	}
+	c.Lock()
+	defer c.Unlock()
+	record, ok := c.resumed[work.Name]
+	if !ok {
+		return false
+	}
+	delete(c.resumed, work.Name)
+	return record.AppliedManifestWork != nil &&
+		record.ManifestWork.ResourceVersion == work.ResourceVersion &&
+		record.AppliedManifestWork.ResourceVersion == appliedWork.ResourceVersion
+}
+
+// ManifestWorks returns the cached manifestworks.
+func (c *Cache) ManifestWorks() (*workapiv1.ManifestWorkList, error) {
+	records, err := c.list()
+	if err != nil {
+		return nil, err
+	}
+	list := &workapiv1.ManifestWorkList{}
+	for _, record := range records {
+		list.Items = append(list.Items, *record.ManifestWork)
+	}
+	return list, nil
+}
+
+// Run marks the manifestworks changed on the events of the informers, and writes the records of the changed
+// manifestworks every interval until the context is done.
+func (c *Cache) Run(ctx context.Context,
+	workInformer workinformer.ManifestWorkInformer,
+	appliedWorkInformer workinformer.AppliedManifestWorkInformer,
+	interval time.Duration) {
+	c.workLister = workInformer.Lister().ManifestWorks(c.namespace)
+	c.appliedLister = appliedWorkInformer.Lister()
+
+	workKeyFunc := func(obj interface{}) string {
+		if work, ok := obj.(*workapiv1.ManifestWork); ok {
+			return work.Name
+		}
+		return ""
+	}
+	appliedKeyFunc := func(obj interface{}) string {
+		if appliedWork, ok := obj.(*workapiv1.AppliedManifestWork); ok {
+			return helper.AppliedManifestworkQueueKeyFunc(c.hubHash)(appliedWork)
+		}
+		return ""
+	}
+	if _, err := workInformer.Informer().AddEventHandler(c.eventHandler(workKeyFunc)); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	if _, err := appliedWorkInformer.Informer().AddEventHandler(c.eventHandler(appliedKeyFunc)); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	if !cache.WaitForCacheSync(ctx.Done(), workInformer.Informer().HasSynced, appliedWorkInformer.Informer().HasSynced) {
+		return
+	}
+	wait.UntilWithContext(ctx, func(context.Context) { c.flush() }, interval)
+	// write the last changes before exiting
+	c.flush()
+}
+
+func (c *Cache) eventHandler(keyFunc func(obj interface{}) string) cache.ResourceEventHandler {
+	markDirty := func(obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		key := keyFunc(obj)
+		if len(key) == 0 {
+			return
+		}
+		c.Lock()
+		defer c.Unlock()
+		c.dirty.Insert(key)
+	}
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    markDirty,
+		UpdateFunc: func(_, obj interface{}) { markDirty(obj) },
+		DeleteFunc: markDirty,
+	}
+}
+
+// flush writes the records of the changed manifestworks, and removes the records of the deleted ones.
+// The lock is only held to take the changed manifestworks, so the event handlers are not blocked by the writes.
+func (c *Cache) flush() {
+	c.Lock()
+	dirty := c.dirty
+	c.dirty = sets.New[string]()
+	c.Unlock()
+
+	for name := range dirty {
+		if err := c.sync(name); err != nil {
+			klog.Errorf("Failed to persist manifestwork %s to the resume cache: %v", name, err)
+			// retry in the next flush
+			c.Lock()
+			c.dirty.Insert(name)
+			c.Unlock()
+		}
+	}
+}
+
+func (c *Cache) sync(name string) error {
+	work, err := c.workLister.Get(name)
+	if errors.IsNotFound(err) {
+		return c.delete(name)
+	}
+	if err != nil {
+		return err
+	}
+
+	record := Record{ManifestWork: work}
+	appliedWork, err := c.appliedLister.Get(fmt.Sprintf("%s-%s", c.hubHash, name))
+	switch {
+	case errors.IsNotFound(err):
+	case err != nil:
+		return err
+	default:
+		record.AppliedManifestWork = appliedWork
+	}
+	return c.save(name, record)
+}
+
+func (c *Cache) path(name string) string {
+	return filepath.Join(c.dir, name+fileSuffix)
+}
+
+// save writes the record to a temporary file and renames it, so a crash during the write does not leave a
+// partial file.
+func (c *Cache) save(name string, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.dir, name+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(name))
+}
+
+func (c *Cache) delete(name string) error {
+	if err := os.Remove(c.path(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// list returns all the records in the cache. The malformed files are ignored.
+func (c *Cache) list() ([]Record, error) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), fileSuffix) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(c.dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		record := Record{}
+		if err := json.Unmarshal(data, &record); err != nil || record.ManifestWork == nil {
+			klog.Warningf("Ignore the malformed resume cache record %s: %v", entry.Name(), err)
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// NewManifestWorkInformer returns a manifestwork informer of the cluster namespace. If the hub cannot be
+// reached when the informer lists the manifestworks, the cached manifestworks are returned instead, so the
+// controllers could resume with the last known manifestworks.
+func NewManifestWorkInformer(
+	workCache *Cache,
+	client workclientset.Interface,
+	namespace string,
+	resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				works, err := client.WorkV1().ManifestWorks(namespace).List(context.TODO(), options)
+				if err == nil {
+					return works, nil
+				}
+				cached, cacheErr := workCache.ManifestWorks()
+				if cacheErr != nil || len(cached.Items) == 0 {
+					return nil, err
+				}
+				klog.Warningf("Failed to list manifestworks from hub, resume from %d cached manifestworks: %v",
+					len(cached.Items), err)
+				return cached, nil
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return client.WorkV1().ManifestWorks(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&workapiv1.ManifestWork{},
+		resyncPeriod,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+}
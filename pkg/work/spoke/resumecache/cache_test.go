@@ -0,0 +1,255 @@
+package resumecache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	fakeworkclient "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+func newWork(name string) *workapiv1.ManifestWork {
+	return &workapiv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "cluster1", ResourceVersion: "1"},
+	}
+}
+
+func newAppliedWork(name string) *workapiv1.AppliedManifestWork {
+	return &workapiv1.AppliedManifestWork{
+		ObjectMeta: metav1.ObjectMeta{Name: "hubhash-" + name, ResourceVersion: "1"},
+		Spec:       workapiv1.AppliedManifestWorkSpec{ManifestWorkName: name, HubHash: "hubhash"},
+	}
+}
+
+func TestFlush(t *testing.T) {
+	dir := t.TempDir()
+	workCache, err := NewCache(dir, "cluster1", "hubhash")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	informerFactory := workinformers.NewSharedInformerFactory(fakeworkclient.NewSimpleClientset(), 5*time.Minute)
+	workStore := informerFactory.Work().V1().ManifestWorks().Informer().GetStore()
+	appliedWorkStore := informerFactory.Work().V1().AppliedManifestWorks().Informer().GetStore()
+	workCache.workLister = informerFactory.Work().V1().ManifestWorks().Lister().ManifestWorks("cluster1")
+	workCache.appliedLister = informerFactory.Work().V1().AppliedManifestWorks().Lister()
+	workHandler := workCache.eventHandler(func(obj interface{}) string { return obj.(*workapiv1.ManifestWork).Name })
+
+	for _, obj := range []runtime.Object{newWork("work1"), newWork("work2"), newAppliedWork("work1")} {
+		var err error
+		switch o := obj.(type) {
+		case *workapiv1.ManifestWork:
+			err = workStore.Add(o)
+			workHandler.OnAdd(o, false)
+		case *workapiv1.AppliedManifestWork:
+			err = appliedWorkStore.Add(o)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// malformed files are ignored
+	if err := os.WriteFile(filepath.Join(dir, "bad.json"), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// nothing is written before the flush
+	assertCachedWorks(t, workCache)
+
+	workCache.flush()
+	assertCachedWorks(t, workCache, "work1", "work2")
+	records, err := workCache.list()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		hasAppliedWork := record.AppliedManifestWork != nil
+		if hasAppliedWork != (record.ManifestWork.Name == "work1") {
+			t.Errorf("unexpected appliedmanifestwork of the record %s: %v", record.ManifestWork.Name, record.AppliedManifestWork)
+		}
+	}
+
+	work1 := newWork("work1")
+	if err := workStore.Delete(work1); err != nil {
+		t.Fatal(err)
+	}
+	workHandler.OnDelete(cache.DeletedFinalStateUnknown{Key: "cluster1/work1", Obj: work1})
+	workCache.flush()
+	assertCachedWorks(t, workCache, "work2")
+}
+
+func TestResumed(t *testing.T) {
+	cases := []struct {
+		name               string
+		cachedAppliedWork  bool
+		workVersion        string
+		appliedWorkVersion string
+		expected           bool
+	}{
+		{
+			name:               "resumed if nothing changed",
+			cachedAppliedWork:  true,
+			workVersion:        "1",
+			appliedWorkVersion: "1",
+			expected:           true,
+		},
+		{
+			name:               "not resumed if the manifestwork changed",
+			cachedAppliedWork:  true,
+			workVersion:        "2",
+			appliedWorkVersion: "1",
+		},
+		{
+			name:               "not resumed if the appliedmanifestwork changed",
+			cachedAppliedWork:  true,
+			workVersion:        "1",
+			appliedWorkVersion: "2",
+		},
+		{
+			name:               "not resumed if the appliedmanifestwork is not cached",
+			workVersion:        "1",
+			appliedWorkVersion: "1",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := t.TempDir()
+			record := Record{ManifestWork: newWork("work1")}
+			if c.cachedAppliedWork {
+				record.AppliedManifestWork = newAppliedWork("work1")
+			}
+			if err := (&Cache{dir: dir}).save("work1", record); err != nil {
+				t.Fatal(err)
+			}
+			workCache, err := NewCache(dir, "cluster1", "hubhash")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			work := newWork("work1")
+			work.ResourceVersion = c.workVersion
+			appliedWork := newAppliedWork("work1")
+			appliedWork.ResourceVersion = c.appliedWorkVersion
+			if resumed := workCache.Resumed(work, appliedWork); resumed != c.expected {
+				t.Errorf("expected resumed %v, but got %v", c.expected, resumed)
+			}
+			if workCache.Resumed(work, appliedWork) {
+				t.Errorf("expected the work to be resumed at most once")
+			}
+		})
+	}
+
+	var nilCache *Cache
+	if nilCache.Resumed(newWork("work1"), newAppliedWork("work1")) {
+		t.Errorf("expected no work resumed by a nil cache")
+	}
+}
+
+func TestNewManifestWorkInformer(t *testing.T) {
+	cases := []struct {
+		name          string
+		cached        []string
+		hubWorks      []runtime.Object
+		listErr       error
+		expectedErr   bool
+		expectedWorks []string
+	}{
+		{
+			name:          "list from hub",
+			cached:        []string{"work1", "stale"},
+			hubWorks:      []runtime.Object{newWork("work1"), newWork("work2")},
+			expectedWorks: []string{"work1", "work2"},
+		},
+		{
+			name:          "resume from the cache if hub is not reachable",
+			cached:        []string{"work1"},
+			listErr:       fmt.Errorf("connection refused"),
+			expectedWorks: []string{"work1"},
+		},
+		{
+			name:        "return the error if hub is not reachable and the cache is empty",
+			listErr:     fmt.Errorf("connection refused"),
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range c.cached {
+				if err := (&Cache{dir: dir}).save(name, Record{ManifestWork: newWork(name)}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			workCache, err := NewCache(dir, "cluster1", "hubhash")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			client := fakeworkclient.NewSimpleClientset(c.hubWorks...)
+			if c.listErr != nil {
+				client.PrependReactor("list", "manifestworks", func(action clienttesting.Action) (bool, runtime.Object, error) {
+					return true, nil, c.listErr
+				})
+			}
+
+			informer := NewManifestWorkInformer(workCache, client, "cluster1", 10*time.Minute)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go informer.Run(ctx.Done())
+
+			syncCtx, syncCancel := context.WithTimeout(ctx, 2*time.Second)
+			defer syncCancel()
+			synced := cache.WaitForCacheSync(syncCtx.Done(), informer.HasSynced)
+			if c.expectedErr {
+				if synced {
+					t.Errorf("expected the informer not synced")
+				}
+				return
+			}
+			if !synced {
+				t.Fatalf("expected the informer synced")
+			}
+
+			keys := informer.GetStore().ListKeys()
+			if len(keys) != len(c.expectedWorks) {
+				t.Errorf("expected works %v, but got %v", c.expectedWorks, keys)
+			}
+			for _, name := range c.expectedWorks {
+				if _, exists, _ := informer.GetStore().GetByKey("cluster1/" + name); !exists {
+					t.Errorf("expected work %s in the informer", name)
+				}
+			}
+		})
+	}
+}
+
+func assertCachedWorks(t *testing.T, workCache *Cache, names ...string) {
+	works, err := workCache.ManifestWorks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(works.Items) != len(names) {
+		t.Fatalf("expected %d cached works, but got %d", len(names), len(works.Items))
+	}
+	expected := map[string]bool{}
+	for _, name := range names {
+		expected[name] = true
+	}
+	for _, work := range works.Items {
+		if !expected[work.Name] {
+			t.Errorf("unexpected cached work %s", work.Name)
+		}
+	}
+}
@@ -2,28 +2,35 @@ package spoke
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
 	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
 	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
 	ocmfeature "open-cluster-management.io/api/feature"
+	workapiv1 "open-cluster-management.io/api/work/v1"
 
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
 	commonoptions "open-cluster-management.io/ocm/pkg/common/options"
 	"open-cluster-management.io/ocm/pkg/features"
 	"open-cluster-management.io/ocm/pkg/work/helper"
+	"open-cluster-management.io/ocm/pkg/work/spoke/apply"
 	"open-cluster-management.io/ocm/pkg/work/spoke/auth"
 	"open-cluster-management.io/ocm/pkg/work/spoke/controllers/appliedmanifestcontroller"
 	"open-cluster-management.io/ocm/pkg/work/spoke/controllers/finalizercontroller"
 	"open-cluster-management.io/ocm/pkg/work/spoke/controllers/manifestcontroller"
 	"open-cluster-management.io/ocm/pkg/work/spoke/controllers/statuscontroller"
+	"open-cluster-management.io/ocm/pkg/work/spoke/resumecache"
 )
 
 const (
@@ -36,6 +43,9 @@ const (
 	appliedManifestWorkFinalizeControllerWorkers = 10
 	manifestWorkFinalizeControllerWorkers        = 10
 	availableStatusControllerWorkers             = 10
+
+	// the changes of the manifestworks are written to the resume cache in batches in this interval.
+	resumeCacheFlushInterval = 10 * time.Second
 )
 
 type WorkAgentConfig struct {
@@ -53,6 +63,62 @@ func NewWorkAgentConfig(commonOpts *commonoptions.AgentOptions, opts *WorkloadAg
 
 // RunWorkloadAgent starts the controllers on agent to process work from hub.
 func (o *WorkAgentConfig) RunWorkloadAgent(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
+	if len(o.agentOptions.FeatureGatesConfigMap) > 0 {
+		if err := o.runFeatureGateReloader(ctx, controllerContext); err != nil {
+			return err
+		}
+	}
+
+	return o.runWorkloadAgent(ctx, controllerContext)
+}
+
+// runFeatureGateReloader loads the feature gates of the work agent in the feature gates configmap, and reloads them
+// once the configmap is changed.
+func (o *WorkAgentConfig) runFeatureGateReloader(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
+	managementKubeClient, err := kubernetes.NewForConfig(controllerContext.KubeConfig)
+	if err != nil {
+		return err
+	}
+	if err := features.LoadFeatureGates(ctx, managementKubeClient, o.agentOptions.ComponentNamespace,
+		o.agentOptions.FeatureGatesConfigMap, features.FeatureGatesConfigMapWorkKey,
+		features.SpokeMutableFeatureGate, ocmfeature.DefaultSpokeWorkFeatureGates); err != nil {
+		return err
+	}
+
+	managementKubeInformerFactory := informers.NewSharedInformerFactoryWithOptions(
+		managementKubeClient, 10*time.Minute, informers.WithNamespace(o.agentOptions.ComponentNamespace))
+	featureGateReloadController := features.NewFeatureGateReloadController(
+		managementKubeClient,
+		managementKubeInformerFactory.Core().V1().ConfigMaps(),
+		o.agentOptions.FeatureGatesConfigMap,
+		features.FeatureGatesConfigMapWorkKey,
+		features.SpokeMutableFeatureGate,
+		ocmfeature.DefaultSpokeWorkFeatureGates,
+		controllerContext.EventRecorder,
+		// the executor validator is built once the agent starts
+		ocmfeature.ExecutorValidatingCaches,
+	)
+	go managementKubeInformerFactory.Start(ctx.Done())
+	go featureGateReloadController.Run(ctx, 1)
+	return nil
+}
+
+func (o *WorkAgentConfig) runWorkloadAgent(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
+	resourcePolicy, err := helper.NewResourcePolicy(o.workOptions.AllowedResources, o.workOptions.DeniedResources)
+	if err != nil {
+		return err
+	}
+	externalAppliers, err := apply.LoadExternalAppliers(o.workOptions.ExternalAppliersConfig)
+	if err != nil {
+		return err
+	}
+	stalePolicy := finalizercontroller.StaleAppliedWorkPolicy(o.workOptions.StaleAppliedManifestWorkPolicy)
+	switch stalePolicy {
+	case finalizercontroller.StaleAppliedWorkPolicyEvict, finalizercontroller.StaleAppliedWorkPolicyAdopt:
+	default:
+		return fmt.Errorf("unsupported stale appliedmanifestwork policy %q", stalePolicy)
+	}
+
 	// build hub client and informer
 	hubRestConfig, err := clientcmd.BuildConfigFromFlags("" /* leave masterurl as empty */, o.agentOptions.HubKubeconfigFile)
 	if err != nil {
@@ -69,9 +135,25 @@ func (o *WorkAgentConfig) RunWorkloadAgent(ctx context.Context, controllerContex
 	if err != nil {
 		return err
 	}
+	hubKubeClient, err := kubernetes.NewForConfig(hubRestConfig)
+	if err != nil {
+		return err
+	}
 	// Only watch the cluster namespace on hub
 	workInformerFactory := workinformers.NewSharedInformerFactoryWithOptions(hubWorkClient, 5*time.Minute,
 		workinformers.WithNamespace(o.agentOptions.SpokeClusterName))
+	var workCache *resumecache.Cache
+	if len(o.workOptions.ResumeCacheDir) > 0 {
+		workCache, err = resumecache.NewCache(o.workOptions.ResumeCacheDir, o.agentOptions.SpokeClusterName, hubhash)
+		if err != nil {
+			return err
+		}
+		// replace the default manifestwork informer with the one backed by the resume cache.
+		workInformerFactory.InformerFor(&workapiv1.ManifestWork{},
+			func(client workclientset.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+				return resumecache.NewManifestWorkInformer(workCache, client, o.agentOptions.SpokeClusterName, resyncPeriod)
+			})
+	}
 
 	// load spoke client config and create spoke clients,
 	// the work agent may not running in the spoke/managed cluster.
@@ -118,6 +200,8 @@ func (o *WorkAgentConfig) RunWorkloadAgent(ctx context.Context, controllerContex
 
 	manifestWorkController := manifestcontroller.NewManifestWorkController(
 		controllerContext.EventRecorder,
+		// the events regarding the manifestworks are sent to the cluster namespace on the hub
+		commonhelpers.NewEventRecorder(ctx, hubKubeClient, "work-agent"),
 		spokeDynamicClient,
 		spokeKubeClient,
 		spokeAPIExtensionClient,
@@ -129,6 +213,12 @@ func (o *WorkAgentConfig) RunWorkloadAgent(ctx context.Context, controllerContex
 		hubhash, agentID,
 		restMapper,
 		validator,
+		o.workOptions.SkipUnchangedManifests,
+		o.workOptions.AdoptionLabelKey,
+		resourcePolicy,
+		externalAppliers,
+		o.workOptions.ManifestApplyTimeout,
+		o.workOptions.WorkSyncDeadline,
 	)
 	addFinalizerController := finalizercontroller.NewAddFinalizerController(
 		controllerContext.EventRecorder,
@@ -148,18 +238,22 @@ func (o *WorkAgentConfig) RunWorkloadAgent(ctx context.Context, controllerContex
 		hubWorkClient.WorkV1().ManifestWorks(o.agentOptions.SpokeClusterName),
 		workInformerFactory.Work().V1().ManifestWorks(),
 		workInformerFactory.Work().V1().ManifestWorks().Lister().ManifestWorks(o.agentOptions.SpokeClusterName),
+		spokeDynamicClient,
 		spokeWorkClient.WorkV1().AppliedManifestWorks(),
 		spokeWorkInformerFactory.Work().V1().AppliedManifestWorks(),
+		externalAppliers,
 		hubhash,
 	)
 	unmanagedAppliedManifestWorkController := finalizercontroller.NewUnManagedAppliedWorkController(
 		controllerContext.EventRecorder,
+		spokeDynamicClient,
 		workInformerFactory.Work().V1().ManifestWorks(),
 		workInformerFactory.Work().V1().ManifestWorks().Lister().ManifestWorks(o.agentOptions.SpokeClusterName),
 		spokeWorkClient.WorkV1().AppliedManifestWorks(),
 		spokeWorkInformerFactory.Work().V1().AppliedManifestWorks(),
 		o.workOptions.AppliedManifestWorkEvictionGracePeriod,
-		hubhash, agentID,
+		stalePolicy,
+		hubhash, agentID, o.workOptions.PreviousAgentID,
 	)
 	appliedManifestWorkController := appliedmanifestcontroller.NewAppliedManifestWorkController(
 		controllerContext.EventRecorder,
@@ -169,6 +263,7 @@ func (o *WorkAgentConfig) RunWorkloadAgent(ctx context.Context, controllerContex
 		spokeWorkClient.WorkV1().AppliedManifestWorks(),
 		spokeWorkInformerFactory.Work().V1().AppliedManifestWorks(),
 		hubhash,
+		workCache,
 	)
 	availableStatusController := statuscontroller.NewAvailableStatusController(
 		controllerContext.EventRecorder,
@@ -177,8 +272,16 @@ func (o *WorkAgentConfig) RunWorkloadAgent(ctx context.Context, controllerContex
 		workInformerFactory.Work().V1().ManifestWorks(),
 		workInformerFactory.Work().V1().ManifestWorks().Lister().ManifestWorks(o.agentOptions.SpokeClusterName),
 		o.workOptions.StatusSyncInterval,
+		o.workOptions.StatusPatchCoalesceWindow,
 	)
 
+	if err := commonhelpers.SetInformerTransform(commonhelpers.TrimManagedFieldsAndLastApplied,
+		workInformerFactory.Work().V1().ManifestWorks().Informer(),
+		spokeWorkInformerFactory.Work().V1().AppliedManifestWorks().Informer(),
+	); err != nil {
+		return err
+	}
+
 	go workInformerFactory.Start(ctx.Done())
 	go spokeWorkInformerFactory.Start(ctx.Done())
 	go addFinalizerController.Run(ctx, 1)
@@ -188,6 +291,10 @@ func (o *WorkAgentConfig) RunWorkloadAgent(ctx context.Context, controllerContex
 	go manifestWorkController.Run(ctx, 1)
 	go manifestWorkFinalizeController.Run(ctx, manifestWorkFinalizeControllerWorkers)
 	go availableStatusController.Run(ctx, availableStatusControllerWorkers)
+	if workCache != nil {
+		go workCache.Run(ctx, workInformerFactory.Work().V1().ManifestWorks(),
+			spokeWorkInformerFactory.Work().V1().AppliedManifestWorks(), resumeCacheFlushInterval)
+	}
 	<-ctx.Done()
 	return nil
 }
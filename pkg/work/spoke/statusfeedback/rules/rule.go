@@ -38,6 +38,10 @@ var jobRule = []workapiv1.JsonPath{
 		Name: "JobSucceeded",
 		Path: `.status.succeeded`,
 	},
+	{
+		Name: "JobFailed",
+		Path: `.status.conditions[?(@.type=="Failed")].status`,
+	},
 }
 
 var podRule = []workapiv1.JsonPath{
@@ -1,19 +1,27 @@
 package webhook
 
-import "github.com/spf13/pflag"
+import (
+	"github.com/spf13/pflag"
+
+	commonoptions "open-cluster-management.io/ocm/pkg/common/options"
+)
 
 // Config contains the server (the webhook) cert and key.
 type Options struct {
 	Port          int
 	CertDir       string
 	ManifestLimit int
+	FIPSMode      bool
+
+	ServingOptions *commonoptions.WebhookServingOptions
 }
 
 // NewOptions constructs a new set of default options for webhook.
 func NewOptions() *Options {
 	return &Options{
-		Port:          9443,
-		ManifestLimit: 500 * 1024, // the default manifest limit is 500k.
+		Port:           9443,
+		ManifestLimit:  500 * 1024, // the default manifest limit is 500k.
+		ServingOptions: commonoptions.NewWebhookServingOptions(),
 	}
 }
 
@@ -25,4 +33,7 @@ func (c *Options) AddFlags(fs *pflag.FlagSet) {
 			"webhook server would look up the server key and certificate in {TempDir}/k8s-webhook-server/serving-certs")
 	fs.IntVar(&c.ManifestLimit, "manifestLimit", c.ManifestLimit,
 		"ManifestLimit is the max size of manifests in a manifestWork. If not set, the default is 500k.")
+	fs.BoolVar(&c.FIPSMode, "fips-mode", c.FIPSMode,
+		"Restrict the TLS configuration and the serving certificate to the FIPS approved algorithms and key sizes.")
+	c.ServingOptions.AddFlags(fs)
 }
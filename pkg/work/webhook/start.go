@@ -1,7 +1,11 @@
 package webhook
 
 import (
+	"context"
 	"crypto/tls"
+	"fmt"
+	"os"
+	"path/filepath"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -16,6 +20,7 @@ import (
 
 	workv1 "open-cluster-management.io/api/work/v1"
 
+	"open-cluster-management.io/ocm/pkg/common/fips"
 	"open-cluster-management.io/ocm/pkg/work/webhook/common"
 	webhookv1 "open-cluster-management.io/ocm/pkg/work/webhook/v1"
 )
@@ -30,14 +35,34 @@ func init() {
 }
 
 func (c *Options) RunWebhookServer() error {
+	logger := klog.LoggerWithName(klog.FromContext(context.Background()), "Webhook Server")
+	if c.FIPSMode {
+		fips.Enable()
+	}
+	if fips.Enabled() {
+		// the serving certificate is looked up in the same default directory as the webhook server.
+		certDir := c.CertDir
+		if len(certDir) == 0 {
+			certDir = filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
+		}
+		if err := fips.ValidateCertificateFile(filepath.Join(certDir, "tls.crt")); err != nil {
+			return fmt.Errorf("the serving certificate is not valid in FIPS mode: %w", err)
+		}
+	}
+	tlsConfigFunc, err := c.ServingOptions.TLSConfigFunc()
+	if err != nil {
+		return err
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		HealthProbeBindAddress: ":8000",
+		// wait for the in-flight admission requests on shutdown
+		GracefulShutdownTimeout: &c.ServingOptions.ShutdownTimeout,
 		WebhookServer: webhook.NewServer(webhook.Options{
 			TLSOpts: []func(config *tls.Config){
-				func(config *tls.Config) {
-					config.MinVersion = tls.VersionTLS12
-				},
+				tlsConfigFunc,
+				fips.SecureTLSConfig,
 			},
 			Port:    c.Port,
 			CertDir: c.CertDir,
@@ -45,31 +70,31 @@ func (c *Options) RunWebhookServer() error {
 	})
 
 	if err != nil {
-		klog.Error(err, "unable to start manager")
+		logger.Error(err, "unable to start manager")
 		return err
 	}
 
 	// add healthz/readyz check handler
 	if err := mgr.AddHealthzCheck("healthz-ping", healthz.Ping); err != nil {
-		klog.Errorf("unable to add healthz check handler: %v", err)
+		logger.Error(err, "unable to add healthz check handler")
 		return err
 	}
 
 	if err := mgr.AddReadyzCheck("readyz-ping", healthz.Ping); err != nil {
-		klog.Errorf("unable to add readyz check handler: %v", err)
+		logger.Error(err, "unable to add readyz check handler")
 		return err
 	}
 
 	common.ManifestValidator.WithLimit(c.ManifestLimit)
 
 	if err = (&webhookv1.ManifestWorkWebhook{}).Init(mgr); err != nil {
-		klog.Error(err, "unable to create ManagedCluster webhook")
+		logger.Error(err, "unable to create ManifestWork webhook")
 		return err
 	}
 
-	klog.Info("starting manager")
+	logger.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
-		klog.Error(err, "problem running manager")
+		logger.Error(err, "problem running manager")
 		return err
 	}
 	return nil
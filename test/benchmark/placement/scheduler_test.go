@@ -0,0 +1,256 @@
+package placement
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/scheduling"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/addon"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/addonhealth"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/antiaffinity"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/balance"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/predicate"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/resource"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/steady"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/tainttoleration"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/topology"
+)
+
+// The scheduler benchmarks run the scheduler and each plugin against fake listers, so they measure the
+// scheduling itself without a kube-apiserver. TestSchedulerRegression compares the results with the
+// thresholds in thresholds.yaml, it is skipped unless PLACEMENT_BENCHMARK_REGRESSION is set since the
+// results depend on the machine.
+const (
+	regressionEnv  = "PLACEMENT_BENCHMARK_REGRESSION"
+	thresholdsFile = "thresholds.yaml"
+)
+
+var benchmarkClusterNums = []int{1000, 5000}
+
+// schedulerThresholds are the max durations of one operation against the number of clusters.
+type schedulerThresholds struct {
+	Clusters     int                        `json:"clusters"`
+	Scheduler    metav1.Duration            `json:"scheduler"`
+	Filters      map[string]metav1.Duration `json:"filters"`
+	Prioritizers map[string]metav1.Duration `json:"prioritizers"`
+}
+
+func BenchmarkScheduler(b *testing.B) {
+	for _, num := range benchmarkClusterNums {
+		clusters := newBenchmarkClusters(num)
+		handle := testinghelpers.NewFakePluginHandle(b, nil, newBenchmarkObjects(clusters)...)
+		b.Run(fmt.Sprintf("clusters=%d", num), func(b *testing.B) {
+			benchmarkScheduler(b, handle, clusters)
+		})
+	}
+}
+
+func BenchmarkFilters(b *testing.B) {
+	for _, num := range benchmarkClusterNums {
+		clusters := newBenchmarkClusters(num)
+		handle := testinghelpers.NewFakePluginHandle(b, nil, newBenchmarkObjects(clusters)...)
+		filters := newBenchmarkFilters(handle)
+		for _, name := range sortedKeys(filters) {
+			b.Run(fmt.Sprintf("%s/clusters=%d", name, num), func(b *testing.B) {
+				benchmarkFilter(b, filters[name], clusters)
+			})
+		}
+	}
+}
+
+func BenchmarkPrioritizers(b *testing.B) {
+	for _, num := range benchmarkClusterNums {
+		clusters := newBenchmarkClusters(num)
+		handle := testinghelpers.NewFakePluginHandle(b, nil, newBenchmarkObjects(clusters)...)
+		prioritizers := newBenchmarkPrioritizers(handle)
+		for _, name := range sortedKeys(prioritizers) {
+			b.Run(fmt.Sprintf("%s/clusters=%d", name, num), func(b *testing.B) {
+				benchmarkPrioritizer(b, prioritizers[name], clusters)
+			})
+		}
+	}
+}
+
+func TestSchedulerRegression(t *testing.T) {
+	if len(os.Getenv(regressionEnv)) == 0 {
+		t.Skipf("set %s to compare the scheduler benchmarks with %s", regressionEnv, thresholdsFile)
+	}
+
+	data, err := os.ReadFile(thresholdsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	thresholds := &schedulerThresholds{}
+	if err := yaml.UnmarshalStrict(data, thresholds); err != nil {
+		t.Fatal(err)
+	}
+
+	clusters := newBenchmarkClusters(thresholds.Clusters)
+	handle := testinghelpers.NewFakePluginHandle(t, nil, newBenchmarkObjects(clusters)...)
+
+	check := func(name string, threshold metav1.Duration, benchmark func(b *testing.B)) {
+		result := testing.Benchmark(benchmark)
+		perOp := time.Duration(result.NsPerOp())
+		t.Logf("%s: %v per operation with %d clusters, threshold %v", name, perOp, thresholds.Clusters, threshold.Duration)
+		if threshold.Duration > 0 && perOp > threshold.Duration {
+			t.Errorf("%s takes %v per operation with %d clusters, which exceeds the threshold %v",
+				name, perOp, thresholds.Clusters, threshold.Duration)
+		}
+	}
+
+	check("Scheduler", thresholds.Scheduler, func(b *testing.B) {
+		benchmarkScheduler(b, handle, clusters)
+	})
+	filters := newBenchmarkFilters(handle)
+	for _, name := range sortedKeys(filters) {
+		check(name, thresholds.Filters[name], func(b *testing.B) {
+			benchmarkFilter(b, filters[name], clusters)
+		})
+	}
+	prioritizers := newBenchmarkPrioritizers(handle)
+	for _, name := range sortedKeys(prioritizers) {
+		check(name, thresholds.Prioritizers[name], func(b *testing.B) {
+			benchmarkPrioritizer(b, prioritizers[name], clusters)
+		})
+	}
+}
+
+func benchmarkScheduler(b *testing.B, handle plugins.Handle, clusters []*clusterapiv1.ManagedCluster) {
+	s := scheduling.NewPluginScheduler(handle)
+	placement := newBenchmarkPlacement()
+
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		if _, status := s.Schedule(context.Background(), placement, clusters); status.IsError() {
+			b.Fatal(status.AsError())
+		}
+	}
+	b.ReportMetric(float64(b.N)/time.Since(start).Seconds(), "placements/s")
+}
+
+func benchmarkFilter(b *testing.B, filter plugins.Filter, clusters []*clusterapiv1.ManagedCluster) {
+	placement := newBenchmarkPlacement()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, status := filter.Filter(context.Background(), placement, clusters); status.IsError() {
+			b.Fatal(status.AsError())
+		}
+	}
+}
+
+func benchmarkPrioritizer(b *testing.B, prioritizer plugins.Prioritizer, clusters []*clusterapiv1.ManagedCluster) {
+	placement := newBenchmarkPlacement()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, status := prioritizer.Score(context.Background(), placement, clusters); status.IsError() {
+			b.Fatal(status.AsError())
+		}
+	}
+}
+
+// newBenchmarkClusters returns the clusters with labels, claims, resources, and taints on part of them.
+func newBenchmarkClusters(num int) []*clusterapiv1.ManagedCluster {
+	clusters := make([]*clusterapiv1.ManagedCluster, 0, num)
+	for i := 0; i < num; i++ {
+		builder := testinghelpers.NewManagedCluster(fmt.Sprintf("cluster%d", i)).
+			WithLabel(clusterSetLabel, name).
+			WithLabel("topology.kubernetes.io/region", fmt.Sprintf("region%d", i%10)).
+			WithClaim("platform.open-cluster-management.io", []string{"AWS", "GCP", "Azure"}[i%3]).
+			WithResource(clusterapiv1.ResourceCPU, fmt.Sprint(i%64+1), "64").
+			WithResource(clusterapiv1.ResourceMemory, fmt.Sprintf("%dGi", i%256+1), "256Gi")
+		if i%20 == 0 {
+			builder = builder.WithTaint(&clusterapiv1.Taint{
+				Key:    "maintenance",
+				Effect: clusterapiv1.TaintEffectNoSelect,
+			})
+		}
+		clusters = append(clusters, builder.Build())
+	}
+	return clusters
+}
+
+// newBenchmarkObjects returns the clusters, their AddOnPlacementScores and ManagedClusterAddOns, and the
+// decisions of another placement, which are used by the Balance, Steady and AntiAffinity plugins.
+func newBenchmarkObjects(clusters []*clusterapiv1.ManagedCluster) []runtime.Object {
+	var objs []runtime.Object
+	var decided []string
+	for i, cluster := range clusters {
+		objs = append(objs, cluster,
+			testinghelpers.NewAddOnPlacementScore(cluster.Name, "demo").WithScore("demo", int32(i%200-100)).Build(),
+			&addonapiv1alpha1.ManagedClusterAddOn{
+				ObjectMeta: metav1.ObjectMeta{Namespace: cluster.Name, Name: "demo"},
+				Status: addonapiv1alpha1.ManagedClusterAddOnStatus{
+					Conditions: []metav1.Condition{
+						{Type: addonapiv1alpha1.ManagedClusterAddOnConditionAvailable, Status: metav1.ConditionTrue},
+					},
+				},
+			})
+		if i%10 == 0 {
+			decided = append(decided, cluster.Name)
+		}
+	}
+	objs = append(objs, testinghelpers.NewPlacementDecision(namespace, testinghelpers.PlacementDecisionName("other", 1)).
+		WithLabel(clusterapiv1beta1.PlacementLabel, "other").WithDecisions(decided...).Build())
+	return objs
+}
+
+func newBenchmarkPlacement() *clusterapiv1beta1.Placement {
+	return testinghelpers.NewPlacementWithAnnotations(namespace, name, map[string]string{
+		antiaffinity.AntiAffinityAnnotationKey:  "other",
+		addonhealth.RequiredAddOnsAnnotationKey: "demo",
+	}).WithNOC(noc).
+		AddPredicate(nil, &clusterapiv1beta1.ClusterClaimSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "platform.open-cluster-management.io", Operator: metav1.LabelSelectorOpIn, Values: []string{"AWS", "GCP"}},
+			},
+		}).
+		WithPrioritizerPolicy(clusterapiv1beta1.PrioritizerPolicyModeAdditive).
+		WithPrioritizerConfig(scheduling.PrioritizerResourceAllocatableCPU, 1).
+		WithScoreCoordinateAddOn("demo", "demo", 1).
+		Build()
+}
+
+func newBenchmarkFilters(handle plugins.Handle) map[string]plugins.Filter {
+	return map[string]plugins.Filter{
+		"Predicate":       predicate.New(handle),
+		"TaintToleration": tainttoleration.New(handle),
+		"AntiAffinity":    antiaffinity.New(handle),
+		"AddOnHealth":     addonhealth.New(handle),
+	}
+}
+
+func newBenchmarkPrioritizers(handle plugins.Handle) map[string]plugins.Prioritizer {
+	return map[string]plugins.Prioritizer{
+		"Balance":                balance.New(handle),
+		"Steady":                 steady.New(handle),
+		"ResourceAllocatableCPU": resource.NewResourcePrioritizerBuilder(handle).WithPrioritizerName(scheduling.PrioritizerResourceAllocatableCPU).Build(),
+		"Topology":               topology.New(handle),
+		"AddOn":                  addon.NewAddOnPrioritizerBuilder(handle).WithResourceName("demo").WithScoreName("demo").Build(),
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
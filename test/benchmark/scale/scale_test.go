@@ -0,0 +1,490 @@
+package scale
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/controller/controllercmd"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/yaml"
+
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	ocmfeature "open-cluster-management.io/api/feature"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/features"
+	placementcontrollers "open-cluster-management.io/ocm/pkg/placement/controllers"
+	ocmtesting "open-cluster-management.io/ocm/pkg/testing"
+	"open-cluster-management.io/ocm/test/integration/util"
+)
+
+const (
+	kubeconfigEnv = "SCALE_TEST_KUBECONFIG"
+	budgetsFile   = "budgets.yaml"
+	apiDir        = "../../../vendor/open-cluster-management.io/api"
+
+	// runLabel is set on the simulated clusters with the id of the run, so the clusters are selected by the
+	// clusterset of the run.
+	runLabel       = "scale.open-cluster-management.io/run"
+	leaseName      = "managed-cluster-lease"
+	scaleNamespace = "scale-test"
+	// agentWorkers is the number of the goroutines simulating the agents.
+	agentWorkers = 50
+	// leaseRenewInterval is the interval the simulated agents renew the leases, which is the default lease
+	// duration of the clusters.
+	leaseRenewInterval = 60 * time.Second
+)
+
+// budgets are the max latencies and resource usage of the hub controllers with the number of the clusters.
+type budgets struct {
+	Clusters         int               `json:"clusters"`
+	WorksPerCluster  int               `json:"worksPerCluster"`
+	SteadyDuration   metav1.Duration   `json:"steadyDuration"`
+	AcceptLatencyP99 metav1.Duration   `json:"acceptLatencyP99"`
+	PlacementLatency metav1.Duration   `json:"placementLatency"`
+	CPU              metav1.Duration   `json:"cpu"`
+	Memory           resource.Quantity `json:"memory"`
+}
+
+// TestScale simulates thousands of managed clusters against a kube-apiserver started by kwok, e.g. with
+// "kwokctl create cluster". The hub controllers run in the test process, and the agent of each cluster is
+// simulated by reporting the status and claims of the cluster, renewing its lease and updating the status of
+// its manifestworks. The latencies and the resource usage are compared with the budgets in budgets.yaml, the
+// test is skipped unless SCALE_TEST_KUBECONFIG is set to the kubeconfig of the kwok cluster.
+func TestScale(t *testing.T) {
+	kubeconfig := os.Getenv(kubeconfigEnv)
+	if len(kubeconfig) == 0 {
+		t.Skipf("set %s to the kubeconfig of a kwok cluster to run the scale test", kubeconfigEnv)
+	}
+
+	data, err := os.ReadFile(budgetsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &budgets{}
+	if err := yaml.UnmarshalStrict(data, b); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the client side throttling would dominate the latencies with thousands of clusters.
+	cfg.QPS, cfg.Burst = 500, 1000
+
+	hub, err := ocmtesting.StartHub(&envtest.Environment{
+		UseExistingCluster:    pointer.Bool(true),
+		Config:                cfg,
+		ErrorIfCRDPathMissing: true,
+		CRDDirectoryPaths:     ocmtesting.CRDPaths(apiDir, ocmtesting.HubCRDFiles),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := hub.Stop(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sampler := newResourceSampler()
+	go sampler.run(ctx)
+
+	startHubControllers(ctx, t, cfg)
+
+	s := &simulator{
+		hub:      hub,
+		runID:    rand.String(6),
+		clusters: make([]string, b.Clusters),
+	}
+	for i := range s.clusters {
+		s.clusters[i] = fmt.Sprintf("scale-%s-%d", s.runID, i)
+	}
+
+	t.Logf("Register %d clusters", b.Clusters)
+	acceptLatencies := s.registerClusters(ctx, t)
+	check(t, "Accept latency p99", percentile(acceptLatencies, 0.99), b.AcceptLatencyP99.Duration)
+
+	t.Logf("Apply %d manifestworks to each cluster", b.WorksPerCluster)
+	s.applyWorks(ctx, t, b.WorksPerCluster)
+
+	t.Logf("Renew the leases of the clusters for %v", b.SteadyDuration.Duration)
+	s.renewLeases(ctx, t, b.SteadyDuration.Duration)
+
+	t.Logf("Select all the clusters with a placement")
+	check(t, "Placement latency", s.placeClusters(ctx, t), b.PlacementLatency.Duration)
+
+	cpu, maxHeap := sampler.usage()
+	check(t, "CPU time", cpu, b.CPU.Duration)
+	t.Logf("Max heap in use: %s, budget %s", resource.NewQuantity(int64(maxHeap), resource.BinarySI), b.Memory.String())
+	if maxHeap > uint64(b.Memory.Value()) {
+		t.Errorf("Max heap in use %s with %d clusters exceeds the budget %s",
+			resource.NewQuantity(int64(maxHeap), resource.BinarySI), b.Clusters, b.Memory.String())
+	}
+}
+
+func check(t *testing.T, name string, actual, budget time.Duration) {
+	t.Logf("%s: %v, budget %v", name, actual, budget)
+	if budget > 0 && actual > budget {
+		t.Errorf("%s %v exceeds the budget %v", name, actual, budget)
+	}
+}
+
+func startHubControllers(ctx context.Context, t *testing.T, cfg *rest.Config) {
+	utilruntime.Must(features.HubMutableFeatureGate.Add(ocmfeature.DefaultHubRegistrationFeatureGates))
+	utilruntime.Must(features.HubMutableFeatureGate.Add(ocmfeature.DefaultHubWorkFeatureGates))
+	recorder := ocmtesting.NewEventRecorder("hub", io.Discard)
+
+	run := func(name string, runFunc func() error) {
+		go func() {
+			if err := runFunc(); err != nil && ctx.Err() == nil {
+				t.Errorf("failed to run the %s controllers: %v", name, err)
+			}
+		}()
+	}
+	run("registration", func() error { return ocmtesting.RunRegistrationHub(ctx, cfg, nil, recorder) })
+	run("work", func() error { return ocmtesting.RunWorkHub(ctx, cfg, nil, recorder) })
+	run("placement", func() error {
+		return placementcontrollers.RunControllerManager(ctx, &controllercmd.ControllerContext{
+			KubeConfig:    cfg,
+			EventRecorder: recorder,
+		})
+	})
+}
+
+// simulator simulates the agents of the clusters.
+type simulator struct {
+	hub      *ocmtesting.Hub
+	runID    string
+	clusters []string
+}
+
+func (s *simulator) parallelize(ctx context.Context, fn func(clusterName string) error) error {
+	var lock sync.Mutex
+	var errs []error
+	workqueue.ParallelizeUntil(ctx, agentWorkers, len(s.clusters), func(i int) {
+		if err := fn(s.clusters[i]); err != nil {
+			lock.Lock()
+			errs = append(errs, err)
+			lock.Unlock()
+		}
+	})
+	if len(errs) > 0 {
+		return fmt.Errorf("%d clusters failed, the first error: %v", len(errs), errs[0])
+	}
+	return nil
+}
+
+// registerClusters creates the accepted clusters and returns the latencies from the creation of each cluster to
+// its HubAccepted condition, then the agents report the status of the clusters and create the leases.
+func (s *simulator) registerClusters(ctx context.Context, t *testing.T) []time.Duration {
+	var lock sync.Mutex
+	created := map[string]time.Time{}
+	accepted := map[string]time.Duration{}
+
+	informerFactory := clusterinformers.NewSharedInformerFactory(s.hub.ClusterClient, 10*time.Minute)
+	_, err := informerFactory.Cluster().V1().ManagedClusters().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(_, newObj interface{}) {
+			cluster := newObj.(*clusterv1.ManagedCluster)
+			if !meta.IsStatusConditionTrue(cluster.Status.Conditions, clusterv1.ManagedClusterConditionHubAccepted) {
+				return
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			if createdTime, ok := created[cluster.Name]; ok {
+				if _, ok := accepted[cluster.Name]; !ok {
+					accepted[cluster.Name] = time.Since(createdTime)
+				}
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	informerCtx, stopInformer := context.WithCancel(ctx)
+	defer stopInformer()
+	informerFactory.Start(informerCtx.Done())
+	informerFactory.WaitForCacheSync(informerCtx.Done())
+
+	err = s.parallelize(ctx, func(clusterName string) error {
+		cluster := &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   clusterName,
+				Labels: map[string]string{runLabel: s.runID},
+			},
+			Spec: clusterv1.ManagedClusterSpec{HubAcceptsClient: true},
+		}
+		lock.Lock()
+		created[clusterName] = time.Now()
+		lock.Unlock()
+		_, err := s.hub.ClusterClient.ClusterV1().ManagedClusters().Create(ctx, cluster, metav1.CreateOptions{})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = wait.PollUntilContextTimeout(ctx, time.Second, 10*time.Minute, true, func(ctx context.Context) (bool, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(accepted) == len(s.clusters), nil
+	})
+	if err != nil {
+		t.Fatalf("clusters are not accepted: %v", err)
+	}
+
+	// the agents join the clusters once they are accepted
+	err = s.parallelize(ctx, func(clusterName string) error {
+		if err := s.renewLease(ctx, clusterName); err != nil {
+			return err
+		}
+		return s.reportClusterStatus(ctx, clusterName)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	latencies := make([]time.Duration, 0, len(accepted))
+	for _, latency := range accepted {
+		latencies = append(latencies, latency)
+	}
+	return latencies
+}
+
+// reportClusterStatus reports the cluster is joined and available with the claims like the registration agent.
+func (s *simulator) reportClusterStatus(ctx context.Context, clusterName string) error {
+	return wait.PollUntilContextTimeout(ctx, time.Second, time.Minute, true, func(ctx context.Context) (bool, error) {
+		cluster, err := s.hub.ClusterClient.ClusterV1().ManagedClusters().Get(ctx, clusterName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:   clusterv1.ManagedClusterConditionJoined,
+			Status: metav1.ConditionTrue,
+			Reason: "ManagedClusterJoined",
+		})
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:   clusterv1.ManagedClusterConditionAvailable,
+			Status: metav1.ConditionTrue,
+			Reason: "ManagedClusterAvailable",
+		})
+		cluster.Status.Version = clusterv1.ManagedClusterVersion{Kubernetes: "v1.27.0"}
+		cluster.Status.ClusterClaims = []clusterv1.ManagedClusterClaim{
+			{Name: "id.k8s.io", Value: clusterName},
+			{Name: "platform.open-cluster-management.io", Value: "kwok"},
+		}
+		_, err = s.hub.ClusterClient.ClusterV1().ManagedClusters().UpdateStatus(ctx, cluster, metav1.UpdateOptions{})
+		if errors.IsConflict(err) {
+			return false, nil
+		}
+		return err == nil, err
+	})
+}
+
+// renewLease renews the lease of the cluster, the lease is created if it is not created by the hub yet.
+func (s *simulator) renewLease(ctx context.Context, clusterName string) error {
+	now := metav1.NewMicroTime(time.Now())
+	leases := s.hub.KubeClient.CoordinationV1().Leases(clusterName)
+	lease, err := leases.Get(ctx, leaseName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: leaseName, Namespace: clusterName},
+			Spec:       coordinationv1.LeaseSpec{RenewTime: &now},
+		}
+		_, err = leases.Create(ctx, lease, metav1.CreateOptions{})
+		return err
+	case err != nil:
+		return err
+	}
+	lease.Spec.RenewTime = &now
+	_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+// renewLeases renews the leases of all the clusters in each interval for the duration.
+func (s *simulator) renewLeases(ctx context.Context, t *testing.T, duration time.Duration) {
+	steadyCtx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+	wait.UntilWithContext(steadyCtx, func(ctx context.Context) {
+		if err := s.parallelize(ctx, func(clusterName string) error {
+			return s.renewLease(ctx, clusterName)
+		}); err != nil && ctx.Err() == nil {
+			t.Errorf("failed to renew the leases: %v", err)
+		}
+	}, leaseRenewInterval)
+}
+
+// applyWorks creates the manifestworks of each cluster, and the agents report they are applied.
+func (s *simulator) applyWorks(ctx context.Context, t *testing.T, worksPerCluster int) {
+	err := s.parallelize(ctx, func(clusterName string) error {
+		for i := 0; i < worksPerCluster; i++ {
+			manifest := workapiv1.Manifest{}
+			manifest.Object = util.NewConfigmap("default", fmt.Sprintf("cm-%d", i), map[string]string{"a": "b"}, nil)
+			work := &workapiv1.ManifestWork{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("work-%d", i), Namespace: clusterName},
+				Spec: workapiv1.ManifestWorkSpec{
+					Workload: workapiv1.ManifestsTemplate{Manifests: []workapiv1.Manifest{manifest}},
+				},
+			}
+			work, err := s.hub.WorkClient.WorkV1().ManifestWorks(clusterName).Create(ctx, work, metav1.CreateOptions{})
+			if err != nil {
+				return err
+			}
+
+			for _, conditionType := range []string{workapiv1.WorkApplied, workapiv1.WorkAvailable} {
+				meta.SetStatusCondition(&work.Status.Conditions, metav1.Condition{
+					Type:               conditionType,
+					Status:             metav1.ConditionTrue,
+					Reason:             conditionType,
+					ObservedGeneration: work.Generation,
+				})
+			}
+			if _, err := s.hub.WorkClient.WorkV1().ManifestWorks(clusterName).UpdateStatus(
+				ctx, work, metav1.UpdateOptions{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// placeClusters creates a placement selecting all the clusters, and returns the latency until all of them are in
+// the decisions.
+func (s *simulator) placeClusters(ctx context.Context, t *testing.T) time.Duration {
+	_, err := s.hub.KubeClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: scaleNamespace},
+	}, metav1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		t.Fatal(err)
+	}
+
+	name := fmt.Sprintf("scale-%s", s.runID)
+	_, err = s.hub.ClusterClient.ClusterV1beta2().ManagedClusterSets().Create(ctx, &clusterv1beta2.ManagedClusterSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: clusterv1beta2.ManagedClusterSetSpec{
+			ClusterSelector: clusterv1beta2.ManagedClusterSelector{
+				SelectorType: clusterv1beta2.LabelSelector,
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{runLabel: s.runID},
+				},
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.hub.ClusterClient.ClusterV1beta2().ManagedClusterSetBindings(scaleNamespace).Create(ctx,
+		&clusterv1beta2.ManagedClusterSetBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: scaleNamespace},
+			Spec:       clusterv1beta2.ManagedClusterSetBindingSpec{ClusterSet: name},
+		}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	_, err = s.hub.ClusterClient.ClusterV1beta1().Placements(scaleNamespace).Create(ctx, &clusterv1beta1.Placement{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: scaleNamespace},
+		Spec:       clusterv1beta1.PlacementSpec{ClusterSets: []string{name}},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 10*time.Minute, true, func(ctx context.Context) (bool, error) {
+		decisions, err := s.hub.ClusterClient.ClusterV1beta1().PlacementDecisions(scaleNamespace).List(ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", clusterv1beta1.PlacementLabel, name),
+		})
+		if err != nil {
+			return false, err
+		}
+		decided := 0
+		for _, decision := range decisions.Items {
+			decided += len(decision.Status.Decisions)
+		}
+		return decided == len(s.clusters), nil
+	})
+	if err != nil {
+		t.Fatalf("clusters are not decided: %v", err)
+	}
+	return time.Since(start)
+}
+
+// resourceSampler samples the max heap in use of the process, and the cpu time of the process since it is created.
+type resourceSampler struct {
+	startCPU time.Duration
+
+	lock    sync.Mutex
+	maxHeap uint64
+}
+
+func newResourceSampler() *resourceSampler {
+	return &resourceSampler{startCPU: cpuTime()}
+}
+
+func (r *resourceSampler) run(ctx context.Context) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		stats := &runtime.MemStats{}
+		runtime.ReadMemStats(stats)
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		if stats.HeapInuse > r.maxHeap {
+			r.maxHeap = stats.HeapInuse
+		}
+	}, time.Second)
+}
+
+func (r *resourceSampler) usage() (time.Duration, uint64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return cpuTime() - r.startCPU, r.maxHeap
+}
+
+func cpuTime() time.Duration {
+	usage := &syscall.Rusage{}
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, usage); err != nil {
+		return 0
+	}
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+}
+
+func percentile(latencies []time.Duration, p float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	return latencies[int(float64(len(latencies)-1)*p)]
+}
@@ -37,6 +37,28 @@ run-e2e: cluster-ip bootstrap-secret
 	go test -c ./test/e2e
 	./e2e.test -test.v -ginkgo.v -deploy-klusterlet=true -nil-executor-validating=true -registration-image=$(REGISTRATION_IMAGE) -work-image=$(WORK_IMAGE) -singleton-image=$(OPERATOR_IMAGE_NAME) -klusterlet-deploy-mode=$(KLUSTERLET_DEPLOY_MODE)
 
+CHAOS_WINDOW?=2m
+CHAOS_HUB_STOP_COMMAND?=
+CHAOS_HUB_START_COMMAND?=
+
+# run-e2e-chaos runs the chaos scenarios only, the agents are disconnected from the hub for CHAOS_WINDOW. The hub outage
+# scenario is skipped unless the commands to stop and start the hub apiserver are specified.
+run-e2e-chaos: cluster-ip bootstrap-secret
+	go test -c ./test/e2e
+	./e2e.test -test.v -ginkgo.v -ginkgo.label-filter=chaos -chaos=true -chaos-window=$(CHAOS_WINDOW) -chaos-hub-stop-command="$(CHAOS_HUB_STOP_COMMAND)" -chaos-hub-start-command="$(CHAOS_HUB_START_COMMAND)" -deploy-klusterlet=true -registration-image=$(REGISTRATION_IMAGE) -work-image=$(WORK_IMAGE) -singleton-image=$(OPERATOR_IMAGE_NAME) -klusterlet-deploy-mode=$(KLUSTERLET_DEPLOY_MODE)
+
+UPGRADE_FROM_IMAGE_TAG?=latest
+
+# test-e2e-upgrade deploys the operators and the components of UPGRADE_FROM_IMAGE_TAG, e.g. the previous release, and
+# runs the upgrade scenarios which upgrade them to IMAGE_TAG.
+test-e2e-upgrade:
+	$(MAKE) deploy-hub deploy-spoke-operator IMAGE_TAG=$(UPGRADE_FROM_IMAGE_TAG)
+	$(MAKE) run-e2e-upgrade
+
+run-e2e-upgrade: cluster-ip bootstrap-secret
+	go test -c ./test/e2e
+	./e2e.test -test.v -ginkgo.v -ginkgo.label-filter=upgrade -upgrade=true -upgrade-image-tag=$(IMAGE_TAG) -deploy-klusterlet=true -registration-image=$(IMAGE_REGISTRY)/registration:$(UPGRADE_FROM_IMAGE_TAG) -work-image=$(IMAGE_REGISTRY)/work:$(UPGRADE_FROM_IMAGE_TAG) -singleton-image=$(IMAGE_REGISTRY)/registration-operator:$(UPGRADE_FROM_IMAGE_TAG) -klusterlet-deploy-mode=$(KLUSTERLET_DEPLOY_MODE)
+
 clean-hub: clean-hub-cr clean-hub-operator
 
 clean-spoke: clean-spoke-cr clean-spoke-operator
@@ -0,0 +1,223 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	operatorhelpers "open-cluster-management.io/ocm/pkg/operator/helpers"
+	"open-cluster-management.io/ocm/pkg/registration/helpers"
+	"open-cluster-management.io/ocm/pkg/registration/hub/taint"
+)
+
+const (
+	// chaosLeaseDurationSeconds shortens the lease of the cluster in the chaos scenarios, so the cluster becomes
+	// unavailable in 5 times of it after the agents are disconnected.
+	chaosLeaseDurationSeconds = 10
+	clusterLeaseName          = "managed-cluster-lease"
+)
+
+// The chaos scenarios disconnect the agents from the hub for the chaos window and assert the resilience guarantees
+// of the agents: the cluster is unavailable and tainted in the window, and after the agents reconnect, the lease is
+// renewed, the taint is removed, the workloads are reapplied and the agents are not bootstrapped again. They only
+// run with the -chaos flag and are selected by the "chaos" label.
+var _ = ginkgo.Describe("Chaos", ginkgo.Label("chaos"), func() {
+	var workName, configMapName string
+	var hubKubeconfigSecretUID types.UID
+
+	ginkgo.BeforeEach(func() {
+		if !chaos {
+			ginkgo.Skip("the chaos scenarios are not enabled")
+		}
+		if !deployKlusterlet || klusterletDeployMode != string(operatorapiv1.InstallModeDefault) {
+			ginkgo.Skip("the chaos scenarios require a klusterlet deployed in the Default mode")
+		}
+
+		workName = fmt.Sprintf("chaos-work-%s", rand.String(6))
+		configMapName = fmt.Sprintf("chaos-cm-%s", rand.String(6))
+
+		ginkgo.By("Shorten the lease duration of the cluster")
+		gomega.Eventually(func() error {
+			cluster, err := t.ClusterClient.ClusterV1().ManagedClusters().Get(context.TODO(), clusterName, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			cluster.Spec.LeaseDurationSeconds = chaosLeaseDurationSeconds
+			_, err = t.ClusterClient.ClusterV1().ManagedClusters().Update(context.TODO(), cluster, metav1.UpdateOptions{})
+			return err
+		}, t.EventuallyTimeout, t.EventuallyInterval).Should(gomega.Succeed())
+
+		ginkgo.By("Apply a configmap with a manifestwork")
+		_, err := t.CreateWorkOfConfigMap(workName, clusterName, configMapName, "default")
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		gomega.Eventually(func() error {
+			return assertWorkApplied(workName)
+		}, t.EventuallyTimeout, t.EventuallyInterval).Should(gomega.Succeed())
+
+		secret, err := t.SpokeKubeClient.CoreV1().Secrets(agentNamespace).Get(
+			context.TODO(), operatorhelpers.HubKubeConfig, metav1.GetOptions{})
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		hubKubeconfigSecretUID = secret.UID
+	})
+
+	ginkgo.AfterEach(func() {
+		if !chaos || !deployKlusterlet {
+			return
+		}
+		gomega.Expect(t.UnblockAgentEgress(agentNamespace)).To(gomega.Succeed())
+		gomega.Expect(t.cleanManifestWorks(clusterName, workName)).To(gomega.Succeed())
+
+		gomega.Eventually(func() error {
+			cluster, err := t.ClusterClient.ClusterV1().ManagedClusters().Get(context.TODO(), clusterName, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			cluster.Spec.LeaseDurationSeconds = 0
+			_, err = t.ClusterClient.ClusterV1().ManagedClusters().Update(context.TODO(), cluster, metav1.UpdateOptions{})
+			return err
+		}, t.EventuallyTimeout, t.EventuallyInterval).Should(gomega.Succeed())
+	})
+
+	ginkgo.It("Should recover after the agents are partitioned from the hub", func() {
+		lastRenewTime := clusterLeaseRenewTime()
+
+		ginkgo.By(fmt.Sprintf("Block the egress of the agents for %v", chaosWindow))
+		gomega.Expect(t.BlockAgentEgress(agentNamespace)).To(gomega.Succeed())
+		start := time.Now()
+
+		ginkgo.By("The cluster is unavailable and tainted in the window")
+		gomega.Eventually(assertClusterUnreachable, chaosWindow, t.EventuallyInterval).Should(gomega.Succeed())
+
+		ginkgo.By("Delete the configmap on the managed cluster in the window")
+		err := t.SpokeKubeClient.CoreV1().ConfigMaps("default").Delete(context.TODO(), configMapName, metav1.DeleteOptions{})
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+		time.Sleep(time.Until(start.Add(chaosWindow)))
+
+		ginkgo.By("Unblock the egress of the agents")
+		gomega.Expect(t.UnblockAgentEgress(agentNamespace)).To(gomega.Succeed())
+
+		assertRecovered(lastRenewTime, configMapName, hubKubeconfigSecretUID)
+	})
+
+	ginkgo.It("Should recover after the hub apiserver is down", func() {
+		if len(chaosHubStopCommand) == 0 || len(chaosHubStartCommand) == 0 {
+			ginkgo.Skip("the commands to stop and start the hub apiserver are not specified")
+		}
+		lastRenewTime := clusterLeaseRenewTime()
+
+		ginkgo.By(fmt.Sprintf("Stop the hub apiserver for %v", chaosWindow))
+		gomega.Expect(t.RunChaosCommand(chaosHubStopCommand)).To(gomega.Succeed())
+		time.Sleep(chaosWindow)
+
+		ginkgo.By("Start the hub apiserver")
+		gomega.Expect(t.RunChaosCommand(chaosHubStartCommand)).To(gomega.Succeed())
+		gomega.Eventually(t.CheckHubReady, t.EventuallyTimeout*5, t.EventuallyInterval*5).Should(gomega.Succeed())
+
+		assertRecovered(lastRenewTime, "", hubKubeconfigSecretUID)
+	})
+})
+
+func assertWorkApplied(workName string) error {
+	work, err := t.HubWorkClient.WorkV1().ManifestWorks(clusterName).Get(context.TODO(), workName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !meta.IsStatusConditionTrue(work.Status.Conditions, workapiv1.WorkApplied) {
+		return fmt.Errorf("work %s is not applied: %v", workName, work.Status.Conditions)
+	}
+	return nil
+}
+
+func assertClusterUnreachable() error {
+	cluster, err := t.ClusterClient.ClusterV1().ManagedClusters().Get(context.TODO(), clusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !meta.IsStatusConditionPresentAndEqual(cluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable,
+		metav1.ConditionUnknown) {
+		return fmt.Errorf("cluster %s is not unknown: %v", clusterName, cluster.Status.Conditions)
+	}
+	for _, clusterTaint := range cluster.Spec.Taints {
+		if helpers.IsTaintEqual(clusterTaint, taint.UnreachableTaint) {
+			return nil
+		}
+	}
+	return fmt.Errorf("cluster %s does not have the unreachable taint: %v", clusterName, cluster.Spec.Taints)
+}
+
+func clusterLeaseRenewTime() time.Time {
+	lease, err := t.HubKubeClient.CoordinationV1().Leases(clusterName).Get(context.TODO(), clusterLeaseName, metav1.GetOptions{})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	return lease.Spec.RenewTime.Time
+}
+
+// assertRecovered asserts the agents reconnect to the hub after the chaos window: the lease is renewed, the cluster is
+// available without taints, the deleted configmap is reapplied, and the agents are not bootstrapped again.
+func assertRecovered(lastRenewTime time.Time, deletedConfigMapName string, hubKubeconfigSecretUID types.UID) {
+	ginkgo.By("The lease of the cluster is renewed")
+	gomega.Eventually(func() error {
+		if renewTime := clusterLeaseRenewTime(); !renewTime.After(lastRenewTime) {
+			return fmt.Errorf("lease is not renewed since %v", lastRenewTime)
+		}
+		return nil
+	}, t.EventuallyTimeout, t.EventuallyInterval).Should(gomega.Succeed())
+
+	ginkgo.By("The cluster is available and not tainted")
+	gomega.Eventually(func() error {
+		if err := t.CheckManagedClusterStatus(clusterName); err != nil {
+			return err
+		}
+		cluster, err := t.ClusterClient.ClusterV1().ManagedClusters().Get(context.TODO(), clusterName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if len(cluster.Spec.Taints) != 0 {
+			return fmt.Errorf("cluster %s is still tainted: %v", clusterName, cluster.Spec.Taints)
+		}
+		return nil
+	}, t.EventuallyTimeout, t.EventuallyInterval).Should(gomega.Succeed())
+
+	if len(deletedConfigMapName) > 0 {
+		ginkgo.By("The deleted configmap is reapplied")
+		gomega.Eventually(func() error {
+			_, err := t.SpokeKubeClient.CoreV1().ConfigMaps("default").Get(context.TODO(), deletedConfigMapName, metav1.GetOptions{})
+			return err
+		}, t.EventuallyTimeout*5, t.EventuallyInterval*5).Should(gomega.Succeed())
+	}
+
+	ginkgo.By("The agents are not bootstrapped again")
+	secret, err := t.SpokeKubeClient.CoreV1().Secrets(agentNamespace).Get(
+		context.TODO(), operatorhelpers.HubKubeConfig, metav1.GetOptions{})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	gomega.Expect(secret.UID).To(gomega.Equal(hubKubeconfigSecretUID))
+
+	events, err := t.SpokeKubeClient.CoreV1().Events(agentNamespace).List(context.TODO(), metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("reason=%s", commonhelpers.EventReasonRebootstrapTriggered),
+	})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	gomega.Expect(eventsAfter(events.Items, lastRenewTime)).To(gomega.BeEmpty())
+}
+
+func eventsAfter(events []corev1.Event, since time.Time) []corev1.Event {
+	var filtered []corev1.Event
+	for _, event := range events {
+		if event.LastTimestamp.After(since) || event.EventTime.After(since) {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
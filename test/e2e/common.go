@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"strings"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	certificatesv1 "k8s.io/api/certificates/v1"
 	coordv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -466,6 +468,132 @@ func (t *Tester) CheckManagedClusterStatus(clusterName string) error {
 	return fmt.Errorf("cluster %s condtions are not ready: %v", clusterName, managedCluster.Status.Conditions)
 }
 
+// chaosNetworkPolicyName is the name of the network policy partitioning the agents from the hub.
+const chaosNetworkPolicyName = "e2e-chaos-deny-egress"
+
+// BlockAgentEgress denies the egress traffic of all the pods in the agent namespace with a network policy, so the
+// agents are partitioned from the hub. It requires a network plugin which enforces the network policies.
+func (t *Tester) BlockAgentEgress(agentNamespace string) error {
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      chaosNetworkPolicyName,
+			Namespace: agentNamespace,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			// an empty pod selector selects all the pods in the namespace, and no egress rule allows no traffic.
+			PodSelector: metav1.LabelSelector{},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+		},
+	}
+	_, err := t.SpokeKubeClient.NetworkingV1().NetworkPolicies(agentNamespace).Create(context.TODO(), policy, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+// UnblockAgentEgress removes the network policy created by BlockAgentEgress.
+func (t *Tester) UnblockAgentEgress(agentNamespace string) error {
+	err := t.SpokeKubeClient.NetworkingV1().NetworkPolicies(agentNamespace).Delete(
+		context.TODO(), chaosNetworkPolicyName, metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// RunChaosCommand runs the shell command, e.g. to stop or start the hub apiserver.
+func (t *Tester) RunChaosCommand(command string) error {
+	output, err := exec.Command("/bin/sh", "-c", command).CombinedOutput() // #nosec G204
+	if err != nil {
+		return fmt.Errorf("failed to run %q: %v, %s", command, err, output)
+	}
+	return nil
+}
+
+// UpgradeClusterManager upgrades the cluster manager operator and the hub components to the images of the tag.
+func (t *Tester) UpgradeClusterManager(imageTag string) error {
+	if err := upgradeOperatorDeployment(t.HubKubeClient, t.operatorNamespace, t.clusterManagerName, imageTag); err != nil {
+		return err
+	}
+
+	cm, err := t.OperatorClient.OperatorV1().ClusterManagers().Get(context.TODO(), t.clusterManagerName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	cm.Spec.RegistrationImagePullSpec = imageWithTag(cm.Spec.RegistrationImagePullSpec, imageTag)
+	cm.Spec.WorkImagePullSpec = imageWithTag(cm.Spec.WorkImagePullSpec, imageTag)
+	cm.Spec.PlacementImagePullSpec = imageWithTag(cm.Spec.PlacementImagePullSpec, imageTag)
+	cm.Spec.AddOnManagerImagePullSpec = imageWithTag(cm.Spec.AddOnManagerImagePullSpec, imageTag)
+	_, err = t.OperatorClient.OperatorV1().ClusterManagers().Update(context.TODO(), cm, metav1.UpdateOptions{})
+	return err
+}
+
+// UpgradeKlusterlet upgrades the klusterlet operator and the agents of the klusterlet to the images of the tag.
+func (t *Tester) UpgradeKlusterlet(klusterletName, imageTag string) error {
+	if err := upgradeOperatorDeployment(t.SpokeKubeClient, t.operatorNamespace, t.klusterletOperator, imageTag); err != nil {
+		return err
+	}
+
+	klusterlet, err := t.OperatorClient.OperatorV1().Klusterlets().Get(context.TODO(), klusterletName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	klusterlet.Spec.RegistrationImagePullSpec = imageWithTag(klusterlet.Spec.RegistrationImagePullSpec, imageTag)
+	klusterlet.Spec.WorkImagePullSpec = imageWithTag(klusterlet.Spec.WorkImagePullSpec, imageTag)
+	klusterlet.Spec.ImagePullSpec = imageWithTag(klusterlet.Spec.ImagePullSpec, imageTag)
+	_, err = t.OperatorClient.OperatorV1().Klusterlets().Update(context.TODO(), klusterlet, metav1.UpdateOptions{})
+	return err
+}
+
+// CheckAgentsUpgraded checks all the deployments in the agent namespace are rolled out with the images of the tag.
+func (t *Tester) CheckAgentsUpgraded(agentNamespace, imageTag string) error {
+	deployments, err := t.SpokeKubeClient.AppsV1().Deployments(agentNamespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	if len(deployments.Items) == 0 {
+		return fmt.Errorf("no agent is deployed in namespace %s", agentNamespace)
+	}
+	for _, deployment := range deployments.Items {
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			if container.Image != imageWithTag(container.Image, imageTag) {
+				return fmt.Errorf("container %s of deployment %s is not upgraded: %s", container.Name, deployment.Name, container.Image)
+			}
+		}
+		replicas := *deployment.Spec.Replicas
+		if deployment.Status.ObservedGeneration != deployment.Generation || deployment.Status.UpdatedReplicas != replicas ||
+			deployment.Status.ReadyReplicas != replicas || deployment.Status.Replicas != replicas {
+			return fmt.Errorf("deployment %s is still rolling out", deployment.Name)
+		}
+	}
+	return nil
+}
+
+func upgradeOperatorDeployment(kubeClient kubernetes.Interface, namespace, name, imageTag string) error {
+	deployment, err := kubeClient.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range deployment.Spec.Template.Spec.Containers {
+		deployment.Spec.Template.Spec.Containers[i].Image = imageWithTag(deployment.Spec.Template.Spec.Containers[i].Image, imageTag)
+	}
+	_, err = kubeClient.AppsV1().Deployments(namespace).Update(context.TODO(), deployment, metav1.UpdateOptions{})
+	return err
+}
+
+// imageWithTag replaces the tag of the image, it returns the empty image as it is, so the default image of the
+// operator is still used.
+func imageWithTag(image, tag string) string {
+	if len(image) == 0 {
+		return image
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return fmt.Sprintf("%s:%s", image, tag)
+}
+
 func (t *Tester) CreateWorkOfConfigMap(name, clusterName, configMapName, configMapNamespace string) (*workapiv1.ManifestWork, error) {
 	manifest := workapiv1.Manifest{}
 	manifest.Object = util.NewConfigmap(configMapNamespace, configMapName, map[string]string{"a": "b"}, []string{})
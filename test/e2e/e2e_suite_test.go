@@ -28,6 +28,12 @@ var (
 	workImage             string
 	singletonImage        string
 	klusterletDeployMode  string
+	chaos                 bool
+	chaosWindow           time.Duration
+	chaosHubStopCommand   string
+	chaosHubStartCommand  string
+	upgrade               bool
+	upgradeImageTag       string
 )
 
 func init() {
@@ -41,6 +47,15 @@ func init() {
 	flag.StringVar(&workImage, "work-image", "", "The image of the work")
 	flag.StringVar(&singletonImage, "singleton-image", "", "The image of the klusterlet agent")
 	flag.StringVar(&klusterletDeployMode, "klusterlet-deploy-mode", string(operatorapiv1.InstallModeDefault), "The image of the work")
+	flag.BoolVar(&chaos, "chaos", false, "Whether run the chaos scenarios which disconnect the agents from the hub or not (default false)")
+	flag.DurationVar(&chaosWindow, "chaos-window", 2*time.Minute, "The duration the agents are disconnected from the hub in the chaos scenarios (default 2 minutes)")
+	flag.StringVar(&chaosHubStopCommand, "chaos-hub-stop-command", "",
+		"The shell command to stop the hub apiserver in the hub outage scenario, e.g. \"docker pause hub-control-plane\". The scenario is skipped if it is empty")
+	flag.StringVar(&chaosHubStartCommand, "chaos-hub-start-command", "",
+		"The shell command to start the hub apiserver again in the hub outage scenario, e.g. \"docker unpause hub-control-plane\"")
+	flag.BoolVar(&upgrade, "upgrade", false,
+		"Whether run the upgrade scenarios which upgrade the operators and the components to the upgrade image tag or not (default false)")
+	flag.StringVar(&upgradeImageTag, "upgrade-image-tag", "", "The tag of the images the operators and the components are upgraded to")
 }
 
 func TestE2E(tt *testing.T) {
@@ -0,0 +1,155 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	operatorhelpers "open-cluster-management.io/ocm/pkg/operator/helpers"
+)
+
+// The upgrade scenarios start with the operators and the components of the previous release deployed, upgrade them
+// to the images of the upgrade image tag, and assert the upgrade guarantees of the operators: the cluster is never
+// unavailable, the agents keep their client certificates and are not bootstrapped again, and the workloads are not
+// applied again. They only run with the -upgrade flag and are selected by the "upgrade" label.
+var _ = ginkgo.Describe("Upgrade", ginkgo.Label("upgrade"), func() {
+	var workName, configMapName string
+
+	ginkgo.BeforeEach(func() {
+		if !upgrade {
+			ginkgo.Skip("the upgrade scenarios are not enabled")
+		}
+		if len(upgradeImageTag) == 0 {
+			ginkgo.Fail("the upgrade image tag is required by the upgrade scenarios")
+		}
+		if !deployKlusterlet || operatorhelpers.IsHosted(operatorapiv1.InstallMode(klusterletDeployMode)) {
+			ginkgo.Skip("the upgrade scenarios require a klusterlet deployed in the Default or Singleton mode")
+		}
+
+		workName = fmt.Sprintf("upgrade-work-%s", rand.String(6))
+		configMapName = fmt.Sprintf("upgrade-cm-%s", rand.String(6))
+
+		ginkgo.By("Apply a configmap with a manifestwork")
+		_, err := t.CreateWorkOfConfigMap(workName, clusterName, configMapName, "default")
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		gomega.Eventually(func() error {
+			return assertWorkApplied(workName)
+		}, t.EventuallyTimeout, t.EventuallyInterval).Should(gomega.Succeed())
+	})
+
+	ginkgo.AfterEach(func() {
+		if !upgrade || !deployKlusterlet {
+			return
+		}
+		gomega.Expect(t.cleanManifestWorks(clusterName, workName)).To(gomega.Succeed())
+	})
+
+	ginkgo.It("Should upgrade the operators and the components without downtime", func() {
+		secret, err := t.SpokeKubeClient.CoreV1().Secrets(agentNamespace).Get(
+			context.TODO(), operatorhelpers.HubKubeConfig, metav1.GetOptions{})
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		configMap, err := t.SpokeKubeClient.CoreV1().ConfigMaps("default").Get(context.TODO(), configMapName, metav1.GetOptions{})
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		work, err := t.HubWorkClient.WorkV1().ManifestWorks(clusterName).Get(context.TODO(), workName, metav1.GetOptions{})
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		applied := meta.FindStatusCondition(work.Status.Conditions, workapiv1.WorkApplied)
+		gomega.Expect(applied).ToNot(gomega.BeNil())
+		start := time.Now()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		unavailable := watchClusterAvailability(ctx)
+		defer cancel()
+
+		ginkgo.By(fmt.Sprintf("Upgrade the cluster manager and the klusterlet to %s", upgradeImageTag))
+		gomega.Eventually(func() error {
+			return t.UpgradeClusterManager(upgradeImageTag)
+		}, t.EventuallyTimeout, t.EventuallyInterval).Should(gomega.Succeed())
+		gomega.Eventually(func() error {
+			return t.UpgradeKlusterlet(klusterletName, upgradeImageTag)
+		}, t.EventuallyTimeout, t.EventuallyInterval).Should(gomega.Succeed())
+
+		ginkgo.By("The hub and the agents are upgraded")
+		gomega.Eventually(t.CheckHubReady, t.EventuallyTimeout*5, t.EventuallyInterval*5).Should(gomega.Succeed())
+		gomega.Eventually(func() error {
+			return t.CheckAgentsUpgraded(agentNamespace, upgradeImageTag)
+		}, t.EventuallyTimeout*5, t.EventuallyInterval*5).Should(gomega.Succeed())
+
+		ginkgo.By("The lease of the cluster is renewed by the upgraded agents")
+		upgradedTime := time.Now()
+		gomega.Eventually(func() error {
+			if renewTime := clusterLeaseRenewTime(); !renewTime.After(upgradedTime) {
+				return fmt.Errorf("lease is not renewed since %v", upgradedTime)
+			}
+			return nil
+		}, t.EventuallyTimeout, t.EventuallyInterval).Should(gomega.Succeed())
+
+		ginkgo.By("The cluster is never unavailable")
+		cancel()
+		gomega.Expect(unavailable()).To(gomega.BeEmpty())
+
+		ginkgo.By("The client certificate of the agents is preserved")
+		upgradedSecret, err := t.SpokeKubeClient.CoreV1().Secrets(agentNamespace).Get(
+			context.TODO(), operatorhelpers.HubKubeConfig, metav1.GetOptions{})
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		gomega.Expect(upgradedSecret.UID).To(gomega.Equal(secret.UID))
+		gomega.Expect(upgradedSecret.Data["tls.crt"]).To(gomega.Equal(secret.Data["tls.crt"]))
+
+		ginkgo.By("The agents are not bootstrapped again")
+		events, err := t.SpokeKubeClient.CoreV1().Events(agentNamespace).List(context.TODO(), metav1.ListOptions{
+			FieldSelector: fmt.Sprintf("reason=%s", commonhelpers.EventReasonRebootstrapTriggered),
+		})
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		gomega.Expect(eventsAfter(events.Items, start)).To(gomega.BeEmpty())
+
+		ginkgo.By("The workload is not applied again")
+		upgradedConfigMap, err := t.SpokeKubeClient.CoreV1().ConfigMaps("default").Get(context.TODO(), configMapName, metav1.GetOptions{})
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		gomega.Expect(upgradedConfigMap.UID).To(gomega.Equal(configMap.UID))
+		gomega.Expect(upgradedConfigMap.ResourceVersion).To(gomega.Equal(configMap.ResourceVersion))
+		upgradedWork, err := t.HubWorkClient.WorkV1().ManifestWorks(clusterName).Get(context.TODO(), workName, metav1.GetOptions{})
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		upgradedApplied := meta.FindStatusCondition(upgradedWork.Status.Conditions, workapiv1.WorkApplied)
+		gomega.Expect(upgradedApplied).ToNot(gomega.BeNil())
+		gomega.Expect(upgradedApplied.Status).To(gomega.Equal(metav1.ConditionTrue))
+		gomega.Expect(upgradedApplied.LastTransitionTime).To(gomega.Equal(applied.LastTransitionTime))
+	})
+})
+
+// watchClusterAvailability polls the cluster until the context is done, and returns a func waiting for the polling to
+// stop and listing the observed conditions of the cluster once it is not available.
+func watchClusterAvailability(ctx context.Context) func() []string {
+	var unavailable []string
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		wait.UntilWithContext(ctx, func(ctx context.Context) {
+			cluster, err := t.ClusterClient.ClusterV1().ManagedClusters().Get(ctx, clusterName, metav1.GetOptions{})
+			if err != nil {
+				// only the observed conditions count, the transient errors of the hub apiserver are ignored.
+				return
+			}
+			if meta.IsStatusConditionTrue(cluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable) {
+				return
+			}
+			unavailable = append(unavailable, fmt.Sprintf("%s: %v", time.Now().Format(time.RFC3339), cluster.Status.Conditions))
+		}, t.EventuallyInterval)
+	}()
+
+	return func() []string {
+		<-done
+		return unavailable
+	}
+}
@@ -41,6 +41,26 @@ test-placement-integration: ensure-kubebuilder-tools
 	./placement-integration.test -ginkgo.slow-spec-threshold=15s -ginkgo.v -ginkgo.fail-fast
 .PHONY: test-placement-integration
 
+# compare the scheduler benchmarks with test/benchmark/placement/thresholds.yaml, and print the benchmarks
+# of the scheduler and each plugin
+test-placement-benchmark:
+	cd ./test/benchmark/placement && PLACEMENT_BENCHMARK_REGRESSION=true go test . -run TestSchedulerRegression -v
+	go test ./test/benchmark/placement -run '^$$' -bench 'Benchmark(Scheduler|Filters|Prioritizers)$$' -benchmem
+.PHONY: test-placement-benchmark
+
+KWOKCTL?=kwokctl
+SCALE_CLUSTER_NAME?=ocm-scale
+SCALE_KUBECONFIG?=$(PWD)/.scale-kubeconfig
+
+# simulate the clusters in test/benchmark/scale/budgets.yaml against a kwok cluster, and compare the latencies
+# and the resource usage of the hub controllers with the budgets. The kwok cluster is deleted after the test.
+test-scale:
+	$(KWOKCTL) create cluster --name $(SCALE_CLUSTER_NAME)
+	$(KWOKCTL) get kubeconfig --name $(SCALE_CLUSTER_NAME) > $(SCALE_KUBECONFIG)
+	cd ./test/benchmark/scale && SCALE_TEST_KUBECONFIG=$(SCALE_KUBECONFIG) go test . -run TestScale -v -timeout 60m; \
+		status=$$?; $(KWOKCTL) delete cluster --name $(SCALE_CLUSTER_NAME); $(RM) $(SCALE_KUBECONFIG); exit $$status
+.PHONY: test-scale
+
 test-registration-operator-integration: ensure-kubebuilder-tools
 	go test -c ./test/integration/operator -o ./registration-operator-integration.test
 	./registration-operator-integration.test -ginkgo.slow-spec-threshold=15s -ginkgo.v -ginkgo.fail-fast
@@ -109,7 +109,7 @@ var _ = ginkgo.BeforeSuite(func() {
 		err = addonManager.Start(mgrContext)
 		gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
-		err = addon.RunManager(mgrContext, &controllercmd.ControllerContext{
+		err = addon.NewAddonManagerOptions().RunAddonManager(mgrContext, &controllercmd.ControllerContext{
 			KubeConfig:    cfg,
 			EventRecorder: util.NewIntegrationTestEventRecorder("integration"),
 		})
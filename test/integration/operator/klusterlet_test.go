@@ -11,6 +11,7 @@ import (
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,6 +35,19 @@ func startKlusterletOperator(ctx context.Context) {
 	gomega.Expect(err).NotTo(gomega.HaveOccurred())
 }
 
+// agentTolerations are the tolerations the agents are always deployed with.
+var agentTolerations = []corev1.Toleration{
+	{
+		Key:      "CriticalAddonsOnly",
+		Operator: corev1.TolerationOpExists,
+	},
+	{
+		Key:      corev1.TaintNodeMemoryPressure,
+		Operator: corev1.TolerationOpExists,
+		Effect:   corev1.TaintEffectNoSchedule,
+	},
+}
+
 var _ = ginkgo.Describe("Klusterlet", func() {
 	var cancel context.CancelFunc
 	var klusterlet *operatorapiv1.Klusterlet
@@ -272,10 +286,8 @@ var _ = ginkgo.Describe("Klusterlet", func() {
 					return false
 				}
 
-				if len(deployment.Spec.Template.Spec.Tolerations) != 0 {
-					return false
-				}
-				return true
+				// only the tolerations of the agents are added
+				return apiequality.Semantic.DeepEqual(deployment.Spec.Template.Spec.Tolerations, agentTolerations)
 			}, eventuallyTimeout, eventuallyInterval).Should(gomega.BeTrue())
 
 			gomega.Eventually(func() error {
@@ -320,6 +332,22 @@ var _ = ginkgo.Describe("Klusterlet", func() {
 				}
 				return false
 			}, eventuallyTimeout, eventuallyInterval).Should(gomega.BeTrue())
+
+			// Check the tolerations of the agents are kept besides the toleration of the node placement
+			gomega.Eventually(func() bool {
+				deployment, err := kubeClient.AppsV1().Deployments(klusterletNamespace).Get(context.Background(), registrationDeploymentName, metav1.GetOptions{})
+				if err != nil {
+					return false
+				}
+				expected := append([]corev1.Toleration{
+					{
+						Key:      infraNodeLabel,
+						Operator: corev1.TolerationOpExists,
+						Effect:   corev1.TaintEffectNoSchedule,
+					},
+				}, agentTolerations...)
+				return apiequality.Semantic.DeepEqual(deployment.Spec.Template.Spec.Tolerations, expected)
+			}, eventuallyTimeout, eventuallyInterval).Should(gomega.BeTrue())
 		})
 
 		ginkgo.It("should have correct registration deployment when server url is empty", func() {
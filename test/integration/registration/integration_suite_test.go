@@ -10,7 +10,6 @@ import (
 
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
-	"github.com/openshift/library-go/pkg/controller/controllercmd"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
@@ -32,6 +31,7 @@ import (
 	"open-cluster-management.io/ocm/pkg/registration/spoke"
 	"open-cluster-management.io/ocm/pkg/registration/spoke/addon"
 	"open-cluster-management.io/ocm/pkg/registration/spoke/registration"
+	ocmtesting "open-cluster-management.io/ocm/pkg/testing"
 	"open-cluster-management.io/ocm/test/integration/util"
 )
 
@@ -78,11 +78,7 @@ var CRDPaths = []string{
 func runAgent(name string, opt *spoke.SpokeAgentOptions, commOption *commonoptions.AgentOptions, cfg *rest.Config) context.CancelFunc {
 	ctx, cancel := context.WithCancel(context.Background())
 	go func() {
-		config := spoke.NewSpokeAgentConfig(commOption, opt)
-		err := config.RunSpokeAgent(ctx, &controllercmd.ControllerContext{
-			KubeConfig:    cfg,
-			EventRecorder: util.NewIntegrationTestEventRecorder(name),
-		})
+		err := ocmtesting.RunRegistrationAgent(ctx, cfg, commOption, opt, util.NewIntegrationTestEventRecorder(name))
 		if err != nil {
 			return
 		}
@@ -191,10 +187,7 @@ var _ = ginkgo.BeforeSuite(func() {
 	go func() {
 		m := hub.NewHubManagerOptions()
 		m.ClusterAutoApprovalUsers = []string{util.AutoApprovalBootstrapUser}
-		err := m.RunControllerManager(ctx, &controllercmd.ControllerContext{
-			KubeConfig:    cfg,
-			EventRecorder: util.NewIntegrationTestEventRecorder("hub"),
-		})
+		err := ocmtesting.RunRegistrationHub(ctx, cfg, m, util.NewIntegrationTestEventRecorder("hub"))
 		gomega.Expect(err).NotTo(gomega.HaveOccurred())
 	}()
 
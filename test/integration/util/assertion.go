@@ -18,6 +18,8 @@ import (
 	operatorclientset "open-cluster-management.io/api/client/operator/clientset/versioned"
 	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
 	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	ocmtesting "open-cluster-management.io/ocm/pkg/testing"
 )
 
 const (
@@ -295,20 +297,5 @@ func AssertAppliedResources(hubHash, workName string, gvrs []schema.GroupVersion
 }
 
 func HaveManifestCondition(conditions []workapiv1.ManifestCondition, expectedType string, expectedStatuses []metav1.ConditionStatus) bool {
-	if len(conditions) != len(expectedStatuses) {
-		return false
-	}
-
-	for index, condition := range conditions {
-		expectedStatus := expectedStatuses[index]
-		if expectedStatus == "" {
-			continue
-		}
-
-		if ok := meta.IsStatusConditionPresentAndEqual(condition.Conditions, expectedType, expectedStatus); !ok {
-			return false
-		}
-	}
-
-	return true
+	return ocmtesting.HaveManifestCondition(conditions, expectedType, expectedStatuses)
 }
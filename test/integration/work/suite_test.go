@@ -8,7 +8,6 @@ import (
 
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
-	"github.com/openshift/library-go/pkg/controller/controllercmd"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
@@ -22,8 +21,8 @@ import (
 	workapiv1 "open-cluster-management.io/api/work/v1"
 
 	"open-cluster-management.io/ocm/pkg/features"
+	ocmtesting "open-cluster-management.io/ocm/pkg/testing"
 	"open-cluster-management.io/ocm/pkg/work/helper"
-	"open-cluster-management.io/ocm/pkg/work/hub"
 	"open-cluster-management.io/ocm/test/integration/util"
 )
 
@@ -102,10 +101,7 @@ var _ = ginkgo.BeforeSuite(func() {
 
 	// start hub controller
 	go func() {
-		err := hub.RunWorkHubManager(envCtx, &controllercmd.ControllerContext{
-			KubeConfig:    cfg,
-			EventRecorder: util.NewIntegrationTestEventRecorder("hub"),
-		})
+		err := ocmtesting.RunWorkHub(envCtx, cfg, nil, util.NewIntegrationTestEventRecorder("hub"))
 		gomega.Expect(err).NotTo(gomega.HaveOccurred())
 	}()
 })